@@ -93,6 +93,9 @@ func (ci *ConfigInitializer) SetupManagerDependencies(registerConfig *handler.Re
 	registerConfig.ConfigService = service.NewConfigService(query.Q)
 	registerConfig.BillingService = service.NewBillingService(query.Q)
 	registerConfig.PrequeueService = service.NewPrequeueService(query.Q, registerConfig.ConfigService)
+	registerConfig.WorkflowService = service.NewWorkflowService(query.Q)
+	registerConfig.JobArrayService = service.NewJobArrayService(query.Q)
+	registerConfig.NotificationService = service.NewNotificationService(query.Q, registerConfig.BillingService)
 
 	registerConfig.GpuAnalysisService = service.NewGpuAnalysisService(
 		query.Q,
@@ -108,10 +111,12 @@ func (ci *ConfigInitializer) SetupManagerDependencies(registerConfig *handler.Re
 		registerConfig.PrometheusClient,
 		registerConfig.GpuAnalysisService,
 		registerConfig.BillingService,
+		registerConfig.NotificationService,
 	)
 
 	registerConfig.ConfigService.SetCronJobManager(registerConfig.CronJobManager)
 	registerConfig.BillingService.SetCronJobManager(registerConfig.CronJobManager)
+	registerConfig.BillingService.SetClient(registerConfig.Client)
 
 	go registerConfig.CronJobManager.SyncCronJob()
 
@@ -13,6 +13,7 @@ import (
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	schedulerpluginsv1alpha1 "sigs.k8s.io/scheduler-plugins/apis/scheduling/v1alpha1"
 	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	bus "volcano.sh/apis/pkg/apis/bus/v1alpha1"
 	scheduling "volcano.sh/apis/pkg/apis/scheduling/v1beta1"
 
 	"github.com/raids-lab/crater/internal/handler"
@@ -21,6 +22,7 @@ import (
 	recommenddljob "github.com/raids-lab/crater/pkg/apis/recommenddljob/v1"
 	"github.com/raids-lab/crater/pkg/config"
 	"github.com/raids-lab/crater/pkg/imageregistry"
+	"github.com/raids-lab/crater/pkg/imagescan"
 	"github.com/raids-lab/crater/pkg/indexer"
 	"github.com/raids-lab/crater/pkg/packer"
 	"github.com/raids-lab/crater/pkg/reconciler"
@@ -182,6 +184,12 @@ func (ms *ManagerSetup) setupImagePacker(mgr manager.Manager, registerConfig *ha
 		return fmt.Errorf("unable to set up model download controller: %w", err)
 	}
 
+	if ms.backendConfig.ImageScan.Enable {
+		if err = mgr.Add(imagescan.New()); err != nil {
+			return fmt.Errorf("unable to set up image scanner: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -189,6 +197,8 @@ func (ms *ManagerSetup) setupImagePacker(mgr manager.Manager, registerConfig *ha
 func (ms *ManagerSetup) setupVolcano(mgr manager.Manager, registerConfig *handler.RegisterConfig) error {
 	utilruntime.Must(scheduling.AddToScheme(mgr.GetScheme()))
 	utilruntime.Must(batch.AddToScheme(mgr.GetScheme()))
+	// bus.Command suspends jobs that exceed a billing budget cap
+	utilruntime.Must(bus.AddToScheme(mgr.GetScheme()))
 
 	vcjobReconciler := reconciler.NewVcJobReconciler(
 		mgr.GetClient(),
@@ -197,6 +207,8 @@ func (ms *ManagerSetup) setupVolcano(mgr manager.Manager, registerConfig *handle
 		registerConfig.KubeClient,
 		registerConfig.PrequeueWatcher,
 		registerConfig.BillingService,
+		registerConfig.WorkflowService,
+		registerConfig.JobArrayService,
 	)
 	err := vcjobReconciler.SetupWithManager(mgr)
 	if err != nil {
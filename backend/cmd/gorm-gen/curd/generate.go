@@ -53,6 +53,21 @@ func main() {
 		model.SystemConfig{},
 		model.PrequeueConfig{},
 		model.QueueQuotaLimit{},
+		model.Workflow{},
+		model.WorkflowNode{},
+		model.JobArray{},
+		model.JobArrayTask{},
+		model.NotificationRule{},
+		model.NotificationDigestItem{},
+		model.BillingLedgerEntry{},
+		model.BillingBudget{},
+		model.StorageQuota{},
+		model.StorageTrashItem{},
+		model.DatasetVersion{},
+		model.DatasetManifest{},
+		model.ImageScan{},
+		model.ImageGCMark{},
+		model.S3AccessKey{},
 	)
 
 	// 执行并生成代码
@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
 	"time"
 
@@ -142,6 +143,449 @@ func modelDownloadSubmissionMigration() *gormigrate.Migration {
 	}
 }
 
+func workflowMigration() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "202607251200",
+		Migrate: func(tx *gorm.DB) error {
+			if err := createTableIfMissing(tx, &model.Workflow{}); err != nil {
+				return err
+			}
+			return createTableIfMissing(tx, &model.WorkflowNode{})
+		},
+		Rollback: func(tx *gorm.DB) error {
+			if err := dropTableIfPresent(tx, &model.WorkflowNode{}); err != nil {
+				return err
+			}
+			return dropTableIfPresent(tx, &model.Workflow{})
+		},
+	}
+}
+
+func jobArrayMigration() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "202607281000",
+		Migrate: func(tx *gorm.DB) error {
+			if err := createTableIfMissing(tx, &model.JobArray{}); err != nil {
+				return err
+			}
+			return createTableIfMissing(tx, &model.JobArrayTask{})
+		},
+		Rollback: func(tx *gorm.DB) error {
+			if err := dropTableIfPresent(tx, &model.JobArrayTask{}); err != nil {
+				return err
+			}
+			return dropTableIfPresent(tx, &model.JobArray{})
+		},
+	}
+}
+
+func notificationRuleMigration() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "202608031000",
+		Migrate: func(tx *gorm.DB) error {
+			if err := createTableIfMissing(tx, &model.NotificationRule{}); err != nil {
+				return err
+			}
+			if err := createTableIfMissing(tx, &model.NotificationDigestItem{}); err != nil {
+				return err
+			}
+			config := notificationDispatchCronJobConfig()
+			return tx.Where("name = ?", config.Name).FirstOrCreate(config).Error
+		},
+		Rollback: func(tx *gorm.DB) error {
+			if err := tx.Unscoped().Where("name = ?", "notification-dispatch").
+				Delete(&model.CronJobConfig{}).Error; err != nil {
+				return err
+			}
+			if err := dropTableIfPresent(tx, &model.NotificationDigestItem{}); err != nil {
+				return err
+			}
+			return dropTableIfPresent(tx, &model.NotificationRule{})
+		},
+	}
+}
+
+// notificationDispatchCronJobConfig is enabled by default: it only acts on users who configured notification rules.
+func notificationDispatchCronJobConfig() *model.CronJobConfig {
+	return &model.CronJobConfig{
+		Name:    "notification-dispatch",
+		Type:    model.CronJobTypePatrolFunc,
+		Spec:    "*/1 * * * *",
+		Status:  model.CronJobConfigStatusIdle,
+		Config:  datatypes.JSON("{}"),
+		EntryID: -1,
+	}
+}
+
+func billingLedgerMigration() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "202608101000",
+		Migrate: func(tx *gorm.DB) error {
+			if err := createTableIfMissing(tx, &model.BillingLedgerEntry{}); err != nil {
+				return err
+			}
+			// Open every existing balance once so reconciliation has a starting point.
+			if err := tx.Exec(`
+				INSERT INTO billing_ledger_entries
+					(created_at, user_id, account_id, balance, reason, delta, balance_before, balance_after, job_id, operator)
+				SELECT CURRENT_TIMESTAMP, ua.user_id, ua.account_id, ?, ?, ua.period_free_balance, 0, ua.period_free_balance, 0, ?
+				FROM user_accounts AS ua
+				WHERE ua.deleted_at IS NULL
+					AND NOT EXISTS (
+						SELECT 1 FROM billing_ledger_entries AS entry
+						WHERE entry.user_id = ua.user_id AND entry.account_id = ua.account_id AND entry.balance = ?
+					)`,
+				model.BillingLedgerPeriodFree, model.BillingLedgerOpening, "system", model.BillingLedgerPeriodFree,
+			).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec(`
+				INSERT INTO billing_ledger_entries
+					(created_at, user_id, account_id, balance, reason, delta, balance_before, balance_after, job_id, operator)
+				SELECT CURRENT_TIMESTAMP, u.id, 0, ?, ?, u.extra_balance, 0, u.extra_balance, 0, ?
+				FROM users AS u
+				WHERE u.deleted_at IS NULL
+					AND NOT EXISTS (
+						SELECT 1 FROM billing_ledger_entries AS entry
+						WHERE entry.user_id = u.id AND entry.balance = ?
+					)`,
+				model.BillingLedgerExtra, model.BillingLedgerOpening, "system", model.BillingLedgerExtra,
+			).Error; err != nil {
+				return err
+			}
+			config := billingLedgerReconcileCronJobConfig()
+			return tx.Where("name = ?", config.Name).FirstOrCreate(config).Error
+		},
+		Rollback: func(tx *gorm.DB) error {
+			if err := tx.Unscoped().Where("name = ?", "billing-ledger-reconcile").
+				Delete(&model.CronJobConfig{}).Error; err != nil {
+				return err
+			}
+			return dropTableIfPresent(tx, &model.BillingLedgerEntry{})
+		},
+	}
+}
+
+func billingBudgetMigration() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "202608201000",
+		Migrate: func(tx *gorm.DB) error {
+			return createTableIfMissing(tx, &model.BillingBudget{})
+		},
+		Rollback: func(tx *gorm.DB) error {
+			return dropTableIfPresent(tx, &model.BillingBudget{})
+		},
+	}
+}
+
+func fairShareMigration() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "202608251000",
+		Migrate: func(tx *gorm.DB) error {
+			if err := addColumnIfMissing(tx, "accounts", &model.Account{}, "FairShare"); err != nil {
+				return err
+			}
+			return addColumnIfMissing(tx, "user_accounts", &model.UserAccount{}, "FairShare")
+		},
+		Rollback: func(tx *gorm.DB) error {
+			if err := dropColumnIfPresent(tx, "user_accounts", &model.UserAccount{}, "FairShare"); err != nil {
+				return err
+			}
+			return dropColumnIfPresent(tx, "accounts", &model.Account{}, "FairShare")
+		},
+	}
+}
+
+func storageQuotaMigration() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "202609011000",
+		Migrate: func(tx *gorm.DB) error {
+			return createTableIfMissing(tx, &model.StorageQuota{})
+		},
+		Rollback: func(tx *gorm.DB) error {
+			return dropTableIfPresent(tx, &model.StorageQuota{})
+		},
+	}
+}
+
+func storageTrashMigration() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "202609051000",
+		Migrate: func(tx *gorm.DB) error {
+			if err := createTableIfMissing(tx, &model.StorageTrashItem{}); err != nil {
+				return err
+			}
+			config := storageTrashCronJobConfig()
+			return tx.Where("name = ?", config.Name).FirstOrCreate(config).Error
+		},
+		Rollback: func(tx *gorm.DB) error {
+			if err := tx.Unscoped().Where("name = ?", storageTrashCronJobConfig().Name).
+				Delete(&model.CronJobConfig{}).Error; err != nil {
+				return err
+			}
+			return dropTableIfPresent(tx, &model.StorageTrashItem{})
+		},
+	}
+}
+
+func datasetVersionMigration() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "202609081000",
+		Migrate: func(tx *gorm.DB) error {
+			if err := createTableIfMissing(tx, &model.DatasetVersion{}); err != nil {
+				return err
+			}
+			return addColumnIfMissing(tx, "jobs", &model.Job{}, "DatasetVersions")
+		},
+		Rollback: func(tx *gorm.DB) error {
+			if err := dropColumnIfPresent(tx, "jobs", &model.Job{}, "DatasetVersions"); err != nil {
+				return err
+			}
+			return dropTableIfPresent(tx, &model.DatasetVersion{})
+		},
+	}
+}
+
+func datasetManifestMigration() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "202609101000",
+		Migrate: func(tx *gorm.DB) error {
+			return createTableIfMissing(tx, &model.DatasetManifest{})
+		},
+		Rollback: func(tx *gorm.DB) error {
+			return dropTableIfPresent(tx, &model.DatasetManifest{})
+		},
+	}
+}
+
+// datasetAccessMigration routes dataset access requests to the dataset owner and lets the grants
+// they approve expire.
+func datasetAccessMigration() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "202609121000",
+		Migrate: func(tx *gorm.DB) error {
+			if err := addColumnIfMissing(tx, "approval_orders", &model.ApprovalOrder{}, "AssigneeID"); err != nil {
+				return err
+			}
+			if err := createIndexIfMissing(tx, "approval_orders", &model.ApprovalOrder{}, "AssigneeID"); err != nil {
+				return err
+			}
+			for _, grant := range []struct {
+				table string
+				value any
+			}{{"user_datasets", &model.UserDataset{}}, {"account_datasets", &model.AccountDataset{}}} {
+				if err := addColumnIfMissing(tx, grant.table, grant.value, "ExpiresAt"); err != nil {
+					return err
+				}
+				if err := createIndexIfMissing(tx, grant.table, grant.value, "ExpiresAt"); err != nil {
+					return err
+				}
+			}
+			config := revokeExpiredSharesCronJobConfig()
+			return tx.Where("name = ?", config.Name).FirstOrCreate(config).Error
+		},
+		Rollback: func(tx *gorm.DB) error {
+			if err := tx.Unscoped().Where("name = ?", revokeExpiredSharesCronJobConfig().Name).
+				Delete(&model.CronJobConfig{}).Error; err != nil {
+				return err
+			}
+			if err := dropColumnIfPresent(tx, "account_datasets", &model.AccountDataset{}, "ExpiresAt"); err != nil {
+				return err
+			}
+			if err := dropColumnIfPresent(tx, "user_datasets", &model.UserDataset{}, "ExpiresAt"); err != nil {
+				return err
+			}
+			return dropColumnIfPresent(tx, "approval_orders", &model.ApprovalOrder{}, "AssigneeID")
+		},
+	}
+}
+
+// imageShareExpiryMigration gives image shares the same optional expiry as dataset shares; the
+// revoke-expired-shares cron job removes both.
+func imageShareExpiryMigration() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "202609141000",
+		Migrate: func(tx *gorm.DB) error {
+			for _, grant := range []struct {
+				table string
+				value any
+			}{{"image_users", &model.ImageUser{}}, {"image_accounts", &model.ImageAccount{}}} {
+				if err := addColumnIfMissing(tx, grant.table, grant.value, "ExpiresAt"); err != nil {
+					return err
+				}
+				if err := createIndexIfMissing(tx, grant.table, grant.value, "ExpiresAt"); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Rollback: func(tx *gorm.DB) error {
+			if err := dropColumnIfPresent(tx, "image_accounts", &model.ImageAccount{}, "ExpiresAt"); err != nil {
+				return err
+			}
+			return dropColumnIfPresent(tx, "image_users", &model.ImageUser{}, "ExpiresAt")
+		},
+	}
+}
+
+// remoteDownloadSourceFields are the download columns describing S3 and HTTP sources.
+var remoteDownloadSourceFields = []string{
+	"RemoteURL", "S3Endpoint", "S3Region", "S3AccessKey", "S3SecretKey", "Extract", "Checksum",
+}
+
+// remoteDownloadSourceMigration lets download tasks pull from S3-compatible buckets and HTTP URLs.
+func remoteDownloadSourceMigration() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "202609211000",
+		Migrate: func(tx *gorm.DB) error {
+			for _, field := range remoteDownloadSourceFields {
+				if err := addColumnIfMissing(tx, "model_downloads", &model.ModelDownload{}, field); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Rollback: func(tx *gorm.DB) error {
+			for _, field := range remoteDownloadSourceFields {
+				if err := dropColumnIfPresent(tx, "model_downloads", &model.ModelDownload{}, field); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func imageScanMigration() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "202610011000",
+		Migrate: func(tx *gorm.DB) error {
+			return createTableIfMissing(tx, &model.ImageScan{})
+		},
+		Rollback: func(tx *gorm.DB) error {
+			return dropTableIfPresent(tx, &model.ImageScan{})
+		},
+	}
+}
+
+// imageGCMigration adds the marks of images whose owners were told about their deletion, and the
+// suspended clean-stale-images cron job that writes them.
+func imageGCMigration() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "202610081000",
+		Migrate: func(tx *gorm.DB) error {
+			if err := createTableIfMissing(tx, &model.ImageGCMark{}); err != nil {
+				return err
+			}
+			config := imageGCCronJobConfig()
+			return tx.Where("name = ?", config.Name).FirstOrCreate(config).Error
+		},
+		Rollback: func(tx *gorm.DB) error {
+			if err := tx.Unscoped().Where("name = ?", imageGCCronJobConfig().Name).
+				Delete(&model.CronJobConfig{}).Error; err != nil {
+				return err
+			}
+			return dropTableIfPresent(tx, &model.ImageGCMark{})
+		},
+	}
+}
+
+// imageGCCronJobConfig deletes images of the inner registry that fall out of the retention
+// policy. It deletes user data, so admins review the dry-run report and enable it themselves.
+func imageGCCronJobConfig() *model.CronJobConfig {
+	return &model.CronJobConfig{
+		Name:   "clean-stale-images",
+		Type:   model.CronJobTypeCleanerFunc,
+		Spec:   "0 3 * * *",
+		Status: model.CronJobConfigStatusSuspended,
+		Config: datatypes.JSON(
+			`{"keepLastPerRepository": 5, "unusedDays": 90, "deleteUntagged": false, "skipShared": true, "noticeDays": 7}`,
+		),
+		EntryID: -1,
+	}
+}
+
+// budgetNoticeRuleMigration subscribes rules saved before budgets existed to the budget notices,
+// which they had no way to opt into. Rolling back keeps the subscriptions.
+func budgetNoticeRuleMigration() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "202610181000",
+		Migrate: func(tx *gorm.DB) error {
+			var rules []*model.NotificationRule
+			if err := tx.Find(&rules).Error; err != nil {
+				return err
+			}
+			for _, rule := range rules {
+				events := rule.Events.Data()
+				for _, event := range []model.NotificationEvent{
+					model.NotificationEventBudgetThreshold, model.NotificationEventBudgetCapReached,
+				} {
+					if !slices.Contains(events, event) {
+						events = append(events, event)
+					}
+				}
+				if err := tx.Model(rule).Update("events", datatypes.NewJSONType(events)).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Rollback: func(*gorm.DB) error {
+			return nil
+		},
+	}
+}
+
+func s3AccessKeyMigration() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "202610181100",
+		Migrate: func(tx *gorm.DB) error {
+			return createTableIfMissing(tx, &model.S3AccessKey{})
+		},
+		Rollback: func(tx *gorm.DB) error {
+			return dropTableIfPresent(tx, &model.S3AccessKey{})
+		},
+	}
+}
+
+// revokeExpiredSharesCronJobConfig removes grants past their expiry. Approved access requests
+// promise that expiry, so it is enabled by default.
+func revokeExpiredSharesCronJobConfig() *model.CronJobConfig {
+	return &model.CronJobConfig{
+		Name:    "revoke-expired-shares",
+		Type:    model.CronJobTypeCleanerFunc,
+		Spec:    "*/10 * * * *",
+		Status:  model.CronJobConfigStatusIdle,
+		Config:  datatypes.JSON("{}"),
+		EntryID: -1,
+	}
+}
+
+// storageTrashCronJobConfig expires trashed files after the retention period. Without it the
+// trash would only grow, so it is enabled by default.
+func storageTrashCronJobConfig() *model.CronJobConfig {
+	return &model.CronJobConfig{
+		Name:    "clean-storage-trash",
+		Type:    model.CronJobTypeCleanerFunc,
+		Spec:    "0 4 * * *",
+		Status:  model.CronJobConfigStatusIdle,
+		Config:  datatypes.JSON(`{"retentionDays": 30}`),
+		EntryID: -1,
+	}
+}
+
+// billingLedgerReconcileCronJobConfig only reads balances, so it is enabled by default.
+func billingLedgerReconcileCronJobConfig() *model.CronJobConfig {
+	return &model.CronJobConfig{
+		Name:    "billing-ledger-reconcile",
+		Type:    model.CronJobTypePatrolFunc,
+		Spec:    "30 3 * * *",
+		Status:  model.CronJobConfigStatusIdle,
+		Config:  datatypes.JSON("{}"),
+		EntryID: -1,
+	}
+}
+
 func createTableIfMissing(db *gorm.DB, value any) error {
 	if db.Migrator().HasTable(value) {
 		return nil
@@ -1500,6 +1944,23 @@ func main() {
 		},
 		modelDatasetSourceMigration(),
 		modelDownloadSubmissionMigration(),
+		workflowMigration(),
+		jobArrayMigration(),
+		notificationRuleMigration(),
+		billingLedgerMigration(),
+		billingBudgetMigration(),
+		fairShareMigration(),
+		storageQuotaMigration(),
+		storageTrashMigration(),
+		datasetVersionMigration(),
+		datasetManifestMigration(),
+		datasetAccessMigration(),
+		imageShareExpiryMigration(),
+		remoteDownloadSourceMigration(),
+		imageScanMigration(),
+		imageGCMigration(),
+		budgetNoticeRuleMigration(),
+		s3AccessKeyMigration(),
 	})
 
 	m.InitSchema(func(tx *gorm.DB) error {
@@ -1535,6 +1996,21 @@ func main() {
 			&model.OperationLog{},
 			&model.PrequeueConfig{},
 			&model.QueueQuotaLimit{},
+			&model.Workflow{},
+			&model.WorkflowNode{},
+			&model.JobArray{},
+			&model.JobArrayTask{},
+			&model.NotificationRule{},
+			&model.NotificationDigestItem{},
+			&model.BillingLedgerEntry{},
+			&model.BillingBudget{},
+			&model.StorageQuota{},
+			&model.StorageTrashItem{},
+			&model.DatasetVersion{},
+			&model.DatasetManifest{},
+			&model.ImageScan{},
+			&model.ImageGCMark{},
+			&model.S3AccessKey{},
 		)
 		if err != nil {
 			return err
@@ -1645,6 +2121,11 @@ func main() {
 				Config:  datatypes.JSON(`{"waitMinitues": 5, "jobTypes": ["custom"]}`),
 				EntryID: -1,
 			},
+			notificationDispatchCronJobConfig(),
+			billingLedgerReconcileCronJobConfig(),
+			storageTrashCronJobConfig(),
+			revokeExpiredSharesCronJobConfig(),
+			imageGCCronJobConfig(),
 		}
 
 		for _, config := range initialCronJobConfigs {
@@ -15,8 +15,10 @@
 package main
 
 import (
+	"slices"
 	"testing"
 
+	"gorm.io/datatypes"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 
@@ -126,3 +128,558 @@ func TestModelDownloadSubmissionMigrationAndRollback(t *testing.T) {
 		t.Fatal("model download submission table remains after rollback")
 	}
 }
+
+func TestWorkflowMigrationAndRollback(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:workflow_migration?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	migration := workflowMigration()
+	for range 2 {
+		if err := migration.Migrate(db); err != nil {
+			t.Fatalf("migrate: %v", err)
+		}
+	}
+	for _, table := range []any{&model.Workflow{}, &model.WorkflowNode{}} {
+		if !db.Migrator().HasTable(table) {
+			t.Fatalf("missing migrated table %T", table)
+		}
+	}
+	if !db.Migrator().HasIndex(&model.WorkflowNode{}, "idx_workflow_node_name") {
+		t.Fatal("workflow_nodes is missing the per-workflow node name index")
+	}
+
+	for range 2 {
+		if err := migration.Rollback(db); err != nil {
+			t.Fatalf("rollback: %v", err)
+		}
+	}
+	if db.Migrator().HasTable(&model.Workflow{}) || db.Migrator().HasTable(&model.WorkflowNode{}) {
+		t.Fatal("workflow tables remain after rollback")
+	}
+}
+
+func TestJobArrayMigrationAndRollback(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:job_array_migration?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	migration := jobArrayMigration()
+	for range 2 {
+		if err := migration.Migrate(db); err != nil {
+			t.Fatalf("migrate: %v", err)
+		}
+	}
+	for _, table := range []any{&model.JobArray{}, &model.JobArrayTask{}} {
+		if !db.Migrator().HasTable(table) {
+			t.Fatalf("missing migrated table %T", table)
+		}
+	}
+	if !db.Migrator().HasIndex(&model.JobArrayTask{}, "idx_job_array_task_index") {
+		t.Fatal("job_array_tasks is missing the per-array index")
+	}
+
+	for range 2 {
+		if err := migration.Rollback(db); err != nil {
+			t.Fatalf("rollback: %v", err)
+		}
+	}
+	if db.Migrator().HasTable(&model.JobArray{}) || db.Migrator().HasTable(&model.JobArrayTask{}) {
+		t.Fatal("job array tables remain after rollback")
+	}
+}
+
+func TestNotificationRuleMigrationAndRollback(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:notification_rule_migration?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.Migrator().CreateTable(&model.CronJobConfig{}); err != nil {
+		t.Fatalf("create cron_job_configs: %v", err)
+	}
+	migration := notificationRuleMigration()
+	for range 2 {
+		if err := migration.Migrate(db); err != nil {
+			t.Fatalf("migrate: %v", err)
+		}
+	}
+	for _, table := range []any{&model.NotificationRule{}, &model.NotificationDigestItem{}} {
+		if !db.Migrator().HasTable(table) {
+			t.Fatalf("missing migrated table %T", table)
+		}
+	}
+	var count int64
+	if err := db.Model(&model.CronJobConfig{}).Where("name = ?", "notification-dispatch").Count(&count).Error; err != nil || count != 1 {
+		t.Fatalf("notification-dispatch cron configs = %d, %v; want 1", count, err)
+	}
+
+	for range 2 {
+		if err := migration.Rollback(db); err != nil {
+			t.Fatalf("rollback: %v", err)
+		}
+	}
+	if db.Migrator().HasTable(&model.NotificationRule{}) || db.Migrator().HasTable(&model.NotificationDigestItem{}) {
+		t.Fatal("notification tables remain after rollback")
+	}
+	if err := db.Model(&model.CronJobConfig{}).Where("name = ?", "notification-dispatch").Count(&count).Error; err != nil || count != 0 {
+		t.Fatalf("notification-dispatch cron configs after rollback = %d, %v; want 0", count, err)
+	}
+}
+
+//nolint:gocyclo // One integration test verifies forward, repeated, rollback, and repeated rollback behavior.
+func TestBillingLedgerMigrationAndRollback(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:billing_ledger_migration?mode=memory&cache=shared"), &gorm.Config{
+		DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.Migrator().CreateTable(&model.CronJobConfig{}, &model.User{}, &model.UserAccount{}); err != nil {
+		t.Fatalf("create tables: %v", err)
+	}
+	if err := db.Create(&model.User{Name: "alice", ExtraBalance: 5}).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	if err := db.Create(&model.UserAccount{UserID: 1, AccountID: 2, PeriodFreeBalance: 3}).Error; err != nil {
+		t.Fatalf("create user account: %v", err)
+	}
+
+	migration := billingLedgerMigration()
+	for range 2 {
+		if err := migration.Migrate(db); err != nil {
+			t.Fatalf("migrate: %v", err)
+		}
+	}
+	var entries []model.BillingLedgerEntry
+	if err := db.Order("balance").Find(&entries).Error; err != nil {
+		t.Fatalf("load ledger: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d opening entries, want one per balance", len(entries))
+	}
+	if entries[0].Balance != model.BillingLedgerExtra || entries[0].BalanceAfter != 5 ||
+		entries[1].Balance != model.BillingLedgerPeriodFree || entries[1].BalanceAfter != 3 ||
+		entries[0].Reason != model.BillingLedgerOpening {
+		t.Fatalf("opening entries = %+v", entries)
+	}
+	var count int64
+	if err := db.Model(&model.CronJobConfig{}).Where("name = ?", "billing-ledger-reconcile").Count(&count).Error; err != nil || count != 1 {
+		t.Fatalf("billing-ledger-reconcile cron configs = %d, %v; want 1", count, err)
+	}
+
+	for range 2 {
+		if err := migration.Rollback(db); err != nil {
+			t.Fatalf("rollback: %v", err)
+		}
+	}
+	if db.Migrator().HasTable(&model.BillingLedgerEntry{}) {
+		t.Fatal("billing ledger table remains after rollback")
+	}
+	if err := db.Model(&model.CronJobConfig{}).Where("name = ?", "billing-ledger-reconcile").Count(&count).Error; err != nil || count != 0 {
+		t.Fatalf("billing-ledger-reconcile cron configs after rollback = %d, %v; want 0", count, err)
+	}
+}
+
+func TestBillingBudgetMigrationAndRollback(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:billing_budget_migration?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	migration := billingBudgetMigration()
+	for range 2 {
+		if err := migration.Migrate(db); err != nil {
+			t.Fatalf("migrate: %v", err)
+		}
+	}
+	if !db.Migrator().HasIndex(&model.BillingBudget{}, "idx_billing_budget_scope") {
+		t.Fatal("billing_budgets is missing the scope index")
+	}
+	for range 2 {
+		if err := migration.Rollback(db); err != nil {
+			t.Fatalf("rollback: %v", err)
+		}
+	}
+	if db.Migrator().HasTable(&model.BillingBudget{}) {
+		t.Fatal("billing_budgets remains after rollback")
+	}
+}
+
+func TestFairShareMigrationAndRollback(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:fair_share_migration?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	for _, statement := range []string{
+		`CREATE TABLE accounts (id integer primary key, name text)`,
+		`CREATE TABLE user_accounts (id integer primary key, user_id integer, account_id integer)`,
+	} {
+		if err := db.Exec(statement).Error; err != nil {
+			t.Fatalf("create legacy table: %v", err)
+		}
+	}
+	migration := fairShareMigration()
+	for range 2 {
+		if err := migration.Migrate(db); err != nil {
+			t.Fatalf("migrate: %v", err)
+		}
+	}
+	for table, value := range map[string]any{
+		"accounts":      &model.Account{},
+		"user_accounts": &model.UserAccount{},
+	} {
+		if !db.Table(table).Migrator().HasColumn(value, "FairShare") {
+			t.Fatalf("%s is missing fair_share", table)
+		}
+	}
+	for range 2 {
+		if err := migration.Rollback(db); err != nil {
+			t.Fatalf("rollback: %v", err)
+		}
+	}
+	if db.Table("accounts").Migrator().HasColumn(&model.Account{}, "FairShare") {
+		t.Fatal("accounts.fair_share remains after rollback")
+	}
+}
+
+func TestStorageQuotaMigrationAndRollback(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:storage_quota_migration?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	migration := storageQuotaMigration()
+	for range 2 {
+		if err := migration.Migrate(db); err != nil {
+			t.Fatalf("migrate: %v", err)
+		}
+	}
+	if !db.Migrator().HasIndex(&model.StorageQuota{}, "idx_storage_quota_space") {
+		t.Fatal("storage_quota is missing the space index")
+	}
+	for range 2 {
+		if err := migration.Rollback(db); err != nil {
+			t.Fatalf("rollback: %v", err)
+		}
+	}
+	if db.Migrator().HasTable(&model.StorageQuota{}) {
+		t.Fatal("storage_quota remains after rollback")
+	}
+}
+
+func TestStorageTrashMigrationAndRollback(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:storage_trash_migration?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.Migrator().CreateTable(&model.CronJobConfig{}); err != nil {
+		t.Fatalf("create tables: %v", err)
+	}
+	migration := storageTrashMigration()
+	for range 2 {
+		if err := migration.Migrate(db); err != nil {
+			t.Fatalf("migrate: %v", err)
+		}
+	}
+	if !db.Migrator().HasIndex(&model.StorageTrashItem{}, "idx_storage_trash_space") {
+		t.Fatal("storage_trash_items is missing the space index")
+	}
+	var count int64
+	if err := db.Model(&model.CronJobConfig{}).Where("name = ?", "clean-storage-trash").Count(&count).Error; err != nil || count != 1 {
+		t.Fatalf("clean-storage-trash cron configs = %d, %v; want 1", count, err)
+	}
+	for range 2 {
+		if err := migration.Rollback(db); err != nil {
+			t.Fatalf("rollback: %v", err)
+		}
+	}
+	if db.Migrator().HasTable(&model.StorageTrashItem{}) {
+		t.Fatal("storage_trash_items remains after rollback")
+	}
+	if err := db.Model(&model.CronJobConfig{}).Where("name = ?", "clean-storage-trash").Count(&count).Error; err != nil || count != 0 {
+		t.Fatalf("clean-storage-trash cron configs after rollback = %d, %v; want 0", count, err)
+	}
+}
+
+func TestDatasetVersionMigrationAndRollback(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:dataset_version_migration?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	for _, statement := range []string{
+		`CREATE TABLE datasets (id integer primary key, name text, url text)`,
+		`CREATE TABLE jobs (id integer primary key, job_name text, template text)`,
+	} {
+		if err := db.Exec(statement).Error; err != nil {
+			t.Fatalf("create legacy table: %v", err)
+		}
+	}
+	migration := datasetVersionMigration()
+	for range 2 {
+		if err := migration.Migrate(db); err != nil {
+			t.Fatalf("migrate: %v", err)
+		}
+	}
+	if !db.Migrator().HasIndex(&model.DatasetVersion{}, "idx_dataset_versions_version") {
+		t.Fatal("dataset_versions is missing the version index")
+	}
+	if !db.Table("jobs").Migrator().HasColumn(&model.Job{}, "DatasetVersions") {
+		t.Fatal("jobs is missing dataset_versions")
+	}
+	for range 2 {
+		if err := migration.Rollback(db); err != nil {
+			t.Fatalf("rollback: %v", err)
+		}
+	}
+	if db.Migrator().HasTable(&model.DatasetVersion{}) {
+		t.Fatal("dataset_versions remains after rollback")
+	}
+	if db.Table("jobs").Migrator().HasColumn(&model.Job{}, "DatasetVersions") {
+		t.Fatal("jobs.dataset_versions remains after rollback")
+	}
+}
+
+func TestDatasetManifestMigrationAndRollback(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:dataset_manifest_migration?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.Exec(`CREATE TABLE datasets (id integer primary key, name text, url text)`).Error; err != nil {
+		t.Fatalf("create legacy table: %v", err)
+	}
+	migration := datasetManifestMigration()
+	for range 2 {
+		if err := migration.Migrate(db); err != nil {
+			t.Fatalf("migrate: %v", err)
+		}
+	}
+	if !db.Migrator().HasColumn(&model.DatasetManifest{}, "VerifyRequested") {
+		t.Fatal("dataset_manifests is missing verify_requested")
+	}
+	for range 2 {
+		if err := migration.Rollback(db); err != nil {
+			t.Fatalf("rollback: %v", err)
+		}
+	}
+	if db.Migrator().HasTable(&model.DatasetManifest{}) {
+		t.Fatal("dataset_manifests remains after rollback")
+	}
+}
+
+func TestDatasetAccessMigrationAndRollback(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:dataset_access_migration?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	for _, statement := range []string{
+		`CREATE TABLE approval_orders (id integer primary key, name text, type text, status text)`,
+		`CREATE TABLE user_datasets (id integer, user_id integer, dataset_id integer, deleted_at datetime)`,
+		`CREATE TABLE account_datasets (id integer, account_id integer, dataset_id integer, deleted_at datetime)`,
+	} {
+		if err := db.Exec(statement).Error; err != nil {
+			t.Fatalf("create legacy table: %v", err)
+		}
+	}
+	if err := db.Migrator().CreateTable(&model.CronJobConfig{}); err != nil {
+		t.Fatalf("create tables: %v", err)
+	}
+	migration := datasetAccessMigration()
+	for range 2 {
+		if err := migration.Migrate(db); err != nil {
+			t.Fatalf("migrate: %v", err)
+		}
+	}
+	if !db.Table("approval_orders").Migrator().HasColumn(&model.ApprovalOrder{}, "AssigneeID") {
+		t.Fatal("approval_orders is missing assignee_id")
+	}
+	for _, table := range []string{"user_datasets", "account_datasets"} {
+		if !db.Migrator().HasColumn(table, "expires_at") {
+			t.Fatalf("%s is missing expires_at", table)
+		}
+	}
+	var count int64
+	if err := db.Model(&model.CronJobConfig{}).Where("name = ?", "revoke-expired-shares").Count(&count).Error; err != nil || count != 1 {
+		t.Fatalf("revoke-expired-shares cron configs = %d, %v; want 1", count, err)
+	}
+	for range 2 {
+		if err := migration.Rollback(db); err != nil {
+			t.Fatalf("rollback: %v", err)
+		}
+	}
+	for _, table := range []string{"user_datasets", "account_datasets"} {
+		if db.Migrator().HasColumn(table, "expires_at") {
+			t.Fatalf("%s.expires_at remains after rollback", table)
+		}
+	}
+	if db.Table("approval_orders").Migrator().HasColumn(&model.ApprovalOrder{}, "AssigneeID") {
+		t.Fatal("approval_orders.assignee_id remains after rollback")
+	}
+	if err := db.Model(&model.CronJobConfig{}).Where("name = ?", "revoke-expired-shares").Count(&count).Error; err != nil || count != 0 {
+		t.Fatalf("revoke-expired-shares cron configs after rollback = %d, %v; want 0", count, err)
+	}
+}
+
+func TestImageShareExpiryMigrationAndRollback(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:image_share_expiry_migration?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	for _, statement := range []string{
+		`CREATE TABLE image_users (id integer primary key, image_id integer, user_id integer, deleted_at datetime)`,
+		`CREATE TABLE image_accounts (id integer primary key, image_id integer, account_id integer, deleted_at datetime)`,
+	} {
+		if err := db.Exec(statement).Error; err != nil {
+			t.Fatalf("create legacy table: %v", err)
+		}
+	}
+	migration := imageShareExpiryMigration()
+	for range 2 {
+		if err := migration.Migrate(db); err != nil {
+			t.Fatalf("migrate: %v", err)
+		}
+	}
+	for _, table := range []string{"image_users", "image_accounts"} {
+		if !db.Migrator().HasColumn(table, "expires_at") {
+			t.Fatalf("%s is missing expires_at", table)
+		}
+	}
+	for range 2 {
+		if err := migration.Rollback(db); err != nil {
+			t.Fatalf("rollback: %v", err)
+		}
+	}
+	for _, table := range []string{"image_users", "image_accounts"} {
+		if db.Migrator().HasColumn(table, "expires_at") {
+			t.Fatalf("%s.expires_at remains after rollback", table)
+		}
+	}
+}
+
+func TestRemoteDownloadSourceMigrationAndRollback(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:remote_download_source_migration?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.Exec(`CREATE TABLE model_downloads (id integer primary key, name text, source text, deleted_at datetime)`).Error; err != nil {
+		t.Fatalf("create legacy table: %v", err)
+	}
+	columns := []string{"remote_url", "s3_endpoint", "s3_region", "s3_access_key", "s3_secret_key", "extract", "checksum"}
+	migration := remoteDownloadSourceMigration()
+	for range 2 {
+		if err := migration.Migrate(db); err != nil {
+			t.Fatalf("migrate: %v", err)
+		}
+	}
+	for _, column := range columns {
+		if !db.Migrator().HasColumn("model_downloads", column) {
+			t.Fatalf("model_downloads is missing %s", column)
+		}
+	}
+	for range 2 {
+		if err := migration.Rollback(db); err != nil {
+			t.Fatalf("rollback: %v", err)
+		}
+	}
+	for _, column := range columns {
+		if db.Migrator().HasColumn("model_downloads", column) {
+			t.Fatalf("model_downloads.%s remains after rollback", column)
+		}
+	}
+}
+
+func TestImageScanMigrationAndRollback(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:image_scan_migration?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	migration := imageScanMigration()
+	for range 2 {
+		if err := migration.Migrate(db); err != nil {
+			t.Fatalf("migrate: %v", err)
+		}
+	}
+	for _, field := range []string{"ImageID", "Status", "Findings", "MaxSeverity"} {
+		if !db.Migrator().HasColumn(&model.ImageScan{}, field) {
+			t.Fatalf("image_scans is missing %s", field)
+		}
+	}
+	for range 2 {
+		if err := migration.Rollback(db); err != nil {
+			t.Fatalf("rollback: %v", err)
+		}
+	}
+	if db.Migrator().HasTable(&model.ImageScan{}) {
+		t.Fatal("image_scans remains after rollback")
+	}
+}
+
+func TestImageGCMigrationAndRollback(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:image_gc_migration?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.Migrator().CreateTable(&model.CronJobConfig{}); err != nil {
+		t.Fatalf("create tables: %v", err)
+	}
+	migration := imageGCMigration()
+	for range 2 {
+		if err := migration.Migrate(db); err != nil {
+			t.Fatalf("migrate: %v", err)
+		}
+	}
+	for _, field := range []string{"ImageID", "UserID", "Reason", "DeleteAfter"} {
+		if !db.Migrator().HasColumn(&model.ImageGCMark{}, field) {
+			t.Fatalf("image_gc_marks is missing %s", field)
+		}
+	}
+	var config model.CronJobConfig
+	if err := db.Where("name = ?", "clean-stale-images").First(&config).Error; err != nil {
+		t.Fatalf("load clean-stale-images cron config: %v", err)
+	}
+	if config.Status != model.CronJobConfigStatusSuspended {
+		t.Fatalf("clean-stale-images status = %s, want suspended", config.Status)
+	}
+	for range 2 {
+		if err := migration.Rollback(db); err != nil {
+			t.Fatalf("rollback: %v", err)
+		}
+	}
+	if db.Migrator().HasTable(&model.ImageGCMark{}) {
+		t.Fatal("image_gc_marks remains after rollback")
+	}
+	var count int64
+	if err := db.Model(&model.CronJobConfig{}).Where("name = ?", "clean-stale-images").Count(&count).Error; err != nil || count != 0 {
+		t.Fatalf("clean-stale-images cron configs after rollback = %d, %v; want 0", count, err)
+	}
+}
+
+func TestBudgetNoticeRuleMigration(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:budget_notice_rule_migration?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.Migrator().CreateTable(&model.NotificationRule{}); err != nil {
+		t.Fatalf("create notification_rules: %v", err)
+	}
+	rule := &model.NotificationRule{
+		UserID: 7,
+		Events: datatypes.NewJSONType([]model.NotificationEvent{
+			model.NotificationEventJobFailed, model.NotificationEventBudgetCapReached,
+		}),
+	}
+	if err := db.Create(rule).Error; err != nil {
+		t.Fatalf("create rule: %v", err)
+	}
+	migration := budgetNoticeRuleMigration()
+	for range 2 {
+		if err := migration.Migrate(db); err != nil {
+			t.Fatalf("migrate: %v", err)
+		}
+	}
+	if err := db.First(rule, rule.ID).Error; err != nil {
+		t.Fatalf("load rule: %v", err)
+	}
+	if events := rule.Events.Data(); !slices.Equal(events, []model.NotificationEvent{
+		model.NotificationEventJobFailed, model.NotificationEventBudgetCapReached, model.NotificationEventBudgetThreshold,
+	}) {
+		t.Fatalf("existing rule events = %v, want budget notices subscribed once", events)
+	}
+}
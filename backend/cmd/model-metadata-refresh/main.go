@@ -117,6 +117,11 @@ func main() {
 		builder := db.Where("status = ? AND id > ?", model.ModelDownloadStatusReady, cursor)
 		if *source != "" {
 			builder = builder.Where("source = ?", *source)
+		} else {
+			// S3 and HTTP downloads have no source repository to refresh from.
+			builder = builder.Where("source IN ?", []model.ModelSource{
+				model.ModelSourceHuggingFace, model.ModelSourceModelScope,
+			})
 		}
 		if *missingLogoOnly {
 			builder = builder.Where(`
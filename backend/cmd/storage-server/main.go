@@ -2,7 +2,9 @@ package main
 
 import (
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -51,6 +53,52 @@ func normalizePort(port string) string {
 	return ":" + port
 }
 
+// quotaScanInterval reads CRATER_STORAGE_QUOTA_SCAN_INTERVAL as a Go duration, e.g. "30m".
+func quotaScanInterval() time.Duration {
+	return durationEnv("CRATER_STORAGE_QUOTA_SCAN_INTERVAL", storage.DefaultQuotaScanInterval)
+}
+
+func datasetIndexInterval() time.Duration {
+	return durationEnv("CRATER_STORAGE_DATASET_INDEX_INTERVAL", storage.DefaultDatasetIndexInterval)
+}
+
+func durationEnv(name string, fallback time.Duration) time.Duration {
+	raw := firstNonEmptyEnv(name)
+	if raw == "" {
+		return fallback
+	}
+	interval, err := time.ParseDuration(raw)
+	if err != nil || interval <= 0 {
+		klog.Warningf("invalid %s %q, using %s", name, raw, fallback)
+		return fallback
+	}
+	return interval
+}
+
+// runS3Gateway serves the S3-compatible API next to the WebDAV server.
+func runS3Gateway(addr string) {
+	r := gin.Default()
+	storage.RegisterS3Routes(r)
+	klog.Infof("storage-server S3 gateway starting on %s", addr)
+	if err := r.Run(addr); err != nil {
+		klog.Fatalf("failed to run S3 gateway: %v", err)
+	}
+}
+
+// archiveMaxBytes reads CRATER_STORAGE_ARCHIVE_MAX_BYTES, the file data cap of one archive download.
+func archiveMaxBytes() int64 {
+	raw := firstNonEmptyEnv("CRATER_STORAGE_ARCHIVE_MAX_BYTES")
+	if raw == "" {
+		return storage.DefaultArchiveMaxBytes
+	}
+	maxBytes, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || maxBytes <= 0 {
+		klog.Warningf("invalid CRATER_STORAGE_ARCHIVE_MAX_BYTES %q, using %d", raw, storage.DefaultArchiveMaxBytes)
+		return storage.DefaultArchiveMaxBytes
+	}
+	return maxBytes
+}
+
 func main() {
 	initVersionInfo()
 
@@ -79,7 +127,15 @@ func main() {
 		klog.Fatalf("failed to create storage root directory %s: %v", rootDir, err)
 	}
 	storage.SetRootDir(rootDir)
+	storage.SetArchiveMaxBytes(archiveMaxBytes())
 	go storage.StartCheckSpace()
+	go storage.StartQuotaScanner(quotaScanInterval())
+	go storage.StartDatasetIndexer(datasetIndexInterval())
+
+	if s3Port := firstNonEmptyEnv("CRATER_STORAGE_S3_PORT"); s3Port != "" {
+		storage.SetS3Endpoint(firstNonEmptyEnv("CRATER_STORAGE_S3_ENDPOINT"))
+		go runS3Gateway(normalizePort(s3Port))
+	}
 
 	r := gin.Default()
 	storage.RegisterRoutes(r)
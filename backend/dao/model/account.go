@@ -40,6 +40,8 @@ type Account struct {
 	BillingIssueAmount        *int64     `gorm:"comment:账户周期发放点数额度(内部微点, 为空表示未配置)"`
 	BillingIssuePeriodMinutes *int       `gorm:"comment:账户周期发放间隔分钟(<=0表示关闭, 为空表示未配置)"`
 	BillingLastIssuedAt       *time.Time `gorm:"comment:账户上次发放时间"`
+	// Fair-share weight used by the prequeue activation policy.
+	FairShare *int `gorm:"comment:账户公平调度份额(为空表示默认份额1)"`
 
 	UserAccounts    []UserAccount
 	AccountDatasets []AccountDataset
@@ -56,4 +58,6 @@ type UserAccount struct {
 	// Billing issue state for current account cycle.
 	BillingIssueAmountOverride *int64 `gorm:"comment:用户在账户内的周期发放额度覆盖(内部微点, 为空表示沿用账户配置)"`
 	PeriodFreeBalance          int64  `gorm:"not null;default:0;comment:用户在当前周期的免费额度剩余(内部微点)"`
+	// Fair-share weight of the user inside the account.
+	FairShare *int `gorm:"comment:用户在账户内的公平调度份额(为空表示默认份额1)"`
 }
@@ -1,6 +1,8 @@
 package model
 
 import (
+	"time"
+
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
@@ -28,6 +30,11 @@ type ApprovalOrderContent struct {
 	ApprovalOrderTypeID         uint   `json:"approvalorderTypeID"`
 	ApprovalOrderExtensionHours uint   `json:"approvalorderExtensionHours"` // 延长小时数
 	ApprovalOrderReason         string `json:"approvalorderReason"`         // 审批原因
+
+	// 以下字段仅用于数据集访问申请：AccountID 不为 0 时为该账户（队列）申请，否则为申请人本人申请；
+	// ExpiresAt 为授权到期时间，为空表示永久，审批人可以在批准时修改
+	ApprovalOrderAccountID uint       `json:"approvalorderAccountID,omitempty"`
+	ApprovalOrderExpiresAt *time.Time `json:"approvalorderExpiresAt,omitempty"`
 }
 
 // ApprovalOrder 审批订单模型
@@ -43,4 +50,6 @@ type ApprovalOrder struct {
 	Creator    User `gorm:"foreignKey:CreatorID"`
 	ReviewerID uint `gorm:"comment:审批者ID"`
 	Reviewer   User `gorm:"foreignKey:ReviewerID"`
+	// AssigneeID 除管理员外可以审批该工单的用户，如数据集访问申请的数据集所有者；0 表示仅管理员审批
+	AssigneeID uint `gorm:"not null;default:0;index;comment:指定审批人ID"`
 }
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// BillingBudgetCapAction is applied to running normal jobs once a hard cap is projected to be exceeded.
+type BillingBudgetCapAction string
+
+const (
+	// BillingBudgetCapStop deletes the job, like an administrator stop.
+	BillingBudgetCapStop BillingBudgetCapAction = "stop"
+	// BillingBudgetCapSuspend aborts the Volcano job and keeps it for a later resume.
+	BillingBudgetCapSuspend BillingBudgetCapAction = "suspend"
+)
+
+// DefaultBillingBudgetAlertPercents are used when a budget is created without thresholds.
+var DefaultBillingBudgetAlertPercents = []int{50, 80, 100}
+
+// BillingBudget limits the spend of an account, or of one member when UserID is set,
+// within the current issue period. Alert percents are relative to the amount issued
+// for the period, the hard cap is an absolute amount.
+type BillingBudget struct {
+	gorm.Model
+	AccountID uint `gorm:"not null;uniqueIndex:idx_billing_budget_scope,priority:1;comment:账户ID"`
+	UserID    uint `gorm:"not null;default:0;uniqueIndex:idx_billing_budget_scope,priority:2;comment:用户ID，0表示整个账户"`
+
+	AlertPercents datatypes.JSONType[[]int] `gorm:"comment:软阈值，周期发放额度的百分比"`
+	// HardCap 为空表示不限制；达到后停止或挂起运行中的普通作业 (内部微点)
+	HardCap   *int64                 `gorm:"type:bigint;comment:周期消费硬上限(内部微点)"`
+	CapAction BillingBudgetCapAction `gorm:"type:varchar(16);not null;default:stop;comment:超出硬上限时的处理方式"`
+}
+
+func (b *BillingBudget) Validate() error {
+	for _, percent := range b.AlertPercents.Data() {
+		if percent <= 0 || percent > 1000 {
+			return fmt.Errorf("alert percent %d must be within (0, 1000]", percent)
+		}
+	}
+	if b.HardCap != nil && *b.HardCap <= 0 {
+		return fmt.Errorf("hard cap must be positive")
+	}
+	if !slices.Contains([]BillingBudgetCapAction{BillingBudgetCapStop, BillingBudgetCapSuspend}, b.CapAction) {
+		return fmt.Errorf("unsupported cap action %q", b.CapAction)
+	}
+	return nil
+}
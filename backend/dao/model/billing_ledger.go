@@ -0,0 +1,68 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// BillingLedgerBalance names the balance a ledger entry moves.
+type BillingLedgerBalance string
+
+const (
+	// BillingLedgerPeriodFree is UserAccount.PeriodFreeBalance of (UserID, AccountID).
+	BillingLedgerPeriodFree BillingLedgerBalance = "period_free"
+	// BillingLedgerExtra is User.ExtraBalance; AccountID is 0.
+	BillingLedgerExtra BillingLedgerBalance = "extra"
+)
+
+// BillingLedgerReason explains why a balance changed.
+type BillingLedgerReason string
+
+const (
+	BillingLedgerOpening    BillingLedgerReason = "opening"
+	BillingLedgerSettlement BillingLedgerReason = "settlement"
+	BillingLedgerIssuance   BillingLedgerReason = "issuance"
+	BillingLedgerAdjustment BillingLedgerReason = "adjustment"
+	BillingLedgerGrant      BillingLedgerReason = "grant"
+)
+
+// ErrBillingLedgerImmutable is returned when code tries to update or delete a ledger entry.
+var ErrBillingLedgerImmutable = errors.New("billing ledger entries are append-only")
+
+// BillingLedgerEntry is one append-only credit or debit of a billing balance.
+// Entries are written in the same transaction as the balance update, so the
+// latest BalanceAfter of a balance always equals its stored value.
+type BillingLedgerEntry struct {
+	ID        uint      `gorm:"primarykey"`
+	CreatedAt time.Time `gorm:"not null;index;comment:记账时间"`
+
+	UserID    uint                 `gorm:"not null;index:idx_billing_ledger_balance,priority:1;comment:用户ID"`
+	AccountID uint                 `gorm:"not null;default:0;index:idx_billing_ledger_balance,priority:2;comment:账户ID, extra 余额为 0"`
+	Balance   BillingLedgerBalance `gorm:"type:varchar(16);not null;index:idx_billing_ledger_balance,priority:3;comment:余额类型"`
+	Reason    BillingLedgerReason  `gorm:"type:varchar(32);not null;index;comment:变动原因"`
+
+	Delta         int64 `gorm:"not null;comment:变动点数(内部微点)"`
+	BalanceBefore int64 `gorm:"not null;comment:变动前余额(内部微点)"`
+	BalanceAfter  int64 `gorm:"not null;comment:变动后余额(内部微点)"`
+
+	JobID    uint   `gorm:"not null;default:0;comment:结算作业ID"`
+	JobName  string `gorm:"type:varchar(256);index;comment:结算作业名称"`
+	Operator string `gorm:"type:varchar(64);comment:操作人, 定时任务为 system"`
+	Note     string `gorm:"type:varchar(512);comment:备注"`
+}
+
+func (BillingLedgerEntry) TableName() string {
+	return "billing_ledger_entries"
+}
+
+// BeforeUpdate rejects updates so the ledger stays append-only.
+func (BillingLedgerEntry) BeforeUpdate(*gorm.DB) error {
+	return ErrBillingLedgerImmutable
+}
+
+// BeforeDelete rejects deletes so the ledger stays append-only.
+func (BillingLedgerEntry) BeforeDelete(*gorm.DB) error {
+	return ErrBillingLedgerImmutable
+}
@@ -1,6 +1,8 @@
 package model
 
 import (
+	"time"
+
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
@@ -40,12 +42,14 @@ type Dataset struct {
 
 type UserDataset struct {
 	gorm.Model
-	UserID    uint `gorm:"primaryKey"`
-	DatasetID uint `gorm:"primaryKey"`
+	UserID    uint       `gorm:"primaryKey"`
+	DatasetID uint       `gorm:"primaryKey"`
+	ExpiresAt *time.Time `gorm:"index;comment:授权到期时间，为空表示永久"`
 }
 
 type AccountDataset struct {
 	gorm.Model
-	AccountID uint `gorm:"primaryKey"`
-	DatasetID uint `gorm:"primaryKey"`
+	AccountID uint       `gorm:"primaryKey"`
+	DatasetID uint       `gorm:"primaryKey"`
+	ExpiresAt *time.Time `gorm:"index;comment:授权到期时间，为空表示永久"`
 }
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// DatasetIndexStatus tells whether the manifest of a dataset reflects its directory.
+type DatasetIndexStatus string
+
+const (
+	// DatasetIndexPending manifests are waiting for the storage server to (re)index the dataset.
+	DatasetIndexPending DatasetIndexStatus = "Pending"
+	DatasetIndexed      DatasetIndexStatus = "Indexed"
+	DatasetIndexFailed  DatasetIndexStatus = "Failed"
+)
+
+// DatasetIntegrity is the result of the last verification of a dataset against its manifest.
+type DatasetIntegrity string
+
+const (
+	DatasetIntegrityUnverified DatasetIntegrity = "Unverified"
+	DatasetIntegrityOK         DatasetIntegrity = "OK"
+	// DatasetIntegrityModified datasets changed through regular writes since they were indexed.
+	DatasetIntegrityModified DatasetIntegrity = "Modified"
+	// DatasetIntegrityCorrupted datasets have files whose content changed while their size and
+	// modification time did not, which normal writes never do.
+	DatasetIntegrityCorrupted DatasetIntegrity = "Corrupted"
+	// DatasetIntegrityIncomplete datasets miss files or bytes, or still hold partial downloads.
+	DatasetIntegrityIncomplete DatasetIntegrity = "Incomplete"
+)
+
+// DatasetManifestProblem is one finding of a verification.
+type DatasetManifestProblem struct {
+	Path    string `json:"path"`
+	Problem string `json:"problem"`
+}
+
+// DatasetManifest is the content index of a dataset or model directory. The storage server
+// keeps it current in the background and re-verifies the files on request.
+type DatasetManifest struct {
+	gorm.Model
+	DatasetID uint    `gorm:"not null;uniqueIndex;comment:数据集ID"`
+	Dataset   Dataset `gorm:"foreignKey:DatasetID"`
+	// Path 为索引时数据集的真实路径，数据集路径变化后需要重新索引
+	Path      string                                     `gorm:"type:varchar(512);not null;default:'';comment:索引时的数据集路径"`
+	Files     datatypes.JSONType[[]DatasetManifestEntry] `gorm:"comment:内容清单(路径、大小、sha256、修改时间)"`
+	FileCount int64                                      `gorm:"type:bigint;not null;default:0;comment:文件数量"`
+	SizeBytes int64                                      `gorm:"type:bigint;not null;default:0;comment:文件总大小(字节)"`
+	Digest    string                                     `gorm:"type:varchar(64);not null;default:'';comment:清单整体sha256"`
+
+	Status    DatasetIndexStatus `gorm:"type:varchar(32);not null;default:Pending;index;comment:索引状态"`
+	Message   string             `gorm:"type:text;comment:状态消息(错误信息等)"`
+	IndexedAt *time.Time         `gorm:"comment:最近索引时间"`
+
+	// ExpectedSizeBytes 来自模型下载任务，实际大小不足时判定为下载不完整
+	ExpectedSizeBytes int64                                        `gorm:"type:bigint;not null;default:0;comment:期望的文件总大小(字节)"`
+	VerifyRequested   bool                                         `gorm:"not null;default:false;index;comment:是否等待校验"`
+	Integrity         DatasetIntegrity                             `gorm:"type:varchar(32);not null;default:Unverified;comment:最近校验结果"`
+	Problems          datatypes.JSONType[[]DatasetManifestProblem] `gorm:"comment:最近校验发现的问题"`
+	VerifiedAt        *time.Time                                   `gorm:"comment:最近校验时间"`
+}
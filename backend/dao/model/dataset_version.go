@@ -0,0 +1,59 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// DatasetVersionStatus is the lifecycle of a published dataset version.
+type DatasetVersionStatus string
+
+const (
+	// DatasetVersionCreating versions are being copied into their snapshot by the storage server.
+	DatasetVersionCreating DatasetVersionStatus = "Creating"
+	DatasetVersionReady    DatasetVersionStatus = "Ready"
+	DatasetVersionFailed   DatasetVersionStatus = "Failed"
+)
+
+// DatasetManifestEntry describes one regular file of a dataset or dataset version.
+type DatasetManifestEntry struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+	// ModTime is only recorded by the dataset indexer, which uses it to skip unchanged files.
+	ModTime *time.Time `json:"mtime,omitempty"`
+}
+
+// DatasetVersion is an immutable snapshot of a dataset. Publishing freezes the content of
+// Dataset.URL into a read-only copy (or hardlinks) below the storage root, so jobs that mount
+// dataset@version keep seeing the same files after the dataset itself changes.
+type DatasetVersion struct {
+	gorm.Model
+	DatasetID uint    `gorm:"not null;uniqueIndex:idx_dataset_versions_version,priority:1;comment:数据集ID"`
+	Dataset   Dataset `gorm:"foreignKey:DatasetID"`
+	Version   string  `gorm:"type:varchar(64);not null;uniqueIndex:idx_dataset_versions_version,priority:2;comment:版本号"`
+	Describe  string  `gorm:"type:text;comment:版本描述"`
+	UserID    uint    `gorm:"not null;default:0;comment:发布者用户ID"`
+
+	// SourcePath / Path 均为相对存储根目录的真实路径
+	SourcePath string                                     `gorm:"type:varchar(512);not null;comment:发布时的数据集路径"`
+	Path       string                                     `gorm:"type:varchar(512);not null;comment:快照路径"`
+	Hardlink   bool                                       `gorm:"not null;default:false;comment:快照是否使用硬链接"`
+	FileCount  int64                                      `gorm:"type:bigint;not null;default:0;comment:文件数量"`
+	SizeBytes  int64                                      `gorm:"type:bigint;not null;default:0;comment:文件总大小(字节)"`
+	Manifest   datatypes.JSONType[[]DatasetManifestEntry] `gorm:"comment:内容清单(路径、大小、sha256)"`
+
+	Status  DatasetVersionStatus `gorm:"type:varchar(32);not null;default:Creating;index;comment:版本状态"`
+	Message string               `gorm:"type:text;comment:状态消息(错误信息等)"`
+	ReadyAt *time.Time           `gorm:"comment:快照完成时间"`
+}
+
+// JobDatasetVersion records which dataset version a job mounted, so the job can be reproduced
+// with the same content later.
+type JobDatasetVersion struct {
+	DatasetID uint   `json:"datasetID"`
+	Version   string `json:"version"`
+	MountPath string `json:"mountPath"`
+}
@@ -1,6 +1,8 @@
 package model
 
 import (
+	"time"
+
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
@@ -24,6 +26,7 @@ const (
 	Snapshot     BuildSource = "Snapshot"
 	EnvdAdvanced BuildSource = "EnvdAdvanced"
 	EnvdRaw      BuildSource = "EnvdRaw"
+	GitRepo      BuildSource = "GitRepo"
 )
 
 type ImageShareType string
@@ -69,10 +72,11 @@ type Image struct {
 
 type ImageUser struct {
 	gorm.Model
-	ImageID uint
-	Image   Image
-	UserID  uint
-	User    User
+	ImageID   uint
+	Image     Image
+	UserID    uint
+	User      User
+	ExpiresAt *time.Time `gorm:"index;comment:分享到期时间，为空表示永久"`
 }
 
 type ImageAccount struct {
@@ -81,6 +85,7 @@ type ImageAccount struct {
 	Image     Image
 	AccountID uint
 	Account   Account
+	ExpiresAt *time.Time `gorm:"index;comment:分享到期时间，为空表示永久"`
 }
 
 type CudaBaseImage struct {
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ImageGCMark records that the image garbage collector has told the owner an image will be
+// deleted. The image is only deleted by a later run, after DeleteAfter, if it still matches the
+// policy; marks of images that no longer match are removed.
+type ImageGCMark struct {
+	gorm.Model
+	ImageID     uint      `gorm:"not null;uniqueIndex;comment:镜像ID"`
+	UserID      uint      `gorm:"not null;index;comment:镜像所有者ID"`
+	ImageLink   string    `gorm:"type:varchar(512);not null;default:'';comment:镜像链接"`
+	Reason      string    `gorm:"type:varchar(64);not null;default:'';comment:清理原因"`
+	NotifiedAt  time.Time `gorm:"comment:通知所有者的时间"`
+	DeleteAfter time.Time `gorm:"index;comment:最早删除时间"`
+}
@@ -0,0 +1,107 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// ImageScanStatus tells where the scan of an image stands.
+type ImageScanStatus string
+
+const (
+	// ImageScanPending scans wait for the scanner to pick them up on its next poll.
+	ImageScanPending   ImageScanStatus = "Pending"
+	ImageScanRunning   ImageScanStatus = "Running"
+	ImageScanSucceeded ImageScanStatus = "Succeeded"
+	ImageScanFailed    ImageScanStatus = "Failed"
+)
+
+// ScanSeverity is the severity of a vulnerability, ordered from Unknown to Critical.
+type ScanSeverity string
+
+const (
+	ScanSeverityUnknown  ScanSeverity = "UNKNOWN"
+	ScanSeverityLow      ScanSeverity = "LOW"
+	ScanSeverityMedium   ScanSeverity = "MEDIUM"
+	ScanSeverityHigh     ScanSeverity = "HIGH"
+	ScanSeverityCritical ScanSeverity = "CRITICAL"
+)
+
+var scanSeverityRanks = map[ScanSeverity]int{
+	ScanSeverityUnknown:  0,
+	ScanSeverityLow:      1,
+	ScanSeverityMedium:   2,
+	ScanSeverityHigh:     3,
+	ScanSeverityCritical: 4,
+}
+
+// ParseScanSeverity normalizes a severity name; ok is false for unknown names.
+func ParseScanSeverity(value string) (severity ScanSeverity, ok bool) {
+	severity = ScanSeverity(strings.ToUpper(strings.TrimSpace(value)))
+	_, ok = scanSeverityRanks[severity]
+	return severity, ok
+}
+
+// Rank orders severities; unrecognized values rank as Unknown.
+func (s ScanSeverity) Rank() int {
+	return scanSeverityRanks[s]
+}
+
+// Package types found in image layers.
+const (
+	ScanPackageDeb  = "deb"
+	ScanPackageApk  = "apk"
+	ScanPackagePyPI = "pypi"
+)
+
+// ImageScanPackage is one installed package of an image.
+type ImageScanPackage struct {
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// Source is the source package of deb and apk packages, used for vulnerability matching.
+	Source string `json:"source,omitempty"`
+}
+
+// ImageScanFinding is a package matched by an entry of the vulnerability database.
+type ImageScanFinding struct {
+	ID           string       `json:"id"`
+	Severity     ScanSeverity `json:"severity"`
+	Type         string       `json:"type"`
+	Package      string       `json:"package"`
+	Version      string       `json:"version"`
+	FixedVersion string       `json:"fixedVersion,omitempty"`
+	Title        string       `json:"title,omitempty"`
+}
+
+// ImageScan is the content and vulnerability report of an image. Builds and uploads request a
+// scan; the scanner fills in the package inventory and the findings.
+type ImageScan struct {
+	gorm.Model
+	ImageID   uint   `gorm:"not null;uniqueIndex;comment:镜像ID"`
+	ImageLink string `gorm:"type:varchar(512);not null;default:'';comment:扫描时的镜像链接"`
+	Digest    string `gorm:"type:varchar(128);not null;default:'';comment:扫描的镜像清单摘要"`
+
+	Status      ImageScanStatus `gorm:"type:varchar(32);not null;default:Pending;index;comment:扫描状态"`
+	Message     string          `gorm:"type:text;comment:状态消息(错误信息等)"`
+	RequestedAt time.Time       `gorm:"comment:最近请求扫描时间"`
+	ScannedAt   *time.Time      `gorm:"comment:最近扫描完成时间"`
+
+	OS       string                                 `gorm:"type:varchar(128);not null;default:'';comment:镜像操作系统"`
+	Arch     string                                 `gorm:"type:varchar(32);not null;default:'';comment:扫描的镜像架构"`
+	Packages datatypes.JSONType[[]ImageScanPackage] `gorm:"comment:软件包清单"`
+	Findings datatypes.JSONType[[]ImageScanFinding] `gorm:"comment:漏洞匹配结果"`
+
+	PackageCount int `gorm:"not null;default:0;comment:软件包数量"`
+	Critical     int `gorm:"not null;default:0;comment:严重漏洞数"`
+	High         int `gorm:"not null;default:0;comment:高危漏洞数"`
+	Medium       int `gorm:"not null;default:0;comment:中危漏洞数"`
+	Low          int `gorm:"not null;default:0;comment:低危漏洞数"`
+	Unknown      int `gorm:"not null;default:0;comment:未知级别漏洞数"`
+	// MaxSeverity 为空表示未发现漏洞或未配置漏洞库
+	MaxSeverity     ScanSeverity `gorm:"type:varchar(16);not null;default:'';comment:最高漏洞级别"`
+	DatabaseVersion string       `gorm:"type:varchar(64);not null;default:'';comment:漏洞库版本，为空表示未匹配漏洞"`
+}
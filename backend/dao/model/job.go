@@ -154,6 +154,8 @@ type Job struct {
 	Resources          datatypes.JSONType[v1.ResourceList] `gorm:"comment:作业的资源需求"`
 	Attributes         datatypes.JSONType[*batch.Job]      `gorm:"comment:作业的原始属性"`
 	Template           string                              `gorm:"type:text;comment:作业的模板配置"`
+	// DatasetVersions 作业挂载的数据集版本，用于复现作业
+	DatasetVersions *datatypes.JSONType[[]JobDatasetVersion] `gorm:"comment:作业挂载的数据集版本"`
 
 	// 通知相关
 	AlertEnabled bool `gorm:"type:boolean;default:false;comment:是否启用通知"`
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type JobArrayStatus string
+
+const (
+	JobArrayStatusRunning   JobArrayStatus = "Running"
+	JobArrayStatusSucceeded JobArrayStatus = "Succeeded"
+	JobArrayStatusFailed    JobArrayStatus = "Failed"
+	JobArrayStatusCancelled JobArrayStatus = "Cancelled"
+)
+
+// JobArray is the parent record of a parameter sweep. Every element is an ordinary
+// vcjob in the jobs table; elements above Parallelism wait in the Prequeue phase
+// until JobArrayTask.Held is cleared by a finished sibling.
+type JobArray struct {
+	gorm.Model
+	Name        string         `gorm:"type:varchar(256);not null;comment:作业数组名称"`
+	UserID      uint           `gorm:"not null;index;comment:创建者ID"`
+	User        User           `gorm:"foreignKey:UserID"`
+	AccountID   uint           `gorm:"not null;index;comment:所属账户ID"`
+	Account     Account        `gorm:"foreignKey:AccountID"`
+	JobType     JobType        `gorm:"not null;comment:元素作业类型"`
+	Size        int            `gorm:"not null;comment:元素数量"`
+	Parallelism int            `gorm:"not null;comment:同时运行的元素上限"`
+	Status      JobArrayStatus `gorm:"type:varchar(32);not null;index;comment:作业数组状态"`
+}
+
+type JobArrayTask struct {
+	gorm.Model
+	ArrayID uint                                  `gorm:"not null;uniqueIndex:idx_job_array_task_index,priority:1;comment:作业数组ID"`
+	Index   int                                   `gorm:"not null;uniqueIndex:idx_job_array_task_index,priority:2;comment:元素下标"`
+	JobName string                                `gorm:"type:varchar(256);not null;index;comment:元素对应的作业名称"`
+	Params  datatypes.JSONType[map[string]string] `gorm:"comment:元素的参数取值"`
+	Held    bool                                  `gorm:"not null;default:false;index;comment:是否因并发上限而等待"`
+}
@@ -11,8 +11,18 @@ type ModelSource string
 const (
 	ModelSourceModelScope  ModelSource = "modelscope"
 	ModelSourceHuggingFace ModelSource = "huggingface"
+	// ModelSourceS3 pulls every object under an S3-compatible bucket prefix.
+	ModelSourceS3 ModelSource = "s3"
+	// ModelSourceHTTP fetches a single file, optionally an archive, over HTTP(S).
+	ModelSourceHTTP ModelSource = "http"
 )
 
+// IsRemoteURL reports whether the source is addressed by RemoteURL instead of a
+// hub repository name.
+func (s ModelSource) IsRemoteURL() bool {
+	return s == ModelSourceS3 || s == ModelSourceHTTP
+}
+
 type DownloadCategory string
 
 const (
@@ -68,6 +78,13 @@ type ModelDownload struct {
 	ModelDatasetSourceID *uint               `gorm:"index;comment:模型或数据集外部来源ID"`
 	ModelDatasetSource   *ModelDatasetSource `gorm:"foreignKey:ModelDatasetSourceID"`
 	JobName              string              `gorm:"type:varchar(256);comment:K8s Job名称"`
+	RemoteURL            string              `gorm:"type:varchar(2048);comment:S3或HTTP下载源地址"`
+	S3Endpoint           string              `gorm:"type:varchar(512);comment:S3兼容服务地址"`
+	S3Region             string              `gorm:"type:varchar(64);comment:S3区域"`
+	S3AccessKey          string              `json:"-" gorm:"type:text;comment:加密后的S3访问密钥ID"`
+	S3SecretKey          string              `json:"-" gorm:"type:text;comment:加密后的S3访问密钥"`
+	Extract              bool                `gorm:"default:false;comment:下载完成后是否解压归档"`
+	Checksum             string              `gorm:"type:varchar(160);comment:期望的文件校验和(sha256:<hex>)"`
 	CreatorID            uint                `gorm:"not null;comment:首个发起下载的用户ID"`
 	Creator              User                `gorm:"foreignKey:CreatorID"`
 	ReferenceCount       int                 `gorm:"default:0;comment:提交下载需求的用户计数"`
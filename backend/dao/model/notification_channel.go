@@ -0,0 +1,120 @@
+package model
+
+import (
+	"fmt"
+	"net/url"
+)
+
+type NotificationChannelType string
+
+const (
+	NotificationChannelEmail    NotificationChannelType = "email"
+	NotificationChannelWebhook  NotificationChannelType = "webhook"  // 通用 JSON webhook
+	NotificationChannelSlack    NotificationChannelType = "slack"    // Slack 兼容的 incoming webhook
+	NotificationChannelWeCom    NotificationChannelType = "wecom"    // 企业微信群机器人
+	NotificationChannelFeishu   NotificationChannelType = "feishu"   // 飞书群机器人
+	NotificationChannelDingTalk NotificationChannelType = "dingtalk" // 钉钉群机器人
+)
+
+const MaxNotificationChannels = 5
+
+// NotificationSecretMask 在响应中替代已保存的签名密钥，更新时原样提交表示沿用已保存的密钥
+const NotificationSecretMask = "******"
+
+// NotificationChannel 单个通知渠道，邮件渠道使用用户属性中的邮箱地址
+type NotificationChannel struct {
+	Type NotificationChannelType `json:"type"`
+	// URL webhook 地址，邮件渠道不需要
+	URL string `json:"url,omitempty"`
+	// Secret 飞书、钉钉机器人的签名密钥，可选；保存时加密，响应中以 NotificationSecretMask 代替
+	Secret string `json:"secret,omitempty"`
+}
+
+// NotificationPreference 用户的通知渠道偏好
+//
+// Channels 按顺序尝试，第一个发送成功的渠道即停止；
+// 若所有渠道都失败且列表中没有邮件渠道，则最后回退到邮件。
+type NotificationPreference struct {
+	Channels []NotificationChannel `json:"channels,omitempty"`
+}
+
+// Validate 校验渠道类型与 webhook 地址，在用户更新属性时调用
+func (p *NotificationPreference) Validate() error {
+	if p == nil {
+		return nil
+	}
+	if len(p.Channels) > MaxNotificationChannels {
+		return fmt.Errorf("at most %d notification channels are allowed", MaxNotificationChannels)
+	}
+	for i := range p.Channels {
+		channel := &p.Channels[i]
+		switch channel.Type {
+		case NotificationChannelEmail:
+			continue
+		case NotificationChannelWebhook, NotificationChannelSlack, NotificationChannelWeCom,
+			NotificationChannelFeishu, NotificationChannelDingTalk:
+		default:
+			return fmt.Errorf("unsupported notification channel type %q", channel.Type)
+		}
+		u, err := url.Parse(channel.URL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("invalid webhook url for %s channel", channel.Type)
+		}
+	}
+	return nil
+}
+
+// StoredSecret 返回同类型、同地址渠道已保存的（加密）密钥
+func (p *NotificationPreference) StoredSecret(channelType NotificationChannelType, rawURL string) (string, bool) {
+	if p == nil {
+		return "", false
+	}
+	for _, channel := range p.Channels {
+		if channel.Type == channelType && channel.URL == rawURL && channel.Secret != "" {
+			return channel.Secret, true
+		}
+	}
+	return "", false
+}
+
+// Redacted 返回用于响应的副本，签名密钥以 NotificationSecretMask 代替
+func (p *NotificationPreference) Redacted() *NotificationPreference {
+	if p == nil {
+		return nil
+	}
+	redacted := &NotificationPreference{Channels: make([]NotificationChannel, len(p.Channels))}
+	copy(redacted.Channels, p.Channels)
+	for i := range redacted.Channels {
+		if redacted.Channels[i].Secret != "" {
+			redacted.Channels[i].Secret = NotificationSecretMask
+		}
+	}
+	return redacted
+}
+
+// Redacted 返回用于响应的用户属性副本，不包含通知渠道的签名密钥
+func (a UserAttribute) Redacted() UserAttribute {
+	a.Notification = a.Notification.Redacted()
+	return a
+}
+
+// NotificationChain 返回实际发送时使用的渠道顺序，邮件作为兜底渠道
+func (a *UserAttribute) NotificationChain() []NotificationChannel {
+	var chain []NotificationChannel
+	hasEmail := false
+	if a.Notification != nil {
+		for _, channel := range a.Notification.Channels {
+			if channel.Type == NotificationChannelEmail {
+				if hasEmail {
+					continue
+				}
+				hasEmail = true
+			}
+			chain = append(chain, channel)
+		}
+	}
+	if !hasEmail {
+		chain = append(chain, NotificationChannel{Type: NotificationChannelEmail})
+	}
+	return chain
+}
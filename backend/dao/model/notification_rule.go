@@ -0,0 +1,151 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type NotificationEvent string
+
+const (
+	NotificationEventJobQueuedTooLong  NotificationEvent = "job_queued_too_long"
+	NotificationEventJobStarted        NotificationEvent = "job_started"
+	NotificationEventJobFailed         NotificationEvent = "job_failed"
+	NotificationEventJobCompleted      NotificationEvent = "job_completed"
+	NotificationEventBackfillPreempted NotificationEvent = "backfill_preempted"
+	NotificationEventBalanceLow        NotificationEvent = "billing_balance_low"
+	NotificationEventApprovalReviewed  NotificationEvent = "approval_order_reviewed"
+	NotificationEventApprovalRequested NotificationEvent = "approval_order_requested"
+	NotificationEventBudgetThreshold   NotificationEvent = "billing_budget_threshold"
+	NotificationEventBudgetCapReached  NotificationEvent = "billing_budget_cap_reached"
+	NotificationEventImageCleanup      NotificationEvent = "image_cleanup_scheduled"
+)
+
+func AllNotificationEvents() []NotificationEvent {
+	return []NotificationEvent{
+		NotificationEventJobQueuedTooLong,
+		NotificationEventJobStarted,
+		NotificationEventJobFailed,
+		NotificationEventJobCompleted,
+		NotificationEventBackfillPreempted,
+		NotificationEventBalanceLow,
+		NotificationEventApprovalReviewed,
+		NotificationEventApprovalRequested,
+		NotificationEventBudgetThreshold,
+		NotificationEventBudgetCapReached,
+		NotificationEventImageCleanup,
+	}
+}
+
+const (
+	DefaultQueuedTooLongMinutes = 30
+	notificationClockLayout     = "15:04"
+)
+
+// NotificationRule is a user's subscription. AccountID 0 is the user-wide rule,
+// a rule with a concrete AccountID overrides it for jobs and balances of that account.
+// Users without any rule keep the legacy behavior driven by Job.AlertEnabled.
+type NotificationRule struct {
+	gorm.Model
+	UserID    uint                                    `gorm:"not null;uniqueIndex:idx_notification_rule_scope,priority:1;comment:用户ID"`
+	AccountID uint                                    `gorm:"not null;default:0;uniqueIndex:idx_notification_rule_scope,priority:2;comment:账户ID，0表示对所有账户生效"`
+	Events    datatypes.JSONType[[]NotificationEvent] `gorm:"comment:订阅的事件"`
+	// QueuedTooLongMinutes 作业排队超过该时长时触发 job_queued_too_long
+	QueuedTooLongMinutes int `gorm:"not null;default:30;comment:排队过久的阈值(分钟)"`
+	// BalanceLowThreshold 用户在账户中的可用点数低于该值时触发 billing_balance_low (内部微点)
+	BalanceLowThreshold int64 `gorm:"type:bigint;not null;default:0;comment:余额不足阈值(内部微点)"`
+	// QuietStart/QuietEnd 免打扰时段 (HH:MM，本地时间)，可以跨越午夜，均为空表示不启用
+	QuietStart string `gorm:"type:varchar(5);comment:免打扰开始时间"`
+	QuietEnd   string `gorm:"type:varchar(5);comment:免打扰结束时间"`
+	// DigestMinutes 大于 0 时，通知合并后每隔该时长发送一次
+	DigestMinutes int `gorm:"not null;default:0;comment:摘要合并间隔(分钟)，0表示立即发送"`
+}
+
+// NotificationDigestItem is a notification waiting for its digest window or for quiet hours to end.
+type NotificationDigestItem struct {
+	gorm.Model
+	UserID       uint              `gorm:"not null;index;comment:用户ID"`
+	Event        NotificationEvent `gorm:"type:varchar(64);not null;comment:事件类型"`
+	Title        string            `gorm:"type:varchar(256);not null;comment:标题"`
+	Message      string            `gorm:"type:text;comment:内容"`
+	URL          string            `gorm:"type:varchar(512);comment:详情链接"`
+	DeliverAfter time.Time         `gorm:"not null;index;comment:最早发送时间"`
+	SentAt       *time.Time        `gorm:"index;comment:发送时间"`
+}
+
+func (r *NotificationRule) Subscribes(event NotificationEvent) bool {
+	return slices.Contains(r.Events.Data(), event)
+}
+
+func (r *NotificationRule) Validate() error {
+	for _, event := range r.Events.Data() {
+		if !slices.Contains(AllNotificationEvents(), event) {
+			return fmt.Errorf("unsupported notification event %q", event)
+		}
+	}
+	if (r.QuietStart == "") != (r.QuietEnd == "") {
+		return fmt.Errorf("quiet hours need both start and end")
+	}
+	for _, clock := range []string{r.QuietStart, r.QuietEnd} {
+		if clock == "" {
+			continue
+		}
+		if _, err := time.Parse(notificationClockLayout, clock); err != nil {
+			return fmt.Errorf("invalid quiet hours clock %q, expected HH:MM", clock)
+		}
+	}
+	if r.QueuedTooLongMinutes <= 0 {
+		return fmt.Errorf("queued too long minutes must be positive")
+	}
+	if r.DigestMinutes < 0 || r.BalanceLowThreshold < 0 {
+		return fmt.Errorf("digest minutes and balance threshold must not be negative")
+	}
+	return nil
+}
+
+// QuietUntil returns the end of the quiet period containing t, or the zero time if t is outside quiet hours.
+func (r *NotificationRule) QuietUntil(t time.Time) time.Time {
+	if r.QuietStart == "" || r.QuietStart == r.QuietEnd {
+		return time.Time{}
+	}
+	start, errStart := time.Parse(notificationClockLayout, r.QuietStart)
+	end, errEnd := time.Parse(notificationClockLayout, r.QuietEnd)
+	if errStart != nil || errEnd != nil {
+		return time.Time{}
+	}
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	startAt := day.Add(time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute)
+	endAt := day.Add(time.Duration(end.Hour())*time.Hour + time.Duration(end.Minute())*time.Minute)
+
+	if startAt.Before(endAt) {
+		if !t.Before(startAt) && t.Before(endAt) {
+			return endAt
+		}
+		return time.Time{}
+	}
+	// 跨越午夜，例如 22:00-08:00
+	if !t.Before(startAt) {
+		return endAt.AddDate(0, 0, 1)
+	}
+	if t.Before(endAt) {
+		return endAt
+	}
+	return time.Time{}
+}
+
+// DeliverAt decides when a notification raised at now should be sent.
+// It returns now for immediate delivery.
+func (r *NotificationRule) DeliverAt(now time.Time) time.Time {
+	at := now
+	if r.DigestMinutes > 0 {
+		at = now.Add(time.Duration(r.DigestMinutes) * time.Minute)
+	}
+	if until := r.QuietUntil(at); !until.IsZero() {
+		at = until
+	}
+	return at
+}
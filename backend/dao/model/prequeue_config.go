@@ -15,12 +15,24 @@ const (
 	PrequeueActivateTickerIntervalSecondsKey              = "activate_ticker_interval_seconds"
 	PrequeueMaxTotalActivationsPerRoundKey                = "max_total_activations_per_round"
 	PrequeueCandidateSizeKey                              = "prequeue_candidate_size"
+	PrequeueActivationPolicyKey                           = "activation_policy"
+	PrequeueFairShareHalfLifeHoursKey                     = "fair_share_half_life_hours"
 	PrequeueDefaultBackfillEnabled                        = false
 	PrequeueDefaultQueueQuotaEnabled                      = false
 	PrequeueDefaultNormalJobWaitingToleranceSeconds int64 = 300
 	PrequeueDefaultActivateTickerIntervalSeconds    int64 = 5
 	PrequeueDefaultMaxTotalActivationsPerRound      int64 = 500
 	DefaultPrequeueCandidateSize                          = 10
+	PrequeueDefaultFairShareHalfLifeHours           int64 = 24
+)
+
+// PrequeueActivationPolicy decides the order in which prequeued jobs are activated.
+const (
+	// PrequeueActivationPolicyFIFO activates jobs by creation time.
+	PrequeueActivationPolicyFIFO = "fifo"
+	// PrequeueActivationPolicyFairShare activates jobs by decayed GPU usage against configured shares.
+	PrequeueActivationPolicyFairShare = "fair_share"
+	PrequeueDefaultActivationPolicy   = PrequeueActivationPolicyFIFO
 )
 
 type PrequeueConfig struct {
@@ -42,16 +54,20 @@ func PrequeueAllConfigs() []*PrequeueConfig {
 		{Key: PrequeueActivateTickerIntervalSecondsKey, Value: strconv.FormatInt(PrequeueDefaultActivateTickerIntervalSeconds, 10)},
 		{Key: PrequeueMaxTotalActivationsPerRoundKey, Value: strconv.FormatInt(PrequeueDefaultMaxTotalActivationsPerRound, 10)},
 		{Key: PrequeueCandidateSizeKey, Value: strconv.Itoa(DefaultPrequeueCandidateSize)},
+		{Key: PrequeueActivationPolicyKey, Value: PrequeueDefaultActivationPolicy},
+		{Key: PrequeueFairShareHalfLifeHoursKey, Value: strconv.FormatInt(PrequeueDefaultFairShareHalfLifeHours, 10)},
 	}
 }
 
 type PrequeueRuntimeConfig struct {
-	BackfillEnabled                  bool  `json:"backfill_enabled"`
-	QueueQuotaEnabled                bool  `json:"queue_quota_enabled"`
-	NormalJobWaitingToleranceSeconds int64 `json:"normal_job_waiting_tolerance_seconds"`
-	ActivateTickerIntervalSeconds    int64 `json:"activate_ticker_interval_seconds"`
-	MaxTotalActivationsPerRound      int64 `json:"max_total_activations_per_round"`
-	PrequeueCandidateSize            int64 `json:"prequeue_candidate_size"`
+	BackfillEnabled                  bool   `json:"backfill_enabled"`
+	QueueQuotaEnabled                bool   `json:"queue_quota_enabled"`
+	NormalJobWaitingToleranceSeconds int64  `json:"normal_job_waiting_tolerance_seconds"`
+	ActivateTickerIntervalSeconds    int64  `json:"activate_ticker_interval_seconds"`
+	MaxTotalActivationsPerRound      int64  `json:"max_total_activations_per_round"`
+	PrequeueCandidateSize            int64  `json:"prequeue_candidate_size"`
+	ActivationPolicy                 string `json:"activation_policy"`
+	FairShareHalfLifeHours           int64  `json:"fair_share_half_life_hours"`
 }
 
 func NewPrequeueRuntimeConfig() *PrequeueRuntimeConfig {
@@ -62,6 +78,8 @@ func NewPrequeueRuntimeConfig() *PrequeueRuntimeConfig {
 		ActivateTickerIntervalSeconds:    PrequeueDefaultActivateTickerIntervalSeconds,
 		MaxTotalActivationsPerRound:      PrequeueDefaultMaxTotalActivationsPerRound,
 		PrequeueCandidateSize:            int64(DefaultPrequeueCandidateSize),
+		ActivationPolicy:                 PrequeueDefaultActivationPolicy,
+		FairShareHalfLifeHours:           PrequeueDefaultFairShareHalfLifeHours,
 	}
 }
 
@@ -74,13 +92,28 @@ func (cfg *PrequeueRuntimeConfig) Validate() error {
 		PrequeueActivateTickerIntervalSecondsKey:    cfg.ActivateTickerIntervalSeconds,
 		PrequeueMaxTotalActivationsPerRoundKey:      cfg.MaxTotalActivationsPerRound,
 		PrequeueCandidateSizeKey:                    cfg.PrequeueCandidateSize,
+		PrequeueFairShareHalfLifeHoursKey:           cfg.FairShareHalfLifeHours,
 	}
 	for key, value := range positiveValues {
 		if value <= 0 {
 			return fmt.Errorf("%s must be greater than 0", key)
 		}
 	}
-	return nil
+	return ValidatePrequeueActivationPolicy(cfg.ActivationPolicy)
+}
+
+func ValidatePrequeueActivationPolicy(policy string) error {
+	switch policy {
+	case PrequeueActivationPolicyFIFO, PrequeueActivationPolicyFairShare:
+		return nil
+	default:
+		return fmt.Errorf("%s must be one of %s, %s",
+			PrequeueActivationPolicyKey, PrequeueActivationPolicyFIFO, PrequeueActivationPolicyFairShare)
+	}
+}
+
+func (cfg *PrequeueRuntimeConfig) FairShareEnabled() bool {
+	return cfg != nil && cfg.ActivationPolicy == PrequeueActivationPolicyFairShare
 }
 
 func (cfg *PrequeueRuntimeConfig) ShouldBlockByTimedOutPendingNormalJob() bool {
@@ -95,5 +128,7 @@ func (cfg *PrequeueRuntimeConfig) ToValueMap() map[string]string {
 		PrequeueActivateTickerIntervalSecondsKey:    strconv.FormatInt(cfg.ActivateTickerIntervalSeconds, 10),
 		PrequeueMaxTotalActivationsPerRoundKey:      strconv.FormatInt(cfg.MaxTotalActivationsPerRound, 10),
 		PrequeueCandidateSizeKey:                    strconv.FormatInt(cfg.PrequeueCandidateSize, 10),
+		PrequeueActivationPolicyKey:                 cfg.ActivationPolicy,
+		PrequeueFairShareHalfLifeHoursKey:           strconv.FormatInt(cfg.FairShareHalfLifeHours, 10),
 	}
 }
@@ -0,0 +1,14 @@
+package model
+
+import "gorm.io/gorm"
+
+// S3AccessKey is the access key a user signs S3 gateway requests with in one account.
+// SigV4 needs the secret to recompute signatures, so it is stored encrypted rather than
+// hashed. Rotating the key soft-deletes the row and creates a new one.
+type S3AccessKey struct {
+	gorm.Model
+	UserID      uint   `gorm:"not null;index:idx_s3_access_key_owner,priority:1;comment:用户ID"`
+	AccountID   uint   `gorm:"not null;index:idx_s3_access_key_owner,priority:2;comment:账户ID"`
+	AccessKeyID string `gorm:"type:varchar(32);not null;uniqueIndex;comment:访问密钥ID"`
+	Secret      string `gorm:"type:text;not null;comment:加密后的私有访问密钥"`
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// StorageSpaceKind tells which storage prefix a quota space lives under.
+type StorageSpaceKind string
+
+const (
+	StorageSpaceUser    StorageSpaceKind = "user"
+	StorageSpaceAccount StorageSpaceKind = "account"
+)
+
+// StorageQuota tracks the usage of one user or account space on the shared filesystem,
+// together with its optional byte and inode limits. Usage is adjusted incrementally by the
+// storage server on every write and periodically reconciled by a full scan.
+type StorageQuota struct {
+	gorm.Model
+	Kind    StorageSpaceKind `gorm:"type:varchar(16);not null;uniqueIndex:idx_storage_quota_space,priority:1;comment:空间类型 (user/account)"`
+	Space   string           `gorm:"type:varchar(256);not null;uniqueIndex:idx_storage_quota_space,priority:2;comment:空间目录名"`
+	OwnerID uint             `gorm:"not null;default:0;comment:用户或账户ID"`
+
+	// MaxBytes / MaxInodes 为空表示不限制
+	MaxBytes  *int64 `gorm:"type:bigint;comment:字节上限"`
+	MaxInodes *int64 `gorm:"type:bigint;comment:文件与目录数量上限"`
+
+	UsedBytes  int64      `gorm:"type:bigint;not null;default:0;comment:已用字节数"`
+	UsedInodes int64      `gorm:"type:bigint;not null;default:0;comment:已用文件与目录数量"`
+	ScannedAt  *time.Time `gorm:"comment:最近一次全量扫描时间"`
+}
+
+// Admits reports whether the space can take addBytes and addInodes more without
+// exceeding its limits. Negative deltas are always admitted.
+func (q *StorageQuota) Admits(addBytes, addInodes int64) bool {
+	if q.MaxBytes != nil && addBytes > 0 && q.UsedBytes+addBytes > *q.MaxBytes {
+		return false
+	}
+	if q.MaxInodes != nil && addInodes > 0 && q.UsedInodes+addInodes > *q.MaxInodes {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// StorageTrashStatus is the lifecycle of a trashed file or directory.
+type StorageTrashStatus string
+
+const (
+	StorageTrashTrashed StorageTrashStatus = "trashed"
+	// StorageTrashExpired items are past retention and wait for the storage server to remove them.
+	StorageTrashExpired  StorageTrashStatus = "expired"
+	StorageTrashRestored StorageTrashStatus = "restored"
+	StorageTrashPurged   StorageTrashStatus = "purged"
+)
+
+// StorageTrashItem records a file or directory deleted from a user or account space.
+// The data is moved out of the space into the trash root of the storage server. It keeps
+// counting toward the space quota and can be restored until it is purged.
+type StorageTrashItem struct {
+	gorm.Model
+	Kind  StorageSpaceKind `gorm:"type:varchar(16);not null;index:idx_storage_trash_space,priority:1;comment:空间类型 (user/account)"`
+	Space string           `gorm:"type:varchar(256);not null;index:idx_storage_trash_space,priority:2;comment:空间目录名"`
+
+	// OriginalPath / TrashPath 均为相对存储根目录的真实路径
+	OriginalPath string `gorm:"type:text;not null;comment:删除前的路径"`
+	TrashPath    string `gorm:"type:text;not null;comment:回收站中的路径"`
+	IsDir        bool   `gorm:"not null;default:false;comment:是否为目录"`
+	Bytes        int64  `gorm:"type:bigint;not null;default:0;comment:字节数"`
+	Inodes       int64  `gorm:"type:bigint;not null;default:0;comment:文件与目录数量"`
+	DeletedBy    uint   `gorm:"not null;default:0;comment:删除者用户ID"`
+
+	Status     StorageTrashStatus `gorm:"type:varchar(16);not null;index;comment:状态"`
+	TrashedAt  time.Time          `gorm:"not null;index;comment:放入回收站时间"`
+	RestoredAt *time.Time         `gorm:"comment:恢复时间"`
+	PurgedAt   *time.Time         `gorm:"comment:彻底删除时间"`
+}
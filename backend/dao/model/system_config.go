@@ -40,6 +40,10 @@ const (
 	ConfigKeyModelDownloadBandwidth = "POD_BANDWIDTH_MODEL_DOWNLOAD"
 	ConfigKeyJobIngressBandwidth    = "POD_BANDWIDTH_JOB_INGRESS"
 	ConfigKeyJobEgressBandwidth     = "POD_BANDWIDTH_JOB_EGRESS"
+
+	// Image scan policy: images with findings at or above this severity cannot be made public.
+	// An empty value disables the check.
+	ConfigKeyImageScanBlockSeverity = "IMAGE_SCAN_BLOCK_PUBLIC_SEVERITY"
 )
 
 // DefaultConfigKeys 定义了系统启动时必须存在的键
@@ -66,4 +70,5 @@ var DefaultConfigKeys = []string{
 	ConfigKeyModelDownloadBandwidth,
 	ConfigKeyJobIngressBandwidth,
 	ConfigKeyJobEgressBandwidth,
+	ConfigKeyImageScanBlockSeverity,
 }
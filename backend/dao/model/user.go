@@ -41,6 +41,9 @@ type UserAttribute struct {
 	// UID and GID are used for Filesystem
 	UID *string `json:"uid,omitempty"` // UID
 	GID *string `json:"gid,omitempty"` // GID
+
+	// Notification 通知渠道偏好，为空时仅使用邮件
+	Notification *NotificationPreference `json:"notification,omitempty"`
 }
 
 // User is the basic entity of the system
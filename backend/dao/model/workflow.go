@@ -0,0 +1,52 @@
+package model
+
+import (
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type WorkflowStatus string
+
+const (
+	WorkflowStatusRunning   WorkflowStatus = "Running"
+	WorkflowStatusSucceeded WorkflowStatus = "Succeeded"
+	WorkflowStatusFailed    WorkflowStatus = "Failed"
+	WorkflowStatusCancelled WorkflowStatus = "Cancelled"
+)
+
+// WorkflowDependencyCondition decides when a finished parent releases a child.
+type WorkflowDependencyCondition string
+
+const (
+	// WorkflowDependencyOnSuccess releases the child only if the parent completed successfully.
+	WorkflowDependencyOnSuccess WorkflowDependencyCondition = "success"
+	// WorkflowDependencyOnCompletion releases the child once the parent reached any terminal phase.
+	WorkflowDependencyOnCompletion WorkflowDependencyCondition = "completion"
+)
+
+type WorkflowDependency struct {
+	Node      string                      `json:"node"`
+	Condition WorkflowDependencyCondition `json:"condition"`
+}
+
+// Workflow groups vcjobs into a DAG. The jobs themselves stay in the jobs table,
+// downstream ones are kept in the Prequeue phase until WorkflowNode.Held is cleared.
+type Workflow struct {
+	gorm.Model
+	Name      string         `gorm:"type:varchar(256);not null;comment:工作流名称"`
+	UserID    uint           `gorm:"not null;index;comment:创建者ID"`
+	User      User           `gorm:"foreignKey:UserID"`
+	AccountID uint           `gorm:"not null;index;comment:所属账户ID"`
+	Account   Account        `gorm:"foreignKey:AccountID"`
+	Status    WorkflowStatus `gorm:"type:varchar(32);not null;index;comment:工作流状态"`
+}
+
+type WorkflowNode struct {
+	gorm.Model
+	WorkflowID uint                                     `gorm:"not null;uniqueIndex:idx_workflow_node_name,priority:1;comment:工作流ID"`
+	Name       string                                   `gorm:"type:varchar(128);not null;uniqueIndex:idx_workflow_node_name,priority:2;comment:节点名称"`
+	JobName    string                                   `gorm:"type:varchar(256);not null;index;comment:节点对应的作业名称"`
+	DependsOn  datatypes.JSONType[[]WorkflowDependency] `gorm:"comment:节点依赖的上游节点"`
+	Held       bool                                     `gorm:"not null;default:false;index;comment:是否仍在等待上游节点"`
+	Skipped    bool                                     `gorm:"not null;default:false;comment:上游条件无法满足而被跳过"`
+}
@@ -34,6 +34,7 @@ func newAccountDataset(db *gorm.DB, opts ...gen.DOOption) accountDataset {
 	_accountDataset.DeletedAt = field.NewField(tableName, "deleted_at")
 	_accountDataset.AccountID = field.NewUint(tableName, "account_id")
 	_accountDataset.DatasetID = field.NewUint(tableName, "dataset_id")
+	_accountDataset.ExpiresAt = field.NewTime(tableName, "expires_at")
 
 	_accountDataset.fillFieldMap()
 
@@ -50,6 +51,7 @@ type accountDataset struct {
 	DeletedAt field.Field
 	AccountID field.Uint
 	DatasetID field.Uint
+	ExpiresAt field.Time // 授权到期时间，为空表示永久
 
 	fieldMap map[string]field.Expr
 }
@@ -72,6 +74,7 @@ func (a *accountDataset) updateTableName(table string) *accountDataset {
 	a.DeletedAt = field.NewField(table, "deleted_at")
 	a.AccountID = field.NewUint(table, "account_id")
 	a.DatasetID = field.NewUint(table, "dataset_id")
+	a.ExpiresAt = field.NewTime(table, "expires_at")
 
 	a.fillFieldMap()
 
@@ -100,13 +103,14 @@ func (a *accountDataset) GetFieldByName(fieldName string) (field.OrderExpr, bool
 }
 
 func (a *accountDataset) fillFieldMap() {
-	a.fieldMap = make(map[string]field.Expr, 6)
+	a.fieldMap = make(map[string]field.Expr, 7)
 	a.fieldMap["id"] = a.ID
 	a.fieldMap["created_at"] = a.CreatedAt
 	a.fieldMap["updated_at"] = a.UpdatedAt
 	a.fieldMap["deleted_at"] = a.DeletedAt
 	a.fieldMap["account_id"] = a.AccountID
 	a.fieldMap["dataset_id"] = a.DatasetID
+	a.fieldMap["expires_at"] = a.ExpiresAt
 }
 
 func (a accountDataset) clone(db *gorm.DB) accountDataset {
@@ -41,6 +41,7 @@ func newAccount(db *gorm.DB, opts ...gen.DOOption) account {
 	_account.BillingIssueAmount = field.NewInt64(tableName, "billing_issue_amount")
 	_account.BillingIssuePeriodMinutes = field.NewInt(tableName, "billing_issue_period_minutes")
 	_account.BillingLastIssuedAt = field.NewTime(tableName, "billing_last_issued_at")
+	_account.FairShare = field.NewInt(tableName, "fair_share")
 	_account.UserAccounts = accountHasManyUserAccounts{
 		db: db.Session(&gorm.Session{}),
 
@@ -75,6 +76,7 @@ type account struct {
 	BillingIssueAmount        field.Int64  // 账户周期发放点数额度(内部微点, 为空表示未配置)
 	BillingIssuePeriodMinutes field.Int    // 账户周期发放间隔分钟(<=0表示关闭, 为空表示未配置)
 	BillingLastIssuedAt       field.Time   // 账户上次发放时间
+	FairShare                 field.Int    // 账户公平调度份额(为空表示默认份额1)
 	UserAccounts              accountHasManyUserAccounts
 
 	AccountDatasets accountHasManyAccountDatasets
@@ -107,6 +109,7 @@ func (a *account) updateTableName(table string) *account {
 	a.BillingIssueAmount = field.NewInt64(table, "billing_issue_amount")
 	a.BillingIssuePeriodMinutes = field.NewInt(table, "billing_issue_period_minutes")
 	a.BillingLastIssuedAt = field.NewTime(table, "billing_last_issued_at")
+	a.FairShare = field.NewInt(table, "fair_share")
 
 	a.fillFieldMap()
 
@@ -131,7 +134,7 @@ func (a *account) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
 }
 
 func (a *account) fillFieldMap() {
-	a.fieldMap = make(map[string]field.Expr, 15)
+	a.fieldMap = make(map[string]field.Expr, 16)
 	a.fieldMap["id"] = a.ID
 	a.fieldMap["created_at"] = a.CreatedAt
 	a.fieldMap["updated_at"] = a.UpdatedAt
@@ -145,6 +148,7 @@ func (a *account) fillFieldMap() {
 	a.fieldMap["billing_issue_amount"] = a.BillingIssueAmount
 	a.fieldMap["billing_issue_period_minutes"] = a.BillingIssuePeriodMinutes
 	a.fieldMap["billing_last_issued_at"] = a.BillingLastIssuedAt
+	a.fieldMap["fair_share"] = a.FairShare
 
 }
 
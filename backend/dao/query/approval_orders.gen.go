@@ -39,6 +39,7 @@ func newApprovalOrder(db *gorm.DB, opts ...gen.DOOption) approvalOrder {
 	_approvalOrder.ReviewNotes = field.NewString(tableName, "review_notes")
 	_approvalOrder.CreatorID = field.NewUint(tableName, "creator_id")
 	_approvalOrder.ReviewerID = field.NewUint(tableName, "reviewer_id")
+	_approvalOrder.AssigneeID = field.NewUint(tableName, "assignee_id")
 	_approvalOrder.Creator = approvalOrderBelongsToCreator{
 		db: db.Session(&gorm.Session{}),
 
@@ -81,6 +82,7 @@ type approvalOrder struct {
 	ReviewNotes field.String // 审批备注
 	CreatorID   field.Uint   // 创建者ID
 	ReviewerID  field.Uint   // 审批者ID
+	AssigneeID  field.Uint   // 指定审批人ID
 	Creator     approvalOrderBelongsToCreator
 
 	Reviewer approvalOrderBelongsToReviewer
@@ -111,6 +113,7 @@ func (a *approvalOrder) updateTableName(table string) *approvalOrder {
 	a.ReviewNotes = field.NewString(table, "review_notes")
 	a.CreatorID = field.NewUint(table, "creator_id")
 	a.ReviewerID = field.NewUint(table, "reviewer_id")
+	a.AssigneeID = field.NewUint(table, "assignee_id")
 
 	a.fillFieldMap()
 
@@ -139,7 +142,7 @@ func (a *approvalOrder) GetFieldByName(fieldName string) (field.OrderExpr, bool)
 }
 
 func (a *approvalOrder) fillFieldMap() {
-	a.fieldMap = make(map[string]field.Expr, 13)
+	a.fieldMap = make(map[string]field.Expr, 14)
 	a.fieldMap["id"] = a.ID
 	a.fieldMap["created_at"] = a.CreatedAt
 	a.fieldMap["updated_at"] = a.UpdatedAt
@@ -151,6 +154,7 @@ func (a *approvalOrder) fillFieldMap() {
 	a.fieldMap["review_notes"] = a.ReviewNotes
 	a.fieldMap["creator_id"] = a.CreatorID
 	a.fieldMap["reviewer_id"] = a.ReviewerID
+	a.fieldMap["assignee_id"] = a.AssigneeID
 
 }
 
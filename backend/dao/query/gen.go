@@ -22,31 +22,46 @@ var (
 	AccountDataset          *accountDataset
 	Alert                   *alert
 	ApprovalOrder           *approvalOrder
+	BillingBudget           *billingBudget
+	BillingLedgerEntry      *billingLedgerEntry
 	CronJobConfig           *cronJobConfig
 	CronJobRecord           *cronJobRecord
 	CudaBaseImage           *cudaBaseImage
 	Dataset                 *dataset
+	DatasetManifest         *datasetManifest
+	DatasetVersion          *datasetVersion
 	GpuAnalysis             *gpuAnalysis
 	Image                   *image
 	ImageAccount            *imageAccount
+	ImageGCMark             *imageGCMark
+	ImageScan               *imageScan
 	ImageUser               *imageUser
 	Job                     *job
+	JobArray                *jobArray
+	JobArrayTask            *jobArrayTask
 	Jobtemplate             *jobtemplate
 	Kaniko                  *kaniko
 	ModelDatasetDiscovery   *modelDatasetDiscovery
 	ModelDatasetSource      *modelDatasetSource
 	ModelDownload           *modelDownload
 	ModelDownloadSubmission *modelDownloadSubmission
+	NotificationDigestItem  *notificationDigestItem
+	NotificationRule        *notificationRule
 	PrequeueConfig          *prequeueConfig
 	QueueQuotaLimit         *queueQuotaLimit
 	Resource                *resource
 	ResourceNetwork         *resourceNetwork
 	ResourceVGPU            *resourceVGPU
+	S3AccessKey             *s3AccessKey
+	StorageQuota            *storageQuota
+	StorageTrashItem        *storageTrashItem
 	SystemConfig            *systemConfig
 	User                    *user
 	UserAccount             *userAccount
 	UserDataset             *userDataset
 	UserModelDownload       *userModelDownload
+	Workflow                *workflow
+	WorkflowNode            *workflowNode
 )
 
 func SetDefault(db *gorm.DB, opts ...gen.DOOption) {
@@ -56,31 +71,46 @@ func SetDefault(db *gorm.DB, opts ...gen.DOOption) {
 	AccountDataset = &Q.AccountDataset
 	Alert = &Q.Alert
 	ApprovalOrder = &Q.ApprovalOrder
+	BillingBudget = &Q.BillingBudget
+	BillingLedgerEntry = &Q.BillingLedgerEntry
 	CronJobConfig = &Q.CronJobConfig
 	CronJobRecord = &Q.CronJobRecord
 	CudaBaseImage = &Q.CudaBaseImage
 	Dataset = &Q.Dataset
+	DatasetManifest = &Q.DatasetManifest
+	DatasetVersion = &Q.DatasetVersion
 	GpuAnalysis = &Q.GpuAnalysis
 	Image = &Q.Image
 	ImageAccount = &Q.ImageAccount
+	ImageGCMark = &Q.ImageGCMark
+	ImageScan = &Q.ImageScan
 	ImageUser = &Q.ImageUser
 	Job = &Q.Job
+	JobArray = &Q.JobArray
+	JobArrayTask = &Q.JobArrayTask
 	Jobtemplate = &Q.Jobtemplate
 	Kaniko = &Q.Kaniko
 	ModelDatasetDiscovery = &Q.ModelDatasetDiscovery
 	ModelDatasetSource = &Q.ModelDatasetSource
 	ModelDownload = &Q.ModelDownload
 	ModelDownloadSubmission = &Q.ModelDownloadSubmission
+	NotificationDigestItem = &Q.NotificationDigestItem
+	NotificationRule = &Q.NotificationRule
 	PrequeueConfig = &Q.PrequeueConfig
 	QueueQuotaLimit = &Q.QueueQuotaLimit
 	Resource = &Q.Resource
 	ResourceNetwork = &Q.ResourceNetwork
 	ResourceVGPU = &Q.ResourceVGPU
+	S3AccessKey = &Q.S3AccessKey
+	StorageQuota = &Q.StorageQuota
+	StorageTrashItem = &Q.StorageTrashItem
 	SystemConfig = &Q.SystemConfig
 	User = &Q.User
 	UserAccount = &Q.UserAccount
 	UserDataset = &Q.UserDataset
 	UserModelDownload = &Q.UserModelDownload
+	Workflow = &Q.Workflow
+	WorkflowNode = &Q.WorkflowNode
 }
 
 func Use(db *gorm.DB, opts ...gen.DOOption) *Query {
@@ -91,31 +121,46 @@ func Use(db *gorm.DB, opts ...gen.DOOption) *Query {
 		AccountDataset:          newAccountDataset(db, opts...),
 		Alert:                   newAlert(db, opts...),
 		ApprovalOrder:           newApprovalOrder(db, opts...),
+		BillingBudget:           newBillingBudget(db, opts...),
+		BillingLedgerEntry:      newBillingLedgerEntry(db, opts...),
 		CronJobConfig:           newCronJobConfig(db, opts...),
 		CronJobRecord:           newCronJobRecord(db, opts...),
 		CudaBaseImage:           newCudaBaseImage(db, opts...),
 		Dataset:                 newDataset(db, opts...),
+		DatasetManifest:         newDatasetManifest(db, opts...),
+		DatasetVersion:          newDatasetVersion(db, opts...),
 		GpuAnalysis:             newGpuAnalysis(db, opts...),
 		Image:                   newImage(db, opts...),
 		ImageAccount:            newImageAccount(db, opts...),
+		ImageGCMark:             newImageGCMark(db, opts...),
+		ImageScan:               newImageScan(db, opts...),
 		ImageUser:               newImageUser(db, opts...),
 		Job:                     newJob(db, opts...),
+		JobArray:                newJobArray(db, opts...),
+		JobArrayTask:            newJobArrayTask(db, opts...),
 		Jobtemplate:             newJobtemplate(db, opts...),
 		Kaniko:                  newKaniko(db, opts...),
 		ModelDatasetDiscovery:   newModelDatasetDiscovery(db, opts...),
 		ModelDatasetSource:      newModelDatasetSource(db, opts...),
 		ModelDownload:           newModelDownload(db, opts...),
 		ModelDownloadSubmission: newModelDownloadSubmission(db, opts...),
+		NotificationDigestItem:  newNotificationDigestItem(db, opts...),
+		NotificationRule:        newNotificationRule(db, opts...),
 		PrequeueConfig:          newPrequeueConfig(db, opts...),
 		QueueQuotaLimit:         newQueueQuotaLimit(db, opts...),
 		Resource:                newResource(db, opts...),
 		ResourceNetwork:         newResourceNetwork(db, opts...),
 		ResourceVGPU:            newResourceVGPU(db, opts...),
+		S3AccessKey:             newS3AccessKey(db, opts...),
+		StorageQuota:            newStorageQuota(db, opts...),
+		StorageTrashItem:        newStorageTrashItem(db, opts...),
 		SystemConfig:            newSystemConfig(db, opts...),
 		User:                    newUser(db, opts...),
 		UserAccount:             newUserAccount(db, opts...),
 		UserDataset:             newUserDataset(db, opts...),
 		UserModelDownload:       newUserModelDownload(db, opts...),
+		Workflow:                newWorkflow(db, opts...),
+		WorkflowNode:            newWorkflowNode(db, opts...),
 	}
 }
 
@@ -127,31 +172,46 @@ type Query struct {
 	AccountDataset          accountDataset
 	Alert                   alert
 	ApprovalOrder           approvalOrder
+	BillingBudget           billingBudget
+	BillingLedgerEntry      billingLedgerEntry
 	CronJobConfig           cronJobConfig
 	CronJobRecord           cronJobRecord
 	CudaBaseImage           cudaBaseImage
 	Dataset                 dataset
+	DatasetManifest         datasetManifest
+	DatasetVersion          datasetVersion
 	GpuAnalysis             gpuAnalysis
 	Image                   image
 	ImageAccount            imageAccount
+	ImageGCMark             imageGCMark
+	ImageScan               imageScan
 	ImageUser               imageUser
 	Job                     job
+	JobArray                jobArray
+	JobArrayTask            jobArrayTask
 	Jobtemplate             jobtemplate
 	Kaniko                  kaniko
 	ModelDatasetDiscovery   modelDatasetDiscovery
 	ModelDatasetSource      modelDatasetSource
 	ModelDownload           modelDownload
 	ModelDownloadSubmission modelDownloadSubmission
+	NotificationDigestItem  notificationDigestItem
+	NotificationRule        notificationRule
 	PrequeueConfig          prequeueConfig
 	QueueQuotaLimit         queueQuotaLimit
 	Resource                resource
 	ResourceNetwork         resourceNetwork
 	ResourceVGPU            resourceVGPU
+	S3AccessKey             s3AccessKey
+	StorageQuota            storageQuota
+	StorageTrashItem        storageTrashItem
 	SystemConfig            systemConfig
 	User                    user
 	UserAccount             userAccount
 	UserDataset             userDataset
 	UserModelDownload       userModelDownload
+	Workflow                workflow
+	WorkflowNode            workflowNode
 }
 
 func (q *Query) Available() bool { return q.db != nil }
@@ -164,31 +224,46 @@ func (q *Query) clone(db *gorm.DB) *Query {
 		AccountDataset:          q.AccountDataset.clone(db),
 		Alert:                   q.Alert.clone(db),
 		ApprovalOrder:           q.ApprovalOrder.clone(db),
+		BillingBudget:           q.BillingBudget.clone(db),
+		BillingLedgerEntry:      q.BillingLedgerEntry.clone(db),
 		CronJobConfig:           q.CronJobConfig.clone(db),
 		CronJobRecord:           q.CronJobRecord.clone(db),
 		CudaBaseImage:           q.CudaBaseImage.clone(db),
 		Dataset:                 q.Dataset.clone(db),
+		DatasetManifest:         q.DatasetManifest.clone(db),
+		DatasetVersion:          q.DatasetVersion.clone(db),
 		GpuAnalysis:             q.GpuAnalysis.clone(db),
 		Image:                   q.Image.clone(db),
 		ImageAccount:            q.ImageAccount.clone(db),
+		ImageGCMark:             q.ImageGCMark.clone(db),
+		ImageScan:               q.ImageScan.clone(db),
 		ImageUser:               q.ImageUser.clone(db),
 		Job:                     q.Job.clone(db),
+		JobArray:                q.JobArray.clone(db),
+		JobArrayTask:            q.JobArrayTask.clone(db),
 		Jobtemplate:             q.Jobtemplate.clone(db),
 		Kaniko:                  q.Kaniko.clone(db),
 		ModelDatasetDiscovery:   q.ModelDatasetDiscovery.clone(db),
 		ModelDatasetSource:      q.ModelDatasetSource.clone(db),
 		ModelDownload:           q.ModelDownload.clone(db),
 		ModelDownloadSubmission: q.ModelDownloadSubmission.clone(db),
+		NotificationDigestItem:  q.NotificationDigestItem.clone(db),
+		NotificationRule:        q.NotificationRule.clone(db),
 		PrequeueConfig:          q.PrequeueConfig.clone(db),
 		QueueQuotaLimit:         q.QueueQuotaLimit.clone(db),
 		Resource:                q.Resource.clone(db),
 		ResourceNetwork:         q.ResourceNetwork.clone(db),
 		ResourceVGPU:            q.ResourceVGPU.clone(db),
+		S3AccessKey:             q.S3AccessKey.clone(db),
+		StorageQuota:            q.StorageQuota.clone(db),
+		StorageTrashItem:        q.StorageTrashItem.clone(db),
 		SystemConfig:            q.SystemConfig.clone(db),
 		User:                    q.User.clone(db),
 		UserAccount:             q.UserAccount.clone(db),
 		UserDataset:             q.UserDataset.clone(db),
 		UserModelDownload:       q.UserModelDownload.clone(db),
+		Workflow:                q.Workflow.clone(db),
+		WorkflowNode:            q.WorkflowNode.clone(db),
 	}
 }
 
@@ -208,31 +283,46 @@ func (q *Query) ReplaceDB(db *gorm.DB) *Query {
 		AccountDataset:          q.AccountDataset.replaceDB(db),
 		Alert:                   q.Alert.replaceDB(db),
 		ApprovalOrder:           q.ApprovalOrder.replaceDB(db),
+		BillingBudget:           q.BillingBudget.replaceDB(db),
+		BillingLedgerEntry:      q.BillingLedgerEntry.replaceDB(db),
 		CronJobConfig:           q.CronJobConfig.replaceDB(db),
 		CronJobRecord:           q.CronJobRecord.replaceDB(db),
 		CudaBaseImage:           q.CudaBaseImage.replaceDB(db),
 		Dataset:                 q.Dataset.replaceDB(db),
+		DatasetManifest:         q.DatasetManifest.replaceDB(db),
+		DatasetVersion:          q.DatasetVersion.replaceDB(db),
 		GpuAnalysis:             q.GpuAnalysis.replaceDB(db),
 		Image:                   q.Image.replaceDB(db),
 		ImageAccount:            q.ImageAccount.replaceDB(db),
+		ImageGCMark:             q.ImageGCMark.replaceDB(db),
+		ImageScan:               q.ImageScan.replaceDB(db),
 		ImageUser:               q.ImageUser.replaceDB(db),
 		Job:                     q.Job.replaceDB(db),
+		JobArray:                q.JobArray.replaceDB(db),
+		JobArrayTask:            q.JobArrayTask.replaceDB(db),
 		Jobtemplate:             q.Jobtemplate.replaceDB(db),
 		Kaniko:                  q.Kaniko.replaceDB(db),
 		ModelDatasetDiscovery:   q.ModelDatasetDiscovery.replaceDB(db),
 		ModelDatasetSource:      q.ModelDatasetSource.replaceDB(db),
 		ModelDownload:           q.ModelDownload.replaceDB(db),
 		ModelDownloadSubmission: q.ModelDownloadSubmission.replaceDB(db),
+		NotificationDigestItem:  q.NotificationDigestItem.replaceDB(db),
+		NotificationRule:        q.NotificationRule.replaceDB(db),
 		PrequeueConfig:          q.PrequeueConfig.replaceDB(db),
 		QueueQuotaLimit:         q.QueueQuotaLimit.replaceDB(db),
 		Resource:                q.Resource.replaceDB(db),
 		ResourceNetwork:         q.ResourceNetwork.replaceDB(db),
 		ResourceVGPU:            q.ResourceVGPU.replaceDB(db),
+		S3AccessKey:             q.S3AccessKey.replaceDB(db),
+		StorageQuota:            q.StorageQuota.replaceDB(db),
+		StorageTrashItem:        q.StorageTrashItem.replaceDB(db),
 		SystemConfig:            q.SystemConfig.replaceDB(db),
 		User:                    q.User.replaceDB(db),
 		UserAccount:             q.UserAccount.replaceDB(db),
 		UserDataset:             q.UserDataset.replaceDB(db),
 		UserModelDownload:       q.UserModelDownload.replaceDB(db),
+		Workflow:                q.Workflow.replaceDB(db),
+		WorkflowNode:            q.WorkflowNode.replaceDB(db),
 	}
 }
 
@@ -242,31 +332,46 @@ type queryCtx struct {
 	AccountDataset          IAccountDatasetDo
 	Alert                   IAlertDo
 	ApprovalOrder           IApprovalOrderDo
+	BillingBudget           IBillingBudgetDo
+	BillingLedgerEntry      IBillingLedgerEntryDo
 	CronJobConfig           ICronJobConfigDo
 	CronJobRecord           ICronJobRecordDo
 	CudaBaseImage           ICudaBaseImageDo
 	Dataset                 IDatasetDo
+	DatasetManifest         IDatasetManifestDo
+	DatasetVersion          IDatasetVersionDo
 	GpuAnalysis             IGpuAnalysisDo
 	Image                   IImageDo
 	ImageAccount            IImageAccountDo
+	ImageGCMark             IImageGCMarkDo
+	ImageScan               IImageScanDo
 	ImageUser               IImageUserDo
 	Job                     IJobDo
+	JobArray                IJobArrayDo
+	JobArrayTask            IJobArrayTaskDo
 	Jobtemplate             IJobtemplateDo
 	Kaniko                  IKanikoDo
 	ModelDatasetDiscovery   IModelDatasetDiscoveryDo
 	ModelDatasetSource      IModelDatasetSourceDo
 	ModelDownload           IModelDownloadDo
 	ModelDownloadSubmission IModelDownloadSubmissionDo
+	NotificationDigestItem  INotificationDigestItemDo
+	NotificationRule        INotificationRuleDo
 	PrequeueConfig          IPrequeueConfigDo
 	QueueQuotaLimit         IQueueQuotaLimitDo
 	Resource                IResourceDo
 	ResourceNetwork         IResourceNetworkDo
 	ResourceVGPU            IResourceVGPUDo
+	S3AccessKey             IS3AccessKeyDo
+	StorageQuota            IStorageQuotaDo
+	StorageTrashItem        IStorageTrashItemDo
 	SystemConfig            ISystemConfigDo
 	User                    IUserDo
 	UserAccount             IUserAccountDo
 	UserDataset             IUserDatasetDo
 	UserModelDownload       IUserModelDownloadDo
+	Workflow                IWorkflowDo
+	WorkflowNode            IWorkflowNodeDo
 }
 
 func (q *Query) WithContext(ctx context.Context) *queryCtx {
@@ -276,31 +381,46 @@ func (q *Query) WithContext(ctx context.Context) *queryCtx {
 		AccountDataset:          q.AccountDataset.WithContext(ctx),
 		Alert:                   q.Alert.WithContext(ctx),
 		ApprovalOrder:           q.ApprovalOrder.WithContext(ctx),
+		BillingBudget:           q.BillingBudget.WithContext(ctx),
+		BillingLedgerEntry:      q.BillingLedgerEntry.WithContext(ctx),
 		CronJobConfig:           q.CronJobConfig.WithContext(ctx),
 		CronJobRecord:           q.CronJobRecord.WithContext(ctx),
 		CudaBaseImage:           q.CudaBaseImage.WithContext(ctx),
 		Dataset:                 q.Dataset.WithContext(ctx),
+		DatasetManifest:         q.DatasetManifest.WithContext(ctx),
+		DatasetVersion:          q.DatasetVersion.WithContext(ctx),
 		GpuAnalysis:             q.GpuAnalysis.WithContext(ctx),
 		Image:                   q.Image.WithContext(ctx),
 		ImageAccount:            q.ImageAccount.WithContext(ctx),
+		ImageGCMark:             q.ImageGCMark.WithContext(ctx),
+		ImageScan:               q.ImageScan.WithContext(ctx),
 		ImageUser:               q.ImageUser.WithContext(ctx),
 		Job:                     q.Job.WithContext(ctx),
+		JobArray:                q.JobArray.WithContext(ctx),
+		JobArrayTask:            q.JobArrayTask.WithContext(ctx),
 		Jobtemplate:             q.Jobtemplate.WithContext(ctx),
 		Kaniko:                  q.Kaniko.WithContext(ctx),
 		ModelDatasetDiscovery:   q.ModelDatasetDiscovery.WithContext(ctx),
 		ModelDatasetSource:      q.ModelDatasetSource.WithContext(ctx),
 		ModelDownload:           q.ModelDownload.WithContext(ctx),
 		ModelDownloadSubmission: q.ModelDownloadSubmission.WithContext(ctx),
+		NotificationDigestItem:  q.NotificationDigestItem.WithContext(ctx),
+		NotificationRule:        q.NotificationRule.WithContext(ctx),
 		PrequeueConfig:          q.PrequeueConfig.WithContext(ctx),
 		QueueQuotaLimit:         q.QueueQuotaLimit.WithContext(ctx),
 		Resource:                q.Resource.WithContext(ctx),
 		ResourceNetwork:         q.ResourceNetwork.WithContext(ctx),
 		ResourceVGPU:            q.ResourceVGPU.WithContext(ctx),
+		S3AccessKey:             q.S3AccessKey.WithContext(ctx),
+		StorageQuota:            q.StorageQuota.WithContext(ctx),
+		StorageTrashItem:        q.StorageTrashItem.WithContext(ctx),
 		SystemConfig:            q.SystemConfig.WithContext(ctx),
 		User:                    q.User.WithContext(ctx),
 		UserAccount:             q.UserAccount.WithContext(ctx),
 		UserDataset:             q.UserDataset.WithContext(ctx),
 		UserModelDownload:       q.UserModelDownload.WithContext(ctx),
+		Workflow:                q.Workflow.WithContext(ctx),
+		WorkflowNode:            q.WorkflowNode.WithContext(ctx),
 	}
 }
 
@@ -34,6 +34,7 @@ func newImageAccount(db *gorm.DB, opts ...gen.DOOption) imageAccount {
 	_imageAccount.DeletedAt = field.NewField(tableName, "deleted_at")
 	_imageAccount.ImageID = field.NewUint(tableName, "image_id")
 	_imageAccount.AccountID = field.NewUint(tableName, "account_id")
+	_imageAccount.ExpiresAt = field.NewTime(tableName, "expires_at")
 	_imageAccount.Image = imageAccountBelongsToImage{
 		db: db.Session(&gorm.Session{}),
 
@@ -92,6 +93,7 @@ type imageAccount struct {
 	DeletedAt field.Field
 	ImageID   field.Uint
 	AccountID field.Uint
+	ExpiresAt field.Time // 分享到期时间，为空表示永久
 	Image     imageAccountBelongsToImage
 
 	Account imageAccountBelongsToAccount
@@ -117,6 +119,7 @@ func (i *imageAccount) updateTableName(table string) *imageAccount {
 	i.DeletedAt = field.NewField(table, "deleted_at")
 	i.ImageID = field.NewUint(table, "image_id")
 	i.AccountID = field.NewUint(table, "account_id")
+	i.ExpiresAt = field.NewTime(table, "expires_at")
 
 	i.fillFieldMap()
 
@@ -145,13 +148,14 @@ func (i *imageAccount) GetFieldByName(fieldName string) (field.OrderExpr, bool)
 }
 
 func (i *imageAccount) fillFieldMap() {
-	i.fieldMap = make(map[string]field.Expr, 8)
+	i.fieldMap = make(map[string]field.Expr, 9)
 	i.fieldMap["id"] = i.ID
 	i.fieldMap["created_at"] = i.CreatedAt
 	i.fieldMap["updated_at"] = i.UpdatedAt
 	i.fieldMap["deleted_at"] = i.DeletedAt
 	i.fieldMap["image_id"] = i.ImageID
 	i.fieldMap["account_id"] = i.AccountID
+	i.fieldMap["expires_at"] = i.ExpiresAt
 
 }
 
@@ -34,6 +34,7 @@ func newImageUser(db *gorm.DB, opts ...gen.DOOption) imageUser {
 	_imageUser.DeletedAt = field.NewField(tableName, "deleted_at")
 	_imageUser.ImageID = field.NewUint(tableName, "image_id")
 	_imageUser.UserID = field.NewUint(tableName, "user_id")
+	_imageUser.ExpiresAt = field.NewTime(tableName, "expires_at")
 	_imageUser.Image = imageUserBelongsToImage{
 		db: db.Session(&gorm.Session{}),
 
@@ -82,6 +83,7 @@ type imageUser struct {
 	DeletedAt field.Field
 	ImageID   field.Uint
 	UserID    field.Uint
+	ExpiresAt field.Time // 分享到期时间，为空表示永久
 	Image     imageUserBelongsToImage
 
 	User imageUserBelongsToUser
@@ -107,6 +109,7 @@ func (i *imageUser) updateTableName(table string) *imageUser {
 	i.DeletedAt = field.NewField(table, "deleted_at")
 	i.ImageID = field.NewUint(table, "image_id")
 	i.UserID = field.NewUint(table, "user_id")
+	i.ExpiresAt = field.NewTime(table, "expires_at")
 
 	i.fillFieldMap()
 
@@ -133,13 +136,14 @@ func (i *imageUser) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
 }
 
 func (i *imageUser) fillFieldMap() {
-	i.fieldMap = make(map[string]field.Expr, 8)
+	i.fieldMap = make(map[string]field.Expr, 9)
 	i.fieldMap["id"] = i.ID
 	i.fieldMap["created_at"] = i.CreatedAt
 	i.fieldMap["updated_at"] = i.UpdatedAt
 	i.fieldMap["deleted_at"] = i.DeletedAt
 	i.fieldMap["image_id"] = i.ImageID
 	i.fieldMap["user_id"] = i.UserID
+	i.fieldMap["expires_at"] = i.ExpiresAt
 
 }
 
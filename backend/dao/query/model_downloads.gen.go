@@ -65,6 +65,13 @@ func newModelDownload(db *gorm.DB, opts ...gen.DOOption) modelDownload {
 	_modelDownload.MetadataRefreshedAt = field.NewTime(tableName, "metadata_refreshed_at")
 	_modelDownload.ModelDatasetSourceID = field.NewUint(tableName, "model_dataset_source_id")
 	_modelDownload.JobName = field.NewString(tableName, "job_name")
+	_modelDownload.RemoteURL = field.NewString(tableName, "remote_url")
+	_modelDownload.S3Endpoint = field.NewString(tableName, "s3_endpoint")
+	_modelDownload.S3Region = field.NewString(tableName, "s3_region")
+	_modelDownload.S3AccessKey = field.NewString(tableName, "s3_access_key")
+	_modelDownload.S3SecretKey = field.NewString(tableName, "s3_secret_key")
+	_modelDownload.Extract = field.NewBool(tableName, "extract")
+	_modelDownload.Checksum = field.NewString(tableName, "checksum")
 	_modelDownload.CreatorID = field.NewUint(tableName, "creator_id")
 	_modelDownload.ReferenceCount = field.NewInt(tableName, "reference_count")
 	_modelDownload.ModelDatasetSource = modelDownloadBelongsToModelDatasetSource{
@@ -135,6 +142,13 @@ type modelDownload struct {
 	MetadataRefreshedAt  field.Time   // 源站元数据刷新时间
 	ModelDatasetSourceID field.Uint   // 模型或数据集外部来源ID
 	JobName              field.String // K8s Job名称
+	RemoteURL            field.String // S3或HTTP下载源地址
+	S3Endpoint           field.String // S3兼容服务地址
+	S3Region             field.String // S3区域
+	S3AccessKey          field.String // 加密后的S3访问密钥ID
+	S3SecretKey          field.String // 加密后的S3访问密钥
+	Extract              field.Bool   // 下载完成后是否解压归档
+	Checksum             field.String // 期望的文件校验和(sha256:<hex>)
 	CreatorID            field.Uint   // 首个发起下载的用户ID
 	ReferenceCount       field.Int    // 提交下载需求的用户计数
 	ModelDatasetSource   modelDownloadBelongsToModelDatasetSource
@@ -193,6 +207,13 @@ func (m *modelDownload) updateTableName(table string) *modelDownload {
 	m.MetadataRefreshedAt = field.NewTime(table, "metadata_refreshed_at")
 	m.ModelDatasetSourceID = field.NewUint(table, "model_dataset_source_id")
 	m.JobName = field.NewString(table, "job_name")
+	m.RemoteURL = field.NewString(table, "remote_url")
+	m.S3Endpoint = field.NewString(table, "s3_endpoint")
+	m.S3Region = field.NewString(table, "s3_region")
+	m.S3AccessKey = field.NewString(table, "s3_access_key")
+	m.S3SecretKey = field.NewString(table, "s3_secret_key")
+	m.Extract = field.NewBool(table, "extract")
+	m.Checksum = field.NewString(table, "checksum")
 	m.CreatorID = field.NewUint(table, "creator_id")
 	m.ReferenceCount = field.NewInt(table, "reference_count")
 
@@ -223,7 +244,7 @@ func (m *modelDownload) GetFieldByName(fieldName string) (field.OrderExpr, bool)
 }
 
 func (m *modelDownload) fillFieldMap() {
-	m.fieldMap = make(map[string]field.Expr, 41)
+	m.fieldMap = make(map[string]field.Expr, 48)
 	m.fieldMap["id"] = m.ID
 	m.fieldMap["created_at"] = m.CreatedAt
 	m.fieldMap["updated_at"] = m.UpdatedAt
@@ -261,6 +282,13 @@ func (m *modelDownload) fillFieldMap() {
 	m.fieldMap["metadata_refreshed_at"] = m.MetadataRefreshedAt
 	m.fieldMap["model_dataset_source_id"] = m.ModelDatasetSourceID
 	m.fieldMap["job_name"] = m.JobName
+	m.fieldMap["remote_url"] = m.RemoteURL
+	m.fieldMap["s3_endpoint"] = m.S3Endpoint
+	m.fieldMap["s3_region"] = m.S3Region
+	m.fieldMap["s3_access_key"] = m.S3AccessKey
+	m.fieldMap["s3_secret_key"] = m.S3SecretKey
+	m.fieldMap["extract"] = m.Extract
+	m.fieldMap["checksum"] = m.Checksum
 	m.fieldMap["creator_id"] = m.CreatorID
 	m.fieldMap["reference_count"] = m.ReferenceCount
 
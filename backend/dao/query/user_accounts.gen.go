@@ -39,6 +39,7 @@ func newUserAccount(db *gorm.DB, opts ...gen.DOOption) userAccount {
 	_userAccount.Quota = field.NewField(tableName, "quota")
 	_userAccount.BillingIssueAmountOverride = field.NewInt64(tableName, "billing_issue_amount_override")
 	_userAccount.PeriodFreeBalance = field.NewInt64(tableName, "period_free_balance")
+	_userAccount.FairShare = field.NewInt(tableName, "fair_share")
 
 	_userAccount.fillFieldMap()
 
@@ -60,6 +61,7 @@ type userAccount struct {
 	Quota                      field.Field // 用户在账户中的资源配额
 	BillingIssueAmountOverride field.Int64 // 用户在账户内的周期发放额度覆盖(内部微点, 为空表示沿用账户配置)
 	PeriodFreeBalance          field.Int64 // 用户在当前周期的免费额度剩余(内部微点)
+	FairShare                  field.Int   // 用户在账户内的公平调度份额(为空表示默认份额1)
 
 	fieldMap map[string]field.Expr
 }
@@ -87,6 +89,7 @@ func (u *userAccount) updateTableName(table string) *userAccount {
 	u.Quota = field.NewField(table, "quota")
 	u.BillingIssueAmountOverride = field.NewInt64(table, "billing_issue_amount_override")
 	u.PeriodFreeBalance = field.NewInt64(table, "period_free_balance")
+	u.FairShare = field.NewInt(table, "fair_share")
 
 	u.fillFieldMap()
 
@@ -113,7 +116,7 @@ func (u *userAccount) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
 }
 
 func (u *userAccount) fillFieldMap() {
-	u.fieldMap = make(map[string]field.Expr, 11)
+	u.fieldMap = make(map[string]field.Expr, 12)
 	u.fieldMap["id"] = u.ID
 	u.fieldMap["created_at"] = u.CreatedAt
 	u.fieldMap["updated_at"] = u.UpdatedAt
@@ -125,6 +128,7 @@ func (u *userAccount) fillFieldMap() {
 	u.fieldMap["quota"] = u.Quota
 	u.fieldMap["billing_issue_amount_override"] = u.BillingIssueAmountOverride
 	u.fieldMap["period_free_balance"] = u.PeriodFreeBalance
+	u.fieldMap["fair_share"] = u.FairShare
 }
 
 func (u userAccount) clone(db *gorm.DB) userAccount {
@@ -34,6 +34,7 @@ func newUserDataset(db *gorm.DB, opts ...gen.DOOption) userDataset {
 	_userDataset.DeletedAt = field.NewField(tableName, "deleted_at")
 	_userDataset.UserID = field.NewUint(tableName, "user_id")
 	_userDataset.DatasetID = field.NewUint(tableName, "dataset_id")
+	_userDataset.ExpiresAt = field.NewTime(tableName, "expires_at")
 
 	_userDataset.fillFieldMap()
 
@@ -50,6 +51,7 @@ type userDataset struct {
 	DeletedAt field.Field
 	UserID    field.Uint
 	DatasetID field.Uint
+	ExpiresAt field.Time // 授权到期时间，为空表示永久
 
 	fieldMap map[string]field.Expr
 }
@@ -72,6 +74,7 @@ func (u *userDataset) updateTableName(table string) *userDataset {
 	u.DeletedAt = field.NewField(table, "deleted_at")
 	u.UserID = field.NewUint(table, "user_id")
 	u.DatasetID = field.NewUint(table, "dataset_id")
+	u.ExpiresAt = field.NewTime(table, "expires_at")
 
 	u.fillFieldMap()
 
@@ -98,13 +101,14 @@ func (u *userDataset) GetFieldByName(fieldName string) (field.OrderExpr, bool) {
 }
 
 func (u *userDataset) fillFieldMap() {
-	u.fieldMap = make(map[string]field.Expr, 6)
+	u.fieldMap = make(map[string]field.Expr, 7)
 	u.fieldMap["id"] = u.ID
 	u.fieldMap["created_at"] = u.CreatedAt
 	u.fieldMap["updated_at"] = u.UpdatedAt
 	u.fieldMap["deleted_at"] = u.DeletedAt
 	u.fieldMap["user_id"] = u.UserID
 	u.fieldMap["dataset_id"] = u.DatasetID
+	u.fieldMap["expires_at"] = u.ExpiresAt
 }
 
 func (u userDataset) clone(db *gorm.DB) userDataset {
@@ -42,6 +42,7 @@ type AccountMgr struct {
 	name           string
 	client         client.Client
 	billingService *service.BillingService
+	prequeueSvc    *service.PrequeueService
 }
 
 func NewAccountMgr(conf *RegisterConfig) Manager {
@@ -49,6 +50,7 @@ func NewAccountMgr(conf *RegisterConfig) Manager {
 		name:           "accounts",
 		client:         conf.Client,
 		billingService: conf.BillingService,
+		prequeueSvc:    conf.PrequeueService,
 	}
 }
 
@@ -122,6 +124,10 @@ func (mgr *AccountMgr) registerUserBillingRoutes(g *gin.RouterGroup) {
 	g.GET(":aid/billing/members", mgr.UserListAccountBillingMembers)
 	g.PUT(":aid/billing/members/:uid", mgr.UserUpdateAccountBillingMemberIssueAmount)
 	g.POST(":aid/billing/reset", mgr.UserResetAccountBillingBalance)
+	g.GET(":aid/billing/statement", mgr.UserGetAccountBillingStatement)
+	g.GET(":aid/billing/budgets", mgr.UserListAccountBudgets)
+	g.PUT(":aid/billing/budgets", mgr.UserUpsertAccountBudget)
+	g.DELETE(":aid/billing/budgets/:uid", mgr.UserDeleteAccountBudget)
 }
 
 func (mgr *AccountMgr) registerUserMemberRoutes(g *gin.RouterGroup) {
@@ -143,6 +149,12 @@ func (mgr *AccountMgr) RegisterAdmin(g *gin.RouterGroup) {
 	g.GET(":aid/billing/members", mgr.AdminListAccountBillingMembers)
 	g.PUT(":aid/billing/members/:uid", mgr.AdminUpdateAccountBillingMemberIssueAmount)
 	g.POST(":aid/billing/reset", mgr.AdminResetAccountBillingBalance)
+	g.GET(":aid/billing/statement", mgr.AdminGetAccountBillingStatement)
+	g.GET(":aid/billing/budgets", mgr.AdminListAccountBudgets)
+	g.PUT(":aid/billing/budgets", mgr.AdminUpsertAccountBudget)
+	g.DELETE(":aid/billing/budgets/:uid", mgr.AdminDeleteAccountBudget)
+	g.GET(":aid/fair-share", mgr.AdminGetAccountFairShare)
+	g.PUT(":aid/fair-share", mgr.AdminUpdateAccountFairShare)
 	g.PUT(":aid", mgr.UpdateAccount)
 	g.DELETE(":aid", mgr.DeleteAccount)
 	g.POST("add/:aid/:uid", mgr.AdminAddAccountMember)
@@ -823,6 +835,77 @@ func (mgr *AccountMgr) UserResetAccountBillingBalance(c *gin.Context) {
 	resputil.Success(c, resp)
 }
 
+// AdminGetAccountBillingStatement godoc
+//
+//	@Summary		Export an account billing statement
+//	@Description	Aggregate job settlements and free balance issuance of every account member over a period, as JSON or CSV
+//	@Tags			Project
+//	@Produce		json
+//	@Produce		text/csv
+//	@Security		Bearer
+//	@Param			aid		path		uint										true	"account id"
+//	@Param			from	query		string										false	"start date (YYYY-MM-DD or RFC3339)"
+//	@Param			to		query		string										false	"end date, inclusive for YYYY-MM-DD"
+//	@Param			format	query		string										false	"json or csv"
+//	@Success		200		{object}	resputil.Response[BillingStatementResp]	"statement"
+//	@Failure		400		{object}	resputil.Response[any]						"invalid period"
+//	@Failure		500		{object}	resputil.Response[any]						"other errors"
+//	@Router			/v1/admin/accounts/{aid}/billing/statement [get]
+func (mgr *AccountMgr) AdminGetAccountBillingStatement(c *gin.Context) {
+	accountID, ok := mgr.bindAccountID(c)
+	if !ok {
+		return
+	}
+	if _, err := mgr.validateAccount(c, accountID); err != nil {
+		resputil.HandleError(c, err)
+		return
+	}
+	mgr.writeAccountBillingStatement(c, accountID)
+}
+
+// UserGetAccountBillingStatement godoc
+//
+//	@Summary		Export an account billing statement
+//	@Description	Same as the admin endpoint, for account administrators
+//	@Tags			Project
+//	@Produce		json
+//	@Produce		text/csv
+//	@Security		Bearer
+//	@Param			aid		path		uint										true	"account id"
+//	@Param			from	query		string										false	"start date (YYYY-MM-DD or RFC3339)"
+//	@Param			to		query		string										false	"end date, inclusive for YYYY-MM-DD"
+//	@Param			format	query		string										false	"json or csv"
+//	@Success		200		{object}	resputil.Response[BillingStatementResp]	"statement"
+//	@Failure		400		{object}	resputil.Response[any]						"invalid period"
+//	@Failure		403		{object}	resputil.Response[any]						"not an account administrator"
+//	@Router			/v1/accounts/{aid}/billing/statement [get]
+func (mgr *AccountMgr) UserGetAccountBillingStatement(c *gin.Context) {
+	if err := mgr.requireUserFacingBillingEnabled(c.Request.Context()); err != nil {
+		resputil.HandleError(c, err)
+		return
+	}
+	accountID, ok := mgr.requireUserManagedAccountID(c)
+	if !ok {
+		return
+	}
+	mgr.writeAccountBillingStatement(c, accountID)
+}
+
+func (mgr *AccountMgr) writeAccountBillingStatement(c *gin.Context, accountID uint) {
+	var req BillingStatementReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.InvalidRequest.Wrap(err, "invalid query parameters"))
+		return
+	}
+	q, err := resolveBillingStatementQuery(&req, time.Now())
+	if err != nil {
+		resputil.HandleError(c, err)
+		return
+	}
+	q.AccountID = accountID
+	writeBillingStatement(c, mgr.billingService, req.Format, q)
+}
+
 func (mgr *AccountMgr) createAccountCore(
 	c *gin.Context,
 	token util.JWTMessage,
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -16,6 +17,7 @@ import (
 	"github.com/raids-lab/crater/dao/query"
 	"github.com/raids-lab/crater/internal/bizerr"
 	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/internal/service"
 	"github.com/raids-lab/crater/internal/util"
 	"github.com/raids-lab/crater/pkg/utils"
 )
@@ -32,12 +34,14 @@ func init() {
 }
 
 type ApprovalOrderMgr struct {
-	name string
+	name                string
+	notificationService *service.NotificationService
 }
 
-func NewApprovalOrderMgr(_ *RegisterConfig) Manager {
+func NewApprovalOrderMgr(conf *RegisterConfig) Manager {
 	return &ApprovalOrderMgr{
-		name: "approvalorder",
+		name:                "approvalorder",
+		notificationService: conf.NotificationService,
 	}
 }
 func (mgr *ApprovalOrderMgr) GetName() string { return mgr.name }
@@ -46,12 +50,14 @@ func (mgr *ApprovalOrderMgr) RegisterPublic(_ *gin.RouterGroup) {}
 
 func (mgr *ApprovalOrderMgr) RegisterProtected(g *gin.RouterGroup) {
 	// RESTful 风格的路由设计
-	g.GET("", mgr.GetMyApprovalOrders)               // 获取我的审批工单列表
-	g.GET("/:id", mgr.GetApprovalOrder)              // 通过ID获取审批工单详情
-	g.POST("", mgr.CreateApprovalOrder)              // 创建审批工单
-	g.PUT("/:id", mgr.UpdateApprovalOrder)           // 更新审批工单
-	g.DELETE("/:id", mgr.DeleteApprovalOrder)        // 删除审批工单
-	g.GET("/name/:name", mgr.GetApprovalOrderByName) // 通过名字获取审批工单
+	g.GET("", mgr.GetMyApprovalOrders)                    // 获取我的审批工单列表
+	g.GET("/assigned", mgr.GetAssignedApprovalOrders)     // 获取指派给我审批的工单列表
+	g.GET("/:id", mgr.GetApprovalOrder)                   // 通过ID获取审批工单详情
+	g.POST("", mgr.CreateApprovalOrder)                   // 创建审批工单
+	g.PUT("/:id", mgr.UpdateApprovalOrder)                // 更新审批工单
+	g.PUT("/:id/review", mgr.ReviewAssignedApprovalOrder) // 指定审批人（如数据集所有者）审核工单
+	g.DELETE("/:id", mgr.DeleteApprovalOrder)             // 删除审批工单
+	g.GET("/name/:name", mgr.GetApprovalOrderByName)      // 通过名字获取审批工单
 }
 
 func (mgr *ApprovalOrderMgr) RegisterAdmin(g *gin.RouterGroup) {
@@ -194,6 +200,7 @@ type ApprovalOrderResp struct {
 	Creator    model.UserInfo `json:"creator"`
 	ReviewerID uint           `json:"reviewerID"`
 	Reviewer   model.UserInfo `json:"reviewer"`
+	AssigneeID uint           `json:"assigneeID"`
 }
 
 // swagger
@@ -269,6 +276,7 @@ func convertToApprovalOrderResps(orders []*model.ApprovalOrder) []ApprovalOrderR
 				Nickname: orders[i].Creator.Nickname,
 			},
 			ReviewerID: orders[i].ReviewerID,
+			AssigneeID: orders[i].AssigneeID,
 		}
 
 		// 处理审批人信息
@@ -322,6 +330,8 @@ type ApprovalOrderreq struct {
 	TypeID         uint                      `json:"approvalorderTypeID"`         // 关联的ID，可能是数据集或任务ID
 	Reason         string                    `json:"approvalOrderReason"`         // 审批原因
 	ExtensionHours uint                      `json:"approvalOrderExtensionHours"` // 延长小时数
+	AccountID      uint                      `json:"approvalOrderAccountID"`      // 数据集申请：为该账户申请，0 表示为本人申请
+	ExpiresAt      *time.Time                `json:"approvalOrderExpiresAt"`      // 数据集申请：授权到期时间，为空表示永久
 }
 
 // swagger
@@ -371,6 +381,18 @@ func (mgr *ApprovalOrderMgr) CreateApprovalOrder(c *gin.Context) {
 		}
 	}
 
+	// 数据集访问申请由数据集所有者审批，工单名称使用数据集名称
+	var assigneeID uint
+	if req.Type == model.ApprovalOrderTypeDataset {
+		dataset, err := prepareDatasetAccessOrder(c, token, &req)
+		if err != nil {
+			resputil.HandleError(c, err)
+			return
+		}
+		req.Name = dataset.Name
+		assigneeID = dataset.UserID
+	}
+
 	// 2. 检查是否满足自动审批条件
 	autoApproved := false
 
@@ -409,9 +431,12 @@ func (mgr *ApprovalOrderMgr) CreateApprovalOrder(c *gin.Context) {
 			ApprovalOrderTypeID:         req.TypeID,
 			ApprovalOrderExtensionHours: req.ExtensionHours,
 			ApprovalOrderReason:         req.Reason,
+			ApprovalOrderAccountID:      req.AccountID,
+			ApprovalOrderExpiresAt:      req.ExpiresAt,
 		}),
 		CreatorID:   token.UserID,
 		ReviewNotes: orderReason,
+		AssigneeID:  assigneeID,
 	}
 
 	if err := query.ApprovalOrder.WithContext(c).Create(&order); err != nil {
@@ -420,6 +445,12 @@ func (mgr *ApprovalOrderMgr) CreateApprovalOrder(c *gin.Context) {
 		return
 	}
 
+	if assigneeID != 0 {
+		// webhook 渠道可能较慢，不阻塞申请请求
+		go mgr.notificationService.NotifyApprovalOrderRequested(
+			context.WithoutCancel(c.Request.Context()), &order, token.Username)
+	}
+
 	message := "create approvalorder successfully"
 	if autoApproved {
 		message = "create approvalorder successfully and auto-approved with job locked"
@@ -435,6 +466,8 @@ type UpdateApprovalOrder struct {
 	TypeID         uint                      `json:"approvalorderTypeID"`         // 关联的ID，可能是数据集或任务ID
 	Reason         string                    `json:"approvalOrderReason"`         // 审批原因
 	ExtensionHours uint                      `json:"approvalOrderExtensionHours"` // 延长小时数
+	AccountID      uint                      `json:"approvalOrderAccountID"`      // 数据集申请：为该账户申请，0 表示为本人申请
+	ExpiresAt      *time.Time                `json:"approvalOrderExpiresAt"`      // 数据集申请：授权到期时间，为空表示永久
 	ReviewerID     uint                      `json:"reviewerID"`                  // 审批人ID
 	ReviewNotes    string                    `json:"reviewNotes"`                 // 审批备注
 }
@@ -442,6 +475,8 @@ type UpdateApprovalOrder struct {
 type ReviewApprovalOrderReq struct {
 	Status      model.ApprovalOrderStatus `json:"status" binding:"required"`
 	ReviewNotes string                    `json:"reviewNotes"`
+	// ExpiresAt 批准数据集访问申请时覆盖申请的授权到期时间
+	ExpiresAt *time.Time `json:"expiresAt"`
 }
 
 type ApprovalOrderIDReq struct {
@@ -510,6 +545,22 @@ func (mgr *ApprovalOrderMgr) UpdateApprovalOrder(c *gin.Context) {
 		return
 	}
 
+	// 数据集访问申请的审批人由数据集决定，不允许更换申请对象
+	if existingOrder.Type == model.ApprovalOrderTypeDataset || req.Type == model.ApprovalOrderTypeDataset {
+		content := existingOrder.Content.Data()
+		if req.Type != existingOrder.Type || req.TypeID != content.ApprovalOrderTypeID ||
+			req.AccountID != content.ApprovalOrderAccountID {
+			resputil.HandleError(c, bizerr.BadRequest.ParameterError.New(
+				"the dataset and queue of a dataset access request cannot be changed"))
+			return
+		}
+		if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+			resputil.HandleError(c, bizerr.BadRequest.ParameterError.New("the expiry must be in the future"))
+			return
+		}
+		req.Name = existingOrder.Name
+	}
+
 	// 2. 更新审批工单
 	order := model.ApprovalOrder{
 		Name:   req.Name,
@@ -519,6 +570,8 @@ func (mgr *ApprovalOrderMgr) UpdateApprovalOrder(c *gin.Context) {
 			ApprovalOrderTypeID:         req.TypeID,
 			ApprovalOrderExtensionHours: req.ExtensionHours,
 			ApprovalOrderReason:         req.Reason,
+			ApprovalOrderAccountID:      req.AccountID,
+			ApprovalOrderExpiresAt:      req.ExpiresAt,
 		}),
 	}
 
@@ -596,22 +649,19 @@ func (mgr *ApprovalOrderMgr) ReviewApprovalOrderAdmin(c *gin.Context) {
 		return
 	}
 
-	info, err := ao.WithContext(c).
-		Where(ao.ID.Eq(orderID.ID)).
-		Updates(map[string]any{
-			"status":       req.Status,
-			"reviewer_id":  token.UserID,
-			"review_notes": req.ReviewNotes,
-		})
-	if err != nil {
+	if err := reviewApprovalOrder(c, existingOrder, token.UserID, &req); err != nil {
 		klog.Errorf("failed to review approval order, reviewerID: %d, orderID: %d, err: %v",
 			token.UserID, orderID.ID, err)
-		resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "failed to review approval order"))
+		resputil.HandleError(c, err)
 		return
 	}
 
-	klog.Infof("reviewed approval order successfully, reviewerID: %d, orderID: %d, affected rows: %d",
-		token.UserID, orderID.ID, info.RowsAffected)
+	klog.Infof("reviewed approval order successfully, reviewerID: %d, orderID: %d", token.UserID, orderID.ID)
+
+	existingOrder.Status = req.Status
+	existingOrder.ReviewNotes = req.ReviewNotes
+	// webhook 渠道可能较慢，不阻塞审核请求
+	go mgr.notificationService.NotifyApprovalOrderReviewed(context.WithoutCancel(c.Request.Context()), existingOrder)
 	resputil.Success(c, "review approvalorder successfully")
 }
 
@@ -692,7 +742,7 @@ func (mgr *ApprovalOrderMgr) DeleteApprovalOrder(c *gin.Context) {
 // swagger
 //
 //	@Summary		获取审批工单详情
-//	@Description	通过ID获取审批工单详情（仅限创建者）
+//	@Description	通过ID获取审批工单详情（仅限创建者和指定审批人）
 //	@Tags			approvalorder
 //	@Accept			json
 //	@Produce		json
@@ -733,8 +783,8 @@ func (mgr *ApprovalOrderMgr) GetApprovalOrder(c *gin.Context) {
 		return
 	}
 
-	// 4. 权限检查：只有创建者才能查看自己的工单
-	if order.CreatorID != token.UserID {
+	// 4. 权限检查：只有创建者和指定审批人才能查看工单
+	if order.CreatorID != token.UserID && order.AssigneeID != token.UserID {
 		klog.Warningf("user attempted to view an order not created by themselves, userID: %d, orderID: %d, creatorID: %d",
 			token.UserID, orderID.ID, order.CreatorID)
 		resputil.Error(c, "permission denied to view this approval order", resputil.NotSpecified)
@@ -870,6 +920,7 @@ func convertToApprovalOrderResp(order *model.ApprovalOrder) ApprovalOrderResp {
 			Nickname: order.Creator.Nickname,
 		},
 		ReviewerID: order.ReviewerID,
+		AssigneeID: order.AssigneeID,
 	}
 
 	// 处理审批人信息
@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"k8s.io/klog/v2"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/bizerr"
+	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/internal/util"
+)
+
+// Dataset access requests are approval orders of type dataset. They are assigned to the dataset
+// owner, who can review them next to the platform admins; approving one creates the UserDataset
+// or AccountDataset grant, optionally with an expiry that the revoke-expired-shares cronjob honors.
+
+// swagger
+//
+//	@Summary		获取指派给我审批的工单
+//	@Description	获取指派给当前用户审批的工单，如对其数据集的访问申请
+//	@Tags			approvalorder
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Success		200	{object}	resputil.Response[[]ApprovalOrderResp]	"成功返回工单列表"
+//	@Failure		500	{object}	resputil.Response[any]					"服务器错误"
+//	@Router			/v1/approvalorder/assigned [get]
+func (mgr *ApprovalOrderMgr) GetAssignedApprovalOrders(c *gin.Context) {
+	token := util.GetToken(c)
+	ao := query.ApprovalOrder
+	orders, err := ao.WithContext(c).
+		Preload(ao.Creator).
+		Preload(ao.Reviewer).
+		Where(ao.AssigneeID.Eq(token.UserID)).
+		Order(ao.CreatedAt.Desc()).
+		Find()
+	if err != nil {
+		resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "failed to list assigned approval orders"))
+		return
+	}
+	resputil.Success(c, convertToApprovalOrderResps(orders))
+}
+
+// swagger
+//
+//	@Summary		指定审批人审核审批工单
+//	@Description	工单的指定审批人（如数据集所有者）批准或拒绝工单；批准数据集访问申请时创建授权，可通过 expiresAt 修改到期时间
+//	@Tags			approvalorder
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			id		path		int							true	"工单ID"
+//	@Param			body	body		ReviewApprovalOrderReq		true	"审核信息"
+//	@Success		200		{object}	resputil.Response[string]	"成功返回值描述"
+//	@Failure		400		{object}	resputil.Response[any]		"请求参数错误"
+//	@Failure		403		{object}	resputil.Response[any]		"不是该工单的指定审批人"
+//	@Failure		409		{object}	resputil.Response[any]		"工单已被审核"
+//	@Router			/v1/approvalorder/{id}/review [put]
+func (mgr *ApprovalOrderMgr) ReviewAssignedApprovalOrder(c *gin.Context) {
+	var req ReviewApprovalOrderReq
+	var orderID ApprovalOrderIDReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.ParameterError.Wrap(err, "invalid request parameters"))
+		return
+	}
+	if err := c.ShouldBindUri(&orderID); err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.ParameterError.Wrap(err, "invalid request parameters"))
+		return
+	}
+	if req.Status != model.ApprovalOrderStatusApproved && req.Status != model.ApprovalOrderStatusRejected {
+		resputil.HandleError(c, bizerr.BadRequest.ParameterError.New("invalid review status"))
+		return
+	}
+	if req.Status == model.ApprovalOrderStatusRejected && req.ReviewNotes == "" {
+		resputil.HandleError(c, bizerr.BadRequest.MissingParameter.New("review notes are required when rejecting an order"))
+		return
+	}
+
+	token := util.GetToken(c)
+	ao := query.ApprovalOrder
+	order, err := ao.WithContext(c).Where(ao.ID.Eq(orderID.ID)).First()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		resputil.HandleError(c, bizerr.NotFound.DataBaseNotFound.New("approval order not found"))
+		return
+	} else if err != nil {
+		resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "failed to get approval order"))
+		return
+	}
+	if order.AssigneeID == 0 || order.AssigneeID != token.UserID {
+		resputil.HandleError(c, bizerr.Forbidden.PermissionDenied.New("this approval order is not assigned to you"))
+		return
+	}
+	if err = reviewApprovalOrder(c, order, token.UserID, &req); err != nil {
+		resputil.HandleError(c, err)
+		return
+	}
+	klog.Infof("assignee reviewed approval order, reviewerID: %d, orderID: %d, status: %s", token.UserID, order.ID, req.Status)
+
+	order.Status = req.Status
+	order.ReviewNotes = req.ReviewNotes
+	// webhook 渠道可能较慢，不阻塞审核请求
+	go mgr.notificationService.NotifyApprovalOrderReviewed(context.WithoutCancel(c.Request.Context()), order)
+	resputil.Success(c, "review approvalorder successfully")
+}
+
+// prepareDatasetAccessOrder validates a dataset access request and returns the requested dataset.
+// Datasets the requester cannot see are reported as missing, so requests cannot probe for them.
+func prepareDatasetAccessOrder(c *gin.Context, token util.JWTMessage, req *ApprovalOrderreq) (*model.Dataset, error) {
+	userID := token.UserID
+	if req.TypeID == 0 {
+		return nil, bizerr.BadRequest.MissingParameter.New("approvalorderTypeID must be the requested dataset")
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, bizerr.BadRequest.ParameterError.New("the expiry must be in the future")
+	}
+	notFound := bizerr.NotFound.DataBaseNotFound.New(fmt.Sprintf("dataset %d does not exist", req.TypeID))
+	d := query.Dataset
+	dataset, err := d.WithContext(c).Where(d.ID.Eq(req.TypeID)).First()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, notFound
+	} else if err != nil {
+		return nil, bizerr.Internal.DatabaseError.Wrap(err, "failed to get dataset")
+	}
+	if dataset.UserID == userID {
+		return nil, bizerr.Conflict.ResourceAlreadyExists.New("you own this dataset")
+	}
+	if token.RolePlatform != model.RoleAdmin {
+		visible, err := datasetVisibleTo(c, query.Q, userID, dataset.ID)
+		if err != nil {
+			return nil, bizerr.Internal.DatabaseError.Wrap(err, "failed to check dataset visibility")
+		}
+		if !visible {
+			return nil, notFound
+		}
+	}
+
+	if req.AccountID != 0 {
+		ua := query.UserAccount
+		if _, err = ua.WithContext(c).Where(ua.UserID.Eq(userID), ua.AccountID.Eq(req.AccountID)).First(); err != nil {
+			return nil, bizerr.Forbidden.PermissionDenied.New(fmt.Sprintf("you are not a member of account %d", req.AccountID))
+		}
+		ad := query.AccountDataset
+		if _, err = ad.WithContext(c).Where(ad.AccountID.Eq(req.AccountID), ad.DatasetID.Eq(dataset.ID), util.NotExpired(ad.ExpiresAt)).First(); err == nil {
+			return nil, bizerr.Conflict.ResourceAlreadyExists.New("the dataset is already shared with this account")
+		}
+	} else if _, _, err = util.GetSubPathByDatasetVolume(c, userID, dataset.ID); err == nil {
+		return nil, bizerr.Conflict.ResourceAlreadyExists.New("you can already mount this dataset")
+	}
+
+	ao := query.ApprovalOrder
+	pending, err := ao.WithContext(c).Where(
+		ao.CreatorID.Eq(userID),
+		ao.Type.Eq(string(model.ApprovalOrderTypeDataset)),
+		ao.Status.Eq(string(model.ApprovalOrderStatusPending)),
+	).Find()
+	if err != nil {
+		return nil, bizerr.Internal.DatabaseError.Wrap(err, "failed to check pending requests")
+	}
+	for _, order := range pending {
+		content := order.Content.Data()
+		if content.ApprovalOrderTypeID == dataset.ID && content.ApprovalOrderAccountID == req.AccountID {
+			return nil, bizerr.Conflict.ResourceAlreadyExists.New(
+				fmt.Sprintf("approval order %d already requests this dataset", order.ID))
+		}
+	}
+	return dataset, nil
+}
+
+// datasetVisibleTo reports whether the user holds a grant of the dataset, directly or through one
+// of their accounts. Expired grants count, so their holders can ask for a renewal.
+func datasetVisibleTo(ctx context.Context, q *query.Query, userID, datasetID uint) (bool, error) {
+	ud := q.UserDataset
+	direct, err := ud.WithContext(ctx).Where(ud.UserID.Eq(userID), ud.DatasetID.Eq(datasetID)).Count()
+	if err != nil || direct > 0 {
+		return direct > 0, err
+	}
+	ua := q.UserAccount
+	var accountIDs []uint
+	if err = ua.WithContext(ctx).Where(ua.UserID.Eq(userID)).Pluck(ua.AccountID, &accountIDs); err != nil {
+		return false, err
+	}
+	if len(accountIDs) == 0 {
+		return false, nil
+	}
+	ad := q.AccountDataset
+	shared, err := ad.WithContext(ctx).Where(ad.DatasetID.Eq(datasetID), ad.AccountID.In(accountIDs...)).Count()
+	return shared > 0, err
+}
+
+// reviewApprovalOrder records a review. The status only moves away from Pending once, so two
+// reviewers cannot both approve; an approved dataset access request is granted in the same
+// transaction.
+func reviewApprovalOrder(
+	ctx context.Context, order *model.ApprovalOrder, reviewerID uint, req *ReviewApprovalOrderReq,
+) error {
+	content := order.Content.Data()
+	grant := order.Type == model.ApprovalOrderTypeDataset && req.Status == model.ApprovalOrderStatusApproved
+	if grant && req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(time.Now()) {
+			return bizerr.BadRequest.ParameterError.New("the expiry must be in the future")
+		}
+		content.ApprovalOrderExpiresAt = req.ExpiresAt
+	}
+	return query.Q.Transaction(func(tx *query.Query) error {
+		ao := tx.ApprovalOrder
+		info, err := ao.WithContext(ctx).
+			Where(ao.ID.Eq(order.ID), ao.Status.Eq(string(model.ApprovalOrderStatusPending))).
+			Updates(map[string]any{
+				"status":       req.Status,
+				"reviewer_id":  reviewerID,
+				"review_notes": req.ReviewNotes,
+				"content":      datatypes.NewJSONType(content),
+			})
+		if err != nil {
+			return bizerr.Internal.DatabaseError.Wrap(err, "failed to review approval order")
+		}
+		if info.RowsAffected == 0 {
+			return bizerr.Conflict.ResourceStatusError.New("only pending approval orders can be reviewed")
+		}
+		if !grant {
+			return nil
+		}
+		if err = grantDatasetAccess(ctx, tx, content.ApprovalOrderTypeID, order.CreatorID,
+			content.ApprovalOrderAccountID, content.ApprovalOrderExpiresAt); err != nil {
+			return bizerr.Internal.DatabaseError.Wrap(err, "failed to grant dataset access")
+		}
+		order.Content = datatypes.NewJSONType(content)
+		return nil
+	})
+}
+
+// grantDatasetAccess creates the grant of an approved request. An existing time-limited grant
+// takes the new expiry; an existing permanent one is left alone.
+func grantDatasetAccess(ctx context.Context, tx *query.Query, datasetID, userID, accountID uint, expiresAt *time.Time) error {
+	if accountID != 0 {
+		ad := tx.AccountDataset
+		existing, err := ad.WithContext(ctx).Where(ad.AccountID.Eq(accountID), ad.DatasetID.Eq(datasetID)).First()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ad.WithContext(ctx).Create(&model.AccountDataset{AccountID: accountID, DatasetID: datasetID, ExpiresAt: expiresAt})
+		} else if err != nil {
+			return err
+		}
+		if existing.ExpiresAt != nil {
+			_, err = ad.WithContext(ctx).Where(ad.ID.Eq(existing.ID)).Update(ad.ExpiresAt, expiresAt)
+		}
+		return err
+	}
+	ud := tx.UserDataset
+	existing, err := ud.WithContext(ctx).Where(ud.UserID.Eq(userID), ud.DatasetID.Eq(datasetID)).First()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ud.WithContext(ctx).Create(&model.UserDataset{UserID: userID, DatasetID: datasetID, ExpiresAt: expiresAt})
+	} else if err != nil {
+		return err
+	}
+	if existing.ExpiresAt != nil {
+		_, err = ud.WithContext(ctx).Where(ud.ID.Eq(existing.ID)).Update(ud.ExpiresAt, expiresAt)
+	}
+	return err
+}
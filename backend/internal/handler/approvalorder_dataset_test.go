@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+)
+
+func TestDatasetVisibleTo(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:dataset_visible_to?mode=memory&cache=shared"), &gorm.Config{
+		DisableForeignKeyConstraintWhenMigrating: true,
+		IgnoreRelationshipsWhenMigrating:         true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = db.AutoMigrate(&model.UserDataset{}, &model.UserAccount{}, &model.AccountDataset{}); err != nil {
+		t.Fatal(err)
+	}
+	expired := time.Now().Add(-time.Hour)
+	for _, row := range []any{
+		&model.UserDataset{UserID: 1, DatasetID: 10, ExpiresAt: &expired},
+		&model.UserAccount{UserID: 2, AccountID: 5},
+		&model.AccountDataset{AccountID: 5, DatasetID: 11},
+		&model.AccountDataset{AccountID: 6, DatasetID: 12},
+	} {
+		if err = db.Create(row).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	q := query.Use(db)
+
+	for _, tt := range []struct {
+		userID, datasetID uint
+		want              bool
+	}{
+		{userID: 1, datasetID: 10, want: true},
+		{userID: 2, datasetID: 11, want: true},
+		{userID: 2, datasetID: 12, want: false},
+		{userID: 3, datasetID: 10, want: false},
+	} {
+		got, err := datasetVisibleTo(t.Context(), q, tt.userID, tt.datasetID)
+		if err != nil || got != tt.want {
+			t.Errorf("datasetVisibleTo(user %d, dataset %d) = %v, %v; want %v", tt.userID, tt.datasetID, got, err, tt.want)
+		}
+	}
+}
@@ -253,7 +253,7 @@ func (mgr *AuthMgr) Check(c *gin.Context) {
 			AccessPublic: publicAccessMode,
 			Space:        user.Space,
 		},
-		User:    user.Attributes.Data(),
+		User:    user.Attributes.Data().Redacted(),
 		Version: GetVersionInfo(),
 	}
 
@@ -418,7 +418,7 @@ func (mgr *AuthMgr) Login(c *gin.Context) {
 		return
 	}
 	// Ensure ID and Name are populated in the response attributes
-	respAttr := user.Attributes.Data()
+	respAttr := user.Attributes.Data().Redacted()
 	respAttr.ID = user.ID
 	respAttr.Name = user.Name
 
@@ -1001,7 +1001,7 @@ func (mgr *AuthMgr) Signup(c *gin.Context) {
 		return
 	}
 	// Ensure ID and Name are populated in the response attributes
-	respAttr := user.Attributes.Data()
+	respAttr := user.Attributes.Data().Redacted()
 	respAttr.ID = user.ID
 	respAttr.Name = user.Name
 
@@ -1144,7 +1144,7 @@ func (mgr *AuthMgr) SwitchQueue(c *gin.Context) {
 	}
 
 	// Ensure ID and Name are populated in the response attributes
-	respAttr := user.Attributes.Data()
+	respAttr := user.Attributes.Data().Redacted()
 	respAttr.ID = user.ID
 	respAttr.Name = user.Name
 
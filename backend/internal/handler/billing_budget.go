@@ -0,0 +1,328 @@
+package handler
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/datatypes"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/bizerr"
+	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/internal/service"
+	"github.com/raids-lab/crater/pkg/constants"
+)
+
+// BillingBudgetReq creates or replaces a budget. UserID 0 is the account-wide budget,
+// an empty AlertPercents uses 50/80/100 and a missing HardCap disables the cap.
+type BillingBudgetReq struct {
+	UserID        uint                         `json:"userId"`
+	AlertPercents []int                        `json:"alertPercents"`
+	HardCap       *service.BillingAmountInput  `json:"hardCap"`
+	CapAction     model.BillingBudgetCapAction `json:"capAction"`
+}
+
+type BillingBudgetUserReq struct {
+	AccountID uint `uri:"aid" binding:"required"`
+	UserID    uint `uri:"uid"`
+}
+
+type BillingBudgetResp struct {
+	AccountID     uint                         `json:"accountId"`
+	UserID        uint                         `json:"userId"`
+	Username      string                       `json:"username,omitempty"`
+	AlertPercents []int                        `json:"alertPercents"`
+	HardCap       *float64                     `json:"hardCap"`
+	CapAction     model.BillingBudgetCapAction `json:"capAction"`
+	PeriodStart   time.Time                    `json:"periodStart"`
+	IssueAmount   float64                      `json:"issueAmount"`
+	Spent         float64                      `json:"spent"`
+	Projected     float64                      `json:"projected"`
+	CapReached    bool                         `json:"capReached"`
+}
+
+// AdminListAccountBudgets godoc
+//
+//	@Summary		List account budgets
+//	@Description	List the budgets of an account with the spend of the current issue period
+//	@Tags			Project
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			aid	path		uint									true	"account id"
+//	@Success		200	{object}	resputil.Response[[]BillingBudgetResp]	"budgets"
+//	@Failure		409	{object}	resputil.Response[any]					"billing disabled"
+//	@Router			/v1/admin/accounts/{aid}/billing/budgets [get]
+func (mgr *AccountMgr) AdminListAccountBudgets(c *gin.Context) {
+	accountID, ok := mgr.bindAccountID(c)
+	if !ok {
+		return
+	}
+	mgr.writeAccountBudgets(c, accountID)
+}
+
+// UserListAccountBudgets godoc
+//
+//	@Summary		List account budgets
+//	@Description	Same as the admin endpoint, for account administrators
+//	@Tags			Project
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			aid	path		uint									true	"account id"
+//	@Success		200	{object}	resputil.Response[[]BillingBudgetResp]	"budgets"
+//	@Failure		403	{object}	resputil.Response[any]					"not an account administrator"
+//	@Router			/v1/accounts/{aid}/billing/budgets [get]
+func (mgr *AccountMgr) UserListAccountBudgets(c *gin.Context) {
+	if err := mgr.requireUserFacingBillingEnabled(c.Request.Context()); err != nil {
+		resputil.HandleError(c, err)
+		return
+	}
+	accountID, ok := mgr.requireUserManagedAccountID(c)
+	if !ok {
+		return
+	}
+	mgr.writeAccountBudgets(c, accountID)
+}
+
+// AdminUpsertAccountBudget godoc
+//
+//	@Summary		Create or replace an account budget
+//	@Description	Soft alert thresholds are percents of the period issue amount; the hard cap stops or suspends running normal jobs
+//	@Tags			Project
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			aid		path		uint								true	"account id"
+//	@Param			data	body		BillingBudgetReq					true	"budget"
+//	@Success		200		{object}	resputil.Response[BillingBudgetResp]	"saved budget"
+//	@Failure		400		{object}	resputil.Response[any]				"invalid budget"
+//	@Router			/v1/admin/accounts/{aid}/billing/budgets [put]
+func (mgr *AccountMgr) AdminUpsertAccountBudget(c *gin.Context) {
+	accountID, ok := mgr.bindAccountID(c)
+	if !ok {
+		return
+	}
+	mgr.upsertAccountBudget(c, accountID)
+}
+
+// UserUpsertAccountBudget godoc
+//
+//	@Summary		Create or replace an account budget
+//	@Description	Same as the admin endpoint, for account administrators
+//	@Tags			Project
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			aid		path		uint								true	"account id"
+//	@Param			data	body		BillingBudgetReq					true	"budget"
+//	@Success		200		{object}	resputil.Response[BillingBudgetResp]	"saved budget"
+//	@Failure		403		{object}	resputil.Response[any]				"not an account administrator"
+//	@Router			/v1/accounts/{aid}/billing/budgets [put]
+func (mgr *AccountMgr) UserUpsertAccountBudget(c *gin.Context) {
+	if err := mgr.requireUserFacingBillingEnabled(c.Request.Context()); err != nil {
+		resputil.HandleError(c, err)
+		return
+	}
+	accountID, ok := mgr.requireUserManagedAccountID(c)
+	if !ok {
+		return
+	}
+	mgr.upsertAccountBudget(c, accountID)
+}
+
+// AdminDeleteAccountBudget godoc
+//
+//	@Summary		Delete an account budget
+//	@Description	uid 0 deletes the account-wide budget
+//	@Tags			Project
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			aid	path		uint					true	"account id"
+//	@Param			uid	path		uint					true	"user id, 0 for the account"
+//	@Success		200	{object}	resputil.Response[any]	"deleted"
+//	@Failure		404	{object}	resputil.Response[any]	"budget not found"
+//	@Router			/v1/admin/accounts/{aid}/billing/budgets/{uid} [delete]
+func (mgr *AccountMgr) AdminDeleteAccountBudget(c *gin.Context) {
+	var uriReq BillingBudgetUserReq
+	if err := c.ShouldBindUri(&uriReq); err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.InvalidRequest.Wrap(err, "invalid uri params"))
+		return
+	}
+	mgr.deleteAccountBudget(c, uriReq)
+}
+
+// UserDeleteAccountBudget godoc
+//
+//	@Summary		Delete an account budget
+//	@Description	Same as the admin endpoint, for account administrators
+//	@Tags			Project
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			aid	path		uint					true	"account id"
+//	@Param			uid	path		uint					true	"user id, 0 for the account"
+//	@Success		200	{object}	resputil.Response[any]	"deleted"
+//	@Failure		403	{object}	resputil.Response[any]	"not an account administrator"
+//	@Router			/v1/accounts/{aid}/billing/budgets/{uid} [delete]
+func (mgr *AccountMgr) UserDeleteAccountBudget(c *gin.Context) {
+	if err := mgr.requireUserFacingBillingEnabled(c.Request.Context()); err != nil {
+		resputil.HandleError(c, err)
+		return
+	}
+	var uriReq BillingBudgetUserReq
+	if err := c.ShouldBindUri(&uriReq); err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.InvalidRequest.Wrap(err, "invalid uri params"))
+		return
+	}
+	if _, ok := mgr.requireUserManagedAccountID(c); !ok {
+		return
+	}
+	mgr.deleteAccountBudget(c, uriReq)
+}
+
+func (mgr *AccountMgr) writeAccountBudgets(c *gin.Context, accountID uint) {
+	if err := mgr.requireBillingEnabled(c.Request.Context()); err != nil {
+		resputil.HandleError(c, err)
+		return
+	}
+	if _, err := mgr.validateAccount(c, accountID); err != nil {
+		resputil.HandleError(c, err)
+		return
+	}
+	statuses, err := mgr.billingService.ListBudgets(c.Request.Context(), accountID)
+	if err != nil {
+		resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "failed to load budgets"))
+		return
+	}
+	resp, err := toBillingBudgetResps(c, statuses)
+	if err != nil {
+		resputil.HandleError(c, err)
+		return
+	}
+	resputil.Success(c, resp)
+}
+
+func (mgr *AccountMgr) upsertAccountBudget(c *gin.Context, accountID uint) {
+	var req BillingBudgetReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.ParameterError.Wrap(err, "invalid request body"))
+		return
+	}
+	if err := mgr.requireBillingEnabled(c.Request.Context()); err != nil {
+		resputil.HandleError(c, err)
+		return
+	}
+	if _, err := mgr.validateAccount(c, accountID); err != nil {
+		resputil.HandleError(c, err)
+		return
+	}
+	budget := &model.BillingBudget{
+		AccountID: accountID,
+		UserID:    req.UserID,
+		CapAction: req.CapAction,
+	}
+	if len(req.AlertPercents) > 0 {
+		budget.AlertPercents = datatypes.NewJSONType(req.AlertPercents)
+	}
+	if req.HardCap != nil {
+		hardCap := req.HardCap.MicroPoints()
+		budget.HardCap = &hardCap
+	}
+	saved, err := mgr.billingService.UpsertBudget(c.Request.Context(), budget)
+	if err != nil {
+		RecordOperationLog(c, constants.OpTypeUpdateBillingBudget, budgetTarget(accountID, req.UserID),
+			constants.OpStatusFailed, err.Error(), nil)
+		resputil.HandleError(c, err)
+		return
+	}
+	RecordOperationLog(c, constants.OpTypeUpdateBillingBudget, budgetTarget(accountID, req.UserID),
+		constants.OpStatusSuccess, "", map[string]any{
+			"alertPercents": saved.AlertPercents.Data(),
+			"hardCap":       saved.HardCap,
+			"capAction":     saved.CapAction,
+		})
+	statuses, err := mgr.billingService.ListBudgets(c.Request.Context(), accountID)
+	if err != nil {
+		resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "failed to load budgets"))
+		return
+	}
+	statuses = slices.DeleteFunc(statuses, func(status *service.BillingBudgetStatus) bool {
+		return status.Budget.ID != saved.ID
+	})
+	resp, err := toBillingBudgetResps(c, statuses)
+	if err != nil {
+		resputil.HandleError(c, err)
+		return
+	}
+	if len(resp) == 0 {
+		resputil.HandleError(c, bizerr.NotFound.DataBaseNotFound.New("saved budget not found"))
+		return
+	}
+	resputil.Success(c, resp[0])
+}
+
+func (mgr *AccountMgr) deleteAccountBudget(c *gin.Context, uriReq BillingBudgetUserReq) {
+	if err := mgr.requireBillingEnabled(c.Request.Context()); err != nil {
+		resputil.HandleError(c, err)
+		return
+	}
+	target := budgetTarget(uriReq.AccountID, uriReq.UserID)
+	if err := mgr.billingService.DeleteBudget(c.Request.Context(), uriReq.AccountID, uriReq.UserID); err != nil {
+		RecordOperationLog(c, constants.OpTypeDeleteBillingBudget, target, constants.OpStatusFailed, err.Error(), nil)
+		resputil.HandleError(c, err)
+		return
+	}
+	RecordOperationLog(c, constants.OpTypeDeleteBillingBudget, target, constants.OpStatusSuccess, "", nil)
+	resputil.Success(c, nil)
+}
+
+func budgetTarget(accountID, userID uint) string {
+	return fmt.Sprintf("account/%d/budget/%d", accountID, userID)
+}
+
+func toBillingBudgetResps(c *gin.Context, statuses []*service.BillingBudgetStatus) ([]BillingBudgetResp, error) {
+	userIDs := make([]uint, 0, len(statuses))
+	for _, status := range statuses {
+		if status.Budget.UserID != 0 {
+			userIDs = append(userIDs, status.Budget.UserID)
+		}
+	}
+	usernames := map[uint]string{}
+	if len(userIDs) > 0 {
+		u := query.User
+		users, err := u.WithContext(c).Unscoped().Where(u.ID.In(userIDs...)).Find()
+		if err != nil {
+			return nil, bizerr.Internal.DatabaseError.Wrap(err, "failed to load budget users")
+		}
+		for _, user := range users {
+			usernames[user.ID] = user.Name
+		}
+	}
+	resp := make([]BillingBudgetResp, 0, len(statuses))
+	for _, status := range statuses {
+		resp = append(resp, toBillingBudgetResp(status, usernames[status.Budget.UserID]))
+	}
+	return resp, nil
+}
+
+func toBillingBudgetResp(status *service.BillingBudgetStatus, username string) BillingBudgetResp {
+	budget := status.Budget
+	resp := BillingBudgetResp{
+		AccountID:     budget.AccountID,
+		UserID:        budget.UserID,
+		Username:      username,
+		AlertPercents: budget.AlertPercents.Data(),
+		CapAction:     budget.CapAction,
+		PeriodStart:   status.PeriodStart,
+		IssueAmount:   service.ToDisplayPoints(status.IssueAmount),
+		Spent:         service.ToDisplayPoints(status.Spent),
+		Projected:     service.ToDisplayPoints(status.Projected),
+		CapReached:    status.CapReached(),
+	}
+	if budget.HardCap != nil {
+		hardCap := service.ToDisplayPoints(*budget.HardCap)
+		resp.HardCap = &hardCap
+	}
+	return resp
+}
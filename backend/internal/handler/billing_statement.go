@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/raids-lab/crater/internal/bizerr"
+	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/internal/service"
+)
+
+const (
+	billingStatementFormatJSON = "json"
+	billingStatementFormatCSV  = "csv"
+	billingStatementDateLayout = "2006-01-02"
+)
+
+// BillingStatementReq selects the statement period. Dates are either YYYY-MM-DD,
+// where `to` includes the whole day, or RFC3339 timestamps. The period defaults to
+// the current month so far.
+type BillingStatementReq struct {
+	From      string `form:"from"`
+	To        string `form:"to"`
+	AccountID uint   `form:"accountId"`
+	Format    string `form:"format"`
+}
+
+type BillingStatementEntryResp struct {
+	Time        time.Time                         `json:"time"`
+	Type        service.BillingStatementEntryType `json:"type"`
+	UserID      uint                              `json:"userId"`
+	Username    string                            `json:"username"`
+	AccountID   uint                              `json:"accountId,omitempty"`
+	AccountName string                            `json:"accountName,omitempty"`
+	JobName     string                            `json:"jobName,omitempty"`
+	Amount      float64                           `json:"amount"`
+	Description string                            `json:"description,omitempty"`
+}
+
+type BillingStatementResp struct {
+	UserID    uint                        `json:"userId,omitempty"`
+	AccountID uint                        `json:"accountId,omitempty"`
+	From      time.Time                   `json:"from"`
+	To        time.Time                   `json:"to"`
+	Settled   float64                     `json:"settled"`
+	Issued    float64                     `json:"issued"`
+	Adjusted  float64                     `json:"adjusted"`
+	Net       float64                     `json:"net"`
+	Entries   []BillingStatementEntryResp `json:"entries"`
+}
+
+func parseBillingStatementTime(raw string, endOfDay bool) (time.Time, error) {
+	if t, err := time.ParseInLocation(billingStatementDateLayout, raw, time.Local); err == nil {
+		if endOfDay {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, raw)
+}
+
+// resolveBillingStatementQuery validates the request and fills in the statement period.
+func resolveBillingStatementQuery(req *BillingStatementReq, now time.Time) (service.BillingStatementQuery, error) {
+	q := service.BillingStatementQuery{AccountID: req.AccountID, To: now}
+	req.Format = strings.ToLower(strings.TrimSpace(req.Format))
+	switch req.Format {
+	case "":
+		req.Format = billingStatementFormatJSON
+	case billingStatementFormatJSON, billingStatementFormatCSV:
+	default:
+		return q, bizerr.BadRequest.ParameterError.New(fmt.Sprintf("unsupported statement format %q", req.Format))
+	}
+	if req.To != "" {
+		to, err := parseBillingStatementTime(req.To, true)
+		if err != nil {
+			return q, bizerr.BadRequest.ParameterError.Wrap(err, "invalid statement end")
+		}
+		q.To = to
+	}
+	q.From = time.Date(q.To.Year(), q.To.Month(), 1, 0, 0, 0, 0, q.To.Location())
+	if req.From != "" {
+		from, err := parseBillingStatementTime(req.From, false)
+		if err != nil {
+			return q, bizerr.BadRequest.ParameterError.Wrap(err, "invalid statement start")
+		}
+		q.From = from
+	}
+	return q, nil
+}
+
+// writeBillingStatement generates the statement and renders it as JSON or as a CSV attachment.
+func writeBillingStatement(
+	c *gin.Context,
+	billingService *service.BillingService,
+	format string,
+	q service.BillingStatementQuery,
+) {
+	st, err := billingService.GenerateStatement(c.Request.Context(), q)
+	if err != nil {
+		resputil.HandleError(c, err)
+		return
+	}
+	if format == billingStatementFormatCSV {
+		var sb strings.Builder
+		if err := st.WriteCSV(&sb); err != nil {
+			resputil.HandleError(c, bizerr.Internal.ServiceError.Wrap(err, "failed to render statement"))
+			return
+		}
+		filename := fmt.Sprintf("statement-%s-%s.csv", st.From.Format(billingStatementDateLayout), st.To.Format(billingStatementDateLayout))
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		c.Data(http.StatusOK, "text/csv; charset=utf-8", []byte(sb.String()))
+		return
+	}
+	resputil.Success(c, toBillingStatementResp(st))
+}
+
+func toBillingStatementResp(st *service.BillingStatement) BillingStatementResp {
+	resp := BillingStatementResp{
+		UserID:    st.UserID,
+		AccountID: st.AccountID,
+		From:      st.From,
+		To:        st.To,
+		Settled:   service.ToDisplayPoints(st.Settled),
+		Issued:    service.ToDisplayPoints(st.Issued),
+		Adjusted:  service.ToDisplayPoints(st.Adjusted),
+		Net:       service.ToDisplayPoints(st.Net()),
+		Entries:   make([]BillingStatementEntryResp, 0, len(st.Entries)),
+	}
+	for i := range st.Entries {
+		entry := &st.Entries[i]
+		resp.Entries = append(resp.Entries, BillingStatementEntryResp{
+			Time:        entry.Time,
+			Type:        entry.Type,
+			UserID:      entry.UserID,
+			Username:    entry.Username,
+			AccountID:   entry.AccountID,
+			AccountName: entry.AccountName,
+			JobName:     entry.JobName,
+			Amount:      service.ToDisplayPoints(entry.Amount),
+			Description: entry.Description,
+		})
+	}
+	return resp
+}
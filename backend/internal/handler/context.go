@@ -17,6 +17,7 @@ import (
 
 	"github.com/raids-lab/crater/dao/model"
 	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/bizerr"
 	"github.com/raids-lab/crater/internal/resputil"
 	"github.com/raids-lab/crater/internal/service"
 	"github.com/raids-lab/crater/internal/util"
@@ -59,6 +60,7 @@ func (mgr *ContextMgr) RegisterProtected(g *gin.RouterGroup) {
 	g.GET("job-resource-summary", mgr.GetJobResourceSummary)
 	g.POST("resource-limit-check", mgr.CheckResourceLimit)
 	g.GET("billing/summary", mgr.GetBillingSummary)
+	g.GET("billing/statement", mgr.GetBillingStatement)
 	g.PUT("attributes", mgr.UpdateUserAttributes)
 	g.POST("email/code", mgr.SendUserVerificationCode)
 	g.POST("email/update", mgr.UpdateUserEmail)
@@ -367,6 +369,41 @@ func (mgr *ContextMgr) GetBillingSummary(c *gin.Context) {
 	resputil.Success(c, resp)
 }
 
+// GetBillingStatement godoc
+//
+//	@Summary		Export my billing statement
+//	@Description	Aggregate my job settlements, free balance issuance and extra balance adjustments over a period
+//	@Tags			Context
+//	@Produce		json
+//	@Produce		text/csv
+//	@Security		Bearer
+//	@Param			accountId	query		uint										false	"limit to one account"
+//	@Param			from		query		string										false	"start date (YYYY-MM-DD or RFC3339)"
+//	@Param			to			query		string										false	"end date, inclusive for YYYY-MM-DD"
+//	@Param			format		query		string										false	"json or csv"
+//	@Success		200			{object}	resputil.Response[BillingStatementResp]	"statement"
+//	@Failure		400			{object}	resputil.Response[any]						"invalid period"
+//	@Failure		409			{object}	resputil.Response[any]						"billing is disabled"
+//	@Router			/v1/context/billing/statement [get]
+func (mgr *ContextMgr) GetBillingStatement(c *gin.Context) {
+	if mgr.billingService == nil || !mgr.billingService.IsUserFacingEnabled(c.Request.Context()) {
+		resputil.HandleError(c, bizerr.Conflict.ResourceStatusError.New("billing feature is disabled"))
+		return
+	}
+	var req BillingStatementReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.InvalidRequest.Wrap(err, "invalid query parameters"))
+		return
+	}
+	q, err := resolveBillingStatementQuery(&req, time.Now())
+	if err != nil {
+		resputil.HandleError(c, err)
+		return
+	}
+	q.UserID = util.GetToken(c).UserID
+	writeBillingStatement(c, mgr.billingService, req.Format, q)
+}
+
 // UpdateUserAttributes godoc
 //
 //	@Summary		Update user attributes
@@ -401,6 +438,13 @@ func (mgr *ContextMgr) UpdateUserAttributes(c *gin.Context) {
 	attributes.ID = oldAttributes.ID
 	attributes.UID = oldAttributes.UID
 	attributes.GID = oldAttributes.GID
+	// Clients unaware of notification preferences omit the field; send an empty list to clear it
+	if attributes.Notification == nil {
+		attributes.Notification = oldAttributes.Notification
+	} else if err := alert.PrepareNotificationPreference(c, attributes.Notification, oldAttributes.Notification); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
 
 	user.Attributes = datatypes.NewJSONType(attributes)
 	if err := u.WithContext(c).Save(user); err != nil {
@@ -51,6 +51,9 @@ func (mgr *DatasetMgr) RegisterProtected(g *gin.RouterGroup) {
 	g.GET("/:datasetId/usersIn", mgr.ListUserOfDataset)
 	g.GET("/:datasetId/queuesNotIn", mgr.ListQueuesOutOfDataset)
 	g.GET("/:datasetId/queuesIn", mgr.ListQueueOfDataset)
+	g.GET("/:datasetId/versions", mgr.ListDatasetVersions)
+	g.GET("/:datasetId/versions/:version", mgr.GetDatasetVersion)
+	g.POST("/:datasetId/verify", mgr.VerifyDataset)
 	g.POST("/create", mgr.CreateDataset)
 	g.DELETE("/delete/:id", mgr.DeleteDataset)
 	g.POST("/share/user", mgr.ShareDatasetWithUser)
@@ -139,6 +142,7 @@ type DatasetResp struct {
 	SourceCreatedAt *time.Time                             `json:"sourceCreatedAt"`
 	Extra           datatypes.JSONType[model.ExtraContent] `json:"extra"`
 	UserInfo        model.UserInfo                         `json:"userInfo"`
+	Manifest        *DatasetManifestResp                   `json:"manifest,omitempty"`
 }
 
 // GetDatasets godoc
@@ -156,7 +160,7 @@ type DatasetResp struct {
 func (mgr *DatasetMgr) GetDatasets(c *gin.Context) {
 	token := util.GetToken(c)
 	ud := query.UserDataset
-	userDatasets, err := ud.WithContext(c).Where(ud.UserID.Eq(token.UserID)).Find()
+	userDatasets, err := ud.WithContext(c).Where(ud.UserID.Eq(token.UserID), util.NotExpired(ud.ExpiresAt)).Find()
 	if err != nil {
 		klog.Infof("Can't get , err: %v", err)
 		resputil.Error(c, "Can't get mydatasets", resputil.NotSpecified)
@@ -172,7 +176,7 @@ func (mgr *DatasetMgr) GetDatasets(c *gin.Context) {
 		accountIDs = append(accountIDs, token.AccountID)
 	}
 	qd := query.AccountDataset
-	accountDatasets, err := qd.WithContext(c).Where(qd.AccountID.In(accountIDs...)).Find()
+	accountDatasets, err := qd.WithContext(c).Where(qd.AccountID.In(accountIDs...), util.NotExpired(qd.ExpiresAt)).Find()
 	if err != nil {
 		resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "get account datasets failed"))
 		return
@@ -201,10 +205,11 @@ func (mgr *DatasetMgr) GetDatasets(c *gin.Context) {
 //	@Accept			json
 //	@Produce		json
 //	@Security		Bearer
-//	@Param			req	path		DatasetGetReq			true	"数据集ID"
-//	@Success		200	{object}	resputil.Response[any]	"成功返回值描述"
-//	@Failure		400	{object}	resputil.Response[any]	"Request parameter error"
-//	@Failure		500	{object}	resputil.Response[any]	"Other errors"
+//	@Param			req		path		DatasetGetReq			true	"数据集ID"
+//	@Param			query	query		DatasetDetailQuery		false	"是否返回逐文件清单"
+//	@Success		200		{object}	resputil.Response[any]	"成功返回值描述"
+//	@Failure		400		{object}	resputil.Response[any]	"Request parameter error"
+//	@Failure		500		{object}	resputil.Response[any]	"Other errors"
 //	@Router			/v1/dataset/detail/{datasetId} [get]
 func (mgr *DatasetMgr) GetDatasetByID(c *gin.Context) {
 	var req DatasetGetReq
@@ -212,6 +217,11 @@ func (mgr *DatasetMgr) GetDatasetByID(c *gin.Context) {
 		resputil.BadRequestError(c, fmt.Sprintf("get dataset failed, detail: %v", err))
 		return
 	}
+	var detailQuery DatasetDetailQuery
+	if err := c.ShouldBindQuery(&detailQuery); err != nil {
+		resputil.BadRequestError(c, fmt.Sprintf("get dataset failed, detail: %v", err))
+		return
+	}
 	d := query.Dataset
 	dataset, err := d.WithContext(c).Preload(d.User).Where(d.ID.Eq(req.DatasetID)).First()
 	if err != nil {
@@ -219,6 +229,10 @@ func (mgr *DatasetMgr) GetDatasetByID(c *gin.Context) {
 		return
 	}
 	res := convertDataset(c, dataset)
+	if res.Manifest, err = loadDatasetManifest(c, dataset.ID, detailQuery.Files); err != nil {
+		resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "failed to get dataset manifest"))
+		return
+	}
 	var result []DatasetResp
 	result = append(result, res)
 	resputil.Success(c, result)
@@ -369,6 +383,8 @@ func redirectDatasetURL(c *gin.Context, url string, token util.JWTMessage) (stri
 type SharedUserReq struct {
 	DatasetID uint   `json:"datasetID" binding:"required"`
 	UserIDs   []uint `json:"userIDs" binding:"required"`
+	// ExpiresAt ends the share at the given time; nil keeps it until it is canceled.
+	ExpiresAt *time.Time `json:"expiresAt"`
 }
 type cancelsharedUserReq struct {
 	DatasetID uint `json:"datasetID" binding:"required"`
@@ -446,19 +462,30 @@ func (mgr *DatasetMgr) shareWithUser(c *gin.Context, userReq SharedUserReq) erro
 	if len(userReq.UserIDs) == 0 {
 		return fmt.Errorf("need to choose users to share")
 	}
+	if userReq.ExpiresAt != nil && !userReq.ExpiresAt.After(time.Now()) {
+		return fmt.Errorf("the expiry must be in the future")
+	}
 	for _, uid := range userReq.UserIDs {
 		_, err := u.WithContext(c).Where(u.ID.Eq(uid)).First()
 		if err != nil {
 			return fmt.Errorf("user not exist")
 		}
 		ud := query.UserDataset
-		hud, _ := ud.WithContext(c).Where(ud.UserID.Eq(uid), ud.DatasetID.Eq(userReq.DatasetID)).First()
+		hud, _ := ud.WithContext(c).Where(ud.UserID.Eq(uid), ud.DatasetID.Eq(userReq.DatasetID), util.NotExpired(ud.ExpiresAt)).First()
 		if hud != nil {
 			return fmt.Errorf("user has shared dataset")
 		}
+		// an expired grant the cron job has not revoked yet is renewed in place
+		if expired, err := ud.WithContext(c).Where(ud.UserID.Eq(uid), ud.DatasetID.Eq(userReq.DatasetID)).First(); err == nil {
+			if _, err = ud.WithContext(c).Where(ud.ID.Eq(expired.ID)).Update(ud.ExpiresAt, userReq.ExpiresAt); err != nil {
+				return err
+			}
+			continue
+		}
 		userDataset := model.UserDataset{
 			UserID:    uid,
 			DatasetID: userReq.DatasetID,
+			ExpiresAt: userReq.ExpiresAt,
 		}
 		if err := ud.WithContext(c).Create(&userDataset); err != nil {
 			return err
@@ -543,7 +570,7 @@ func (mgr *DatasetMgr) cancelShareWithUser(c *gin.Context, userReq cancelsharedU
 	if uud == nil {
 		return fmt.Errorf("user doesn't shared dataset")
 	}
-	if _, err := ud.WithContext(c).Delete(uud); err != nil {
+	if _, err := ud.WithContext(c).Where(ud.UserID.Eq(userReq.UserID), ud.DatasetID.Eq(userReq.DatasetID)).Delete(); err != nil {
 		return err
 	}
 	return nil
@@ -552,6 +579,8 @@ func (mgr *DatasetMgr) cancelShareWithUser(c *gin.Context, userReq cancelsharedU
 type SharedQueueReq struct {
 	DatasetID uint   `json:"datasetID" binding:"required"`
 	QueueIDs  []uint `json:"queueIDs" binding:"required"`
+	// ExpiresAt ends the share at the given time; nil keeps it until it is canceled.
+	ExpiresAt *time.Time `json:"expiresAt"`
 }
 
 // ShareDatasetWithQueue godoc
@@ -627,18 +656,29 @@ func (mgr *DatasetMgr) shareWithQueue(c *gin.Context, queueReq SharedQueueReq) e
 	if len(queueReq.QueueIDs) == 0 {
 		return fmt.Errorf("need to choose queues to share")
 	}
+	if queueReq.ExpiresAt != nil && !queueReq.ExpiresAt.After(time.Now()) {
+		return fmt.Errorf("the expiry must be in the future")
+	}
 	for _, QueueID := range queueReq.QueueIDs {
 		_, err := q.WithContext(c).Where(q.ID.Eq(QueueID)).First()
 		if err != nil {
 			return fmt.Errorf("queue not exist")
 		}
-		hqd, _ := qd.WithContext(c).Where(qd.AccountID.Eq(QueueID), qd.DatasetID.Eq(queueReq.DatasetID)).First()
+		hqd, _ := qd.WithContext(c).Where(qd.AccountID.Eq(QueueID), qd.DatasetID.Eq(queueReq.DatasetID), util.NotExpired(qd.ExpiresAt)).First()
 		if hqd != nil {
 			return fmt.Errorf("queue has shared dataset")
 		}
+		// an expired grant the cron job has not revoked yet is renewed in place
+		if expired, err := qd.WithContext(c).Where(qd.AccountID.Eq(QueueID), qd.DatasetID.Eq(queueReq.DatasetID)).First(); err == nil {
+			if _, err = qd.WithContext(c).Where(qd.ID.Eq(expired.ID)).Update(qd.ExpiresAt, queueReq.ExpiresAt); err != nil {
+				return err
+			}
+			continue
+		}
 		queuedataset := model.AccountDataset{
 			AccountID: QueueID,
 			DatasetID: queueReq.DatasetID,
+			ExpiresAt: queueReq.ExpiresAt,
 		}
 		if err := qd.WithContext(c).Create(&queuedataset); err != nil {
 			return err
@@ -731,7 +771,7 @@ func (mgr *DatasetMgr) cancelShareWithQueue(c *gin.Context, cancelQueueReq cance
 	if hqd == nil {
 		return fmt.Errorf("the dataset was not shared with the queue")
 	}
-	if _, err := qd.WithContext(c).Delete(hqd); err != nil {
+	if _, err := qd.WithContext(c).Where(qd.AccountID.Eq(cancelQueueReq.QueueID), qd.DatasetID.Eq(cancelQueueReq.DatasetID)).Delete(); err != nil {
 		return err
 	}
 	return nil
@@ -932,7 +972,7 @@ func (mgr *DatasetMgr) ListUsersOutOfDataset(c *gin.Context) {
 	u := query.User
 	ud := query.UserDataset
 	var uids []uint
-	if err := ud.WithContext(c).Select(ud.UserID).Where(ud.DatasetID.Eq(dataset.ID)).Scan(&uids); err != nil {
+	if err := ud.WithContext(c).Select(ud.UserID).Where(ud.DatasetID.Eq(dataset.ID), util.NotExpired(ud.ExpiresAt)).Scan(&uids); err != nil {
 		resputil.Error(c, fmt.Sprintf("Failed to scan user IDs: %v", err), resputil.NotSpecified)
 		return
 	}
@@ -944,16 +984,17 @@ func (mgr *DatasetMgr) ListUsersOutOfDataset(c *gin.Context) {
 	}
 	resp := make([]model.UserAttribute, len(attributes))
 	for i := range attributes {
-		resp[i] = attributes[i].Attributes.Data()
+		resp[i] = attributes[i].Attributes.Data().Redacted()
 	}
 	resputil.Success(c, resp)
 }
 
 type UserOfDatasetResp struct {
-	ID       uint                                    `json:"id"`
-	Name     string                                  `json:"name"`
-	IsOwner  bool                                    `json:"isowner"`
-	UserInfo datatypes.JSONType[model.UserAttribute] `json:"userInfo"`
+	ID        uint                                    `json:"id"`
+	Name      string                                  `json:"name"`
+	IsOwner   bool                                    `json:"isowner"`
+	UserInfo  datatypes.JSONType[model.UserAttribute] `json:"userInfo"`
+	ExpiresAt *time.Time                              `json:"expiresAt,omitempty"`
 }
 
 // 函数名称 ListUserOfDataset
@@ -983,11 +1024,19 @@ func (mgr *DatasetMgr) ListUserOfDataset(c *gin.Context) {
 		resputil.Error(c, "this dataset not exist", resputil.InvalidRequest)
 		return
 	}
-	var uids []uint
-	if err := ud.WithContext(c).Select(ud.UserID).Where(ud.DatasetID.Eq(dataset.ID)).Distinct().Scan(&uids); err != nil {
+	grants, err := ud.WithContext(c).Where(ud.DatasetID.Eq(dataset.ID), util.NotExpired(ud.ExpiresAt)).Find()
+	if err != nil {
 		resputil.Error(c, fmt.Sprintf("Failed to scan user IDs: %v", err), resputil.NotSpecified)
 		return
 	}
+	var uids []uint
+	expiresAt := make(map[uint]*time.Time, len(grants))
+	for _, grant := range grants {
+		if _, seen := expiresAt[grant.UserID]; !seen {
+			uids = append(uids, grant.UserID)
+		}
+		expiresAt[grant.UserID] = grant.ExpiresAt
+	}
 
 	var resp []UserOfDatasetResp
 	for i := range uids {
@@ -996,10 +1045,11 @@ func (mgr *DatasetMgr) ListUserOfDataset(c *gin.Context) {
 			resputil.Error(c, fmt.Sprintf("Can't find user :%v", err), resputil.InvalidRequest)
 		}
 		res := UserOfDatasetResp{
-			ID:       uids[i],
-			IsOwner:  uids[i] == dataset.UserID,
-			Name:     user.Name,
-			UserInfo: user.Attributes,
+			ID:        uids[i],
+			IsOwner:   uids[i] == dataset.UserID,
+			Name:      user.Name,
+			UserInfo:  user.Attributes,
+			ExpiresAt: expiresAt[uids[i]],
 		}
 		resp = append(resp, res)
 	}
@@ -1011,6 +1061,7 @@ type QueueDatasetGetResp struct {
 	ID         uint                                    `json:"id"`
 	Nickname   string                                  `json:"name"`
 	Attributes datatypes.JSONType[model.UserAttribute] `json:"attributes"`
+	ExpiresAt  *time.Time                              `json:"expiresAt,omitempty" gorm:"-"`
 }
 
 // ListQueuesOutOfDataset godoc
@@ -1041,7 +1092,7 @@ func (mgr *DatasetMgr) ListQueuesOutOfDataset(c *gin.Context) {
 	q := query.Account
 	qd := query.AccountDataset
 	var qids []uint
-	if err := qd.WithContext(c).Select(qd.AccountID).Where(qd.DatasetID.Eq(dataset.ID)).Scan(&qids); err != nil {
+	if err := qd.WithContext(c).Select(qd.AccountID).Where(qd.DatasetID.Eq(dataset.ID), util.NotExpired(qd.ExpiresAt)).Scan(&qids); err != nil {
 		resputil.Error(c, fmt.Sprintf("Failed to scan queue IDs: %v", err), resputil.NotSpecified)
 		return
 	}
@@ -1081,17 +1132,26 @@ func (mgr *DatasetMgr) ListQueueOfDataset(c *gin.Context) {
 		resputil.Error(c, "this dataset not exist", resputil.InvalidRequest)
 		return
 	}
-	var qids []uint
-	if err := qd.WithContext(c).Select(qd.AccountID).Where(qd.DatasetID.Eq(dataset.ID)).Distinct().Scan(&qids); err != nil {
+	grants, err := qd.WithContext(c).Where(qd.DatasetID.Eq(dataset.ID), util.NotExpired(qd.ExpiresAt)).Find()
+	if err != nil {
 		resputil.Error(c, fmt.Sprintf("Failed to scan queue IDs: %v", err), resputil.NotSpecified)
 		return
 	}
+	qids := make([]uint, 0, len(grants))
+	expiresAt := make(map[uint]*time.Time, len(grants))
+	for _, grant := range grants {
+		qids = append(qids, grant.AccountID)
+		expiresAt[grant.AccountID] = grant.ExpiresAt
+	}
 	var resp []QueueDatasetGetResp
 	exec := q.WithContext(c).Where(q.ID.In(qids...)).Distinct()
 	if err := exec.Scan(&resp); err != nil {
 		resputil.Error(c, fmt.Sprintf("Get QueueDataset failed, detail: %v", err), resputil.NotSpecified)
 		return
 	}
+	for i := range resp {
+		resp[i].ExpiresAt = expiresAt[resp[i].ID]
+	}
 	resputil.Success(c, resp)
 }
 
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/bizerr"
+	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/internal/service"
+)
+
+// Dataset manifests are built and verified by the storage server (internal/storage), which has
+// the filesystem; this side reports them and queues verification requests.
+
+type DatasetDetailQuery struct {
+	// Files includes the per-file manifest, which can be large for models.
+	Files bool `form:"files"`
+}
+
+type DatasetManifestResp struct {
+	Status          model.DatasetIndexStatus       `json:"status"`
+	Message         string                         `json:"message,omitempty"`
+	FileCount       int64                          `json:"fileCount"`
+	SizeBytes       int64                          `json:"sizeBytes"`
+	Digest          string                         `json:"digest"`
+	IndexedAt       *time.Time                     `json:"indexedAt,omitempty"`
+	Integrity       model.DatasetIntegrity         `json:"integrity"`
+	Problems        []model.DatasetManifestProblem `json:"problems,omitempty"`
+	VerifyRequested bool                           `json:"verifyRequested"`
+	VerifiedAt      *time.Time                     `json:"verifiedAt,omitempty"`
+	Files           []model.DatasetManifestEntry   `json:"files,omitempty"`
+}
+
+func newDatasetManifestResp(manifest *model.DatasetManifest, withFiles bool) *DatasetManifestResp {
+	resp := &DatasetManifestResp{
+		Status:          manifest.Status,
+		Message:         manifest.Message,
+		FileCount:       manifest.FileCount,
+		SizeBytes:       manifest.SizeBytes,
+		Digest:          manifest.Digest,
+		IndexedAt:       manifest.IndexedAt,
+		Integrity:       manifest.Integrity,
+		Problems:        manifest.Problems.Data(),
+		VerifyRequested: manifest.VerifyRequested,
+		VerifiedAt:      manifest.VerifiedAt,
+	}
+	if withFiles {
+		resp.Files = manifest.Files.Data()
+	}
+	return resp
+}
+
+// loadDatasetManifest returns nil when the dataset has not been indexed yet.
+func loadDatasetManifest(c *gin.Context, datasetID uint, withFiles bool) (*DatasetManifestResp, error) {
+	m := query.DatasetManifest
+	do := m.WithContext(c).Where(m.DatasetID.Eq(datasetID))
+	if !withFiles {
+		do = do.Omit(m.Files)
+	}
+	manifest, err := do.First()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return newDatasetManifestResp(manifest, withFiles), nil
+}
+
+// VerifyDataset godoc
+//
+//	@Summary		Verify a dataset
+//	@Description	Queue a full rehash of the dataset files and a comparison with its manifest, to detect silent corruption or partial downloads
+//	@Tags			Dataset
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			datasetId	path		uint									true	"dataset id"
+//	@Success		200			{object}	resputil.Response[DatasetManifestResp]	"manifest with the queued verification"
+//	@Failure		403			{object}	resputil.Response[any]					"dataset not shared with the caller"
+//	@Failure		404			{object}	resputil.Response[any]					"dataset not found"
+//	@Router			/v1/dataset/{datasetId}/verify [post]
+func (mgr *DatasetMgr) VerifyDataset(c *gin.Context) {
+	var req DatasetGetReq
+	if err := c.ShouldBindUri(&req); err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.ParameterError.Wrap(err, "invalid dataset id"))
+		return
+	}
+	if err := datasetReadable(c, req.DatasetID); err != nil {
+		resputil.HandleError(c, err)
+		return
+	}
+	if err := service.RequestDatasetVerification(c, query.GetDB(), req.DatasetID, 0); err != nil {
+		resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "failed to request verification"))
+		return
+	}
+	manifest, err := loadDatasetManifest(c, req.DatasetID, false)
+	if err != nil {
+		resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "failed to get dataset manifest"))
+		return
+	}
+	resputil.Success(c, manifest)
+}
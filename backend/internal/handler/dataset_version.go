@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/bizerr"
+	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/internal/util"
+)
+
+// Dataset versions are published by the storage server (POST /api/ss/datasets/{id}/versions),
+// which has the filesystem to snapshot them; this side only reads them.
+
+type DatasetVersionGetReq struct {
+	DatasetID uint   `uri:"datasetId" binding:"required"`
+	Version   string `uri:"version" binding:"required"`
+}
+
+type DatasetVersionResp struct {
+	ID        uint                         `json:"id"`
+	DatasetID uint                         `json:"datasetID"`
+	Version   string                       `json:"version"`
+	Describe  string                       `json:"describe"`
+	Hardlink  bool                         `json:"hardlink"`
+	FileCount int64                        `json:"fileCount"`
+	SizeBytes int64                        `json:"sizeBytes"`
+	Status    model.DatasetVersionStatus   `json:"status"`
+	Message   string                       `json:"message,omitempty"`
+	UserInfo  model.UserInfo               `json:"userInfo"`
+	CreatedAt time.Time                    `json:"createdAt"`
+	ReadyAt   *time.Time                   `json:"readyAt,omitempty"`
+	Manifest  []model.DatasetManifestEntry `json:"manifest,omitempty"`
+}
+
+func newDatasetVersionResp(version *model.DatasetVersion, withManifest bool) DatasetVersionResp {
+	resp := DatasetVersionResp{
+		ID:        version.ID,
+		DatasetID: version.DatasetID,
+		Version:   version.Version,
+		Describe:  version.Describe,
+		Hardlink:  version.Hardlink,
+		FileCount: version.FileCount,
+		SizeBytes: version.SizeBytes,
+		Status:    version.Status,
+		Message:   version.Message,
+		CreatedAt: version.CreatedAt,
+		ReadyAt:   version.ReadyAt,
+	}
+	if withManifest {
+		resp.Manifest = version.Manifest.Data()
+	}
+	return resp
+}
+
+// datasetReadable checks that the caller owns the dataset, is an admin, or has it shared with
+// them directly or through an account.
+func datasetReadable(c *gin.Context, datasetID uint) error {
+	token := util.GetToken(c)
+	d := query.Dataset
+	dataset, err := d.WithContext(c).Where(d.ID.Eq(datasetID)).First()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return bizerr.NotFound.DataBaseNotFound.New(fmt.Sprintf("dataset %d does not exist", datasetID))
+	} else if err != nil {
+		return bizerr.Internal.DatabaseError.Wrap(err, "failed to get dataset")
+	}
+	if dataset.UserID == token.UserID || token.RolePlatform == model.RoleAdmin {
+		return nil
+	}
+	if _, _, err = util.GetSubPathByDatasetVolume(c, token.UserID, datasetID); err != nil {
+		return bizerr.Forbidden.PermissionDenied.New(fmt.Sprintf("dataset %d is not shared with you", datasetID))
+	}
+	return nil
+}
+
+func loadDatasetVersionUsers(c *gin.Context, versions []*model.DatasetVersion) (map[uint]model.UserInfo, error) {
+	ids := make([]uint, 0, len(versions))
+	for _, version := range versions {
+		ids = append(ids, version.UserID)
+	}
+	u := query.User
+	users, err := u.WithContext(c).Where(u.ID.In(ids...)).Find()
+	if err != nil {
+		return nil, err
+	}
+	infos := make(map[uint]model.UserInfo, len(users))
+	for _, user := range users {
+		infos[user.ID] = model.UserInfo{Username: user.Name, Nickname: user.Nickname}
+	}
+	return infos, nil
+}
+
+// ListDatasetVersions godoc
+//
+//	@Summary		List dataset versions
+//	@Description	Published versions of a dataset, newest first, without their manifests
+//	@Tags			Dataset
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			datasetId	path		uint										true	"dataset id"
+//	@Success		200			{object}	resputil.Response[[]DatasetVersionResp]	"dataset versions"
+//	@Failure		403			{object}	resputil.Response[any]						"dataset not shared with the caller"
+//	@Failure		404			{object}	resputil.Response[any]						"dataset not found"
+//	@Router			/v1/dataset/{datasetId}/versions [get]
+func (mgr *DatasetMgr) ListDatasetVersions(c *gin.Context) {
+	var req DatasetGetReq
+	if err := c.ShouldBindUri(&req); err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.ParameterError.Wrap(err, "invalid dataset id"))
+		return
+	}
+	if err := datasetReadable(c, req.DatasetID); err != nil {
+		resputil.HandleError(c, err)
+		return
+	}
+	v := query.DatasetVersion
+	versions, err := v.WithContext(c).Omit(v.Manifest).Where(v.DatasetID.Eq(req.DatasetID)).Order(v.ID.Desc()).Find()
+	if err != nil {
+		resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "failed to list dataset versions"))
+		return
+	}
+	users, err := loadDatasetVersionUsers(c, versions)
+	if err != nil {
+		resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "failed to load publishers"))
+		return
+	}
+	resp := make([]DatasetVersionResp, 0, len(versions))
+	for _, version := range versions {
+		item := newDatasetVersionResp(version, false)
+		item.UserInfo = users[version.UserID]
+		resp = append(resp, item)
+	}
+	resputil.Success(c, resp)
+}
+
+// GetDatasetVersion godoc
+//
+//	@Summary		Get a dataset version
+//	@Description	A published dataset version with its manifest of paths, sizes and sha256 hashes
+//	@Tags			Dataset
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			datasetId	path		uint									true	"dataset id"
+//	@Param			version		path		string									true	"version"
+//	@Success		200			{object}	resputil.Response[DatasetVersionResp]	"dataset version"
+//	@Failure		403			{object}	resputil.Response[any]					"dataset not shared with the caller"
+//	@Failure		404			{object}	resputil.Response[any]					"dataset or version not found"
+//	@Router			/v1/dataset/{datasetId}/versions/{version} [get]
+func (mgr *DatasetMgr) GetDatasetVersion(c *gin.Context) {
+	var req DatasetVersionGetReq
+	if err := c.ShouldBindUri(&req); err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.ParameterError.Wrap(err, "invalid dataset version"))
+		return
+	}
+	if err := datasetReadable(c, req.DatasetID); err != nil {
+		resputil.HandleError(c, err)
+		return
+	}
+	v := query.DatasetVersion
+	version, err := v.WithContext(c).Where(v.DatasetID.Eq(req.DatasetID), v.Version.Eq(req.Version)).First()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		resputil.HandleError(c, bizerr.NotFound.DataBaseNotFound.New(
+			fmt.Sprintf("version %s of dataset %d does not exist", req.Version, req.DatasetID)))
+		return
+	} else if err != nil {
+		resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "failed to get dataset version"))
+		return
+	}
+	users, err := loadDatasetVersionUsers(c, []*model.DatasetVersion{version})
+	if err != nil {
+		resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "failed to load publisher"))
+		return
+	}
+	resp := newDatasetVersionResp(version, true)
+	resp.UserInfo = users[version.UserID]
+	resputil.Success(c, resp)
+}
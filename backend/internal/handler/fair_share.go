@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/raids-lab/crater/internal/bizerr"
+	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/pkg/constants"
+)
+
+// FairShareUpdateReq sets the share of the account (UserID 0) or of one member.
+// A missing Share restores the default share of 1.
+type FairShareUpdateReq struct {
+	UserID uint `json:"userId"`
+	Share  *int `json:"share" binding:"omitempty,gt=0"`
+}
+
+// AdminGetAccountFairShare godoc
+//
+//	@Summary		Get account fair share
+//	@Description	Shares, decayed GPU-hours and activation priority of an account and its members
+//	@Tags			Project
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			aid	path		uint												true	"account id"
+//	@Success		200	{object}	resputil.Response[service.AccountFairShareReport]	"fair share report"
+//	@Failure		404	{object}	resputil.Response[any]								"account not found"
+//	@Router			/v1/admin/accounts/{aid}/fair-share [get]
+func (mgr *AccountMgr) AdminGetAccountFairShare(c *gin.Context) {
+	accountID, ok := mgr.bindAccountID(c)
+	if !ok {
+		return
+	}
+	if _, err := mgr.validateAccount(c, accountID); err != nil {
+		resputil.HandleError(c, err)
+		return
+	}
+	report, err := mgr.prequeueSvc.GetAccountFairShare(c.Request.Context(), accountID)
+	if err != nil {
+		resputil.HandleError(c, bizerr.Internal.ServiceError.Wrap(err, "failed to load fair share"))
+		return
+	}
+	resputil.Success(c, report)
+}
+
+// AdminUpdateAccountFairShare godoc
+//
+//	@Summary		Update account fair share
+//	@Description	Set the fair-share weight of an account (userId 0) or of a member inside it
+//	@Tags			Project
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			aid		path		uint												true	"account id"
+//	@Param			data	body		FairShareUpdateReq									true	"share"
+//	@Success		200		{object}	resputil.Response[service.AccountFairShareReport]	"fair share report"
+//	@Failure		400		{object}	resputil.Response[any]								"invalid share"
+//	@Failure		404		{object}	resputil.Response[any]								"account or member not found"
+//	@Router			/v1/admin/accounts/{aid}/fair-share [put]
+func (mgr *AccountMgr) AdminUpdateAccountFairShare(c *gin.Context) {
+	accountID, ok := mgr.bindAccountID(c)
+	if !ok {
+		return
+	}
+	var req FairShareUpdateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.ParameterError.Wrap(err, "invalid request body"))
+		return
+	}
+	if _, err := mgr.validateAccount(c, accountID); err != nil {
+		resputil.HandleError(c, err)
+		return
+	}
+
+	target := fmt.Sprintf("account/%d/fair-share/%d", accountID, req.UserID)
+	if err := mgr.prequeueSvc.SetFairShare(c.Request.Context(), accountID, req.UserID, req.Share); err != nil {
+		RecordOperationLog(c, constants.OpTypeUpdateFairShare, target, constants.OpStatusFailed, err.Error(), nil)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			resputil.HandleError(c, bizerr.NotFound.DataBaseNotFound.New(
+				fmt.Sprintf("user %d is not a member of account %d", req.UserID, accountID)))
+			return
+		}
+		resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "failed to update fair share"))
+		return
+	}
+	RecordOperationLog(c, constants.OpTypeUpdateFairShare, target, constants.OpStatusSuccess, "", map[string]any{
+		"share": req.Share,
+	})
+
+	report, err := mgr.prequeueSvc.GetAccountFairShare(c.Request.Context(), accountID)
+	if err != nil {
+		resputil.HandleError(c, bizerr.Internal.ServiceError.Wrap(err, "failed to load fair share"))
+		return
+	}
+	resputil.Success(c, report)
+}
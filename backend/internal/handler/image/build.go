@@ -1,7 +1,11 @@
 package image
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"path"
+	"regexp"
 	"strings"
 
 	"github.com/gin-gonic/gin"
@@ -33,8 +37,10 @@ func (mgr *ImagePackMgr) UserCreateByPipApt(c *gin.Context) {
 		resputil.BadRequestError(c, msg)
 		return
 	}
-	if len(req.Archs) == 0 {
-		req.Archs = []string{"linux/amd64"}
+	archs, err := packer.NormalizePlatforms(req.Archs)
+	if err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
 	}
 	dockerfile := mgr.generateDockerfile(req)
 	buildData := &DockerfileBuildData{
@@ -49,7 +55,8 @@ func (mgr *ImagePackMgr) UserCreateByPipApt(c *gin.Context) {
 		Tags:         req.Tags,
 		Template:     req.Template,
 		BuildSource:  model.PipApt,
-		Archs:        req.Archs,
+		Archs:        archs,
+		NoCache:      req.NoCache,
 	}
 	mgr.buildFromDockerfile(c, buildData)
 }
@@ -72,8 +79,10 @@ func (mgr *ImagePackMgr) UserCreateByDockerfile(c *gin.Context) {
 		resputil.BadRequestError(c, msg)
 		return
 	}
-	if len(req.Archs) == 0 {
-		req.Archs = []string{"linux/amd64"}
+	archs, err := packer.NormalizePlatforms(req.Archs)
+	if err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
 	}
 	baseImage, err := extractBaseImageFromDockerfile(req.Dockerfile)
 	if err != nil {
@@ -92,42 +101,140 @@ func (mgr *ImagePackMgr) UserCreateByDockerfile(c *gin.Context) {
 		Tags:         req.Tags,
 		Template:     req.Template,
 		BuildSource:  model.Dockerfile,
-		Archs:        req.Archs,
+		Archs:        archs,
 		VolumeMounts: req.VolumeMounts,
+		NoCache:      req.NoCache,
+	}
+	mgr.buildFromDockerfile(c, buildData)
+}
+
+// UserCreateByGit godoc
+//
+//	@Summary		克隆Git仓库并按其中的Dockerfile创建镜像
+//	@Description	获取参数，校验仓库地址与路径，构建完成后在模板中记录提交SHA
+//	@Tags			ImagePack
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			data	body	CreateByGitRequest	true	"创建ImagePack CRD"
+//	@Router			/v1/images/git [POST]
+func (mgr *ImagePackMgr) UserCreateByGit(c *gin.Context) {
+	req := &CreateByGitRequest{}
+	token := util.GetToken(c)
+	if err := c.ShouldBindJSON(req); err != nil {
+		msg := fmt.Sprintf("validate create parameters failed, err %v", err)
+		resputil.BadRequestError(c, msg)
+		return
+	}
+	archs, err := packer.NormalizePlatforms(req.Archs)
+	if err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	source := &packer.GitSource{
+		Repo:             req.Repo,
+		Ref:              req.Ref,
+		Subdir:           req.Subdir,
+		Dockerfile:       req.Dockerfile,
+		CredentialSecret: req.CredentialSecret,
+	}
+	if err = source.Normalize(); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+	imageName := req.ImageName
+	if imageName == "" {
+		imageName = imageNameFromGitRepo(source.Repo)
+	}
+
+	// the template records the cleaned request, and the reconciler adds the cloned commit
+	req.Repo, req.Ref, req.Subdir, req.Dockerfile = source.Repo, source.Ref, source.Subdir, source.Dockerfile
+	req.CredentialSecret, req.Archs = source.CredentialSecret, archs
+	template, err := json.Marshal(req)
+	if err != nil {
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+		return
+	}
+	buildData := &DockerfileBuildData{
+		Description: req.Description,
+		ImageName:   imageName,
+		ImageTag:    req.ImageTag,
+		UserName:    token.Username,
+		UserID:      token.UserID,
+		Tags:        req.Tags,
+		Template:    string(template),
+		BuildSource: model.GitRepo,
+		Archs:       archs,
+		NoCache:     req.NoCache,
+		Git:         source,
 	}
 	mgr.buildFromDockerfile(c, buildData)
 }
 
+var invalidImageNameChars = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// imageNameFromGitRepo names an image after its repository, e.g. "crater" for
+// https://github.com/raids-lab/crater.git.
+func imageNameFromGitRepo(repo string) string {
+	name := strings.TrimSuffix(path.Base(strings.TrimRight(repo, "/")), ".git")
+	name = strings.Trim(invalidImageNameChars.ReplaceAllString(strings.ToLower(name), "-"), "-._")
+	if name == "" {
+		return "git-build"
+	}
+	return name
+}
+
+// extractBaseImageFromDockerfile returns the base image of the final stage. Stages built FROM an
+// earlier stage resolve to that stage's image, and flags such as --platform are skipped.
 func extractBaseImageFromDockerfile(dockerfile string) (string, error) {
 	lines := strings.Split(dockerfile, "\n")
-	for i, line := range lines {
-		line = strings.TrimSpace(line)
-
+	stages := map[string]string{}
+	baseImage := ""
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
 		if strings.HasPrefix(line, "#") {
 			continue
 		}
+		if !strings.HasPrefix(strings.ToUpper(line), "FROM") {
+			continue
+		}
 
-		if strings.HasPrefix(line, "FROM") {
-			for strings.HasSuffix(line, "\\") {
-				line = line[:len(line)-1]
-				line = strings.TrimSpace(line)
-				// last line check
-				if i+1 >= len(lines) {
-					return "", fmt.Errorf("unexpected end of Dockerfile after line: %s", line)
-				}
-				nextLine := strings.TrimSpace(lines[i+1])
-				line += " " + nextLine
-				i++ // move to next line
+		for strings.HasSuffix(line, "\\") {
+			line = strings.TrimSpace(line[:len(line)-1])
+			// last line check
+			if i+1 >= len(lines) {
+				return "", fmt.Errorf("unexpected end of Dockerfile after line: %s", line)
 			}
+			i++ // move to next line
+			line += " " + strings.TrimSpace(lines[i])
+		}
 
-			parts := strings.Fields(line)
-			if len(parts) >= 2 {
-				return parts[1], nil
+		parts := strings.Fields(line)
+		if !strings.EqualFold(parts[0], "FROM") {
+			continue
+		}
+		args := make([]string, 0, len(parts))
+		for _, part := range parts[1:] {
+			if !strings.HasPrefix(part, "--") {
+				args = append(args, part)
 			}
+		}
+		if len(args) == 0 {
 			return "", fmt.Errorf("invalid FROM instruction: %s", line)
 		}
+		image := args[0]
+		if stage, ok := stages[strings.ToLower(image)]; ok {
+			image = stage
+		}
+		if len(args) >= 3 && strings.EqualFold(args[1], "AS") {
+			stages[strings.ToLower(args[2])] = image
+		}
+		baseImage = image
+	}
+	if baseImage == "" {
+		return "", fmt.Errorf("no FROM instruction found in Dockerfile")
 	}
-	return "", fmt.Errorf("no FROM instruction found in Dockerfile")
+	return baseImage, nil
 }
 
 // UserCreateByEnvd godoc
@@ -188,6 +295,11 @@ func (mgr *ImagePackMgr) AdminCreate(c *gin.Context) {
 		return
 	}
 	klog.Infof("create params: %+v", req)
+	archs, err := packer.NormalizePlatforms(req.Archs)
+	if err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
 	dockerfile := mgr.generateDockerfile(req)
 	buildData := &DockerfileBuildData{
 		BaseImage:   req.SourceImage,
@@ -200,6 +312,8 @@ func (mgr *ImagePackMgr) AdminCreate(c *gin.Context) {
 		Tags:        req.Tags,
 		Template:    req.Template,
 		BuildSource: model.Dockerfile,
+		Archs:       archs,
+		NoCache:     req.NoCache,
 	}
 	mgr.buildFromDockerfile(c, buildData)
 }
@@ -247,6 +361,10 @@ func (mgr *ImagePackMgr) buildFromDockerfile(c *gin.Context, data *DockerfileBui
 		resputil.Error(c, err.Error(), resputil.NotSpecified)
 		return
 	}
+	cacheRef := ""
+	if _, _, repository, _, splitErr := utils.SplitImageLink(imageLink); splitErr == nil {
+		cacheRef = packer.BuildCacheRef(data.UserName, repository)
+	}
 
 	// create ImagePack CRD
 	buildkitData := &packer.BuildKitReq{
@@ -255,6 +373,7 @@ func (mgr *ImagePackMgr) buildFromDockerfile(c *gin.Context, data *DockerfileBui
 		Dockerfile:   &data.Dockerfile,
 		ImageLink:    imageLink,
 		UserID:       data.UserID,
+		UserName:     data.UserName,
 		Description:  &data.Description,
 		Requirements: data.Requirements,
 		Tags:         data.Tags,
@@ -263,9 +382,16 @@ func (mgr *ImagePackMgr) buildFromDockerfile(c *gin.Context, data *DockerfileBui
 		Archs:        data.Archs,
 		VolumeMounts: data.VolumeMounts,
 		Token:        token,
+		CacheRef:     cacheRef,
+		NoCache:      data.NoCache,
+		Git:          data.Git,
 	}
 
 	if err := mgr.imagePacker.CreateFromDockerfile(c, buildkitData); err != nil {
+		if errors.Is(err, packer.ErrInvalidGitCredential) {
+			resputil.BadRequestError(c, err.Error())
+			return
+		}
 		klog.Errorf("create imagepack failed, err:%+v", err)
 		resputil.Error(c, "create imagepack failed", resputil.NotSpecified)
 		return
@@ -0,0 +1,48 @@
+package image
+
+import "testing"
+
+func TestExtractBaseImageFromDockerfile(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		dockerfile string
+		want       string
+	}{
+		{"single stage", "# base\nFROM ubuntu:22.04\nRUN apt-get update", "ubuntu:22.04"},
+		{"continued line", "FROM \\\n  nvcr.io/nvidia/pytorch:24.01-py3\n", "nvcr.io/nvidia/pytorch:24.01-py3"},
+		{
+			"multi stage",
+			"FROM --platform=$BUILDPLATFORM golang:1.22 AS builder\nRUN go build ./...\n" +
+				"FROM python:3.11-slim AS runtime\nCOPY --from=builder /out /app\n",
+			"python:3.11-slim",
+		},
+		{
+			"final stage from an earlier stage",
+			"FROM nvidia/cuda:12.4.0-devel-ubuntu22.04 AS base\nRUN pip install torch\nfrom base\nCMD [\"python\"]\n",
+			"nvidia/cuda:12.4.0-devel-ubuntu22.04",
+		},
+	} {
+		got, err := extractBaseImageFromDockerfile(tt.dockerfile)
+		if err != nil || got != tt.want {
+			t.Errorf("%s: extractBaseImageFromDockerfile = %q, %v; want %q", tt.name, got, err, tt.want)
+		}
+	}
+	for _, dockerfile := range []string{"RUN echo hi", "FROM --platform=linux/amd64", "FROM ubuntu \\"} {
+		if _, err := extractBaseImageFromDockerfile(dockerfile); err == nil {
+			t.Errorf("extractBaseImageFromDockerfile(%q) succeeded, want an error", dockerfile)
+		}
+	}
+}
+
+func TestImageNameFromGitRepo(t *testing.T) {
+	for repo, want := range map[string]string{
+		"https://github.com/raids-lab/crater.git":     "crater",
+		"https://gitlab.example.com/Team/My_Project/": "my_project",
+		"https://example.com/":                        "example.com",
+		"https://example.com/--.git":                  "git-build",
+	} {
+		if got := imageNameFromGitRepo(repo); got != want {
+			t.Errorf("imageNameFromGitRepo(%q) = %q, want %q", repo, got, want)
+		}
+	}
+}
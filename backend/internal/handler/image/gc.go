@@ -0,0 +1,68 @@
+package image
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/bizerr"
+	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/pkg/cleaner"
+)
+
+// AdminGetImageGCReport godoc
+//
+//	@Summary		镜像清理演练报告
+//	@Description	按 clean-stale-images 定时任务的保留策略计算将被删除的镜像，不做任何修改；查询参数可临时覆盖策略
+//	@Tags			ImagePack
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			keepLastPerRepository	query		int		false	"每个仓库保留的最新镜像数"
+//	@Param			unusedDays				query		int		false	"未被作业使用的天数"
+//	@Param			deleteUntagged			query		bool	false	"是否删除没有标签指向的镜像"
+//	@Param			skipShared				query		bool	false	"是否跳过公开或共享的镜像"
+//	@Param			noticeDays				query		int		false	"通知所有者到删除的天数"
+//	@Success		200						{object}	resputil.Response[ImageGCReportResp]
+//	@Router			/v1/admin/images/gc [GET]
+func (mgr *ImagePackMgr) AdminGetImageGCReport(c *gin.Context) {
+	cronQuery := query.CronJobConfig
+	job, err := cronQuery.WithContext(c).Where(cronQuery.Name.Eq(cleaner.CLEAN_STALE_IMAGES_JOB)).First()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		resputil.HandleError(c, bizerr.NotFound.DataBaseNotFound.New("the clean-stale-images cron job is not configured"))
+		return
+	} else if err != nil {
+		resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "get image gc policy failed"))
+		return
+	}
+
+	policy := &cleaner.ImageGCRequest{}
+	if len(job.Config) > 0 {
+		if err = json.Unmarshal(job.Config, policy); err != nil {
+			resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "invalid image gc policy"))
+			return
+		}
+	}
+	if err = c.ShouldBindQuery(policy); err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.ParameterError.Wrap(err, "invalid image gc policy"))
+		return
+	}
+	policy.DryRun = true
+	if err = policy.Validate(); err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.ParameterError.Wrap(err, "invalid image gc policy"))
+		return
+	}
+
+	report, err := cleaner.PlanImageGC(c, mgr.imageRegistry, policy)
+	if err != nil {
+		resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "plan image gc failed"))
+		return
+	}
+	resputil.Success(c, ImageGCReportResp{
+		Schedule:      job.Spec,
+		JobStatus:     job.Status,
+		ImageGCReport: report,
+	})
+}
@@ -0,0 +1,76 @@
+package image
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/datatypes"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/pkg/cleaner"
+)
+
+func newImageGCTestRouter(t *testing.T, databaseName string) *gin.Engine {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+databaseName+"?mode=memory&cache=shared"), &gorm.Config{
+		DisableForeignKeyConstraintWhenMigrating: true,
+		IgnoreRelationshipsWhenMigrating:         true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = db.AutoMigrate(
+		&model.CronJobConfig{}, &model.User{}, &model.Image{}, &model.ImageUser{}, &model.ImageAccount{},
+		&model.ImageGCMark{},
+	); err != nil {
+		t.Fatal(err)
+	}
+	// jobs and users share the "status" index name, which sqlite keeps per database.
+	if err = db.Exec(`CREATE TABLE jobs (id integer primary key, status text, creation_timestamp datetime,
+		attributes text, deleted_at datetime)`).Error; err != nil {
+		t.Fatal(err)
+	}
+	query.SetDefault(db)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mgr := &ImagePackMgr{}
+	mgr.RegisterAdmin(router.Group("/api/v1/admin/images"))
+	return router
+}
+
+func TestAdminGetImageGCReport(t *testing.T) {
+	router := newImageGCTestRouter(t, "image_gc_report")
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/admin/images/gc", http.NoBody))
+	if recorder.Code != http.StatusNotFound {
+		t.Fatalf("report without a cron job = %d, want %d", recorder.Code, http.StatusNotFound)
+	}
+
+	if err := query.CronJobConfig.WithContext(t.Context()).Create(&model.CronJobConfig{
+		Name:   cleaner.CLEAN_STALE_IMAGES_JOB,
+		Type:   model.CronJobTypeCleanerFunc,
+		Spec:   "0 3 * * *",
+		Status: model.CronJobConfigStatusSuspended,
+		Config: datatypes.JSON(`{"keepLastPerRepository": 5}`),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	for target, want := range map[string]int{
+		"/api/v1/admin/images/gc":                          http.StatusOK,
+		"/api/v1/admin/images/gc?unusedDays=30":            http.StatusOK,
+		"/api/v1/admin/images/gc?keepLastPerRepository=0":  http.StatusBadRequest,
+		"/api/v1/admin/images/gc?keepLastPerRepository=-2": http.StatusBadRequest,
+	} {
+		recorder = httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, http.NoBody))
+		if recorder.Code != want {
+			t.Errorf("GET %s = %d, want %d: %s", target, recorder.Code, want, recorder.Body.String())
+		}
+	}
+}
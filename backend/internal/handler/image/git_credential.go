@@ -0,0 +1,169 @@
+package image
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/raids-lab/crater/internal/bizerr"
+	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/internal/util"
+	"github.com/raids-lab/crater/pkg/crclient"
+	"github.com/raids-lab/crater/pkg/packer"
+)
+
+type (
+	GitCredentialReq struct {
+		Name     string `json:"name" binding:"required"`
+		Username string `json:"username" binding:"required"`
+		Password string `json:"password" binding:"required"`
+	}
+
+	GitCredentialResp struct {
+		Name      string    `json:"name"`
+		Username  string    `json:"username"`
+		CreatedAt time.Time `json:"createdAt"`
+	}
+)
+
+// UserSaveGitCredential godoc
+//
+//	@Summary		保存Git仓库凭据
+//	@Description	在构建命名空间中创建或更新属于当前用户的 basic-auth Secret，供 Git 源构建的 credentialSecret 使用
+//	@Tags			ImagePack
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			data	body		GitCredentialReq						true	"凭据名称、用户名与密码或访问令牌"
+//	@Success		200		{object}	resputil.Response[GitCredentialResp]	"保存的凭据"
+//	@Failure		400		{object}	resputil.Response[any]					"Request parameter error"
+//	@Failure		409		{object}	resputil.Response[any]					"名称已被其他 Secret 占用"
+//	@Failure		500		{object}	resputil.Response[any]					"Other errors"
+//	@Router			/v1/images/git/credential [POST]
+func (mgr *ImagePackMgr) UserSaveGitCredential(c *gin.Context) {
+	req := &GitCredentialReq{}
+	if err := c.ShouldBindJSON(req); err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.ParameterError.Wrap(err, "invalid request body"))
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if errs := validation.IsDNS1123Subdomain(req.Name); len(errs) > 0 {
+		resputil.HandleError(c, bizerr.BadRequest.ParameterError.New("invalid credential name: "+strings.Join(errs, "; ")))
+		return
+	}
+	token := util.GetToken(c)
+	data := map[string][]byte{
+		corev1.BasicAuthUsernameKey: []byte(req.Username),
+		corev1.BasicAuthPasswordKey: []byte(req.Password),
+	}
+
+	secret := &corev1.Secret{}
+	err := mgr.imagepackClient.Get(c, client.ObjectKey{Namespace: UserNameSpace, Name: req.Name}, secret)
+	switch {
+	case k8serrors.IsNotFound(err):
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      req.Name,
+				Namespace: UserNameSpace,
+				Labels: map[string]string{
+					crclient.LabelKeyTaskUser:    token.Username,
+					packer.LabelKeyGitCredential: "true",
+				},
+			},
+			Type: corev1.SecretTypeBasicAuth,
+			Data: data,
+		}
+		err = mgr.imagepackClient.Create(c, secret)
+	case err != nil:
+	case !isGitCredentialOf(secret, token.Username):
+		// the name belongs to another user's credential or to a platform secret
+		resputil.HandleError(c, bizerr.Conflict.ResourceAlreadyExists.New("the credential name is already taken"))
+		return
+	default:
+		secret.Data = data
+		err = mgr.imagepackClient.Update(c, secret)
+	}
+	if err != nil {
+		klog.Errorf("save git credential %s failed, err %v", req.Name, err)
+		resputil.HandleError(c, bizerr.Internal.K8sServiceError.Wrap(err, "save git credential failed"))
+		return
+	}
+	resputil.Success(c, gitCredentialResp(secret))
+}
+
+// UserListGitCredentials godoc
+//
+//	@Summary		获取Git仓库凭据列表
+//	@Description	列出当前用户保存的 Git 仓库凭据，不返回密码
+//	@Tags			ImagePack
+//	@Produce		json
+//	@Security		Bearer
+//	@Success		200	{object}	resputil.Response[[]GitCredentialResp]	"凭据列表"
+//	@Failure		500	{object}	resputil.Response[any]					"Other errors"
+//	@Router			/v1/images/git/credential [GET]
+func (mgr *ImagePackMgr) UserListGitCredentials(c *gin.Context) {
+	token := util.GetToken(c)
+	secrets := &corev1.SecretList{}
+	if err := mgr.imagepackClient.List(c, secrets, client.InNamespace(UserNameSpace), client.MatchingLabels{
+		crclient.LabelKeyTaskUser:    token.Username,
+		packer.LabelKeyGitCredential: "true",
+	}); err != nil {
+		resputil.HandleError(c, bizerr.Internal.K8sServiceError.Wrap(err, "list git credentials failed"))
+		return
+	}
+	resp := make([]GitCredentialResp, 0, len(secrets.Items))
+	for i := range secrets.Items {
+		resp = append(resp, gitCredentialResp(&secrets.Items[i]))
+	}
+	resputil.Success(c, resp)
+}
+
+// UserDeleteGitCredential godoc
+//
+//	@Summary		删除Git仓库凭据
+//	@Description	删除当前用户保存的 Git 仓库凭据
+//	@Tags			ImagePack
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			name	path		string					true	"凭据名称"
+//	@Success		200		{object}	resputil.Response[any]	"删除成功"
+//	@Failure		404		{object}	resputil.Response[any]	"凭据不存在"
+//	@Failure		500		{object}	resputil.Response[any]	"Other errors"
+//	@Router			/v1/images/git/credential/{name} [DELETE]
+func (mgr *ImagePackMgr) UserDeleteGitCredential(c *gin.Context) {
+	name := c.Param("name")
+	token := util.GetToken(c)
+	secret := &corev1.Secret{}
+	err := mgr.imagepackClient.Get(c, client.ObjectKey{Namespace: UserNameSpace, Name: name}, secret)
+	if k8serrors.IsNotFound(err) || (err == nil && !isGitCredentialOf(secret, token.Username)) {
+		resputil.HandleError(c, bizerr.NotFound.K8sResourceNotFound.New("git credential "+name+" does not exist"))
+		return
+	}
+	if err == nil {
+		err = mgr.imagepackClient.Delete(c, secret)
+	}
+	if err != nil && !k8serrors.IsNotFound(err) {
+		resputil.HandleError(c, bizerr.Internal.K8sServiceError.Wrap(err, "delete git credential failed"))
+		return
+	}
+	resputil.Success(c, nil)
+}
+
+func isGitCredentialOf(secret *corev1.Secret, username string) bool {
+	return secret.Labels[crclient.LabelKeyTaskUser] == username && secret.Labels[packer.LabelKeyGitCredential] == "true"
+}
+
+func gitCredentialResp(secret *corev1.Secret) GitCredentialResp {
+	return GitCredentialResp{
+		Name:      secret.Name,
+		Username:  string(secret.Data[corev1.BasicAuthUsernameKey]),
+		CreatedAt: secret.CreationTimestamp.Time,
+	}
+}
@@ -0,0 +1,81 @@
+package image
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/raids-lab/crater/internal/util"
+	"github.com/raids-lab/crater/pkg/crclient"
+)
+
+func TestGitCredentialLifecycle(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	platform := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "registry-auth", Namespace: UserNameSpace}}
+	kube := fake.NewClientBuilder().WithObjects(platform).Build()
+	mgr := &ImagePackMgr{imagepackClient: &crclient.ImagePackController{Client: kube}}
+
+	call := func(user string, handle gin.HandlerFunc, method, body string, params ...gin.Param) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Request = httptest.NewRequest(method, "/", strings.NewReader(body))
+		c.Params = params
+		util.SetJWTContext(c, util.JWTMessage{Username: user})
+		handle(c)
+		return recorder
+	}
+	save := func(user, body string) int {
+		return call(user, mgr.UserSaveGitCredential, http.MethodPost, body).Code
+	}
+
+	if code := save("alice", `{"name":"alice-git","username":"alice","password":"t1"}`); code != http.StatusOK {
+		t.Fatalf("save = %d", code)
+	}
+	if code := save("alice", `{"name":"alice-git","username":"alice","password":"t2"}`); code != http.StatusOK {
+		t.Fatalf("update = %d", code)
+	}
+	secret := &corev1.Secret{}
+	if err := kube.Get(context.Background(), client.ObjectKey{Namespace: UserNameSpace, Name: "alice-git"}, secret); err != nil {
+		t.Fatal(err)
+	}
+	if secret.Type != corev1.SecretTypeBasicAuth || string(secret.Data[corev1.BasicAuthPasswordKey]) != "t2" ||
+		secret.Labels[crclient.LabelKeyTaskUser] != "alice" {
+		t.Fatalf("unexpected secret: %+v", secret)
+	}
+	for name, code := range map[string]int{
+		"alice-git":     http.StatusConflict,
+		"registry-auth": http.StatusConflict,
+		"Bad_Name":      http.StatusBadRequest,
+	} {
+		if got := save("bob", `{"name":"`+name+`","username":"bob","password":"x"}`); got != code {
+			t.Errorf("bob saving %s = %d, want %d", name, got, code)
+		}
+	}
+
+	if list := call("alice", mgr.UserListGitCredentials, http.MethodGet, ""); !strings.Contains(list.Body.String(), `"alice-git"`) ||
+		strings.Contains(list.Body.String(), "t2") {
+		t.Fatalf("list = %s", list.Body.String())
+	}
+	if list := call("bob", mgr.UserListGitCredentials, http.MethodGet, ""); strings.Contains(list.Body.String(), "alice-git") {
+		t.Fatalf("bob sees alice's credential: %s", list.Body.String())
+	}
+
+	param := gin.Param{Key: "name", Value: "alice-git"}
+	if code := call("bob", mgr.UserDeleteGitCredential, http.MethodDelete, "", param).Code; code != http.StatusNotFound {
+		t.Fatalf("bob deleting alice's credential = %d, want %d", code, http.StatusNotFound)
+	}
+	if code := call("alice", mgr.UserDeleteGitCredential, http.MethodDelete, "", param).Code; code != http.StatusOK {
+		t.Fatalf("delete = %d", code)
+	}
+	if err := kube.Get(context.Background(), client.ObjectKey{Namespace: UserNameSpace, Name: "alice-git"}, secret); err == nil {
+		t.Fatal("the credential should be deleted")
+	}
+}
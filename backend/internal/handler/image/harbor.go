@@ -1,14 +1,18 @@
 package image
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/url"
 
 	"github.com/gin-gonic/gin"
 	"k8s.io/klog/v2"
 
+	"github.com/raids-lab/crater/internal/bizerr"
 	"github.com/raids-lab/crater/internal/resputil"
 	"github.com/raids-lab/crater/internal/util"
+	"github.com/raids-lab/crater/pkg/imageregistry"
 	"github.com/raids-lab/crater/pkg/utils"
 )
 
@@ -22,7 +26,7 @@ import (
 //	@Security		Bearer
 //	@Router			/v1/images/harbor [GET]
 func (mgr *ImagePackMgr) GetHarborIP(c *gin.Context) {
-	harborIP := mgr.imageRegistry.GetHarborIP()
+	harborIP := mgr.imageRegistry.GetRegistryURL()
 	resp := GetHarborIPResponse{
 		HarborIP: harborIP,
 	}
@@ -47,7 +51,7 @@ func (mgr *ImagePackMgr) CheckLinkValidity(c *gin.Context) {
 	}
 	invalidPairs := []ImageInfoLinkPair{}
 	for _, linkPair := range req.LinkPairs {
-		if !mgr.checkLinkValidity(linkPair.ImageLink) {
+		if !mgr.checkLinkValidity(c, linkPair.ImageLink) {
 			invalidPairs = append(invalidPairs, linkPair)
 		}
 	}
@@ -58,7 +62,17 @@ func (mgr *ImagePackMgr) CheckLinkValidity(c *gin.Context) {
 	resputil.Success(c, resp)
 }
 
-func (mgr *ImagePackMgr) checkLinkValidity(link string) bool {
+func (mgr *ImagePackMgr) checkLinkValidity(c context.Context, link string) bool {
+	// images in the inner registry are checked through the registry backend
+	exist, err := mgr.imageRegistry.ImageExists(c, link)
+	if !errors.Is(err, imageregistry.ErrImageNotInRegistry) {
+		if err != nil {
+			// an unreachable registry says nothing about the link itself
+			klog.Errorf("check image in registry failed, err %v", err)
+			return true
+		}
+		return exist
+	}
 	ip, project, repository, tag, err := utils.SplitImageLink(link)
 	if err != nil {
 		klog.Errorf("split image link failed, err %v", err)
@@ -122,6 +136,11 @@ func (mgr *ImagePackMgr) UserGetProjectCredential(c *gin.Context) {
 		}
 	}
 	password, err := mgr.imageRegistry.CreateUser(c, token.Username)
+	if errors.Is(err, imageregistry.ErrUnsupported) {
+		resputil.HandleError(c, bizerr.MethodNotAllowed.MethodNotAllowed.New(
+			"the image registry has no per-user accounts, ask an administrator for push credentials"))
+		return
+	}
 	if err != nil {
 		klog.Errorf("create user failed: %+v", err)
 		resputil.Error(c, "create user failed", resputil.NotSpecified)
@@ -159,7 +178,12 @@ func (mgr *ImagePackMgr) UpdateProjectQuota(c *gin.Context) {
 	}
 	projectName := fmt.Sprintf("user-%s", token.Username)
 	if err := mgr.imageRegistry.UpdateQuotaForProject(c, projectName, req.Size); err != nil {
+		if errors.Is(err, imageregistry.ErrUnsupported) {
+			resputil.HandleError(c, bizerr.MethodNotAllowed.MethodNotAllowed.New("the image registry does not enforce quotas"))
+			return
+		}
 		resputil.Error(c, "update harbor project quota failed", resputil.NotSpecified)
+		return
 	}
 	resputil.Success(c, "")
 }
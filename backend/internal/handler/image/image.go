@@ -3,7 +3,9 @@ package image
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"sort"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"k8s.io/klog/v2"
@@ -16,6 +18,7 @@ import (
 	"github.com/raids-lab/crater/internal/bizerr"
 	"github.com/raids-lab/crater/internal/resputil"
 	"github.com/raids-lab/crater/internal/util"
+	"github.com/raids-lab/crater/pkg/imagescan"
 )
 
 // UserUploadImage godoc
@@ -56,6 +59,9 @@ func (mgr *ImagePackMgr) UserUploadImage(c *gin.Context) {
 	imageQuery := query.Image
 	if err := imageQuery.WithContext(c).Create(imageEntity); err != nil {
 		klog.Errorf("create imagepack entity failed, params: %+v", imageEntity)
+	} else if err := imagescan.RequestScan(c, imageEntity.ID, imageEntity.ImageLink); err != nil &&
+		!errors.Is(err, imagescan.ErrScanDisabled) {
+		klog.Errorf("request scan of image %d failed, err: %v", imageEntity.ID, err)
 	}
 	resputil.Success(c, "")
 }
@@ -201,7 +207,7 @@ func (mgr *ImagePackMgr) getUserSharedImages(c *gin.Context, userID uint) []*mod
 	imageShares, err := imageShareQuery.WithContext(c).
 		Preload(imageShareQuery.Image).
 		Preload(imageShareQuery.Image.User).
-		Where(imageShareQuery.UserID.Eq(userID)).
+		Where(imageShareQuery.UserID.Eq(userID), util.NotExpired(imageShareQuery.ExpiresAt)).
 		Find()
 	if err != nil {
 		klog.Errorf("fetch shared image failed, err:%v", err)
@@ -220,7 +226,7 @@ func (mgr *ImagePackMgr) getPublicImages(c *gin.Context) []*model.Image {
 	imageShares, err := imageShareQuery.WithContext(c).
 		Preload(imageShareQuery.Image).
 		Preload(imageShareQuery.Image.User).
-		Where(imageShareQuery.AccountID.Eq(model.DefaultAccountID)).
+		Where(imageShareQuery.AccountID.Eq(model.DefaultAccountID), util.NotExpired(imageShareQuery.ExpiresAt)).
 		Find()
 	if err != nil {
 		klog.Errorf("fetch shared image failed, err:%v", err)
@@ -239,7 +245,7 @@ func (mgr *ImagePackMgr) getAccountSharedImages(c *gin.Context, accountID uint)
 	imageShares, err := imageShareQuery.WithContext(c).
 		Preload(imageShareQuery.Image).
 		Preload(imageShareQuery.Image.User).
-		Where(imageShareQuery.AccountID.Eq(accountID)).
+		Where(imageShareQuery.AccountID.Eq(accountID), util.NotExpired(imageShareQuery.ExpiresAt)).
 		Find()
 	if err != nil {
 		klog.Errorf("fetch shared image failed, err:%v", err)
@@ -293,6 +299,7 @@ func (mgr *ImagePackMgr) DeleteImageByID(c *gin.Context) {
 		klog.Errorf("delete image entity failed! err:%v", err)
 		resputil.Error(c, "failed to delete image", resputil.NotSpecified)
 	}
+	mgr.deleteImageScans(c, imageID)
 	resputil.Success(c, "")
 }
 
@@ -364,6 +371,7 @@ func (mgr *ImagePackMgr) deleteImageByIDList(c *gin.Context, isAdminMode bool, i
 		klog.Errorf("delete image entity failed! err:%v", err)
 		flag = false
 	}
+	mgr.deleteImageScans(c, imageIDList...)
 	return flag
 }
 
@@ -391,6 +399,9 @@ func (mgr *ImagePackMgr) AdminUpdateImagePublicStatus(c *gin.Context) {
 		Where(imageAccountQuery.ImageID.Eq(req.ID)).
 		Where(imageAccountQuery.AccountID.Eq(model.DefaultAccountID)).
 		First(); err != nil {
+		if !mgr.checkPublishAllowed(c, req.ID) {
+			return
+		}
 		imageAccountEntity := &model.ImageAccount{
 			ImageID:   req.ID,
 			AccountID: model.DefaultAccountID,
@@ -655,15 +666,23 @@ func (mgr *ImagePackMgr) UserShareImage(c *gin.Context) {
 	if !mgr.requireImageOwner(c, req.ImageID) {
 		return
 	}
+	// sharing with the default account makes the image public, so it is subject to the scan policy
+	if req.Type != "user" && slices.Contains(req.IDList, model.DefaultAccountID) && !mgr.checkPublishAllowed(c, req.ImageID) {
+		return
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		resputil.BadRequestError(c, "the expiry must be in the future")
+		return
+	}
 	for _, id := range req.IDList {
 		if req.Type == "user" {
-			if err := mgr.createImageUserEntity(c, req.ImageID, id); err != nil {
+			if err := mgr.createImageUserEntity(c, req.ImageID, id, req.ExpiresAt); err != nil {
 				klog.Errorf("create image share entity failed, err %v", err)
 				resputil.Error(c, fmt.Sprintf("%+v", err), resputil.NotSpecified)
 				return
 			}
 		} else {
-			if err := mgr.createImageAccountEntity(c, req.ImageID, id); err != nil {
+			if err := mgr.createImageAccountEntity(c, req.ImageID, id, req.ExpiresAt); err != nil {
 				klog.Errorf("create image share entity failed, err %v", err)
 				resputil.Error(c, fmt.Sprintf("%+v", err), resputil.NotSpecified)
 				return
@@ -674,7 +693,7 @@ func (mgr *ImagePackMgr) UserShareImage(c *gin.Context) {
 }
 
 //nolint:dupl // ignore duplicate code
-func (mgr *ImagePackMgr) createImageAccountEntity(c *gin.Context, imageID, accountID uint) error {
+func (mgr *ImagePackMgr) createImageAccountEntity(c *gin.Context, imageID, accountID uint, expiresAt *time.Time) error {
 	accountImageQuery := query.ImageAccount
 	accountQuery := query.Account
 	if _, err := accountQuery.WithContext(c).Where(accountQuery.ID.Eq(accountID)).First(); err != nil {
@@ -684,14 +703,23 @@ func (mgr *ImagePackMgr) createImageAccountEntity(c *gin.Context, imageID, accou
 	imageShareEntity, _ := accountImageQuery.WithContext(c).
 		Where(accountImageQuery.ImageID.Eq(imageID)).
 		Where(accountImageQuery.AccountID.Eq(accountID)).
+		Where(util.NotExpired(accountImageQuery.ExpiresAt)).
 		First()
 	if imageShareEntity != nil {
 		return fmt.Errorf("image has been shared to this account")
 	}
+	// renew an expired share the cron job has not revoked yet instead of adding a second one
+	if expired, err := accountImageQuery.WithContext(c).
+		Where(accountImageQuery.ImageID.Eq(imageID), accountImageQuery.AccountID.Eq(accountID)).
+		First(); err == nil {
+		_, err = accountImageQuery.WithContext(c).Where(accountImageQuery.ID.Eq(expired.ID)).Update(accountImageQuery.ExpiresAt, expiresAt)
+		return err
+	}
 	// create a new image share entity
 	imageShareEntity = &model.ImageAccount{
 		ImageID:   imageID,
 		AccountID: accountID,
+		ExpiresAt: expiresAt,
 	}
 	if err := accountImageQuery.WithContext(c).Create(imageShareEntity); err != nil {
 		return err
@@ -700,7 +728,7 @@ func (mgr *ImagePackMgr) createImageAccountEntity(c *gin.Context, imageID, accou
 }
 
 //nolint:dupl // ignore duplicate code
-func (mgr *ImagePackMgr) createImageUserEntity(c *gin.Context, imageID, userID uint) error {
+func (mgr *ImagePackMgr) createImageUserEntity(c *gin.Context, imageID, userID uint, expiresAt *time.Time) error {
 	userImageQuery := query.ImageUser
 	userQuery := query.User
 	// check if the user exists
@@ -711,14 +739,23 @@ func (mgr *ImagePackMgr) createImageUserEntity(c *gin.Context, imageID, userID u
 	imageShareEntity, _ := userImageQuery.WithContext(c).
 		Where(userImageQuery.ImageID.Eq(imageID)).
 		Where(userImageQuery.UserID.Eq(userID)).
+		Where(util.NotExpired(userImageQuery.ExpiresAt)).
 		First()
 	if imageShareEntity != nil {
 		return fmt.Errorf("image has been shared to this user")
 	}
+	// renew an expired share the cron job has not revoked yet instead of adding a second one
+	if expired, err := userImageQuery.WithContext(c).
+		Where(userImageQuery.ImageID.Eq(imageID), userImageQuery.UserID.Eq(userID)).
+		First(); err == nil {
+		_, err = userImageQuery.WithContext(c).Where(userImageQuery.ID.Eq(expired.ID)).Update(userImageQuery.ExpiresAt, expiresAt)
+		return err
+	}
 	// create a new image share entity
 	imageShareEntity = &model.ImageUser{
-		ImageID: imageID,
-		UserID:  userID,
+		ImageID:   imageID,
+		UserID:    userID,
+		ExpiresAt: expiresAt,
 	}
 	if err := userImageQuery.WithContext(c).Create(imageShareEntity); err != nil {
 		return err
@@ -776,7 +813,9 @@ func (mgr *ImagePackMgr) cancelShareImageWithAccount(c *gin.Context, imageID, ac
 		return fmt.Errorf("image hasn't been shared to this account")
 	}
 	// create a new image share entity
-	if _, err := accountImageQuery.WithContext(c).Delete(imageAccountEntity); err != nil {
+	if _, err := accountImageQuery.WithContext(c).
+		Where(accountImageQuery.ImageID.Eq(imageID), accountImageQuery.AccountID.Eq(accountID)).
+		Delete(); err != nil {
 		return fmt.Errorf("cancel share with account failed: %w", err)
 	}
 	return nil
@@ -799,7 +838,9 @@ func (mgr *ImagePackMgr) cancelShareImageWithUser(c *gin.Context, imageID, userI
 		return fmt.Errorf("image hasn't been shared to this user")
 	}
 	// create a new image share entity
-	if _, err := userImageQuery.WithContext(c).Delete(imageUserEntity); err != nil {
+	if _, err := userImageQuery.WithContext(c).
+		Where(userImageQuery.ImageID.Eq(imageID), userImageQuery.UserID.Eq(userID)).
+		Delete(); err != nil {
 		return fmt.Errorf("cancel share with user failed: %w", err)
 	}
 	return nil
@@ -829,7 +870,7 @@ func (mgr *ImagePackMgr) GetImageGrantedUserOrAccount(c *gin.Context) {
 	imageAccountQuery := query.ImageAccount
 	imageAccounts, err := imageAccountQuery.WithContext(c).
 		Preload(imageAccountQuery.Account).
-		Where(imageAccountQuery.ImageID.Eq(req.ImageID)).
+		Where(imageAccountQuery.ImageID.Eq(req.ImageID), util.NotExpired(imageAccountQuery.ExpiresAt)).
 		Find()
 	if err != nil {
 		klog.Errorf("fetch image share with account failed, err:%v", err)
@@ -838,8 +879,9 @@ func (mgr *ImagePackMgr) GetImageGrantedUserOrAccount(c *gin.Context) {
 	}
 	for _, ia := range imageAccounts {
 		grangtedAccounts = append(grangtedAccounts, ImageGrantedAccounts{
-			ID:   ia.Account.ID,
-			Name: ia.Account.Nickname,
+			ID:        ia.Account.ID,
+			Name:      ia.Account.Nickname,
+			ExpiresAt: ia.ExpiresAt,
 		})
 	}
 
@@ -847,7 +889,7 @@ func (mgr *ImagePackMgr) GetImageGrantedUserOrAccount(c *gin.Context) {
 	imageUserQuery := query.ImageUser
 	imageUsers, err := imageUserQuery.WithContext(c).
 		Preload(imageUserQuery.User).
-		Where(imageUserQuery.ImageID.Eq(req.ImageID)).
+		Where(imageUserQuery.ImageID.Eq(req.ImageID), util.NotExpired(imageUserQuery.ExpiresAt)).
 		Find()
 	if err != nil {
 		klog.Errorf("fetch image share with user failed, err:%v", err)
@@ -856,9 +898,10 @@ func (mgr *ImagePackMgr) GetImageGrantedUserOrAccount(c *gin.Context) {
 	}
 	for _, iu := range imageUsers {
 		grantedUsers = append(grantedUsers, ImageGrantedUsers{
-			ID:       iu.User.ID,
-			Name:     iu.User.Name,
-			Nickname: iu.User.Nickname,
+			ID:        iu.User.ID,
+			Name:      iu.User.Name,
+			Nickname:  iu.User.Nickname,
+			ExpiresAt: iu.ExpiresAt,
 		})
 	}
 	resputil.Success(c, ImageGrantResponse{UserList: grantedUsers, AccountList: grangtedAccounts})
@@ -887,7 +930,7 @@ func (mgr *ImagePackMgr) UserGetImageUngrantedAccounts(c *gin.Context) {
 	sharedAccountIDs := []uint{}
 	imageAccountQuery := query.ImageAccount
 	if err := imageAccountQuery.WithContext(c).
-		Where(imageAccountQuery.ImageID.Eq(req.ImageID)).
+		Where(imageAccountQuery.ImageID.Eq(req.ImageID), util.NotExpired(imageAccountQuery.ExpiresAt)).
 		Pluck(imageAccountQuery.AccountID, &sharedAccountIDs); err != nil {
 		klog.Errorf("query shared account ids failed, err:%v", err)
 		resputil.Error(c, "query shared account ids failed", resputil.NotSpecified)
@@ -951,7 +994,7 @@ func (mgr *ImagePackMgr) UserSearchUngrantedUsers(c *gin.Context) {
 	sharedUserIDs := []uint{}
 	imageUserQuery := query.ImageUser
 	if err := imageUserQuery.WithContext(c).
-		Where(imageUserQuery.ImageID.Eq(req.ImageID)).
+		Where(imageUserQuery.ImageID.Eq(req.ImageID), util.NotExpired(imageUserQuery.ExpiresAt)).
 		Pluck(imageUserQuery.UserID, &sharedUserIDs); err != nil {
 		resputil.Error(c, "query shared user ids failed", resputil.NotSpecified)
 		return
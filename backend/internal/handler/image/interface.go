@@ -8,6 +8,7 @@ import (
 	imrocreq "github.com/imroc/req/v3"
 
 	"github.com/raids-lab/crater/internal/handler"
+	"github.com/raids-lab/crater/internal/service"
 	"github.com/raids-lab/crater/pkg/config"
 	"github.com/raids-lab/crater/pkg/crclient"
 	"github.com/raids-lab/crater/pkg/imageregistry"
@@ -24,6 +25,7 @@ type ImagePackMgr struct {
 	imagepackClient *crclient.ImagePackController
 	imagePacker     packer.ImagePackerInterface
 	imageRegistry   imageregistry.ImageRegistryInterface
+	configService   *service.ConfigService
 	req             *imrocreq.Client
 }
 
@@ -35,6 +37,10 @@ func (mgr *ImagePackMgr) RegisterProtected(g *gin.RouterGroup) {
 	g.GET("/kaniko", mgr.UserListKaniko)
 	g.POST("/kaniko", mgr.UserCreateByPipApt)
 	g.POST("/dockerfile", mgr.UserCreateByDockerfile)
+	g.POST("/git", mgr.UserCreateByGit)
+	g.GET("/git/credential", mgr.UserListGitCredentials)
+	g.POST("/git/credential", mgr.UserSaveGitCredential)
+	g.DELETE("/git/credential/:name", mgr.UserDeleteGitCredential)
 	g.POST("/envd", mgr.UserCreateByEnvd)
 	g.POST("/remove", mgr.UserRemoveKanikoByID)
 
@@ -64,6 +70,8 @@ func (mgr *ImagePackMgr) RegisterProtected(g *gin.RouterGroup) {
 	g.GET("/account", mgr.UserGetImageUngrantedAccounts)
 	g.GET("/cudabaseimage", mgr.UserGetCudaBaseImages)
 	g.POST("/arch", mgr.UserUpdateImageArch)
+	g.GET("/scan/:id", mgr.UserGetImageScan)
+	g.POST("/scan/:id", mgr.UserRescanImage)
 }
 
 func (mgr *ImagePackMgr) RegisterAdmin(g *gin.RouterGroup) {
@@ -78,6 +86,9 @@ func (mgr *ImagePackMgr) RegisterAdmin(g *gin.RouterGroup) {
 	g.POST("/arch", mgr.AdminUpdateImageArch)
 	g.POST("/cudabaseimage", mgr.AdminAddCudaBaseImage)
 	g.DELETE("/cudabaseimage/:id", mgr.AdminDeleteCudaBaseImage)
+	g.GET("/scan/:id", mgr.AdminGetImageScan)
+	g.POST("/scan/:id", mgr.AdminRescanImage)
+	g.GET("/gc", mgr.AdminGetImageGCReport)
 }
 
 func NewImagePackMgr(conf *handler.RegisterConfig) handler.Manager {
@@ -86,6 +97,7 @@ func NewImagePackMgr(conf *handler.RegisterConfig) handler.Manager {
 		imagepackClient: &crclient.ImagePackController{Client: conf.Client},
 		imagePacker:     conf.ImagePacker,
 		imageRegistry:   conf.ImageRegistry,
+		configService:   conf.ConfigService,
 		req:             imrocreq.C(),
 	}
 }
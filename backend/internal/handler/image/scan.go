@@ -0,0 +1,237 @@
+package image
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"k8s.io/klog/v2"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/bizerr"
+	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/internal/util"
+	"github.com/raids-lab/crater/pkg/imagescan"
+)
+
+// UserGetImageScan godoc
+//
+//	@Summary		获取镜像扫描报告
+//	@Description	返回镜像的软件包与漏洞扫描结果，镜像需为本人所有、被分享或公开
+//	@Tags			ImagePack
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			id			path		uint	true	"镜像ID"
+//	@Param			packages	query		bool	false	"是否返回软件包清单"
+//	@Success		200			{object}	resputil.Response[ImageScanResp]
+//	@Router			/v1/images/scan/{id} [GET]
+func (mgr *ImagePackMgr) UserGetImageScan(c *gin.Context) {
+	mgr.getImageScan(c, false)
+}
+
+// AdminGetImageScan godoc
+//
+//	@Summary		管理员获取镜像扫描报告
+//	@Description	返回任意镜像的软件包与漏洞扫描结果
+//	@Tags			ImagePack
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			id			path		uint	true	"镜像ID"
+//	@Param			packages	query		bool	false	"是否返回软件包清单"
+//	@Success		200			{object}	resputil.Response[ImageScanResp]
+//	@Router			/v1/admin/images/scan/{id} [GET]
+func (mgr *ImagePackMgr) AdminGetImageScan(c *gin.Context) {
+	mgr.getImageScan(c, true)
+}
+
+// UserRescanImage godoc
+//
+//	@Summary		重新扫描镜像
+//	@Description	为本人的镜像排队一次新的扫描
+//	@Tags			ImagePack
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			id	path		uint	true	"镜像ID"
+//	@Success		200	{object}	resputil.Response[string]
+//	@Router			/v1/images/scan/{id} [POST]
+func (mgr *ImagePackMgr) UserRescanImage(c *gin.Context) {
+	mgr.rescanImage(c, false)
+}
+
+// AdminRescanImage godoc
+//
+//	@Summary		管理员重新扫描镜像
+//	@Description	为任意镜像排队一次新的扫描
+//	@Tags			ImagePack
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			id	path		uint	true	"镜像ID"
+//	@Success		200	{object}	resputil.Response[string]
+//	@Router			/v1/admin/images/scan/{id} [POST]
+func (mgr *ImagePackMgr) AdminRescanImage(c *gin.Context) {
+	mgr.rescanImage(c, true)
+}
+
+func (mgr *ImagePackMgr) getImageScan(c *gin.Context, isAdminMode bool) {
+	var req ImageScanRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.InvalidRequest.Wrap(err, "invalid image id"))
+		return
+	}
+	var params GetImageScanQuery
+	if err := c.ShouldBindQuery(&params); err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.ParameterError.Wrap(err, "invalid scan query"))
+		return
+	}
+	if !isAdminMode && !mgr.requireImageVisible(c, req.ID) {
+		return
+	}
+
+	scanQuery := query.ImageScan
+	scan, err := scanQuery.WithContext(c).Where(scanQuery.ImageID.Eq(req.ID)).First()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		resputil.HandleError(c, bizerr.NotFound.DataBaseNotFound.New("the image has not been scanned"))
+		return
+	} else if err != nil {
+		resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "get image scan failed"))
+		return
+	}
+
+	resp := ImageScanResp{
+		ImageID:         scan.ImageID,
+		ImageLink:       scan.ImageLink,
+		Digest:          scan.Digest,
+		Status:          scan.Status,
+		Message:         scan.Message,
+		RequestedAt:     scan.RequestedAt,
+		ScannedAt:       scan.ScannedAt,
+		OS:              scan.OS,
+		Arch:            scan.Arch,
+		PackageCount:    scan.PackageCount,
+		Critical:        scan.Critical,
+		High:            scan.High,
+		Medium:          scan.Medium,
+		Low:             scan.Low,
+		Unknown:         scan.Unknown,
+		MaxSeverity:     scan.MaxSeverity,
+		DatabaseVersion: scan.DatabaseVersion,
+		Findings:        scan.Findings.Data(),
+	}
+	if resp.Findings == nil {
+		resp.Findings = []model.ImageScanFinding{}
+	}
+	if params.Packages {
+		resp.Packages = scan.Packages.Data()
+	}
+	resputil.Success(c, resp)
+}
+
+func (mgr *ImagePackMgr) rescanImage(c *gin.Context, isAdminMode bool) {
+	var req ImageScanRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.InvalidRequest.Wrap(err, "invalid image id"))
+		return
+	}
+	if !isAdminMode && !mgr.requireImageOwner(c, req.ID) {
+		return
+	}
+	imageQuery := query.Image
+	image, err := imageQuery.WithContext(c).Where(imageQuery.ID.Eq(req.ID)).First()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		resputil.HandleError(c, bizerr.NotFound.DataBaseNotFound.New("image not found"))
+		return
+	} else if err != nil {
+		resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "get image failed"))
+		return
+	}
+	if err = imagescan.RequestScan(c, image.ID, image.ImageLink); errors.Is(err, imagescan.ErrScanDisabled) {
+		resputil.HandleError(c, bizerr.ServiceError.ServiceUnavailable.Wrap(err, "image scanning is not enabled"))
+		return
+	} else if err != nil {
+		resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "request image scan failed"))
+		return
+	}
+	resputil.Success(c, "image scan requested")
+}
+
+// requireImageVisible allows the owner, the users and accounts the image is shared with,
+// and everyone once the image is public.
+func (mgr *ImagePackMgr) requireImageVisible(c *gin.Context, imageID uint) bool {
+	token := util.GetToken(c)
+	imageQuery := query.Image
+	image, err := imageQuery.WithContext(c).Where(imageQuery.ID.Eq(imageID)).First()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		resputil.HandleError(c, bizerr.NotFound.DataBaseNotFound.New("image not found"))
+		return false
+	} else if err != nil {
+		resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "get image failed"))
+		return false
+	}
+	// TODO: 移除IsPublic的兼容代码
+	if image.UserID == token.UserID || image.IsPublic {
+		return true
+	}
+
+	imageAccountQuery := query.ImageAccount
+	accountShares, err := imageAccountQuery.WithContext(c).
+		Where(imageAccountQuery.ImageID.Eq(imageID), util.NotExpired(imageAccountQuery.ExpiresAt)).
+		Where(imageAccountQuery.AccountID.In(model.DefaultAccountID, token.AccountID)).
+		Count()
+	if err != nil {
+		resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "check image share failed"))
+		return false
+	}
+	imageUserQuery := query.ImageUser
+	userShares, err := imageUserQuery.WithContext(c).
+		Where(imageUserQuery.ImageID.Eq(imageID), util.NotExpired(imageUserQuery.ExpiresAt)).
+		Where(imageUserQuery.UserID.Eq(token.UserID)).
+		Count()
+	if err != nil {
+		resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "check image share failed"))
+		return false
+	}
+	if accountShares+userShares == 0 {
+		resputil.HandleError(c, bizerr.Forbidden.PermissionDenied.New("permission denied to view this image"))
+		return false
+	}
+	return true
+}
+
+// checkPublishAllowed enforces the admin's scan severity policy before an image is made public.
+func (mgr *ImagePackMgr) checkPublishAllowed(c *gin.Context, imageID uint) bool {
+	threshold, err := mgr.configService.GetImageScanBlockSeverity(c)
+	if err != nil {
+		resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "get image scan policy failed"))
+		return false
+	}
+	if threshold == "" {
+		return true
+	}
+	scanQuery := query.ImageScan
+	scan, err := scanQuery.WithContext(c).Where(scanQuery.ImageID.Eq(imageID)).First()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		scan = nil
+	} else if err != nil {
+		resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "get image scan failed"))
+		return false
+	}
+	if reason, blocked := imagescan.BlocksPublishing(threshold, scan); blocked {
+		klog.Infof("publishing image %d blocked: %s", imageID, reason)
+		resputil.HandleError(c, bizerr.Conflict.ResourceStatusError.New("cannot make the image public: "+reason))
+		return false
+	}
+	return true
+}
+
+// deleteImageScans drops the scan reports of those images that no longer exist.
+func (mgr *ImagePackMgr) deleteImageScans(c *gin.Context, imageIDList ...uint) {
+	iq := query.Image
+	sq := query.ImageScan
+	if _, err := sq.WithContext(c).
+		Where(sq.ImageID.In(imageIDList...)).
+		Where(sq.Columns(sq.ImageID).NotIn(iq.WithContext(c).Select(iq.ID).Where(iq.ID.In(imageIDList...)))).
+		Delete(); err != nil {
+		klog.Errorf("delete image scan entity failed! err:%v", err)
+	}
+}
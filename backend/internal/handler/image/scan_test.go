@@ -0,0 +1,180 @@
+package image
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/service"
+	"github.com/raids-lab/crater/internal/util"
+)
+
+func TestImageScanRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mgr := &ImagePackMgr{}
+	mgr.RegisterProtected(router.Group("/api/v1/images"))
+	mgr.RegisterAdmin(router.Group("/api/v1/admin/images"))
+
+	routes := make(map[string]bool)
+	for _, route := range router.Routes() {
+		routes[route.Method+" "+route.Path] = true
+	}
+	for _, route := range []string{
+		http.MethodGet + " /api/v1/images/scan/:id",
+		http.MethodPost + " /api/v1/images/scan/:id",
+		http.MethodGet + " /api/v1/admin/images/scan/:id",
+		http.MethodPost + " /api/v1/admin/images/scan/:id",
+	} {
+		if !routes[route] {
+			t.Errorf("missing route %s", route)
+		}
+	}
+}
+
+func newImageScanTestMgr(t *testing.T, databaseName string) *ImagePackMgr {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+databaseName+"?mode=memory&cache=shared"), &gorm.Config{
+		DisableForeignKeyConstraintWhenMigrating: true,
+		IgnoreRelationshipsWhenMigrating:         true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = db.AutoMigrate(
+		&model.Image{}, &model.ImageScan{}, &model.ImageAccount{}, &model.Account{}, &model.SystemConfig{}, &model.PrequeueConfig{},
+	); err != nil {
+		t.Fatal(err)
+	}
+	query.SetDefault(db)
+	return &ImagePackMgr{configService: service.NewConfigService(query.Use(db))}
+}
+
+func TestCheckPublishAllowed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mgr := newImageScanTestMgr(t, "image_scan_publish")
+	scans := []*model.ImageScan{
+		{ImageID: 1, Status: model.ImageScanSucceeded, DatabaseVersion: "v1", MaxSeverity: model.ScanSeverityLow},
+		{ImageID: 2, Status: model.ImageScanSucceeded, DatabaseVersion: "v1", MaxSeverity: model.ScanSeverityCritical},
+	}
+	if err := query.ImageScan.WithContext(t.Context()).Create(scans...); err != nil {
+		t.Fatal(err)
+	}
+
+	allowed := func(imageID uint) (bool, int) {
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		c.Request = httptest.NewRequest(http.MethodPost, "/", http.NoBody)
+		ok := mgr.checkPublishAllowed(c, imageID)
+		return ok, recorder.Code
+	}
+	for _, imageID := range []uint{1, 2, 3} {
+		if ok, _ := allowed(imageID); !ok {
+			t.Fatalf("image %d should be publishable without a policy", imageID)
+		}
+	}
+
+	if err := mgr.configService.UpdateImageScanBlockSeverity(t.Context(), "high"); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := allowed(1); !ok {
+		t.Fatal("image below the threshold should be publishable")
+	}
+	for _, imageID := range []uint{2, 3} {
+		if ok, code := allowed(imageID); ok || code != http.StatusConflict {
+			t.Fatalf("image %d should be blocked with a conflict, got %v %d", imageID, ok, code)
+		}
+	}
+}
+
+func TestDeleteImageScansKeepsExistingImages(t *testing.T) {
+	mgr := newImageScanTestMgr(t, "image_scan_delete")
+	images := []*model.Image{{ImageLink: "registry/a:v1"}, {ImageLink: "registry/b:v1"}}
+	if err := query.Image.WithContext(t.Context()).Create(images...); err != nil {
+		t.Fatal(err)
+	}
+	for _, image := range images {
+		if err := query.ImageScan.WithContext(t.Context()).
+			Create(&model.ImageScan{ImageID: image.ID, ImageLink: image.ImageLink}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := query.Image.WithContext(t.Context()).Where(query.Image.ID.Eq(images[0].ID)).Delete(); err != nil {
+		t.Fatal(err)
+	}
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/", http.NoBody)
+	mgr.deleteImageScans(c, images[0].ID, images[1].ID)
+
+	remaining, err := query.ImageScan.WithContext(t.Context()).Find()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(remaining) != 1 || remaining[0].ImageID != images[1].ID {
+		t.Fatalf("unexpected remaining scans: %+v", remaining)
+	}
+}
+
+func TestUserShareImageWithDefaultAccount(t *testing.T) {
+	mgr := newImageScanTestMgr(t, "image_share_default_account")
+	images := []*model.Image{{UserID: 7, ImageLink: "registry/a:v1"}, {UserID: 7, ImageLink: "registry/b:v1"}}
+	if err := query.Image.WithContext(t.Context()).Create(images...); err != nil {
+		t.Fatal(err)
+	}
+	for _, account := range []*model.Account{{Name: "default", Space: "/"}, {Name: "vision", Space: "/q/vision"}} {
+		if err := query.Account.WithContext(t.Context()).Create(account); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	share := func(imageID uint, accountIDs string) int {
+		recorder := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(recorder)
+		body := fmt.Sprintf(`{"imageID":%d,"type":"account","idList":[%s]}`, imageID, accountIDs)
+		c.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		util.SetJWTContext(c, util.JWTMessage{UserID: 7, RolePlatform: model.RoleUser})
+		mgr.UserShareImage(c)
+		return recorder.Code
+	}
+	sharedWith := func(imageID uint) []uint {
+		var accountIDs []uint
+		if err := query.ImageAccount.WithContext(t.Context()).
+			Where(query.ImageAccount.ImageID.Eq(imageID)).
+			Order(query.ImageAccount.AccountID).
+			Pluck(query.ImageAccount.AccountID, &accountIDs); err != nil {
+			t.Fatal(err)
+		}
+		return accountIDs
+	}
+
+	defaultAndVision := fmt.Sprintf("%d,2", model.DefaultAccountID)
+	if code := share(images[0].ID, defaultAndVision); code != http.StatusOK {
+		t.Fatalf("sharing with the default account without a policy = %d, want %d", code, http.StatusOK)
+	}
+	if got := sharedWith(images[0].ID); !slices.Equal(got, []uint{model.DefaultAccountID, 2}) {
+		t.Fatalf("image shared with accounts %v, want [%d 2]", got, model.DefaultAccountID)
+	}
+
+	if err := mgr.configService.UpdateImageScanBlockSeverity(t.Context(), "high"); err != nil {
+		t.Fatal(err)
+	}
+	if code := share(images[1].ID, defaultAndVision); code != http.StatusConflict {
+		t.Fatalf("sharing an unscanned image with the default account = %d, want %d", code, http.StatusConflict)
+	}
+	if code := share(images[1].ID, "2"); code != http.StatusOK {
+		t.Fatalf("sharing with an account = %d, want %d", code, http.StatusOK)
+	}
+	if got := sharedWith(images[1].ID); !slices.Equal(got, []uint{2}) {
+		t.Fatalf("image shared with accounts %v, want only [2]", got)
+	}
+}
@@ -5,6 +5,8 @@ import (
 
 	"github.com/raids-lab/crater/dao/model"
 	"github.com/raids-lab/crater/internal/util"
+	"github.com/raids-lab/crater/pkg/cleaner"
+	"github.com/raids-lab/crater/pkg/packer"
 )
 
 type (
@@ -18,6 +20,7 @@ type (
 		Tags               []string `json:"tags"`
 		Template           string   `json:"template"`
 		Archs              []string `json:"archs"`
+		NoCache            bool     `json:"noCache"`
 	}
 
 	CreateByDockerfileRequest struct {
@@ -29,6 +32,21 @@ type (
 		Template     string             `json:"template"`
 		Archs        []string           `json:"archs"`
 		VolumeMounts []util.VolumeMount `json:"volumeMounts,omitempty"`
+		NoCache      bool               `json:"noCache"`
+	}
+
+	CreateByGitRequest struct {
+		Description      string   `json:"description"`
+		Repo             string   `json:"repo" binding:"required"`
+		Ref              string   `json:"ref"`
+		Subdir           string   `json:"subdir"`
+		Dockerfile       string   `json:"dockerfile"`
+		CredentialSecret string   `json:"credentialSecret"`
+		ImageName        string   `json:"name"`
+		ImageTag         string   `json:"tag"`
+		Tags             []string `json:"tags"`
+		Archs            []string `json:"archs"`
+		NoCache          bool     `json:"noCache"`
 	}
 
 	CreateByEnvdRequest struct {
@@ -112,9 +130,10 @@ type (
 	}
 
 	ShareImageRequest struct {
-		IDList  []uint `json:"idList"`
-		ImageID uint   `json:"imageID"`
-		Type    string `json:"type"` // user: share with user, account: share with account
+		IDList    []uint     `json:"idList"`
+		ImageID   uint       `json:"imageID"`
+		Type      string     `json:"type"`      // user: share with user, account: share with account
+		ExpiresAt *time.Time `json:"expiresAt"` // nil keeps the share until it is canceled
 	}
 
 	CancelShareImageRequest struct {
@@ -270,6 +289,8 @@ type (
 		BuildSource  model.BuildSource
 		Archs        []string
 		VolumeMounts []util.VolumeMount
+		NoCache      bool
+		Git          *packer.GitSource
 	}
 
 	EnvdBuildData struct {
@@ -288,13 +309,15 @@ type (
 	}
 
 	ImageGrantedUsers struct {
-		Nickname string `json:"nickname"`
-		Name     string `json:"name"`
-		ID       uint   `json:"id"`
+		Nickname  string     `json:"nickname"`
+		Name      string     `json:"name"`
+		ID        uint       `json:"id"`
+		ExpiresAt *time.Time `json:"expiresAt,omitempty"`
 	}
 	ImageGrantedAccounts struct {
-		Name string `json:"name"`
-		ID   uint   `json:"id"`
+		Name      string     `json:"name"`
+		ID        uint       `json:"id"`
+		ExpiresAt *time.Time `json:"expiresAt,omitempty"`
 	}
 
 	CudaBaseImage struct {
@@ -303,4 +326,41 @@ type (
 		ImageLabel string `json:"imageLabel"`
 		Value      string `json:"value"`
 	}
+
+	ImageScanRequest struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+
+	GetImageScanQuery struct {
+		Packages bool `form:"packages"`
+	}
+
+	ImageScanResp struct {
+		ImageID         uint                     `json:"imageId"`
+		ImageLink       string                   `json:"imageLink"`
+		Digest          string                   `json:"digest"`
+		Status          model.ImageScanStatus    `json:"status"`
+		Message         string                   `json:"message"`
+		RequestedAt     time.Time                `json:"requestedAt"`
+		ScannedAt       *time.Time               `json:"scannedAt"`
+		OS              string                   `json:"os"`
+		Arch            string                   `json:"arch"`
+		PackageCount    int                      `json:"packageCount"`
+		Critical        int                      `json:"critical"`
+		High            int                      `json:"high"`
+		Medium          int                      `json:"medium"`
+		Low             int                      `json:"low"`
+		Unknown         int                      `json:"unknown"`
+		MaxSeverity     model.ScanSeverity       `json:"maxSeverity"`
+		DatabaseVersion string                   `json:"databaseVersion"`
+		Findings        []model.ImageScanFinding `json:"findings"`
+		Packages        []model.ImageScanPackage `json:"packages,omitempty"`
+	}
 )
+
+// ImageGCReportResp is the dry run of the clean-stale-images cron job.
+type ImageGCReportResp struct {
+	Schedule  string                    `json:"schedule"`
+	JobStatus model.CronJobConfigStatus `json:"jobStatus"`
+	*cleaner.ImageGCReport
+}
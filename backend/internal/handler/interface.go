@@ -54,10 +54,13 @@ type RegisterConfig struct {
 	PrequeueWatcher *prequeuewatcher.PrequeueWatcher
 
 	// services
-	ConfigService      *service.ConfigService
-	PrequeueService    *service.PrequeueService
-	BillingService     *service.BillingService
-	GpuAnalysisService *service.GpuAnalysisService
+	ConfigService       *service.ConfigService
+	PrequeueService     *service.PrequeueService
+	BillingService      *service.BillingService
+	GpuAnalysisService  *service.GpuAnalysisService
+	WorkflowService     *service.WorkflowService
+	JobArrayService     *service.JobArrayService
+	NotificationService *service.NotificationService
 }
 
 // Registers is a slice of Manager Init functions.
@@ -89,7 +89,9 @@ func (mgr *ModelDownloadMgr) RegisterAdmin(g *gin.RouterGroup) {
 
 // Request/Response types
 type CreateDownloadReq struct {
-	Name     string `json:"name" binding:"required"`
+	// Name is required for hub sources. Bucket and URL sources derive it from
+	// URL when omitted.
+	Name     string `json:"name"`
 	Revision string `json:"revision"`
 	Source   string `json:"source"`
 	Category string `json:"category" binding:"required,oneof=model dataset"`
@@ -97,6 +99,19 @@ type CreateDownloadReq struct {
 	// source site. It is only forwarded to the download Job as an env var and is
 	// never persisted on the (shared, deduplicated) download record.
 	Token string `json:"token"`
+	// URL addresses s3 (s3://bucket/prefix) and http (http(s)://host/file) sources.
+	URL string `json:"url"`
+	// Endpoint and Region select an S3-compatible service such as MinIO.
+	Endpoint string `json:"endpoint"`
+	Region   string `json:"region"`
+	// AccessKey and SecretKey are stored encrypted so retries and resumes can
+	// reuse them; they are dropped once the download is ready.
+	AccessKey string `json:"accessKey"`
+	SecretKey string `json:"secretKey"`
+	// Extract unpacks a zip or tar archive fetched from an http source.
+	Extract bool `json:"extract"`
+	// Checksum is the expected sha256 of the file fetched from an http source.
+	Checksum string `json:"checksum"`
 }
 
 type DownloadActionReq struct {
@@ -139,6 +154,8 @@ type ModelDownloadResp struct {
 	ModelType       string           `json:"modelType"`
 	ParameterCount  int64            `json:"parameterCount"`
 	SourceCreatedAt *time.Time       `json:"sourceCreatedAt"`
+	Extract         bool             `json:"extract"`
+	Checksum        string           `json:"checksum"`
 }
 
 const (
@@ -472,6 +489,7 @@ func (mgr *ModelDownloadMgr) resetFailedDownload(
 func (mgr *ModelDownloadMgr) getOrCreateDownload(
 	c *gin.Context, req *CreateDownloadReq, token util.JWTMessage,
 	source model.ModelSource, category model.DownloadCategory, downloadPath, revision string,
+	remote *model.ModelDownload,
 ) (*model.ModelDownload, bool, error) {
 	db := query.Use(query.GetDB())
 	var download *model.ModelDownload
@@ -512,6 +530,9 @@ func (mgr *ModelDownloadMgr) getOrCreateDownload(
 			}
 			download, isNewDownload = restored, shouldSubmitRestoredDownload(restored)
 			if isNewDownload {
+				if err := updateRemoteDownloadSource(c, tx, restored, remote); err != nil {
+					return err
+				}
 				return mgr.quotaService.Reserve(
 					c, tx.ModelDownload.WithContext(c).UnderlyingDB(), token.UserID,
 					restored.ID, model.ModelDownloadSubmissionCreate,
@@ -530,6 +551,9 @@ func (mgr *ModelDownloadMgr) getOrCreateDownload(
 				return err
 			}
 			download, isNewDownload = failed, true
+			if err := updateRemoteDownloadSource(c, tx, failed, remote); err != nil {
+				return err
+			}
 			return mgr.quotaService.Reserve(
 				c, tx.ModelDownload.WithContext(c).UnderlyingDB(), token.UserID,
 				failed.ID, model.ModelDownloadSubmissionCreate,
@@ -544,6 +568,7 @@ func (mgr *ModelDownloadMgr) getOrCreateDownload(
 			Status: model.ModelDownloadStatusPending, JobName: fmt.Sprintf("model-dl-%s-%s", token.Username, uuid.New().String()[:8]),
 			CreatorID: token.UserID, ReferenceCount: 1,
 		}
+		applyRemoteDownloadSource(newDownload, remote)
 		if err := txQ.WithContext(c).Create(newDownload); err != nil {
 			return fmt.Errorf("create download failed: %w", err)
 		}
@@ -583,18 +608,33 @@ func (mgr *ModelDownloadMgr) CreateDownload(c *gin.Context) {
 		return
 	}
 
-	if !isValidModelName(req.Name) {
-		resputil.HandleError(c, bizerr.BadRequest.ParameterError.New("invalid model name format, expected: owner/model-name"))
-		return
-	}
-
 	// 设置默认来源
 	source := model.ModelSourceModelScope
 	if req.Source != "" {
 		source = model.ModelSource(req.Source)
+	} else if req.URL != "" {
+		source = remoteSourceFromURL(req.URL)
 	}
-	if source != model.ModelSourceModelScope && source != model.ModelSourceHuggingFace {
-		resputil.HandleError(c, bizerr.BadRequest.ParameterError.New("source must be modelscope or huggingface"))
+	var remote *model.ModelDownload
+	switch source {
+	case model.ModelSourceModelScope, model.ModelSourceHuggingFace:
+		if req.URL != "" {
+			resputil.HandleError(c, bizerr.BadRequest.ParameterError.New("url is only supported for s3 and http sources"))
+			return
+		}
+	case model.ModelSourceS3, model.ModelSourceHTTP:
+		var err error
+		if remote, err = prepareRemoteDownload(&req, source, token.UserID); err != nil {
+			resputil.HandleError(c, err)
+			return
+		}
+	default:
+		resputil.HandleError(c, bizerr.BadRequest.ParameterError.New("source must be modelscope, huggingface, s3 or http"))
+		return
+	}
+
+	if !isValidModelName(req.Name) {
+		resputil.HandleError(c, bizerr.BadRequest.ParameterError.New("invalid model name format, expected: owner/model-name"))
 		return
 	}
 	if len(req.Revision) > maxDownloadRevisionLength {
@@ -618,7 +658,7 @@ func (mgr *ModelDownloadMgr) CreateDownload(c *gin.Context) {
 	downloadPath := modelDownloadStoragePath(basePath, safeName)
 
 	// 在事务中获取或创建下载任务
-	download, isNewDownload, err := mgr.getOrCreateDownload(c, &req, token, source, category, downloadPath, req.Revision)
+	download, isNewDownload, err := mgr.getOrCreateDownload(c, &req, token, source, category, downloadPath, req.Revision, remote)
 	if err != nil {
 		klog.Errorf("get or create download failed: %v", err)
 		resputil.HandleError(c, err)
@@ -877,10 +917,15 @@ func (mgr *ModelDownloadMgr) RetryDownload(c *gin.Context) {
 		return
 	}
 
-	if _, err := mgr.requireManagePermission(c, req.ID); err != nil {
+	current, err := mgr.requireManagePermission(c, req.ID)
+	if err != nil {
 		resputil.HandleError(c, err)
 		return
 	}
+	if action.Revision != nil && current.Source.IsRemoteURL() {
+		resputil.HandleError(c, bizerr.BadRequest.ParameterError.New("s3 and http downloads do not have revisions"))
+		return
+	}
 
 	download, err := mgr.prepareRetryDownload(c, req.ID, token, action.Revision)
 
@@ -1315,6 +1360,10 @@ func (mgr *ModelDownloadMgr) submitDownloadJob(c *gin.Context, download *model.M
 	modelDirName := filepath.Base(physicalPath)
 
 	downloadCmd := mgr.buildDownloadCommand(download, modelDirName)
+	env, err := downloadSourceEnv(download, accessToken)
+	if err != nil {
+		return err
+	}
 	memRequest, memLimit := mgr.memoryForModel(download.Name)
 	backoffLimit := downloadJobBackoffLimit
 
@@ -1349,7 +1398,7 @@ func (mgr *ModelDownloadMgr) submitDownloadJob(c *gin.Context, download *model.M
 							Image:   mgr.getDownloadImage(download.Source),
 							Command: []string{"/bin/bash", "-c"},
 							Args:    []string{downloadCmd},
-							Env:     downloadTokenEnv(download.Source, accessToken),
+							Env:     env,
 							Resources: corev1.ResourceRequirements{
 								Requests: corev1.ResourceList{
 									corev1.ResourceCPU:    resource.MustParse("1"),
@@ -1414,6 +1463,8 @@ func downloadTokenEnv(source model.ModelSource, accessToken string) []corev1.Env
 		}
 	case model.ModelSourceModelScope:
 		return []corev1.EnvVar{{Name: "MODELSCOPE_API_TOKEN", Value: accessToken}}
+	case model.ModelSourceHTTP:
+		return []corev1.EnvVar{{Name: "DOWNLOAD_TOKEN", Value: accessToken}}
 	default:
 		return nil
 	}
@@ -1589,6 +1640,8 @@ print("[META] " + json.dumps({
 }, separators=(",", ":")), flush=True)
 PY
 `, download.Name, repoType, download.Revision, download.Name, download.Name)
+	} else if download.Source.IsRemoteURL() {
+		installCmd, totalProbeCmd, preflightCmd, downloadCommand = buildRemoteDownloadScripts(download, modelDirName)
 	} else {
 		// The project image already contains these dependencies. The fallback keeps
 		// user-supplied Python images working while avoiding an unpinned installation.
@@ -1769,11 +1822,13 @@ PY
 `
 
 	// 进度监控脚本（保持你原来的逻辑）
+	// PROGRESS_DIR lets sources that stage an archive outside OUT_DIR report the
+	// bytes fetched rather than the bytes extracted.
 	progressScript := `
 monitor_progress() {
     while true; do
-        if [ -d "$OUT_DIR" ]; then
-            CURRENT_SIZE=$(du -sb "$OUT_DIR" 2>/dev/null | cut -f1 || echo 0)
+        if [ -d "${PROGRESS_DIR:-$OUT_DIR}" ]; then
+            CURRENT_SIZE=$(du -sb "${PROGRESS_DIR:-$OUT_DIR}" 2>/dev/null | cut -f1 || echo 0)
             echo "[PROGRESS] downloaded_bytes=$CURRENT_SIZE"
         fi
         sleep 5
@@ -1944,6 +1999,8 @@ func convertDownloadToResp(d *model.ModelDownload, token util.JWTMessage) ModelD
 		ModelType:       d.ModelType,
 		ParameterCount:  d.ParameterCount,
 		SourceCreatedAt: d.SourceCreatedAt,
+		Extract:         d.Extract,
+		Checksum:        d.Checksum,
 	}
 }
 
@@ -0,0 +1,497 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/bizerr"
+	"github.com/raids-lab/crater/pkg/crypto"
+)
+
+const (
+	maxRemoteURLLength   = 2048
+	maxS3RegionLength    = 64
+	boto3Version         = "1.35.36"
+	remoteRevisionPrefix = "url-"
+)
+
+var (
+	remoteNameInvalidChars = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+	sha256DigestPattern    = regexp.MustCompile(`^[0-9a-f]{64}$`)
+	archiveSuffixes        = []string{".tar.gz", ".tgz", ".tar.bz2", ".tbz2", ".tar.xz", ".txz", ".tar", ".zip"}
+)
+
+// remoteSourceFromURL infers the download source from the URL scheme when the
+// request omits it. Unknown schemes yield an empty source, which is rejected.
+func remoteSourceFromURL(raw string) model.ModelSource {
+	lower := strings.ToLower(strings.TrimSpace(raw))
+	switch {
+	case strings.HasPrefix(lower, "s3://"):
+		return model.ModelSourceS3
+	case strings.HasPrefix(lower, "http://"), strings.HasPrefix(lower, "https://"):
+		return model.ModelSourceHTTP
+	default:
+		return ""
+	}
+}
+
+// prepareRemoteDownload validates an s3 or http request and returns the source
+// fields to store on the download record. It fills in the name when omitted
+// and sets the revision to a digest of the source, so the identity checks
+// shared with hub downloads dedupe by URL. Credentialed requests are only
+// deduped with downloads of the same user.
+func prepareRemoteDownload(req *CreateDownloadReq, source model.ModelSource, userID uint) (*model.ModelDownload, error) {
+	raw := strings.TrimSpace(req.URL)
+	if raw == "" {
+		return nil, bizerr.BadRequest.MissingParameter.New("url is required for s3 and http sources")
+	}
+	if len(raw) > maxRemoteURLLength {
+		return nil, bizerr.BadRequest.ParameterError.New("url must not exceed 2048 characters")
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return nil, bizerr.BadRequest.ParameterError.New("invalid url")
+	}
+	if u.User != nil {
+		return nil, bizerr.BadRequest.ParameterError.New("url must not embed credentials; use accessKey/secretKey or token")
+	}
+	if req.Revision != "" {
+		return nil, bizerr.BadRequest.ParameterError.New("s3 and http sources do not support revision")
+	}
+
+	remote := &model.ModelDownload{RemoteURL: raw, Extract: req.Extract, SourceURL: redactRemoteURL(u)}
+	switch source {
+	case model.ModelSourceS3:
+		if err := fillS3DownloadSource(req, u, remote); err != nil {
+			return nil, err
+		}
+	case model.ModelSourceHTTP:
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return nil, bizerr.BadRequest.ParameterError.New("http source url must use http or https")
+		}
+		if req.Endpoint != "" || req.Region != "" || req.AccessKey != "" || req.SecretKey != "" {
+			return nil, bizerr.BadRequest.ParameterError.New("endpoint, region and access keys are only supported for s3 sources")
+		}
+		if remote.Checksum, err = normalizeDownloadChecksum(req.Checksum); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, bizerr.BadRequest.ParameterError.New("source must be s3 or http")
+	}
+
+	if req.Name == "" {
+		req.Name = remoteDownloadName(u)
+	}
+	var owner uint
+	if req.AccessKey != "" || req.Token != "" {
+		owner = userID
+	}
+	req.Revision = remoteDownloadRevision(source, remote, owner)
+	return remote, nil
+}
+
+func fillS3DownloadSource(req *CreateDownloadReq, u *url.URL, remote *model.ModelDownload) error {
+	if u.Scheme != "s3" {
+		return bizerr.BadRequest.ParameterError.New("s3 source url must look like s3://bucket/prefix")
+	}
+	if req.Extract || req.Checksum != "" {
+		return bizerr.BadRequest.ParameterError.New("extract and checksum are only supported for http sources")
+	}
+	if req.Token != "" {
+		return bizerr.BadRequest.ParameterError.New("s3 sources use accessKey and secretKey instead of token")
+	}
+	if (req.AccessKey == "") != (req.SecretKey == "") {
+		return bizerr.BadRequest.ParameterError.New("accessKey and secretKey must be provided together")
+	}
+	endpoint := strings.TrimRight(strings.TrimSpace(req.Endpoint), "/")
+	if endpoint != "" {
+		parsed, err := url.Parse(endpoint)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return bizerr.BadRequest.ParameterError.New("endpoint must be an http or https URL")
+		}
+	}
+	region := strings.TrimSpace(req.Region)
+	if len(region) > maxS3RegionLength {
+		return bizerr.BadRequest.ParameterError.New("region must not exceed 64 characters")
+	}
+	remote.S3Endpoint, remote.S3Region = endpoint, region
+	if req.AccessKey == "" {
+		return nil
+	}
+	var err error
+	if remote.S3AccessKey, err = crypto.Encrypt(req.AccessKey); err != nil {
+		return bizerr.Internal.DatabaseError.Wrap(err, "encrypt s3 credentials")
+	}
+	if remote.S3SecretKey, err = crypto.Encrypt(req.SecretKey); err != nil {
+		return bizerr.Internal.DatabaseError.Wrap(err, "encrypt s3 credentials")
+	}
+	return nil
+}
+
+// normalizeDownloadChecksum accepts a sha256 digest with or without the
+// "sha256:" prefix and returns it in prefixed form.
+func normalizeDownloadChecksum(value string) (string, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return "", nil
+	}
+	value = strings.TrimPrefix(value, "sha256:")
+	if !sha256DigestPattern.MatchString(value) {
+		return "", bizerr.BadRequest.ParameterError.New("checksum must be a sha256 digest, optionally prefixed with sha256:")
+	}
+	return "sha256:" + value, nil
+}
+
+// redactRemoteURL drops the query and fragment, which may carry presigned
+// credentials, from the URL shown to users.
+func redactRemoteURL(u *url.URL) string {
+	redacted := *u
+	redacted.User, redacted.RawQuery, redacted.Fragment = nil, "", ""
+	return redacted.String()
+}
+
+// remoteDownloadName derives owner/name from the bucket or host and the last
+// path segment without its archive suffix.
+func remoteDownloadName(u *url.URL) string {
+	owner := remoteNameSegment(u.Host)
+	leaf := remoteNameSegment(trimArchiveSuffix(path.Base(strings.TrimSuffix(u.Path, "/"))))
+	if leaf == "" {
+		leaf = owner
+	}
+	return owner + "/" + leaf
+}
+
+func remoteNameSegment(value string) string {
+	return strings.Trim(remoteNameInvalidChars.ReplaceAllString(value, "-"), "-.")
+}
+
+func trimArchiveSuffix(name string) string {
+	lower := strings.ToLower(name)
+	for _, suffix := range archiveSuffixes {
+		if strings.HasSuffix(lower, suffix) {
+			return name[:len(name)-len(suffix)]
+		}
+	}
+	return name
+}
+
+// remoteDownloadRevision identifies a bucket or URL download in the revision
+// column, which is part of the download identity shared with hub sources.
+// A non-zero owner keeps a download fetched with private credentials from
+// being handed to users who could not read the source themselves.
+func remoteDownloadRevision(source model.ModelSource, remote *model.ModelDownload, owner uint) string {
+	parts := []string{string(source), remote.S3Endpoint, remote.RemoteURL, strconv.FormatBool(remote.Extract)}
+	if owner != 0 {
+		parts = append(parts, "owner:"+strconv.FormatUint(uint64(owner), 10))
+	}
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\n")))
+	return remoteRevisionPrefix + hex.EncodeToString(sum[:8])
+}
+
+func applyRemoteDownloadSource(download, remote *model.ModelDownload) {
+	if remote == nil {
+		return
+	}
+	download.RemoteURL = remote.RemoteURL
+	download.SourceURL = remote.SourceURL
+	download.S3Endpoint = remote.S3Endpoint
+	download.S3Region = remote.S3Region
+	download.S3AccessKey = remote.S3AccessKey
+	download.S3SecretKey = remote.S3SecretKey
+	download.Extract = remote.Extract
+	download.Checksum = remote.Checksum
+}
+
+// updateRemoteDownloadSource stores the credentials of the request that
+// resubmits a failed or restored download, replacing those of earlier attempts.
+func updateRemoteDownloadSource(
+	c *gin.Context, txQ *query.Query, download, remote *model.ModelDownload,
+) error {
+	if remote == nil {
+		return nil
+	}
+	q := txQ.ModelDownload
+	if _, err := q.WithContext(c).Where(q.ID.Eq(download.ID)).Updates(map[string]any{
+		"remote_url":    remote.RemoteURL,
+		"source_url":    remote.SourceURL,
+		"s3_endpoint":   remote.S3Endpoint,
+		"s3_region":     remote.S3Region,
+		"s3_access_key": remote.S3AccessKey,
+		"s3_secret_key": remote.S3SecretKey,
+		"extract":       remote.Extract,
+		"checksum":      remote.Checksum,
+	}); err != nil {
+		return fmt.Errorf("update download source failed: %w", err)
+	}
+	applyRemoteDownloadSource(download, remote)
+	return nil
+}
+
+// downloadSourceEnv extends downloadTokenEnv with the decrypted credentials
+// of s3 sources.
+func downloadSourceEnv(download *model.ModelDownload, accessToken string) ([]corev1.EnvVar, error) {
+	if download.Source != model.ModelSourceS3 {
+		return downloadTokenEnv(download.Source, accessToken), nil
+	}
+	var env []corev1.EnvVar
+	if download.S3Endpoint != "" {
+		env = append(env, corev1.EnvVar{Name: "S3_ENDPOINT", Value: download.S3Endpoint})
+	}
+	if download.S3Region != "" {
+		env = append(env, corev1.EnvVar{Name: "S3_REGION", Value: download.S3Region})
+	}
+	if download.S3AccessKey == "" {
+		return env, nil
+	}
+	accessKey, err := crypto.Decrypt(download.S3AccessKey)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt s3 credentials: %w", err)
+	}
+	secretKey, err := crypto.Decrypt(download.S3SecretKey)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt s3 credentials: %w", err)
+	}
+	return append(env,
+		corev1.EnvVar{Name: "AWS_ACCESS_KEY_ID", Value: accessKey},
+		corev1.EnvVar{Name: "AWS_SECRET_ACCESS_KEY", Value: secretKey},
+	), nil
+}
+
+// buildRemoteDownloadScripts returns the install, total-size probe, preflight
+// and download steps of buildDownloadCommand for s3 and http sources.
+func buildRemoteDownloadScripts(download *model.ModelDownload, modelDirName string) (install, totalProbe, preflight, fetch string) {
+	u, err := url.Parse(download.RemoteURL)
+	if err != nil {
+		u = &url.URL{}
+	}
+	if download.Source == model.ModelSourceS3 {
+		return buildS3DownloadScripts(u.Host, strings.TrimPrefix(u.Path, "/"))
+	}
+	return buildHTTPDownloadScripts(download, remoteFileName(u), modelDirName)
+}
+
+func remoteFileName(u *url.URL) string {
+	if name := remoteNameSegment(path.Base(u.Path)); name != "" {
+		return name
+	}
+	return "download"
+}
+
+// s3ClientPython is shared by the s3 probe and download steps. Buckets are
+// read anonymously when no credentials were supplied.
+const s3ClientPython = `
+import os
+import boto3
+from botocore import UNSIGNED
+from botocore.config import Config
+from botocore.exceptions import ClientError
+
+def s3_client():
+    kwargs = {}
+    options = {}
+    if os.environ.get("S3_ENDPOINT"):
+        kwargs["endpoint_url"] = os.environ["S3_ENDPOINT"]
+        options["s3"] = {"addressing_style": "path"}
+    if os.environ.get("S3_REGION"):
+        kwargs["region_name"] = os.environ["S3_REGION"]
+    if not os.environ.get("AWS_ACCESS_KEY_ID"):
+        options["signature_version"] = UNSIGNED
+    return boto3.client("s3", config=Config(**options), **kwargs)
+
+def s3_objects(client, bucket, prefix):
+    if prefix and not prefix.endswith("/"):
+        try:
+            head = client.head_object(Bucket=bucket, Key=prefix)
+            yield {"Key": prefix, "Size": head["ContentLength"]}, os.path.basename(prefix)
+            return
+        except ClientError:
+            prefix += "/"
+    for page in client.get_paginator("list_objects_v2").paginate(Bucket=bucket, Prefix=prefix):
+        for obj in page.get("Contents") or []:
+            if not obj["Key"].endswith("/"):
+                yield obj, obj["Key"][len(prefix):]
+`
+
+func buildS3DownloadScripts(bucket, prefix string) (install, totalProbe, preflight, fetch string) {
+	install = fmt.Sprintf(
+		`if ! python -c 'import boto3' >/dev/null 2>&1; then
+    echo "boto3 is missing; installing the tested fallback version"
+    pip install --no-cache-dir 'boto3==%s'
+fi
+python -c 'import boto3; print("[TOOLS] boto3=" + boto3.__version__)'`,
+		boto3Version,
+	)
+
+	// Best-effort total-size probe so the UI can render a real progress bar.
+	totalProbe = fmt.Sprintf(`
+echo "Probing bucket prefix total size..."
+python - << 'PY' || true
+%s
+try:
+    total = sum(obj["Size"] for obj, _ in s3_objects(s3_client(), %q, %q))
+    if total > 0:
+        print(f"[TOTAL] size_bytes={total}", flush=True)
+except Exception:
+    pass
+PY
+`, s3ClientPython, bucket, prefix)
+
+	// Objects already present with the expected size are skipped, so resumed
+	// and retried Jobs only fetch what is missing.
+	fetch = fmt.Sprintf(`
+python - << 'PY'
+import sys
+%s
+out_dir = os.path.realpath(os.environ["OUT_DIR"])
+bucket, prefix = %q, %q
+client = s3_client()
+count = 0
+for obj, rel in s3_objects(client, bucket, prefix):
+    target = os.path.realpath(os.path.join(out_dir, rel))
+    if not target.startswith(out_dir + os.sep):
+        print("[WARN] skipping object outside the target directory: " + obj["Key"], flush=True)
+        continue
+    count += 1
+    if os.path.isfile(target) and os.path.getsize(target) == obj["Size"]:
+        continue
+    os.makedirs(os.path.dirname(target), exist_ok=True)
+    client.download_file(bucket, obj["Key"], target)
+if count == 0:
+    print("[ERROR] s3_no_objects: no objects found under s3://{}/{}".format(bucket, prefix), file=sys.stderr, flush=True)
+    raise SystemExit(2)
+print("[S3] objects={}".format(count), flush=True)
+PY
+`, s3ClientPython, bucket, prefix)
+	return install, totalProbe, "", fetch
+}
+
+func buildHTTPDownloadScripts(
+	download *model.ModelDownload, fileName, modelDirName string,
+) (install, totalProbe, preflight, fetch string) {
+	install = `python -c 'import sys; print("[TOOLS] python=" + sys.version.split()[0])'`
+
+	totalProbe = fmt.Sprintf(`
+echo "Probing file size..."
+python - << 'PY' || true
+import os
+import urllib.request
+
+request = urllib.request.Request(%q, method="HEAD")
+token = os.environ.get("DOWNLOAD_TOKEN", "")
+if token:
+    request.add_header("Authorization", "Bearer " + token)
+try:
+    with urllib.request.urlopen(request, timeout=20) as response:
+        total = int(response.headers.get("Content-Length") or 0)
+    if total > 0:
+        print(f"[TOTAL] size_bytes={total}", flush=True)
+except Exception:
+    pass
+PY
+`, download.RemoteURL)
+
+	// Archives are staged next to OUT_DIR so progress tracks the bytes fetched
+	// and a failed extraction can resume without fetching the archive again.
+	if download.Extract {
+		preflight = fmt.Sprintf(`
+PROGRESS_DIR="/data/.%s.partial"
+export PROGRESS_DIR
+mkdir -p "$PROGRESS_DIR"
+`, modelDirName)
+	}
+
+	extract := "False"
+	if download.Extract {
+		extract = "True"
+	}
+	fetch = fmt.Sprintf(`
+python - << 'PY'
+import hashlib
+import os
+import shutil
+import sys
+import tarfile
+import urllib.error
+import urllib.request
+import zipfile
+
+url, file_name, extract, checksum = %q, %q, %s, %q
+out_dir = os.path.realpath(os.environ["OUT_DIR"])
+stage_dir = os.environ.get("PROGRESS_DIR") or out_dir
+target = os.path.join(stage_dir, file_name)
+
+headers = {}
+token = os.environ.get("DOWNLOAD_TOKEN", "")
+if token:
+    headers["Authorization"] = "Bearer " + token
+offset = os.path.getsize(target) if os.path.isfile(target) else 0
+if offset:
+    headers["Range"] = "bytes={}-".format(offset)
+try:
+    response = urllib.request.urlopen(urllib.request.Request(url, headers=headers), timeout=60)
+except urllib.error.HTTPError as error:
+    # 416 means the partial file from an earlier attempt is already complete.
+    if error.code != 416 or not offset:
+        raise
+    response = None
+if response is not None:
+    with response:
+        mode = "ab" if offset and response.status == 206 else "wb"
+        with open(target, mode) as f:
+            shutil.copyfileobj(response, f, 1024 * 1024)
+
+if checksum:
+    algorithm, expected = checksum.split(":", 1)
+    digest = hashlib.new(algorithm)
+    with open(target, "rb") as f:
+        for chunk in iter(lambda: f.read(1024 * 1024), b""):
+            digest.update(chunk)
+    if digest.hexdigest() != expected:
+        os.remove(target)
+        print("[ERROR] checksum_mismatch: expected {} but got {}:{}".format(
+            checksum, algorithm, digest.hexdigest()), file=sys.stderr, flush=True)
+        raise SystemExit(23)
+    print("[CHECKSUM] verified " + checksum, flush=True)
+
+def check_member(name):
+    path = os.path.realpath(os.path.join(out_dir, name))
+    if path != out_dir and not path.startswith(out_dir + os.sep):
+        print("[ERROR] unsafe_archive: entry {!r} escapes the target directory".format(name), file=sys.stderr, flush=True)
+        raise SystemExit(24)
+
+if extract:
+    if zipfile.is_zipfile(target):
+        with zipfile.ZipFile(target) as archive:
+            for name in archive.namelist():
+                check_member(name)
+            archive.extractall(out_dir)
+    elif tarfile.is_tarfile(target):
+        with tarfile.open(target) as archive:
+            if hasattr(tarfile, "data_filter"):
+                archive.extractall(out_dir, filter="data")
+            else:
+                for member in archive.getmembers():
+                    check_member(member.name)
+                    if member.issym() or member.islnk():
+                        check_member(os.path.join(os.path.dirname(member.name), member.linkname))
+                archive.extractall(out_dir)
+    else:
+        print("[ERROR] unsupported_archive: {} is not a zip or tar archive".format(file_name), file=sys.stderr, flush=True)
+        raise SystemExit(24)
+    shutil.rmtree(stage_dir, ignore_errors=True)
+    print("[EXTRACT] extracted {}".format(file_name), flush=True)
+PY
+`, download.RemoteURL, fileName, extract, download.Checksum)
+	return install, totalProbe, preflight, fetch
+}
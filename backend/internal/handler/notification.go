@@ -0,0 +1,256 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/datatypes"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/bizerr"
+	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/internal/service"
+	"github.com/raids-lab/crater/internal/util"
+	"github.com/raids-lab/crater/pkg/alert"
+)
+
+//nolint:gochecknoinits // This is the standard way to register a gin handler.
+func init() {
+	Registers = append(Registers, NewNotificationMgr)
+}
+
+// NotificationMgr serves the notification rules and channel preferences of the current user.
+type NotificationMgr struct {
+	name                string
+	notificationService *service.NotificationService
+}
+
+func NewNotificationMgr(conf *RegisterConfig) Manager {
+	return &NotificationMgr{
+		name:                "notifications",
+		notificationService: conf.NotificationService,
+	}
+}
+
+func (mgr *NotificationMgr) GetName() string { return mgr.name }
+
+func (mgr *NotificationMgr) RegisterPublic(_ *gin.RouterGroup) {}
+
+func (mgr *NotificationMgr) RegisterProtected(g *gin.RouterGroup) {
+	g.GET("events", mgr.ListNotificationEvents)
+	g.GET("rules", mgr.ListNotificationRules)
+	g.PUT("rules", mgr.UpsertNotificationRule)
+	g.DELETE("rules/:accountId", mgr.DeleteNotificationRule)
+	g.GET("channels", mgr.GetNotificationChannels)
+	g.PUT("channels", mgr.UpdateNotificationChannels)
+}
+
+func (mgr *NotificationMgr) RegisterAdmin(_ *gin.RouterGroup) {}
+
+type (
+	NotificationRuleReq struct {
+		// AccountID 0 applies to every account of the user
+		AccountID            uint                        `json:"accountId"`
+		Events               []model.NotificationEvent   `json:"events"`
+		QueuedTooLongMinutes int                         `json:"queuedTooLongMinutes"`
+		BalanceLowThreshold  *service.BillingAmountInput `json:"balanceLowThreshold"`
+		QuietStart           string                      `json:"quietStart"`
+		QuietEnd             string                      `json:"quietEnd"`
+		DigestMinutes        int                         `json:"digestMinutes"`
+	}
+
+	NotificationRuleResp struct {
+		AccountID            uint                      `json:"accountId"`
+		Events               []model.NotificationEvent `json:"events"`
+		QueuedTooLongMinutes int                       `json:"queuedTooLongMinutes"`
+		BalanceLowThreshold  float64                   `json:"balanceLowThreshold"`
+		QuietStart           string                    `json:"quietStart,omitempty"`
+		QuietEnd             string                    `json:"quietEnd,omitempty"`
+		DigestMinutes        int                       `json:"digestMinutes"`
+	}
+
+	NotificationRuleAccountReq struct {
+		AccountID uint `uri:"accountId"`
+	}
+)
+
+func toNotificationRuleResp(rule *model.NotificationRule) NotificationRuleResp {
+	events := rule.Events.Data()
+	if events == nil {
+		events = []model.NotificationEvent{}
+	}
+	return NotificationRuleResp{
+		AccountID:            rule.AccountID,
+		Events:               events,
+		QueuedTooLongMinutes: rule.QueuedTooLongMinutes,
+		BalanceLowThreshold:  service.ToDisplayPoints(rule.BalanceLowThreshold),
+		QuietStart:           rule.QuietStart,
+		QuietEnd:             rule.QuietEnd,
+		DigestMinutes:        rule.DigestMinutes,
+	}
+}
+
+// ListNotificationEvents godoc
+//
+//	@Summary		List notification events
+//	@Description	List the events a notification rule can subscribe to
+//	@Tags			Notification
+//	@Produce		json
+//	@Security		Bearer
+//	@Success		200	{object}	resputil.Response[[]model.NotificationEvent]	"Success"
+//	@Router			/v1/notifications/events [get]
+func (mgr *NotificationMgr) ListNotificationEvents(c *gin.Context) {
+	resputil.Success(c, model.AllNotificationEvents())
+}
+
+// ListNotificationRules godoc
+//
+//	@Summary		List notification rules
+//	@Description	List the notification rules of the current user; without rules the legacy per-job email switch applies
+//	@Tags			Notification
+//	@Produce		json
+//	@Security		Bearer
+//	@Success		200	{object}	resputil.Response[[]NotificationRuleResp]	"Success"
+//	@Failure		500	{object}	resputil.Response[any]						"Other errors"
+//	@Router			/v1/notifications/rules [get]
+func (mgr *NotificationMgr) ListNotificationRules(c *gin.Context) {
+	token := util.GetToken(c)
+	rules, err := mgr.notificationService.ListRules(c, token.UserID)
+	if err != nil {
+		resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "list notification rules"))
+		return
+	}
+	resp := make([]NotificationRuleResp, 0, len(rules))
+	for _, rule := range rules {
+		resp = append(resp, toNotificationRuleResp(rule))
+	}
+	resputil.Success(c, resp)
+}
+
+// UpsertNotificationRule godoc
+//
+//	@Summary		Create or replace a notification rule
+//	@Description	Create or replace the rule of the given account (0 for all accounts) of the current user
+//	@Tags			Notification
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			body	body		NotificationRuleReq							true	"Notification rule"
+//	@Success		200		{object}	resputil.Response[NotificationRuleResp]	"Success"
+//	@Failure		400		{object}	resputil.Response[any]						"Request parameter error"
+//	@Failure		500		{object}	resputil.Response[any]						"Other errors"
+//	@Router			/v1/notifications/rules [put]
+func (mgr *NotificationMgr) UpsertNotificationRule(c *gin.Context) {
+	var req NotificationRuleReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.ParameterError.Wrap(err, "invalid request parameters"))
+		return
+	}
+	token := util.GetToken(c)
+	rule := &model.NotificationRule{
+		UserID:               token.UserID,
+		AccountID:            req.AccountID,
+		Events:               datatypes.NewJSONType(req.Events),
+		QueuedTooLongMinutes: req.QueuedTooLongMinutes,
+		QuietStart:           req.QuietStart,
+		QuietEnd:             req.QuietEnd,
+		DigestMinutes:        req.DigestMinutes,
+	}
+	if req.BalanceLowThreshold != nil {
+		rule.BalanceLowThreshold = req.BalanceLowThreshold.MicroPoints()
+	}
+	saved, err := mgr.notificationService.UpsertRule(c, rule)
+	if err != nil {
+		resputil.HandleError(c, err)
+		return
+	}
+	resputil.Success(c, toNotificationRuleResp(saved))
+}
+
+// DeleteNotificationRule godoc
+//
+//	@Summary		Delete a notification rule
+//	@Description	Delete the rule of the given account (0 for the user-wide rule) of the current user
+//	@Tags			Notification
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			accountId	path		int						true	"Account ID, 0 for the user-wide rule"
+//	@Success		200			{object}	resputil.Response[any]	"Success"
+//	@Failure		404			{object}	resputil.Response[any]	"Rule not found"
+//	@Router			/v1/notifications/rules/{accountId} [delete]
+func (mgr *NotificationMgr) DeleteNotificationRule(c *gin.Context) {
+	var req NotificationRuleAccountReq
+	if err := c.ShouldBindUri(&req); err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.ParameterError.Wrap(err, "invalid request parameters"))
+		return
+	}
+	token := util.GetToken(c)
+	if err := mgr.notificationService.DeleteRule(c, token.UserID, req.AccountID); err != nil {
+		resputil.HandleError(c, err)
+		return
+	}
+	resputil.Success(c, "notification rule deleted")
+}
+
+// GetNotificationChannels godoc
+//
+//	@Summary		Get notification channels
+//	@Description	Get the channel fallback chain of the current user; email is always the last resort
+//	@Tags			Notification
+//	@Produce		json
+//	@Security		Bearer
+//	@Success		200	{object}	resputil.Response[model.NotificationPreference]	"Success"
+//	@Router			/v1/notifications/channels [get]
+func (mgr *NotificationMgr) GetNotificationChannels(c *gin.Context) {
+	token := util.GetToken(c)
+	u := query.User
+	user, err := u.WithContext(c).Where(u.ID.Eq(token.UserID)).First()
+	if err != nil {
+		resputil.HandleError(c, bizerr.NotFound.DataBaseNotFound.Wrap(err, "user not found"))
+		return
+	}
+	preference := user.Attributes.Data().Notification.Redacted()
+	if preference == nil {
+		preference = &model.NotificationPreference{}
+	}
+	resputil.Success(c, preference)
+}
+
+// UpdateNotificationChannels godoc
+//
+//	@Summary		Update notification channels
+//	@Description	Replace the channel fallback chain of the current user, other attributes are kept
+//	@Tags			Notification
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			body	body		model.NotificationPreference	true	"Channel preference"
+//	@Success		200		{object}	resputil.Response[any]			"Success"
+//	@Failure		400		{object}	resputil.Response[any]			"Request parameter error"
+//	@Router			/v1/notifications/channels [put]
+func (mgr *NotificationMgr) UpdateNotificationChannels(c *gin.Context) {
+	var preference model.NotificationPreference
+	if err := c.ShouldBindJSON(&preference); err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.ParameterError.Wrap(err, "invalid request parameters"))
+		return
+	}
+
+	token := util.GetToken(c)
+	u := query.User
+	user, err := u.WithContext(c).Where(u.ID.Eq(token.UserID)).First()
+	if err != nil {
+		resputil.HandleError(c, bizerr.NotFound.DataBaseNotFound.Wrap(err, "user not found"))
+		return
+	}
+	attributes := user.Attributes.Data()
+	if err := alert.PrepareNotificationPreference(c, &preference, attributes.Notification); err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.ParameterError.Wrap(err, "invalid notification channels"))
+		return
+	}
+	attributes.Notification = &preference
+	if _, err := u.WithContext(c).Where(u.ID.Eq(user.ID)).
+		Update(u.Attributes, datatypes.NewJSONType(attributes)); err != nil {
+		resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "update notification channels"))
+		return
+	}
+	resputil.Success(c, "notification channels updated")
+}
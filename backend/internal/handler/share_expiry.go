@@ -0,0 +1,262 @@
+package handler
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/bizerr"
+	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/internal/util"
+)
+
+//nolint:gochecknoinits // This is the standard way to register a gin handler.
+func init() {
+	Registers = append(Registers, NewShareMgr)
+}
+
+const (
+	defaultExpiringShareDays = 7
+	maxExpiringShareDays     = 365
+)
+
+// ShareMgr reports time-limited dataset and image shares. Shares are created by the dataset and
+// image handlers and removed by the revoke-expired-shares cron job once they expire.
+type ShareMgr struct {
+	name string
+}
+
+func NewShareMgr(_ *RegisterConfig) Manager {
+	return &ShareMgr{
+		name: "shares",
+	}
+}
+
+func (mgr *ShareMgr) GetName() string { return mgr.name }
+
+func (mgr *ShareMgr) RegisterPublic(_ *gin.RouterGroup) {}
+
+func (mgr *ShareMgr) RegisterProtected(g *gin.RouterGroup) {
+	g.GET("/expiring", mgr.ListExpiringShares)
+}
+
+func (mgr *ShareMgr) RegisterAdmin(g *gin.RouterGroup) {
+	g.GET("/expiring", mgr.AdminListExpiringShares)
+}
+
+type (
+	ExpiringShareReq struct {
+		// Days is the look-ahead window, 7 by default
+		Days int `form:"days"`
+	}
+
+	ExpiringShareResp struct {
+		Resource     string    `json:"resource"` // dataset or image
+		ResourceID   uint      `json:"resourceID"`
+		ResourceName string    `json:"resourceName"`
+		OwnerID      uint      `json:"ownerID"`
+		Target       string    `json:"target"` // user or account
+		TargetID     uint      `json:"targetID"`
+		TargetName   string    `json:"targetName"`
+		ExpiresAt    time.Time `json:"expiresAt"`
+	}
+)
+
+// ListExpiringShares godoc
+//
+//	@Summary		获取即将到期的共享
+//	@Description	返回窗口期内到期的限时共享，包括用户共享出去的数据集和镜像，以及用户或其账户收到的共享
+//	@Tags			Share
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			days	query		int										false	"窗口期天数，默认 7 天"
+//	@Success		200		{object}	resputil.Response[[]ExpiringShareResp]	"即将到期的共享，按到期时间升序"
+//	@Failure		400		{object}	resputil.Response[any]					"Request parameter error"
+//	@Failure		500		{object}	resputil.Response[any]					"Other errors"
+//	@Router			/v1/shares/expiring [get]
+func (mgr *ShareMgr) ListExpiringShares(c *gin.Context) {
+	mgr.listExpiringShares(c, false)
+}
+
+// AdminListExpiringShares godoc
+//
+//	@Summary		获取所有即将到期的共享
+//	@Description	返回窗口期内到期的所有数据集和镜像限时共享
+//	@Tags			Share
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			days	query		int										false	"窗口期天数，默认 7 天"
+//	@Success		200		{object}	resputil.Response[[]ExpiringShareResp]	"即将到期的共享，按到期时间升序"
+//	@Failure		400		{object}	resputil.Response[any]					"Request parameter error"
+//	@Failure		500		{object}	resputil.Response[any]					"Other errors"
+//	@Router			/v1/admin/shares/expiring [get]
+func (mgr *ShareMgr) AdminListExpiringShares(c *gin.Context) {
+	mgr.listExpiringShares(c, true)
+}
+
+func (mgr *ShareMgr) listExpiringShares(c *gin.Context, admin bool) {
+	var req ExpiringShareReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.ParameterError.Wrap(err, "invalid query parameters"))
+		return
+	}
+	if req.Days == 0 {
+		req.Days = defaultExpiringShareDays
+	}
+	if req.Days < 0 || req.Days > maxExpiringShareDays {
+		resputil.HandleError(c, bizerr.BadRequest.ParameterError.New("days must be between 1 and 365"))
+		return
+	}
+	now := time.Now()
+	shares, err := expiringShares(c, now, now.AddDate(0, 0, req.Days))
+	if err != nil {
+		resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "failed to list expiring shares"))
+		return
+	}
+	if !admin {
+		token := util.GetToken(c)
+		ua := query.UserAccount
+		var accountIDs []uint
+		if err = ua.WithContext(c).Where(ua.UserID.Eq(token.UserID)).Pluck(ua.AccountID, &accountIDs); err != nil {
+			resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "failed to list accounts"))
+			return
+		}
+		shares = filterSharesOfUser(shares, token.UserID, accountIDs)
+	}
+	resputil.Success(c, shares)
+}
+
+// filterSharesOfUser keeps the shares a user granted on their own resources and the shares they
+// receive directly or through one of their accounts.
+func filterSharesOfUser(shares []ExpiringShareResp, userID uint, accountIDs []uint) []ExpiringShareResp {
+	result := make([]ExpiringShareResp, 0, len(shares))
+	for i := range shares {
+		share := &shares[i]
+		if share.OwnerID == userID ||
+			(share.Target == "user" && share.TargetID == userID) ||
+			(share.Target == "account" && slices.Contains(accountIDs, share.TargetID)) {
+			result = append(result, *share)
+		}
+	}
+	return result
+}
+
+// expiringShares returns the dataset and image shares expiring in (after, before], soonest first.
+//
+//nolint:gocyclo // Four share tables with the same shape.
+func expiringShares(ctx context.Context, after, before time.Time) ([]ExpiringShareResp, error) {
+	ud := query.UserDataset
+	userDatasets, err := ud.WithContext(ctx).Where(ud.ExpiresAt.Gt(after), ud.ExpiresAt.Lte(before)).Find()
+	if err != nil {
+		return nil, err
+	}
+	ad := query.AccountDataset
+	accountDatasets, err := ad.WithContext(ctx).Where(ad.ExpiresAt.Gt(after), ad.ExpiresAt.Lte(before)).Find()
+	if err != nil {
+		return nil, err
+	}
+	iu := query.ImageUser
+	imageUsers, err := iu.WithContext(ctx).Preload(iu.Image, iu.User).
+		Where(iu.ExpiresAt.Gt(after), iu.ExpiresAt.Lte(before)).Find()
+	if err != nil {
+		return nil, err
+	}
+	ia := query.ImageAccount
+	imageAccounts, err := ia.WithContext(ctx).Preload(ia.Image, ia.Account).
+		Where(ia.ExpiresAt.Gt(after), ia.ExpiresAt.Lte(before)).Find()
+	if err != nil {
+		return nil, err
+	}
+
+	datasetIDs := make([]uint, 0, len(userDatasets)+len(accountDatasets))
+	userIDs := make([]uint, 0, len(userDatasets))
+	accountIDs := make([]uint, 0, len(accountDatasets))
+	for _, grant := range userDatasets {
+		datasetIDs = append(datasetIDs, grant.DatasetID)
+		userIDs = append(userIDs, grant.UserID)
+	}
+	for _, grant := range accountDatasets {
+		datasetIDs = append(datasetIDs, grant.DatasetID)
+		accountIDs = append(accountIDs, grant.AccountID)
+	}
+	datasets := map[uint]*model.Dataset{}
+	users := map[uint]*model.User{}
+	accounts := map[uint]*model.Account{}
+	if len(datasetIDs) > 0 {
+		d := query.Dataset
+		rows, findErr := d.WithContext(ctx).Where(d.ID.In(datasetIDs...)).Find()
+		if findErr != nil {
+			return nil, findErr
+		}
+		for _, row := range rows {
+			datasets[row.ID] = row
+		}
+	}
+	if len(userIDs) > 0 {
+		u := query.User
+		rows, findErr := u.WithContext(ctx).Where(u.ID.In(userIDs...)).Find()
+		if findErr != nil {
+			return nil, findErr
+		}
+		for _, row := range rows {
+			users[row.ID] = row
+		}
+	}
+	if len(accountIDs) > 0 {
+		a := query.Account
+		rows, findErr := a.WithContext(ctx).Where(a.ID.In(accountIDs...)).Find()
+		if findErr != nil {
+			return nil, findErr
+		}
+		for _, row := range rows {
+			accounts[row.ID] = row
+		}
+	}
+
+	result := make([]ExpiringShareResp, 0, len(userDatasets)+len(accountDatasets)+len(imageUsers)+len(imageAccounts))
+	for _, grant := range userDatasets {
+		dataset, user := datasets[grant.DatasetID], users[grant.UserID]
+		if dataset == nil || user == nil {
+			continue
+		}
+		result = append(result, ExpiringShareResp{
+			Resource: "dataset", ResourceID: dataset.ID, ResourceName: dataset.Name, OwnerID: dataset.UserID,
+			Target: "user", TargetID: user.ID, TargetName: user.Name, ExpiresAt: *grant.ExpiresAt,
+		})
+	}
+	for _, grant := range accountDatasets {
+		dataset, account := datasets[grant.DatasetID], accounts[grant.AccountID]
+		if dataset == nil || account == nil {
+			continue
+		}
+		result = append(result, ExpiringShareResp{
+			Resource: "dataset", ResourceID: dataset.ID, ResourceName: dataset.Name, OwnerID: dataset.UserID,
+			Target: "account", TargetID: account.ID, TargetName: account.Nickname, ExpiresAt: *grant.ExpiresAt,
+		})
+	}
+	for _, grant := range imageUsers {
+		if grant.Image.ID == 0 || grant.User.ID == 0 {
+			continue
+		}
+		result = append(result, ExpiringShareResp{
+			Resource: "image", ResourceID: grant.Image.ID, ResourceName: grant.Image.ImageLink, OwnerID: grant.Image.UserID,
+			Target: "user", TargetID: grant.User.ID, TargetName: grant.User.Name, ExpiresAt: *grant.ExpiresAt,
+		})
+	}
+	for _, grant := range imageAccounts {
+		if grant.Image.ID == 0 || grant.Account.ID == 0 {
+			continue
+		}
+		result = append(result, ExpiringShareResp{
+			Resource: "image", ResourceID: grant.Image.ID, ResourceName: grant.Image.ImageLink, OwnerID: grant.Image.UserID,
+			Target: "account", TargetID: grant.Account.ID, TargetName: grant.Account.Nickname, ExpiresAt: *grant.ExpiresAt,
+		})
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].ExpiresAt.Before(result[j].ExpiresAt) })
+	return result, nil
+}
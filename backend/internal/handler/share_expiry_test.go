@@ -0,0 +1,25 @@
+package handler
+
+import "testing"
+
+func TestFilterSharesOfUser(t *testing.T) {
+	t.Parallel()
+
+	shares := []ExpiringShareResp{
+		{Resource: "dataset", ResourceID: 1, OwnerID: 7, Target: "user", TargetID: 9},
+		{Resource: "dataset", ResourceID: 2, OwnerID: 8, Target: "user", TargetID: 7},
+		{Resource: "image", ResourceID: 3, OwnerID: 8, Target: "account", TargetID: 4},
+		{Resource: "image", ResourceID: 4, OwnerID: 8, Target: "account", TargetID: 5},
+		{Resource: "dataset", ResourceID: 5, OwnerID: 8, Target: "user", TargetID: 9},
+	}
+	got := filterSharesOfUser(shares, 7, []uint{4})
+	want := []uint{1, 2, 3}
+	if len(got) != len(want) {
+		t.Fatalf("filterSharesOfUser() returned %d shares, want %d", len(got), len(want))
+	}
+	for i, share := range got {
+		if share.ResourceID != want[i] {
+			t.Fatalf("share %d has resource %d, want %d", i, share.ResourceID, want[i])
+		}
+	}
+}
@@ -13,11 +13,14 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 
+	"github.com/raids-lab/crater/dao/model"
 	"github.com/raids-lab/crater/dao/query"
 	"github.com/raids-lab/crater/internal/bizerr"
 	"github.com/raids-lab/crater/internal/resputil"
 	"github.com/raids-lab/crater/internal/service"
 	"github.com/raids-lab/crater/internal/util"
+	"github.com/raids-lab/crater/pkg/config"
+	"github.com/raids-lab/crater/pkg/constants"
 	"github.com/raids-lab/crater/pkg/cronjob"
 	"github.com/raids-lab/crater/pkg/prequeuewatcher"
 )
@@ -69,10 +72,13 @@ func (mgr *SystemConfigMgr) RegisterAdmin(g *gin.RouterGroup) {
 	g.PUT("/model-download-limit", mgr.UpdateModelDownloadLimitConfig)
 	g.GET("/pod-bandwidth", mgr.GetAdminPodBandwidthConfig)
 	g.PUT("/pod-bandwidth", mgr.UpdateAdminPodBandwidthConfig)
+	g.GET("/image-scan", mgr.GetImageScanConfig)
+	g.PUT("/image-scan", mgr.UpdateImageScanConfig)
 
 	g.GET("/billing", mgr.GetBillingStatus)
 	g.PUT("/billing", mgr.SetBillingStatus)
 	g.POST("/billing/reconcile", mgr.TriggerBillingBaseLoop)
+	g.POST("/billing/ledger/reconcile", mgr.ReconcileBillingLedger)
 	g.POST("/billing/reset-all", mgr.ResetAllBillingBalances)
 	g.POST("/billing/extra-balance-all", mgr.GrantAllUsersExtraBalance)
 }
@@ -101,12 +107,14 @@ type SetGpuAnalysisStatusReq struct {
 }
 
 type PrequeueConfigResp struct {
-	BackfillEnabled                  bool  `json:"backfillEnabled"`
-	QueueQuotaEnabled                bool  `json:"queueQuotaEnabled"`
-	NormalJobWaitingToleranceSeconds int64 `json:"normalJobWaitingToleranceSeconds"`
-	ActivateTickerIntervalSeconds    int64 `json:"activateTickerIntervalSeconds"`
-	MaxTotalActivationsPerRound      int64 `json:"maxTotalActivationsPerRound"`
-	PrequeueCandidateSize            int64 `json:"prequeueCandidateSize"`
+	BackfillEnabled                  bool   `json:"backfillEnabled"`
+	QueueQuotaEnabled                bool   `json:"queueQuotaEnabled"`
+	NormalJobWaitingToleranceSeconds int64  `json:"normalJobWaitingToleranceSeconds"`
+	ActivateTickerIntervalSeconds    int64  `json:"activateTickerIntervalSeconds"`
+	MaxTotalActivationsPerRound      int64  `json:"maxTotalActivationsPerRound"`
+	PrequeueCandidateSize            int64  `json:"prequeueCandidateSize"`
+	ActivationPolicy                 string `json:"activationPolicy"`
+	FairShareHalfLifeHours           int64  `json:"fairShareHalfLifeHours"`
 }
 
 type UpdatePrequeueConfigReq struct {
@@ -116,6 +124,9 @@ type UpdatePrequeueConfigReq struct {
 	ActivateTickerIntervalSeconds    *int64 `json:"activateTickerIntervalSeconds" binding:"required,gt=0"`
 	MaxTotalActivationsPerRound      *int64 `json:"maxTotalActivationsPerRound" binding:"required,gt=0"`
 	PrequeueCandidateSize            *int64 `json:"prequeueCandidateSize" binding:"required,gt=0"`
+	// ActivationPolicy and FairShareHalfLifeHours are optional so older clients keep working.
+	ActivationPolicy       *string `json:"activationPolicy" binding:"omitempty,oneof=fifo fair_share"`
+	FairShareHalfLifeHours *int64  `json:"fairShareHalfLifeHours" binding:"omitempty,gt=0"`
 }
 
 type ModelDownloadLimitConfigResp struct {
@@ -158,6 +169,17 @@ type UpdatePodBandwidthConfigReq struct {
 	JobEgressBandwidth     string `json:"jobEgressBandwidth" binding:"required"`
 }
 
+type ImageScanConfigResp struct {
+	ScannerEnabled            bool   `json:"scannerEnabled"`
+	VulnerabilityDBConfigured bool   `json:"vulnerabilityDbConfigured"`
+	BlockPublicSeverity       string `json:"blockPublicSeverity"`
+}
+
+type UpdateImageScanConfigReq struct {
+	// BlockPublicSeverity is a severity name, or empty / "none" to stop gating publishing.
+	BlockPublicSeverity string `json:"blockPublicSeverity"`
+}
+
 type BillingStatusResp struct {
 	FeatureEnabled                    bool    `json:"featureEnabled"`
 	Active                            bool    `json:"active"`
@@ -338,7 +360,7 @@ func (mgr *SystemConfigMgr) SetGpuAnalysisStatus(c *gin.Context) {
 
 // GetPrequeueConfig godoc
 // @Summary		获取新版排队配置
-// @Description	获取当前回填提交开关、Crater 队内资源配额开关、普通作业等待忍耐时间和 watcher 运行参数
+// @Description	获取当前回填提交开关、Crater 队内资源配额开关、普通作业等待忍耐时间、激活策略和 watcher 运行参数
 // @Tags			SystemConfig
 // @Produce		json
 // @Security		Bearer
@@ -359,12 +381,14 @@ func (mgr *SystemConfigMgr) GetPrequeueConfig(c *gin.Context) {
 		ActivateTickerIntervalSeconds:    cfg.ActivateTickerIntervalSeconds,
 		MaxTotalActivationsPerRound:      cfg.MaxTotalActivationsPerRound,
 		PrequeueCandidateSize:            cfg.PrequeueCandidateSize,
+		ActivationPolicy:                 cfg.ActivationPolicy,
+		FairShareHalfLifeHours:           cfg.FairShareHalfLifeHours,
 	})
 }
 
 // UpdatePrequeueConfig godoc
 // @Summary		更新新版排队配置
-// @Description	更新回填提交开关、Crater 队内资源配额开关、普通作业等待忍耐时间和 watcher 运行参数
+// @Description	更新回填提交开关、Crater 队内资源配额开关、普通作业等待忍耐时间、激活策略和 watcher 运行参数
 // @Tags			SystemConfig
 // @Accept			json
 // @Produce		json
@@ -388,9 +412,11 @@ func (mgr *SystemConfigMgr) UpdatePrequeueConfig(c *gin.Context) {
 		ActivateTickerIntervalSeconds:    req.ActivateTickerIntervalSeconds,
 		MaxTotalActivationsPerRound:      req.MaxTotalActivationsPerRound,
 		PrequeueCandidateSize:            req.PrequeueCandidateSize,
+		ActivationPolicy:                 req.ActivationPolicy,
+		FairShareHalfLifeHours:           req.FairShareHalfLifeHours,
 	}
 	if err := mgr.service.UpdatePrequeueConfig(c.Request.Context(), cfg); err != nil {
-		if strings.Contains(err.Error(), "must be greater than 0") {
+		if strings.Contains(err.Error(), "must be greater than 0") || strings.Contains(err.Error(), "must be one of") {
 			resputil.BadRequestError(c, err.Error())
 			return
 		}
@@ -547,6 +573,53 @@ func (mgr *SystemConfigMgr) UpdateAdminPodBandwidthConfig(c *gin.Context) {
 	resputil.Success(c, "Pod bandwidth configuration updated")
 }
 
+// GetImageScanConfig godoc
+//
+//	@Summary		获取镜像扫描配置
+//	@Description	返回扫描器是否启用、是否配置漏洞库，以及禁止公开镜像的漏洞级别阈值
+//	@Tags			SystemConfig
+//	@Produce		json
+//	@Security		Bearer
+//	@Success		200	{object}	resputil.Response[ImageScanConfigResp]
+//	@Router			/v1/admin/system-config/image-scan [get]
+func (mgr *SystemConfigMgr) GetImageScanConfig(c *gin.Context) {
+	severity, err := mgr.service.GetImageScanBlockSeverity(c.Request.Context())
+	if err != nil {
+		resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "get image scan config failed"))
+		return
+	}
+	scanConfig := config.GetConfig().ImageScan
+	resputil.Success(c, ImageScanConfigResp{
+		ScannerEnabled:            scanConfig.Enable,
+		VulnerabilityDBConfigured: scanConfig.VulnerabilityDB != "",
+		BlockPublicSeverity:       string(severity),
+	})
+}
+
+// UpdateImageScanConfig godoc
+//
+//	@Summary		更新镜像扫描配置
+//	@Description	设置禁止公开镜像的漏洞级别阈值，留空或 none 表示不限制
+//	@Tags			SystemConfig
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			data	body		UpdateImageScanConfigReq	true	"镜像扫描配置"
+//	@Success		200		{object}	resputil.Response[string]
+//	@Router			/v1/admin/system-config/image-scan [put]
+func (mgr *SystemConfigMgr) UpdateImageScanConfig(c *gin.Context) {
+	var req UpdateImageScanConfigReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.ParameterError.Wrap(err, "invalid image scan config"))
+		return
+	}
+	if err := mgr.service.UpdateImageScanBlockSeverity(c.Request.Context(), req.BlockPublicSeverity); err != nil {
+		resputil.HandleError(c, err)
+		return
+	}
+	resputil.Success(c, "Image scan configuration updated")
+}
+
 func (mgr *SystemConfigMgr) GetBillingStatus(c *gin.Context) {
 	if mgr.billingService == nil {
 		resputil.Error(c, "billing service is not initialized", resputil.ServiceError)
@@ -621,6 +694,29 @@ func (mgr *SystemConfigMgr) TriggerBillingBaseLoop(c *gin.Context) {
 	resputil.Success(c, result)
 }
 
+// ReconcileBillingLedger godoc
+//
+//	@Summary		Reconcile billing balances against the ledger
+//	@Description	Recompute every balance from the append-only billing ledger and report drifted balances
+//	@Tags			SystemConfig
+//	@Produce		json
+//	@Security		Bearer
+//	@Success		200	{object}	resputil.Response[service.BillingLedgerReconcileResult]	"reconciliation result"
+//	@Failure		500	{object}	resputil.Response[any]									"database error"
+//	@Router			/v1/admin/system-config/billing/ledger/reconcile [post]
+func (mgr *SystemConfigMgr) ReconcileBillingLedger(c *gin.Context) {
+	if mgr.billingService == nil {
+		resputil.HandleError(c, bizerr.Internal.ServiceError.New("billing service is not initialized"))
+		return
+	}
+	result, err := mgr.billingService.ReconcileLedger(c.Request.Context())
+	if err != nil {
+		resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "failed to reconcile billing ledger"))
+		return
+	}
+	resputil.Success(c, result)
+}
+
 func (mgr *SystemConfigMgr) ResetAllBillingBalances(c *gin.Context) {
 	if mgr.billingService == nil {
 		resputil.Error(c, "billing service is not initialized", resputil.ServiceError)
@@ -667,6 +763,8 @@ func (mgr *SystemConfigMgr) GrantAllUsersExtraBalance(c *gin.Context) {
 			return err
 		}
 
+		operator := util.GetToken(c).Username
+		ledgerEntries := make([]*model.BillingLedgerEntry, 0, len(users))
 		for i := range users {
 			user := users[i]
 			after := user.ExtraBalance + delta
@@ -675,14 +773,26 @@ func (mgr *SystemConfigMgr) GrantAllUsersExtraBalance(c *gin.Context) {
 				Update(u.ExtraBalance, after); err != nil {
 				return err
 			}
+			entry := service.ExtraLedgerEntry(user.ID, model.BillingLedgerGrant, user.ExtraBalance, after, operator, req.Reason)
+			entry.CreatedAt = issuedAt
+			ledgerEntries = append(ledgerEntries, entry)
 			usersAffected++
 		}
-		return nil
+		return service.AppendBillingLedgerTx(tx, ledgerEntries...)
 	})
 	if err != nil {
+		RecordOperationLog(c, constants.OpTypeGrantExtraBalance, "all", constants.OpStatusFailed, err.Error(), map[string]any{
+			"delta":  delta,
+			"reason": req.Reason,
+		})
 		resputil.Error(c, fmt.Sprintf("grant extra balance to all users failed: %v", err), resputil.NotSpecified)
 		return
 	}
+	RecordOperationLog(c, constants.OpTypeGrantExtraBalance, "all", constants.OpStatusSuccess, req.Reason, map[string]any{
+		"delta":         delta,
+		"reason":        req.Reason,
+		"usersAffected": usersAffected,
+	})
 
 	klog.Infof(
 		"grant extra balance to all users success, usersAffected=%d delta=%d reason=%s",
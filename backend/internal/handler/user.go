@@ -13,9 +13,12 @@ import (
 	"github.com/raids-lab/crater/dao/model"
 	"github.com/raids-lab/crater/dao/query"
 
+	"github.com/raids-lab/crater/internal/bizerr"
 	"github.com/raids-lab/crater/internal/resputil"
 	"github.com/raids-lab/crater/internal/service"
 	"github.com/raids-lab/crater/internal/util"
+	"github.com/raids-lab/crater/pkg/alert"
+	"github.com/raids-lab/crater/pkg/constants"
 	"github.com/raids-lab/crater/pkg/utils"
 )
 
@@ -54,6 +57,7 @@ func (mgr *UserMgr) RegisterAdmin(g *gin.RouterGroup) {
 	g.PUT("/:name/attributes", mgr.UpdateUserAttributesByAdmin)
 	g.POST("/:name/billing/extra-balance", mgr.AdjustUserExtraBalance)
 	g.GET("/:name/billing/accounts", mgr.GetUserBillingAccounts)
+	g.GET("/:name/billing/statement", mgr.GetUserBillingStatement)
 }
 
 type UserResp struct {
@@ -192,7 +196,7 @@ func (mgr *UserMgr) ListUser(c *gin.Context) {
 			Role:         users[i].Role,
 			Status:       users[i].Status,
 			ExtraBalance: extraBalance,
-			Attributes:   users[i].Attributes,
+			Attributes:   datatypes.NewJSONType(users[i].Attributes.Data().Redacted()),
 		})
 	}
 	klog.Infof("list users success, count: %d", len(resp))
@@ -474,6 +478,13 @@ func (mgr *UserMgr) UpdateUserAttributesByAdmin(c *gin.Context) {
 		return
 	}
 
+	if attributes.Notification == nil {
+		attributes.Notification = user.Attributes.Data().Notification
+	} else if err := alert.PrepareNotificationPreference(c, attributes.Notification, user.Attributes.Data().Notification); err != nil {
+		resputil.BadRequestError(c, err.Error())
+		return
+	}
+
 	user.Attributes = datatypes.NewJSONType(attributes)
 	user.Nickname = attributes.Nickname
 	if err := u.WithContext(c).Save(user); err != nil {
@@ -514,6 +525,7 @@ func (mgr *UserMgr) AdjustUserExtraBalance(c *gin.Context) {
 		return
 	}
 
+	var delta int64
 	err := query.GetDB().WithContext(c).Transaction(func(tx *gorm.DB) error {
 		txQuery := query.Use(tx)
 		u := txQuery.User
@@ -526,7 +538,7 @@ func (mgr *UserMgr) AdjustUserExtraBalance(c *gin.Context) {
 		}
 
 		before := user.ExtraBalance
-		delta := req.Delta.MicroPoints()
+		delta = req.Delta.MicroPoints()
 		after := before + delta
 		if after < 0 {
 			return fmt.Errorf("extraBalance would be negative: before=%d delta=%d", before, delta)
@@ -536,6 +548,12 @@ func (mgr *UserMgr) AdjustUserExtraBalance(c *gin.Context) {
 			Update(u.ExtraBalance, after); err != nil {
 			return err
 		}
+		operator := util.GetToken(c).Username
+		if err := service.AppendBillingLedgerTx(tx, service.ExtraLedgerEntry(
+			user.ID, model.BillingLedgerAdjustment, before, after, operator, req.Reason,
+		)); err != nil {
+			return err
+		}
 		resp = AdjustUserExtraBalanceResp{
 			UserID:        user.ID,
 			Username:      user.Name,
@@ -546,9 +564,18 @@ func (mgr *UserMgr) AdjustUserExtraBalance(c *gin.Context) {
 		return nil
 	})
 	if err != nil {
+		RecordOperationLog(c, constants.OpTypeAdjustExtraBalance, nameReq.Name, constants.OpStatusFailed, err.Error(), map[string]any{
+			"delta":  req.Delta.MicroPoints(),
+			"reason": req.Reason,
+		})
 		resputil.Error(c, fmt.Sprintf("adjust user extra balance failed: %v", err), resputil.NotSpecified)
 		return
 	}
+	RecordOperationLog(c, constants.OpTypeAdjustExtraBalance, resp.Username, constants.OpStatusSuccess, req.Reason, map[string]any{
+		"userId": resp.UserID,
+		"delta":  delta,
+		"reason": req.Reason,
+	})
 
 	klog.Infof(
 		"adjust user extra balance success, user=%s delta=%.2f before=%.2f after=%.2f reason=%s",
@@ -561,6 +588,51 @@ func (mgr *UserMgr) AdjustUserExtraBalance(c *gin.Context) {
 	resputil.Success(c, resp)
 }
 
+// GetUserBillingStatement godoc
+//
+//	@Summary		Export a user billing statement
+//	@Description	Aggregate job settlements, free balance issuance and extra balance adjustments of a user over a period
+//	@Tags			User
+//	@Produce		json
+//	@Produce		text/csv
+//	@Security		Bearer
+//	@Param			name		path		string										true	"username"
+//	@Param			accountId	query		uint										false	"limit to one account"
+//	@Param			from		query		string										false	"start date (YYYY-MM-DD or RFC3339)"
+//	@Param			to			query		string										false	"end date, inclusive for YYYY-MM-DD"
+//	@Param			format		query		string										false	"json or csv"
+//	@Success		200			{object}	resputil.Response[BillingStatementResp]	"statement"
+//	@Failure		400			{object}	resputil.Response[any]						"invalid period"
+//	@Failure		404			{object}	resputil.Response[any]						"user not found"
+//	@Router			/v1/admin/users/{name}/billing/statement [get]
+func (mgr *UserMgr) GetUserBillingStatement(c *gin.Context) {
+	var (
+		nameReq UserNameReq
+		req     BillingStatementReq
+	)
+	if err := c.ShouldBindUri(&nameReq); err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.InvalidRequest.Wrap(err, "invalid uri params"))
+		return
+	}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.InvalidRequest.Wrap(err, "invalid query parameters"))
+		return
+	}
+	q, err := resolveBillingStatementQuery(&req, time.Now())
+	if err != nil {
+		resputil.HandleError(c, err)
+		return
+	}
+	u := query.User
+	user, err := u.WithContext(c).Where(u.Name.Eq(nameReq.Name)).First()
+	if err != nil {
+		resputil.HandleError(c, bizerr.NotFound.DataBaseNotFound.Wrap(err, "user not found"))
+		return
+	}
+	q.UserID = user.ID
+	writeBillingStatement(c, mgr.billingService, req.Format, q)
+}
+
 func (mgr *UserMgr) GetUserBillingAccounts(c *gin.Context) {
 	if !mgr.isBillingFeatureEnabled(c) {
 		resputil.Success(c, []UserBillingAccountResp{})
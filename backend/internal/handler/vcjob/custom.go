@@ -26,6 +26,8 @@ type (
 		Shell           *string         `json:"shell"`
 		Command         *string         `json:"command"`
 		WorkingDir      string          `json:"workingDir" binding:"required"`
+		// Array expands the request into a job array instead of a single job.
+		Array *JobArrayReq `json:"array,omitempty"`
 	}
 )
 
@@ -79,6 +81,32 @@ func (mgr *VolcanojobMgr) CreateTrainingJob(c *gin.Context) {
 		return
 	}
 
+	if req.Array != nil {
+		mgr.createTrainingJobArray(c, token, &req, scheduleMetadata)
+		return
+	}
+
+	job, err := buildTrainingJob(c, token, &req, scheduleMetadata)
+	if err != nil {
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+		return
+	}
+
+	if err = mgr.submitJob(c, token, job); err != nil {
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+		return
+	}
+
+	resputil.Success(c, job)
+}
+
+// buildTrainingJob assembles the volcano job of a custom training request without submitting it.
+func buildTrainingJob(
+	ctx context.Context,
+	token util.JWTMessage,
+	req *CreateCustomReq,
+	scheduleMetadata *jobScheduleMetadata,
+) (*batch.Job, error) {
 	// Generate job name with type prefix (RFC 1035 compliant)
 	jobName := utils.GenerateJobName("sg", token.Username)
 	// baseURL for ingress paths (without type prefix)
@@ -94,15 +122,14 @@ func (mgr *VolcanojobMgr) CreateTrainingJob(c *gin.Context) {
 	)
 
 	// 5. Create the pod spec
-	podSpec, err := GenerateCustomPodSpec(c, token, &req)
+	podSpec, err := GenerateCustomPodSpec(ctx, token, req)
 	if err != nil {
-		resputil.Error(c, err.Error(), resputil.NotSpecified)
-		return
+		return nil, err
 	}
 
 	queueName := vcqueue.ResolveJobQueueName(token)
 	// 6. Create volcano job
-	job := batch.Job{
+	job := &batch.Job{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        jobName,
 			Namespace:   config.GetConfig().Namespaces.Job,
@@ -143,12 +170,7 @@ func (mgr *VolcanojobMgr) CreateTrainingJob(c *gin.Context) {
 		},
 	}
 
-	if err = mgr.submitJob(c, token, &job); err != nil {
-		resputil.Error(c, err.Error(), resputil.NotSpecified)
-		return
-	}
-
-	resputil.Success(c, job)
+	return job, nil
 }
 
 func GenerateCustomPodSpec(
@@ -0,0 +1,120 @@
+package vcjob
+
+import (
+	"github.com/gin-gonic/gin"
+	v1 "k8s.io/api/core/v1"
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/internal/bizerr"
+	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/internal/service"
+	vcjobservice "github.com/raids-lab/crater/internal/service/vcjob"
+	"github.com/raids-lab/crater/internal/util"
+)
+
+const (
+	estimateTypePytorch    = "pytorch"
+	estimateTypeTensorflow = "tensorflow"
+	estimateTypeTraining   = "training"
+)
+
+type JobCostEstimateResp struct {
+	*service.BillingJobEstimate `json:",inline"`
+	JobType                     string          `json:"jobType"`
+	Resources                   v1.ResourceList `json:"resources"`
+}
+
+// EstimateJobCost godoc
+//
+//	@Summary		Estimate the cost of a job before submitting it
+//	@Description	Build the job from the same payload as the create endpoint and price it with the current unit prices; nothing is submitted. Job arrays are priced per element.
+//	@Tags			VolcanoJob
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			type	path		string									true	"pytorch, tensorflow or training"
+//	@Param			req		body		any										true	"Same body as the create endpoint of the type"
+//	@Success		200		{object}	resputil.Response[JobCostEstimateResp]	"Success"
+//	@Failure		400		{object}	resputil.Response[any]					"Request parameter error"
+//	@Failure		500		{object}	resputil.Response[any]					"Other errors"
+//	@Router			/v1/vcjobs/estimate/{type} [post]
+func (mgr *VolcanojobMgr) EstimateJobCost(c *gin.Context) {
+	token := util.GetToken(c)
+	jobType := c.Param("type")
+
+	job, scheduleType, err := mgr.buildJobForEstimate(c, token, jobType)
+	if err != nil {
+		resputil.HandleError(c, err)
+		return
+	}
+	if mgr.billingService == nil {
+		resputil.HandleError(c, bizerr.Internal.ServiceError.New("billing service is not initialized"))
+		return
+	}
+
+	resources := vcjobservice.CalculateJobResources(job)
+	estimate, err := mgr.billingService.EstimateJobCost(c.Request.Context(), token.UserID, token.AccountID, resources, &scheduleType)
+	if err != nil {
+		resputil.HandleError(c, err)
+		return
+	}
+	resputil.Success(c, JobCostEstimateResp{
+		BillingJobEstimate: estimate,
+		JobType:            jobType,
+		Resources:          resources,
+	})
+}
+
+// buildJobForEstimate runs the request through the same builders as job creation so
+// that the priced resources match what would be submitted.
+func (mgr *VolcanojobMgr) buildJobForEstimate(
+	c *gin.Context,
+	token util.JWTMessage,
+	jobType string,
+) (*batch.Job, model.ScheduleType, error) {
+	switch jobType {
+	case estimateTypePytorch, estimateTypeTensorflow:
+		var req CreateTensorflowReq
+		if err := c.ShouldBindJSON(&req); err != nil {
+			return nil, model.ScheduleTypeNormal, bizerr.BadRequest.InvalidRequest.Wrap(err, "invalid job payload")
+		}
+		scheduleType, err := req.validateScheduleOptions(false)
+		if err != nil {
+			return nil, model.ScheduleTypeNormal, bizerr.BadRequest.ParameterError.Wrap(err, err.Error())
+		}
+		metadata, err := mgr.resolveJobScheduleMetadata(c.Request.Context(), scheduleType)
+		if err != nil {
+			return nil, model.ScheduleTypeNormal, bizerr.Internal.ServiceError.Wrap(err, err.Error())
+		}
+		build := buildTensorflowJob
+		if jobType == estimateTypePytorch {
+			build = buildPytorchJob
+		}
+		job, err := build(c, token, &req, metadata)
+		if err != nil {
+			return nil, model.ScheduleTypeNormal, bizerr.BadRequest.ParameterError.Wrap(err, err.Error())
+		}
+		return job, scheduleType, nil
+	case estimateTypeTraining:
+		var req CreateCustomReq
+		if err := c.ShouldBindJSON(&req); err != nil {
+			return nil, model.ScheduleTypeNormal, bizerr.BadRequest.InvalidRequest.Wrap(err, "invalid job payload")
+		}
+		scheduleType, err := req.validateScheduleOptions(true)
+		if err != nil {
+			return nil, model.ScheduleTypeNormal, bizerr.BadRequest.ParameterError.Wrap(err, err.Error())
+		}
+		metadata, err := mgr.resolveJobScheduleMetadata(c.Request.Context(), scheduleType)
+		if err != nil {
+			return nil, model.ScheduleTypeNormal, bizerr.Internal.ServiceError.Wrap(err, err.Error())
+		}
+		job, err := buildTrainingJob(c, token, &req, metadata)
+		if err != nil {
+			return nil, model.ScheduleTypeNormal, bizerr.BadRequest.ParameterError.Wrap(err, err.Error())
+		}
+		return job, scheduleType, nil
+	default:
+		return nil, model.ScheduleTypeNormal, bizerr.BadRequest.ParameterError.New("job type must be pytorch, tensorflow or training")
+	}
+}
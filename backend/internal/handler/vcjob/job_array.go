@@ -0,0 +1,419 @@
+package vcjob
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/internal/bizerr"
+	"github.com/raids-lab/crater/internal/handler"
+	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/internal/service"
+	vcjobservice "github.com/raids-lab/crater/internal/service/vcjob"
+	"github.com/raids-lab/crater/internal/util"
+	"github.com/raids-lab/crater/pkg/vcqueue"
+)
+
+//nolint:gochecknoinits // This is the standard way to register a gin handler.
+func init() {
+	handler.Registers = append(handler.Registers, NewJobArrayMgr)
+}
+
+// JobArrayMgr serves the job arrays created through POST /vcjobs/training with an
+// array field. Elements are ordinary vcjobs; this manager lists, cancels and bills
+// them as a unit.
+type JobArrayMgr struct {
+	name            string
+	jobs            *VolcanojobMgr
+	jobArrayService *service.JobArrayService
+}
+
+func NewJobArrayMgr(conf *handler.RegisterConfig) handler.Manager {
+	return &JobArrayMgr{
+		name:            "jobarrays",
+		jobs:            NewVolcanojobMgr(conf).(*VolcanojobMgr),
+		jobArrayService: conf.JobArrayService,
+	}
+}
+
+func (mgr *JobArrayMgr) GetName() string { return mgr.name }
+
+func (mgr *JobArrayMgr) RegisterPublic(_ *gin.RouterGroup) {}
+
+func (mgr *JobArrayMgr) RegisterProtected(g *gin.RouterGroup) {
+	g.GET("", mgr.ListJobArrays)
+	g.GET(":id", mgr.GetJobArray)
+	g.GET(":id/billing", mgr.GetJobArrayBilling)
+	g.DELETE(":id", mgr.CancelJobArray)
+}
+
+func (mgr *JobArrayMgr) RegisterAdmin(_ *gin.RouterGroup) {}
+
+type (
+	// JobArrayReq turns a training job request into a job array. Exactly one of Count
+	// and Matrix is set; Parallelism caps how many elements run at once.
+	JobArrayReq struct {
+		Count       int                 `json:"count,omitempty"`
+		Matrix      map[string][]string `json:"matrix,omitempty"`
+		Parallelism int                 `json:"parallelism,omitempty"`
+	}
+
+	JobArrayIDReq struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+
+	JobArrayTaskResp struct {
+		Index   int               `json:"index"`
+		JobName string            `json:"jobName"`
+		Params  map[string]string `json:"params,omitempty"`
+		// Status is Held while the element waits for a parallelism slot, otherwise the job phase.
+		Status string `json:"status"`
+	}
+
+	JobArrayResp struct {
+		ID          uint                 `json:"id"`
+		Name        string               `json:"name"`
+		JobType     model.JobType        `json:"jobType"`
+		Size        int                  `json:"size"`
+		Parallelism int                  `json:"parallelism"`
+		Status      model.JobArrayStatus `json:"status"`
+		CreatedAt   time.Time            `json:"createdAt"`
+		Tasks       []JobArrayTaskResp   `json:"tasks,omitempty"`
+	}
+
+	JobArrayBillingResp struct {
+		ID                uint             `json:"id"`
+		Name              string           `json:"name"`
+		BilledPointsTotal float64          `json:"billedPointsTotal"`
+		Jobs              []JobBillingResp `json:"jobs"`
+	}
+)
+
+const jobArrayTaskStatusHeld = "Held"
+
+// createTrainingJobArray expands a training request with an array field into one job
+// per element. Elements above the parallelism cap are stored as held Prequeue records
+// and released by the reconciler as their siblings finish.
+func (mgr *VolcanojobMgr) createTrainingJobArray(
+	c *gin.Context,
+	token util.JWTMessage,
+	req *CreateCustomReq,
+	scheduleMetadata *jobScheduleMetadata,
+) {
+	if mgr.jobArrayService == nil {
+		resputil.HandleError(c, bizerr.Conflict.ResourceStatusError.New("job arrays are not enabled"))
+		return
+	}
+	elements, err := service.ExpandJobArray(req.Array.Count, req.Array.Matrix)
+	if err != nil {
+		resputil.HandleError(c, err)
+		return
+	}
+	parallelism, err := mgr.resolveJobArrayParallelism(c, token, req, scheduleMetadata.ScheduleType, len(elements))
+	if err != nil {
+		resputil.Error(c, err.Error(), resputil.ServiceError)
+		return
+	}
+	if parallelism < len(elements) && mgr.prequeueWatcher == nil {
+		resputil.HandleError(c, bizerr.Conflict.ResourceStatusError.New(
+			"job arrays larger than their parallelism require the prequeue watcher",
+		))
+		return
+	}
+
+	jobs := make([]*batch.Job, 0, len(elements))
+	tasks := make([]*model.JobArrayTask, 0, len(elements))
+	heldRecords := make([]*model.Job, 0, len(elements))
+	jobNames := make(map[string]struct{}, len(elements))
+	for i, params := range elements {
+		elementReq := *req
+		elementReq.Array = nil
+		elementReq.Name = fmt.Sprintf("%s-%d", req.Name, i)
+		elementReq.Envs = withJobArrayEnvs(req.Envs, i, len(elements), params)
+
+		job, err := buildUniqueTrainingJob(c, token, &elementReq, scheduleMetadata, jobNames)
+		if err != nil {
+			resputil.Error(c, err.Error(), resputil.NotSpecified)
+			return
+		}
+		// Elements only differ in their envs, so one admission check covers the array.
+		if i == 0 {
+			if err := mgr.ensureJobAdmitted(c, job); err != nil {
+				resputil.HandleError(c, bizerr.BadRequest.ParameterError.Wrap(err, "job array"))
+				return
+			}
+		}
+
+		task := service.NewJobArrayTask(i, parallelism, params, job.Name)
+		if task.Held {
+			record, err := vcjobservice.GenerateJobRecord(job, token.UserID, token.AccountID, model.Prequeue)
+			if err != nil {
+				resputil.Error(c, err.Error(), resputil.NotSpecified)
+				return
+			}
+			heldRecords = append(heldRecords, record)
+		}
+		jobs = append(jobs, job)
+		tasks = append(tasks, task)
+	}
+
+	array := &model.JobArray{
+		Name:        req.Name,
+		UserID:      token.UserID,
+		AccountID:   token.AccountID,
+		JobType:     model.JobTypeCustom,
+		Parallelism: parallelism,
+	}
+	if err := mgr.jobArrayService.CreateJobArray(c, array, tasks, heldRecords); err != nil {
+		resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "create job array"))
+		return
+	}
+
+	for i, task := range tasks {
+		if task.Held {
+			continue
+		}
+		if err := mgr.submitJob(c, token, jobs[i]); err != nil {
+			mgr.abortJobArray(c, token, array.ID)
+			resputil.Error(c, fmt.Sprintf("job array element %d: %v", i, err), resputil.NotSpecified)
+			return
+		}
+	}
+
+	mgr.respondJobArray(c, token, array.ID)
+}
+
+// buildUniqueTrainingJob retries the generated job name until it differs from the other
+// elements of the same array; the random suffix is short enough to collide in a sweep.
+func buildUniqueTrainingJob(
+	ctx context.Context,
+	token util.JWTMessage,
+	req *CreateCustomReq,
+	scheduleMetadata *jobScheduleMetadata,
+	jobNames map[string]struct{},
+) (*batch.Job, error) {
+	for {
+		job, err := buildTrainingJob(ctx, token, req, scheduleMetadata)
+		if err != nil {
+			return nil, err
+		}
+		if _, taken := jobNames[job.Name]; !taken {
+			jobNames[job.Name] = struct{}{}
+			return job, nil
+		}
+	}
+}
+
+// resolveJobArrayParallelism caps the requested parallelism so that the running
+// elements of an array fit into the user's queue quota together.
+func (mgr *VolcanojobMgr) resolveJobArrayParallelism(
+	ctx context.Context,
+	token util.JWTMessage,
+	req *CreateCustomReq,
+	scheduleType model.ScheduleType,
+	size int,
+) (int, error) {
+	parallelism := size
+	if req.Array.Parallelism > 0 && req.Array.Parallelism < size {
+		parallelism = req.Array.Parallelism
+	}
+	// Backfill jobs are not accounted against the queue quota.
+	if scheduleType != model.ScheduleTypeNormal || mgr.queueQuotaSvc == nil {
+		return parallelism, nil
+	}
+	resolved, err := mgr.queueQuotaSvc.ResolveQueueQuota(ctx, token.UserID, token.AccountID, vcqueue.ResolveJobQueueName(token))
+	if err != nil {
+		return 0, err
+	}
+	if !resolved.Enabled {
+		return parallelism, nil
+	}
+	if limit := service.JobArrayParallelismForQuota(resolved.Quota, req.Resource); limit > 0 && limit < parallelism {
+		parallelism = limit
+	}
+	return parallelism, nil
+}
+
+// abortJobArray cancels an array whose first elements could not all be submitted.
+func (mgr *VolcanojobMgr) abortJobArray(c *gin.Context, token util.JWTMessage, id uint) {
+	active, err := mgr.jobArrayService.CancelJobArray(c, id, token.UserID, token.AccountID)
+	if err != nil {
+		klog.Errorf("failed to cancel job array %d after submission error: %v", id, err)
+		return
+	}
+	for _, jobName := range active {
+		if err := mgr.deleteActiveJob(c, jobName); err != nil {
+			klog.Errorf("failed to delete job %s of job array %d: %v", jobName, id, err)
+		}
+	}
+}
+
+func (mgr *VolcanojobMgr) respondJobArray(c *gin.Context, token util.JWTMessage, id uint) {
+	array, states, err := mgr.jobArrayService.GetJobArray(c, id, token.UserID, token.AccountID)
+	if err != nil {
+		resputil.HandleError(c, err)
+		return
+	}
+	resp := newJobArrayResp(array)
+	resp.Tasks = make([]JobArrayTaskResp, 0, len(states))
+	for _, state := range states {
+		status := string(state.JobStatus)
+		if state.Task.Held {
+			status = jobArrayTaskStatusHeld
+		}
+		resp.Tasks = append(resp.Tasks, JobArrayTaskResp{
+			Index:   state.Task.Index,
+			JobName: state.Task.JobName,
+			Params:  state.Task.Params.Data(),
+			Status:  status,
+		})
+	}
+	resputil.Success(c, resp)
+}
+
+func newJobArrayResp(array *model.JobArray) JobArrayResp {
+	return JobArrayResp{
+		ID:          array.ID,
+		Name:        array.Name,
+		JobType:     array.JobType,
+		Size:        array.Size,
+		Parallelism: array.Parallelism,
+		Status:      array.Status,
+		CreatedAt:   array.CreatedAt,
+	}
+}
+
+// ListJobArrays godoc
+//
+//	@Summary		List job arrays
+//	@Description	List the job arrays of the current user in the current account
+//	@Tags			JobArray
+//	@Produce		json
+//	@Security		Bearer
+//	@Success		200	{object}	resputil.Response[[]JobArrayResp]	"Success"
+//	@Failure		500	{object}	resputil.Response[any]				"Other errors"
+//	@Router			/v1/jobarrays [get]
+func (mgr *JobArrayMgr) ListJobArrays(c *gin.Context) {
+	token := util.GetToken(c)
+	if mgr.jobArrayService == nil {
+		resputil.Success(c, []JobArrayResp{})
+		return
+	}
+	arrays, err := mgr.jobArrayService.ListJobArrays(c, token.UserID, token.AccountID)
+	if err != nil {
+		resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "list job arrays"))
+		return
+	}
+	resp := make([]JobArrayResp, 0, len(arrays))
+	for _, array := range arrays {
+		resp = append(resp, newJobArrayResp(array))
+	}
+	resputil.Success(c, resp)
+}
+
+// GetJobArray godoc
+//
+//	@Summary		Get a job array
+//	@Description	Get a job array with the parameters and status of every element
+//	@Tags			JobArray
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			id	path		int								true	"Job array ID"
+//	@Success		200	{object}	resputil.Response[JobArrayResp]	"Success"
+//	@Failure		404	{object}	resputil.Response[any]			"Job array not found"
+//	@Router			/v1/jobarrays/{id} [get]
+func (mgr *JobArrayMgr) GetJobArray(c *gin.Context) {
+	id, ok := mgr.bindJobArrayID(c)
+	if !ok {
+		return
+	}
+	mgr.jobs.respondJobArray(c, util.GetToken(c), id)
+}
+
+// GetJobArrayBilling godoc
+//
+//	@Summary		Get job array billing
+//	@Description	Sum the settled points of every element of a job array
+//	@Tags			JobArray
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			id	path		int										true	"Job array ID"
+//	@Success		200	{object}	resputil.Response[JobArrayBillingResp]	"Success"
+//	@Failure		404	{object}	resputil.Response[any]					"Job array not found"
+//	@Router			/v1/jobarrays/{id}/billing [get]
+func (mgr *JobArrayMgr) GetJobArrayBilling(c *gin.Context) {
+	id, ok := mgr.bindJobArrayID(c)
+	if !ok {
+		return
+	}
+	token := util.GetToken(c)
+	array, states, err := mgr.jobArrayService.GetJobArray(c, id, token.UserID, token.AccountID)
+	if err != nil {
+		resputil.HandleError(c, err)
+		return
+	}
+	resp := JobArrayBillingResp{ID: array.ID, Name: array.Name, Jobs: []JobBillingResp{}}
+	if mgr.jobs.billingService == nil || !mgr.jobs.billingService.IsUserFacingEnabled(c.Request.Context()) {
+		resputil.Success(c, resp)
+		return
+	}
+	resp.BilledPointsTotal = service.ToDisplayPoints(service.SumJobArrayBilling(states))
+	for _, state := range states {
+		resp.Jobs = append(resp.Jobs, JobBillingResp{
+			JobName:           state.Task.JobName,
+			Name:              fmt.Sprintf("%s-%d", array.Name, state.Task.Index),
+			BilledPointsTotal: service.ToDisplayPoints(state.BilledPointsTotal),
+		})
+	}
+	resputil.Success(c, resp)
+}
+
+// CancelJobArray godoc
+//
+//	@Summary		Cancel a job array
+//	@Description	Drop the elements that have not started yet and delete the submitted ones
+//	@Tags			JobArray
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			id	path		int								true	"Job array ID"
+//	@Success		200	{object}	resputil.Response[JobArrayResp]	"Success"
+//	@Failure		404	{object}	resputil.Response[any]			"Job array not found"
+//	@Failure		409	{object}	resputil.Response[any]			"Job array already finished"
+//	@Router			/v1/jobarrays/{id} [delete]
+func (mgr *JobArrayMgr) CancelJobArray(c *gin.Context) {
+	id, ok := mgr.bindJobArrayID(c)
+	if !ok {
+		return
+	}
+	token := util.GetToken(c)
+	active, err := mgr.jobArrayService.CancelJobArray(c, id, token.UserID, token.AccountID)
+	if err != nil {
+		resputil.HandleError(c, err)
+		return
+	}
+	for _, jobName := range active {
+		if err := mgr.jobs.deleteActiveJob(c, jobName); err != nil {
+			resputil.Error(c, fmt.Sprintf("failed to delete job %s: %v", jobName, err), resputil.NotSpecified)
+			return
+		}
+	}
+	mgr.jobs.respondJobArray(c, token, id)
+}
+
+func (mgr *JobArrayMgr) bindJobArrayID(c *gin.Context) (uint, bool) {
+	var req JobArrayIDReq
+	if err := c.ShouldBindUri(&req); err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.InvalidRequest.Wrap(err, "invalid job array id"))
+		return 0, false
+	}
+	if mgr.jobArrayService == nil {
+		resputil.HandleError(c, bizerr.NotFound.DataBaseNotFound.New(fmt.Sprintf("job array %d not found", req.ID)))
+		return 0, false
+	}
+	return req.ID, true
+}
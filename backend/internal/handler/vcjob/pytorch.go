@@ -1,6 +1,7 @@
 package vcjob
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/gin-gonic/gin"
@@ -39,16 +40,6 @@ func (mgr *VolcanojobMgr) CreatePytorchJob(c *gin.Context) {
 		return
 	}
 
-	jobResources := utils.CalculateReplicatedResources(
-		req.Tasks,
-		func(task TaskReq) v1.ResourceList {
-			return task.Resource
-		},
-		func(task TaskReq) int32 {
-			return task.Replicas
-		},
-	)
-
 	if err := vcqueue.EnsureAccountQueueExists(c, mgr.client, token, token.AccountID); err != nil {
 		resputil.Error(c, fmt.Sprintf("failed to ensure account queue exists: %v", err), resputil.NotSpecified)
 		return
@@ -64,22 +55,52 @@ func (mgr *VolcanojobMgr) CreatePytorchJob(c *gin.Context) {
 		return
 	}
 
+	job, err := buildPytorchJob(c, token, &req, scheduleMetadata)
+	if err != nil {
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+		return
+	}
+
+	if err = mgr.submitJob(c, token, job); err != nil {
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+		return
+	}
+
+	resputil.Success(c, job)
+}
+
+// buildPytorchJob assembles the volcano job of a pytorch request without submitting it.
+func buildPytorchJob(
+	ctx context.Context,
+	token util.JWTMessage,
+	req *CreateTensorflowReq,
+	scheduleMetadata *jobScheduleMetadata,
+) (*batch.Job, error) {
+	jobResources := utils.CalculateReplicatedResources(
+		req.Tasks,
+		func(task TaskReq) v1.ResourceList {
+			return task.Resource
+		},
+		func(task TaskReq) int32 {
+			return task.Replicas
+		},
+	)
+
 	// Generate job name with type prefix (RFC 1035 compliant)
 	jobName := utils.GenerateJobName("pyt", token.Username)
 	// baseURL for ingress paths (without type prefix)
 	baseURL := jobName[4:] // Remove "pyt-" prefix
 
 	// 1. Volume Mounts
-	volumes, volumeMounts, err := GenerateVolumeMounts(c, req.VolumeMounts, token)
+	volumes, volumeMounts, err := GenerateVolumeMounts(ctx, req.VolumeMounts, token)
 	if err != nil {
-		resputil.Error(c, err.Error(), resputil.NotSpecified)
-		return
+		return nil, err
 	}
 
 	// 2. Node Affinity and Tolerations
 	baseAffinity := GenerateNodeAffinity(req.Selectors, jobResources)
 	baseTolerations := GenerateTaintTolerationsForAccount(token)
-	envs := GenerateEnvs(c, token, req.Envs)
+	envs := GenerateEnvs(ctx, token, req.Envs)
 
 	// 3. Labels and Annotations
 	labels, jobAnnotations, podAnnotations := getLabelAndAnnotations(
@@ -156,7 +177,7 @@ func (mgr *VolcanojobMgr) CreatePytorchJob(c *gin.Context) {
 
 	queueName := vcqueue.ResolveJobQueueName(token)
 	// 5. Create volcano job
-	job := batch.Job{
+	job := &batch.Job{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        jobName,
 			Namespace:   config.GetConfig().Namespaces.Job,
@@ -181,10 +202,5 @@ func (mgr *VolcanojobMgr) CreatePytorchJob(c *gin.Context) {
 		},
 	}
 
-	if err = mgr.submitJob(c, token, &job); err != nil {
-		resputil.Error(c, err.Error(), resputil.NotSpecified)
-		return
-	}
-
-	resputil.Success(c, job)
+	return job, nil
 }
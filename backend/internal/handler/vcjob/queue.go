@@ -0,0 +1,162 @@
+package vcjob
+
+import (
+	"github.com/gin-gonic/gin"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/bizerr"
+	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/internal/util"
+	"github.com/raids-lab/crater/pkg/prequeuewatcher"
+)
+
+type QueuedJobResp struct {
+	Name              string                           `json:"name"`
+	JobName           string                           `json:"jobName"`
+	UserInfo          model.UserInfo                   `json:"userInfo"`
+	Account           string                           `json:"account"`
+	Queue             string                           `json:"queue"`
+	JobType           string                           `json:"jobType"`
+	ScheduleType      model.ScheduleType               `json:"scheduleType"`
+	CreationTimestamp metav1.Time                      `json:"createdAt"`
+	Resources         v1.ResourceList                  `json:"resources"`
+	Position          int                              `json:"position"`
+	ScopeSize         int                              `json:"scopeSize"`
+	Reason            prequeuewatcher.QueueBlockReason `json:"reason"`
+	Detail            string                           `json:"detail,omitempty"`
+	EstimatedStartAt  *metav1.Time                     `json:"estimatedStartAt,omitempty"`
+	FairSharePriority *float64                         `json:"fairSharePriority,omitempty"`
+}
+
+// GetSelfJobQueue godoc
+//
+//	@Summary		Get the queue status of my prequeued jobs
+//	@Description	Position inside the account queue, blocking reason and estimated start time of the current user's prequeued jobs in the current account
+//	@Tags			VolcanoJob
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			page		query	int	false	"Page number"
+//	@Param			page_size	query	int	false	"Page size, 1-200"
+//	@Success		200	{object}	resputil.Response[resputil.Page[QueuedJobResp]]	"Queue status"
+//	@Failure		400	{object}	resputil.Response[any]							"Request parameter error"
+//	@Failure		500	{object}	resputil.Response[any]							"Other errors"
+//	@Router			/v1/vcjobs/queue [get]
+func (mgr *VolcanojobMgr) GetSelfJobQueue(c *gin.Context) {
+	token := util.GetToken(c)
+	mgr.writeJobQueue(c, prequeuewatcher.QueueStatusQuery{UserID: token.UserID, AccountID: token.AccountID})
+}
+
+// AdminGetJobQueue godoc
+//
+//	@Summary		Get the queue status of all prequeued jobs
+//	@Description	Position, blocking reason and estimated start time of every prequeued job, in activation order
+//	@Tags			VolcanoJob
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			page		query	int	false	"Page number"
+//	@Param			page_size	query	int	false	"Page size, 1-200"
+//	@Success		200	{object}	resputil.Response[resputil.Page[QueuedJobResp]]	"Queue status"
+//	@Failure		400	{object}	resputil.Response[any]							"Request parameter error"
+//	@Failure		500	{object}	resputil.Response[any]							"Other errors"
+//	@Router			/v1/admin/vcjobs/queue [get]
+func (mgr *VolcanojobMgr) AdminGetJobQueue(c *gin.Context) {
+	mgr.writeJobQueue(c, prequeuewatcher.QueueStatusQuery{})
+}
+
+type jobQueuePageQuery struct {
+	Page     int `form:"page,default=1" binding:"min=1"`
+	PageSize int `form:"page_size,default=50" binding:"min=1,max=200"`
+}
+
+func (mgr *VolcanojobMgr) writeJobQueue(c *gin.Context, statusQuery prequeuewatcher.QueueStatusQuery) {
+	if mgr.prequeueWatcher == nil {
+		resputil.HandleError(c, bizerr.Internal.ServiceError.New("prequeue watcher is not initialized"))
+		return
+	}
+	var request jobQueuePageQuery
+	if err := c.ShouldBindQuery(&request); err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.ParameterError.Wrap(err, "invalid job queue query"))
+		return
+	}
+	if request.Page > int(^uint(0)>>1)/request.PageSize {
+		resputil.HandleError(c, bizerr.BadRequest.ParameterError.New("page is too large for page_size"))
+		return
+	}
+	statusQuery.Offset = (request.Page - 1) * request.PageSize
+	statusQuery.Limit = request.PageSize
+	statuses, total, err := mgr.prequeueWatcher.QueueStatus(c.Request.Context(), statusQuery)
+	if err != nil {
+		resputil.HandleError(c, bizerr.Internal.ServiceError.Wrap(err, "failed to compute queue status"))
+		return
+	}
+	resp, err := convertQueuedJobResp(c, statuses)
+	if err != nil {
+		resputil.HandleError(c, err)
+		return
+	}
+	resputil.Success(c, resputil.NewPage(resp, total, request.Page, request.PageSize))
+}
+
+func convertQueuedJobResp(c *gin.Context, statuses []*prequeuewatcher.QueuedJobStatus) ([]QueuedJobResp, error) {
+	userIDs := make([]uint, 0, len(statuses))
+	accountIDs := make([]uint, 0, len(statuses))
+	for _, status := range statuses {
+		userIDs = append(userIDs, status.Job.UserID)
+		accountIDs = append(accountIDs, status.Job.AccountID)
+	}
+	users := map[uint]*model.User{}
+	accounts := map[uint]string{}
+	if len(statuses) > 0 {
+		u := query.User
+		userRows, err := u.WithContext(c).Unscoped().Where(u.ID.In(userIDs...)).Find()
+		if err != nil {
+			return nil, bizerr.Internal.DatabaseError.Wrap(err, "failed to load job owners")
+		}
+		for _, user := range userRows {
+			users[user.ID] = user
+		}
+		a := query.Account
+		accountRows, err := a.WithContext(c).Unscoped().Where(a.ID.In(accountIDs...)).Find()
+		if err != nil {
+			return nil, bizerr.Internal.DatabaseError.Wrap(err, "failed to load job accounts")
+		}
+		for _, account := range accountRows {
+			accounts[account.ID] = account.Nickname
+		}
+	}
+
+	resp := make([]QueuedJobResp, 0, len(statuses))
+	for _, status := range statuses {
+		job := status.Job
+		item := QueuedJobResp{
+			Name:              job.Name,
+			JobName:           job.JobName,
+			Account:           accounts[job.AccountID],
+			Queue:             job.Queue,
+			JobType:           string(job.JobType),
+			ScheduleType:      model.ScheduleTypeNormal,
+			CreationTimestamp: metav1.NewTime(job.CreationTimestamp),
+			Resources:         job.Resources.Data(),
+			Position:          status.Position,
+			ScopeSize:         status.ScopeSize,
+			Reason:            status.Reason,
+			Detail:            status.Detail,
+			FairSharePriority: status.FairSharePriority,
+		}
+		if job.ScheduleType != nil {
+			item.ScheduleType = *job.ScheduleType
+		}
+		if user := users[job.UserID]; user != nil {
+			item.UserInfo = model.UserInfo{Username: user.Name, Nickname: user.Nickname}
+		}
+		if status.EstimatedStartAt != nil {
+			estimated := metav1.NewTime(*status.EstimatedStartAt)
+			item.EstimatedStartAt = &estimated
+		}
+		resp = append(resp, item)
+	}
+	return resp, nil
+}
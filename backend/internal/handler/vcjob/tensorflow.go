@@ -1,6 +1,7 @@
 package vcjob
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/gin-gonic/gin"
@@ -75,16 +76,6 @@ func (mgr *VolcanojobMgr) CreateTensorflowJob(c *gin.Context) {
 		return
 	}
 
-	jobResources := utils.CalculateReplicatedResources(
-		req.Tasks,
-		func(task TaskReq) v1.ResourceList {
-			return task.Resource
-		},
-		func(task TaskReq) int32 {
-			return task.Replicas
-		},
-	)
-
 	if err := vcqueue.EnsureAccountQueueExists(c, mgr.client, token, token.AccountID); err != nil {
 		resputil.Error(c, fmt.Sprintf("failed to ensure account queue exists: %v", err), resputil.NotSpecified)
 		return
@@ -100,22 +91,52 @@ func (mgr *VolcanojobMgr) CreateTensorflowJob(c *gin.Context) {
 		return
 	}
 
+	job, err := buildTensorflowJob(c, token, &req, scheduleMetadata)
+	if err != nil {
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+		return
+	}
+
+	if err = mgr.submitJob(c, token, job); err != nil {
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+		return
+	}
+
+	resputil.Success(c, job)
+}
+
+// buildTensorflowJob assembles the volcano job of a tensorflow request without submitting it.
+func buildTensorflowJob(
+	ctx context.Context,
+	token util.JWTMessage,
+	req *CreateTensorflowReq,
+	scheduleMetadata *jobScheduleMetadata,
+) (*batch.Job, error) {
+	jobResources := utils.CalculateReplicatedResources(
+		req.Tasks,
+		func(task TaskReq) v1.ResourceList {
+			return task.Resource
+		},
+		func(task TaskReq) int32 {
+			return task.Replicas
+		},
+	)
+
 	// Generate job name with type prefix (RFC 1035 compliant)
 	jobName := utils.GenerateJobName("tf", token.Username)
 	// baseURL for ingress paths (without type prefix)
 	baseURL := jobName[3:] // Remove "tf-" prefix
 
 	// 1. Volume Mounts
-	volumes, volumeMounts, err := GenerateVolumeMounts(c, req.VolumeMounts, token)
+	volumes, volumeMounts, err := GenerateVolumeMounts(ctx, req.VolumeMounts, token)
 	if err != nil {
-		resputil.Error(c, err.Error(), resputil.NotSpecified)
-		return
+		return nil, err
 	}
 
 	// 2. Node Affinity and Tolerations
 	baseAffinity := GenerateNodeAffinity(req.Selectors, jobResources)
 	baseTolerations := GenerateTaintTolerationsForAccount(token)
-	envs := GenerateEnvs(c, token, req.Envs)
+	envs := GenerateEnvs(ctx, token, req.Envs)
 
 	// 3. Labels and Annotations
 	labels, jobAnnotations, podAnnotations := getLabelAndAnnotations(
@@ -141,7 +162,7 @@ func (mgr *VolcanojobMgr) CreateTensorflowJob(c *gin.Context) {
 
 	queueName := vcqueue.ResolveJobQueueName(token)
 	// 5. Create volcano job
-	job := batch.Job{
+	job := &batch.Job{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        jobName,
 			Namespace:   config.GetConfig().Namespaces.Job,
@@ -167,12 +188,7 @@ func (mgr *VolcanojobMgr) CreateTensorflowJob(c *gin.Context) {
 		},
 	}
 
-	if err = mgr.submitJob(c, token, &job); err != nil {
-		resputil.Error(c, err.Error(), resputil.NotSpecified)
-		return
-	}
-
-	resputil.Success(c, job)
+	return job, nil
 }
 
 func buildTensorflowTasks(
@@ -5,6 +5,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -272,6 +274,27 @@ func GenerateArchitectureNodeAffinity(imageInfo ImageBaseInfo, baseAffinity *v1.
 	return newAffinity
 }
 
+// JobArrayIndexEnv carries the index of a job array element into its container.
+const JobArrayIndexEnv = "CRATER_ARRAY_INDEX"
+
+// JobArraySizeEnv carries the number of elements of the job array.
+const JobArraySizeEnv = "CRATER_ARRAY_SIZE"
+
+// withJobArrayEnvs returns a copy of customEnvs extended with the index of an array
+// element and its matrix parameters, so GenerateEnvs injects them like user envs.
+func withJobArrayEnvs(customEnvs []v1.EnvVar, index, size int, params map[string]string) []v1.EnvVar {
+	envs := make([]v1.EnvVar, 0, len(customEnvs)+len(params)+2)
+	envs = append(envs, customEnvs...)
+	envs = append(envs,
+		v1.EnvVar{Name: JobArrayIndexEnv, Value: strconv.Itoa(index)},
+		v1.EnvVar{Name: JobArraySizeEnv, Value: strconv.Itoa(size)},
+	)
+	for _, name := range slices.Sorted(maps.Keys(params)) {
+		envs = append(envs, v1.EnvVar{Name: name, Value: params[name]})
+	}
+	return envs
+}
+
 // GenerateEnvs generates environment variables for the pod
 // NB_USER: username
 // NB_GID: group ID
@@ -569,6 +592,11 @@ func getLabelAndAnnotations(
 			jobAnnotations[vcjobservice.AnnotationKeyMountedDatasetIDs] = string(data)
 		}
 	}
+	if versions := collectMountedDatasetVersions(c.VolumeMounts); len(versions) > 0 {
+		if data, err := json.Marshal(versions); err == nil {
+			jobAnnotations[vcjobservice.AnnotationKeyMountedDatasetVersions] = string(data)
+		}
+	}
 	podAnnotations = map[string]string{
 		AnnotationKeyTaskName: c.Name,
 		AnnotationKeyUser:     token.Username,
@@ -595,6 +623,69 @@ func collectMountedDatasetIDs(mounts []util.VolumeMount) []uint {
 	return ids
 }
 
+func collectMountedDatasetVersions(mounts []util.VolumeMount) []model.JobDatasetVersion {
+	versions := []model.JobDatasetVersion{}
+	for _, vm := range mounts {
+		if vm.Type != util.DataType || vm.DatasetVersion == "" {
+			continue
+		}
+		versions = append(versions, model.JobDatasetVersion{
+			DatasetID: vm.DatasetID,
+			Version:   vm.DatasetVersion,
+			MountPath: vm.MountPath,
+		})
+	}
+	return versions
+}
+
+// pinTemplateDatasetVersions writes the dataset versions a job mounted into the volume mounts of
+// its template, so a job created from the template mounts the same content. A mount matches by
+// dataset and mount path, or by dataset alone when the job mounted it once. Templates without
+// data.volumeMounts are returned unchanged.
+func pinTemplateDatasetVersions(template string, versions []model.JobDatasetVersion) string {
+	if len(versions) == 0 || strings.TrimSpace(template) == "" {
+		return template
+	}
+	var doc map[string]any
+	if err := json.Unmarshal([]byte(template), &doc); err != nil {
+		return template
+	}
+	data, _ := doc["data"].(map[string]any)
+	mounts, _ := data["volumeMounts"].([]any)
+	mountsOfDataset := map[uint]int{}
+	for _, version := range versions {
+		mountsOfDataset[version.DatasetID]++
+	}
+	changed := false
+	for _, raw := range mounts {
+		mount, ok := raw.(map[string]any)
+		if !ok {
+			continue
+		}
+		datasetID, _ := mount["datasetID"].(float64)
+		mountPath, _ := mount["mountPath"].(string)
+		for _, version := range versions {
+			if uint(datasetID) != version.DatasetID ||
+				(mountPath != version.MountPath && mountsOfDataset[version.DatasetID] > 1) {
+				continue
+			}
+			if mount["datasetVersion"] != version.Version {
+				mount["datasetVersion"] = version.Version
+				changed = true
+			}
+			break
+		}
+	}
+	if !changed {
+		return template
+	}
+	pinned, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return template
+	}
+	return string(pinned)
+}
+
 // generateInteractivePodSpec generates the pod spec for interactive jobs (Jupyter, WebIDE)
 func generateInteractivePodSpec(
 	c context.Context,
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -56,6 +57,8 @@ type VolcanojobMgr struct {
 	queueQuotaSvc   *service.PrequeueService
 	prequeueWatcher *prequeuewatcher.PrequeueWatcher
 	billingService  *service.BillingService
+	workflowService *service.WorkflowService
+	jobArrayService *service.JobArrayService
 }
 
 func NewVolcanojobMgr(conf *handler.RegisterConfig) handler.Manager {
@@ -71,6 +74,8 @@ func NewVolcanojobMgr(conf *handler.RegisterConfig) handler.Manager {
 		queueQuotaSvc:   conf.PrequeueService,
 		prequeueWatcher: conf.PrequeueWatcher,
 		billingService:  conf.BillingService,
+		workflowService: conf.WorkflowService,
+		jobArrayService: conf.JobArrayService,
 	}
 }
 
@@ -88,6 +93,7 @@ func (mgr *VolcanojobMgr) RegisterProtected(g *gin.RouterGroup) {
 	g.GET("billing", mgr.GetSelfJobBilling)
 	g.GET("billing/all", mgr.GetAllJobBillingInDays)
 	g.GET("billing/user/:username", mgr.GetUserJobBillingInDays)
+	g.GET("queue", mgr.GetSelfJobQueue)
 	g.DELETE(":name", mgr.DeleteJob)
 
 	g.GET(":name/detail", mgr.GetJobDetail)
@@ -120,6 +126,9 @@ func (mgr *VolcanojobMgr) RegisterProtected(g *gin.RouterGroup) {
 
 	// pytorch
 	g.POST("pytorch", mgr.CreatePytorchJob)
+
+	// dry-run pricing of the create payloads above
+	g.POST("estimate/:type", mgr.EstimateJobCost)
 }
 
 func (mgr *VolcanojobMgr) RegisterAdmin(g *gin.RouterGroup) {
@@ -130,6 +139,7 @@ func (mgr *VolcanojobMgr) RegisterAdmin(g *gin.RouterGroup) {
 	g.GET("billing", mgr.GetAllJobBillingInDays)
 	g.GET("billing/user/:username", mgr.GetUserJobBillingInDays)
 	g.GET(":name/billing", mgr.GetJobBillingDetail)
+	g.GET("queue", mgr.AdminGetJobQueue)
 	// delete job
 	g.DELETE(":name", mgr.AdminDeleteJob)
 }
@@ -500,9 +510,46 @@ func (mgr *VolcanojobMgr) deleteJob(c *gin.Context, recordAdminOperation bool) {
 	)
 
 	resputil.Success(c, nil)
+	mgr.releaseDeletedPrequeueJob(c, jobRecord)
 	mgr.notifyDeletedPrequeue(plan.shouldDeleteRecord)
 }
 
+// deleteActiveJob deletes a job of a workflow or job array the same way DeleteJob does,
+// including final settlement.
+func (mgr *VolcanojobMgr) deleteActiveJob(c *gin.Context, jobName string) error {
+	record, err := mgr.getDeleteJobRecord(c, jobName)
+	if err != nil {
+		return err
+	}
+	plan, err := mgr.buildDeleteJobPlan(c, record)
+	if err != nil {
+		return err
+	}
+	if err := mgr.applyDeleteJobPlan(c, record, plan); err != nil {
+		return err
+	}
+	return mgr.deleteClusterJob(c, plan)
+}
+
+// releaseDeletedPrequeueJob lets workflows and job arrays react to a job deleted before
+// it reached Volcano; the reconciler never sees such jobs.
+func (mgr *VolcanojobMgr) releaseDeletedPrequeueJob(ctx context.Context, record *model.Job) {
+	if record == nil || record.Status != model.Prequeue {
+		return
+	}
+	workflowReleased, err := mgr.workflowService.ReleaseDependents(ctx, record.JobName)
+	if err != nil {
+		klog.Errorf("failed to update workflow of deleted job %s: %v", record.JobName, err)
+	}
+	arrayReleased, err := mgr.jobArrayService.ReleaseNext(ctx, record.JobName)
+	if err != nil {
+		klog.Errorf("failed to update job array of deleted job %s: %v", record.JobName, err)
+	}
+	if (workflowReleased || arrayReleased) && mgr.prequeueWatcher != nil {
+		mgr.prequeueWatcher.RequestFullScan()
+	}
+}
+
 func (mgr *VolcanojobMgr) notifyDeletedPrequeue(shouldDeleteRecord bool) {
 	if !shouldDeleteRecord || mgr.prequeueWatcher == nil {
 		return
@@ -587,6 +634,8 @@ type (
 		PermanentLocked         bool               `json:"permanentLocked"`
 		LockedTimestamp         metav1.Time        `json:"lockedTimestamp"`
 		BilledPointsTotal       float64            `json:"billedPointsTotal"`
+		// FairSharePriority is only set for prequeued jobs under the fair-share activation policy.
+		FairSharePriority *float64 `json:"fairSharePriority,omitempty"`
 	}
 
 	JobBillingResp struct {
@@ -661,14 +710,47 @@ func (mgr *VolcanojobMgr) listJobs(c *gin.Context, defaultDays int, scope jobLis
 		resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "list jobs failed"))
 		return
 	}
+	jobList := convertJobResp(jobs)
+	mgr.fillFairSharePriority(c.Request.Context(), jobs, jobList)
 	resputil.Success(c, resputil.NewPage(
-		convertJobResp(jobs),
+		jobList,
 		total,
 		request.Page,
 		request.PageSize,
 	))
 }
 
+// fillFairSharePriority exposes the activation priority of prequeued jobs. Failures only
+// hide the priority, the list itself is still returned.
+func (mgr *VolcanojobMgr) fillFairSharePriority(ctx context.Context, jobs []*model.Job, jobList []JobResp) {
+	if mgr.configService == nil || mgr.queueQuotaSvc == nil {
+		return
+	}
+	if !slices.ContainsFunc(jobs, func(job *model.Job) bool { return job.Status == model.Prequeue }) {
+		return
+	}
+	cfg, err := mgr.configService.GetPrequeueConfig(ctx)
+	if err != nil || !cfg.FairShareEnabled() {
+		return
+	}
+	snapshot, err := mgr.queueQuotaSvc.LoadFairShareSnapshot(
+		ctx,
+		time.Duration(cfg.FairShareHalfLifeHours)*time.Hour,
+		utils.GetLocalTime(),
+	)
+	if err != nil {
+		klog.Warningf("load fair share snapshot failed: %v", err)
+		return
+	}
+	for i, job := range jobs {
+		if job.Status != model.Prequeue {
+			continue
+		}
+		priority := snapshot.Priority(job.AccountID, job.UserID)
+		jobList[i].FairSharePriority = &priority
+	}
+}
+
 // GetSelfJobFacets godoc
 //
 //	@Summary	Get job facets for the current user
@@ -1343,7 +1425,7 @@ func (mgr *VolcanojobMgr) GetJobYaml(c *gin.Context) {
 // GetJobTemplate godoc
 //
 //	@Summary		获取任务的 template
-//	@Description	获取任务的 template
+//	@Description	获取任务的 template，挂载过的数据集版本会写入模板的 volumeMounts
 //	@Tags			VolcanoJob
 //	@Accept			json
 //	@Produce		json
@@ -1365,6 +1447,10 @@ func (mgr *VolcanojobMgr) GetJobTemplate(c *gin.Context) {
 		resputil.Error(c, err.Error(), resputil.NotSpecified)
 		return
 	}
+	if job.DatasetVersions != nil {
+		resputil.Success(c, pinTemplateDatasetVersions(job.Template, job.DatasetVersions.Data()))
+		return
+	}
 	resputil.Success(c, job.Template)
 }
 
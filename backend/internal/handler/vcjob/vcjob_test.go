@@ -1,9 +1,12 @@
 package vcjob
 
 import (
+	"encoding/json"
+	"reflect"
 	"testing"
 	"time"
 
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
 
@@ -61,3 +64,63 @@ func TestResolveDeleteSettlementTime(t *testing.T) {
 		})
 	}
 }
+
+func TestWithJobArrayEnvs(t *testing.T) {
+	userEnvs := []v1.EnvVar{{Name: "EPOCHS", Value: "10"}}
+	envs := withJobArrayEnvs(userEnvs, 2, 6, map[string]string{"LR": "0.01", "BATCH": "64"})
+
+	want := []v1.EnvVar{
+		{Name: "EPOCHS", Value: "10"},
+		{Name: JobArrayIndexEnv, Value: "2"},
+		{Name: JobArraySizeEnv, Value: "6"},
+		{Name: "BATCH", Value: "64"},
+		{Name: "LR", Value: "0.01"},
+	}
+	if len(envs) != len(want) {
+		t.Fatalf("envs = %v, want %v", envs, want)
+	}
+	for i := range want {
+		if envs[i] != want[i] {
+			t.Fatalf("envs[%d] = %v, want %v", i, envs[i], want[i])
+		}
+	}
+	if len(userEnvs) != 1 {
+		t.Fatalf("user envs were modified: %v", userEnvs)
+	}
+}
+
+func TestPinTemplateDatasetVersions(t *testing.T) {
+	t.Parallel()
+
+	versions := []model.JobDatasetVersion{
+		{DatasetID: 7, Version: "v1", MountPath: "/data"},
+		{DatasetID: 9, Version: "2024.06", MountPath: "/home/alice/models"},
+	}
+	template := `{"version":"1","type":"custom","data":{"volumeMounts":[` +
+		`{"type":2,"datasetID":7,"mountPath":"/data"},` +
+		`{"type":2,"datasetID":9,"mountPath":"/home/${CRATER_USERNAME}/models"},` +
+		`{"type":1,"subPath":"user","mountPath":"/home/${CRATER_USERNAME}"}]}}`
+
+	pinned := pinTemplateDatasetVersions(template, versions)
+	var doc struct {
+		Data struct {
+			VolumeMounts []map[string]any `json:"volumeMounts"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(pinned), &doc); err != nil {
+		t.Fatalf("pinned template is not JSON: %v", err)
+	}
+	got := []any{}
+	for _, mount := range doc.Data.VolumeMounts {
+		got = append(got, mount["datasetVersion"])
+	}
+	if !reflect.DeepEqual(got, []any{"v1", "2024.06", nil}) {
+		t.Fatalf("pinned versions = %v", got)
+	}
+
+	for _, unchanged := range []string{"", "custom template", `{"data":{}}`} {
+		if got := pinTemplateDatasetVersions(unchanged, versions); got != unchanged {
+			t.Fatalf("pinTemplateDatasetVersions(%q) = %q", unchanged, got)
+		}
+	}
+}
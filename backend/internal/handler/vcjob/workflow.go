@@ -0,0 +1,450 @@
+package vcjob
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"k8s.io/klog/v2"
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/internal/bizerr"
+	"github.com/raids-lab/crater/internal/handler"
+	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/internal/service"
+	vcjobservice "github.com/raids-lab/crater/internal/service/vcjob"
+	"github.com/raids-lab/crater/internal/util"
+	"github.com/raids-lab/crater/pkg/utils"
+	"github.com/raids-lab/crater/pkg/vcqueue"
+)
+
+//nolint:gochecknoinits // This is the standard way to register a gin handler.
+func init() {
+	handler.Registers = append(handler.Registers, NewWorkflowMgr)
+}
+
+// WorkflowMgr serves DAG workflows of vcjobs. It reuses the job builders and the
+// submission path of VolcanojobMgr, so a workflow node behaves exactly like a job
+// created through /vcjobs once its parents have finished.
+type WorkflowMgr struct {
+	name            string
+	jobs            *VolcanojobMgr
+	workflowService *service.WorkflowService
+}
+
+func NewWorkflowMgr(conf *handler.RegisterConfig) handler.Manager {
+	return &WorkflowMgr{
+		name:            "workflows",
+		jobs:            NewVolcanojobMgr(conf).(*VolcanojobMgr),
+		workflowService: conf.WorkflowService,
+	}
+}
+
+func (mgr *WorkflowMgr) GetName() string { return mgr.name }
+
+func (mgr *WorkflowMgr) RegisterPublic(_ *gin.RouterGroup) {}
+
+func (mgr *WorkflowMgr) RegisterProtected(g *gin.RouterGroup) {
+	g.GET("", mgr.ListWorkflows)
+	g.POST("", mgr.CreateWorkflow)
+	g.GET(":id", mgr.GetWorkflow)
+	g.DELETE(":id", mgr.CancelWorkflow)
+}
+
+func (mgr *WorkflowMgr) RegisterAdmin(_ *gin.RouterGroup) {}
+
+type (
+	WorkflowNodeReq struct {
+		Name      string                     `json:"name" binding:"required"`
+		DependsOn []model.WorkflowDependency `json:"dependsOn"`
+		// JobType selects how Spec is decoded: custom uses the training request, pytorch the pytorch request.
+		JobType model.JobType   `json:"jobType" binding:"required"`
+		Spec    json.RawMessage `json:"spec" binding:"required"`
+	}
+
+	CreateWorkflowReq struct {
+		Name  string            `json:"name" binding:"required"`
+		Nodes []WorkflowNodeReq `json:"nodes" binding:"required,min=1,dive"`
+	}
+
+	WorkflowIDReq struct {
+		ID uint `uri:"id" binding:"required"`
+	}
+
+	WorkflowNodeResp struct {
+		Name      string                     `json:"name"`
+		JobName   string                     `json:"jobName"`
+		DependsOn []model.WorkflowDependency `json:"dependsOn"`
+		// Status is Held or Skipped while the node never reached Volcano, otherwise the job phase.
+		Status string `json:"status"`
+	}
+
+	WorkflowResp struct {
+		ID        uint                 `json:"id"`
+		Name      string               `json:"name"`
+		Status    model.WorkflowStatus `json:"status"`
+		CreatedAt time.Time            `json:"createdAt"`
+		Nodes     []WorkflowNodeResp   `json:"nodes,omitempty"`
+	}
+)
+
+const (
+	workflowNodeStatusHeld    = "Held"
+	workflowNodeStatusSkipped = "Skipped"
+)
+
+type workflowNodeJob struct {
+	spec service.WorkflowNodeSpec
+	job  *batch.Job
+}
+
+// CreateWorkflow godoc
+//
+//	@Summary		Create a workflow
+//	@Description	Submit several jobs with dependsOn edges, downstream jobs stay in Prequeue until their parents finish
+//	@Tags			Workflow
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			req	body		CreateWorkflowReq				true	"Workflow definition"
+//	@Success		200	{object}	resputil.Response[WorkflowResp]	"Success"
+//	@Failure		400	{object}	resputil.Response[any]			"Request parameter error"
+//	@Failure		500	{object}	resputil.Response[any]			"Other errors"
+//	@Router			/v1/workflows [post]
+func (mgr *WorkflowMgr) CreateWorkflow(c *gin.Context) {
+	token := util.GetToken(c)
+
+	var req CreateWorkflowReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.InvalidRequest.Wrap(err, "invalid workflow request"))
+		return
+	}
+	if mgr.workflowService == nil || mgr.jobs.prequeueWatcher == nil {
+		resputil.HandleError(c, bizerr.Conflict.ResourceStatusError.New("workflows require the prequeue watcher"))
+		return
+	}
+
+	specs := make([]service.WorkflowNodeSpec, 0, len(req.Nodes))
+	nodeReqs := make(map[string]*WorkflowNodeReq, len(req.Nodes))
+	for i := range req.Nodes {
+		node := &req.Nodes[i]
+		for j := range node.DependsOn {
+			if node.DependsOn[j].Condition == "" {
+				node.DependsOn[j].Condition = model.WorkflowDependencyOnSuccess
+			}
+		}
+		specs = append(specs, service.WorkflowNodeSpec{Name: node.Name, DependsOn: node.DependsOn})
+		nodeReqs[node.Name] = node
+	}
+	order, err := service.SortWorkflowNodes(specs)
+	if err != nil {
+		resputil.HandleError(c, err)
+		return
+	}
+
+	if err := vcqueue.EnsureAccountQueueExists(c, mgr.jobs.client, token, token.AccountID); err != nil {
+		resputil.Error(c, fmt.Sprintf("failed to ensure account queue exists: %v", err), resputil.NotSpecified)
+		return
+	}
+	if err := vcqueue.EnsureUserQueueExists(c, mgr.jobs.client, token, token.AccountID, token.UserID); err != nil {
+		resputil.Error(c, fmt.Sprintf("failed to ensure user queue exists: %v", err), resputil.NotSpecified)
+		return
+	}
+
+	nodeJobs := make([]workflowNodeJob, 0, len(order))
+	for _, name := range order {
+		nodeReq := nodeReqs[name]
+		job, ok := mgr.buildWorkflowNodeJob(c, token, nodeReq)
+		if !ok {
+			return
+		}
+		if err := mgr.admitWorkflowNodeJob(c, token, job); err != nil {
+			resputil.HandleError(c, bizerr.BadRequest.ParameterError.Wrap(err, fmt.Sprintf("workflow node %q", name)))
+			return
+		}
+		nodeJobs = append(nodeJobs, workflowNodeJob{
+			spec: service.WorkflowNodeSpec{Name: name, DependsOn: nodeReq.DependsOn},
+			job:  job,
+		})
+	}
+
+	workflow, err := mgr.persistWorkflow(c, token, req.Name, nodeJobs)
+	if err != nil {
+		resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "create workflow"))
+		return
+	}
+
+	for _, nodeJob := range nodeJobs {
+		if len(nodeJob.spec.DependsOn) > 0 {
+			continue
+		}
+		if err := mgr.jobs.submitJob(c, token, nodeJob.job); err != nil {
+			mgr.abortWorkflow(c, token, workflow.ID)
+			resputil.Error(c, fmt.Sprintf("workflow node %q: %v", nodeJob.spec.Name, err), resputil.NotSpecified)
+			return
+		}
+	}
+
+	mgr.respondWorkflow(c, token, workflow.ID)
+}
+
+// buildWorkflowNodeJob decodes and validates one node spec with the same checks as
+// the standalone create endpoints. It writes the error response itself.
+func (mgr *WorkflowMgr) buildWorkflowNodeJob(
+	c *gin.Context,
+	token util.JWTMessage,
+	nodeReq *WorkflowNodeReq,
+) (*batch.Job, bool) {
+	var (
+		common *CreateJobCommon
+		build  func(ctx context.Context, meta *jobScheduleMetadata) (*batch.Job, error)
+		target any
+	)
+	allowBackfill := false
+	switch nodeReq.JobType {
+	case model.JobTypeCustom:
+		req := &CreateCustomReq{}
+		common, target, allowBackfill = &req.CreateJobCommon, req, true
+		build = func(ctx context.Context, meta *jobScheduleMetadata) (*batch.Job, error) {
+			if req.Array != nil {
+				return nil, fmt.Errorf("job arrays cannot be used as workflow nodes")
+			}
+			return buildTrainingJob(ctx, token, req, meta)
+		}
+	case model.JobTypePytorch:
+		req := &CreateTensorflowReq{}
+		common, target = &req.CreateJobCommon, req
+		build = func(ctx context.Context, meta *jobScheduleMetadata) (*batch.Job, error) {
+			return buildPytorchJob(ctx, token, req, meta)
+		}
+	default:
+		resputil.HandleError(c, bizerr.BadRequest.ParameterError.New(
+			fmt.Sprintf("workflow node %q: jobType must be %q or %q", nodeReq.Name, model.JobTypeCustom, model.JobTypePytorch),
+		))
+		return nil, false
+	}
+
+	if err := json.Unmarshal(nodeReq.Spec, target); err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.InvalidRequest.Wrap(err, fmt.Sprintf("workflow node %q", nodeReq.Name)))
+		return nil, false
+	}
+	if err := binding.Validator.ValidateStruct(target); err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.ParameterError.Wrap(err, fmt.Sprintf("workflow node %q", nodeReq.Name)))
+		return nil, false
+	}
+	scheduleType, err := common.validateScheduleOptions(allowBackfill)
+	if err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.ParameterError.Wrap(err, fmt.Sprintf("workflow node %q", nodeReq.Name)))
+		return nil, false
+	}
+	scheduleMetadata, err := mgr.jobs.resolveJobScheduleMetadata(c.Request.Context(), scheduleType)
+	if err != nil {
+		resputil.Error(c, err.Error(), resputil.ServiceError)
+		return nil, false
+	}
+	if !mgr.jobs.preCheckCreateJob(c, token, scheduleType, false) {
+		return nil, false
+	}
+	if common.AlertEnabled && !utils.CheckUserEmail(c, token.UserID) {
+		resputil.Error(c, "Email not verified", resputil.UserEmailNotVerified)
+		return nil, false
+	}
+
+	job, err := build(c, scheduleMetadata)
+	if err != nil {
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+		return nil, false
+	}
+	return job, true
+}
+
+// admitWorkflowNodeJob runs the checks a direct submit applies before the job may queue, so a
+// held node never waits for a quota its job cannot fit in.
+func (mgr *WorkflowMgr) admitWorkflowNodeJob(ctx context.Context, token util.JWTMessage, job *batch.Job) error {
+	if err := mgr.jobs.ensureJobAdmitted(ctx, job); err != nil {
+		return err
+	}
+	scheduleType, err := model.ParseScheduleType(job.Annotations[vcjobservice.AnnotationKeyScheduleType])
+	if err != nil {
+		return err
+	}
+	resources := utils.ToStringMap(vcjobservice.CalculateJobResources(job))
+	_, err = mgr.jobs.checkSubmissionQuota(ctx, token, job, scheduleType, resources)
+	return err
+}
+
+// persistWorkflow stores the workflow rows. Nodes with parents get their job record
+// right away so they show up in the job list, held in the Prequeue phase.
+func (mgr *WorkflowMgr) persistWorkflow(
+	ctx context.Context,
+	token util.JWTMessage,
+	name string,
+	nodeJobs []workflowNodeJob,
+) (*model.Workflow, error) {
+	workflow := &model.Workflow{
+		Name:      name,
+		UserID:    token.UserID,
+		AccountID: token.AccountID,
+	}
+	nodes := make([]*model.WorkflowNode, 0, len(nodeJobs))
+	heldRecords := make([]*model.Job, 0, len(nodeJobs))
+	for _, nodeJob := range nodeJobs {
+		node := service.NewWorkflowNode(nodeJob.spec, nodeJob.job.Name)
+		nodes = append(nodes, node)
+		if !node.Held {
+			continue
+		}
+		record, err := vcjobservice.GenerateJobRecord(nodeJob.job, token.UserID, token.AccountID, model.Prequeue)
+		if err != nil {
+			return nil, err
+		}
+		heldRecords = append(heldRecords, record)
+	}
+	if err := mgr.workflowService.CreateWorkflow(ctx, workflow, nodes, heldRecords); err != nil {
+		return nil, err
+	}
+	return workflow, nil
+}
+
+// abortWorkflow cancels a workflow whose root nodes could not all be submitted.
+func (mgr *WorkflowMgr) abortWorkflow(c *gin.Context, token util.JWTMessage, id uint) {
+	active, err := mgr.workflowService.CancelWorkflow(c, id, token.UserID, token.AccountID)
+	if err != nil {
+		klog.Errorf("failed to cancel workflow %d after submission error: %v", id, err)
+		return
+	}
+	for _, jobName := range active {
+		if err := mgr.jobs.deleteActiveJob(c, jobName); err != nil {
+			klog.Errorf("failed to delete job %s of workflow %d: %v", jobName, id, err)
+		}
+	}
+}
+
+// ListWorkflows godoc
+//
+//	@Summary		List workflows
+//	@Description	List the workflows of the current user in the current account
+//	@Tags			Workflow
+//	@Produce		json
+//	@Security		Bearer
+//	@Success		200	{object}	resputil.Response[[]WorkflowResp]	"Success"
+//	@Failure		500	{object}	resputil.Response[any]				"Other errors"
+//	@Router			/v1/workflows [get]
+func (mgr *WorkflowMgr) ListWorkflows(c *gin.Context) {
+	token := util.GetToken(c)
+	if mgr.workflowService == nil {
+		resputil.Success(c, []WorkflowResp{})
+		return
+	}
+	workflows, err := mgr.workflowService.ListWorkflows(c, token.UserID, token.AccountID)
+	if err != nil {
+		resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "list workflows"))
+		return
+	}
+	resp := make([]WorkflowResp, 0, len(workflows))
+	for _, workflow := range workflows {
+		resp = append(resp, WorkflowResp{
+			ID:        workflow.ID,
+			Name:      workflow.Name,
+			Status:    workflow.Status,
+			CreatedAt: workflow.CreatedAt,
+		})
+	}
+	resputil.Success(c, resp)
+}
+
+// GetWorkflow godoc
+//
+//	@Summary		Get a workflow
+//	@Description	Get a workflow with the status of every node
+//	@Tags			Workflow
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			id	path		int								true	"Workflow ID"
+//	@Success		200	{object}	resputil.Response[WorkflowResp]	"Success"
+//	@Failure		404	{object}	resputil.Response[any]			"Workflow not found"
+//	@Router			/v1/workflows/{id} [get]
+func (mgr *WorkflowMgr) GetWorkflow(c *gin.Context) {
+	var req WorkflowIDReq
+	if err := c.ShouldBindUri(&req); err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.InvalidRequest.Wrap(err, "invalid workflow id"))
+		return
+	}
+	if mgr.workflowService == nil {
+		resputil.HandleError(c, bizerr.NotFound.DataBaseNotFound.New(fmt.Sprintf("workflow %d not found", req.ID)))
+		return
+	}
+	mgr.respondWorkflow(c, util.GetToken(c), req.ID)
+}
+
+// CancelWorkflow godoc
+//
+//	@Summary		Cancel a workflow
+//	@Description	Drop the nodes that have not started yet and delete the running ones
+//	@Tags			Workflow
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			id	path		int								true	"Workflow ID"
+//	@Success		200	{object}	resputil.Response[WorkflowResp]	"Success"
+//	@Failure		404	{object}	resputil.Response[any]			"Workflow not found"
+//	@Failure		409	{object}	resputil.Response[any]			"Workflow already finished"
+//	@Router			/v1/workflows/{id} [delete]
+func (mgr *WorkflowMgr) CancelWorkflow(c *gin.Context) {
+	token := util.GetToken(c)
+	var req WorkflowIDReq
+	if err := c.ShouldBindUri(&req); err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.InvalidRequest.Wrap(err, "invalid workflow id"))
+		return
+	}
+	if mgr.workflowService == nil {
+		resputil.HandleError(c, bizerr.NotFound.DataBaseNotFound.New(fmt.Sprintf("workflow %d not found", req.ID)))
+		return
+	}
+	active, err := mgr.workflowService.CancelWorkflow(c, req.ID, token.UserID, token.AccountID)
+	if err != nil {
+		resputil.HandleError(c, err)
+		return
+	}
+	for _, jobName := range active {
+		if err := mgr.jobs.deleteActiveJob(c, jobName); err != nil {
+			resputil.Error(c, fmt.Sprintf("failed to delete job %s: %v", jobName, err), resputil.NotSpecified)
+			return
+		}
+	}
+	mgr.respondWorkflow(c, token, req.ID)
+}
+
+func (mgr *WorkflowMgr) respondWorkflow(c *gin.Context, token util.JWTMessage, id uint) {
+	workflow, states, err := mgr.workflowService.GetWorkflow(c, id, token.UserID, token.AccountID)
+	if err != nil {
+		resputil.HandleError(c, err)
+		return
+	}
+	resp := WorkflowResp{
+		ID:        workflow.ID,
+		Name:      workflow.Name,
+		Status:    workflow.Status,
+		CreatedAt: workflow.CreatedAt,
+		Nodes:     make([]WorkflowNodeResp, 0, len(states)),
+	}
+	for _, state := range states {
+		status := string(state.JobStatus)
+		switch {
+		case state.Node.Held:
+			status = workflowNodeStatusHeld
+		case state.Node.Skipped:
+			status = workflowNodeStatusSkipped
+		}
+		resp.Nodes = append(resp.Nodes, WorkflowNodeResp{
+			Name:      state.Node.Name,
+			JobName:   state.Node.JobName,
+			DependsOn: state.Node.DependsOn.Data(),
+			Status:    status,
+		})
+	}
+	resputil.Success(c, resp)
+}
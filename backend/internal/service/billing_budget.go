@@ -0,0 +1,563 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	bus "volcano.sh/apis/pkg/apis/bus/v1alpha1"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/bizerr"
+	"github.com/raids-lab/crater/pkg/alert"
+	"github.com/raids-lab/crater/pkg/config"
+	"github.com/raids-lab/crater/pkg/utils"
+)
+
+const billingBudgetCapReason = "BudgetCapExceeded"
+
+// BillingBudgetStatus is a budget together with the spend of its current issue period.
+type BillingBudgetStatus struct {
+	Budget      *model.BillingBudget
+	PeriodStart time.Time
+	// IssueAmount is what the period issues to the budget scope, the base of alert percents.
+	IssueAmount int64
+	Spent       int64
+	// Projected adds what running normal jobs cost until the next settlement tick.
+	Projected int64
+}
+
+// CapReached reports whether the hard cap is used up.
+func (st *BillingBudgetStatus) CapReached() bool {
+	return st.Budget.HardCap != nil && st.Spent >= *st.Budget.HardCap
+}
+
+// budgetJobBurn is a running normal job and its cost over one settlement interval.
+type budgetJobBurn struct {
+	job  *model.Job
+	burn int64
+}
+
+// SetClient enables hard budget caps, which stop or suspend Volcano jobs.
+func (s *BillingService) SetClient(cli client.Client) {
+	s.client = cli
+}
+
+func (s *BillingService) alertMgr() alert.AlertInterface {
+	if s.alerter == nil {
+		s.alerter = alert.GetAlertMgr()
+	}
+	return s.alerter
+}
+
+// ListBudgets returns the budgets of an account with the spend of the current period.
+func (s *BillingService) ListBudgets(ctx context.Context, accountID uint) ([]*BillingBudgetStatus, error) {
+	bb := s.q.BillingBudget
+	budgets, err := bb.WithContext(ctx).Where(bb.AccountID.Eq(accountID)).Order(bb.UserID).Find()
+	if err != nil {
+		return nil, err
+	}
+	priceMap, err := s.loadUnitPriceMap(ctx)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]*BillingBudgetStatus, 0, len(budgets))
+	for _, budget := range budgets {
+		status, _, err := s.budgetStatus(ctx, budget, priceMap)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// UpsertBudget creates or replaces the budget of an account or of one of its members.
+func (s *BillingService) UpsertBudget(ctx context.Context, budget *model.BillingBudget) (*model.BillingBudget, error) {
+	if budget.CapAction == "" {
+		budget.CapAction = model.BillingBudgetCapStop
+	}
+	if budget.AlertPercents.Data() == nil {
+		budget.AlertPercents = datatypes.NewJSONType(slices.Clone(model.DefaultBillingBudgetAlertPercents))
+	}
+	if err := budget.Validate(); err != nil {
+		return nil, bizerr.BadRequest.ParameterError.Wrap(err, "invalid budget")
+	}
+	if budget.UserID != 0 {
+		ua := s.q.UserAccount
+		if _, err := ua.WithContext(ctx).Where(ua.UserID.Eq(budget.UserID), ua.AccountID.Eq(budget.AccountID)).First(); err != nil {
+			return nil, bizerr.NotFound.DataBaseNotFound.Wrap(err, "user is not a member of the account")
+		}
+	}
+
+	bb := s.q.BillingBudget
+	existing, err := bb.WithContext(ctx).Where(bb.AccountID.Eq(budget.AccountID), bb.UserID.Eq(budget.UserID)).Find()
+	if err != nil {
+		return nil, bizerr.Internal.DatabaseError.Wrap(err, "failed to load budget")
+	}
+	if len(existing) > 0 {
+		budget.ID = existing[0].ID
+		budget.CreatedAt = existing[0].CreatedAt
+	}
+	if err := bb.WithContext(ctx).Save(budget); err != nil {
+		return nil, bizerr.Internal.DatabaseError.Wrap(err, "failed to save budget")
+	}
+	return budget, nil
+}
+
+// DeleteBudget removes a budget; userID 0 is the account-wide budget.
+func (s *BillingService) DeleteBudget(ctx context.Context, accountID, userID uint) error {
+	bb := s.q.BillingBudget
+	info, err := bb.WithContext(ctx).Unscoped().Where(bb.AccountID.Eq(accountID), bb.UserID.Eq(userID)).Delete()
+	if err != nil {
+		return bizerr.Internal.DatabaseError.Wrap(err, "failed to delete budget")
+	}
+	if info.RowsAffected == 0 {
+		return bizerr.NotFound.DataBaseNotFound.New("budget not found")
+	}
+	return nil
+}
+
+// checkBudgetCapsForJobCreate blocks new normal jobs once the account or the
+// member has used up a hard cap, rather than starting jobs only to stop them.
+func (s *BillingService) checkBudgetCapsForJobCreate(ctx context.Context, userID, accountID uint) error {
+	bb := s.q.BillingBudget
+	budgets, err := bb.WithContext(ctx).
+		Where(bb.AccountID.Eq(accountID), bb.UserID.In(0, userID), bb.HardCap.IsNotNull()).
+		Find()
+	if err != nil || len(budgets) == 0 {
+		return err
+	}
+	account, err := s.loadBudgetAccount(ctx, accountID)
+	if err != nil {
+		return err
+	}
+	for _, budget := range budgets {
+		spent, err := s.budgetSpent(ctx, budget, budgetPeriodStart(account))
+		if err != nil {
+			return err
+		}
+		if spent >= *budget.HardCap {
+			return fmt.Errorf("billing precheck blocked: %s spent %.2f of its %.2f points budget cap in this period",
+				budgetScopeName(budget), ToDisplayPoints(spent), ToDisplayPoints(*budget.HardCap))
+		}
+	}
+	return nil
+}
+
+// enforceBudgets runs after each running settlement tick: it sends soft threshold
+// alerts and stops or suspends running normal jobs whose cost until the next tick
+// would push the scope over its hard cap.
+func (s *BillingService) enforceBudgets(ctx context.Context) error {
+	budgets, err := s.q.BillingBudget.WithContext(ctx).Find()
+	if err != nil || len(budgets) == 0 {
+		return err
+	}
+	priceMap, err := s.loadUnitPriceMap(ctx)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, budget := range budgets {
+		status, burns, err := s.budgetStatus(ctx, budget, priceMap)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := s.notifyBudgetThresholds(ctx, status); err != nil {
+			errs = append(errs, err)
+		}
+		if budget.HardCap == nil {
+			continue
+		}
+		for _, victim := range selectJobsOverBudgetCap(status.Spent, *budget.HardCap, burns) {
+			if err := s.applyBudgetCapAction(ctx, status, victim.job); err != nil {
+				errs = append(errs, fmt.Errorf("budget cap on job %s: %w", victim.job.JobName, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *BillingService) budgetStatus(
+	ctx context.Context,
+	budget *model.BillingBudget,
+	priceMap map[string]int64,
+) (*BillingBudgetStatus, []budgetJobBurn, error) {
+	account, err := s.loadBudgetAccount(ctx, budget.AccountID)
+	if err != nil {
+		return nil, nil, err
+	}
+	status := &BillingBudgetStatus{Budget: budget, PeriodStart: budgetPeriodStart(account)}
+	if status.IssueAmount, err = s.budgetIssueAmount(ctx, budget, account); err != nil {
+		return nil, nil, err
+	}
+	if status.Spent, err = s.budgetSpent(ctx, budget, status.PeriodStart); err != nil {
+		return nil, nil, err
+	}
+
+	j := s.q.Job
+	do := j.WithContext(ctx).Where(j.AccountID.Eq(budget.AccountID), j.Status.Eq(string(batch.Running)),
+		j.ScheduleType.Eq(int(model.ScheduleTypeNormal)))
+	if budget.UserID != 0 {
+		do = do.Where(j.UserID.Eq(budget.UserID))
+	}
+	jobs, err := do.Find()
+	if err != nil {
+		return nil, nil, err
+	}
+	// Free minutes are ignored, so the projection errs on the side of the cap.
+	interval := time.Duration(s.GetRunningSettlementIntervalMinutes(ctx)) * time.Minute
+	status.Projected = status.Spent
+	burns := make([]budgetJobBurn, 0, len(jobs))
+	for _, job := range jobs {
+		burn := calcJobCostMicroPoints(job.Resources.Data(), priceMap, interval, billingMultiplierForJob(job))
+		if burn <= 0 {
+			continue
+		}
+		burns = append(burns, budgetJobBurn{job: job, burn: burn})
+		status.Projected += burn
+	}
+	return status, burns, nil
+}
+
+func (s *BillingService) loadBudgetAccount(ctx context.Context, accountID uint) (*model.Account, error) {
+	a := s.q.Account
+	return a.WithContext(ctx).Where(a.ID.Eq(accountID)).First()
+}
+
+// budgetPeriodStart is the last issuance of the account, or its creation when it never issued.
+func budgetPeriodStart(account *model.Account) time.Time {
+	if account.BillingLastIssuedAt != nil {
+		return *account.BillingLastIssuedAt
+	}
+	return account.CreatedAt
+}
+
+func (s *BillingService) budgetIssueAmount(ctx context.Context, budget *model.BillingBudget, account *model.Account) (int64, error) {
+	ua := s.q.UserAccount
+	do := ua.WithContext(ctx).Where(ua.AccountID.Eq(budget.AccountID))
+	if budget.UserID != 0 {
+		do = do.Where(ua.UserID.Eq(budget.UserID))
+	}
+	members, err := do.Find()
+	if err != nil {
+		return 0, err
+	}
+	var total int64
+	for _, member := range members {
+		amount, _ := s.ResolveEffectiveIssueConfigForUserAccount(ctx, member, account)
+		total += amount
+	}
+	return total, nil
+}
+
+// budgetSpent sums the settlement debits since the period start from the billing
+// ledger. Extra balance debits are not tied to an account in the ledger, so jobs
+// of the account select them.
+func (s *BillingService) budgetSpent(ctx context.Context, budget *model.BillingBudget, since time.Time) (int64, error) {
+	j := s.q.Job
+	jobDo := j.WithContext(ctx).Unscoped().Where(j.AccountID.Eq(budget.AccountID), j.LastSettledAt.Gte(since))
+	if budget.UserID != 0 {
+		jobDo = jobDo.Where(j.UserID.Eq(budget.UserID))
+	}
+	var jobIDs []uint
+	if err := jobDo.Pluck(j.ID, &jobIDs); err != nil {
+		return 0, err
+	}
+	if len(jobIDs) == 0 {
+		return 0, nil
+	}
+	le := s.q.BillingLedgerEntry
+	var debit struct{ Total int64 }
+	if err := le.WithContext(ctx).
+		Select(le.Delta.Sum().As("total")).
+		Where(le.Reason.Eq(string(model.BillingLedgerSettlement)), le.CreatedAt.Gte(since), le.JobID.In(jobIDs...)).
+		Scan(&debit); err != nil {
+		return 0, err
+	}
+	return -debit.Total, nil
+}
+
+func (s *BillingService) loadUnitPriceMap(ctx context.Context) (map[string]int64, error) {
+	r := s.q.Resource
+	resources, err := r.WithContext(ctx).Find()
+	if err != nil {
+		return nil, err
+	}
+	priceMap := make(map[string]int64, len(resources))
+	for _, resource := range resources {
+		priceMap[resource.ResourceName] = resource.UnitPrice
+	}
+	return priceMap, nil
+}
+
+// budgetThresholdsCrossed returns the alert percents the spend has reached, highest last.
+func budgetThresholdsCrossed(percents []int, issueAmount, spent int64) []int {
+	if issueAmount <= 0 {
+		return nil
+	}
+	crossed := make([]int, 0, len(percents))
+	for _, percent := range percents {
+		if spent*100 >= issueAmount*int64(percent) {
+			crossed = append(crossed, percent)
+		}
+	}
+	slices.Sort(crossed)
+	return slices.Compact(crossed)
+}
+
+// selectJobsOverBudgetCap picks the most expensive jobs first until the cost of the
+// remaining ones until the next tick fits under the cap. Once the cap is used up
+// every billable job is picked.
+func selectJobsOverBudgetCap(spent, hardCap int64, burns []budgetJobBurn) []budgetJobBurn {
+	projected := spent
+	for _, b := range burns {
+		projected += b.burn
+	}
+	if projected <= hardCap {
+		return nil
+	}
+	sorted := slices.Clone(burns)
+	slices.SortStableFunc(sorted, func(a, b budgetJobBurn) int {
+		return cmp.Or(cmp.Compare(b.burn, a.burn), cmp.Compare(a.job.ID, b.job.ID))
+	})
+	var victims []budgetJobBurn
+	for _, b := range sorted {
+		if projected <= hardCap && spent < hardCap {
+			break
+		}
+		victims = append(victims, b)
+		projected -= b.burn
+	}
+	return victims
+}
+
+func budgetScopeName(budget *model.BillingBudget) string {
+	if budget.UserID != 0 {
+		return fmt.Sprintf("user %d in account %d", budget.UserID, budget.AccountID)
+	}
+	return fmt.Sprintf("account %d", budget.AccountID)
+}
+
+// budgetAlertKey dedupes alerts per budget and period through the alerts table.
+func budgetAlertKey(status *BillingBudgetStatus) string {
+	return fmt.Sprintf("billing-budget-%d-%d-%d", status.Budget.AccountID, status.Budget.UserID, status.PeriodStart.Unix())
+}
+
+// budgetRecipients are the member of a user budget, or the account administrators.
+func (s *BillingService) budgetRecipients(ctx context.Context, budget *model.BillingBudget) ([]uint, error) {
+	if budget.UserID != 0 {
+		return []uint{budget.UserID}, nil
+	}
+	ua := s.q.UserAccount
+	var userIDs []uint
+	err := ua.WithContext(ctx).
+		Where(ua.AccountID.Eq(budget.AccountID), ua.Role.Eq(uint8(model.RoleAdmin))).
+		Pluck(ua.UserID, &userIDs)
+	return userIDs, err
+}
+
+func (s *BillingService) notifyBudgetThresholds(ctx context.Context, status *BillingBudgetStatus) error {
+	crossed := budgetThresholdsCrossed(status.Budget.AlertPercents.Data(), status.IssueAmount, status.Spent)
+	if len(crossed) == 0 {
+		return nil
+	}
+	// Only the highest threshold is announced; lower ones are marked as sent with it.
+	key := budgetAlertKey(status)
+	a := s.q.Alert
+	var sent []string
+	if err := a.WithContext(ctx).Where(a.JobName.Eq(key)).Pluck(a.AlertType, &sent); err != nil {
+		return err
+	}
+	highest := crossed[len(crossed)-1]
+	if slices.Contains(sent, budgetAlertType(highest)) {
+		return nil
+	}
+	recipients, err := s.budgetRecipients(ctx, status.Budget)
+	if err != nil {
+		return err
+	}
+	account, err := s.loadBudgetAccount(ctx, status.Budget.AccountID)
+	if err != nil {
+		return err
+	}
+	scope := fmt.Sprintf("账户 <strong>%s</strong>", account.Nickname)
+	if status.Budget.UserID != 0 {
+		scope = fmt.Sprintf("您在账户 <strong>%s</strong> 中", account.Nickname)
+	}
+	message := fmt.Sprintf("%s本周期已消费 %.2f 点，达到周期发放额度 %.2f 点的 %d%%。",
+		scope, ToDisplayPoints(status.Spent), ToDisplayPoints(status.IssueAmount), highest)
+	if status.Budget.HardCap != nil {
+		message += fmt.Sprintf("超过消费上限 %.2f 点后，运行中的普通作业将被%s。",
+			ToDisplayPoints(*status.Budget.HardCap), budgetCapActionName(status.Budget.CapAction))
+	}
+	var errs []error
+	for _, userID := range recipients {
+		notice := &alert.Notice{
+			Title:      fmt.Sprintf("账户预算已使用 %d%%", highest),
+			Message:    message,
+			URL:        fmt.Sprintf("https://%s/portal/overview", config.GetConfig().Host),
+			ButtonText: "查看账单",
+		}
+		if err := s.alertMgr().NotifyUserEvent(ctx, userID, status.Budget.AccountID,
+			model.NotificationEventBudgetThreshold, notice); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, percent := range crossed {
+		if slices.Contains(sent, budgetAlertType(percent)) {
+			continue
+		}
+		if err := a.WithContext(ctx).Create(&model.Alert{
+			JobName:        key,
+			AlertType:      budgetAlertType(percent),
+			AlertTimestamp: utils.GetLocalTime(),
+			SendCount:      1,
+		}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func budgetAlertType(percent int) string {
+	return fmt.Sprintf("%s/%d", model.NotificationEventBudgetThreshold, percent)
+}
+
+func budgetCapActionName(action model.BillingBudgetCapAction) string {
+	if action == model.BillingBudgetCapSuspend {
+		return "挂起"
+	}
+	return "停止"
+}
+
+// applyBudgetCapAction stops or suspends one job and tells its owner why.
+func (s *BillingService) applyBudgetCapAction(ctx context.Context, status *BillingBudgetStatus, job *model.Job) error {
+	if s.client == nil {
+		return fmt.Errorf("kubernetes client is not configured for budget caps")
+	}
+	vcjob := &batch.Job{}
+	namespace := config.GetConfig().Namespaces.Job
+	err := s.client.Get(ctx, client.ObjectKey{Name: job.JobName, Namespace: namespace}, vcjob)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil
+
+	switch status.Budget.CapAction {
+	case model.BillingBudgetCapSuspend:
+		if !exists {
+			return nil
+		}
+		// The bus controller removes a command once handled, so a pending one means the
+		// previous tick already suspended the job and sent the notice.
+		pending, err := s.hasPendingBudgetCapCommand(ctx, vcjob)
+		if err != nil || pending {
+			return err
+		}
+		owner := metav1.NewControllerRef(vcjob, batch.SchemeGroupVersion.WithKind("Job"))
+		command := &bus.Command{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName:    strings.ToLower(job.JobName) + "-budget-",
+				Namespace:       namespace,
+				OwnerReferences: []metav1.OwnerReference{*owner},
+			},
+			TargetObject: owner,
+			Action:       string(bus.AbortJobAction),
+			Reason:       billingBudgetCapReason,
+			Message:      "billing budget cap exceeded",
+		}
+		if err := s.client.Create(ctx, command); err != nil {
+			return err
+		}
+	default:
+		if err := s.settleAndMarkDeleted(ctx, job.ID, time.Now()); err != nil {
+			return err
+		}
+		if exists {
+			if err := s.client.Delete(ctx, vcjob); err != nil && !k8serrors.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+	klog.Warningf("[Billing] budget cap of %s exceeded, %s job %s (spent=%d cap=%d)",
+		budgetScopeName(status.Budget), status.Budget.CapAction, job.JobName, status.Spent, *status.Budget.HardCap)
+
+	// A resumed job may be capped again within the period; its owner is told only once.
+	a := s.q.Alert
+	sent, err := a.WithContext(ctx).Where(a.JobName.Eq(job.JobName), a.AlertType.Eq(budgetCapAlertType(status))).Count()
+	if err != nil || sent > 0 {
+		return err
+	}
+	if err := a.WithContext(ctx).Create(&model.Alert{
+		JobName:        job.JobName,
+		AlertType:      budgetCapAlertType(status),
+		AlertTimestamp: utils.GetLocalTime(),
+		SendCount:      1,
+	}); err != nil {
+		return err
+	}
+	notice := &alert.Notice{
+		Title: "作业因预算上限被" + budgetCapActionName(status.Budget.CapAction),
+		Message: fmt.Sprintf("作业 <strong>%s</strong> 所在的预算本周期已消费 %.2f 点，预计将超过消费上限 %.2f 点，作业已被%s。",
+			job.Name, ToDisplayPoints(status.Spent), ToDisplayPoints(*status.Budget.HardCap),
+			budgetCapActionName(status.Budget.CapAction)),
+		URL:        fmt.Sprintf("https://%s/portal/jobs/detail/%s", config.GetConfig().Host, job.JobName),
+		ButtonText: "查看作业",
+	}
+	return s.alertMgr().NotifyUserEvent(ctx, job.UserID, job.AccountID, model.NotificationEventBudgetCapReached, notice)
+}
+
+func budgetCapAlertType(status *BillingBudgetStatus) string {
+	return fmt.Sprintf("%s/%d", model.NotificationEventBudgetCapReached, status.PeriodStart.Unix())
+}
+
+// hasPendingBudgetCapCommand reports whether a budget cap command for the job awaits the bus controller.
+func (s *BillingService) hasPendingBudgetCapCommand(ctx context.Context, vcjob *batch.Job) (bool, error) {
+	commands := &bus.CommandList{}
+	if err := s.client.List(ctx, commands, client.InNamespace(vcjob.Namespace)); err != nil {
+		return false, err
+	}
+	for i := range commands.Items {
+		command := &commands.Items[i]
+		if command.Reason == billingBudgetCapReason && command.TargetObject != nil &&
+			command.TargetObject.Name == vcjob.Name && command.TargetObject.UID == vcjob.UID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// settleAndMarkDeleted bills the job up to completedAt before marking it Deleted, as deleting
+// a job does; the reconciler does not settle jobs that are already Deleted.
+func (s *BillingService) settleAndMarkDeleted(ctx context.Context, jobID uint, completedAt time.Time) error {
+	db := s.q.Job.WithContext(ctx).UnderlyingDB().Session(&gorm.Session{NewDB: true})
+	return db.Transaction(func(tx *gorm.DB) error {
+		priceMap, err := loadUnitPriceMapTx(ctx, tx)
+		if err != nil {
+			return err
+		}
+		if _, err := settleOneJobTx(ctx, tx, jobID, completedAt, priceMap); err != nil {
+			return err
+		}
+		j := query.Use(tx).Job
+		_, err = j.WithContext(ctx).Where(j.ID.Eq(jobID)).Updates(model.Job{
+			Status:             model.Deleted,
+			CompletedTimestamp: completedAt,
+		})
+		return err
+	})
+}
@@ -0,0 +1,329 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+	bus "volcano.sh/apis/pkg/apis/bus/v1alpha1"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/pkg/alert"
+	"github.com/raids-lab/crater/pkg/config"
+)
+
+func TestBudgetThresholdsCrossed(t *testing.T) {
+	issue := 100 * BillingPointScale
+	tests := []struct {
+		spent int64
+		want  []int
+	}{
+		{49 * BillingPointScale, []int{}},
+		{50 * BillingPointScale, []int{50}},
+		{99 * BillingPointScale, []int{50, 80}},
+		{120 * BillingPointScale, []int{50, 80, 100}},
+	}
+	for _, tt := range tests {
+		if got := budgetThresholdsCrossed([]int{100, 50, 80, 80}, issue, tt.spent); !slices.Equal(got, tt.want) {
+			t.Errorf("spent %d: budgetThresholdsCrossed() = %v, want %v", tt.spent, got, tt.want)
+		}
+	}
+	if got := budgetThresholdsCrossed([]int{50}, 0, BillingPointScale); got != nil {
+		t.Errorf("zero issue amount crossed %v, want none", got)
+	}
+}
+
+func TestSelectJobsOverBudgetCap(t *testing.T) {
+	burns := []budgetJobBurn{
+		{job: &model.Job{Model: gorm.Model{ID: 1}}, burn: 10},
+		{job: &model.Job{Model: gorm.Model{ID: 2}}, burn: 30},
+		{job: &model.Job{Model: gorm.Model{ID: 3}}, burn: 20},
+	}
+	victimIDs := func(victims []budgetJobBurn) []uint {
+		ids := make([]uint, 0, len(victims))
+		for _, v := range victims {
+			ids = append(ids, v.job.ID)
+		}
+		return ids
+	}
+	if got := selectJobsOverBudgetCap(40, 100, burns); got != nil {
+		t.Fatalf("projection within cap selected %v", victimIDs(got))
+	}
+	if got := victimIDs(selectJobsOverBudgetCap(60, 80, burns)); !slices.Equal(got, []uint{2, 3}) {
+		t.Fatalf("over cap selected %v, want the most expensive jobs [2 3]", got)
+	}
+	if got := victimIDs(selectJobsOverBudgetCap(100, 100, burns)); !slices.Equal(got, []uint{2, 3, 1}) {
+		t.Fatalf("used up cap selected %v, want every job", got)
+	}
+}
+
+func TestBillingBudgetSpendAndJobCreateCap(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:billing_budget_spend?mode=memory&cache=shared"), &gorm.Config{
+		DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.Migrator().CreateTable(
+		&model.Account{}, &model.UserAccount{}, &model.Job{}, &model.BillingLedgerEntry{}, &model.BillingBudget{},
+		&model.Resource{}, &model.SystemConfig{},
+	); err != nil {
+		t.Fatalf("create tables: %v", err)
+	}
+	svc := NewBillingService(query.Use(db))
+	ctx := context.Background()
+
+	periodStart := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	if err := db.Create(&model.Account{
+		Model: gorm.Model{ID: 2}, Name: "vision", Nickname: "Vision", Space: "/q/vision", BillingLastIssuedAt: &periodStart,
+	}).Error; err != nil {
+		t.Fatalf("create account: %v", err)
+	}
+	for _, userID := range []uint{7, 8} {
+		if err := db.Create(&model.UserAccount{UserID: userID, AccountID: 2}).Error; err != nil {
+			t.Fatalf("create member: %v", err)
+		}
+	}
+	settled := periodStart.Add(48 * time.Hour)
+	for _, job := range []*model.Job{
+		{Model: gorm.Model{ID: 11}, JobName: "sg-7-1", UserID: 7, AccountID: 2, LastSettledAt: &settled},
+		{Model: gorm.Model{ID: 12}, JobName: "sg-8-1", UserID: 8, AccountID: 2, LastSettledAt: &settled},
+		{Model: gorm.Model{ID: 13}, JobName: "sg-7-2", UserID: 7, AccountID: 3, LastSettledAt: &settled},
+	} {
+		if err := db.Create(job).Error; err != nil {
+			t.Fatalf("create job: %v", err)
+		}
+	}
+	ledger := []*model.BillingLedgerEntry{
+		{CreatedAt: settled, UserID: 7, AccountID: 2, Balance: model.BillingLedgerPeriodFree, Reason: model.BillingLedgerSettlement,
+			Delta: -30 * BillingPointScale, JobID: 11},
+		{CreatedAt: settled, UserID: 7, Balance: model.BillingLedgerExtra, Reason: model.BillingLedgerSettlement,
+			Delta: -5 * BillingPointScale, JobID: 11},
+		{CreatedAt: settled, UserID: 8, AccountID: 2, Balance: model.BillingLedgerPeriodFree, Reason: model.BillingLedgerSettlement,
+			Delta: -20 * BillingPointScale, JobID: 12},
+		{CreatedAt: settled, UserID: 7, AccountID: 3, Balance: model.BillingLedgerPeriodFree, Reason: model.BillingLedgerSettlement,
+			Delta: -90 * BillingPointScale, JobID: 13},
+		{CreatedAt: periodStart.Add(-time.Hour), UserID: 7, AccountID: 2, Balance: model.BillingLedgerPeriodFree,
+			Reason: model.BillingLedgerSettlement, Delta: -90 * BillingPointScale, JobID: 11},
+	}
+	if err := AppendBillingLedgerTx(db, ledger...); err != nil {
+		t.Fatalf("append ledger: %v", err)
+	}
+
+	userCap := 40 * BillingPointScale
+	if _, err := svc.UpsertBudget(ctx, &model.BillingBudget{AccountID: 2, UserID: 7, HardCap: &userCap}); err != nil {
+		t.Fatalf("UpsertBudget() error = %v", err)
+	}
+	accountCap := 50 * BillingPointScale
+	if _, err := svc.UpsertBudget(ctx, &model.BillingBudget{
+		AccountID: 2, HardCap: &accountCap, AlertPercents: datatypes.NewJSONType([]int{80}),
+	}); err != nil {
+		t.Fatalf("UpsertBudget() error = %v", err)
+	}
+	if _, err := svc.UpsertBudget(ctx, &model.BillingBudget{AccountID: 2, UserID: 99}); err == nil {
+		t.Fatal("UpsertBudget() accepted a user outside the account")
+	}
+
+	statuses, err := svc.ListBudgets(ctx, 2)
+	if err != nil {
+		t.Fatalf("ListBudgets() error = %v", err)
+	}
+	if len(statuses) != 2 {
+		t.Fatalf("ListBudgets() returned %d budgets, want 2", len(statuses))
+	}
+	account, user := statuses[0], statuses[1]
+	if account.Spent != 55*BillingPointScale || !account.CapReached() {
+		t.Fatalf("account budget spent %d capReached %v, want 55 points and reached", account.Spent, account.CapReached())
+	}
+	if user.Spent != 35*BillingPointScale || user.CapReached() {
+		t.Fatalf("user budget spent %d, want 35 points", user.Spent)
+	}
+	if !slices.Equal(user.Budget.AlertPercents.Data(), model.DefaultBillingBudgetAlertPercents) ||
+		user.Budget.CapAction != model.BillingBudgetCapStop {
+		t.Fatalf("user budget defaults = %+v", user.Budget)
+	}
+
+	err = svc.checkBudgetCapsForJobCreate(ctx, 7, 2)
+	if err == nil || !strings.Contains(err.Error(), "budget cap") {
+		t.Fatalf("checkBudgetCapsForJobCreate() = %v, want the account cap to block", err)
+	}
+	if err := svc.DeleteBudget(ctx, 2, 0); err != nil {
+		t.Fatalf("DeleteBudget() error = %v", err)
+	}
+	if err := svc.checkBudgetCapsForJobCreate(ctx, 7, 2); err != nil {
+		t.Fatalf("checkBudgetCapsForJobCreate() = %v, want the user cap to leave room", err)
+	}
+	if err := svc.DeleteBudget(ctx, 2, 0); err == nil {
+		t.Fatal("DeleteBudget() of a missing budget succeeded")
+	}
+}
+
+type recordingBudgetAlerter struct {
+	alert.AlertInterface
+	events []model.NotificationEvent
+}
+
+func (a *recordingBudgetAlerter) NotifyUserEvent(
+	_ context.Context, _, _ uint, event model.NotificationEvent, _ *alert.Notice,
+) error {
+	a.events = append(a.events, event)
+	return nil
+}
+
+func TestBudgetCapStopSettlesJob(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:billing_budget_cap_stop?mode=memory&cache=shared"), &gorm.Config{
+		DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.Migrator().CreateTable(
+		&model.UserAccount{}, &model.Job{}, &model.BillingLedgerEntry{}, &model.Resource{}, &model.SystemConfig{}, &model.Alert{},
+	); err != nil {
+		t.Fatalf("create tables: %v", err)
+	}
+	// jobs and users share the "status" index name, which sqlite keeps per database.
+	if err := db.Exec(`CREATE TABLE users (id integer primary key, created_at datetime, updated_at datetime,
+		deleted_at datetime, name text, extra_balance integer)`).Error; err != nil {
+		t.Fatalf("create users: %v", err)
+	}
+	if err := db.Exec(`INSERT INTO users (id, name, extra_balance) VALUES (7, 'alice', 0)`).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	if err := db.Create(&model.UserAccount{UserID: 7, AccountID: 2, PeriodFreeBalance: 100 * BillingPointScale}).Error; err != nil {
+		t.Fatalf("create user account: %v", err)
+	}
+	if err := db.Create(&model.Resource{ResourceName: "cpu", ResourceType: "default", UnitPrice: 10 * BillingPointScale}).Error; err != nil {
+		t.Fatalf("create resource: %v", err)
+	}
+	lastSettled := time.Now().Add(-30 * time.Minute)
+	job := &model.Job{
+		Model: gorm.Model{ID: 11}, Name: "train", JobName: "sg-alice-1", UserID: 7, AccountID: 2,
+		Status: batch.Running, RunningTimestamp: lastSettled.Add(-time.Hour), LastSettledAt: &lastSettled,
+		Resources: datatypes.NewJSONType(v1.ResourceList{v1.ResourceCPU: resource.MustParse("2")}),
+	}
+	if err := db.Create(job).Error; err != nil {
+		t.Fatalf("create job: %v", err)
+	}
+
+	scheme := runtime.NewScheme()
+	if err := batch.AddToScheme(scheme); err != nil {
+		t.Fatalf("add scheme: %v", err)
+	}
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&batch.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: job.JobName, Namespace: config.GetConfig().Namespaces.Job},
+	}).Build()
+	alerter := &recordingBudgetAlerter{}
+	svc := NewBillingService(query.Use(db))
+	svc.SetClient(cli)
+	svc.alerter = alerter
+
+	hardCap := 50 * BillingPointScale
+	status := &BillingBudgetStatus{
+		Budget: &model.BillingBudget{AccountID: 2, HardCap: &hardCap, CapAction: model.BillingBudgetCapStop},
+		Spent:  45 * BillingPointScale,
+	}
+	if err := svc.applyBudgetCapAction(context.Background(), status, job); err != nil {
+		t.Fatalf("applyBudgetCapAction() error = %v", err)
+	}
+
+	// 2 cpu at 10 points per cpu-hour for the 30 minutes since the last settlement.
+	var entries []model.BillingLedgerEntry
+	if err := db.Where("reason = ? AND job_id = ?", model.BillingLedgerSettlement, job.ID).Find(&entries).Error; err != nil {
+		t.Fatalf("load ledger: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Delta > -10*BillingPointScale || entries[0].Delta < -11*BillingPointScale {
+		t.Fatalf("settlement entries = %+v, want the last 10 points billed", entries)
+	}
+	stopped := &model.Job{}
+	if err := db.First(stopped, job.ID).Error; err != nil {
+		t.Fatalf("load job: %v", err)
+	}
+	if stopped.Status != model.Deleted || stopped.CompletedTimestamp.IsZero() || stopped.LastSettledAt.Before(lastSettled.Add(30*time.Minute)) {
+		t.Fatalf("stopped job = status %s completed %v settled %v", stopped.Status, stopped.CompletedTimestamp, stopped.LastSettledAt)
+	}
+	err = cli.Get(context.Background(), client.ObjectKey{Name: job.JobName, Namespace: config.GetConfig().Namespaces.Job}, &batch.Job{})
+	if !k8serrors.IsNotFound(err) {
+		t.Fatalf("vcjob still exists: %v", err)
+	}
+	if !slices.Equal(alerter.events, []model.NotificationEvent{model.NotificationEventBudgetCapReached}) {
+		t.Fatalf("notified %v", alerter.events)
+	}
+}
+
+func TestBudgetCapSuspendActsOnce(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:billing_budget_cap_suspend?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(&model.Alert{}); err != nil {
+		t.Fatalf("create tables: %v", err)
+	}
+	scheme := runtime.NewScheme()
+	if err := batch.AddToScheme(scheme); err != nil {
+		t.Fatalf("add scheme: %v", err)
+	}
+	if err := bus.AddToScheme(scheme); err != nil {
+		t.Fatalf("add scheme: %v", err)
+	}
+	namespace := config.GetConfig().Namespaces.Job
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&batch.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "sg-alice-2", Namespace: namespace, UID: "uid-2"},
+	}).Build()
+	alerter := &recordingBudgetAlerter{}
+	svc := NewBillingService(query.Use(db))
+	svc.SetClient(cli)
+	svc.alerter = alerter
+
+	hardCap := 50 * BillingPointScale
+	status := &BillingBudgetStatus{
+		Budget:      &model.BillingBudget{AccountID: 2, HardCap: &hardCap, CapAction: model.BillingBudgetCapSuspend},
+		Spent:       45 * BillingPointScale,
+		PeriodStart: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+	}
+	job := &model.Job{Name: "train", JobName: "sg-alice-2", UserID: 7, AccountID: 2}
+	for range 2 {
+		if err := svc.applyBudgetCapAction(context.Background(), status, job); err != nil {
+			t.Fatalf("applyBudgetCapAction() error = %v", err)
+		}
+	}
+
+	commands := &bus.CommandList{}
+	if err := cli.List(context.Background(), commands, client.InNamespace(namespace)); err != nil {
+		t.Fatalf("list commands: %v", err)
+	}
+	if len(commands.Items) != 1 || commands.Items[0].Action != string(bus.AbortJobAction) {
+		t.Fatalf("commands = %+v, want one abort command while the first is pending", commands.Items)
+	}
+
+	// Once the controller handled the command, a resumed job is suspended again without a second notice.
+	if err := cli.Delete(context.Background(), &commands.Items[0]); err != nil {
+		t.Fatalf("delete command: %v", err)
+	}
+	if err := svc.applyBudgetCapAction(context.Background(), status, job); err != nil {
+		t.Fatalf("applyBudgetCapAction() error = %v", err)
+	}
+	if err := cli.List(context.Background(), commands, client.InNamespace(namespace)); err != nil {
+		t.Fatalf("list commands: %v", err)
+	}
+	if len(commands.Items) != 1 {
+		t.Fatalf("commands = %d, want the resumed job suspended again", len(commands.Items))
+	}
+	if !slices.Equal(alerter.events, []model.NotificationEvent{model.NotificationEventBudgetCapReached}) {
+		t.Fatalf("notified %v, want a single cap notice", alerter.events)
+	}
+}
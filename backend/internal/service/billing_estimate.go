@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/internal/bizerr"
+)
+
+// BillingJobEstimate is the dry-run price of a job before it is submitted.
+// CoveredHours is nil when the job is free, i.e. billing is inactive, the job is
+// a backfill job or none of its resources are priced.
+type BillingJobEstimate struct {
+	Billable          bool     `json:"billable"`
+	CostPerHour       float64  `json:"costPerHour"`
+	FreeMinutes       int      `json:"freeMinutes"`
+	PeriodFreeBalance float64  `json:"periodFreeBalance"`
+	ExtraBalance      float64  `json:"extraBalance"`
+	CoveredHours      *float64 `json:"coveredHours"`
+}
+
+// EstimateJobCost prices the requested resources with the current unit prices the
+// same way the running settlement does, without touching any balance.
+func (s *BillingService) EstimateJobCost(
+	ctx context.Context,
+	userID, accountID uint,
+	resources v1.ResourceList,
+	scheduleType *model.ScheduleType,
+) (*BillingJobEstimate, error) {
+	estimate := &BillingJobEstimate{}
+	if !s.IsFeatureEnabled(ctx) || !s.IsActive(ctx) {
+		return estimate, nil
+	}
+
+	u := s.q.User
+	user, err := u.WithContext(ctx).Where(u.ID.Eq(userID)).First()
+	if err != nil {
+		return nil, bizerr.NotFound.DataBaseNotFound.Wrap(err, "user not found")
+	}
+	ua := s.q.UserAccount
+	userAccount, err := ua.WithContext(ctx).Where(ua.UserID.Eq(userID), ua.AccountID.Eq(accountID)).First()
+	if err != nil {
+		return nil, bizerr.NotFound.DataBaseNotFound.Wrap(err, "user is not a member of the account")
+	}
+	priceMap, err := s.loadUnitPriceMap(ctx)
+	if err != nil {
+		return nil, bizerr.Internal.DatabaseError.Wrap(err, "load resource prices failed")
+	}
+
+	costPerHour := calcJobCostMicroPoints(resources, priceMap, time.Hour, billingMultiplierForScheduleType(scheduleType))
+	freeMinutes := s.GetJobFreeMinutes(ctx)
+	estimate.Billable = costPerHour > 0
+	estimate.CostPerHour = ToDisplayPoints(costPerHour)
+	estimate.FreeMinutes = freeMinutes
+	estimate.PeriodFreeBalance = ToDisplayPoints(userAccount.PeriodFreeBalance)
+	estimate.ExtraBalance = ToDisplayPoints(user.ExtraBalance)
+	estimate.CoveredHours = estimateCoveredHours(userAccount.PeriodFreeBalance, user.ExtraBalance, costPerHour, freeMinutes)
+	return estimate, nil
+}
+
+// estimateCoveredHours is how long the job can run before both balances are used up.
+// The free minutes at the start of every job are not charged and come on top.
+func estimateCoveredHours(periodFreeBalance, extraBalance, costPerHour int64, freeMinutes int) *float64 {
+	if costPerHour <= 0 {
+		return nil
+	}
+	balance := max(periodFreeBalance, 0) + max(extraBalance, 0)
+	hours := new(big.Rat).SetFrac64(balance, costPerHour)
+	hours.Add(hours, big.NewRat(int64(max(freeMinutes, 0)), 60))
+	covered, _ := hours.Float64()
+	return &covered
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+)
+
+func TestEstimateCoveredHours(t *testing.T) {
+	if got := estimateCoveredHours(100, 100, 0, 30); got != nil {
+		t.Fatalf("free job covered = %v, want nil", *got)
+	}
+	got := estimateCoveredHours(30*BillingPointScale, -5*BillingPointScale, 20*BillingPointScale, 30)
+	if got == nil || math.Abs(*got-2) > 1e-9 {
+		t.Fatalf("covered = %v, want 2", got)
+	}
+}
+
+func TestEstimateJobCost(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:billing_estimate?mode=memory&cache=shared"), &gorm.Config{
+		DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.Migrator().CreateTable(&model.User{}, &model.UserAccount{}, &model.Resource{}, &model.SystemConfig{}); err != nil {
+		t.Fatalf("create tables: %v", err)
+	}
+	svc := NewBillingService(query.Use(db))
+	ctx := context.Background()
+
+	resources := v1.ResourceList{
+		"nvidia.com/a100": resource.MustParse("2"),
+		v1.ResourceCPU:    resource.MustParse("8"),
+	}
+	estimate, err := svc.EstimateJobCost(ctx, 7, 2, resources, nil)
+	if err != nil || estimate.Billable {
+		t.Fatalf("inactive billing estimate = %+v, err = %v", estimate, err)
+	}
+
+	for _, cfg := range []model.SystemConfig{
+		{Key: model.ConfigKeyEnableBillingFeature, Value: "true"},
+		{Key: model.ConfigKeyEnableBillingActive, Value: "true"},
+		{Key: model.ConfigKeyBillingJobFreeMinutes, Value: "15"},
+	} {
+		if err := db.Create(&cfg).Error; err != nil {
+			t.Fatalf("create config: %v", err)
+		}
+	}
+	if err := db.Create(&model.Resource{ResourceName: "nvidia.com/a100", ResourceType: "gpu", UnitPrice: 5 * BillingPointScale}).Error; err != nil {
+		t.Fatalf("create resource: %v", err)
+	}
+	if err := db.Create(&model.User{Model: gorm.Model{ID: 7}, Name: "alice", ExtraBalance: 10 * BillingPointScale}).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	if err := db.Create(&model.UserAccount{UserID: 7, AccountID: 2, PeriodFreeBalance: 20 * BillingPointScale}).Error; err != nil {
+		t.Fatalf("create member: %v", err)
+	}
+
+	estimate, err = svc.EstimateJobCost(ctx, 7, 2, resources, nil)
+	if err != nil {
+		t.Fatalf("EstimateJobCost() error = %v", err)
+	}
+	if !estimate.Billable || estimate.CostPerHour != 10 || estimate.FreeMinutes != 15 ||
+		estimate.CoveredHours == nil || math.Abs(*estimate.CoveredHours-3.25) > 1e-9 {
+		t.Fatalf("estimate = %+v", estimate)
+	}
+
+	backfill, err := svc.EstimateJobCost(ctx, 7, 2, resources, ptr.To(model.ScheduleTypeBackfill))
+	if err != nil || backfill.Billable || backfill.CostPerHour != 0 || backfill.CoveredHours != nil {
+		t.Fatalf("backfill estimate = %+v, err = %v", backfill, err)
+	}
+}
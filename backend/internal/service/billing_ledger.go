@@ -0,0 +1,181 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"gorm.io/gorm"
+	"k8s.io/klog/v2"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+)
+
+// BillingLedgerDriftKind classifies a reconciliation finding.
+type BillingLedgerDriftKind string
+
+const (
+	// BillingLedgerMismatch means the stored balance differs from the ledger sum.
+	BillingLedgerMismatch BillingLedgerDriftKind = "mismatch"
+	// BillingLedgerUntracked means a non-zero balance has no ledger entries at all.
+	BillingLedgerUntracked BillingLedgerDriftKind = "untracked"
+)
+
+// BillingLedgerDrift is one balance whose stored value cannot be explained by the ledger.
+type BillingLedgerDrift struct {
+	Kind      BillingLedgerDriftKind     `json:"kind"`
+	UserID    uint                       `json:"userId"`
+	AccountID uint                       `json:"accountId"`
+	Balance   model.BillingLedgerBalance `json:"balance"`
+	Ledger    int64                      `json:"ledger"`
+	Actual    int64                      `json:"actual"`
+}
+
+// BillingLedgerReconcileResult is reported by the billing-ledger-reconcile patrol job.
+type BillingLedgerReconcileResult struct {
+	CheckedBalances int                  `json:"checkedBalances"`
+	Drifts          []BillingLedgerDrift `json:"drifts"`
+}
+
+type billingLedgerKey struct {
+	UserID    uint
+	AccountID uint
+	Balance   model.BillingLedgerBalance
+}
+
+// AppendBillingLedgerTx writes ledger entries in the caller's transaction, next to
+// the balance update they describe. Entries that do not move a balance are dropped,
+// except issuance, which still documents the start of a period.
+func AppendBillingLedgerTx(tx *gorm.DB, entries ...*model.BillingLedgerEntry) error {
+	rows := make([]*model.BillingLedgerEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Delta == 0 && entry.Reason != model.BillingLedgerIssuance {
+			continue
+		}
+		if entry.Operator == "" {
+			entry.Operator = billingSystemOperator
+		}
+		rows = append(rows, entry)
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	return query.Use(tx).BillingLedgerEntry.WithContext(tx.Statement.Context).Create(rows...)
+}
+
+func periodFreeLedgerEntry(
+	userID, accountID uint,
+	reason model.BillingLedgerReason,
+	before, after int64,
+) *model.BillingLedgerEntry {
+	return &model.BillingLedgerEntry{
+		UserID:        userID,
+		AccountID:     accountID,
+		Balance:       model.BillingLedgerPeriodFree,
+		Reason:        reason,
+		Delta:         after - before,
+		BalanceBefore: before,
+		BalanceAfter:  after,
+	}
+}
+
+// ExtraLedgerEntry describes a change of User.ExtraBalance.
+func ExtraLedgerEntry(
+	userID uint,
+	reason model.BillingLedgerReason,
+	before, after int64,
+	operator, note string,
+) *model.BillingLedgerEntry {
+	return &model.BillingLedgerEntry{
+		UserID:        userID,
+		Balance:       model.BillingLedgerExtra,
+		Reason:        reason,
+		Delta:         after - before,
+		BalanceBefore: before,
+		BalanceAfter:  after,
+		Operator:      operator,
+		Note:          note,
+	}
+}
+
+// ReconcileLedgerOnce is the patrol entry of billing-ledger-reconcile.
+func (s *BillingService) ReconcileLedgerOnce(ctx context.Context) (any, error) {
+	return s.ReconcileLedger(ctx)
+}
+
+// ReconcileLedger recomputes every balance as the sum of its ledger deltas and
+// reports balances whose stored value drifted from it.
+func (s *BillingService) ReconcileLedger(ctx context.Context) (*BillingLedgerReconcileResult, error) {
+	var sums []struct {
+		UserID    uint
+		AccountID uint
+		Balance   model.BillingLedgerBalance
+		Total     int64
+	}
+	le := s.q.BillingLedgerEntry
+	if err := le.WithContext(ctx).
+		Select(le.UserID, le.AccountID, le.Balance, le.Delta.Sum().As("total")).
+		Group(le.UserID, le.AccountID, le.Balance).
+		Scan(&sums); err != nil {
+		return nil, fmt.Errorf("sum billing ledger: %w", err)
+	}
+	ledger := make(map[billingLedgerKey]int64, len(sums))
+	for _, sum := range sums {
+		ledger[billingLedgerKey{UserID: sum.UserID, AccountID: sum.AccountID, Balance: sum.Balance}] = sum.Total
+	}
+
+	actual := make(map[billingLedgerKey]int64)
+	ua := s.q.UserAccount
+	userAccounts, err := ua.WithContext(ctx).Select(ua.UserID, ua.AccountID, ua.PeriodFreeBalance).Find()
+	if err != nil {
+		return nil, fmt.Errorf("load period free balances: %w", err)
+	}
+	for _, userAccount := range userAccounts {
+		key := billingLedgerKey{UserID: userAccount.UserID, AccountID: userAccount.AccountID, Balance: model.BillingLedgerPeriodFree}
+		actual[key] = userAccount.PeriodFreeBalance
+	}
+	u := s.q.User
+	users, err := u.WithContext(ctx).Select(u.ID, u.ExtraBalance).Find()
+	if err != nil {
+		return nil, fmt.Errorf("load extra balances: %w", err)
+	}
+	for _, user := range users {
+		actual[billingLedgerKey{UserID: user.ID, Balance: model.BillingLedgerExtra}] = user.ExtraBalance
+	}
+
+	result := &BillingLedgerReconcileResult{CheckedBalances: len(actual), Drifts: findBillingLedgerDrifts(ledger, actual)}
+	for _, drift := range result.Drifts {
+		klog.Warningf("[Billing] ledger drift %s: user=%d account=%d balance=%s ledger=%d actual=%d",
+			drift.Kind, drift.UserID, drift.AccountID, drift.Balance, drift.Ledger, drift.Actual)
+	}
+	return result, nil
+}
+
+// findBillingLedgerDrifts compares stored balances of live users and user-accounts
+// with their ledger sums. Ledger rows of deleted members are not checked.
+func findBillingLedgerDrifts(ledger, actual map[billingLedgerKey]int64) []BillingLedgerDrift {
+	drifts := make([]BillingLedgerDrift, 0)
+	for key, value := range actual {
+		sum, tracked := ledger[key]
+		switch {
+		case !tracked && value != 0:
+			drifts = append(drifts, BillingLedgerDrift{
+				Kind: BillingLedgerUntracked, UserID: key.UserID, AccountID: key.AccountID, Balance: key.Balance, Actual: value,
+			})
+		case tracked && sum != value:
+			drifts = append(drifts, BillingLedgerDrift{
+				Kind: BillingLedgerMismatch, UserID: key.UserID, AccountID: key.AccountID, Balance: key.Balance, Ledger: sum, Actual: value,
+			})
+		}
+	}
+	slices.SortFunc(drifts, func(a, b BillingLedgerDrift) int {
+		return cmp.Or(
+			cmp.Compare(a.UserID, b.UserID),
+			cmp.Compare(a.AccountID, b.AccountID),
+			cmp.Compare(a.Balance, b.Balance),
+		)
+	})
+	return drifts
+}
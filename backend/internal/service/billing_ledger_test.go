@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+)
+
+func newBillingLedgerTestDB(t *testing.T, name string) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+name+"?mode=memory&cache=shared"), &gorm.Config{
+		DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.Migrator().CreateTable(&model.User{}, &model.UserAccount{}, &model.BillingLedgerEntry{}); err != nil {
+		t.Fatalf("create tables: %v", err)
+	}
+	return db
+}
+
+func TestDeductSettlementCostAppendsLedger(t *testing.T) {
+	db := newBillingLedgerTestDB(t, "billing_ledger_settlement")
+	if err := db.Create(&model.User{Model: gorm.Model{ID: 7}, Name: "alice", ExtraBalance: 5 * BillingPointScale}).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	if err := db.Create(&model.UserAccount{UserID: 7, AccountID: 2, PeriodFreeBalance: 3 * BillingPointScale}).Error; err != nil {
+		t.Fatalf("create user account: %v", err)
+	}
+	if err := AppendBillingLedgerTx(db,
+		periodFreeLedgerEntry(7, 2, model.BillingLedgerOpening, 0, 3*BillingPointScale),
+		ExtraLedgerEntry(7, model.BillingLedgerOpening, 0, 5*BillingPointScale, "", ""),
+	); err != nil {
+		t.Fatalf("append opening entries: %v", err)
+	}
+
+	job := &model.Job{Model: gorm.Model{ID: 11}, JobName: "sg-alice-1", UserID: 7, AccountID: 2}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		_, _, _, err := deductSettlementCost(tx, job, 10*BillingPointScale)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("deductSettlementCost() error = %v", err)
+	}
+
+	var entries []model.BillingLedgerEntry
+	if err := db.Where("reason = ?", model.BillingLedgerSettlement).Order("balance").Find(&entries).Error; err != nil {
+		t.Fatalf("load ledger: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d settlement entries, want 2", len(entries))
+	}
+	extra, free := entries[0], entries[1]
+	if extra.Balance != model.BillingLedgerExtra || extra.Delta != -5*BillingPointScale || extra.BalanceAfter != 0 {
+		t.Fatalf("extra entry = %+v", extra)
+	}
+	if free.Delta != -5*BillingPointScale || free.BalanceAfter != -2*BillingPointScale || free.JobID != 11 || free.JobName != "sg-alice-1" {
+		t.Fatalf("period free entry = %+v", free)
+	}
+	if free.Operator != billingSystemOperator {
+		t.Fatalf("operator = %q, want %q", free.Operator, billingSystemOperator)
+	}
+
+	svc := NewBillingService(query.Use(db))
+	result, err := svc.ReconcileLedger(context.Background())
+	if err != nil {
+		t.Fatalf("ReconcileLedger() error = %v", err)
+	}
+	if result.CheckedBalances != 2 || len(result.Drifts) != 0 {
+		t.Fatalf("ReconcileLedger() = %+v, want 2 balances without drift", result)
+	}
+
+	if err := db.Model(&model.User{}).Where("id = ?", 7).Update("extra_balance", BillingPointScale).Error; err != nil {
+		t.Fatalf("tamper extra balance: %v", err)
+	}
+	if err := db.Create(&model.UserAccount{UserID: 8, AccountID: 2, PeriodFreeBalance: BillingPointScale}).Error; err != nil {
+		t.Fatalf("create untracked user account: %v", err)
+	}
+	result, err = svc.ReconcileLedger(context.Background())
+	if err != nil {
+		t.Fatalf("ReconcileLedger() error = %v", err)
+	}
+	want := []BillingLedgerDrift{
+		{Kind: BillingLedgerMismatch, UserID: 7, Balance: model.BillingLedgerExtra, Ledger: 0, Actual: BillingPointScale},
+		{Kind: BillingLedgerUntracked, UserID: 8, AccountID: 2, Balance: model.BillingLedgerPeriodFree, Actual: BillingPointScale},
+	}
+	if len(result.Drifts) != len(want) {
+		t.Fatalf("drifts = %+v, want %+v", result.Drifts, want)
+	}
+	for i := range want {
+		if result.Drifts[i] != want[i] {
+			t.Fatalf("drift[%d] = %+v, want %+v", i, result.Drifts[i], want[i])
+		}
+	}
+}
+
+func TestBillingLedgerEntriesAreImmutable(t *testing.T) {
+	db := newBillingLedgerTestDB(t, "billing_ledger_immutable")
+	entry := ExtraLedgerEntry(7, model.BillingLedgerAdjustment, 0, BillingPointScale, "admin", "")
+	if err := AppendBillingLedgerTx(db, entry); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	if err := db.Model(entry).Update("delta", 0).Error; !errors.Is(err, model.ErrBillingLedgerImmutable) {
+		t.Fatalf("update error = %v, want ErrBillingLedgerImmutable", err)
+	}
+	if err := db.Delete(entry).Error; !errors.Is(err, model.ErrBillingLedgerImmutable) {
+		t.Fatalf("delete error = %v, want ErrBillingLedgerImmutable", err)
+	}
+	if err := AppendBillingLedgerTx(db, ExtraLedgerEntry(7, model.BillingLedgerAdjustment, 1, 1, "admin", "")); err != nil {
+		t.Fatalf("append zero delta: %v", err)
+	}
+	var count int64
+	db.Model(&model.BillingLedgerEntry{}).Count(&count)
+	if count != 1 {
+		t.Fatalf("ledger has %d entries, want zero-delta adjustments to be dropped", count)
+	}
+}
@@ -14,10 +14,12 @@ import (
 	"gorm.io/gorm/clause"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
 
 	"github.com/raids-lab/crater/dao/model"
 	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/pkg/alert"
 	"github.com/raids-lab/crater/pkg/cronjob"
 	"github.com/raids-lab/crater/pkg/patrol"
 )
@@ -84,6 +86,8 @@ type billingIssueConfig struct {
 type BillingService struct {
 	q                   *query.Query
 	cronJobManager      *cronjob.CronJobManager
+	client              client.Client
+	alerter             alert.AlertInterface
 	lastTickMu          sync.Mutex
 	lastRunningSettleAt time.Time
 }
@@ -387,7 +391,7 @@ func (s *BillingService) OnJobCreateCheck(ctx context.Context, userID, accountID
 			user.ExtraBalance,
 		)
 	}
-	return nil
+	return s.checkBudgetCapsForJobCreate(ctx, userID, accountID)
 }
 
 func shouldBlockJobCreateForBalance(periodFreeBalance, extraBalance int64) bool {
@@ -605,6 +609,7 @@ func (s *BillingService) issueAccountNowTx(
 		userAccounts = append(userAccounts, *foundUserAccounts[i])
 	}
 
+	ledgerEntries := make([]*model.BillingLedgerEntry, 0, len(userAccounts))
 	for i := range userAccounts {
 		ua := &userAccounts[i]
 		issueAmountForUser := resolveUserIssueAmount(issueAmount, ua, amountOverrideEnabled)
@@ -613,6 +618,12 @@ func (s *BillingService) issueAccountNowTx(
 			Update(uaQuery.PeriodFreeBalance, issueAmountForUser); err != nil {
 			return 0, err
 		}
+		entry := periodFreeLedgerEntry(ua.UserID, accountID, model.BillingLedgerIssuance, ua.PeriodFreeBalance, issueAmountForUser)
+		entry.CreatedAt = now
+		ledgerEntries = append(ledgerEntries, entry)
+	}
+	if err := AppendBillingLedgerTx(tx, ledgerEntries...); err != nil {
+		return 0, err
 	}
 
 	accountQuery := txQuery.Account
@@ -650,10 +661,13 @@ func (s *BillingService) issueUserAccountNowTx(ctx context.Context, tx *gorm.DB,
 	}
 
 	issueAmount = resolveUserIssueAmount(issueAmount, ua, issueConfig.amountOverrideEnabled)
-	_, err = uaQuery.WithContext(ctx).
+	if _, err := uaQuery.WithContext(ctx).
 		Where(uaQuery.UserID.Eq(userID), uaQuery.AccountID.Eq(accountID), uaQuery.DeletedAt.IsNull()).
-		Update(uaQuery.PeriodFreeBalance, issueAmount)
-	return err
+		Update(uaQuery.PeriodFreeBalance, issueAmount); err != nil {
+		return err
+	}
+	return AppendBillingLedgerTx(tx,
+		periodFreeLedgerEntry(userID, accountID, model.BillingLedgerIssuance, ua.PeriodFreeBalance, issueAmount))
 }
 
 func (s *BillingService) runRunningSettlementTickOnce(ctx context.Context, settleAt time.Time) (int, error) {
@@ -663,10 +677,15 @@ func (s *BillingService) runRunningSettlementTickOnce(ctx context.Context, settl
 		settledJobs, err = s.settleAllRunningJobsAtTx(ctx, tx, settleAt)
 		return err
 	})
-	if err == nil {
-		klog.Infof("[Billing] running settlement done, settledJobs=%d", settledJobs)
+	if err != nil {
+		return 0, err
+	}
+	klog.Infof("[Billing] running settlement done, settledJobs=%d", settledJobs)
+	// Budgets are enforced after the commit so a failing stop never rolls back a settlement.
+	if err := s.enforceBudgets(ctx); err != nil {
+		klog.Errorf("[Billing] enforce budgets failed: %v", err)
 	}
-	return settledJobs, err
+	return settledJobs, nil
 }
 
 func (s *BillingService) settleAllRunningJobsAtTx(ctx context.Context, tx *gorm.DB, settleAt time.Time) (int, error) {
@@ -1097,6 +1116,16 @@ func deductSettlementCost(tx *gorm.DB, job *model.Job, jobCost int64) (freeDeduc
 			return 0, 0, 0, err
 		}
 	}
+
+	freeEntry := periodFreeLedgerEntry(ua.UserID, ua.AccountID, model.BillingLedgerSettlement, ua.PeriodFreeBalance, newPeriodFreeBalance)
+	extraEntry := ExtraLedgerEntry(user.ID, model.BillingLedgerSettlement, user.ExtraBalance, newExtraBalance, "", "")
+	for _, entry := range []*model.BillingLedgerEntry{freeEntry, extraEntry} {
+		entry.JobID = job.ID
+		entry.JobName = job.JobName
+	}
+	if err := AppendBillingLedgerTx(tx, freeEntry, extraEntry); err != nil {
+		return 0, 0, 0, err
+	}
 	return freeDeduct, extraDeduct, freeDebt, nil
 }
 
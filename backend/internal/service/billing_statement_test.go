@@ -0,0 +1,93 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/raids-lab/crater/dao/model"
+)
+
+func TestSettlementInWindow(t *testing.T) {
+	start := time.Date(2026, 8, 31, 12, 0, 0, 0, time.UTC)
+	settled := start.Add(24 * time.Hour)
+	job := &model.Job{RunningTimestamp: start, LastSettledAt: &settled, BilledPointsTotal: 240 * BillingPointScale}
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     int64
+	}{
+		{"whole run inside", start.Add(-time.Hour), settled.Add(time.Hour), 240 * BillingPointScale},
+		{"second half", time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC), settled.Add(time.Hour), 120 * BillingPointScale},
+		{"first quarter", start, start.Add(6 * time.Hour), 60 * BillingPointScale},
+		{"outside", settled, settled.Add(time.Hour), 0},
+	}
+	for _, tt := range tests {
+		if got := settlementInWindow(job, tt.from, tt.to); got != tt.want {
+			t.Errorf("%s: settlementInWindow() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+	if got := settlementInWindow(&model.Job{RunningTimestamp: start, BilledPointsTotal: 1}, start, settled); got != 0 {
+		t.Errorf("unsettled job = %d, want 0", got)
+	}
+}
+
+func TestStatementEntriesFromLedger(t *testing.T) {
+	at := time.Date(2026, 9, 10, 8, 0, 0, 0, time.UTC)
+	ledger := []*model.BillingLedgerEntry{
+		{CreatedAt: at, UserID: 7, AccountID: 2, Balance: model.BillingLedgerPeriodFree, Reason: model.BillingLedgerIssuance,
+			Delta: 600000, BalanceBefore: 400000, BalanceAfter: 1000000},
+		{CreatedAt: at, UserID: 7, Balance: model.BillingLedgerExtra, Reason: model.BillingLedgerAdjustment,
+			Delta: -500000, Operator: "admin", Note: "refund"},
+		{CreatedAt: at, UserID: 7, Balance: model.BillingLedgerExtra, Reason: model.BillingLedgerGrant,
+			Delta: 400000, Operator: "admin"},
+		{CreatedAt: at, UserID: 7, AccountID: 2, Balance: model.BillingLedgerPeriodFree, Reason: model.BillingLedgerSettlement,
+			Delta: -100000, JobName: "sg-7-1"},
+	}
+
+	entries := statementEntriesFromLedger(ledger)
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries, want settlements to be skipped: %+v", len(entries), entries)
+	}
+	if entries[0].Type != BillingStatementIssuance || entries[0].Amount != 1000000 {
+		t.Fatalf("issuance entry = %+v, want the issued amount 1000000", entries[0])
+	}
+	if entries[1].Type != BillingStatementAdjustment || entries[1].Amount != -500000 ||
+		entries[1].Description != "extra balance adjusted by admin: refund" {
+		t.Fatalf("adjustment entry = %+v", entries[1])
+	}
+	if entries[2].Amount != 400000 || entries[2].AccountID != 0 {
+		t.Fatalf("grant entry = %+v", entries[2])
+	}
+}
+
+func TestBillingStatementWriteCSV(t *testing.T) {
+	st := &BillingStatement{Entries: []BillingStatementEntry{{
+		Time:        time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC),
+		Type:        BillingStatementSettlement,
+		Username:    "alice",
+		AccountName: "vision, lab",
+		JobName:     "sg-alice-1",
+		Amount:      -1234 * BillingPointScale / 100,
+		Description: "train",
+	}, {
+		Time:        time.Date(2026, 9, 2, 0, 0, 0, 0, time.UTC),
+		Type:        BillingStatementSettlement,
+		Username:    "bob",
+		AccountName: "@lab",
+		JobName:     "=HYPERLINK(\"http://x\")",
+		Amount:      -BillingPointScale,
+		Description: "-rm",
+	}}}
+	var sb strings.Builder
+	if err := st.WriteCSV(&sb); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	want := "time,type,user,account,job,amount,description\n" +
+		"2026-09-01T00:00:00Z,settlement,alice,\"vision, lab\",sg-alice-1,-12.34,train\n" +
+		"2026-09-02T00:00:00Z,settlement,bob,'@lab,\"'=HYPERLINK(\"\"http://x\"\")\",-1.00,'-rm\n"
+	if sb.String() != want {
+		t.Fatalf("WriteCSV() =\n%s\nwant\n%s", sb.String(), want)
+	}
+}
@@ -595,12 +595,14 @@ func parsePrequeueRuntimeConfig(recordMap map[string]string) (*model.PrequeueRun
 }
 
 type UpdatePrequeueConfigReq struct {
-	BackfillEnabled                  *bool  `json:"backfill_enabled,omitempty"`
-	QueueQuotaEnabled                *bool  `json:"queue_quota_enabled,omitempty"`
-	NormalJobWaitingToleranceSeconds *int64 `json:"normal_job_waiting_tolerance_seconds,omitempty"`
-	ActivateTickerIntervalSeconds    *int64 `json:"activate_ticker_interval_seconds,omitempty"`
-	MaxTotalActivationsPerRound      *int64 `json:"max_total_activations_per_round,omitempty"`
-	PrequeueCandidateSize            *int64 `json:"prequeue_candidate_size,omitempty"`
+	BackfillEnabled                  *bool   `json:"backfill_enabled,omitempty"`
+	QueueQuotaEnabled                *bool   `json:"queue_quota_enabled,omitempty"`
+	NormalJobWaitingToleranceSeconds *int64  `json:"normal_job_waiting_tolerance_seconds,omitempty"`
+	ActivateTickerIntervalSeconds    *int64  `json:"activate_ticker_interval_seconds,omitempty"`
+	MaxTotalActivationsPerRound      *int64  `json:"max_total_activations_per_round,omitempty"`
+	PrequeueCandidateSize            *int64  `json:"prequeue_candidate_size,omitempty"`
+	ActivationPolicy                 *string `json:"activation_policy,omitempty"`
+	FairShareHalfLifeHours           *int64  `json:"fair_share_half_life_hours,omitempty"`
 }
 
 func (r *UpdatePrequeueConfigReq) Validate() error {
@@ -612,12 +614,16 @@ func (r *UpdatePrequeueConfigReq) Validate() error {
 		model.PrequeueActivateTickerIntervalSecondsKey:    r.ActivateTickerIntervalSeconds,
 		model.PrequeueMaxTotalActivationsPerRoundKey:      r.MaxTotalActivationsPerRound,
 		model.PrequeueCandidateSizeKey:                    r.PrequeueCandidateSize,
+		model.PrequeueFairShareHalfLifeHoursKey:           r.FairShareHalfLifeHours,
 	}
 	for key, value := range positiveValues {
 		if value != nil && *value <= 0 {
 			return fmt.Errorf("%s must be greater than 0", key)
 		}
 	}
+	if r.ActivationPolicy != nil {
+		return model.ValidatePrequeueActivationPolicy(*r.ActivationPolicy)
+	}
 	return nil
 }
 
@@ -641,6 +647,12 @@ func (r *UpdatePrequeueConfigReq) ToValueMap() map[string]string {
 	if r.PrequeueCandidateSize != nil {
 		valueMap[model.PrequeueCandidateSizeKey] = strconv.FormatInt(*r.PrequeueCandidateSize, 10)
 	}
+	if r.ActivationPolicy != nil {
+		valueMap[model.PrequeueActivationPolicyKey] = *r.ActivationPolicy
+	}
+	if r.FairShareHalfLifeHours != nil {
+		valueMap[model.PrequeueFairShareHalfLifeHoursKey] = strconv.FormatInt(*r.FairShareHalfLifeHours, 10)
+	}
 	return valueMap
 }
 
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/raids-lab/crater/dao/model"
+)
+
+// RequestDatasetVerification asks the storage server to rehash a dataset and compare it with its
+// manifest; the indexer picks the request up on its next poll. A positive expectedSize, such as
+// the repository size reported by a model download, also flags the dataset as incomplete when it
+// holds fewer bytes.
+func RequestDatasetVerification(ctx context.Context, db *gorm.DB, datasetID uint, expectedSize int64) error {
+	updates := map[string]any{
+		"verify_requested": true,
+		"updated_at":       time.Now(),
+	}
+	if expectedSize > 0 {
+		updates["expected_size_bytes"] = expectedSize
+	}
+	return db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "dataset_id"}},
+		DoUpdates: clause.Assignments(updates),
+	}).Create(&model.DatasetManifest{
+		DatasetID:         datasetID,
+		Status:            model.DatasetIndexPending,
+		Integrity:         model.DatasetIntegrityUnverified,
+		ExpectedSizeBytes: expectedSize,
+		VerifyRequested:   true,
+	}).Error
+}
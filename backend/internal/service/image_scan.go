@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/internal/bizerr"
+)
+
+// imageScanPolicyDisabled is accepted in place of an empty threshold.
+const imageScanPolicyDisabled = "none"
+
+// GetImageScanBlockSeverity returns the severity at and above which images may not be
+// made public. An empty severity means publishing is not gated by scan results.
+func (s *ConfigService) GetImageScanBlockSeverity(ctx context.Context) (model.ScanSeverity, error) {
+	configMap, err := s.getConfigs(ctx, model.ConfigKeyImageScanBlockSeverity)
+	if err != nil {
+		return "", err
+	}
+	value := configMap[model.ConfigKeyImageScanBlockSeverity]
+	if value == "" {
+		return "", nil
+	}
+	severity, ok := model.ParseScanSeverity(value)
+	if !ok {
+		return "", bizerr.Internal.DatabaseError.New(
+			"invalid image scan config " + model.ConfigKeyImageScanBlockSeverity + "=" + strconv.Quote(value),
+		)
+	}
+	return severity, nil
+}
+
+func (s *ConfigService) UpdateImageScanBlockSeverity(ctx context.Context, value string) error {
+	value = strings.TrimSpace(value)
+	if value != "" && !strings.EqualFold(value, imageScanPolicyDisabled) {
+		severity, ok := model.ParseScanSeverity(value)
+		if !ok {
+			return bizerr.BadRequest.ParameterError.New(
+				"block severity must be one of UNKNOWN, LOW, MEDIUM, HIGH, CRITICAL or none",
+			)
+		}
+		value = string(severity)
+	} else {
+		value = ""
+	}
+	return s.updateConfigs(ctx, map[string]string{model.ConfigKeyImageScanBlockSeverity: value})
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/raids-lab/crater/dao/model"
+)
+
+func TestImageScanBlockSeverity(t *testing.T) {
+	configService := newPodBandwidthTestConfigService(t, "image_scan_block_severity")
+
+	severity, err := configService.GetImageScanBlockSeverity(t.Context())
+	if err != nil || severity != "" {
+		t.Fatalf("default block severity = %q, %v", severity, err)
+	}
+	if err = configService.UpdateImageScanBlockSeverity(t.Context(), " high "); err != nil {
+		t.Fatal(err)
+	}
+	if severity, err = configService.GetImageScanBlockSeverity(t.Context()); err != nil ||
+		severity != model.ScanSeverityHigh {
+		t.Fatalf("block severity = %q, %v", severity, err)
+	}
+	if err = configService.UpdateImageScanBlockSeverity(t.Context(), "severe"); err == nil {
+		t.Fatal("unknown severity should be rejected")
+	}
+	if err = configService.UpdateImageScanBlockSeverity(t.Context(), "none"); err != nil {
+		t.Fatal(err)
+	}
+	if severity, err = configService.GetImageScanBlockSeverity(t.Context()); err != nil || severity != "" {
+		t.Fatalf("disabled block severity = %q, %v", severity, err)
+	}
+}
@@ -0,0 +1,410 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	v1 "k8s.io/api/core/v1"
+	apiresource "k8s.io/apimachinery/pkg/api/resource"
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/bizerr"
+	"github.com/raids-lab/crater/pkg/utils"
+)
+
+// MaxJobArraySize bounds the number of vcjobs a single array submission may expand to.
+const MaxJobArraySize = 256
+
+// Matrix parameters are injected as environment variables, so their names must be valid ones.
+var jobArrayParamNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// JobArrayTaskState is an array element joined with its job record.
+type JobArrayTaskState struct {
+	Task              *model.JobArrayTask
+	JobStatus         batch.JobPhase
+	BilledPointsTotal int64
+}
+
+// JobArrayService keeps the bookkeeping of job arrays. Like workflows, elements are
+// normal job records; the service only decides which held elements may enter the
+// prequeue so that no more than Parallelism of them run at once.
+type JobArrayService struct {
+	q *query.Query
+}
+
+func NewJobArrayService(q *query.Query) *JobArrayService {
+	return &JobArrayService{q: q}
+}
+
+// ExpandJobArray returns the parameters of every array element. A range index yields
+// count elements without parameters; a matrix yields its cartesian product, ordered by
+// parameter name with the last name varying fastest.
+func ExpandJobArray(count int, matrix map[string][]string) ([]map[string]string, error) {
+	if count > 0 && len(matrix) > 0 {
+		return nil, bizerr.BadRequest.ParameterError.New("job array takes either count or matrix, not both")
+	}
+	if len(matrix) == 0 {
+		if count <= 0 {
+			return nil, bizerr.BadRequest.ParameterError.New("job array requires a positive count or a matrix")
+		}
+		if count > MaxJobArraySize {
+			return nil, bizerr.BadRequest.ParameterError.New(
+				fmt.Sprintf("job array size %d exceeds the limit of %d", count, MaxJobArraySize),
+			)
+		}
+		elements := make([]map[string]string, count)
+		for i := range elements {
+			elements[i] = map[string]string{}
+		}
+		return elements, nil
+	}
+
+	names := slices.Sorted(maps.Keys(matrix))
+	size := 1
+	for _, name := range names {
+		if !jobArrayParamNamePattern.MatchString(name) {
+			return nil, bizerr.BadRequest.ParameterError.New(
+				fmt.Sprintf("job array matrix parameter %q is not a valid environment variable name", name),
+			)
+		}
+		if len(matrix[name]) == 0 {
+			return nil, bizerr.BadRequest.ParameterError.New(
+				fmt.Sprintf("job array matrix parameter %q has no values", name),
+			)
+		}
+		size *= len(matrix[name])
+		if size > MaxJobArraySize {
+			return nil, bizerr.BadRequest.ParameterError.New(
+				fmt.Sprintf("job array matrix expands to more than %d elements", MaxJobArraySize),
+			)
+		}
+	}
+
+	elements := make([]map[string]string, 0, size)
+	for i := range size {
+		params := make(map[string]string, len(names))
+		rest := i
+		for j := len(names) - 1; j >= 0; j-- {
+			values := matrix[names[j]]
+			params[names[j]] = values[rest%len(values)]
+			rest /= len(values)
+		}
+		elements = append(elements, params)
+	}
+	return elements, nil
+}
+
+// JobArrayParallelismForQuota returns how many elements requesting perTask fit into the
+// queue quota at the same time, or 0 if the quota does not limit any requested resource.
+func JobArrayParallelismForQuota(quota map[string]string, perTask v1.ResourceList) int {
+	limit := 0
+	for name, raw := range quota {
+		if !isSupportedQueueResource(name) {
+			continue
+		}
+		request, ok := perTask[v1.ResourceName(name)]
+		if !ok || request.Sign() <= 0 {
+			continue
+		}
+		capacity, err := apiresource.ParseQuantity(raw)
+		if err != nil {
+			continue
+		}
+		// A single element above the quota still counts as one; submission reports it.
+		fits := max(int(capacity.MilliValue()/request.MilliValue()), 1)
+		if limit == 0 || fits < limit {
+			limit = fits
+		}
+	}
+	return limit
+}
+
+// CreateJobArray stores the array, its elements and the held job records of the
+// elements above the parallelism cap in one transaction. The others are submitted by
+// the caller afterwards.
+func (s *JobArrayService) CreateJobArray(
+	ctx context.Context,
+	array *model.JobArray,
+	tasks []*model.JobArrayTask,
+	heldRecords []*model.Job,
+) error {
+	array.Size = len(tasks)
+	array.Status = model.JobArrayStatusRunning
+	return s.q.Transaction(func(tx *query.Query) error {
+		if err := tx.JobArray.WithContext(ctx).Create(array); err != nil {
+			return err
+		}
+		for _, task := range tasks {
+			task.ArrayID = array.ID
+		}
+		if err := tx.JobArrayTask.WithContext(ctx).Create(tasks...); err != nil {
+			return err
+		}
+		if len(heldRecords) == 0 {
+			return nil
+		}
+		return tx.Job.WithContext(ctx).Create(heldRecords...)
+	})
+}
+
+// ListJobArrays returns the arrays of a user within an account, newest first.
+func (s *JobArrayService) ListJobArrays(ctx context.Context, userID, accountID uint) ([]*model.JobArray, error) {
+	a := s.q.JobArray
+	return a.WithContext(ctx).
+		Where(a.UserID.Eq(userID), a.AccountID.Eq(accountID)).
+		Order(a.ID.Desc()).
+		Find()
+}
+
+// GetJobArray loads an array owned by the user together with the state of every element.
+func (s *JobArrayService) GetJobArray(
+	ctx context.Context,
+	id, userID, accountID uint,
+) (*model.JobArray, []JobArrayTaskState, error) {
+	a := s.q.JobArray
+	array, err := a.WithContext(ctx).
+		Where(a.ID.Eq(id), a.UserID.Eq(userID), a.AccountID.Eq(accountID)).
+		First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, bizerr.NotFound.DataBaseNotFound.New(fmt.Sprintf("job array %d not found", id))
+		}
+		return nil, nil, bizerr.Internal.DatabaseError.Wrap(err, "get job array")
+	}
+	states, err := s.loadTaskStates(ctx, s.q, array.ID)
+	if err != nil {
+		return nil, nil, bizerr.Internal.DatabaseError.Wrap(err, "get job array tasks")
+	}
+	return array, states, nil
+}
+
+// CancelJobArray drops every held element and returns the jobs that were already
+// submitted, so the caller can delete them from the cluster.
+func (s *JobArrayService) CancelJobArray(ctx context.Context, id, userID, accountID uint) ([]string, error) {
+	array, states, err := s.GetJobArray(ctx, id, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if array.Status != model.JobArrayStatusRunning {
+		return nil, bizerr.Conflict.ResourceStatusError.New(
+			fmt.Sprintf("job array %d is already %s", id, array.Status),
+		)
+	}
+
+	active := make([]string, 0, len(states))
+	err = s.q.Transaction(func(tx *query.Query) error {
+		for _, state := range states {
+			if state.Task.Held {
+				if err := dropHeldJobArrayTaskTx(ctx, tx, state.Task); err != nil {
+					return err
+				}
+				continue
+			}
+			if !isJobPhaseFinished(state.JobStatus) {
+				active = append(active, state.Task.JobName)
+			}
+		}
+		_, err := tx.JobArray.WithContext(ctx).
+			Where(tx.JobArray.ID.Eq(array.ID)).
+			Update(tx.JobArray.Status, model.JobArrayStatusCancelled)
+		return err
+	})
+	if err != nil {
+		return nil, bizerr.Internal.DatabaseError.Wrap(err, "cancel job array")
+	}
+	return active, nil
+}
+
+// ReleaseNext is called once a job reached a terminal phase. If the job belongs to an
+// array, it releases as many held elements as the parallelism cap allows and refreshes
+// the array status. The returned flag tells the caller whether the prequeue should be
+// rescanned.
+func (s *JobArrayService) ReleaseNext(ctx context.Context, jobName string) (bool, error) {
+	if s == nil {
+		return false, nil
+	}
+	// Most finished jobs are not array elements, so avoid First and its not-found log.
+	at := s.q.JobArrayTask
+	matches, err := at.WithContext(ctx).Where(at.JobName.Eq(jobName)).Limit(1).Find()
+	if err != nil {
+		return false, err
+	}
+	if len(matches) == 0 {
+		return false, nil
+	}
+	arrayID := matches[0].ArrayID
+
+	released := false
+	now := utils.GetLocalTime()
+	err = s.q.Transaction(func(tx *query.Query) error {
+		array, err := tx.JobArray.WithContext(ctx).Where(tx.JobArray.ID.Eq(arrayID)).First()
+		if err != nil {
+			return err
+		}
+		states, err := s.loadTaskStates(ctx, tx, arrayID)
+		if err != nil {
+			return err
+		}
+
+		// A held element whose job was deleted by its owner no longer waits for a slot.
+		for i := range states {
+			state := &states[i]
+			if state.Task.Held && isJobPhaseFinished(state.JobStatus) {
+				if err := dropHeldJobArrayTaskTx(ctx, tx, state.Task); err != nil {
+					return err
+				}
+				state.Task.Held = false
+				state.JobStatus = model.Deleted
+			}
+		}
+
+		if array.Status != model.JobArrayStatusRunning {
+			return nil
+		}
+		for _, i := range selectJobArrayTasksToRelease(states, array.Parallelism) {
+			state := &states[i]
+			if _, err := tx.JobArrayTask.WithContext(ctx).
+				Where(tx.JobArrayTask.ID.Eq(state.Task.ID)).
+				Update(tx.JobArrayTask.Held, false); err != nil {
+				return err
+			}
+			// The waiting tolerance of a released element starts now, not at array submission.
+			if _, err := tx.Job.WithContext(ctx).
+				Where(tx.Job.JobName.Eq(state.Task.JobName), tx.Job.Status.Eq(string(model.Prequeue))).
+				Update(tx.Job.CreationTimestamp, now); err != nil {
+				return err
+			}
+			state.Task.Held = false
+			released = true
+		}
+
+		status := summarizeJobArrayStatus(states)
+		if status == array.Status {
+			return nil
+		}
+		_, err = tx.JobArray.WithContext(ctx).
+			Where(tx.JobArray.ID.Eq(array.ID)).
+			Update(tx.JobArray.Status, status)
+		return err
+	})
+	return released, err
+}
+
+// SumJobArrayBilling adds up the settled points of every element.
+func SumJobArrayBilling(states []JobArrayTaskState) int64 {
+	var total int64
+	for _, state := range states {
+		total += state.BilledPointsTotal
+	}
+	return total
+}
+
+func (s *JobArrayService) loadTaskStates(
+	ctx context.Context,
+	q *query.Query,
+	arrayID uint,
+) ([]JobArrayTaskState, error) {
+	tasks, err := q.JobArrayTask.WithContext(ctx).
+		Where(q.JobArrayTask.ArrayID.Eq(arrayID)).
+		Order(q.JobArrayTask.Index).
+		Find()
+	if err != nil {
+		return nil, err
+	}
+	jobNames := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		jobNames = append(jobNames, task.JobName)
+	}
+	jobs, err := q.Job.WithContext(ctx).
+		Select(q.Job.JobName, q.Job.Status, q.Job.BilledPointsTotal).
+		Where(q.Job.JobName.In(jobNames...)).
+		Find()
+	if err != nil {
+		return nil, err
+	}
+	jobByName := make(map[string]*model.Job, len(jobs))
+	for _, job := range jobs {
+		jobByName[job.JobName] = job
+	}
+
+	states := make([]JobArrayTaskState, 0, len(tasks))
+	for _, task := range tasks {
+		state := JobArrayTaskState{Task: task, JobStatus: model.Deleted}
+		if job, ok := jobByName[task.JobName]; ok {
+			state.JobStatus = job.Status
+			state.BilledPointsTotal = job.BilledPointsTotal
+		}
+		states = append(states, state)
+	}
+	return states, nil
+}
+
+func dropHeldJobArrayTaskTx(ctx context.Context, tx *query.Query, task *model.JobArrayTask) error {
+	if _, err := tx.JobArrayTask.WithContext(ctx).
+		Where(tx.JobArrayTask.ID.Eq(task.ID)).
+		Update(tx.JobArrayTask.Held, false); err != nil {
+		return err
+	}
+	_, err := tx.Job.WithContext(ctx).
+		Where(tx.Job.JobName.Eq(task.JobName), tx.Job.Status.Eq(string(model.Prequeue))).
+		Update(tx.Job.Status, model.Deleted)
+	return err
+}
+
+// selectJobArrayTasksToRelease returns the indexes of the held elements that fit into
+// the free parallelism slots, lowest array index first.
+func selectJobArrayTasksToRelease(states []JobArrayTaskState, parallelism int) []int {
+	running := 0
+	held := make([]int, 0, len(states))
+	for i, state := range states {
+		switch {
+		case state.Task.Held:
+			held = append(held, i)
+		case !isJobPhaseFinished(state.JobStatus):
+			running++
+		}
+	}
+	free := parallelism - running
+	if free <= 0 {
+		return nil
+	}
+	if free < len(held) {
+		held = held[:free]
+	}
+	return held
+}
+
+func summarizeJobArrayStatus(states []JobArrayTaskState) model.JobArrayStatus {
+	succeeded := true
+	for _, state := range states {
+		if state.Task.Held || !isJobPhaseFinished(state.JobStatus) {
+			return model.JobArrayStatusRunning
+		}
+		if state.JobStatus != batch.Completed {
+			succeeded = false
+		}
+	}
+	if succeeded {
+		return model.JobArrayStatusSucceeded
+	}
+	return model.JobArrayStatusFailed
+}
+
+// NewJobArrayTask builds the element row for a job of an array. Elements at or above
+// the parallelism cap start held.
+func NewJobArrayTask(index, parallelism int, params map[string]string, jobName string) *model.JobArrayTask {
+	return &model.JobArrayTask{
+		Index:   index,
+		JobName: jobName,
+		Params:  datatypes.NewJSONType(params),
+		Held:    index >= parallelism,
+	}
+}
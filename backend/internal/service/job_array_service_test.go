@@ -0,0 +1,177 @@
+package service
+
+import (
+	"fmt"
+	"maps"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+)
+
+func TestExpandJobArray(t *testing.T) {
+	t.Parallel()
+
+	elements, err := ExpandJobArray(0, map[string][]string{
+		"LR":    {"0.1", "0.01"},
+		"BATCH": {"32", "64", "128"},
+	})
+	if err != nil {
+		t.Fatalf("ExpandJobArray() error = %v", err)
+	}
+	if len(elements) != 6 {
+		t.Fatalf("len(elements) = %d, want 6", len(elements))
+	}
+	// Names are sorted, so LR varies fastest.
+	want := []map[string]string{
+		{"BATCH": "32", "LR": "0.1"},
+		{"BATCH": "32", "LR": "0.01"},
+		{"BATCH": "64", "LR": "0.1"},
+	}
+	for i, params := range want {
+		if !maps.Equal(elements[i], params) {
+			t.Fatalf("elements[%d] = %v, want %v", i, elements[i], params)
+		}
+	}
+
+	if elements, err = ExpandJobArray(3, nil); err != nil || len(elements) != 3 {
+		t.Fatalf("ExpandJobArray(3) = %v, %v", elements, err)
+	}
+	for _, bad := range []struct {
+		count  int
+		matrix map[string][]string
+	}{
+		{count: 0},
+		{count: MaxJobArraySize + 1},
+		{count: 2, matrix: map[string][]string{"LR": {"0.1"}}},
+		{matrix: map[string][]string{"LR": {}}},
+		{matrix: map[string][]string{"1LR": {"0.1"}}},
+	} {
+		if _, err := ExpandJobArray(bad.count, bad.matrix); err == nil {
+			t.Fatalf("ExpandJobArray(%d, %v) should fail", bad.count, bad.matrix)
+		}
+	}
+}
+
+func TestJobArrayParallelismForQuota(t *testing.T) {
+	t.Parallel()
+
+	perTask := v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("4"),
+		"nvidia.com/gpu":  resource.MustParse("1"),
+		v1.ResourceMemory: resource.MustParse("16Gi"),
+	}
+	tests := []struct {
+		name  string
+		quota map[string]string
+		want  int
+	}{
+		{name: "no quota", quota: nil, want: 0},
+		{name: "tightest resource wins", quota: map[string]string{"cpu": "32", "nvidia.com/gpu": "3"}, want: 3},
+		{name: "single element above quota", quota: map[string]string{"memory": "8Gi"}, want: 1},
+		{name: "unrequested resource is ignored", quota: map[string]string{"example.com/fpga": "1"}, want: 0},
+	}
+	for _, tc := range tests {
+		if got := JobArrayParallelismForQuota(tc.quota, perTask); got != tc.want {
+			t.Fatalf("%s: JobArrayParallelismForQuota() = %d, want %d", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestJobArrayServiceReleaseNext(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:job_array_release?mode=memory&cache=shared"), &gorm.Config{
+		DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.Migrator().CreateTable(&model.Job{}, &model.JobArray{}, &model.JobArrayTask{}); err != nil {
+		t.Fatalf("create tables: %v", err)
+	}
+	svc := NewJobArrayService(query.Use(db))
+	ctx := t.Context()
+
+	const size, parallelism = 4, 2
+	tasks := make([]*model.JobArrayTask, 0, size)
+	held := make([]*model.Job, 0, size)
+	for i := range size {
+		task := NewJobArrayTask(i, parallelism, map[string]string{}, fmt.Sprintf("job-%d", i))
+		tasks = append(tasks, task)
+		if task.Held {
+			held = append(held, &model.Job{Name: task.JobName, JobName: task.JobName, Status: model.Prequeue})
+			continue
+		}
+		record := &model.Job{Name: task.JobName, JobName: task.JobName, Status: batch.Running, BilledPointsTotal: 100}
+		if err := db.Create(record).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	array := &model.JobArray{Name: "sweep", UserID: 1, AccountID: 1, JobType: model.JobTypeCustom, Parallelism: parallelism}
+	if err := svc.CreateJobArray(ctx, array, tasks, held); err != nil {
+		t.Fatalf("create job array: %v", err)
+	}
+
+	setStatus := func(jobName string, status batch.JobPhase) {
+		t.Helper()
+		if err := db.Model(&model.Job{}).Where("job_name = ?", jobName).Update("status", status).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	heldTasks := func() []int {
+		t.Helper()
+		_, states, err := svc.GetJobArray(ctx, array.ID, 1, 1)
+		if err != nil {
+			t.Fatalf("get job array: %v", err)
+		}
+		var result []int
+		for _, state := range states {
+			if state.Task.Held {
+				result = append(result, state.Task.Index)
+			}
+		}
+		return result
+	}
+
+	// The user deletes the last held element; no slot opens for it.
+	setStatus("job-3", model.Deleted)
+	if released, err := svc.ReleaseNext(ctx, "job-3"); err != nil || released {
+		t.Fatalf("deleted held element released=%v err=%v, want nothing released", released, err)
+	}
+	if got := heldTasks(); len(got) != 1 || got[0] != 2 {
+		t.Fatalf("held after delete = %v, want [2]", got)
+	}
+
+	setStatus("job-0", batch.Completed)
+	if released, err := svc.ReleaseNext(ctx, "job-0"); err != nil || !released {
+		t.Fatalf("element 0 completion released=%v err=%v, want released", released, err)
+	}
+	if got := heldTasks(); len(got) != 0 {
+		t.Fatalf("held after completion = %v, want none", got)
+	}
+
+	setStatus("job-1", batch.Completed)
+	setStatus("job-2", batch.Completed)
+	if _, err := svc.ReleaseNext(ctx, "job-2"); err != nil {
+		t.Fatal(err)
+	}
+	got, states, err := svc.GetJobArray(ctx, array.ID, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Element 3 was deleted, so the array did not fully succeed.
+	if got.Status != model.JobArrayStatusFailed {
+		t.Fatalf("array status = %s, want %s", got.Status, model.JobArrayStatusFailed)
+	}
+	if total := SumJobArrayBilling(states); total != 200 {
+		t.Fatalf("SumJobArrayBilling() = %d, want 200", total)
+	}
+	if _, err := svc.CancelJobArray(ctx, array.ID, 1, 1); err == nil {
+		t.Fatal("cancelling a finished array should fail")
+	}
+}
@@ -0,0 +1,343 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"k8s.io/klog/v2"
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/bizerr"
+	"github.com/raids-lab/crater/pkg/alert"
+	"github.com/raids-lab/crater/pkg/config"
+	"github.com/raids-lab/crater/pkg/utils"
+)
+
+// NotificationDispatchResult is recorded by the notification-dispatch patrol cronjob.
+type NotificationDispatchResult struct {
+	QueuedJobsNotified int `json:"queuedJobsNotified"`
+	LowBalanceNotified int `json:"lowBalanceNotified"`
+	DigestsSent        int `json:"digestsSent"`
+}
+
+// NotificationService manages notification rules and raises the events that are not
+// tied to a job state transition (queued too long, billing balance low).
+type NotificationService struct {
+	q              *query.Query
+	billingService *BillingService
+	alerter        alert.AlertInterface
+}
+
+func NewNotificationService(q *query.Query, billingService *BillingService) *NotificationService {
+	return &NotificationService{q: q, billingService: billingService}
+}
+
+func (s *NotificationService) alertMgr() alert.AlertInterface {
+	if s.alerter == nil {
+		s.alerter = alert.GetAlertMgr()
+	}
+	return s.alerter
+}
+
+func (s *NotificationService) ListRules(ctx context.Context, userID uint) ([]*model.NotificationRule, error) {
+	nr := s.q.NotificationRule
+	return nr.WithContext(ctx).Where(nr.UserID.Eq(userID)).Order(nr.AccountID).Find()
+}
+
+// UpsertRule creates or replaces the rule of (UserID, AccountID).
+func (s *NotificationService) UpsertRule(ctx context.Context, rule *model.NotificationRule) (*model.NotificationRule, error) {
+	if rule.QueuedTooLongMinutes == 0 {
+		rule.QueuedTooLongMinutes = model.DefaultQueuedTooLongMinutes
+	}
+	if err := rule.Validate(); err != nil {
+		return nil, bizerr.BadRequest.ParameterError.Wrap(err, "invalid notification rule")
+	}
+	if rule.AccountID != 0 {
+		ua := s.q.UserAccount
+		if _, err := ua.WithContext(ctx).Where(ua.UserID.Eq(rule.UserID), ua.AccountID.Eq(rule.AccountID)).First(); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, bizerr.Forbidden.PermissionDenied.New(fmt.Sprintf("user is not a member of account %d", rule.AccountID))
+			}
+			return nil, err
+		}
+	}
+
+	nr := s.q.NotificationRule
+	err := nr.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "account_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"events", "queued_too_long_minutes", "balance_low_threshold",
+			"quiet_start", "quiet_end", "digest_minutes", "updated_at", "deleted_at",
+		}),
+	}).Create(rule)
+	if err != nil {
+		return nil, err
+	}
+	return nr.WithContext(ctx).Where(nr.UserID.Eq(rule.UserID), nr.AccountID.Eq(rule.AccountID)).First()
+}
+
+func (s *NotificationService) DeleteRule(ctx context.Context, userID, accountID uint) error {
+	nr := s.q.NotificationRule
+	info, err := nr.WithContext(ctx).Unscoped().Where(nr.UserID.Eq(userID), nr.AccountID.Eq(accountID)).Delete()
+	if err != nil {
+		return err
+	}
+	if info.RowsAffected == 0 {
+		return bizerr.NotFound.DataBaseNotFound.New("notification rule not found")
+	}
+	return nil
+}
+
+// RunDispatchOnce is the entry of the notification-dispatch patrol cronjob.
+func (s *NotificationService) RunDispatchOnce(ctx context.Context) (any, error) {
+	result := &NotificationDispatchResult{}
+	var errs []error
+
+	rules, err := s.q.NotificationRule.WithContext(ctx).Find()
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) > 0 {
+		if result.QueuedJobsNotified, err = s.notifyQueuedJobs(ctx, rules); err != nil {
+			errs = append(errs, err)
+		}
+		if result.LowBalanceNotified, err = s.notifyLowBalances(ctx, rules); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if result.DigestsSent, err = s.alertMgr().FlushNotificationDigests(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	return result, errors.Join(errs...)
+}
+
+func subscribedUserIDs(rules []*model.NotificationRule, event model.NotificationEvent) []uint {
+	var ids []uint
+	for _, rule := range rules {
+		if rule.Subscribes(event) && !slices.Contains(ids, rule.UserID) {
+			ids = append(ids, rule.UserID)
+		}
+	}
+	return ids
+}
+
+func (s *NotificationService) notifyQueuedJobs(ctx context.Context, rules []*model.NotificationRule) (int, error) {
+	userIDs := subscribedUserIDs(rules, model.NotificationEventJobQueuedTooLong)
+	if len(userIDs) == 0 {
+		return 0, nil
+	}
+	minThreshold := 0
+	for _, rule := range rules {
+		if rule.Subscribes(model.NotificationEventJobQueuedTooLong) &&
+			(minThreshold == 0 || rule.QueuedTooLongMinutes < minThreshold) {
+			minThreshold = rule.QueuedTooLongMinutes
+		}
+	}
+	now := utils.GetLocalTime()
+
+	j := s.q.Job
+	jobs, err := j.WithContext(ctx).
+		Where(
+			j.UserID.In(userIDs...),
+			j.Status.In(string(batch.Pending), string(model.Prequeue)),
+			j.CreationTimestamp.Lte(now.Add(-time.Duration(minThreshold)*time.Minute)),
+		).
+		Find()
+	if err != nil || len(jobs) == 0 {
+		return 0, err
+	}
+	held, err := s.heldJobNames(ctx, jobs)
+	if err != nil {
+		return 0, err
+	}
+
+	notified := 0
+	var errs []error
+	for _, job := range jobs {
+		if held[job.JobName] {
+			continue
+		}
+		rule, err := alert.FindNotificationRule(ctx, s.q, job.UserID, job.AccountID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if rule == nil || !rule.Subscribes(model.NotificationEventJobQueuedTooLong) ||
+			now.Sub(job.CreationTimestamp) < time.Duration(rule.QueuedTooLongMinutes)*time.Minute {
+			continue
+		}
+		if err := s.alertMgr().NotifyJobEvent(ctx, job, model.NotificationEventJobQueuedTooLong); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		notified++
+	}
+	return notified, errors.Join(errs...)
+}
+
+// heldJobNames returns workflow and job array elements that wait for their siblings on purpose.
+func (s *NotificationService) heldJobNames(ctx context.Context, jobs []*model.Job) (map[string]bool, error) {
+	names := make([]string, len(jobs))
+	for i := range jobs {
+		names[i] = jobs[i].JobName
+	}
+	var heldNames []string
+	wn := s.q.WorkflowNode
+	if err := wn.WithContext(ctx).Where(wn.JobName.In(names...), wn.Held.Is(true)).Pluck(wn.JobName, &heldNames); err != nil {
+		return nil, err
+	}
+	var arrayHeld []string
+	jat := s.q.JobArrayTask
+	if err := jat.WithContext(ctx).Where(jat.JobName.In(names...), jat.Held.Is(true)).Pluck(jat.JobName, &arrayHeld); err != nil {
+		return nil, err
+	}
+	held := make(map[string]bool, len(heldNames)+len(arrayHeld))
+	for _, name := range append(heldNames, arrayHeld...) {
+		held[name] = true
+	}
+	return held, nil
+}
+
+func balanceAlertKey(userID, accountID uint) string {
+	return fmt.Sprintf("billing-balance-%d-%d", userID, accountID)
+}
+
+// notifyLowBalances notifies once per drop below the threshold; the record is cleared
+// when the balance recovers so the next drop notifies again.
+func (s *NotificationService) notifyLowBalances(ctx context.Context, rules []*model.NotificationRule) (int, error) {
+	if s.billingService == nil || !s.billingService.IsFeatureEnabled(ctx) || !s.billingService.IsActive(ctx) {
+		return 0, nil
+	}
+	userIDs := subscribedUserIDs(rules, model.NotificationEventBalanceLow)
+	if len(userIDs) == 0 {
+		return 0, nil
+	}
+
+	ua := s.q.UserAccount
+	userAccounts, err := ua.WithContext(ctx).Where(ua.UserID.In(userIDs...)).Find()
+	if err != nil {
+		return 0, err
+	}
+	accountIDs := make([]uint, 0, len(userAccounts))
+	for _, userAccount := range userAccounts {
+		accountIDs = append(accountIDs, userAccount.AccountID)
+	}
+	acc := s.q.Account
+	accounts, err := acc.WithContext(ctx).Where(acc.ID.In(accountIDs...)).Find()
+	if err != nil {
+		return 0, err
+	}
+	accountNames := make(map[uint]string, len(accounts))
+	for _, account := range accounts {
+		accountNames[account.ID] = account.Nickname
+	}
+	u := s.q.User
+	users, err := u.WithContext(ctx).Where(u.ID.In(userIDs...)).Find()
+	if err != nil {
+		return 0, err
+	}
+	extraBalance := make(map[uint]int64, len(users))
+	for _, user := range users {
+		extraBalance[user.ID] = user.ExtraBalance
+	}
+
+	notified := 0
+	var errs []error
+	for _, userAccount := range userAccounts {
+		balance := userAccount.PeriodFreeBalance + extraBalance[userAccount.UserID]
+		sent, err := s.checkLowBalance(ctx, userAccount, accountNames[userAccount.AccountID], balance)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if sent {
+			notified++
+		}
+	}
+	return notified, errors.Join(errs...)
+}
+
+func (s *NotificationService) checkLowBalance(
+	ctx context.Context,
+	userAccount *model.UserAccount,
+	accountName string,
+	balance int64,
+) (bool, error) {
+	rule, err := alert.FindNotificationRule(ctx, s.q, userAccount.UserID, userAccount.AccountID)
+	if err != nil || rule == nil || !rule.Subscribes(model.NotificationEventBalanceLow) {
+		return false, err
+	}
+	key := balanceAlertKey(userAccount.UserID, userAccount.AccountID)
+	a := s.q.Alert
+	alertType := string(model.NotificationEventBalanceLow)
+
+	if balance >= rule.BalanceLowThreshold {
+		_, err := a.WithContext(ctx).Unscoped().Where(a.JobName.Eq(key), a.AlertType.Eq(alertType)).Delete()
+		return false, err
+	}
+	count, err := a.WithContext(ctx).Where(a.JobName.Eq(key), a.AlertType.Eq(alertType)).Count()
+	if err != nil || count > 0 {
+		return false, err
+	}
+
+	notice := &alert.Notice{
+		Title: "账户点数余额不足",
+		Message: fmt.Sprintf("您在账户 <strong>%s</strong> 中的可用点数为 %.2f，已低于提醒阈值 %.2f。余额耗尽后将无法提交新的作业。",
+			accountName, ToDisplayPoints(balance), ToDisplayPoints(rule.BalanceLowThreshold)),
+		URL:        fmt.Sprintf("https://%s/portal/overview", config.GetConfig().Host),
+		ButtonText: "查看账单",
+	}
+	if err := s.alertMgr().NotifyUserEvent(ctx, userAccount.UserID, userAccount.AccountID,
+		model.NotificationEventBalanceLow, notice); err != nil {
+		return false, err
+	}
+	return true, a.WithContext(ctx).Create(&model.Alert{
+		JobName:        key,
+		AlertType:      alertType,
+		AlertTimestamp: utils.GetLocalTime(),
+		SendCount:      1,
+	})
+}
+
+// NotifyApprovalOrderReviewed tells the creator of an approval order about the review result.
+func (s *NotificationService) NotifyApprovalOrderReviewed(ctx context.Context, order *model.ApprovalOrder) {
+	if s == nil || order == nil {
+		return
+	}
+	notice := &alert.Notice{
+		Title: "审批工单已处理",
+		Message: fmt.Sprintf("您的审批工单 <strong>%s</strong> (ID: %d) 已被审核，结果为：%s。%s",
+			order.Name, order.ID, order.Status, order.ReviewNotes),
+		URL:        fmt.Sprintf("https://%s/portal/more/orders/%d", config.GetConfig().Host, order.ID),
+		ButtonText: "查看工单",
+	}
+	if err := s.alertMgr().NotifyUserEvent(ctx, order.CreatorID, 0, model.NotificationEventApprovalReviewed, notice); err != nil {
+		klog.Warningf("notify approval order %d reviewed failed: %v", order.ID, err)
+	}
+}
+
+// NotifyApprovalOrderRequested tells the assignee of an order, such as the owner of a requested
+// dataset, that it waits for their review.
+func (s *NotificationService) NotifyApprovalOrderRequested(ctx context.Context, order *model.ApprovalOrder, requester string) {
+	if s == nil || order == nil || order.AssigneeID == 0 {
+		return
+	}
+	content := order.Content.Data()
+	notice := &alert.Notice{
+		Title: "新的审批工单待处理",
+		Message: fmt.Sprintf("用户 <strong>%s</strong> 申请访问数据集 <strong>%s</strong> (ID: %d)，申请原因：%s",
+			requester, order.Name, content.ApprovalOrderTypeID, content.ApprovalOrderReason),
+		URL:        fmt.Sprintf("https://%s/portal/more/orders/%d", config.GetConfig().Host, order.ID),
+		ButtonText: "处理工单",
+	}
+	if err := s.alertMgr().NotifyUserEvent(ctx, order.AssigneeID, 0, model.NotificationEventApprovalRequested, notice); err != nil {
+		klog.Warningf("notify approval order %d requested failed: %v", order.ID, err)
+	}
+}
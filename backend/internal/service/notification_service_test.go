@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/pkg/alert"
+	"github.com/raids-lab/crater/pkg/utils"
+)
+
+type recordingAlerter struct {
+	alert.AlertInterface
+	jobEvents  []string
+	userEvents []model.NotificationEvent
+}
+
+func (r *recordingAlerter) NotifyJobEvent(_ context.Context, job *model.Job, event model.NotificationEvent) error {
+	r.jobEvents = append(r.jobEvents, job.JobName+":"+string(event))
+	return nil
+}
+
+func (r *recordingAlerter) NotifyUserEvent(
+	_ context.Context, _, _ uint, event model.NotificationEvent, _ *alert.Notice,
+) error {
+	r.userEvents = append(r.userEvents, event)
+	return nil
+}
+
+func newNotificationTestService(t *testing.T, name string) (*NotificationService, *recordingAlerter, *gorm.DB) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+name+"?mode=memory&cache=shared"), &gorm.Config{
+		DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.Migrator().CreateTable(
+		&model.Job{}, &model.UserAccount{}, &model.Alert{}, &model.NotificationRule{},
+		&model.WorkflowNode{}, &model.JobArrayTask{},
+	); err != nil {
+		t.Fatalf("create tables: %v", err)
+	}
+	recorder := &recordingAlerter{}
+	svc := NewNotificationService(query.Use(db), nil)
+	svc.alerter = recorder
+	return svc, recorder, db
+}
+
+func TestNotificationServiceUpsertRule(t *testing.T) {
+	svc, _, db := newNotificationTestService(t, "notification_upsert")
+	ctx := t.Context()
+	if err := db.Create(&model.UserAccount{UserID: 1, AccountID: 2}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	rule := &model.NotificationRule{
+		UserID: 1,
+		Events: datatypes.NewJSONType([]model.NotificationEvent{model.NotificationEventJobFailed}),
+	}
+	saved, err := svc.UpsertRule(ctx, rule)
+	if err != nil {
+		t.Fatalf("UpsertRule() error = %v", err)
+	}
+	if saved.QueuedTooLongMinutes != model.DefaultQueuedTooLongMinutes {
+		t.Fatalf("QueuedTooLongMinutes = %d, want default", saved.QueuedTooLongMinutes)
+	}
+
+	replaced, err := svc.UpsertRule(ctx, &model.NotificationRule{
+		UserID:        1,
+		Events:        datatypes.NewJSONType([]model.NotificationEvent{model.NotificationEventJobCompleted}),
+		DigestMinutes: 60,
+	})
+	if err != nil {
+		t.Fatalf("UpsertRule() replace error = %v", err)
+	}
+	if replaced.ID != saved.ID || !replaced.Subscribes(model.NotificationEventJobCompleted) || replaced.DigestMinutes != 60 {
+		t.Fatalf("replaced rule = %+v, want the same row updated", replaced)
+	}
+
+	for _, bad := range []*model.NotificationRule{
+		{UserID: 1, Events: datatypes.NewJSONType([]model.NotificationEvent{"job_exploded"})},
+		{UserID: 1, QuietStart: "22:00"},
+		{UserID: 1, QuietStart: "25:00", QuietEnd: "08:00"},
+		{UserID: 1, AccountID: 3},
+	} {
+		if _, err := svc.UpsertRule(ctx, bad); err == nil {
+			t.Fatalf("UpsertRule(%+v) should fail", bad)
+		}
+	}
+
+	rules, err := svc.ListRules(ctx, 1)
+	if err != nil || len(rules) != 1 {
+		t.Fatalf("ListRules() = %v, %v", rules, err)
+	}
+	if err := svc.DeleteRule(ctx, 1, 0); err != nil {
+		t.Fatalf("DeleteRule() error = %v", err)
+	}
+	if err := svc.DeleteRule(ctx, 1, 0); err == nil {
+		t.Fatal("deleting a missing rule should fail")
+	}
+}
+
+func TestNotificationServiceNotifyQueuedJobs(t *testing.T) {
+	svc, recorder, db := newNotificationTestService(t, "notification_queued")
+	ctx := t.Context()
+	now := utils.GetLocalTime()
+
+	rules := []*model.NotificationRule{
+		{
+			UserID:               1,
+			Events:               datatypes.NewJSONType([]model.NotificationEvent{model.NotificationEventJobQueuedTooLong}),
+			QueuedTooLongMinutes: 30,
+		},
+		// Account 2 overrides the user-wide rule and does not subscribe.
+		{UserID: 1, AccountID: 2, QueuedTooLongMinutes: 30},
+	}
+	for _, rule := range rules {
+		if err := db.Create(rule).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	jobs := []*model.Job{
+		{JobName: "old-pending", UserID: 1, AccountID: 1, Status: batch.Pending, CreationTimestamp: now.Add(-time.Hour)},
+		{JobName: "old-prequeue", UserID: 1, AccountID: 1, Status: model.Prequeue, CreationTimestamp: now.Add(-time.Hour)},
+		{JobName: "fresh-pending", UserID: 1, AccountID: 1, Status: batch.Pending, CreationTimestamp: now.Add(-time.Minute)},
+		{JobName: "running", UserID: 1, AccountID: 1, Status: batch.Running, CreationTimestamp: now.Add(-time.Hour)},
+		{JobName: "held-array", UserID: 1, AccountID: 1, Status: model.Prequeue, CreationTimestamp: now.Add(-time.Hour)},
+		{JobName: "other-account", UserID: 1, AccountID: 2, Status: batch.Pending, CreationTimestamp: now.Add(-time.Hour)},
+		{JobName: "other-user", UserID: 2, AccountID: 1, Status: batch.Pending, CreationTimestamp: now.Add(-time.Hour)},
+	}
+	for _, job := range jobs {
+		job.Name = job.JobName
+		if err := db.Create(job).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.Create(&model.JobArrayTask{ArrayID: 1, JobName: "held-array", Held: true}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	notified, err := svc.notifyQueuedJobs(ctx, rules)
+	if err != nil {
+		t.Fatalf("notifyQueuedJobs() error = %v", err)
+	}
+	want := map[string]bool{
+		"old-pending:job_queued_too_long":  true,
+		"old-prequeue:job_queued_too_long": true,
+	}
+	if notified != len(want) || len(recorder.jobEvents) != len(want) {
+		t.Fatalf("notified %d, events %v; want %v", notified, recorder.jobEvents, want)
+	}
+	for _, event := range recorder.jobEvents {
+		if !want[event] {
+			t.Fatalf("unexpected event %s", event)
+		}
+	}
+}
+
+func TestNotificationServiceCheckLowBalance(t *testing.T) {
+	svc, recorder, db := newNotificationTestService(t, "notification_balance")
+	ctx := t.Context()
+	rule := &model.NotificationRule{
+		UserID:               1,
+		Events:               datatypes.NewJSONType([]model.NotificationEvent{model.NotificationEventBalanceLow}),
+		QueuedTooLongMinutes: 30,
+		BalanceLowThreshold:  BillingWholePoints(10),
+	}
+	if err := db.Create(rule).Error; err != nil {
+		t.Fatal(err)
+	}
+	userAccount := &model.UserAccount{UserID: 1, AccountID: 1}
+
+	steps := []struct {
+		balance  int64
+		wantSent bool
+	}{
+		{balance: BillingWholePoints(5), wantSent: true},
+		{balance: BillingWholePoints(4), wantSent: false}, // still low, already notified
+		{balance: BillingWholePoints(20), wantSent: false},
+		{balance: BillingWholePoints(1), wantSent: true}, // dropped again after recovery
+	}
+	for i, step := range steps {
+		sent, err := svc.checkLowBalance(ctx, userAccount, "default", step.balance)
+		if err != nil {
+			t.Fatalf("step %d: checkLowBalance() error = %v", i, err)
+		}
+		if sent != step.wantSent {
+			t.Fatalf("step %d: sent = %v, want %v", i, sent, step.wantSent)
+		}
+	}
+	if len(recorder.userEvents) != 2 {
+		t.Fatalf("user events = %v, want 2 balance notifications", recorder.userEvents)
+	}
+}
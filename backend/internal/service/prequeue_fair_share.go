@@ -0,0 +1,389 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	v1 "k8s.io/api/core/v1"
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+
+	"github.com/raids-lab/crater/dao/model"
+)
+
+// fairShareLookbackHalfLives bounds the usage history scan; older jobs weigh less than 1/32.
+const fairShareLookbackHalfLives = 5
+
+// DefaultFairShare is used for accounts and members without a configured share.
+const DefaultFairShare = 1
+
+type fairSharePair struct {
+	accountID uint
+	userID    uint
+}
+
+// FairShareEntry is the decayed usage and resulting factor of an account or of a user in an account.
+type FairShareEntry struct {
+	Share         int     `json:"share"`
+	Configured    bool    `json:"configured"`
+	UsageGPUHours float64 `json:"usageGpuHours"`
+	Factor        float64 `json:"factor"`
+}
+
+// FairShareSnapshot holds fair-share factors for every account and user that has recent usage
+// or waiting prequeue jobs. Anyone outside the snapshot has no usage and therefore factor 1.
+type FairShareSnapshot struct {
+	accounts map[uint]*FairShareEntry
+	users    map[fairSharePair]*FairShareEntry
+}
+
+// Account returns the account entry, or nil when the account is idle.
+func (s *FairShareSnapshot) Account(accountID uint) *FairShareEntry {
+	if s == nil {
+		return nil
+	}
+	return s.accounts[accountID]
+}
+
+// User returns the entry of a user inside an account, or nil when the user is idle there.
+func (s *FairShareSnapshot) User(accountID, userID uint) *FairShareEntry {
+	if s == nil {
+		return nil
+	}
+	return s.users[fairSharePair{accountID: accountID, userID: userID}]
+}
+
+// Priority is the product of the account factor and the user factor, in (0, 1].
+func (s *FairShareSnapshot) Priority(accountID, userID uint) float64 {
+	priority := 1.0
+	if entry := s.Account(accountID); entry != nil {
+		priority *= entry.Factor
+	}
+	if entry := s.User(accountID, userID); entry != nil {
+		priority *= entry.Factor
+	}
+	return math.Round(priority*1e4) / 1e4
+}
+
+// SortJobsByFairShare orders jobs by descending priority. The sort is stable, so jobs with
+// equal priority keep their incoming (FIFO) order.
+func (s *FairShareSnapshot) SortJobsByFairShare(jobs []*model.Job) {
+	sort.SliceStable(jobs, func(i, j int) bool {
+		return s.Priority(jobs[i].AccountID, jobs[i].UserID) > s.Priority(jobs[j].AccountID, jobs[j].UserID)
+	})
+}
+
+type fairShareUsageRecord struct {
+	AccountID          uint
+	UserID             uint
+	Status             batch.JobPhase
+	RunningTimestamp   time.Time
+	CompletedTimestamp time.Time
+	Resources          string
+}
+
+// LoadFairShareSnapshot computes decayed GPU-hours per account and per user from job history
+// and turns them into fair-share factors against the configured shares.
+func (s *PrequeueService) LoadFairShareSnapshot(
+	ctx context.Context,
+	halfLife time.Duration,
+	now time.Time,
+) (*FairShareSnapshot, error) {
+	if halfLife <= 0 {
+		return nil, fmt.Errorf("fair share half-life must be positive")
+	}
+	db := s.q.Job.WithContext(ctx).UnderlyingDB()
+
+	records := make([]fairShareUsageRecord, 0)
+	windowStart := now.Add(-fairShareLookbackHalfLives * halfLife)
+	if err := db.Model(&model.Job{}).
+		Select("account_id", "user_id", "status", "running_timestamp", "completed_timestamp", "resources").
+		Where("running_timestamp > ?", time.Time{}).
+		Where("status = ? OR completed_timestamp >= ?", batch.Running, windowStart).
+		Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	accountUsage := make(map[uint]float64)
+	userUsage := make(map[fairSharePair]float64)
+	for i := range records {
+		record := &records[i]
+		usage := decayedGPUHours(record, halfLife, now)
+		if usage <= 0 {
+			continue
+		}
+		pair := fairSharePair{accountID: record.AccountID, userID: record.UserID}
+		accountUsage[pair.accountID] += usage
+		userUsage[pair] += usage
+	}
+
+	waiting := make([]struct {
+		AccountID uint
+		UserID    uint
+	}, 0)
+	if err := db.Model(&model.Job{}).
+		Distinct("account_id", "user_id").
+		Where("status = ?", model.Prequeue).
+		Scan(&waiting).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range waiting {
+		pair := fairSharePair{accountID: row.AccountID, userID: row.UserID}
+		if _, ok := userUsage[pair]; !ok {
+			userUsage[pair] = 0
+		}
+		if _, ok := accountUsage[pair.accountID]; !ok {
+			accountUsage[pair.accountID] = 0
+		}
+	}
+
+	accountShares, userShares, err := s.loadFairShares(ctx, accountUsage, userUsage)
+	if err != nil {
+		return nil, err
+	}
+	return buildFairShareSnapshot(accountUsage, userUsage, accountShares, userShares), nil
+}
+
+func (s *PrequeueService) loadFairShares(
+	ctx context.Context,
+	accountUsage map[uint]float64,
+	userUsage map[fairSharePair]float64,
+) (accountShares map[uint]*int, userShares map[fairSharePair]*int, err error) {
+	accountShares = make(map[uint]*int)
+	userShares = make(map[fairSharePair]*int)
+	if len(accountUsage) == 0 {
+		return accountShares, userShares, nil
+	}
+	accountIDs := make([]uint, 0, len(accountUsage))
+	for accountID := range accountUsage {
+		accountIDs = append(accountIDs, accountID)
+	}
+
+	accounts, err := s.q.Account.WithContext(ctx).
+		Select(s.q.Account.ID, s.q.Account.FairShare).
+		Where(s.q.Account.ID.In(accountIDs...)).
+		Find()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, account := range accounts {
+		accountShares[account.ID] = account.FairShare
+	}
+
+	members, err := s.q.UserAccount.WithContext(ctx).
+		Select(s.q.UserAccount.AccountID, s.q.UserAccount.UserID, s.q.UserAccount.FairShare).
+		Where(s.q.UserAccount.AccountID.In(accountIDs...)).
+		Find()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, member := range members {
+		pair := fairSharePair{accountID: member.AccountID, userID: member.UserID}
+		if _, ok := userUsage[pair]; ok {
+			userShares[pair] = member.FairShare
+		}
+	}
+	return accountShares, userShares, nil
+}
+
+// buildFairShareSnapshot normalizes shares and usage among siblings and applies the classic
+// factor 2^(-usage/share): a sibling that used exactly its share of the recent usage gets 0.5.
+func buildFairShareSnapshot(
+	accountUsage map[uint]float64,
+	userUsage map[fairSharePair]float64,
+	accountShares map[uint]*int,
+	userShares map[fairSharePair]*int,
+) *FairShareSnapshot {
+	snapshot := &FairShareSnapshot{
+		accounts: make(map[uint]*FairShareEntry, len(accountUsage)),
+		users:    make(map[fairSharePair]*FairShareEntry, len(userUsage)),
+	}
+
+	accountEntries := make(map[uint]*FairShareEntry, len(accountUsage))
+	for accountID, usage := range accountUsage {
+		accountEntries[accountID] = newFairShareEntry(accountShares[accountID], usage)
+	}
+	applyFairShareFactors(accountEntries)
+	snapshot.accounts = accountEntries
+
+	siblings := make(map[uint]map[fairSharePair]*FairShareEntry)
+	for pair, usage := range userUsage {
+		entry := newFairShareEntry(userShares[pair], usage)
+		snapshot.users[pair] = entry
+		if siblings[pair.accountID] == nil {
+			siblings[pair.accountID] = make(map[fairSharePair]*FairShareEntry)
+		}
+		siblings[pair.accountID][pair] = entry
+	}
+	for _, group := range siblings {
+		applyFairShareFactors(group)
+	}
+	return snapshot
+}
+
+func newFairShareEntry(share *int, usage float64) *FairShareEntry {
+	entry := &FairShareEntry{Share: DefaultFairShare, UsageGPUHours: usage, Factor: 1}
+	if share != nil && *share > 0 {
+		entry.Share = *share
+		entry.Configured = true
+	}
+	return entry
+}
+
+func applyFairShareFactors[K comparable](entries map[K]*FairShareEntry) {
+	totalShare, totalUsage := 0.0, 0.0
+	for _, entry := range entries {
+		totalShare += float64(entry.Share)
+		totalUsage += entry.UsageGPUHours
+	}
+	if totalShare <= 0 || totalUsage <= 0 {
+		return
+	}
+	for _, entry := range entries {
+		normalizedShare := float64(entry.Share) / totalShare
+		normalizedUsage := entry.UsageGPUHours / totalUsage
+		entry.Factor = math.Pow(2, -normalizedUsage/normalizedShare)
+	}
+}
+
+// decayedGPUHours integrates gpus * 2^(-age/halfLife) over the job's running interval.
+func decayedGPUHours(record *fairShareUsageRecord, halfLife time.Duration, now time.Time) float64 {
+	gpus := fairShareGPUCount(record.Resources)
+	if gpus <= 0 || record.RunningTimestamp.IsZero() {
+		return 0
+	}
+	end := record.CompletedTimestamp
+	if record.Status == batch.Running || end.IsZero() || end.After(now) {
+		end = now
+	}
+	start := record.RunningTimestamp
+	if !end.After(start) {
+		return 0
+	}
+	halfLifeHours := halfLife.Hours()
+	decay := func(t time.Time) float64 {
+		return math.Pow(2, -now.Sub(t).Hours()/halfLifeHours)
+	}
+	return gpus * halfLifeHours / math.Ln2 * (decay(end) - decay(start))
+}
+
+func fairShareGPUCount(rawResources string) float64 {
+	if rawResources == "" {
+		return 0
+	}
+	var resources v1.ResourceList
+	if err := json.Unmarshal([]byte(rawResources), &resources); err != nil {
+		return 0
+	}
+	total := 0.0
+	for name, quantity := range resources {
+		if strings.Contains(name.String(), "/") {
+			total += quantity.AsApproximateFloat64()
+		}
+	}
+	return total
+}
+
+// AccountFairShareReport is the fair-share view of one account for administrators.
+type AccountFairShareReport struct {
+	AccountID uint                     `json:"accountId"`
+	Policy    string                   `json:"policy"`
+	HalfLife  int64                    `json:"halfLifeHours"`
+	Account   FairShareEntry           `json:"account"`
+	Members   []AccountFairShareMember `json:"members"`
+}
+
+type AccountFairShareMember struct {
+	UserID   uint   `json:"userId"`
+	Name     string `json:"name"`
+	Nickname string `json:"nickname"`
+	FairShareEntry
+	Priority float64 `json:"priority"`
+}
+
+// GetAccountFairShare reports the account's and its members' shares, decayed usage and priority.
+func (s *PrequeueService) GetAccountFairShare(ctx context.Context, accountID uint) (*AccountFairShareReport, error) {
+	cfg, err := s.configService.GetPrequeueConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	account, err := s.q.Account.WithContext(ctx).Where(s.q.Account.ID.Eq(accountID)).First()
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := s.LoadFairShareSnapshot(ctx, time.Duration(cfg.FairShareHalfLifeHours)*time.Hour, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	report := &AccountFairShareReport{
+		AccountID: accountID,
+		Policy:    cfg.ActivationPolicy,
+		HalfLife:  cfg.FairShareHalfLifeHours,
+		Account:   *newFairShareEntry(account.FairShare, 0),
+		Members:   make([]AccountFairShareMember, 0),
+	}
+	if entry := snapshot.Account(accountID); entry != nil {
+		report.Account = *entry
+	}
+
+	ua := s.q.UserAccount
+	u := s.q.User
+	rows := make([]struct {
+		UserID    uint
+		Name      string
+		Nickname  string
+		FairShare *int
+	}, 0)
+	if err := ua.WithContext(ctx).
+		Select(ua.UserID, u.Name, u.Nickname, ua.FairShare).
+		Join(u, u.ID.EqCol(ua.UserID)).
+		Where(ua.AccountID.Eq(accountID)).
+		Order(ua.UserID).
+		Scan(&rows); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		entry := newFairShareEntry(row.FairShare, 0)
+		if computed := snapshot.User(accountID, row.UserID); computed != nil {
+			entry = computed
+		}
+		report.Members = append(report.Members, AccountFairShareMember{
+			UserID:         row.UserID,
+			Name:           row.Name,
+			Nickname:       row.Nickname,
+			FairShareEntry: *entry,
+			Priority:       snapshot.Priority(accountID, row.UserID),
+		})
+	}
+	return report, nil
+}
+
+// SetFairShare updates the share of an account (userID == 0) or of a member inside it.
+// A nil share restores the default.
+func (s *PrequeueService) SetFairShare(ctx context.Context, accountID, userID uint, share *int) error {
+	if share != nil && *share <= 0 {
+		return fmt.Errorf("fair share must be greater than 0")
+	}
+	db := s.q.Account.WithContext(ctx).UnderlyingDB()
+	var result *gorm.DB
+	if userID == 0 {
+		result = db.Model(&model.Account{}).Where("id = ?", accountID).Update("fair_share", share)
+	} else {
+		result = db.Model(&model.UserAccount{}).
+			Where("account_id = ? AND user_id = ?", accountID, userID).
+			Update("fair_share", share)
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+)
+
+func TestDecayedGPUHours(t *testing.T) {
+	now := time.Date(2026, 8, 25, 12, 0, 0, 0, time.UTC)
+	record := &fairShareUsageRecord{
+		Status:             batch.Completed,
+		RunningTimestamp:   now.Add(-4 * time.Hour),
+		CompletedTimestamp: now.Add(-2 * time.Hour),
+		Resources:          `{"cpu":"8","nvidia.com/gpu":"2"}`,
+	}
+	// With a 2h half-life the interval [-4h, -2h] weighs 2h/ln2 * (1/2 - 1/4) per GPU.
+	want := 2 * 2 / math.Ln2 * 0.25
+	if got := decayedGPUHours(record, 2*time.Hour, now); math.Abs(got-want) > 1e-9 {
+		t.Fatalf("decayed usage = %v, want %v", got, want)
+	}
+
+	record.Resources = `{"cpu":"8"}`
+	if got := decayedGPUHours(record, 2*time.Hour, now); got != 0 {
+		t.Fatalf("cpu-only job usage = %v, want 0", got)
+	}
+}
+
+func TestFairShareSnapshotOrdering(t *testing.T) {
+	snapshot := buildFairShareSnapshot(
+		map[uint]float64{1: 30, 2: 10},
+		map[fairSharePair]float64{{1, 10}: 30, {2, 20}: 10, {2, 21}: 0},
+		map[uint]*int{1: ptr.To(3)},
+		map[fairSharePair]*int{},
+	)
+	// Account 1 used 75% of the usage with 75% of the shares, account 2 used 25% with 25%.
+	if got := snapshot.Account(1).Factor; math.Abs(got-0.5) > 1e-9 {
+		t.Fatalf("account 1 factor = %v, want 0.5", got)
+	}
+	if got := snapshot.Priority(2, 21); got != 0.5 {
+		t.Fatalf("idle member priority = %v, want the account factor 0.5", got)
+	}
+	// Inside account 2 the busy member used all usage with half the shares: 0.5 * 2^-2.
+	if got := snapshot.Priority(2, 20); got != 0.125 {
+		t.Fatalf("busy member priority = %v, want 0.125", got)
+	}
+	if got := snapshot.Priority(3, 30); got != 1 {
+		t.Fatalf("unknown account priority = %v, want 1", got)
+	}
+
+	jobs := []*model.Job{
+		{JobName: "a", AccountID: 2, UserID: 20},
+		{JobName: "b", AccountID: 1, UserID: 10},
+		{JobName: "c", AccountID: 2, UserID: 21},
+		{JobName: "d", AccountID: 1, UserID: 10},
+	}
+	snapshot.SortJobsByFairShare(jobs)
+	order := ""
+	for _, job := range jobs {
+		order += job.JobName
+	}
+	if order != "cbda" {
+		t.Fatalf("fair share order = %s, want cbda", order)
+	}
+}
+
+func TestLoadFairShareSnapshotAndSetShare(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:prequeue_fair_share?mode=memory&cache=shared"), &gorm.Config{
+		DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Migrator().CreateTable(&model.Account{}, &model.UserAccount{}, &model.Job{}); err != nil {
+		t.Fatal(err)
+	}
+	svc := NewPrequeueService(query.Use(db), nil)
+	ctx := t.Context()
+	now := time.Now()
+
+	for _, account := range []*model.Account{
+		{Model: gorm.Model{ID: 1}, Name: "busy", Space: "/busy"},
+		{Model: gorm.Model{ID: 2}, Name: "idle", Space: "/idle"},
+	} {
+		if err := db.Create(account).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, member := range []*model.UserAccount{
+		{UserID: 10, AccountID: 1}, {UserID: 20, AccountID: 2},
+	} {
+		if err := db.Create(member).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	gpus := datatypes.NewJSONType(v1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")})
+	for _, job := range []*model.Job{
+		{
+			JobName: "running", AccountID: 1, UserID: 10, Status: batch.Running,
+			CreationTimestamp: now.Add(-time.Hour), RunningTimestamp: now.Add(-time.Hour), Resources: gpus,
+		},
+		{JobName: "wait-busy", AccountID: 1, UserID: 10, Status: model.Prequeue, CreationTimestamp: now, Resources: gpus},
+		{JobName: "wait-idle", AccountID: 2, UserID: 20, Status: model.Prequeue, CreationTimestamp: now, Resources: gpus},
+	} {
+		if err := db.Create(job).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	snapshot, err := svc.LoadFairShareSnapshot(ctx, 24*time.Hour, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Priority(2, 20) != 1 || snapshot.Priority(1, 10) >= snapshot.Priority(2, 20) {
+		t.Fatalf("priorities busy=%v idle=%v, want idle account first",
+			snapshot.Priority(1, 10), snapshot.Priority(2, 20))
+	}
+
+	if err := svc.SetFairShare(ctx, 1, 0, ptr.To(4)); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.SetFairShare(ctx, 1, 99, ptr.To(2)); err == nil {
+		t.Fatal("setting the share of a non-member should fail")
+	}
+	if err := svc.SetFairShare(ctx, 1, 10, ptr.To(0)); err == nil {
+		t.Fatal("zero share should be rejected")
+	}
+	snapshot, err = svc.LoadFairShareSnapshot(ctx, 24*time.Hour, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry := snapshot.Account(1); entry == nil || entry.Share != 4 || !entry.Configured {
+		t.Fatalf("account 1 entry = %+v, want configured share 4", entry)
+	}
+}
@@ -7,7 +7,9 @@ import (
 	"sort"
 	"strings"
 
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
+	"k8s.io/utils/ptr"
 	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
 
 	"github.com/raids-lab/crater/dao/model"
@@ -39,6 +41,19 @@ func increaseDatasetMountCount(ctx context.Context, job *batch.Job) error {
 		Error
 }
 
+// MountedDatasetVersions reads the dataset versions a job mounts from its annotations.
+func MountedDatasetVersions(job *batch.Job) *datatypes.JSONType[[]model.JobDatasetVersion] {
+	raw := strings.TrimSpace(job.Annotations[AnnotationKeyMountedDatasetVersions])
+	if raw == "" {
+		return nil
+	}
+	var versions []model.JobDatasetVersion
+	if err := json.Unmarshal([]byte(raw), &versions); err != nil || len(versions) == 0 {
+		return nil
+	}
+	return ptr.To(datatypes.NewJSONType(versions))
+}
+
 func parseMountedDatasetIDs(raw string) ([]uint, error) {
 	var ids []uint
 	if err := json.Unmarshal([]byte(raw), &ids); err != nil {
@@ -44,6 +44,9 @@ const (
 	annotationKeyForwards     = "crater.raids.io/forwards"
 	// AnnotationKeyMountedDatasetIDs stores mounted dataset IDs as JSON array on job annotations.
 	AnnotationKeyMountedDatasetIDs = "crater.raids.io/mounted-dataset-ids"
+	// AnnotationKeyMountedDatasetVersions stores the mounted dataset versions as a JSON array of
+	// model.JobDatasetVersion, which is copied into the job record.
+	AnnotationKeyMountedDatasetVersions = "crater.raids.io/mounted-dataset-versions"
 
 	jobTypeJupyter = "jupyter"
 	jobTypeWebIDE  = "webide"
@@ -102,6 +105,7 @@ func GenerateJobRecord(
 		Resources:               datatypes.NewJSONType(CalculateJobResources(job)),
 		Attributes:              datatypes.NewJSONType(job),
 		Template:                job.Annotations[annotationKeyTaskTemplate],
+		DatasetVersions:         MountedDatasetVersions(job),
 		AlertEnabled:            alertEnabled,
 	}
 	return ret, nil
@@ -0,0 +1,434 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/bizerr"
+	"github.com/raids-lab/crater/pkg/utils"
+)
+
+// WorkflowNodeSpec is the graph part of a workflow node, independent of the job it runs.
+type WorkflowNodeSpec struct {
+	Name      string
+	DependsOn []model.WorkflowDependency
+}
+
+// WorkflowNodeState is a workflow node joined with the phase of its job record.
+type WorkflowNodeState struct {
+	Node      *model.WorkflowNode
+	JobStatus batch.JobPhase
+}
+
+// WorkflowService keeps the DAG bookkeeping for vcjob workflows. Job creation and
+// activation stay with the vcjob handler and the prequeue watcher; this service only
+// decides which held nodes may enter the normal prequeue flow.
+type WorkflowService struct {
+	q *query.Query
+}
+
+func NewWorkflowService(q *query.Query) *WorkflowService {
+	return &WorkflowService{q: q}
+}
+
+// SortWorkflowNodes validates the DAG and returns node names in a topological order.
+func SortWorkflowNodes(nodes []WorkflowNodeSpec) ([]string, error) {
+	if len(nodes) == 0 {
+		return nil, bizerr.BadRequest.ParameterError.New("workflow must contain at least one node")
+	}
+
+	byName := make(map[string]WorkflowNodeSpec, len(nodes))
+	for _, node := range nodes {
+		if node.Name == "" {
+			return nil, bizerr.BadRequest.ParameterError.New("workflow node name is required")
+		}
+		if _, ok := byName[node.Name]; ok {
+			return nil, bizerr.BadRequest.ParameterError.New(fmt.Sprintf("duplicate workflow node %q", node.Name))
+		}
+		byName[node.Name] = node
+	}
+
+	inDegree := make(map[string]int, len(nodes))
+	children := make(map[string][]string, len(nodes))
+	for _, node := range nodes {
+		seen := make(map[string]struct{}, len(node.DependsOn))
+		for _, dep := range node.DependsOn {
+			if dep.Node == node.Name {
+				return nil, bizerr.BadRequest.ParameterError.New(fmt.Sprintf("workflow node %q depends on itself", node.Name))
+			}
+			if _, ok := byName[dep.Node]; !ok {
+				return nil, bizerr.BadRequest.ParameterError.New(
+					fmt.Sprintf("workflow node %q depends on unknown node %q", node.Name, dep.Node),
+				)
+			}
+			if _, ok := seen[dep.Node]; ok {
+				return nil, bizerr.BadRequest.ParameterError.New(
+					fmt.Sprintf("workflow node %q lists %q more than once", node.Name, dep.Node),
+				)
+			}
+			switch dep.Condition {
+			case model.WorkflowDependencyOnSuccess, model.WorkflowDependencyOnCompletion:
+			default:
+				return nil, bizerr.BadRequest.ParameterError.New(
+					fmt.Sprintf("workflow dependency condition must be %q or %q",
+						model.WorkflowDependencyOnSuccess, model.WorkflowDependencyOnCompletion),
+				)
+			}
+			seen[dep.Node] = struct{}{}
+			inDegree[node.Name]++
+			children[dep.Node] = append(children[dep.Node], node.Name)
+		}
+	}
+
+	// Kahn's algorithm, seeded in request order so the result is stable.
+	order := make([]string, 0, len(nodes))
+	ready := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		if inDegree[node.Name] == 0 {
+			ready = append(ready, node.Name)
+		}
+	}
+	for len(ready) > 0 {
+		name := ready[0]
+		ready = ready[1:]
+		order = append(order, name)
+		for _, child := range children[name] {
+			inDegree[child]--
+			if inDegree[child] == 0 {
+				ready = append(ready, child)
+			}
+		}
+	}
+	if len(order) != len(nodes) {
+		return nil, bizerr.BadRequest.ParameterError.New("workflow dependencies contain a cycle")
+	}
+	return order, nil
+}
+
+// CreateWorkflow stores the workflow, its nodes and the held job records of downstream
+// nodes in one transaction. Root nodes are submitted by the caller afterwards.
+func (s *WorkflowService) CreateWorkflow(
+	ctx context.Context,
+	workflow *model.Workflow,
+	nodes []*model.WorkflowNode,
+	heldRecords []*model.Job,
+) error {
+	workflow.Status = model.WorkflowStatusRunning
+	return s.q.Transaction(func(tx *query.Query) error {
+		if err := tx.Workflow.WithContext(ctx).Create(workflow); err != nil {
+			return err
+		}
+		for _, node := range nodes {
+			node.WorkflowID = workflow.ID
+		}
+		if err := tx.WorkflowNode.WithContext(ctx).Create(nodes...); err != nil {
+			return err
+		}
+		if len(heldRecords) == 0 {
+			return nil
+		}
+		return tx.Job.WithContext(ctx).Create(heldRecords...)
+	})
+}
+
+// ListWorkflows returns the workflows of a user within an account, newest first.
+func (s *WorkflowService) ListWorkflows(ctx context.Context, userID, accountID uint) ([]*model.Workflow, error) {
+	w := s.q.Workflow
+	return w.WithContext(ctx).
+		Where(w.UserID.Eq(userID), w.AccountID.Eq(accountID)).
+		Order(w.ID.Desc()).
+		Find()
+}
+
+// GetWorkflow loads a workflow owned by the user together with the state of every node.
+func (s *WorkflowService) GetWorkflow(
+	ctx context.Context,
+	id, userID, accountID uint,
+) (*model.Workflow, []WorkflowNodeState, error) {
+	w := s.q.Workflow
+	workflow, err := w.WithContext(ctx).
+		Where(w.ID.Eq(id), w.UserID.Eq(userID), w.AccountID.Eq(accountID)).
+		First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, bizerr.NotFound.DataBaseNotFound.New(fmt.Sprintf("workflow %d not found", id))
+		}
+		return nil, nil, bizerr.Internal.DatabaseError.Wrap(err, "get workflow")
+	}
+	states, err := s.loadNodeStates(ctx, s.q, workflow.ID)
+	if err != nil {
+		return nil, nil, bizerr.Internal.DatabaseError.Wrap(err, "get workflow nodes")
+	}
+	return workflow, states, nil
+}
+
+// CancelWorkflow drops every node that has not been released yet and returns the
+// jobs that are already running, so the caller can delete them from the cluster.
+func (s *WorkflowService) CancelWorkflow(ctx context.Context, id, userID, accountID uint) ([]string, error) {
+	workflow, states, err := s.GetWorkflow(ctx, id, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if isWorkflowFinished(workflow.Status) {
+		return nil, bizerr.Conflict.ResourceStatusError.New(
+			fmt.Sprintf("workflow %d is already %s", id, workflow.Status),
+		)
+	}
+
+	active := make([]string, 0, len(states))
+	err = s.q.Transaction(func(tx *query.Query) error {
+		for _, state := range states {
+			if state.JobStatus == model.Prequeue {
+				if err := skipWorkflowNodeTx(ctx, tx, state.Node); err != nil {
+					return err
+				}
+				continue
+			}
+			if !isJobPhaseFinished(state.JobStatus) {
+				active = append(active, state.Node.JobName)
+			}
+		}
+		_, err := tx.Workflow.WithContext(ctx).
+			Where(tx.Workflow.ID.Eq(workflow.ID)).
+			Update(tx.Workflow.Status, model.WorkflowStatusCancelled)
+		return err
+	})
+	if err != nil {
+		return nil, bizerr.Internal.DatabaseError.Wrap(err, "cancel workflow")
+	}
+	return active, nil
+}
+
+// ReleaseDependents is called once a job reached a terminal phase. It releases every
+// held node of the same workflow whose dependencies are now satisfied, skips those
+// that can no longer be satisfied, and refreshes the workflow status.
+// The returned flag tells the caller whether the prequeue should be rescanned.
+func (s *WorkflowService) ReleaseDependents(ctx context.Context, jobName string) (bool, error) {
+	if s == nil {
+		return false, nil
+	}
+	// Most finished jobs are not part of a workflow, so avoid First and its not-found log.
+	wn := s.q.WorkflowNode
+	matches, err := wn.WithContext(ctx).Where(wn.JobName.Eq(jobName)).Limit(1).Find()
+	if err != nil {
+		return false, err
+	}
+	if len(matches) == 0 {
+		return false, nil
+	}
+	node := matches[0]
+
+	released := false
+	now := utils.GetLocalTime()
+	err = s.q.Transaction(func(tx *query.Query) error {
+		states, err := s.loadNodeStates(ctx, tx, node.WorkflowID)
+		if err != nil {
+			return err
+		}
+		decisions := resolveHeldWorkflowNodes(states)
+		for i := range states {
+			state := &states[i]
+			switch decisions[state.Node.Name] {
+			case workflowNodeRelease:
+				if _, err := tx.WorkflowNode.WithContext(ctx).
+					Where(tx.WorkflowNode.ID.Eq(state.Node.ID)).
+					Update(tx.WorkflowNode.Held, false); err != nil {
+					return err
+				}
+				// The waiting tolerance of a released node starts now, not at workflow submission.
+				if _, err := tx.Job.WithContext(ctx).
+					Where(tx.Job.JobName.Eq(state.Node.JobName), tx.Job.Status.Eq(string(model.Prequeue))).
+					Update(tx.Job.CreationTimestamp, now); err != nil {
+					return err
+				}
+				state.Node.Held = false
+				released = true
+			case workflowNodeSkip:
+				if err := skipWorkflowNodeTx(ctx, tx, state.Node); err != nil {
+					return err
+				}
+				state.Node.Held = false
+				state.Node.Skipped = true
+				state.JobStatus = model.Deleted
+			}
+		}
+
+		workflow, err := tx.Workflow.WithContext(ctx).Where(tx.Workflow.ID.Eq(node.WorkflowID)).First()
+		if err != nil {
+			return err
+		}
+		if isWorkflowFinished(workflow.Status) {
+			return nil
+		}
+		status := summarizeWorkflowStatus(states)
+		if status == workflow.Status {
+			return nil
+		}
+		_, err = tx.Workflow.WithContext(ctx).
+			Where(tx.Workflow.ID.Eq(workflow.ID)).
+			Update(tx.Workflow.Status, status)
+		return err
+	})
+	return released, err
+}
+
+func (s *WorkflowService) loadNodeStates(
+	ctx context.Context,
+	q *query.Query,
+	workflowID uint,
+) ([]WorkflowNodeState, error) {
+	nodes, err := q.WorkflowNode.WithContext(ctx).
+		Where(q.WorkflowNode.WorkflowID.Eq(workflowID)).
+		Order(q.WorkflowNode.ID).
+		Find()
+	if err != nil {
+		return nil, err
+	}
+	jobNames := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		jobNames = append(jobNames, node.JobName)
+	}
+	jobs, err := q.Job.WithContext(ctx).
+		Select(q.Job.JobName, q.Job.Status).
+		Where(q.Job.JobName.In(jobNames...)).
+		Find()
+	if err != nil {
+		return nil, err
+	}
+	statusByJob := make(map[string]batch.JobPhase, len(jobs))
+	for _, job := range jobs {
+		statusByJob[job.JobName] = job.Status
+	}
+
+	states := make([]WorkflowNodeState, 0, len(nodes))
+	for _, node := range nodes {
+		status, ok := statusByJob[node.JobName]
+		if !ok {
+			// The record is only missing if the user removed a finished job.
+			status = model.Deleted
+		}
+		states = append(states, WorkflowNodeState{Node: node, JobStatus: status})
+	}
+	return states, nil
+}
+
+func skipWorkflowNodeTx(ctx context.Context, tx *query.Query, node *model.WorkflowNode) error {
+	if _, err := tx.WorkflowNode.WithContext(ctx).
+		Where(tx.WorkflowNode.ID.Eq(node.ID)).
+		Updates(map[string]any{"held": false, "skipped": true}); err != nil {
+		return err
+	}
+	_, err := tx.Job.WithContext(ctx).
+		Where(tx.Job.JobName.Eq(node.JobName), tx.Job.Status.Eq(string(model.Prequeue))).
+		Update(tx.Job.Status, model.Deleted)
+	return err
+}
+
+type workflowNodeDecision int
+
+const (
+	workflowNodeWait workflowNodeDecision = iota
+	workflowNodeRelease
+	workflowNodeSkip
+)
+
+// resolveHeldWorkflowNodes decides the fate of every held node. Skipping a node makes
+// it a failed parent for its own children, so the pass repeats until nothing changes.
+func resolveHeldWorkflowNodes(states []WorkflowNodeState) map[string]workflowNodeDecision {
+	statusByNode := make(map[string]batch.JobPhase, len(states))
+	held := make(map[string]*model.WorkflowNode, len(states))
+	for _, state := range states {
+		statusByNode[state.Node.Name] = state.JobStatus
+		if state.Node.Held {
+			held[state.Node.Name] = state.Node
+		}
+	}
+
+	decisions := make(map[string]workflowNodeDecision, len(held))
+	for changed := true; changed; {
+		changed = false
+		for name, node := range held {
+			if _, decided := decisions[name]; decided {
+				continue
+			}
+			decision := decideWorkflowNode(node.DependsOn.Data(), statusByNode)
+			if isJobPhaseFinished(statusByNode[name]) {
+				// The held job was deleted by its owner before its parents finished.
+				decision = workflowNodeSkip
+			}
+			if decision == workflowNodeWait {
+				continue
+			}
+			decisions[name] = decision
+			if decision == workflowNodeSkip {
+				statusByNode[name] = model.Deleted
+				changed = true
+			}
+		}
+	}
+	return decisions
+}
+
+func decideWorkflowNode(deps []model.WorkflowDependency, statusByNode map[string]batch.JobPhase) workflowNodeDecision {
+	decision := workflowNodeRelease
+	for _, dep := range deps {
+		status := statusByNode[dep.Node]
+		if !isJobPhaseFinished(status) {
+			decision = workflowNodeWait
+			continue
+		}
+		if dep.Condition == model.WorkflowDependencyOnSuccess && status != batch.Completed {
+			return workflowNodeSkip
+		}
+	}
+	return decision
+}
+
+func summarizeWorkflowStatus(states []WorkflowNodeState) model.WorkflowStatus {
+	succeeded := true
+	for _, state := range states {
+		if state.Node.Held || !isJobPhaseFinished(state.JobStatus) {
+			return model.WorkflowStatusRunning
+		}
+		if state.JobStatus != batch.Completed {
+			succeeded = false
+		}
+	}
+	if succeeded {
+		return model.WorkflowStatusSucceeded
+	}
+	return model.WorkflowStatusFailed
+}
+
+func isJobPhaseFinished(status batch.JobPhase) bool {
+	return slices.Contains([]batch.JobPhase{
+		batch.Completed,
+		batch.Failed,
+		batch.Aborted,
+		batch.Terminated,
+		model.Deleted,
+		model.Freed,
+	}, status)
+}
+
+func isWorkflowFinished(status model.WorkflowStatus) bool {
+	return status != model.WorkflowStatusRunning
+}
+
+// NewWorkflowNode builds the node row for a job that belongs to a workflow.
+func NewWorkflowNode(spec WorkflowNodeSpec, jobName string) *model.WorkflowNode {
+	return &model.WorkflowNode{
+		Name:      spec.Name,
+		JobName:   jobName,
+		DependsOn: datatypes.NewJSONType(spec.DependsOn),
+		Held:      len(spec.DependsOn) > 0,
+	}
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"slices"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+)
+
+func TestSortWorkflowNodes(t *testing.T) {
+	t.Parallel()
+
+	onSuccess := func(node string) model.WorkflowDependency {
+		return model.WorkflowDependency{Node: node, Condition: model.WorkflowDependencyOnSuccess}
+	}
+	tests := []struct {
+		name    string
+		nodes   []WorkflowNodeSpec
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "chain keeps parents before children",
+			nodes: []WorkflowNodeSpec{
+				{Name: "eval", DependsOn: []model.WorkflowDependency{onSuccess("train")}},
+				{Name: "train", DependsOn: []model.WorkflowDependency{onSuccess("prep")}},
+				{Name: "prep"},
+			},
+			want: []string{"prep", "train", "eval"},
+		},
+		{
+			name: "rejects cycles",
+			nodes: []WorkflowNodeSpec{
+				{Name: "a", DependsOn: []model.WorkflowDependency{onSuccess("b")}},
+				{Name: "b", DependsOn: []model.WorkflowDependency{onSuccess("a")}},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "rejects unknown parents",
+			nodes:   []WorkflowNodeSpec{{Name: "a", DependsOn: []model.WorkflowDependency{onSuccess("missing")}}},
+			wantErr: true,
+		},
+		{
+			name:    "rejects duplicate names",
+			nodes:   []WorkflowNodeSpec{{Name: "a"}, {Name: "a"}},
+			wantErr: true,
+		},
+		{
+			name: "rejects unknown conditions",
+			nodes: []WorkflowNodeSpec{
+				{Name: "a"},
+				{Name: "b", DependsOn: []model.WorkflowDependency{{Node: "a", Condition: "sometimes"}}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := SortWorkflowNodes(tc.nodes)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("SortWorkflowNodes() = %v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SortWorkflowNodes() error = %v", err)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Fatalf("SortWorkflowNodes() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+//nolint:gocyclo // One scenario walks a diamond workflow from submission to completion.
+func TestWorkflowServiceReleaseDependents(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:workflow_release?mode=memory&cache=shared"), &gorm.Config{
+		DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	// CreateTable instead of AutoMigrate: AutoMigrate would also create users, whose
+	// "status" index name collides with the one on jobs in sqlite's global namespace.
+	if err := db.Migrator().CreateTable(&model.Job{}, &model.Workflow{}, &model.WorkflowNode{}); err != nil {
+		t.Fatalf("create tables: %v", err)
+	}
+	svc := NewWorkflowService(query.Use(db))
+	ctx := t.Context()
+
+	// prep -> train (on success) -> eval (on completion), prep -> report (on success)
+	specs := []WorkflowNodeSpec{
+		{Name: "prep"},
+		{Name: "train", DependsOn: []model.WorkflowDependency{
+			{Node: "prep", Condition: model.WorkflowDependencyOnSuccess},
+		}},
+		{Name: "eval", DependsOn: []model.WorkflowDependency{
+			{Node: "train", Condition: model.WorkflowDependencyOnCompletion},
+		}},
+		{Name: "report", DependsOn: []model.WorkflowDependency{
+			{Node: "eval", Condition: model.WorkflowDependencyOnSuccess},
+		}},
+	}
+	nodes := make([]*model.WorkflowNode, 0, len(specs))
+	held := make([]*model.Job, 0, len(specs))
+	for _, spec := range specs {
+		node := NewWorkflowNode(spec, "job-"+spec.Name)
+		nodes = append(nodes, node)
+		if node.Held {
+			held = append(held, &model.Job{Name: spec.Name, JobName: node.JobName, Status: model.Prequeue})
+		}
+	}
+	workflow := &model.Workflow{Name: "pipeline", UserID: 1, AccountID: 1}
+	if err := svc.CreateWorkflow(ctx, workflow, nodes, held); err != nil {
+		t.Fatalf("create workflow: %v", err)
+	}
+	if err := db.Create(&model.Job{Name: "prep", JobName: "job-prep", Status: batch.Running}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	setStatus := func(jobName string, status batch.JobPhase) {
+		t.Helper()
+		if err := db.Model(&model.Job{}).Where("job_name = ?", jobName).Update("status", status).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	nodeStatus := func() map[string]string {
+		t.Helper()
+		_, states, err := svc.GetWorkflow(ctx, workflow.ID, 1, 1)
+		if err != nil {
+			t.Fatalf("get workflow: %v", err)
+		}
+		result := make(map[string]string, len(states))
+		for _, state := range states {
+			switch {
+			case state.Node.Held:
+				result[state.Node.Name] = "held"
+			case state.Node.Skipped:
+				result[state.Node.Name] = "skipped"
+			default:
+				result[state.Node.Name] = string(state.JobStatus)
+			}
+		}
+		return result
+	}
+
+	released, err := svc.ReleaseDependents(ctx, "job-unrelated")
+	if err != nil || released {
+		t.Fatalf("unrelated job released=%v err=%v", released, err)
+	}
+
+	setStatus("job-prep", batch.Completed)
+	if released, err = svc.ReleaseDependents(ctx, "job-prep"); err != nil || !released {
+		t.Fatalf("prep completion released=%v err=%v, want released", released, err)
+	}
+	if got := nodeStatus(); got["train"] != string(model.Prequeue) || got["eval"] != "held" {
+		t.Fatalf("after prep = %v, want train released and eval held", got)
+	}
+
+	// A failed parent still releases an on-completion child.
+	setStatus("job-train", batch.Failed)
+	if released, err = svc.ReleaseDependents(ctx, "job-train"); err != nil || !released {
+		t.Fatalf("train failure released=%v err=%v, want released", released, err)
+	}
+	if got := nodeStatus(); got["eval"] != string(model.Prequeue) || got["report"] != "held" {
+		t.Fatalf("after train = %v, want eval released and report held", got)
+	}
+
+	// An on-success child of a failed parent is skipped and its prequeue record dropped.
+	setStatus("job-eval", batch.Failed)
+	if released, err = svc.ReleaseDependents(ctx, "job-eval"); err != nil || released {
+		t.Fatalf("eval failure released=%v err=%v, want nothing released", released, err)
+	}
+	if got := nodeStatus(); got["report"] != "skipped" {
+		t.Fatalf("after eval = %v, want report skipped", got)
+	}
+	var report model.Job
+	if err := db.Where("job_name = ?", "job-report").First(&report).Error; err != nil {
+		t.Fatal(err)
+	}
+	if report.Status != model.Deleted {
+		t.Fatalf("skipped job status = %s, want %s", report.Status, model.Deleted)
+	}
+
+	got, _, err := svc.GetWorkflow(ctx, workflow.ID, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != model.WorkflowStatusFailed {
+		t.Fatalf("workflow status = %s, want %s", got.Status, model.WorkflowStatusFailed)
+	}
+	if _, _, err := svc.GetWorkflow(ctx, workflow.ID, 2, 1); err == nil {
+		t.Fatal("workflow of another user should not be visible")
+	}
+}
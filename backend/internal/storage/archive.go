@@ -0,0 +1,299 @@
+package storage
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/internal/bizerr"
+	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/internal/util"
+)
+
+// DefaultArchiveMaxBytes caps the file data of one archive download.
+const DefaultArchiveMaxBytes int64 = 20 << 30
+
+var archiveMaxBytes = DefaultArchiveMaxBytes
+
+// SetArchiveMaxBytes changes the archive size cap; values below one keep the default.
+func SetArchiveMaxBytes(maxBytes int64) {
+	if maxBytes > 0 {
+		archiveMaxBytes = maxBytes
+	}
+}
+
+type archiveFormat string
+
+const (
+	archiveTarGz archiveFormat = "tar.gz"
+	archiveZip   archiveFormat = "zip"
+)
+
+var (
+	errArchiveFormat    = errors.New("format must be tar.gz or zip")
+	errArchiveNotFound  = errors.New("path does not exist")
+	errArchiveDuplicate = errors.New("selected paths must have distinct names")
+	errArchiveTooLarge  = errors.New("archive exceeds the size limit")
+	errNoReadPermission = errors.New("you have no permission to read this path")
+)
+
+func parseArchiveFormat(raw string) (archiveFormat, error) {
+	switch raw {
+	case "", "tar.gz", "tgz":
+		return archiveTarGz, nil
+	case "zip":
+		return archiveZip, nil
+	}
+	return "", errArchiveFormat
+}
+
+func (f archiveFormat) contentType() string {
+	if f == archiveZip {
+		return "application/zip"
+	}
+	return "application/gzip"
+}
+
+// archiveSource is a selected file or directory and the name it gets at the archive root.
+type archiveSource struct {
+	name  string
+	local string
+}
+
+// resolveArchiveSources checks that the caller may read every logical path.
+func resolveArchiveSources(c *gin.Context, token util.JWTMessage, paths []string) ([]archiveSource, error) {
+	sources := make([]archiveSource, 0, len(paths))
+	names := map[string]bool{}
+	for _, p := range paths {
+		if GetPermission(p, token, c) == model.NotAllowed {
+			return nil, fmt.Errorf("%s: %w", p, errNoReadPermission)
+		}
+		realPath, err := Redirect(c, p, token)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p, err)
+		}
+		local := localPath(realPath)
+		if _, err = os.Lstat(local); err != nil {
+			return nil, fmt.Errorf("%s: %w", p, errArchiveNotFound)
+		}
+		name := path.Base(cleanURLPath(p))
+		if names[name] {
+			return nil, fmt.Errorf("%s: %w", name, errArchiveDuplicate)
+		}
+		names[name] = true
+		sources = append(sources, archiveSource{name: name, local: local})
+	}
+	return sources, nil
+}
+
+// archiveEntryWriter abstracts the zip and tar writers.
+type archiveEntryWriter interface {
+	add(name string, info os.FileInfo) (io.Writer, error)
+	Close() error
+}
+
+type zipEntryWriter struct{ zw *zip.Writer }
+
+func (z *zipEntryWriter) add(name string, info os.FileInfo) (io.Writer, error) {
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return nil, err
+	}
+	header.Name = name
+	if info.IsDir() {
+		header.Name += "/"
+		header.Method = zip.Store
+	} else {
+		header.Method = zip.Deflate
+	}
+	return z.zw.CreateHeader(header)
+}
+
+func (z *zipEntryWriter) Close() error { return z.zw.Close() }
+
+type tarEntryWriter struct {
+	gz *gzip.Writer
+	tw *tar.Writer
+}
+
+func (t *tarEntryWriter) add(name string, info os.FileInfo) (io.Writer, error) {
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return nil, err
+	}
+	header.Name = name
+	if info.IsDir() {
+		header.Name += "/"
+	}
+	// Numeric owners of the storage server mean nothing on the client.
+	header.Uid, header.Gid, header.Uname, header.Gname = 0, 0, "", ""
+	if err = t.tw.WriteHeader(header); err != nil {
+		return nil, err
+	}
+	return t.tw, nil
+}
+
+func (t *tarEntryWriter) Close() error {
+	if err := t.tw.Close(); err != nil {
+		return err
+	}
+	return t.gz.Close()
+}
+
+func newArchiveEntryWriter(w io.Writer, format archiveFormat) archiveEntryWriter {
+	if format == archiveZip {
+		return &zipEntryWriter{zw: zip.NewWriter(w)}
+	}
+	gz := gzip.NewWriter(w)
+	return &tarEntryWriter{gz: gz, tw: tar.NewWriter(gz)}
+}
+
+// writeArchive streams the sources into w. Symlinks and special files are skipped, so the
+// archive never reaches outside the selected trees. Files are cut at the size they had when
+// they were reached, and the whole archive at maxBytes of file data.
+func writeArchive(w io.Writer, format archiveFormat, sources []archiveSource, maxBytes int64) error {
+	aw := newArchiveEntryWriter(w, format)
+	var total int64
+	for _, src := range sources {
+		err := filepath.WalkDir(src.local, func(p string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && !d.Type().IsRegular() {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(src.local, p)
+			if err != nil {
+				return err
+			}
+			name := path.Join(src.name, filepath.ToSlash(rel))
+			if !info.IsDir() {
+				if total += info.Size(); total > maxBytes {
+					return errArchiveTooLarge
+				}
+			}
+			entry, err := aw.add(name, info)
+			if err != nil || info.IsDir() {
+				return err
+			}
+			return copyArchiveFile(entry, p, info.Size())
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return aw.Close()
+}
+
+func copyArchiveFile(dst io.Writer, local string, size int64) error {
+	f, err := os.Open(local)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.CopyN(dst, f, size)
+	return err
+}
+
+type ArchiveReq struct {
+	Paths  []string `json:"paths" binding:"required,min=1"`
+	Format string   `json:"format"`
+	// Name is the download name without extension, used when several paths are selected.
+	Name string `json:"name"`
+}
+
+// DownloadArchive streams a file or directory as tar.gz (default) or zip.
+func DownloadArchive(c *gin.Context) {
+	jwttoken, err := CheckJWTToken(c)
+	if err != nil {
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+		return
+	}
+	p := c.Param("path")
+	serveArchive(c, jwttoken, []string{p}, c.Query("format"), path.Base(cleanURLPath(p)))
+}
+
+// DownloadSelectionArchive streams several selected paths as one archive.
+func DownloadSelectionArchive(c *gin.Context) {
+	jwttoken, err := CheckJWTToken(c)
+	if err != nil {
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+		return
+	}
+	var req ArchiveReq
+	if err = c.ShouldBindJSON(&req); err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.InvalidRequest.Wrap(err, err.Error()))
+		return
+	}
+	name := req.Name
+	if name == "" {
+		name = "crater-files"
+		if len(req.Paths) == 1 {
+			name = path.Base(cleanURLPath(req.Paths[0]))
+		}
+	}
+	serveArchive(c, jwttoken, req.Paths, req.Format, name)
+}
+
+func serveArchive(c *gin.Context, token util.JWTMessage, paths []string, rawFormat, name string) {
+	format, err := parseArchiveFormat(rawFormat)
+	if err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.ParameterError.Wrap(err, err.Error()))
+		return
+	}
+	sources, err := resolveArchiveSources(c, token, paths)
+	if errors.Is(err, errNoReadPermission) {
+		resputil.HTTPError(c, http.StatusUnauthorized, err.Error(), resputil.UserNotAllowed)
+		return
+	} else if errors.Is(err, errArchiveNotFound) {
+		resputil.HandleError(c, bizerr.NotFound.DataBaseNotFound.Wrap(err, err.Error()))
+		return
+	} else if err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.ParameterError.Wrap(err, err.Error()))
+		return
+	}
+	var size int64
+	for _, src := range sources {
+		usage, err := measureTree(src.local)
+		if err != nil {
+			resputil.HandleError(c, bizerr.Internal.FileSystemError.Wrap(err, "failed to measure the selected paths"))
+			return
+		}
+		size += usage.Bytes
+	}
+	if size > archiveMaxBytes {
+		resputil.HandleError(c, bizerr.PayloadTooLarge.PayloadTooLarge.New(
+			fmt.Sprintf("the selection holds %d bytes, archives are limited to %d bytes", size, archiveMaxBytes)))
+		return
+	}
+	c.Header("Content-Type", format.contentType())
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+"."+string(format)))
+	c.Header("X-Archive-Size", strconv.FormatInt(size, 10))
+	c.Status(http.StatusOK)
+	if err = writeArchive(c.Writer, format, sources, archiveMaxBytes); err != nil {
+		// The status is already sent; the unfinished archive fails to unpack on the client.
+		klog.Warningf("stream archive of %v: %v", paths, err)
+		_ = c.Error(err)
+	}
+}
+
+func RegisterArchive(webdavGroup *gin.RouterGroup) {
+	webdavGroup.GET("/archive/*path", DownloadArchive)
+	webdavGroup.POST("/archive", DownloadSelectionArchive)
+}
@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func archiveFixture(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	results := filepath.Join(root, "results")
+	for name, content := range map[string]string{"a.txt": "alpha", "sub/b.txt": "bravo"} {
+		p := filepath.Join(results, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(results, "empty"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("/etc/passwd", filepath.Join(results, "escape")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "notes.md"), []byte("notes"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func TestWriteArchiveTarGz(t *testing.T) {
+	t.Parallel()
+
+	root := archiveFixture(t)
+	sources := []archiveSource{
+		{name: "results", local: filepath.Join(root, "results")},
+		{name: "notes.md", local: filepath.Join(root, "notes.md")},
+	}
+	var buf bytes.Buffer
+	if err := writeArchive(&buf, archiveTarGz, sources, 1<<20); err != nil {
+		t.Fatalf("writeArchive: %v", err)
+	}
+	gz, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	got := map[string]string{}
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(tr)
+		got[header.Name] = string(data)
+	}
+	want := map[string]string{
+		"results/": "", "results/a.txt": "alpha", "results/empty/": "",
+		"results/sub/": "", "results/sub/b.txt": "bravo", "notes.md": "notes",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("entries = %v, want %v", got, want)
+	}
+	for name, content := range want {
+		if got[name] != content {
+			t.Fatalf("entry %s = %q, want %q (all: %v)", name, got[name], content, got)
+		}
+	}
+}
+
+func TestWriteArchiveZip(t *testing.T) {
+	t.Parallel()
+
+	root := archiveFixture(t)
+	var buf bytes.Buffer
+	if err := writeArchive(&buf, archiveZip, []archiveSource{{name: "results", local: filepath.Join(root, "results")}}, 1<<20); err != nil {
+		t.Fatalf("writeArchive: %v", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	sort.Strings(names)
+	if got := strings.Join(names, ","); got != "results/,results/a.txt,results/empty/,results/sub/,results/sub/b.txt" {
+		t.Fatalf("zip entries = %s", got)
+	}
+}
+
+func TestWriteArchiveSizeCap(t *testing.T) {
+	t.Parallel()
+
+	root := archiveFixture(t)
+	err := writeArchive(io.Discard, archiveTarGz, []archiveSource{{name: "results", local: filepath.Join(root, "results")}}, 7)
+	if !errors.Is(err, errArchiveTooLarge) {
+		t.Fatalf("writeArchive over the cap = %v", err)
+	}
+}
+
+func TestParseArchiveFormat(t *testing.T) {
+	t.Parallel()
+
+	for raw, want := range map[string]archiveFormat{"": archiveTarGz, "tgz": archiveTarGz, "tar.gz": archiveTarGz, "zip": archiveZip} {
+		if got, err := parseArchiveFormat(raw); err != nil || got != want {
+			t.Fatalf("parseArchiveFormat(%q) = %q, %v", raw, got, err)
+		}
+	}
+	if _, err := parseArchiveFormat("rar"); err == nil {
+		t.Fatalf("parseArchiveFormat accepted rar")
+	}
+}
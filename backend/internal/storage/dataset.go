@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -52,7 +53,10 @@ func MoveFile(c *gin.Context) {
 		resputil.Error(c, err.Error(), resputil.NotSpecified)
 	}
 	err = moveFiles(c.Request.Context(), realPath, realDst, false)
-	if err != nil {
+	if errors.Is(err, errStorageQuotaExceeded) {
+		resputil.HTTPError(c, http.StatusInsufficientStorage, err.Error(), resputil.NotSpecified)
+		return
+	} else if err != nil {
 		resputil.Error(c, err.Error(), resputil.NotSpecified)
 		return
 	}
@@ -183,11 +187,21 @@ func moveFiles(ctx context.Context, src, dst string, overwrite bool) error {
 		}
 	}
 
-	return fs.FileSystem.Rename(ctx, src, dst)
+	// The destination is known to be absent or removed here, so only the moved tree counts.
+	change := prepareQuotaChange("MOVE", src, dst)
+	if err := change.admit(ctx, 0); err != nil {
+		return err
+	}
+	if err := fs.FileSystem.Rename(ctx, src, dst); err != nil {
+		return err
+	}
+	change.commit(ctx)
+	return nil
 }
 
 func RegisterDataset(webdavGroup *gin.RouterGroup) {
 	webdavGroup.POST("/move/*path", MoveFile)
 	webdavGroup.POST("/datasets/:id/move", MoveDatasetOrModel)
 	webdavGroup.POST("/datasets/restore", RestoreDatasetOrModel)
+	webdavGroup.POST("/datasets/:id/versions", PublishDatasetVersion)
 }
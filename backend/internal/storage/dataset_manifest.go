@@ -0,0 +1,329 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gen/field"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"k8s.io/klog/v2"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+)
+
+// DefaultDatasetIndexInterval is how old a manifest may get before the dataset is indexed again.
+const DefaultDatasetIndexInterval = 24 * time.Hour
+
+const (
+	// datasetIndexPoll is how often the indexer looks for verification requests.
+	datasetIndexPoll = 30 * time.Second
+	// datasetIndexBatch bounds the routine reindexing per poll, so verification requests do not
+	// wait behind a full pass over every dataset.
+	datasetIndexBatch = 10
+)
+
+// partialDownloadSuffixes are left behind by interrupted downloads (huggingface_hub, browsers,
+// curl/wget wrappers and aria2).
+var partialDownloadSuffixes = []string{".incomplete", ".part", ".partial", ".crdownload", ".aria2"}
+
+// StartDatasetIndexer keeps the content manifests of datasets and models current and runs the
+// verifications requested through the main backend.
+func StartDatasetIndexer(interval time.Duration) {
+	checkfs()
+	if interval <= 0 {
+		interval = DefaultDatasetIndexInterval
+	}
+	ctx := context.Background()
+	for {
+		indexRequestedManifests(ctx)
+		indexStaleDatasets(ctx, interval)
+		time.Sleep(datasetIndexPoll)
+	}
+}
+
+// indexRequestedManifests handles the manifests that wait for an index or a verification.
+func indexRequestedManifests(ctx context.Context) {
+	m := query.DatasetManifest
+	manifests, err := m.WithContext(ctx).
+		Where(field.Or(m.VerifyRequested.Is(true), m.Status.Eq(string(model.DatasetIndexPending)))).
+		Order(m.UpdatedAt).Find()
+	if err != nil {
+		klog.Errorf("list requested dataset manifests: %v", err)
+		return
+	}
+	d := query.Dataset
+	for _, manifest := range manifests {
+		dataset, err := d.WithContext(ctx).Where(d.ID.Eq(manifest.DatasetID)).First()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if _, err = m.WithContext(ctx).Unscoped().Where(m.ID.Eq(manifest.ID)).Delete(); err != nil {
+				klog.Warningf("delete manifest of removed dataset %d: %v", manifest.DatasetID, err)
+			}
+			continue
+		} else if err != nil {
+			klog.Errorf("get dataset %d: %v", manifest.DatasetID, err)
+			continue
+		}
+		indexDataset(ctx, dataset, manifest)
+	}
+}
+
+// indexStaleDatasets indexes the datasets that have no manifest yet or an outdated one.
+func indexStaleDatasets(ctx context.Context, interval time.Duration) {
+	d := query.Dataset
+	m := query.DatasetManifest
+	datasets, err := d.WithContext(ctx).Select(d.ALL).
+		LeftJoin(m, m.DatasetID.EqCol(d.ID)).
+		Where(field.Or(m.ID.IsNull(), m.UpdatedAt.Lt(time.Now().Add(-interval)))).
+		Order(d.ID).Limit(datasetIndexBatch).Find()
+	if err != nil {
+		klog.Errorf("list datasets to index: %v", err)
+		return
+	}
+	for _, dataset := range datasets {
+		manifest, err := m.WithContext(ctx).Where(m.DatasetID.Eq(dataset.ID)).First()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			manifest = &model.DatasetManifest{
+				DatasetID: dataset.ID,
+				Status:    model.DatasetIndexPending,
+				Integrity: model.DatasetIntegrityUnverified,
+			}
+			// Another storage server replica may have created it meanwhile; it will index it.
+			result := query.GetDB().WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(manifest)
+			if result.Error != nil {
+				klog.Errorf("create manifest of dataset %d: %v", dataset.ID, result.Error)
+				continue
+			} else if result.RowsAffected == 0 {
+				continue
+			}
+		} else if err != nil {
+			klog.Errorf("get manifest of dataset %d: %v", dataset.ID, err)
+			continue
+		}
+		indexDataset(ctx, dataset, manifest)
+	}
+}
+
+// indexDataset refreshes the manifest of one dataset and, when requested, verifies the files
+// against the manifest recorded before. A verification rehashes every file; a routine index only
+// hashes the files whose size or modification time changed.
+func indexDataset(ctx context.Context, dataset *model.Dataset, manifest *model.DatasetManifest) {
+	m := query.DatasetManifest
+	var recorded []model.DatasetManifestEntry
+	if manifest.Status == model.DatasetIndexed && manifest.Path == dataset.URL {
+		recorded = manifest.Files.Data()
+	}
+	verify := manifest.VerifyRequested
+	now := time.Now()
+	updates := map[string]any{"path": dataset.URL}
+	if verify {
+		updates["verify_requested"] = false
+		updates["verified_at"] = now
+	}
+
+	files, err := indexDatasetFiles(ctx, localPath(dataset.URL), recorded, verify)
+	if err != nil {
+		klog.Errorf("index dataset %d: %v", dataset.ID, err)
+		updates["status"] = string(model.DatasetIndexFailed)
+		updates["message"] = err.Error()
+		if verify {
+			problem := model.DatasetManifestProblem{Problem: err.Error()}
+			if errors.Is(err, os.ErrNotExist) {
+				problem.Problem = "the dataset directory does not exist"
+			}
+			updates["integrity"] = string(model.DatasetIntegrityIncomplete)
+			updates["problems"] = datatypes.NewJSONType([]model.DatasetManifestProblem{problem})
+		}
+	} else {
+		integrity, problems := model.DatasetIntegrityUnverified, []model.DatasetManifestProblem(nil)
+		if verify {
+			integrity, problems = verifyDatasetFiles(recorded, files, manifest.ExpectedSizeBytes)
+			updates["integrity"] = string(integrity)
+			updates["problems"] = datatypes.NewJSONType(problems)
+		}
+		// A corrupted dataset keeps its previous manifest, so the damage is reported again by the
+		// next verification instead of becoming the new reference.
+		if integrity != model.DatasetIntegrityCorrupted {
+			var size int64
+			for _, entry := range files {
+				size += entry.Size
+			}
+			updates["files"] = datatypes.NewJSONType(files)
+			updates["file_count"] = int64(len(files))
+			updates["size_bytes"] = size
+			updates["digest"] = manifestDigest(files)
+			updates["indexed_at"] = now
+		}
+		updates["status"] = string(model.DatasetIndexed)
+		updates["message"] = ""
+	}
+	if _, err = m.WithContext(ctx).Where(m.ID.Eq(manifest.ID)).Updates(updates); err != nil {
+		klog.Errorf("save manifest of dataset %d: %v", dataset.ID, err)
+	}
+}
+
+// indexDatasetFiles lists the regular files below root with their size, modification time and
+// SHA-256. The hash of a recorded file with the same size and modification time is reused unless
+// rehash is set. Symbolic links and special files are skipped, like in snapshots.
+func indexDatasetFiles(
+	ctx context.Context, root string, recorded []model.DatasetManifestEntry, rehash bool,
+) ([]model.DatasetManifestEntry, error) {
+	known := make(map[string]model.DatasetManifestEntry, len(recorded))
+	for _, entry := range recorded {
+		known[entry.Path] = entry
+	}
+	files := []model.DatasetManifestEntry{}
+	err := filepath.WalkDir(root, func(current string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, current)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			rel = filepath.Base(root)
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		modTime := info.ModTime().UTC()
+		entry := model.DatasetManifestEntry{Path: filepath.ToSlash(rel), Size: info.Size(), ModTime: &modTime}
+		if prev, ok := known[entry.Path]; ok && !rehash && unchangedEntry(prev, entry) {
+			entry.SHA256 = prev.SHA256
+		} else if entry.SHA256, err = hashFile(current); err != nil {
+			return err
+		}
+		files = append(files, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+func unchangedEntry(recorded, current model.DatasetManifestEntry) bool {
+	return recorded.Size == current.Size && recorded.ModTime != nil && current.ModTime != nil &&
+		recorded.ModTime.Equal(*current.ModTime)
+}
+
+func hashFile(name string) (string, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err = io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// manifestDigest is a single checksum over the paths, sizes and hashes of a manifest. Two
+// datasets with the same digest hold the same files, whatever their modification times.
+func manifestDigest(files []model.DatasetManifestEntry) string {
+	hash := sha256.New()
+	for _, entry := range files {
+		_, _ = fmt.Fprintf(hash, "%s\x00%d\x00%s\n", entry.Path, entry.Size, entry.SHA256)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// verifyDatasetFiles compares freshly hashed files with the recorded manifest. Without a recorded
+// manifest, as right after a model download, only partial files and the expected size are checked.
+// The worst finding decides the result: corrupted, then incomplete, then modified.
+func verifyDatasetFiles(
+	recorded, current []model.DatasetManifestEntry, expectedSize int64,
+) (model.DatasetIntegrity, []model.DatasetManifestProblem) {
+	problems := []model.DatasetManifestProblem{}
+	corrupted, incomplete, modified := false, false, false
+	report := func(path, format string, args ...any) {
+		problems = append(problems, model.DatasetManifestProblem{Path: path, Problem: fmt.Sprintf(format, args...)})
+	}
+
+	known := make(map[string]model.DatasetManifestEntry, len(recorded))
+	for _, entry := range recorded {
+		known[entry.Path] = entry
+	}
+	var size int64
+	for _, entry := range current {
+		size += entry.Size
+		if isPartialDownload(entry.Path) {
+			incomplete = true
+			report(entry.Path, "partially downloaded file")
+		}
+		prev, ok := known[entry.Path]
+		if !ok {
+			if recorded != nil {
+				modified = true
+				report(entry.Path, "added since the last index")
+			}
+			continue
+		}
+		delete(known, entry.Path)
+		switch {
+		case prev.SHA256 == entry.SHA256:
+		case prev.ModTime != nil && entry.ModTime != nil && prev.ModTime.Equal(*entry.ModTime):
+			// Regular writes update the modification time; content that changes without one is
+			// silent corruption.
+			corrupted = true
+			if prev.Size != entry.Size {
+				report(entry.Path, "size changed from %d to %d bytes without a modification", prev.Size, entry.Size)
+			} else {
+				report(entry.Path, "checksum mismatch without a modification")
+			}
+		default:
+			modified = true
+			report(entry.Path, "modified since the last index")
+		}
+	}
+	for _, entry := range recorded {
+		if _, ok := known[entry.Path]; ok {
+			incomplete = true
+			report(entry.Path, "missing")
+		}
+	}
+	if expectedSize > 0 && size < expectedSize {
+		incomplete = true
+		report("", "holds %d bytes, %d expected", size, expectedSize)
+	}
+
+	switch {
+	case corrupted:
+		return model.DatasetIntegrityCorrupted, problems
+	case incomplete:
+		return model.DatasetIntegrityIncomplete, problems
+	case modified:
+		return model.DatasetIntegrityModified, problems
+	default:
+		return model.DatasetIntegrityOK, problems
+	}
+}
+
+func isPartialDownload(name string) bool {
+	for _, suffix := range partialDownloadSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/raids-lab/crater/dao/model"
+)
+
+func TestIndexDatasetFilesReusesUnchangedHashes(t *testing.T) {
+	src := writeDatasetFixture(t)
+	files, err := indexDatasetFiles(context.Background(), src, nil, false)
+	if err != nil {
+		t.Fatalf("index: %v", err)
+	}
+	if len(files) != 3 || files[1].Path != "train/a.bin" || files[1].ModTime == nil {
+		t.Fatalf("files = %+v, want README.md, train/a.bin and train/b.bin with mtimes", files)
+	}
+
+	// Fake a recorded hash: a routine index keeps it, a rehash replaces it.
+	recorded := append([]model.DatasetManifestEntry(nil), files...)
+	recorded[1].SHA256 = "recorded"
+	again, err := indexDatasetFiles(context.Background(), src, recorded, false)
+	if err != nil {
+		t.Fatalf("reindex: %v", err)
+	}
+	if again[1].SHA256 != "recorded" {
+		t.Fatalf("unchanged file was rehashed: %q", again[1].SHA256)
+	}
+	rehashed, err := indexDatasetFiles(context.Background(), src, recorded, true)
+	if err != nil {
+		t.Fatalf("rehash: %v", err)
+	}
+	if rehashed[1].SHA256 != files[1].SHA256 {
+		t.Fatalf("rehash kept %q, want %q", rehashed[1].SHA256, files[1].SHA256)
+	}
+	if manifestDigest(rehashed) != manifestDigest(files) {
+		t.Fatal("digest differs for the same content")
+	}
+}
+
+func TestVerifyDatasetFiles(t *testing.T) {
+	indexed := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	later := indexed.Add(time.Hour)
+	recorded := []model.DatasetManifestEntry{
+		{Path: "a.bin", Size: 4, SHA256: "aaaa", ModTime: &indexed},
+		{Path: "b.bin", Size: 2, SHA256: "bbbb", ModTime: &indexed},
+	}
+	tests := []struct {
+		name     string
+		recorded []model.DatasetManifestEntry
+		current  []model.DatasetManifestEntry
+		expected int64
+		want     model.DatasetIntegrity
+		problems int
+	}{
+		{name: "unchanged", recorded: recorded, current: recorded, want: model.DatasetIntegrityOK},
+		{
+			name:     "rewritten",
+			recorded: recorded,
+			current: []model.DatasetManifestEntry{
+				recorded[0], {Path: "b.bin", Size: 3, SHA256: "cccc", ModTime: &later},
+			},
+			want: model.DatasetIntegrityModified, problems: 1,
+		},
+		{
+			name:     "silently changed",
+			recorded: recorded,
+			current: []model.DatasetManifestEntry{
+				recorded[0], {Path: "b.bin", Size: 2, SHA256: "cccc", ModTime: &indexed},
+			},
+			want: model.DatasetIntegrityCorrupted, problems: 1,
+		},
+		{
+			name:     "missing file",
+			recorded: recorded,
+			current:  recorded[:1],
+			want:     model.DatasetIntegrityIncomplete, problems: 1,
+		},
+		{
+			name: "fresh download with a partial file",
+			current: []model.DatasetManifestEntry{
+				{Path: "model.safetensors.incomplete", Size: 10, SHA256: "dddd", ModTime: &indexed},
+			},
+			want: model.DatasetIntegrityIncomplete, problems: 1,
+		},
+		{
+			name:     "fresh download below the expected size",
+			current:  recorded,
+			expected: 100,
+			want:     model.DatasetIntegrityIncomplete, problems: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, problems := verifyDatasetFiles(tt.recorded, tt.current, tt.expected)
+			if got != tt.want || len(problems) != tt.problems {
+				t.Fatalf("verify = %s %+v, want %s with %d problems", got, problems, tt.want, tt.problems)
+			}
+		})
+	}
+}
+
+func TestIndexDatasetFilesMissingRoot(t *testing.T) {
+	_, err := indexDatasetFiles(context.Background(), filepath.Join(t.TempDir(), "gone"), nil, false)
+	if !os.IsNotExist(err) {
+		t.Fatalf("err = %v, want not exist", err)
+	}
+}
@@ -0,0 +1,382 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"k8s.io/klog/v2"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/bizerr"
+	"github.com/raids-lab/crater/internal/resputil"
+)
+
+const (
+	// datasetVersionRootDir keeps version snapshots outside every space, so they can only be
+	// reached by mounting dataset@version. Copied snapshots are charged to the space the
+	// dataset lives in; hardlinked ones share the blocks of the dataset and are free.
+	datasetVersionRootDir = ".crater-dataset-versions"
+	datasetVersionStaging = ".staging-"
+	readOnlyFilePerm      = 0o444
+	readOnlyDirPerm       = 0o555
+)
+
+var (
+	datasetVersionPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+	errDatasetVersionBusy = errors.New("the dataset version is being published")
+)
+
+// datasetVersionPath returns the real path the snapshot of a version is kept at.
+func datasetVersionPath(datasetID uint, version string) string {
+	return path.Join(datasetVersionRootDir, strconv.FormatUint(uint64(datasetID), 10), version)
+}
+
+// datasetVersionSpace returns the space a version snapshot is charged to: the space of the
+// dataset it was published from, unless the snapshot only hardlinks the dataset files.
+func datasetVersionSpace(version *model.DatasetVersion) (quotaSpace, bool) {
+	if version.Hardlink {
+		return quotaSpace{}, false
+	}
+	return chargedSpace(version.SourcePath)
+}
+
+// snapshotQuotaChange charges a snapshot of the given usage to the space of its dataset.
+func snapshotQuotaChange(version *model.DatasetVersion, usage spaceUsage) *quotaChange {
+	space, ok := datasetVersionSpace(version)
+	if !ok {
+		return nil
+	}
+	return &quotaChange{method: "MOVE", dst: space, dstOK: true, moved: usage}
+}
+
+// datasetVersionRoots lists the snapshots of ready versions charged to a space.
+func datasetVersionRoots(ctx context.Context, space quotaSpace) ([]string, error) {
+	v := query.DatasetVersion
+	versions, err := v.WithContext(ctx).Select(v.SourcePath, v.Path, v.Hardlink).
+		Where(v.Status.Eq(string(model.DatasetVersionReady)), v.Hardlink.Is(false)).Find()
+	if err != nil {
+		return nil, err
+	}
+	var roots []string
+	for _, version := range versions {
+		if charged, ok := datasetVersionSpace(version); ok && charged == space {
+			roots = append(roots, version.Path)
+		}
+	}
+	return roots, nil
+}
+
+func datasetVersionStagingPath(datasetID uint, version string) string {
+	return path.Join(path.Dir(datasetVersionPath(datasetID, version)), datasetVersionStaging+version)
+}
+
+// snapshotDataset copies, or hardlinks, the directories and regular files below src into dst
+// and returns the manifest of the files. Symbolic links and special files are skipped, like in
+// archives. Copied files and directories are made read-only; hardlinked files keep the mode of
+// the dataset because they share it.
+func snapshotDataset(ctx context.Context, src, dst string, hardlink bool) ([]model.DatasetManifestEntry, error) {
+	manifest := []model.DatasetManifestEntry{}
+	dirs := []string{}
+	err := filepath.WalkDir(src, func(current string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, current)
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && rel == "." {
+			rel = filepath.Base(src)
+		}
+		target := filepath.Join(dst, rel)
+		switch {
+		case d.IsDir():
+			dirs = append(dirs, target)
+			return os.MkdirAll(target, os.ModePerm)
+		case d.Type().IsRegular():
+			if err = os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+				return err
+			}
+			entry, err := snapshotFile(current, target, hardlink)
+			if err != nil {
+				return err
+			}
+			entry.Path = filepath.ToSlash(rel)
+			manifest = append(manifest, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !hardlink {
+		// Deepest directories first, so a parent is still writable while its children change.
+		for _, dir := range slices.Backward(dirs) {
+			if err = os.Chmod(dir, readOnlyDirPerm); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return manifest, nil
+}
+
+// snapshotFile places one file into the snapshot and hashes it. Hardlinks fall back to a copy
+// when the snapshot root is on another filesystem.
+func snapshotFile(src, dst string, hardlink bool) (model.DatasetManifestEntry, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return model.DatasetManifestEntry{}, err
+	}
+	defer in.Close()
+	hash := sha256.New()
+	var out io.Writer = hash
+	var file *os.File
+	if hardlink {
+		err = os.Link(src, dst)
+		if errors.Is(err, syscall.EXDEV) {
+			hardlink = false
+		} else if err != nil {
+			return model.DatasetManifestEntry{}, err
+		}
+	}
+	if !hardlink {
+		file, err = os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, readOnlyFilePerm)
+		if err != nil {
+			return model.DatasetManifestEntry{}, err
+		}
+		out = io.MultiWriter(hash, file)
+	}
+	size, err := io.Copy(out, in)
+	if file != nil {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		return model.DatasetManifestEntry{}, err
+	}
+	return model.DatasetManifestEntry{Size: size, SHA256: hex.EncodeToString(hash.Sum(nil))}, nil
+}
+
+// removeSnapshot deletes a snapshot tree, restoring write permission on read-only directories.
+func removeSnapshot(root string) error {
+	_ = filepath.WalkDir(root, func(current string, d os.DirEntry, err error) error {
+		if err == nil && d.IsDir() {
+			_ = os.Chmod(current, os.ModePerm)
+		}
+		return nil
+	})
+	return os.RemoveAll(root)
+}
+
+// buildDatasetVersion snapshots the dataset into a staging directory, moves it into place and
+// marks the version ready. A failed snapshot is removed and its error kept on the version.
+func buildDatasetVersion(ctx context.Context, version *model.DatasetVersion) {
+	v := query.DatasetVersion
+	staging := localPath(datasetVersionStagingPath(version.DatasetID, version.Version))
+	manifest, err := func() ([]model.DatasetManifestEntry, error) {
+		if err := removeSnapshot(staging); err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(filepath.Dir(staging), os.ModePerm); err != nil {
+			return nil, err
+		}
+		manifest, err := snapshotDataset(ctx, localPath(version.SourcePath), staging, version.Hardlink)
+		if err != nil {
+			return nil, err
+		}
+		return manifest, os.Rename(staging, localPath(version.Path))
+	}()
+	if err != nil {
+		klog.Errorf("publish version %s of dataset %d: %v", version.Version, version.DatasetID, err)
+		if rmErr := removeSnapshot(staging); rmErr != nil {
+			klog.Warningf("remove snapshot staging %s: %v", staging, rmErr)
+		}
+		if _, updErr := v.WithContext(ctx).Where(v.ID.Eq(version.ID)).UpdateSimple(
+			v.Status.Value(string(model.DatasetVersionFailed)), v.Message.Value(err.Error()),
+		); updErr != nil {
+			klog.Errorf("mark dataset version %d failed: %v", version.ID, updErr)
+		}
+		return
+	}
+	var size int64
+	for _, entry := range manifest {
+		size += entry.Size
+	}
+	if change := snapshotQuotaChange(version, spaceUsage{}); change != nil {
+		if change.moved, err = measureTree(localPath(version.Path)); err != nil {
+			klog.Warningf("measure snapshot of dataset version %d: %v", version.ID, err)
+		} else {
+			change.commit(ctx)
+		}
+	}
+	now := time.Now()
+	if _, err = v.WithContext(ctx).Where(v.ID.Eq(version.ID)).Updates(map[string]any{
+		"status":     string(model.DatasetVersionReady),
+		"message":    "",
+		"manifest":   datatypes.NewJSONType(manifest),
+		"file_count": int64(len(manifest)),
+		"size_bytes": size,
+		"ready_at":   now,
+	}); err != nil {
+		klog.Errorf("mark dataset version %d ready: %v", version.ID, err)
+	}
+}
+
+// failInterruptedDatasetVersions marks the versions that were being published when the storage
+// server stopped as failed, so they can be published again.
+func failInterruptedDatasetVersions(ctx context.Context) {
+	v := query.DatasetVersion
+	versions, err := v.WithContext(ctx).Where(v.Status.Eq(string(model.DatasetVersionCreating))).Find()
+	if err != nil {
+		klog.Errorf("list unfinished dataset versions: %v", err)
+		return
+	}
+	for _, version := range versions {
+		if err := removeSnapshot(localPath(datasetVersionStagingPath(version.DatasetID, version.Version))); err != nil {
+			klog.Warningf("remove snapshot staging of dataset version %d: %v", version.ID, err)
+		}
+		if _, err := v.WithContext(ctx).Where(v.ID.Eq(version.ID)).UpdateSimple(
+			v.Status.Value(string(model.DatasetVersionFailed)),
+			v.Message.Value("interrupted by a storage server restart"),
+		); err != nil {
+			klog.Errorf("mark dataset version %d failed: %v", version.ID, err)
+		}
+	}
+}
+
+type PublishDatasetVersionReq struct {
+	Version  string `json:"version" binding:"required"`
+	Describe string `json:"describe"`
+	// Hardlink links the snapshot to the dataset files instead of copying them. It saves space
+	// and quota, but a file that is modified in place rather than replaced also changes in the version.
+	Hardlink bool `json:"hardlink"`
+}
+
+type DatasetVersionResp struct {
+	ID        uint                       `json:"id"`
+	DatasetID uint                       `json:"datasetID"`
+	Version   string                     `json:"version"`
+	Describe  string                     `json:"describe"`
+	Hardlink  bool                       `json:"hardlink"`
+	FileCount int64                      `json:"fileCount"`
+	SizeBytes int64                      `json:"sizeBytes"`
+	Status    model.DatasetVersionStatus `json:"status"`
+	Message   string                     `json:"message,omitempty"`
+	CreatedAt time.Time                  `json:"createdAt"`
+}
+
+// PublishDatasetVersion freezes the current content of a dataset as a new version. The snapshot
+// is built in the background; the version can be mounted once its status is Ready.
+func PublishDatasetVersion(c *gin.Context) {
+	jwttoken, err := CheckJWTToken(c)
+	if err != nil {
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+		return
+	}
+	var uri DatasetRequest
+	if err = c.ShouldBindUri(&uri); err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.InvalidRequest.Wrap(err, err.Error()))
+		return
+	}
+	var req PublishDatasetVersionReq
+	if err = c.ShouldBindJSON(&req); err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.InvalidRequest.Wrap(err, err.Error()))
+		return
+	}
+	if !datasetVersionPattern.MatchString(req.Version) {
+		resputil.HandleError(c, bizerr.BadRequest.ParameterError.New(
+			"version must start with a letter or digit and contain at most 64 letters, digits, '.', '_' or '-'"))
+		return
+	}
+	d := query.Dataset
+	dataset, err := d.WithContext(c).Where(d.ID.Eq(uri.ID)).First()
+	if err != nil {
+		resputil.HandleError(c, bizerr.NotFound.DataBaseNotFound.Wrap(err, "dataset does not exist"))
+		return
+	}
+	if dataset.UserID != jwttoken.UserID && jwttoken.RolePlatform != model.RoleAdmin {
+		resputil.HandleError(c, bizerr.Forbidden.PermissionDenied.New("only the dataset owner can publish versions"))
+		return
+	}
+	if _, err = os.Stat(localPath(dataset.URL)); err != nil {
+		resputil.HandleError(c, bizerr.Internal.FileSystemError.Wrap(err, "dataset content is not available"))
+		return
+	}
+
+	version := &model.DatasetVersion{
+		DatasetID:  dataset.ID,
+		Version:    req.Version,
+		Describe:   req.Describe,
+		UserID:     jwttoken.UserID,
+		SourcePath: cleanURLPath(dataset.URL),
+		Path:       datasetVersionPath(dataset.ID, req.Version),
+		Hardlink:   req.Hardlink,
+		Status:     model.DatasetVersionCreating,
+	}
+	if change := snapshotQuotaChange(version, spaceUsage{}); change != nil {
+		if change.moved, err = measureTree(localPath(dataset.URL)); err != nil {
+			resputil.HandleError(c, bizerr.Internal.FileSystemError.Wrap(err, "failed to measure dataset content"))
+			return
+		}
+		if err = change.admit(c, 0); err != nil {
+			resputil.HTTPError(c, http.StatusInsufficientStorage, err.Error(), resputil.NotSpecified)
+			return
+		}
+	}
+	v := query.DatasetVersion
+	existing, err := v.WithContext(c).Unscoped().Where(v.DatasetID.Eq(dataset.ID), v.Version.Eq(req.Version)).First()
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		err = v.WithContext(c).Create(version)
+	case err == nil && existing.Status == model.DatasetVersionFailed && !existing.DeletedAt.Valid:
+		// A failed version left no snapshot behind, so its name can be published again.
+		version.ID, version.CreatedAt = existing.ID, existing.CreatedAt
+		result, updErr := v.WithContext(c).Where(v.ID.Eq(existing.ID), v.Status.Eq(string(model.DatasetVersionFailed))).
+			Select(v.Describe, v.UserID, v.SourcePath, v.Hardlink, v.Status, v.Message).Updates(version)
+		if err = updErr; err == nil && result.RowsAffected == 0 {
+			err = errDatasetVersionBusy
+		}
+	case err == nil:
+		resputil.HandleError(c, bizerr.Conflict.ResourceAlreadyExists.New(
+			fmt.Sprintf("version %s of dataset %d already exists", req.Version, dataset.ID)))
+		return
+	}
+	if errors.Is(err, errDatasetVersionBusy) {
+		resputil.HandleError(c, bizerr.Conflict.ResourceStatusError.Wrap(err, err.Error()))
+		return
+	} else if err != nil {
+		resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "failed to create dataset version"))
+		return
+	}
+	go buildDatasetVersion(context.Background(), version)
+	resputil.Success(c, DatasetVersionResp{
+		ID:        version.ID,
+		DatasetID: version.DatasetID,
+		Version:   version.Version,
+		Describe:  version.Describe,
+		Hardlink:  version.Hardlink,
+		Status:    version.Status,
+		CreatedAt: version.CreatedAt,
+	})
+}
@@ -0,0 +1,156 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/pkg/config"
+)
+
+func writeDatasetFixture(t *testing.T) string {
+	t.Helper()
+	src := filepath.Join(t.TempDir(), "mnist")
+	files := map[string]string{"train/a.bin": "aaaa", "train/b.bin": "bb", "README.md": "# mnist"}
+	for name, content := range files {
+		full := filepath.Join(src, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink("/etc/passwd", filepath.Join(src, "link")); err != nil {
+		t.Fatal(err)
+	}
+	return src
+}
+
+func TestSnapshotDatasetCopy(t *testing.T) {
+	src := writeDatasetFixture(t)
+	dst := filepath.Join(t.TempDir(), "v1")
+	t.Cleanup(func() { _ = removeSnapshot(dst) })
+
+	manifest, err := snapshotDataset(context.Background(), src, dst, false)
+	if err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+	sum := sha256.Sum256([]byte("aaaa"))
+	want := []model.DatasetManifestEntry{
+		{Path: "README.md", Size: 7},
+		{Path: "train/a.bin", Size: 4, SHA256: hex.EncodeToString(sum[:])},
+		{Path: "train/b.bin", Size: 2},
+	}
+	if len(manifest) != len(want) {
+		t.Fatalf("manifest = %+v", manifest)
+	}
+	for i, entry := range manifest {
+		if entry.Path != want[i].Path || entry.Size != want[i].Size {
+			t.Fatalf("manifest[%d] = %+v, want %+v", i, entry, want[i])
+		}
+	}
+	if manifest[1].SHA256 != want[1].SHA256 {
+		t.Fatalf("sha256 of train/a.bin = %s", manifest[1].SHA256)
+	}
+	if _, err := os.Lstat(filepath.Join(dst, "link")); !os.IsNotExist(err) {
+		t.Fatalf("symlink was copied: %v", err)
+	}
+
+	// Changing the dataset must not change the version.
+	if err := os.WriteFile(filepath.Join(src, "train", "a.bin"), []byte("changed"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dst, "train", "a.bin"))
+	if err != nil || string(data) != "aaaa" {
+		t.Fatalf("snapshot content = %q, %v", data, err)
+	}
+	for _, name := range []string{"train", "train/a.bin"} {
+		info, err := os.Stat(filepath.Join(dst, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm()&0o222 != 0 {
+			t.Fatalf("%s is writable: %v", name, info.Mode())
+		}
+	}
+}
+
+func TestSnapshotDatasetHardlink(t *testing.T) {
+	src := writeDatasetFixture(t)
+	dst := filepath.Join(filepath.Dir(src), "v1")
+
+	manifest, err := snapshotDataset(context.Background(), src, dst, true)
+	if err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+	if len(manifest) != 3 {
+		t.Fatalf("manifest = %+v", manifest)
+	}
+	srcInfo, err := os.Stat(filepath.Join(src, "README.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dstInfo, err := os.Stat(filepath.Join(dst, "README.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !os.SameFile(srcInfo, dstInfo) {
+		t.Fatal("README.md was copied instead of hardlinked")
+	}
+}
+
+func TestDatasetVersionPathIsNotCharged(t *testing.T) {
+	t.Parallel()
+
+	if got := datasetVersionStagingPath(7, "v1"); got != ".crater-dataset-versions/7/.staging-v1" {
+		t.Fatalf("datasetVersionStagingPath = %q", got)
+	}
+	got := datasetVersionPath(7, "v1")
+	if got != ".crater-dataset-versions/7/v1" {
+		t.Fatalf("datasetVersionPath = %q", got)
+	}
+	if space, ok := spaceOfPath(got, "users", "accounts"); ok {
+		t.Fatalf("version path charged to %+v", space)
+	}
+}
+
+func TestDatasetVersionPattern(t *testing.T) {
+	t.Parallel()
+
+	for version, valid := range map[string]bool{
+		"v1": true, "2024.06.01": true, "rc_1-final": true,
+		"": false, ".staging-v1": false, "../v1": false, "v/1": false, "-v1": false,
+	} {
+		if got := datasetVersionPattern.MatchString(version); got != valid {
+			t.Errorf("valid(%q) = %v, want %v", version, got, valid)
+		}
+	}
+}
+
+func TestDatasetVersionSpace(t *testing.T) {
+	t.Parallel()
+
+	prefix := config.GetConfig().Storage.Prefix
+	alice := quotaSpace{Kind: model.StorageSpaceUser, Space: "alice"}
+	copied := &model.DatasetVersion{SourcePath: prefix.User + "/alice/mnist"}
+	if space, ok := datasetVersionSpace(copied); !ok || space != alice {
+		t.Fatalf("copied snapshot charged to %+v, %v; want alice", space, ok)
+	}
+	snapshot := spaceUsage{Bytes: 13, Inodes: 5}
+	if got := snapshotQuotaChange(copied, snapshot).deltas(spaceUsage{}); len(got) != 1 || got[alice] != snapshot {
+		t.Fatalf("copied snapshot deltas = %+v", got)
+	}
+	linked := &model.DatasetVersion{SourcePath: prefix.User + "/alice/mnist", Hardlink: true}
+	if change := snapshotQuotaChange(linked, snapshot); change != nil {
+		t.Fatalf("hardlinked snapshot charged: %+v", change)
+	}
+	public := &model.DatasetVersion{SourcePath: prefix.Public + "/mnist"}
+	if change := snapshotQuotaChange(public, snapshot); change != nil {
+		t.Fatalf("public snapshot charged: %+v", change)
+	}
+}
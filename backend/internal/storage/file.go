@@ -192,14 +192,47 @@ func WebDav(c *gin.Context) {
 		resputil.Error(c, err.Error(), resputil.NotSpecified)
 		return
 	}
-	rwMethods := []string{"PROPPATCH", "MKCOL", "PUT", "DELETE"}
+	rwMethods := []string{"PROPPATCH", "MKCOL", "PUT", "DELETE", "MOVE"}
 	if permission == model.ReadOnly && containsString(rwMethods, c.Request.Method) {
 		resputil.HTTPError(c, http.StatusUnauthorized, "You have no permission to do this", resputil.NotSpecified)
 		return
 	}
+	var change *quotaChange
+	switch c.Request.Method {
+	case "DELETE":
+		if space, ok := chargedSpace(realPath); ok {
+			trashWebDAVDelete(c, realPath, space, jwttoken)
+			return
+		}
+	case "PUT", "MKCOL":
+		change = prepareQuotaChange(c.Request.Method, realPath, "")
+	case "MOVE":
+		dstPath, err := redirectDestination(c, jwttoken)
+		if errors.Is(err, errNoWritePermission) {
+			resputil.HTTPError(c, http.StatusUnauthorized, err.Error(), resputil.NotSpecified)
+			return
+		} else if err != nil {
+			resputil.HTTPError(c, http.StatusBadRequest, err.Error(), resputil.InvalidRequest)
+			return
+		}
+		change = prepareQuotaChange(c.Request.Method, realPath, dstPath)
+	}
+	if err := change.admit(c, c.Request.ContentLength); err != nil {
+		resputil.HTTPError(c, http.StatusInsufficientStorage, err.Error(), resputil.NotSpecified)
+		return
+	}
+	// WebDAV writes the target in place, so a body of unknown length cannot be capped safely.
+	if c.Request.ContentLength < 0 && change.bodyLimited() {
+		resputil.HTTPError(c, http.StatusLengthRequired, "uploads to a space with a storage quota need a Content-Length",
+			resputil.NotSpecified)
+		return
+	}
 	http.StripPrefix("/api/ss", fs)
 	c.Request.URL.Path = "/api/ss/" + realPath
 	fs.ServeHTTP(c.Writer, c.Request)
+	if c.Writer.Status() < http.StatusMultipleChoices {
+		change.commit(c)
+	}
 	// For collection or upload requests, enforce folder permission for the target path.
 	if c.Request.Method == "MKCOL" || c.Request.Method == "PUT" {
 		chmod(c, model.RWXFolderPerm)
@@ -225,12 +258,13 @@ func AlloweOption(c *gin.Context) {
 	origin := c.Request.Header.Get("Origin")
 	if origin != "" {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
-		c.Header("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE,MKCOL,PROPFIND,PROPPATCH,MOVE,COPY")
+		c.Header("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, PATCH, DELETE,MKCOL,PROPFIND,PROPPATCH,MOVE,COPY")
 		c.Header("Content-Type", "application/json; charset=utf-8 ")
 		c.Header("Access-Control-Allow-Credentials", "true")
 		c.Header("Access-Control-Allow-Headers", "Authorization, Content-Length,Token,session,Accept,"+
 			"Origin, Host, Connection, Accept-Encoding, Accept-Language,DNT, X-CustomHeader, X-Requested-With,"+
-			"Content-Type, Destination,X-Debug-Username")
+			"Content-Type, Destination,X-Debug-Username,Upload-Offset")
+		c.Header("Access-Control-Expose-Headers", "Upload-Offset, X-Archive-Size, Content-Disposition")
 	}
 }
 
@@ -483,7 +517,7 @@ func GetDatasetPermission(c *gin.Context, datasetID uint, token util.JWTMessage)
 	if dataset.UserID == token.UserID || token.RolePlatform == model.RoleAdmin {
 		return model.ReadWrite
 	}
-	accountDataset, err := ad.WithContext(c).Where(ad.DatasetID.Eq(datasetID)).Find()
+	accountDataset, err := ad.WithContext(c).Where(ad.DatasetID.Eq(datasetID), util.NotExpired(ad.ExpiresAt)).Find()
 	if err == nil && len(accountDataset) != 0 {
 		for i := range accountDataset {
 			if accountDataset[i].AccountID == model.DefaultAccountID || accountDataset[i].AccountID == token.AccountID {
@@ -491,7 +525,7 @@ func GetDatasetPermission(c *gin.Context, datasetID uint, token util.JWTMessage)
 			}
 		}
 	}
-	_, err = ud.WithContext(c).Where(ud.DatasetID.Eq(datasetID), ud.UserID.Eq(token.UserID)).First()
+	_, err = ud.WithContext(c).Where(ud.DatasetID.Eq(datasetID), ud.UserID.Eq(token.UserID), util.NotExpired(ud.ExpiresAt)).First()
 	if err == nil {
 		return model.ReadOnly
 	}
@@ -690,7 +724,12 @@ func DeleteFile(c *gin.Context) {
 		resputil.Error(c, err.Error(), resputil.NotSpecified)
 		return
 	}
-	err = fs.FileSystem.RemoveAll(c, realPath)
+	// Deletes in user and account spaces go to the trash; the public space is removed directly.
+	if space, ok := chargedSpace(realPath); ok {
+		_, err = moveToTrash(c, realPath, space, jwttoken.UserID)
+	} else {
+		err = fs.FileSystem.RemoveAll(c, realPath)
+	}
 	if err != nil {
 		resputil.Error(c, err.Error(), resputil.NotSpecified)
 		return
@@ -748,19 +787,26 @@ const defaultTime = 60
 
 func StartCheckSpace() {
 	checkfs()
+	failInterruptedDatasetVersions(context.Background())
 	for {
 		checkSpace()
+		defaultUploadStore().removeStale(time.Now().Add(-UploadRetention))
+		purgeExpiredTrash(context.Background())
+		removeStaleS3Staging(time.Now().Add(-s3StagingTempTTL))
+		removeStaleS3Multipart(time.Now().Add(-UploadRetention))
 		time.Sleep(time.Second * defaultTime)
 	}
 }
 
 type UserSpaceResp struct {
-	Username string `json:"username"`
-	Space    string `json:"space"`
+	Username string      `json:"username"`
+	Space    string      `json:"space"`
+	Usage    *SpaceUsage `json:"usage,omitempty"`
 }
 type AccountSpaceResp struct {
-	Accountname string `json:"queuename"`
-	Space       string `json:"space"`
+	Accountname string      `json:"queuename"`
+	Space       string      `json:"space"`
+	Usage       *SpaceUsage `json:"usage,omitempty"`
 }
 
 func GetUserSpace(c *gin.Context) {
@@ -779,11 +825,19 @@ func GetUserSpace(c *gin.Context) {
 		resputil.Error(c, err.Error(), resputil.NotSpecified)
 		return
 	}
+	quotas, err := loadSpaceQuotas(c, model.StorageSpaceUser)
+	if err != nil {
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+		return
+	}
 	var userSpaceResp []UserSpaceResp
 	for i := range user {
 		var userspace UserSpaceResp
 		userspace.Space = user[i].Space
 		userspace.Username = user[i].Name
+		if space := strings.Trim(user[i].Space, "/"); space != "" {
+			userspace.Usage = newSpaceUsage(quotaSpace{Kind: model.StorageSpaceUser, Space: space}, quotas[space])
+		}
 		userSpaceResp = append(userSpaceResp, userspace)
 	}
 	resputil.Success(c, userSpaceResp)
@@ -805,11 +859,19 @@ func GetAccountSpace(c *gin.Context) {
 		resputil.Error(c, "has no permission to get queue", resputil.UserNotAllowed)
 		return
 	}
+	quotas, err := loadSpaceQuotas(c, model.StorageSpaceAccount)
+	if err != nil {
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+		return
+	}
 	var accountSpaceResp []AccountSpaceResp
 	for i := range account {
 		var accountspace AccountSpaceResp
 		accountspace.Accountname = account[i].Name
 		accountspace.Space = account[i].Space
+		if space := strings.Trim(account[i].Space, "/"); space != "" {
+			accountspace.Usage = newSpaceUsage(quotaSpace{Kind: model.StorageSpaceAccount, Space: space}, quotas[space])
+		}
 		accountSpaceResp = append(accountSpaceResp, accountspace)
 	}
 	resputil.Success(c, accountSpaceResp)
@@ -877,6 +939,8 @@ func RegisterFile(webdavGroup *gin.RouterGroup) {
 	webdavGroup.DELETE("/delete/*path", DeleteFile)
 	webdavGroup.GET("/userspace", GetUserSpace)
 	webdavGroup.GET("/queuespace", GetAccountSpace)
+	webdavGroup.GET("/usage", GetMyStorageUsage)
+	webdavGroup.POST("/admin/quota", SetStorageQuota)
 	webdavGroup.GET("/dataset/:id", GetDatasetFiles)
 	webdavGroup.GET("/dataset/:id/*path", GetDatasetFiles)
 }
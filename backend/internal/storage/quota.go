@@ -0,0 +1,592 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"k8s.io/klog/v2"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/bizerr"
+	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/internal/util"
+	"github.com/raids-lab/crater/pkg/config"
+)
+
+// DefaultQuotaScanInterval is used when the storage server is started without an explicit interval.
+const DefaultQuotaScanInterval = time.Hour
+
+var (
+	errStorageQuotaExceeded = errors.New("storage quota exceeded")
+	errInvalidDestination   = errors.New("invalid destination")
+	errNoWritePermission    = errors.New("you have no permission to write the destination")
+)
+
+// quotaSpace identifies the user or account space a storage path is charged to.
+type quotaSpace struct {
+	Kind  model.StorageSpaceKind
+	Space string
+}
+
+type spaceUsage struct {
+	Bytes  int64
+	Inodes int64
+}
+
+func (u spaceUsage) sub(o spaceUsage) spaceUsage {
+	return spaceUsage{Bytes: u.Bytes - o.Bytes, Inodes: u.Inodes - o.Inodes}
+}
+
+// spaceOfPath maps a real storage path to the space it is charged to. The public space,
+// the prefixes and the space roots themselves are not charged.
+func spaceOfPath(realPath, userPrefix, accountPrefix string) (quotaSpace, bool) {
+	cleaned := cleanURLPath(realPath)
+	roots := []struct {
+		kind   model.StorageSpaceKind
+		prefix string
+	}{
+		{kind: model.StorageSpaceUser, prefix: userPrefix},
+		{kind: model.StorageSpaceAccount, prefix: accountPrefix},
+	}
+	for _, root := range roots {
+		prefix := cleanURLPath(root.prefix)
+		if prefix == "" {
+			continue
+		}
+		rest, found := strings.CutPrefix(cleaned, prefix+"/")
+		if !found {
+			continue
+		}
+		space, below, _ := strings.Cut(rest, "/")
+		if space == "" || below == "" {
+			return quotaSpace{}, false
+		}
+		return quotaSpace{Kind: root.kind, Space: space}, true
+	}
+	return quotaSpace{}, false
+}
+
+func chargedSpace(realPath string) (quotaSpace, bool) {
+	prefix := config.GetConfig().Storage.Prefix
+	return spaceOfPath(realPath, prefix.User, prefix.Account)
+}
+
+// spaceRootPath returns the real storage path of a space root.
+func spaceRootPath(space quotaSpace) string {
+	prefix := config.GetConfig().Storage.Prefix.User
+	if space.Kind == model.StorageSpaceAccount {
+		prefix = config.GetConfig().Storage.Prefix.Account
+	}
+	return prefix + "/" + space.Space
+}
+
+func localPath(realPath string) string {
+	return filepath.Join(storageRootDir, filepath.FromSlash(cleanURLPath(realPath)))
+}
+
+// measureTree counts the regular file bytes and the entries of a tree, the root included.
+// A missing root measures as empty.
+func measureTree(root string) (spaceUsage, error) {
+	var usage spaceUsage
+	err := filepath.WalkDir(root, func(_ string, d os.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		usage.Inodes++
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			usage.Bytes += info.Size()
+		}
+		return nil
+	})
+	return usage, err
+}
+
+// quotaChange captures the usage a write request is about to move, so that it can be
+// checked against the quota first and applied once the request has succeeded.
+type quotaChange struct {
+	method string
+	// src is the request target; for MOVE dst is the destination.
+	src, dst     quotaSpace
+	srcOK, dstOK bool
+	srcLocal     string
+	// moved is the tree removed by DELETE or MOVE; replaced is what PUT or MOVE overwrites.
+	moved, replaced spaceUsage
+	// reserved is room a PUT must leave for unfinished uploads into the same space.
+	reserved spaceUsage
+	// room is the largest PUT body the byte quotas still take, nil when no limit applies.
+	room *int64
+}
+
+// prepareQuotaChange measures the affected paths before the request runs. It returns nil when
+// no charged space is involved or the paths cannot be measured; the scanner catches up then.
+func prepareQuotaChange(method, srcPath, dstPath string) *quotaChange {
+	change := &quotaChange{method: method, srcLocal: localPath(srcPath)}
+	change.src, change.srcOK = chargedSpace(srcPath)
+	if method == "MOVE" {
+		change.dst, change.dstOK = chargedSpace(dstPath)
+	}
+	if !change.srcOK && !change.dstOK {
+		return nil
+	}
+	var err error
+	switch method {
+	case http.MethodPut:
+		change.replaced, err = measureTree(change.srcLocal)
+	case http.MethodDelete:
+		change.moved, err = measureTree(change.srcLocal)
+	case "MOVE":
+		if change.moved, err = measureTree(change.srcLocal); err == nil {
+			change.replaced, err = measureTree(localPath(dstPath))
+		}
+	}
+	if err != nil {
+		klog.Warningf("skip quota tracking for %s %s: %v", method, srcPath, err)
+		return nil
+	}
+	return change
+}
+
+// deltas returns the usage change per space, given what a PUT left behind.
+func (q *quotaChange) deltas(written spaceUsage) map[quotaSpace]spaceUsage {
+	result := map[quotaSpace]spaceUsage{}
+	add := func(space quotaSpace, ok bool, delta spaceUsage) {
+		if !ok {
+			return
+		}
+		cur := result[space]
+		result[space] = spaceUsage{Bytes: cur.Bytes + delta.Bytes, Inodes: cur.Inodes + delta.Inodes}
+	}
+	switch q.method {
+	case http.MethodPut:
+		add(q.src, q.srcOK, written.sub(q.replaced))
+	case "MKCOL":
+		add(q.src, q.srcOK, spaceUsage{Inodes: 1})
+	case http.MethodDelete:
+		add(q.src, q.srcOK, spaceUsage{}.sub(q.moved))
+	case "MOVE":
+		add(q.src, q.srcOK, spaceUsage{}.sub(q.moved))
+		add(q.dst, q.dstOK, q.moved.sub(q.replaced))
+	}
+	for space, delta := range result {
+		if delta == (spaceUsage{}) {
+			delete(result, space)
+		}
+	}
+	return result
+}
+
+// admit rejects the request when it would push any space beyond its quota. For a PUT the
+// request body length is taken as the new file size; an unknown length only fits a space
+// that still has room for one more byte, and the body must then be capped with limitBody.
+func (q *quotaChange) admit(ctx context.Context, contentLength int64) error {
+	if q == nil {
+		return nil
+	}
+	written := spaceUsage{Bytes: contentLength, Inodes: 1}
+	if contentLength < 0 {
+		written.Bytes = q.replaced.Bytes + 1
+	}
+	written.Bytes += q.reserved.Bytes
+	written.Inodes += q.reserved.Inodes
+	for space, delta := range q.deltas(written) {
+		quota, err := findQuota(ctx, space)
+		if err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				klog.Warningf("load storage quota of %s space %s: %v", space.Kind, space.Space, err)
+			}
+			continue
+		}
+		if !quota.Admits(delta.Bytes, delta.Inodes) {
+			return fmt.Errorf("%w: %s space %s", errStorageQuotaExceeded, space.Kind, space.Space)
+		}
+		if q.method == http.MethodPut && quota.MaxBytes != nil {
+			room := *quota.MaxBytes - quota.UsedBytes + q.replaced.Bytes - q.reserved.Bytes
+			if q.room == nil || room < *q.room {
+				q.room = &room
+			}
+		}
+	}
+	return nil
+}
+
+// bodyLimited reports whether a byte quota bounds the body of an admitted PUT.
+func (q *quotaChange) bodyLimited() bool {
+	return q != nil && q.room != nil
+}
+
+// limitBody caps a PUT body of unknown length at the room admit found in the byte quotas.
+func (q *quotaChange) limitBody(r io.Reader) io.Reader {
+	if !q.bodyLimited() {
+		return r
+	}
+	return &quotaLimitedReader{r: r, remaining: *q.room}
+}
+
+// quotaLimitedReader fails with errStorageQuotaExceeded once the body grows beyond remaining.
+type quotaLimitedReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (l *quotaLimitedReader) Read(p []byte) (int, error) {
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.r.Read(p)
+	if int64(n) > l.remaining {
+		l.remaining = -1
+		return 0, errStorageQuotaExceeded
+	}
+	l.remaining -= int64(n)
+	return n, err
+}
+
+// commit applies the change after the request has succeeded.
+func (q *quotaChange) commit(ctx context.Context) {
+	if q == nil {
+		return
+	}
+	var written spaceUsage
+	if q.method == http.MethodPut {
+		var err error
+		if written, err = measureTree(q.srcLocal); err != nil {
+			klog.Warningf("measure %s after upload: %v", q.srcLocal, err)
+			return
+		}
+	}
+	for space, delta := range q.deltas(written) {
+		if err := applyUsageDelta(ctx, space, delta); err != nil {
+			klog.Warningf("update storage usage of %s space %s: %v", space.Kind, space.Space, err)
+		}
+	}
+}
+
+func findQuota(ctx context.Context, space quotaSpace) (*model.StorageQuota, error) {
+	sq := query.StorageQuota
+	return sq.WithContext(ctx).Where(sq.Kind.Eq(string(space.Kind)), sq.Space.Eq(space.Space)).First()
+}
+
+// applyUsageDelta only touches existing rows; spaces without one are picked up by the scanner.
+func applyUsageDelta(ctx context.Context, space quotaSpace, delta spaceUsage) error {
+	sq := query.StorageQuota
+	_, err := sq.WithContext(ctx).
+		Where(sq.Kind.Eq(string(space.Kind)), sq.Space.Eq(space.Space)).
+		UpdateSimple(sq.UsedBytes.Add(delta.Bytes), sq.UsedInodes.Add(delta.Inodes))
+	return err
+}
+
+// redirectDestination maps the Destination header of a MOVE to the real storage path after
+// checking that the caller may write there, and rewrites the header for the WebDAV handler.
+func redirectDestination(c *gin.Context, token util.JWTMessage) (string, error) {
+	dst, err := url.Parse(c.Request.Header.Get("Destination"))
+	if err != nil {
+		return "", errInvalidDestination
+	}
+	param, found := strings.CutPrefix(dst.Path, "/api/ss")
+	if !found {
+		return "", errInvalidDestination
+	}
+	if GetPermission(param, token, c) != model.ReadWrite {
+		return "", errNoWritePermission
+	}
+	realPath, err := Redirect(c, param, token)
+	if err != nil {
+		return "", err
+	}
+	dst.Path = "/api/ss/" + realPath
+	dst.RawPath = ""
+	c.Request.Header.Set("Destination", dst.String())
+	return realPath, nil
+}
+
+// StartQuotaScanner periodically recomputes the usage of every user and account space,
+// correcting drift of the incremental tracking and writes made outside the storage server.
+func StartQuotaScanner(interval time.Duration) {
+	checkfs()
+	if interval <= 0 {
+		interval = DefaultQuotaScanInterval
+	}
+	for {
+		reconcileQuotas(context.Background())
+		time.Sleep(interval)
+	}
+}
+
+func reconcileQuotas(ctx context.Context) {
+	u := query.User
+	users, err := u.WithContext(ctx).Where(u.Space.Neq("")).Find()
+	if err != nil {
+		klog.Errorf("list user spaces for quota scan: %v", err)
+		return
+	}
+	for _, user := range users {
+		space := quotaSpace{Kind: model.StorageSpaceUser, Space: strings.Trim(user.Space, "/")}
+		if err := reconcileSpace(ctx, space, user.ID); err != nil {
+			klog.Warningf("scan user space %s: %v", space.Space, err)
+		}
+	}
+	a := query.Account
+	accounts, err := a.WithContext(ctx).Where(a.Space.Neq("")).Find()
+	if err != nil {
+		klog.Errorf("list account spaces for quota scan: %v", err)
+		return
+	}
+	for _, account := range accounts {
+		space := quotaSpace{Kind: model.StorageSpaceAccount, Space: strings.Trim(account.Space, "/")}
+		if err := reconcileSpace(ctx, space, account.ID); err != nil {
+			klog.Warningf("scan account space %s: %v", space.Space, err)
+		}
+	}
+}
+
+// reconcileSpace replaces the tracked usage of one space with a fresh measurement.
+func reconcileSpace(ctx context.Context, space quotaSpace, ownerID uint) error {
+	if space.Space == "" {
+		return nil
+	}
+	var usage spaceUsage
+	// The trashed items of the space are charged like its files; neither root is.
+	for _, root := range []string{spaceRootPath(space), spaceTrashPath(space)} {
+		tree, err := measureTree(localPath(root))
+		if err != nil {
+			return err
+		}
+		if tree.Inodes > 0 {
+			tree.Inodes--
+		}
+		usage.Bytes += tree.Bytes
+		usage.Inodes += tree.Inodes
+	}
+	// Copied dataset version snapshots are charged to the space of their dataset, roots included.
+	snapshots, err := datasetVersionRoots(ctx, space)
+	if err != nil {
+		return err
+	}
+	for _, root := range snapshots {
+		tree, err := measureTree(localPath(root))
+		if err != nil {
+			return err
+		}
+		usage.Bytes += tree.Bytes
+		usage.Inodes += tree.Inodes
+	}
+	sq := query.StorageQuota
+	row, err := sq.WithContext(ctx).
+		Where(sq.Kind.Eq(string(space.Kind)), sq.Space.Eq(space.Space)).
+		Attrs(sq.OwnerID.Value(ownerID)).
+		FirstOrCreate()
+	if err != nil {
+		return err
+	}
+	_, err = sq.WithContext(ctx).Where(sq.ID.Eq(row.ID)).UpdateSimple(
+		sq.OwnerID.Value(ownerID),
+		sq.UsedBytes.Value(usage.Bytes),
+		sq.UsedInodes.Value(usage.Inodes),
+		sq.ScannedAt.Value(time.Now()),
+	)
+	return err
+}
+
+// SpaceUsage is the tracked usage and the limits of one user or account space.
+// Limits are omitted when the space is unlimited.
+type SpaceUsage struct {
+	Kind       model.StorageSpaceKind `json:"kind"`
+	Space      string                 `json:"space"`
+	UsedBytes  int64                  `json:"usedBytes"`
+	UsedInodes int64                  `json:"usedInodes"`
+	MaxBytes   *int64                 `json:"maxBytes,omitempty"`
+	MaxInodes  *int64                 `json:"maxInodes,omitempty"`
+	ScannedAt  *time.Time             `json:"scannedAt,omitempty"`
+}
+
+func newSpaceUsage(space quotaSpace, quota *model.StorageQuota) *SpaceUsage {
+	usage := &SpaceUsage{Kind: space.Kind, Space: space.Space}
+	if quota != nil {
+		usage.UsedBytes = quota.UsedBytes
+		usage.UsedInodes = quota.UsedInodes
+		usage.MaxBytes = quota.MaxBytes
+		usage.MaxInodes = quota.MaxInodes
+		usage.ScannedAt = quota.ScannedAt
+	}
+	return usage
+}
+
+// loadSpaceQuotas returns the quota row of every tracked space of one kind, keyed by space name.
+func loadSpaceQuotas(ctx context.Context, kind model.StorageSpaceKind) (map[string]*model.StorageQuota, error) {
+	sq := query.StorageQuota
+	rows, err := sq.WithContext(ctx).Where(sq.Kind.Eq(string(kind))).Find()
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]*model.StorageQuota, len(rows))
+	for _, row := range rows {
+		result[row.Space] = row
+	}
+	return result, nil
+}
+
+func lookupSpaceUsage(ctx context.Context, space quotaSpace) (*SpaceUsage, error) {
+	quota, err := findQuota(ctx, space)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	return newSpaceUsage(space, quota), nil
+}
+
+// callerSpaces returns the caller's user space and, outside the default account, the space
+// of the current account.
+func callerSpaces(ctx context.Context, token util.JWTMessage) ([]quotaSpace, error) {
+	u := query.User
+	user, err := u.WithContext(ctx).Where(u.ID.Eq(token.UserID)).First()
+	if err != nil {
+		return nil, err
+	}
+	var spaces []quotaSpace
+	if user.Space != "" {
+		spaces = append(spaces, quotaSpace{Kind: model.StorageSpaceUser, Space: strings.Trim(user.Space, "/")})
+	}
+	if token.AccountID != 0 && token.AccountID != model.DefaultAccountID {
+		a := query.Account
+		account, err := a.WithContext(ctx).Where(a.ID.Eq(token.AccountID)).First()
+		if err == nil && account.Space != "" {
+			spaces = append(spaces, quotaSpace{Kind: model.StorageSpaceAccount, Space: strings.Trim(account.Space, "/")})
+		}
+	}
+	return spaces, nil
+}
+
+// GetMyStorageUsage returns the usage of the caller's user space and current account space.
+func GetMyStorageUsage(c *gin.Context) {
+	jwttoken, err := CheckJWTToken(c)
+	if err != nil {
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+		return
+	}
+	spaces, err := callerSpaces(c, jwttoken)
+	if err != nil {
+		resputil.HandleError(c, bizerr.NotFound.DataBaseNotFound.Wrap(err, "user does not exist"))
+		return
+	}
+	resp := make([]*SpaceUsage, 0, len(spaces))
+	for _, space := range spaces {
+		usage, err := lookupSpaceUsage(c, space)
+		if err != nil {
+			resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "failed to load storage usage"))
+			return
+		}
+		resp = append(resp, usage)
+	}
+	resputil.Success(c, resp)
+}
+
+// SetStorageQuotaReq sets the limits of one space, addressed by user or account name.
+// A nil limit is left unchanged, zero or a negative value removes it.
+type SetStorageQuotaReq struct {
+	Kind      model.StorageSpaceKind `json:"kind" binding:"required,oneof=user account"`
+	Name      string                 `json:"name" binding:"required"`
+	MaxBytes  *int64                 `json:"maxBytes"`
+	MaxInodes *int64                 `json:"maxInodes"`
+}
+
+// SetStorageQuota lets platform administrators change the limits of a user or account space.
+func SetStorageQuota(c *gin.Context) {
+	jwttoken, err := CheckJWTToken(c)
+	if err != nil {
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+		return
+	}
+	if jwttoken.RolePlatform != model.RoleAdmin {
+		resputil.HTTPError(c, http.StatusUnauthorized, "Your RolePlatform is not RoleAdmin", resputil.UserNotAllowed)
+		return
+	}
+	var req SetStorageQuotaReq
+	if err = c.ShouldBindJSON(&req); err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.InvalidRequest.Wrap(err, err.Error()))
+		return
+	}
+	space, ownerID, err := resolveQuotaSpace(c, req.Kind, req.Name)
+	if err != nil {
+		resputil.HandleError(c, bizerr.NotFound.DataBaseNotFound.Wrap(err, err.Error()))
+		return
+	}
+
+	sq := query.StorageQuota
+	row, err := sq.WithContext(c).
+		Where(sq.Kind.Eq(string(space.Kind)), sq.Space.Eq(space.Space)).
+		Attrs(sq.OwnerID.Value(ownerID)).
+		FirstOrCreate()
+	if err != nil {
+		resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "failed to load storage quota"))
+		return
+	}
+	updates := map[string]any{}
+	if req.MaxBytes != nil {
+		updates[sq.MaxBytes.ColumnName().String()] = positiveOrNil(*req.MaxBytes)
+	}
+	if req.MaxInodes != nil {
+		updates[sq.MaxInodes.ColumnName().String()] = positiveOrNil(*req.MaxInodes)
+	}
+	if len(updates) > 0 {
+		if _, err = sq.WithContext(c).Where(sq.ID.Eq(row.ID)).Updates(updates); err != nil {
+			resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "failed to update storage quota"))
+			return
+		}
+	}
+	if row.ScannedAt == nil {
+		// A space without usage yet would admit everything until the next scan.
+		checkfs()
+		go func() {
+			if err := reconcileSpace(context.Background(), space, ownerID); err != nil {
+				klog.Warningf("scan %s space %s: %v", space.Kind, space.Space, err)
+			}
+		}()
+	}
+	usage, err := lookupSpaceUsage(c, space)
+	if err != nil {
+		resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "failed to load storage usage"))
+		return
+	}
+	resputil.Success(c, usage)
+}
+
+func resolveQuotaSpace(c *gin.Context, kind model.StorageSpaceKind, name string) (quotaSpace, uint, error) {
+	if kind == model.StorageSpaceAccount {
+		a := query.Account
+		account, err := a.WithContext(c).Where(a.Name.Eq(name)).First()
+		if err != nil || account.Space == "" {
+			return quotaSpace{}, 0, fmt.Errorf("account %s has no storage space", name)
+		}
+		return quotaSpace{Kind: kind, Space: strings.Trim(account.Space, "/")}, account.ID, nil
+	}
+	u := query.User
+	user, err := u.WithContext(c).Where(u.Name.Eq(name)).First()
+	if err != nil || user.Space == "" {
+		return quotaSpace{}, 0, fmt.Errorf("user %s has no storage space", name)
+	}
+	return quotaSpace{Kind: kind, Space: strings.Trim(user.Space, "/")}, user.ID, nil
+}
+
+func positiveOrNil(v int64) *int64 {
+	if v <= 0 {
+		return nil
+	}
+	return &v
+}
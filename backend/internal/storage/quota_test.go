@@ -0,0 +1,142 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/raids-lab/crater/dao/model"
+)
+
+func TestSpaceOfPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		input  string
+		want   quotaSpace
+		wantOK bool
+	}{
+		{name: "user file", input: "users/alice/a.pdf", want: quotaSpace{Kind: model.StorageSpaceUser, Space: "alice"}, wantOK: true},
+		{name: "account nested", input: "/accounts/q1/data/x", want: quotaSpace{Kind: model.StorageSpaceAccount, Space: "q1"}, wantOK: true},
+		{name: "space root", input: "users/alice", wantOK: false},
+		{name: "prefix only", input: "users", wantOK: false},
+		{name: "public", input: "public/a.pdf", wantOK: false},
+		{name: "similar prefix", input: "users-old/alice/a.pdf", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := spaceOfPath(tt.input, "users", "/accounts")
+			if ok != tt.wantOK || got != tt.want {
+				t.Fatalf("spaceOfPath(%q) = %+v, %v; want %+v, %v", tt.input, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestQuotaChangeDeltas(t *testing.T) {
+	t.Parallel()
+
+	alice := quotaSpace{Kind: model.StorageSpaceUser, Space: "alice"}
+	q1 := quotaSpace{Kind: model.StorageSpaceAccount, Space: "q1"}
+
+	overwrite := &quotaChange{method: http.MethodPut, src: alice, srcOK: true, replaced: spaceUsage{Bytes: 10, Inodes: 1}}
+	if got := overwrite.deltas(spaceUsage{Bytes: 25, Inodes: 1}); got[alice] != (spaceUsage{Bytes: 15}) {
+		t.Fatalf("overwrite deltas = %+v", got)
+	}
+
+	remove := &quotaChange{method: http.MethodDelete, src: alice, srcOK: true, moved: spaceUsage{Bytes: 30, Inodes: 3}}
+	if got := remove.deltas(spaceUsage{}); got[alice] != (spaceUsage{Bytes: -30, Inodes: -3}) {
+		t.Fatalf("delete deltas = %+v", got)
+	}
+
+	across := &quotaChange{
+		method: "MOVE", src: alice, srcOK: true, dst: q1, dstOK: true,
+		moved: spaceUsage{Bytes: 30, Inodes: 3}, replaced: spaceUsage{Bytes: 5, Inodes: 1},
+	}
+	got := across.deltas(spaceUsage{})
+	if got[alice] != (spaceUsage{Bytes: -30, Inodes: -3}) || got[q1] != (spaceUsage{Bytes: 25, Inodes: 2}) {
+		t.Fatalf("cross-space move deltas = %+v", got)
+	}
+
+	within := &quotaChange{method: "MOVE", src: alice, srcOK: true, dst: alice, dstOK: true, moved: spaceUsage{Bytes: 30, Inodes: 3}}
+	if got := within.deltas(spaceUsage{}); len(got) != 0 {
+		t.Fatalf("same-space move deltas = %+v, want none", got)
+	}
+
+	fromPublic := &quotaChange{method: "MOVE", dst: q1, dstOK: true, moved: spaceUsage{Bytes: 7, Inodes: 1}}
+	if got := fromPublic.deltas(spaceUsage{}); len(got) != 1 || got[q1] != (spaceUsage{Bytes: 7, Inodes: 1}) {
+		t.Fatalf("move from public deltas = %+v", got)
+	}
+}
+
+func TestStorageQuotaAdmits(t *testing.T) {
+	t.Parallel()
+
+	maxBytes, maxInodes := int64(100), int64(2)
+	quota := &model.StorageQuota{MaxBytes: &maxBytes, MaxInodes: &maxInodes, UsedBytes: 90, UsedInodes: 2}
+	if !quota.Admits(10, 0) {
+		t.Fatal("a write that exactly fills the byte quota should be admitted")
+	}
+	if quota.Admits(11, 0) {
+		t.Fatal("a write beyond the byte quota should be rejected")
+	}
+	if quota.Admits(1, 1) {
+		t.Fatal("a new file beyond the inode quota should be rejected")
+	}
+	if !quota.Admits(-50, -1) {
+		t.Fatal("freeing space should always be admitted")
+	}
+	if !(&model.StorageQuota{UsedBytes: 1 << 40}).Admits(1<<40, 1) {
+		t.Fatal("a space without limits should admit everything")
+	}
+}
+
+func TestQuotaLimitedBody(t *testing.T) {
+	t.Parallel()
+
+	room := int64(4)
+	change := &quotaChange{method: http.MethodPut, room: &room}
+	if got, err := io.ReadAll(change.limitBody(strings.NewReader("abcd"))); err != nil || string(got) != "abcd" {
+		t.Fatalf("a body that fills the room = %q, %v", got, err)
+	}
+	if _, err := io.ReadAll(change.limitBody(strings.NewReader("abcde"))); !errors.Is(err, errStorageQuotaExceeded) {
+		t.Fatalf("a body beyond the room error = %v, want quota exceeded", err)
+	}
+	var unlimited *quotaChange
+	if got, _ := io.ReadAll(unlimited.limitBody(strings.NewReader("abcde"))); string(got) != "abcde" {
+		t.Fatalf("an untracked body = %q", got)
+	}
+}
+
+func TestMeasureTree(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "a", "b"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, size := range map[string]int{"x": 3, "a/y": 4, "a/b/z": 5} {
+		if err := os.WriteFile(filepath.Join(root, name), make([]byte, size), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	usage, err := measureTree(root)
+	if err != nil {
+		t.Fatalf("measureTree: %v", err)
+	}
+	if usage != (spaceUsage{Bytes: 12, Inodes: 6}) {
+		t.Fatalf("measureTree = %+v, want 12 bytes and 6 inodes", usage)
+	}
+	missing, err := measureTree(filepath.Join(root, "missing"))
+	if err != nil || missing != (spaceUsage{}) {
+		t.Fatalf("measureTree(missing) = %+v, %v; want empty", missing, err)
+	}
+}
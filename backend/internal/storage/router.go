@@ -8,6 +8,7 @@ func RegisterRoutes(r *gin.Engine) {
 		"MKCOL",
 		"PROPFIND",
 		"PROPPATCH",
+		"MOVE",
 	}
 
 	for _, m := range methods {
@@ -18,4 +19,8 @@ func RegisterRoutes(r *gin.Engine) {
 	webdavGroup := r.Group("api/ss", WebDAVMiddleware())
 	RegisterDataset(webdavGroup)
 	RegisterFile(webdavGroup)
+	RegisterUpload(webdavGroup)
+	RegisterTrash(webdavGroup)
+	RegisterS3(webdavGroup)
+	RegisterArchive(webdavGroup)
 }
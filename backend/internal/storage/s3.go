@@ -0,0 +1,468 @@
+package storage
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/internal/util"
+)
+
+// The S3 gateway serves the spaces of ListMySpace as buckets: "user" is the caller's user
+// space, "account" the space of the account the access key was issued for and "public" the
+// public space. Only path-style addressing is supported.
+
+const (
+	s3Namespace      = "http://s3.amazonaws.com/doc/2006-03-01/"
+	s3MaxKeyLength   = 1024
+	s3DefaultMaxKeys = 1000
+	s3DefaultRegion  = "us-east-1"
+)
+
+// s3Endpoint is the public URL of the gateway, advertised with the credentials when set.
+var s3Endpoint string
+
+// SetS3Endpoint sets the URL clients should use to reach the S3 gateway.
+func SetS3Endpoint(endpoint string) {
+	s3Endpoint = strings.TrimRight(endpoint, "/")
+}
+
+type s3Error struct {
+	status  int
+	code    string
+	message string
+}
+
+func (e *s3Error) Error() string { return e.message }
+
+func newS3Error(status int, code, message string) *s3Error {
+	return &s3Error{status: status, code: code, message: message}
+}
+
+var (
+	s3ErrAccessDenied    = newS3Error(http.StatusForbidden, "AccessDenied", "Access Denied")
+	s3ErrNoSuchBucket    = newS3Error(http.StatusNotFound, "NoSuchBucket", "The specified bucket does not exist")
+	s3ErrNoSuchKey       = newS3Error(http.StatusNotFound, "NoSuchKey", "The specified key does not exist")
+	s3ErrNoSuchUpload    = newS3Error(http.StatusNotFound, "NoSuchUpload", "The specified multipart upload does not exist")
+	s3ErrInvalidKey      = newS3Error(http.StatusBadRequest, "InvalidArgument", "The key cannot be stored as a file path")
+	s3ErrMalformedXML    = newS3Error(http.StatusBadRequest, "MalformedXML", "The XML you provided was not well-formed")
+	s3ErrNotImplemented  = newS3Error(http.StatusNotImplemented, "NotImplemented", "This operation is not supported by the gateway")
+	s3ErrBucketExists    = newS3Error(http.StatusConflict, "BucketAlreadyOwnedByYou", "Buckets are the storage spaces and always exist")
+	s3ErrQuotaExceeded   = newS3Error(http.StatusForbidden, "QuotaExceeded", errStorageQuotaExceeded.Error())
+	s3ErrBadDigest       = newS3Error(http.StatusBadRequest, "XAmzContentSHA256Mismatch", "The payload does not match x-amz-content-sha256")
+	s3ErrChunkSignature  = newS3Error(http.StatusForbidden, "SignatureDoesNotMatch", "A chunk signature of the payload does not match")
+	s3ErrIncompleteBody  = newS3Error(http.StatusBadRequest, "IncompleteBody", "The request body is shorter than announced")
+	s3ErrInvalidPart     = newS3Error(http.StatusBadRequest, "InvalidPart", "One or more of the specified parts could not be found")
+	s3ErrInvalidOrder    = newS3Error(http.StatusBadRequest, "InvalidPartOrder", "The list of parts was not in ascending order")
+	s3ErrInvalidArgument = newS3Error(http.StatusBadRequest, "InvalidArgument", "Invalid argument")
+)
+
+type s3ErrorResp struct {
+	XMLName   xml.Name `xml:"Error"`
+	Code      string   `xml:"Code"`
+	Message   string   `xml:"Message"`
+	Resource  string   `xml:"Resource"`
+	RequestID string   `xml:"RequestId"`
+}
+
+func writeS3Error(c *gin.Context, err error) {
+	var s3Err *s3Error
+	if !errors.As(err, &s3Err) {
+		klog.Warningf("s3 %s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+		s3Err = newS3Error(http.StatusInternalServerError, "InternalError", "We encountered an internal error, please try again")
+	}
+	resp := s3ErrorResp{Code: s3Err.code, Message: s3Err.message, Resource: c.Request.URL.Path}
+	if c.Request.Method == http.MethodHead {
+		c.Status(s3Err.status)
+		return
+	}
+	writeS3XML(c, s3Err.status, resp)
+}
+
+func writeS3XML(c *gin.Context, status int, v any) {
+	body, err := xml.Marshal(v)
+	if err != nil {
+		klog.Errorf("marshal s3 response: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	c.Data(status, "application/xml", append([]byte(xml.Header), body...))
+}
+
+// authenticateS3 verifies the request signature and rebuilds the token of the key owner from
+// the database, so permission changes apply to existing keys right away.
+func authenticateS3(c *gin.Context) (util.JWTMessage, error) {
+	sig, err := parseS3Signature(c.Request)
+	if err != nil {
+		return util.JWTMessage{}, newS3Error(http.StatusForbidden, "AccessDenied", err.Error())
+	}
+	if err = sig.checkTime(time.Now()); err != nil {
+		return util.JWTMessage{}, newS3Error(http.StatusForbidden, "RequestTimeTooSkewed", err.Error())
+	}
+	key, secret, err := lookupS3AccessKey(c, sig.AccessKeyID)
+	if errors.Is(err, errS3UnknownAccessKey) {
+		return util.JWTMessage{}, newS3Error(http.StatusForbidden, "InvalidAccessKeyId", err.Error())
+	}
+	if err != nil {
+		return util.JWTMessage{}, err
+	}
+	if err = sig.verify(c.Request, secret); err != nil {
+		return util.JWTMessage{}, newS3Error(http.StatusForbidden, "SignatureDoesNotMatch", err.Error())
+	}
+	token, err := s3Token(c, key.UserID, key.AccountID)
+	if err != nil {
+		return util.JWTMessage{}, newS3Error(http.StatusForbidden, "InvalidAccessKeyId", err.Error())
+	}
+	c.Set(s3PayloadHashKey, sig.PayloadHash)
+	if sig.streamingSigned() {
+		c.Set(s3ChunkChainKey, sig.chunkChain(secret))
+	}
+	return token, nil
+}
+
+const (
+	s3PayloadHashKey = "s3PayloadHash"
+	s3ChunkChainKey  = "s3ChunkChain"
+)
+
+// s3Token builds the same claims a login into the account would produce.
+func s3Token(ctx context.Context, userID, accountID uint) (util.JWTMessage, error) {
+	u := query.User
+	user, err := u.WithContext(ctx).Where(u.ID.Eq(userID)).First()
+	if err != nil {
+		return util.JWTMessage{}, errors.New("the user of this access key does not exist")
+	}
+	if user.Status != model.StatusActive {
+		return util.JWTMessage{}, errors.New("the user of this access key is not active")
+	}
+	ua := query.UserAccount
+	membership, err := ua.WithContext(ctx).Where(ua.UserID.Eq(userID), ua.AccountID.Eq(accountID)).First()
+	if err != nil {
+		return util.JWTMessage{}, errors.New("the user no longer belongs to the account of this access key")
+	}
+	a := query.Account
+	account, err := a.WithContext(ctx).Where(a.ID.Eq(accountID)).First()
+	if err != nil {
+		return util.JWTMessage{}, errors.New("the account of this access key does not exist")
+	}
+	publicAccessMode := model.AccessModeNA
+	if public, err := ua.WithContext(ctx).Where(ua.UserID.Eq(userID), ua.AccountID.Eq(model.DefaultAccountID)).First(); err == nil {
+		publicAccessMode = public.AccessMode
+	}
+	return util.JWTMessage{
+		UserID:            user.ID,
+		Username:          user.Name,
+		AccountID:         account.ID,
+		AccountName:       account.Name,
+		RoleAccount:       membership.Role,
+		AccountAccessMode: membership.AccessMode,
+		PublicAccessMode:  publicAccessMode,
+		RolePlatform:      user.Role,
+	}, nil
+}
+
+// s3Buckets lists the logical roots of ListMySpace the caller may at least read.
+func s3Buckets(c *gin.Context, token util.JWTMessage) []string {
+	_, realSpaces := ListMySpace(token.UserID, token.AccountID, c)
+	var buckets []string
+	for _, root := range []string{model.UserPath, model.AccountPath, model.PublicPath} {
+		if GetPermission(root, token, c) == model.NotAllowed {
+			continue
+		}
+		realRoot, err := Redirect(c, root, token)
+		if err != nil || !containsString(realSpaces, realRoot) {
+			continue
+		}
+		buckets = append(buckets, root)
+	}
+	return buckets
+}
+
+// splitS3Path splits a path-style request path into bucket and key.
+func splitS3Path(p string) (bucket, key string) {
+	bucket, key, _ = strings.Cut(strings.TrimPrefix(p, "/"), "/")
+	return bucket, key
+}
+
+// validS3Key rejects keys that do not map one-to-one to a file path. A trailing slash marks
+// a directory.
+func validS3Key(key string) bool {
+	if key == "" || len(key) > s3MaxKeyLength || strings.Contains(key, "\\") {
+		return false
+	}
+	for _, segment := range strings.Split(strings.TrimSuffix(key, "/"), "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+// s3Target is a bucket or object addressed by a request.
+type s3Target struct {
+	token      util.JWTMessage
+	bucket     string
+	key        string
+	permission model.FilePermission
+	// realPath is the real storage path of the key, or of the bucket root without a key.
+	realPath string
+}
+
+func (t *s3Target) isDirKey() bool {
+	return strings.HasSuffix(t.key, "/")
+}
+
+// resolveS3Target checks the bucket and key and maps them to a real storage path.
+func resolveS3Target(c *gin.Context, token util.JWTMessage, bucket, key string) (*s3Target, error) {
+	if !containsString(s3Buckets(c, token), bucket) {
+		return nil, s3ErrNoSuchBucket
+	}
+	if key != "" && !validS3Key(key) {
+		return nil, s3ErrInvalidKey
+	}
+	logical := bucket
+	if key != "" {
+		logical = bucket + "/" + strings.TrimSuffix(key, "/")
+	}
+	realPath, err := Redirect(c, logical, token)
+	if err != nil {
+		return nil, s3ErrNoSuchBucket
+	}
+	return &s3Target{
+		token:      token,
+		bucket:     bucket,
+		key:        key,
+		permission: GetPermission(logical, token, c),
+		realPath:   realPath,
+	}, nil
+}
+
+func (t *s3Target) requireRead() error {
+	if t.permission == model.NotAllowed {
+		return s3ErrAccessDenied
+	}
+	return nil
+}
+
+func (t *s3Target) requireWrite() error {
+	if t.permission != model.ReadWrite {
+		return s3ErrAccessDenied
+	}
+	return nil
+}
+
+// ServeS3 dispatches one S3 request.
+func ServeS3(c *gin.Context) {
+	checkfs()
+	token, err := authenticateS3(c)
+	if err != nil {
+		writeS3Error(c, err)
+		return
+	}
+	bucket, key := splitS3Path(c.Request.URL.Path)
+	if bucket == "" {
+		if c.Request.Method != http.MethodGet {
+			writeS3Error(c, s3ErrNotImplemented)
+			return
+		}
+		listS3Buckets(c, token)
+		return
+	}
+	target, err := resolveS3Target(c, token, bucket, key)
+	if err == nil {
+		if key == "" {
+			err = serveS3Bucket(c, target)
+		} else {
+			err = serveS3Object(c, target)
+		}
+	}
+	if err != nil {
+		writeS3Error(c, err)
+	}
+}
+
+func serveS3Bucket(c *gin.Context, t *s3Target) error {
+	q := c.Request.URL.Query()
+	switch c.Request.Method {
+	case http.MethodHead:
+		if err := t.requireRead(); err != nil {
+			return err
+		}
+		c.Status(http.StatusOK)
+		return nil
+	case http.MethodGet:
+		if err := t.requireRead(); err != nil {
+			return err
+		}
+		switch {
+		case q.Has("location"):
+			writeS3XML(c, http.StatusOK, s3LocationResp{Namespace: s3Namespace, Region: s3DefaultRegion})
+			return nil
+		case q.Has("uploads"):
+			return listS3MultipartUploads(c, t)
+		case q.Has("versioning"), q.Has("acl"), q.Has("policy"), q.Has("cors"), q.Has("lifecycle"):
+			return s3ErrNotImplemented
+		}
+		return listS3Objects(c, t, q.Get("list-type") == "2")
+	case http.MethodPut:
+		return s3ErrBucketExists
+	case http.MethodPost:
+		if q.Has("delete") {
+			return deleteS3Objects(c, t)
+		}
+	}
+	return s3ErrNotImplemented
+}
+
+func serveS3Object(c *gin.Context, t *s3Target) error {
+	q := c.Request.URL.Query()
+	uploadID := q.Get("uploadId")
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead:
+		if uploadID != "" {
+			return listS3Parts(c, t, uploadID)
+		}
+		return getS3Object(c, t)
+	case http.MethodPut:
+		if c.GetHeader("X-Amz-Copy-Source") != "" {
+			return s3ErrNotImplemented
+		}
+		if uploadID != "" {
+			return uploadS3Part(c, t, uploadID, q.Get("partNumber"))
+		}
+		return putS3Object(c, t)
+	case http.MethodPost:
+		if q.Has("uploads") {
+			return createS3MultipartUpload(c, t)
+		}
+		if uploadID != "" {
+			return completeS3MultipartUpload(c, t, uploadID)
+		}
+	case http.MethodDelete:
+		if uploadID != "" {
+			return abortS3MultipartUpload(c, t, uploadID)
+		}
+		if err := t.requireWrite(); err != nil {
+			return err
+		}
+		if err := deleteS3Object(c, t); err != nil {
+			return err
+		}
+		c.Status(http.StatusNoContent)
+		return nil
+	}
+	return s3ErrNotImplemented
+}
+
+type s3Owner struct {
+	ID          string `xml:"ID"`
+	DisplayName string `xml:"DisplayName"`
+}
+
+type s3BucketResp struct {
+	Name         string `xml:"Name"`
+	CreationDate string `xml:"CreationDate"`
+}
+
+type s3ListBucketsResp struct {
+	XMLName   xml.Name       `xml:"ListAllMyBucketsResult"`
+	Namespace string         `xml:"xmlns,attr"`
+	Owner     s3Owner        `xml:"Owner"`
+	Buckets   []s3BucketResp `xml:"Buckets>Bucket"`
+}
+
+type s3LocationResp struct {
+	XMLName   xml.Name `xml:"LocationConstraint"`
+	Namespace string   `xml:"xmlns,attr"`
+	Region    string   `xml:",chardata"`
+}
+
+func listS3Buckets(c *gin.Context, token util.JWTMessage) {
+	resp := s3ListBucketsResp{
+		Namespace: s3Namespace,
+		Owner:     s3Owner{ID: token.Username, DisplayName: token.Username},
+		Buckets:   []s3BucketResp{},
+	}
+	for _, bucket := range s3Buckets(c, token) {
+		created := time.Unix(0, 0)
+		if realRoot, err := Redirect(c, bucket, token); err == nil {
+			if stat, err := os.Stat(localPath(realRoot)); err == nil {
+				created = stat.ModTime()
+			}
+		}
+		resp.Buckets = append(resp.Buckets, s3BucketResp{Name: bucket, CreationDate: created.UTC().Format(time.RFC3339)})
+	}
+	writeS3XML(c, http.StatusOK, resp)
+}
+
+// S3CredentialsResp carries the S3 access key of the caller in the current account.
+type S3CredentialsResp struct {
+	AccessKeyID     string   `json:"accessKeyId"`
+	SecretAccessKey string   `json:"secretAccessKey"`
+	Region          string   `json:"region"`
+	Endpoint        string   `json:"endpoint,omitempty"`
+	Buckets         []string `json:"buckets"`
+}
+
+// GetS3Credentials returns the S3 access key of the caller in the current account, issuing
+// one on first use.
+func GetS3Credentials(c *gin.Context) {
+	jwttoken, err := CheckJWTToken(c)
+	if err != nil {
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+		return
+	}
+	key, secret, err := s3AccessKeyOf(c, jwttoken.UserID, jwttoken.AccountID)
+	if err != nil {
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+		return
+	}
+	writeS3Credentials(c, jwttoken, key, secret)
+}
+
+// RotateS3Credentials replaces the S3 access key of the caller in the current account. The
+// old key stops working right away.
+func RotateS3Credentials(c *gin.Context) {
+	jwttoken, err := CheckJWTToken(c)
+	if err != nil {
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+		return
+	}
+	key, secret, err := rotateS3AccessKey(c, jwttoken.UserID, jwttoken.AccountID)
+	if err != nil {
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+		return
+	}
+	writeS3Credentials(c, jwttoken, key, secret)
+}
+
+func writeS3Credentials(c *gin.Context, jwttoken util.JWTMessage, key *model.S3AccessKey, secret string) {
+	resputil.Success(c, S3CredentialsResp{
+		AccessKeyID:     key.AccessKeyID,
+		SecretAccessKey: secret,
+		Region:          s3DefaultRegion,
+		Endpoint:        s3Endpoint,
+		Buckets:         s3Buckets(c, jwttoken),
+	})
+}
+
+func RegisterS3(webdavGroup *gin.RouterGroup) {
+	webdavGroup.GET("/s3/credentials", GetS3Credentials)
+	webdavGroup.POST("/s3/credentials/rotate", RotateS3Credentials)
+}
+
+// RegisterS3Routes serves the S3 gateway on its own engine, since S3 clients address buckets
+// at the root of the endpoint.
+func RegisterS3Routes(r *gin.Engine) {
+	r.Any("/*path", ServeS3)
+}
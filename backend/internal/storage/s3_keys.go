@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/pkg/crypto"
+)
+
+const (
+	s3AccessKeyPrefix = "CR"
+	s3AccessKeyIDLen  = 20
+	s3SecretLen       = 40
+)
+
+var errS3UnknownAccessKey = errors.New("the access key does not exist or has been rotated")
+
+// newS3AccessKey generates a random key pair in the shape S3 clients expect: a 20 character
+// upper-case key ID and a 40 character secret.
+func newS3AccessKey() (accessKeyID, secret string, err error) {
+	raw := make([]byte, 45)
+	if _, err = rand.Read(raw); err != nil {
+		return "", "", err
+	}
+	id := base32.StdEncoding.EncodeToString(raw[:15])
+	accessKeyID = s3AccessKeyPrefix + id[:s3AccessKeyIDLen-len(s3AccessKeyPrefix)]
+	secret = base64.RawURLEncoding.EncodeToString(raw[15:])[:s3SecretLen]
+	return accessKeyID, secret, nil
+}
+
+// createS3AccessKey stores a new key of a user in an account and returns its plain secret.
+func createS3AccessKey(ctx context.Context, tx *query.Query, userID, accountID uint) (*model.S3AccessKey, string, error) {
+	accessKeyID, secret, err := newS3AccessKey()
+	if err != nil {
+		return nil, "", err
+	}
+	encrypted, err := crypto.Encrypt(secret)
+	if err != nil {
+		return nil, "", err
+	}
+	key := &model.S3AccessKey{UserID: userID, AccountID: accountID, AccessKeyID: accessKeyID, Secret: encrypted}
+	if err = tx.S3AccessKey.WithContext(ctx).Create(key); err != nil {
+		return nil, "", err
+	}
+	return key, secret, nil
+}
+
+// s3AccessKeyOf returns the key of a user in an account and its plain secret, issuing one on
+// first use.
+func s3AccessKeyOf(ctx context.Context, userID, accountID uint) (*model.S3AccessKey, string, error) {
+	k := query.S3AccessKey
+	key, err := k.WithContext(ctx).Where(k.UserID.Eq(userID), k.AccountID.Eq(accountID)).Order(k.ID.Desc()).First()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return createS3AccessKey(ctx, query.Q, userID, accountID)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := crypto.Decrypt(key.Secret)
+	if err != nil {
+		return nil, "", err
+	}
+	return key, secret, nil
+}
+
+// rotateS3AccessKey replaces the key of a user in an account. Requests signed with the old
+// key are refused from then on.
+func rotateS3AccessKey(ctx context.Context, userID, accountID uint) (key *model.S3AccessKey, secret string, err error) {
+	err = query.Q.Transaction(func(tx *query.Query) error {
+		k := tx.S3AccessKey
+		if _, err := k.WithContext(ctx).Where(k.UserID.Eq(userID), k.AccountID.Eq(accountID)).Delete(); err != nil {
+			return err
+		}
+		key, secret, err = createS3AccessKey(ctx, tx, userID, accountID)
+		return err
+	})
+	return key, secret, err
+}
+
+// lookupS3AccessKey finds the owner and the plain secret of an access key ID.
+func lookupS3AccessKey(ctx context.Context, accessKeyID string) (*model.S3AccessKey, string, error) {
+	k := query.S3AccessKey
+	key, err := k.WithContext(ctx).Where(k.AccessKeyID.Eq(accessKeyID)).First()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, "", errS3UnknownAccessKey
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := crypto.Decrypt(key.Secret)
+	if err != nil {
+		return nil, "", err
+	}
+	return key, secret, nil
+}
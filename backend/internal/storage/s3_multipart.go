@@ -0,0 +1,453 @@
+package storage
+
+import (
+	"crypto/md5" //nolint:gosec // S3 ETags are MD5 digests
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+)
+
+const (
+	s3MultipartDir = "multipart"
+	s3MaxParts     = 10000
+	s3InfoFile     = "info.json"
+)
+
+var s3UploadIDPattern = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// s3MultipartInfo is stored next to the parts of an unfinished multipart upload.
+type s3MultipartInfo struct {
+	ID        string    `json:"id"`
+	UserID    uint      `json:"userID"`
+	AccountID uint      `json:"accountID"`
+	Bucket    string    `json:"bucket"`
+	Key       string    `json:"key"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type s3PartInfo struct {
+	Number   int
+	ETag     string
+	Size     int64
+	Modified time.Time
+}
+
+func s3UploadDir(id string) string {
+	return s3StagingPath(s3MultipartDir, id)
+}
+
+func s3PartPath(id string, number int) string {
+	return filepath.Join(s3UploadDir(id), fmt.Sprintf("part-%05d", number))
+}
+
+func readS3MultipartInfo(id string) (*s3MultipartInfo, error) {
+	if !s3UploadIDPattern.MatchString(id) {
+		return nil, s3ErrNoSuchUpload
+	}
+	data, err := os.ReadFile(filepath.Join(s3UploadDir(id), s3InfoFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, s3ErrNoSuchUpload
+	} else if err != nil {
+		return nil, err
+	}
+	var info s3MultipartInfo
+	if err = json.Unmarshal(data, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// loadS3Multipart returns an upload of the caller's access key for the target key.
+func loadS3Multipart(t *s3Target, id string) (*s3MultipartInfo, error) {
+	info, err := readS3MultipartInfo(id)
+	if err != nil {
+		return nil, err
+	}
+	if info.UserID != t.token.UserID || info.AccountID != t.token.AccountID || info.Bucket != t.bucket || info.Key != t.key {
+		return nil, s3ErrNoSuchUpload
+	}
+	return info, nil
+}
+
+// listS3UploadParts returns the received parts in ascending order.
+func listS3UploadParts(id string) ([]s3PartInfo, error) {
+	entries, err := os.ReadDir(s3UploadDir(id))
+	if err != nil {
+		return nil, err
+	}
+	var parts []s3PartInfo
+	for _, entry := range entries {
+		numberField, found := strings.CutPrefix(entry.Name(), "part-")
+		if !found {
+			continue
+		}
+		number, err := strconv.Atoi(numberField)
+		if err != nil {
+			continue
+		}
+		stat, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		etag, err := os.ReadFile(s3PartPath(id, number) + ".md5")
+		if err != nil {
+			continue
+		}
+		parts = append(parts, s3PartInfo{Number: number, ETag: "\"" + string(etag) + "\"", Size: stat.Size(), Modified: stat.ModTime()})
+	}
+	return parts, nil
+}
+
+type s3InitiateResp struct {
+	XMLName   xml.Name `xml:"InitiateMultipartUploadResult"`
+	Namespace string   `xml:"xmlns,attr"`
+	Bucket    string   `xml:"Bucket"`
+	Key       string   `xml:"Key"`
+	UploadID  string   `xml:"UploadId"`
+}
+
+func createS3MultipartUpload(c *gin.Context, t *s3Target) error {
+	if err := t.requireWrite(); err != nil {
+		return err
+	}
+	if t.isDirKey() {
+		return s3ErrInvalidKey
+	}
+	id, err := newRandomID()
+	if err != nil {
+		return err
+	}
+	info := s3MultipartInfo{
+		ID:        id,
+		UserID:    t.token.UserID,
+		AccountID: t.token.AccountID,
+		Bucket:    t.bucket,
+		Key:       t.key,
+		CreatedAt: time.Now(),
+	}
+	data, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(s3UploadDir(id), os.ModePerm); err != nil {
+		return err
+	}
+	if err = os.WriteFile(filepath.Join(s3UploadDir(id), s3InfoFile), data, 0o600); err != nil {
+		return err
+	}
+	writeS3XML(c, http.StatusOK, s3InitiateResp{Namespace: s3Namespace, Bucket: t.bucket, Key: t.key, UploadID: id})
+	return nil
+}
+
+func uploadS3Part(c *gin.Context, t *s3Target, id, rawNumber string) error {
+	if err := t.requireWrite(); err != nil {
+		return err
+	}
+	number, err := strconv.Atoi(rawNumber)
+	if err != nil || number < 1 || number > s3MaxParts {
+		return newS3Error(http.StatusBadRequest, "InvalidArgument", "Part number must be an integer between 1 and 10000")
+	}
+	if _, err = loadS3Multipart(t, id); err != nil {
+		return err
+	}
+	staged, digest, err := writeS3Staged(c, s3UploadDir(id), s3ContentLength(c), nil)
+	if err != nil {
+		return err
+	}
+	etag := hex.EncodeToString(digest)
+	if err = os.WriteFile(s3PartPath(id, number)+".md5", []byte(etag), 0o600); err == nil {
+		err = os.Rename(staged, s3PartPath(id, number))
+	}
+	if err != nil {
+		_ = os.Remove(staged)
+		return err
+	}
+	c.Header("ETag", "\""+etag+"\"")
+	c.Status(http.StatusOK)
+	return nil
+}
+
+type s3CompleteReq struct {
+	Parts []struct {
+		PartNumber int    `xml:"PartNumber"`
+		ETag       string `xml:"ETag"`
+	} `xml:"Part"`
+}
+
+type s3CompleteResp struct {
+	XMLName   xml.Name `xml:"CompleteMultipartUploadResult"`
+	Namespace string   `xml:"xmlns,attr"`
+	Location  string   `xml:"Location"`
+	Bucket    string   `xml:"Bucket"`
+	Key       string   `xml:"Key"`
+	ETag      string   `xml:"ETag"`
+}
+
+// selectS3Parts matches the parts named by the client against the received ones. The client
+// must list them in ascending order with the ETags it was given.
+func selectS3Parts(requested []int, etags []string, received []s3PartInfo) ([]s3PartInfo, error) {
+	if len(requested) == 0 {
+		return nil, s3ErrMalformedXML
+	}
+	byNumber := make(map[int]s3PartInfo, len(received))
+	for _, part := range received {
+		byNumber[part.Number] = part
+	}
+	selected := make([]s3PartInfo, 0, len(requested))
+	for i, number := range requested {
+		if i > 0 && number <= requested[i-1] {
+			return nil, s3ErrInvalidOrder
+		}
+		part, ok := byNumber[number]
+		if !ok || strings.Trim(part.ETag, "\"") != strings.Trim(etags[i], "\"") {
+			return nil, s3ErrInvalidPart
+		}
+		selected = append(selected, part)
+	}
+	return selected, nil
+}
+
+// s3MultipartETag is the ETag S3 gives multipart objects: the MD5 of the part digests
+// followed by the number of parts.
+func s3MultipartETag(parts []s3PartInfo) string {
+	sum := md5.New() //nolint:gosec // S3 ETags are MD5 digests
+	for _, part := range parts {
+		digest, err := hex.DecodeString(strings.Trim(part.ETag, "\""))
+		if err == nil {
+			sum.Write(digest)
+		}
+	}
+	return fmt.Sprintf("\"%s-%d\"", hex.EncodeToString(sum.Sum(nil)), len(parts))
+}
+
+func completeS3MultipartUpload(c *gin.Context, t *s3Target, id string) error {
+	if err := t.requireWrite(); err != nil {
+		return err
+	}
+	if _, err := loadS3Multipart(t, id); err != nil {
+		return err
+	}
+	var req s3CompleteReq
+	if err := readS3XMLBody(c, &req); err != nil {
+		return err
+	}
+	numbers := make([]int, len(req.Parts))
+	etags := make([]string, len(req.Parts))
+	for i, part := range req.Parts {
+		numbers[i], etags[i] = part.PartNumber, part.ETag
+	}
+	received, err := listS3UploadParts(id)
+	if err != nil {
+		return err
+	}
+	parts, err := selectS3Parts(numbers, etags, received)
+	if err != nil {
+		return err
+	}
+	var size int64
+	for _, part := range parts {
+		size += part.Size
+	}
+	change := prepareQuotaChange(http.MethodPut, t.realPath, "")
+	if err = change.admit(c, size); err != nil {
+		return s3ErrQuotaExceeded
+	}
+	assembled, err := assembleS3Parts(id, parts)
+	if err != nil {
+		return err
+	}
+	if err = placeS3File(assembled, localPath(t.realPath)); err != nil {
+		_ = os.Remove(assembled)
+		return err
+	}
+	change.commit(c)
+	if err = os.RemoveAll(s3UploadDir(id)); err != nil {
+		klog.Warningf("remove finished s3 upload %s: %v", id, err)
+	}
+	writeS3XML(c, http.StatusOK, s3CompleteResp{
+		Namespace: s3Namespace,
+		Location:  "/" + t.bucket + "/" + t.key,
+		Bucket:    t.bucket,
+		Key:       t.key,
+		ETag:      s3MultipartETag(parts),
+	})
+	return nil
+}
+
+// assembleS3Parts concatenates the parts into one file inside the upload directory.
+func assembleS3Parts(id string, parts []s3PartInfo) (string, error) {
+	out, err := os.CreateTemp(s3UploadDir(id), "assembled-*")
+	if err != nil {
+		return "", err
+	}
+	for _, part := range parts {
+		if err = appendS3Part(out, s3PartPath(id, part.Number)); err != nil {
+			break
+		}
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(out.Name())
+		return "", err
+	}
+	return out.Name(), nil
+}
+
+func appendS3Part(out io.Writer, partPath string) error {
+	in, err := os.Open(partPath)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	_, err = io.Copy(out, in)
+	return err
+}
+
+func abortS3MultipartUpload(c *gin.Context, t *s3Target, id string) error {
+	if _, err := loadS3Multipart(t, id); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(s3UploadDir(id)); err != nil {
+		return err
+	}
+	c.Status(http.StatusNoContent)
+	return nil
+}
+
+type s3PartResp struct {
+	PartNumber   int    `xml:"PartNumber"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag"`
+	Size         int64  `xml:"Size"`
+}
+
+type s3ListPartsResp struct {
+	XMLName              xml.Name     `xml:"ListPartsResult"`
+	Namespace            string       `xml:"xmlns,attr"`
+	Bucket               string       `xml:"Bucket"`
+	Key                  string       `xml:"Key"`
+	UploadID             string       `xml:"UploadId"`
+	PartNumberMarker     int          `xml:"PartNumberMarker"`
+	NextPartNumberMarker int          `xml:"NextPartNumberMarker"`
+	MaxParts             int          `xml:"MaxParts"`
+	IsTruncated          bool         `xml:"IsTruncated"`
+	Parts                []s3PartResp `xml:"Part"`
+}
+
+func listS3Parts(c *gin.Context, t *s3Target, id string) error {
+	if _, err := loadS3Multipart(t, id); err != nil {
+		return err
+	}
+	marker, _ := strconv.Atoi(c.Query("part-number-marker"))
+	maxParts := s3DefaultMaxKeys
+	if n, err := strconv.Atoi(c.Query("max-parts")); err == nil && n >= 0 && n < maxParts {
+		maxParts = n
+	}
+	parts, err := listS3UploadParts(id)
+	if err != nil {
+		return err
+	}
+	resp := s3ListPartsResp{
+		Namespace:        s3Namespace,
+		Bucket:           t.bucket,
+		Key:              t.key,
+		UploadID:         id,
+		PartNumberMarker: marker,
+		MaxParts:         maxParts,
+	}
+	for _, part := range parts {
+		if part.Number <= marker {
+			continue
+		}
+		if len(resp.Parts) == maxParts {
+			resp.IsTruncated = true
+			break
+		}
+		resp.Parts = append(resp.Parts, s3PartResp{
+			PartNumber:   part.Number,
+			LastModified: part.Modified.UTC().Format("2006-01-02T15:04:05.000Z"),
+			ETag:         part.ETag,
+			Size:         part.Size,
+		})
+		resp.NextPartNumberMarker = part.Number
+	}
+	writeS3XML(c, http.StatusOK, resp)
+	return nil
+}
+
+type s3UploadResp struct {
+	Key       string `xml:"Key"`
+	UploadID  string `xml:"UploadId"`
+	Initiated string `xml:"Initiated"`
+}
+
+type s3ListUploadsResp struct {
+	XMLName     xml.Name       `xml:"ListMultipartUploadsResult"`
+	Namespace   string         `xml:"xmlns,attr"`
+	Bucket      string         `xml:"Bucket"`
+	Prefix      string         `xml:"Prefix"`
+	MaxUploads  int            `xml:"MaxUploads"`
+	IsTruncated bool           `xml:"IsTruncated"`
+	Uploads     []s3UploadResp `xml:"Upload"`
+}
+
+// listS3MultipartUploads lists the unfinished uploads of the caller's access key in a bucket.
+func listS3MultipartUploads(c *gin.Context, t *s3Target) error {
+	prefix := c.Query("prefix")
+	resp := s3ListUploadsResp{Namespace: s3Namespace, Bucket: t.bucket, Prefix: prefix, MaxUploads: s3DefaultMaxKeys}
+	entries, err := os.ReadDir(s3StagingPath(s3MultipartDir))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	for _, entry := range entries {
+		info, err := readS3MultipartInfo(entry.Name())
+		if err != nil || info.UserID != t.token.UserID || info.AccountID != t.token.AccountID ||
+			info.Bucket != t.bucket || !strings.HasPrefix(info.Key, prefix) {
+			continue
+		}
+		if len(resp.Uploads) == s3DefaultMaxKeys {
+			resp.IsTruncated = true
+			break
+		}
+		resp.Uploads = append(resp.Uploads, s3UploadResp{
+			Key:       info.Key,
+			UploadID:  info.ID,
+			Initiated: info.CreatedAt.UTC().Format("2006-01-02T15:04:05.000Z"),
+		})
+	}
+	writeS3XML(c, http.StatusOK, resp)
+	return nil
+}
+
+// removeStaleS3Multipart drops multipart uploads that have not received a part since before.
+func removeStaleS3Multipart(before time.Time) {
+	root := s3StagingPath(s3MultipartDir)
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		stat, err := entry.Info()
+		if err != nil || stat.ModTime().After(before) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(root, entry.Name())); err != nil {
+			klog.Warningf("remove stale s3 upload %s: %v", entry.Name(), err)
+		}
+	}
+}
@@ -0,0 +1,659 @@
+package storage
+
+import (
+	"bufio"
+	"crypto/md5" //nolint:gosec // S3 ETags are MD5 digests
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+
+	"github.com/raids-lab/crater/dao/model"
+)
+
+const (
+	// s3StagingDir holds PUT bodies and multipart parts until they are moved into place.
+	s3StagingDir      = ".crater-s3"
+	s3MaxDeleteKeys   = 1000
+	s3StagingTempDir  = "tmp"
+	s3StagingTempTTL  = 24 * time.Hour
+	s3EmptyContentMD5 = "d41d8cd98f00b204e9800998ecf8427e"
+)
+
+func s3StagingPath(elem ...string) string {
+	return filepath.Join(append([]string{storageRootDir, s3StagingDir}, elem...)...)
+}
+
+// s3ETag is the ETag of a stored file. Files written over WebDAV have no recorded digest, so
+// the ETag is derived from mtime and size; the dash keeps clients from taking it for an MD5.
+func s3ETag(info os.FileInfo) string {
+	return fmt.Sprintf("\"%x-%x\"", info.ModTime().UnixNano(), info.Size())
+}
+
+func getS3Object(c *gin.Context, t *s3Target) error {
+	if err := t.requireRead(); err != nil {
+		return err
+	}
+	local := localPath(t.realPath)
+	info, err := os.Stat(local)
+	if err != nil || info.IsDir() != t.isDirKey() {
+		return s3ErrNoSuchKey
+	}
+	c.Header("ETag", s3ETag(info))
+	c.Header("Accept-Ranges", "bytes")
+	if info.IsDir() {
+		c.Header("Content-Type", "application/x-directory")
+		c.Header("Last-Modified", info.ModTime().UTC().Format(http.TimeFormat))
+		c.Header("Content-Length", "0")
+		c.Status(http.StatusOK)
+		return nil
+	}
+	contentType := mime.TypeByExtension(path.Ext(t.key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	c.Header("Content-Type", contentType)
+	file, err := os.Open(local)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	http.ServeContent(c.Writer, c.Request, path.Base(t.key), info.ModTime(), file)
+	return nil
+}
+
+// s3Payload returns the request body without aws-chunked framing, and a check that reports
+// whether the body matched the signed payload hash once it has been read completely. Chunk
+// signatures of signed streaming uploads are verified while the body is read.
+func s3Payload(c *gin.Context) (io.Reader, func() error) {
+	payloadHash := c.GetString(s3PayloadHashKey)
+	body := io.Reader(c.Request.Body)
+	if strings.HasPrefix(payloadHash, s3StreamingPrefix) ||
+		strings.Contains(c.GetHeader("Content-Encoding"), "aws-chunked") {
+		chain, _ := c.Value(s3ChunkChainKey).(*s3ChunkChain)
+		return newAWSChunkedReader(body, chain), func() error { return nil }
+	}
+	if len(payloadHash) != sha256.Size*2 {
+		return body, func() error { return nil }
+	}
+	h := sha256.New()
+	return io.TeeReader(body, h), func() error {
+		if hex.EncodeToString(h.Sum(nil)) != strings.ToLower(payloadHash) {
+			return s3ErrBadDigest
+		}
+		return nil
+	}
+}
+
+// s3ContentLength is the object size announced by the request, -1 when unknown.
+func s3ContentLength(c *gin.Context) int64 {
+	if decoded := c.GetHeader("X-Amz-Decoded-Content-Length"); decoded != "" {
+		if n, err := strconv.ParseInt(decoded, 10, 64); err == nil {
+			return n
+		}
+		return -1
+	}
+	return c.Request.ContentLength
+}
+
+// awsChunkedReader strips the aws-chunked content encoding: each chunk is
+// "<hex size>[;chunk-signature=...]\r\n<data>\r\n" and a zero sized chunk, optionally
+// followed by trailers, ends the body. With a chain, every chunk signature is checked once
+// its data has been read, and the trailers are checked after the final chunk.
+type awsChunkedReader struct {
+	r         *bufio.Reader
+	chain     *s3ChunkChain
+	remaining int64
+	signature string
+	hash      hash.Hash
+	done      bool
+}
+
+var errMalformedAWSChunked = errors.New("malformed aws-chunked body")
+
+func newAWSChunkedReader(r io.Reader, chain *s3ChunkChain) *awsChunkedReader {
+	return &awsChunkedReader{r: bufio.NewReader(r), chain: chain, hash: sha256.New()}
+}
+
+func (a *awsChunkedReader) Read(p []byte) (int, error) {
+	if a.done {
+		return 0, io.EOF
+	}
+	if a.remaining == 0 {
+		if err := a.nextChunk(); err != nil {
+			return 0, err
+		}
+		if a.done {
+			return 0, io.EOF
+		}
+	}
+	if int64(len(p)) > a.remaining {
+		p = p[:a.remaining]
+	}
+	n, err := a.r.Read(p)
+	a.remaining -= int64(n)
+	a.hash.Write(p[:n])
+	if a.remaining == 0 {
+		if _, crlfErr := a.r.Discard(2); crlfErr != nil {
+			return n, io.ErrUnexpectedEOF
+		}
+		if verifyErr := a.verifyChunk(); verifyErr != nil {
+			return n, verifyErr
+		}
+	}
+	if errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+// nextChunk reads the header of the next chunk. The final zero sized chunk is verified right
+// away, together with the trailers that follow it.
+func (a *awsChunkedReader) nextChunk() error {
+	line, err := a.r.ReadString('\n')
+	if err != nil {
+		return io.ErrUnexpectedEOF
+	}
+	sizeField, extension, _ := strings.Cut(strings.TrimSpace(line), ";")
+	size, err := strconv.ParseInt(sizeField, 16, 64)
+	if err != nil || size < 0 {
+		return errMalformedAWSChunked
+	}
+	a.remaining = size
+	a.signature, _ = strings.CutPrefix(extension, "chunk-signature=")
+	a.hash.Reset()
+	if size > 0 {
+		return nil
+	}
+	a.done = true
+	if err = a.verifyChunk(); err != nil {
+		return err
+	}
+	if a.chain != nil && a.chain.trailer {
+		return a.verifyTrailer()
+	}
+	return nil
+}
+
+func (a *awsChunkedReader) verifyChunk() error {
+	if a.chain == nil {
+		return nil
+	}
+	if err := a.chain.verifyChunk(a.signature, a.hash.Sum(nil)); err != nil {
+		return s3ErrChunkSignature
+	}
+	return nil
+}
+
+// verifyTrailer reads the trailing headers up to the closing empty line and checks their
+// signature, which covers every trailer in its canonical "name:value\n" form.
+func (a *awsChunkedReader) verifyTrailer() error {
+	var trailers strings.Builder
+	var signature string
+	for {
+		line, err := a.r.ReadString('\n')
+		if err != nil && (!errors.Is(err, io.EOF) || strings.TrimSpace(line) != "") {
+			return io.ErrUnexpectedEOF
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return errMalformedAWSChunked
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "x-amz-trailer-signature" {
+			signature = strings.TrimSpace(value)
+			continue
+		}
+		trailers.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+	sum := sha256.Sum256([]byte(trailers.String()))
+	if err := a.chain.verifyTrailer(signature, sum[:]); err != nil {
+		return s3ErrChunkSignature
+	}
+	return nil
+}
+
+// writeS3Staged copies the payload into a new file below dir and returns its path and MD5.
+// The file is removed again when the copy fails, outgrows the quota change or does not verify.
+func writeS3Staged(c *gin.Context, dir string, size int64, change *quotaChange) (staged string, digest []byte, err error) {
+	if err = os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", nil, err
+	}
+	file, err := os.CreateTemp(dir, ".staging-*")
+	if err != nil {
+		return "", nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(file.Name())
+		}
+	}()
+	body, check := s3Payload(c)
+	if size < 0 {
+		body = change.limitBody(body)
+	}
+	sum := md5.New() //nolint:gosec // S3 ETags are MD5 digests
+	written, err := io.Copy(io.MultiWriter(file, sum), body)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return "", nil, s3ErrIncompleteBody
+		}
+		if errors.Is(err, errStorageQuotaExceeded) {
+			return "", nil, s3ErrQuotaExceeded
+		}
+		return "", nil, err
+	}
+	if size >= 0 && written != size {
+		return "", nil, s3ErrIncompleteBody
+	}
+	if err = check(); err != nil {
+		return "", nil, err
+	}
+	if expected := c.GetHeader("Content-Md5"); expected != "" && expected != base64.StdEncoding.EncodeToString(sum.Sum(nil)) {
+		return "", nil, newS3Error(http.StatusBadRequest, "BadDigest", "The Content-MD5 you specified did not match what we received")
+	}
+	return file.Name(), sum.Sum(nil), nil
+}
+
+// placeS3File moves a staged file to the key, creating missing parent directories the way
+// S3 keys imply them.
+func placeS3File(staged, dest string) error {
+	if info, err := os.Stat(dest); err == nil && info.IsDir() {
+		return newS3Error(http.StatusConflict, "InvalidRequest", "A directory exists at this key")
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), model.RWXFolderPerm); err != nil {
+		return newS3Error(http.StatusConflict, "InvalidRequest", "A parent of this key is a file")
+	}
+	if err := os.Rename(staged, dest); err != nil {
+		return err
+	}
+	if err := os.Chmod(dest, model.RWXFolderPerm); err != nil {
+		klog.Warningf("chmod %s: %v", dest, err)
+	}
+	return nil
+}
+
+func putS3Object(c *gin.Context, t *s3Target) error {
+	if err := t.requireWrite(); err != nil {
+		return err
+	}
+	dest := localPath(t.realPath)
+	if t.isDirKey() {
+		change := prepareQuotaChange("MKCOL", t.realPath, "")
+		if err := change.admit(c, 0); err != nil {
+			return s3ErrQuotaExceeded
+		}
+		if _, err := os.Stat(dest); err != nil {
+			if err = os.MkdirAll(dest, model.RWXFolderPerm); err != nil {
+				return newS3Error(http.StatusConflict, "InvalidRequest", "A parent of this key is a file")
+			}
+			change.commit(c)
+		}
+		c.Header("ETag", "\""+s3EmptyContentMD5+"\"")
+		c.Status(http.StatusOK)
+		return nil
+	}
+	size := s3ContentLength(c)
+	change := prepareQuotaChange(http.MethodPut, t.realPath, "")
+	if err := change.admit(c, size); err != nil {
+		return s3ErrQuotaExceeded
+	}
+	staged, digest, err := writeS3Staged(c, s3StagingPath(s3StagingTempDir), size, change)
+	if err != nil {
+		return err
+	}
+	if err = placeS3File(staged, dest); err != nil {
+		_ = os.Remove(staged)
+		return err
+	}
+	change.commit(c)
+	c.Header("ETag", "\""+hex.EncodeToString(digest)+"\"")
+	c.Status(http.StatusOK)
+	return nil
+}
+
+// deleteS3Object deletes a key. Files of user and account spaces go to the trash like WebDAV
+// deletes; a directory key only removes an empty directory. Missing keys are not an error.
+func deleteS3Object(c *gin.Context, t *s3Target) error {
+	local := localPath(t.realPath)
+	info, err := os.Lstat(local)
+	if err != nil || info.IsDir() != t.isDirKey() {
+		return nil
+	}
+	if info.IsDir() {
+		if err = os.Remove(local); err == nil {
+			if space, ok := chargedSpace(t.realPath); ok {
+				if err := applyUsageDelta(c, space, spaceUsage{Inodes: -1}); err != nil {
+					klog.Warningf("update storage usage of %s space %s: %v", space.Kind, space.Space, err)
+				}
+			}
+		}
+		return nil
+	}
+	if space, ok := chargedSpace(t.realPath); ok {
+		_, err = moveToTrash(c, t.realPath, space, t.token.UserID)
+	} else {
+		err = os.Remove(local)
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+type s3DeleteReq struct {
+	Quiet   bool `xml:"Quiet"`
+	Objects []struct {
+		Key string `xml:"Key"`
+	} `xml:"Object"`
+}
+
+type s3DeletedResp struct {
+	Key string `xml:"Key"`
+}
+
+type s3DeleteErrorResp struct {
+	Key     string `xml:"Key"`
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+type s3DeleteResp struct {
+	XMLName   xml.Name            `xml:"DeleteResult"`
+	Namespace string              `xml:"xmlns,attr"`
+	Deleted   []s3DeletedResp     `xml:"Deleted"`
+	Errors    []s3DeleteErrorResp `xml:"Error"`
+}
+
+// readS3XMLBody decodes a small XML request body after checking its payload hash.
+func readS3XMLBody(c *gin.Context, v any) error {
+	body, check := s3Payload(c)
+	data, err := io.ReadAll(io.LimitReader(body, 1<<20))
+	if errors.Is(err, s3ErrChunkSignature) {
+		return err
+	}
+	if err != nil {
+		return s3ErrIncompleteBody
+	}
+	if err = check(); err != nil {
+		return err
+	}
+	if err = xml.Unmarshal(data, v); err != nil {
+		return s3ErrMalformedXML
+	}
+	return nil
+}
+
+func deleteS3Objects(c *gin.Context, bucket *s3Target) error {
+	var req s3DeleteReq
+	if err := readS3XMLBody(c, &req); err != nil {
+		return err
+	}
+	if len(req.Objects) > s3MaxDeleteKeys {
+		return s3ErrMalformedXML
+	}
+	resp := s3DeleteResp{Namespace: s3Namespace}
+	for _, object := range req.Objects {
+		target, err := resolveS3Target(c, bucket.token, bucket.bucket, object.Key)
+		if err == nil {
+			if err = target.requireWrite(); err == nil {
+				err = deleteS3Object(c, target)
+			}
+		}
+		if err != nil {
+			var s3Err *s3Error
+			if !errors.As(err, &s3Err) {
+				klog.Warningf("s3 delete %s/%s: %v", bucket.bucket, object.Key, err)
+				s3Err = newS3Error(http.StatusInternalServerError, "InternalError", "failed to delete the key")
+			}
+			resp.Errors = append(resp.Errors, s3DeleteErrorResp{Key: object.Key, Code: s3Err.code, Message: s3Err.message})
+			continue
+		}
+		if !req.Quiet {
+			resp.Deleted = append(resp.Deleted, s3DeletedResp{Key: object.Key})
+		}
+	}
+	writeS3XML(c, http.StatusOK, resp)
+	return nil
+}
+
+// s3Object is one entry of a bucket listing.
+type s3Object struct {
+	Key      string
+	Size     int64
+	Modified time.Time
+	ETag     string
+	// IsPrefix marks a common prefix produced by the delimiter.
+	IsPrefix bool
+}
+
+// listS3Keys lists the keys below root in S3 order. With the "/" delimiter only the directory
+// holding the prefix is read; other listings walk the tree and skip directories, which hold no
+// data of their own.
+func listS3Keys(root, prefix, delimiter, after string, maxKeys int) (entries []s3Object, truncated bool, err error) {
+	dirPart := prefix[:strings.LastIndex(prefix, "/")+1]
+	start := filepath.Join(root, filepath.FromSlash(dirPart))
+	var all []s3Object
+	add := func(key string, info os.FileInfo) {
+		if !strings.HasPrefix(key, prefix) {
+			return
+		}
+		if delimiter != "" {
+			if i := strings.Index(key[len(prefix):], delimiter); i >= 0 {
+				all = append(all, s3Object{Key: key[:len(prefix)+i+len(delimiter)], IsPrefix: true})
+				return
+			}
+		}
+		all = append(all, s3Object{Key: key, Size: info.Size(), Modified: info.ModTime(), ETag: s3ETag(info)})
+	}
+	if delimiter == "/" {
+		dirEntries, err := os.ReadDir(start)
+		if err != nil && !errors.Is(err, os.ErrNotExist) && !errors.Is(err, syscall.ENOTDIR) {
+			return nil, false, err
+		}
+		for _, entry := range dirEntries {
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			key := dirPart + entry.Name()
+			if entry.IsDir() {
+				key += "/"
+			} else if !info.Mode().IsRegular() {
+				continue
+			}
+			add(key, info)
+		}
+	} else {
+		err = filepath.WalkDir(start, func(p string, d os.DirEntry, err error) error {
+			if err != nil {
+				if errors.Is(err, os.ErrNotExist) || p != start {
+					return nil
+				}
+				return err
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			rel, err := filepath.Rel(root, p)
+			if err != nil {
+				return nil
+			}
+			add(filepath.ToSlash(rel), info)
+			return nil
+		})
+		if err != nil && !errors.Is(err, syscall.ENOTDIR) {
+			return nil, false, err
+		}
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Key < all[j].Key })
+	for i, entry := range all {
+		if entry.Key <= after || (i > 0 && entry.IsPrefix && all[i-1].Key == entry.Key) {
+			continue
+		}
+		if len(entries) == maxKeys {
+			return entries, true, nil
+		}
+		entries = append(entries, entry)
+	}
+	return entries, false, nil
+}
+
+type s3ContentResp struct {
+	Key          string `xml:"Key"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag"`
+	Size         int64  `xml:"Size"`
+	StorageClass string `xml:"StorageClass"`
+}
+
+type s3PrefixResp struct {
+	Prefix string `xml:"Prefix"`
+}
+
+type s3ListObjectsResp struct {
+	XMLName               xml.Name        `xml:"ListBucketResult"`
+	Namespace             string          `xml:"xmlns,attr"`
+	Name                  string          `xml:"Name"`
+	Prefix                string          `xml:"Prefix"`
+	Delimiter             string          `xml:"Delimiter,omitempty"`
+	MaxKeys               int             `xml:"MaxKeys"`
+	EncodingType          string          `xml:"EncodingType,omitempty"`
+	IsTruncated           bool            `xml:"IsTruncated"`
+	Marker                *string         `xml:"Marker,omitempty"`
+	NextMarker            string          `xml:"NextMarker,omitempty"`
+	KeyCount              *int            `xml:"KeyCount,omitempty"`
+	ContinuationToken     string          `xml:"ContinuationToken,omitempty"`
+	NextContinuationToken string          `xml:"NextContinuationToken,omitempty"`
+	StartAfter            string          `xml:"StartAfter,omitempty"`
+	Contents              []s3ContentResp `xml:"Contents"`
+	CommonPrefixes        []s3PrefixResp  `xml:"CommonPrefixes"`
+}
+
+// listS3Objects serves ListObjectsV2 and, for older clients, ListObjects.
+func listS3Objects(c *gin.Context, t *s3Target, v2 bool) error {
+	q := c.Request.URL.Query()
+	maxKeys := s3DefaultMaxKeys
+	if raw := q.Get("max-keys"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			return s3ErrInvalidArgument
+		}
+		maxKeys = min(n, s3DefaultMaxKeys)
+	}
+	prefix, delimiter := q.Get("prefix"), q.Get("delimiter")
+	encode := func(s string) string { return s }
+	if q.Get("encoding-type") == "url" {
+		encode = s3URLEncodeKey
+	}
+	resp := s3ListObjectsResp{
+		Namespace:    s3Namespace,
+		Name:         t.bucket,
+		Prefix:       encode(prefix),
+		Delimiter:    encode(delimiter),
+		MaxKeys:      maxKeys,
+		EncodingType: q.Get("encoding-type"),
+	}
+	after := q.Get("marker")
+	if v2 {
+		after = q.Get("start-after")
+		resp.StartAfter = encode(after)
+		if token := q.Get("continuation-token"); token != "" {
+			decoded, err := base64.RawURLEncoding.DecodeString(token)
+			if err != nil {
+				return newS3Error(http.StatusBadRequest, "InvalidArgument", "The continuation token provided is incorrect")
+			}
+			after = string(decoded)
+			resp.ContinuationToken = token
+		}
+	} else {
+		resp.Marker = &after
+	}
+	entries, truncated, err := listS3Keys(localPath(t.realPath), prefix, delimiter, after, maxKeys)
+	if err != nil {
+		return err
+	}
+	resp.IsTruncated = truncated
+	for _, entry := range entries {
+		if entry.IsPrefix {
+			resp.CommonPrefixes = append(resp.CommonPrefixes, s3PrefixResp{Prefix: encode(entry.Key)})
+			continue
+		}
+		resp.Contents = append(resp.Contents, s3ContentResp{
+			Key:          encode(entry.Key),
+			LastModified: entry.Modified.UTC().Format("2006-01-02T15:04:05.000Z"),
+			ETag:         entry.ETag,
+			Size:         entry.Size,
+			StorageClass: "STANDARD",
+		})
+	}
+	if truncated && len(entries) > 0 {
+		last := entries[len(entries)-1].Key
+		if v2 {
+			resp.NextContinuationToken = base64.RawURLEncoding.EncodeToString([]byte(last))
+		} else {
+			resp.NextMarker = encode(last)
+		}
+	}
+	if v2 {
+		count := len(entries)
+		resp.KeyCount = &count
+	}
+	writeS3XML(c, http.StatusOK, resp)
+	return nil
+}
+
+// s3URLEncodeKey encodes a key for encoding-type=url, keeping the slashes readable.
+func s3URLEncodeKey(key string) string {
+	parts := strings.Split(key, "/")
+	for i, part := range parts {
+		parts[i] = s3URIEncode(part)
+	}
+	return strings.Join(parts, "/")
+}
+
+// removeStaleS3Staging drops PUT bodies that were never moved into place, e.g. after a crash.
+func removeStaleS3Staging(before time.Time) {
+	dir := s3StagingPath(s3StagingTempDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || info.ModTime().After(before) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(dir, entry.Name())); err != nil {
+			klog.Warningf("remove stale s3 staging file %s: %v", entry.Name(), err)
+		}
+	}
+}
@@ -0,0 +1,381 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"k8s.io/klog/v2"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/bizerr"
+	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/internal/util"
+)
+
+const (
+	// trashRootDir lives under the storage root but outside every space, so trashed data is
+	// not reachable through logical paths. It stays charged to its space until purged.
+	trashRootDir = ".crater-trash"
+	// trashCronJobName is the cleaner job that expires trashed items, see cleaner.CLEAN_STORAGE_TRASH_JOB.
+	trashCronJobName = "clean-storage-trash"
+	trashPurgeBatch  = 100
+)
+
+var (
+	errTrashItemNotFound = errors.New("trash item not found")
+	errRestoreTarget     = errors.New("the restore target already exists")
+)
+
+// trashPath returns the real path a trashed item is kept at.
+func trashPath(space quotaSpace, id string) string {
+	return path.Join(spaceTrashPath(space), id)
+}
+
+// spaceTrashPath returns the real path holding the trashed items of a space.
+func spaceTrashPath(space quotaSpace) string {
+	return path.Join(trashRootDir, string(space.Kind), space.Space)
+}
+
+// trashLogicalPath maps the original real path of an item back to the logical path its owner
+// saw, e.g. users/alice/data/a.txt becomes user/data/a.txt.
+func trashLogicalPath(item *model.StorageTrashItem, spaceRoot string) string {
+	root := model.UserPath
+	if item.Kind == model.StorageSpaceAccount {
+		root = model.AccountPath
+	}
+	rest, found := strings.CutPrefix(cleanURLPath(item.OriginalPath), cleanURLPath(spaceRoot)+"/")
+	if !found {
+		return cleanURLPath(item.OriginalPath)
+	}
+	return root + "/" + rest
+}
+
+// moveToTrash moves a file or directory of a user or account space into the trash. Its usage
+// stays charged to the space, so deleting cannot be used to park data beyond the quota.
+func moveToTrash(ctx context.Context, realPath string, space quotaSpace, deletedBy uint) (*model.StorageTrashItem, error) {
+	src := localPath(realPath)
+	stat, err := os.Lstat(src)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := measureTree(src)
+	if err != nil {
+		return nil, err
+	}
+	id, err := newRandomID()
+	if err != nil {
+		return nil, err
+	}
+	item := &model.StorageTrashItem{
+		Kind:         space.Kind,
+		Space:        space.Space,
+		OriginalPath: cleanURLPath(realPath),
+		TrashPath:    trashPath(space, id),
+		IsDir:        stat.IsDir(),
+		Bytes:        usage.Bytes,
+		Inodes:       usage.Inodes,
+		DeletedBy:    deletedBy,
+		Status:       model.StorageTrashTrashed,
+		TrashedAt:    time.Now(),
+	}
+	t := query.StorageTrashItem
+	if err = t.WithContext(ctx).Create(item); err != nil {
+		return nil, err
+	}
+	dst := localPath(item.TrashPath)
+	if err = os.MkdirAll(filepath.Dir(dst), os.ModePerm); err == nil {
+		err = os.Rename(src, dst)
+	}
+	if err != nil {
+		if _, delErr := t.WithContext(ctx).Unscoped().Where(t.ID.Eq(item.ID)).Delete(); delErr != nil {
+			klog.Warningf("drop trash record %d: %v", item.ID, delErr)
+		}
+		return nil, err
+	}
+	return item, nil
+}
+
+// trashRetention reads the retention of the clean-storage-trash cronjob. It reports false
+// when the job is missing or suspended, in which case trashed items never expire.
+func trashRetention(ctx context.Context) (time.Duration, bool) {
+	cjc := query.CronJobConfig
+	cfg, err := cjc.WithContext(ctx).Where(cjc.Name.Eq(trashCronJobName)).First()
+	if err != nil || cfg.IsSuspended() {
+		return 0, false
+	}
+	var req struct {
+		RetentionDays int `json:"retentionDays"`
+	}
+	if err = json.Unmarshal(cfg.Config, &req); err != nil || req.RetentionDays <= 0 {
+		return 0, false
+	}
+	return time.Duration(req.RetentionDays) * 24 * time.Hour, true
+}
+
+// purgeExpiredTrash removes the data of all items the cleaner has marked as expired and
+// releases their usage from the space quota. Items that fail to purge are retried next run.
+func purgeExpiredTrash(ctx context.Context) {
+	t := query.StorageTrashItem
+	var lastID uint
+	for {
+		items, err := t.WithContext(ctx).
+			Where(t.Status.Eq(string(model.StorageTrashExpired)), t.ID.Gt(lastID)).
+			Order(t.ID).Limit(trashPurgeBatch).Find()
+		if err != nil {
+			klog.Errorf("list expired trash items: %v", err)
+			return
+		}
+		for _, item := range items {
+			lastID = item.ID
+			purgeTrashItem(ctx, item)
+		}
+		if len(items) < trashPurgeBatch {
+			return
+		}
+	}
+}
+
+func purgeTrashItem(ctx context.Context, item *model.StorageTrashItem) {
+	if err := os.RemoveAll(localPath(item.TrashPath)); err != nil {
+		klog.Warningf("purge trash item %d: %v", item.ID, err)
+		return
+	}
+	t := query.StorageTrashItem
+	if _, err := t.WithContext(ctx).Where(t.ID.Eq(item.ID)).
+		UpdateSimple(t.Status.Value(string(model.StorageTrashPurged)), t.PurgedAt.Value(time.Now())); err != nil {
+		klog.Warningf("mark trash item %d purged: %v", item.ID, err)
+		return
+	}
+	space := quotaSpace{Kind: item.Kind, Space: item.Space}
+	if err := applyUsageDelta(ctx, space, spaceUsage{Bytes: -item.Bytes, Inodes: -item.Inodes}); err != nil {
+		klog.Warningf("update storage usage of %s space %s: %v", space.Kind, space.Space, err)
+	}
+}
+
+// restoreQuotaChange moves the usage of a trashed item, which is still charged to its space,
+// to the space of the restore target.
+func restoreQuotaChange(item *model.StorageTrashItem, realPath string) *quotaChange {
+	change := &quotaChange{
+		method: "MOVE",
+		src:    quotaSpace{Kind: item.Kind, Space: item.Space},
+		srcOK:  true,
+		moved:  spaceUsage{Bytes: item.Bytes, Inodes: item.Inodes},
+	}
+	change.dst, change.dstOK = chargedSpace(realPath)
+	return change
+}
+
+type TrashItemResp struct {
+	ID        uint                   `json:"id"`
+	Kind      model.StorageSpaceKind `json:"kind"`
+	Path      string                 `json:"path"`
+	Name      string                 `json:"name"`
+	IsDir     bool                   `json:"isDir"`
+	Bytes     int64                  `json:"bytes"`
+	Inodes    int64                  `json:"inodes"`
+	TrashedAt time.Time              `json:"trashedAt"`
+	ExpiresAt *time.Time             `json:"expiresAt,omitempty"`
+}
+
+func newTrashItemResp(item *model.StorageTrashItem, retention time.Duration, expires bool) TrashItemResp {
+	logical := trashLogicalPath(item, spaceRootPath(quotaSpace{Kind: item.Kind, Space: item.Space}))
+	resp := TrashItemResp{
+		ID:        item.ID,
+		Kind:      item.Kind,
+		Path:      logical,
+		Name:      path.Base(logical),
+		IsDir:     item.IsDir,
+		Bytes:     item.Bytes,
+		Inodes:    item.Inodes,
+		TrashedAt: item.TrashedAt,
+	}
+	if expires {
+		expiresAt := item.TrashedAt.Add(retention)
+		resp.ExpiresAt = &expiresAt
+	}
+	return resp
+}
+
+// ListTrash lists the trashed items of the caller's user space and current account space.
+func ListTrash(c *gin.Context) {
+	jwttoken, err := CheckJWTToken(c)
+	if err != nil {
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+		return
+	}
+	spaces, err := callerSpaces(c, jwttoken)
+	if err != nil {
+		resputil.HandleError(c, bizerr.NotFound.DataBaseNotFound.Wrap(err, "user does not exist"))
+		return
+	}
+	retention, expires := trashRetention(c)
+	resp := []TrashItemResp{}
+	t := query.StorageTrashItem
+	for _, space := range spaces {
+		items, err := t.WithContext(c).
+			Where(t.Kind.Eq(string(space.Kind)), t.Space.Eq(space.Space), t.Status.Eq(string(model.StorageTrashTrashed))).
+			Order(t.TrashedAt.Desc()).Find()
+		if err != nil {
+			resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "failed to list trash"))
+			return
+		}
+		for _, item := range items {
+			resp = append(resp, newTrashItemResp(item, retention, expires))
+		}
+	}
+	resputil.Success(c, resp)
+}
+
+// trashItemAllowed checks that the caller may restore an item: admins may restore anything,
+// others only items of their own space or, with write access, of their current account.
+func trashItemAllowed(ctx context.Context, item *model.StorageTrashItem, token util.JWTMessage) bool {
+	if token.RolePlatform == model.RoleAdmin {
+		return true
+	}
+	if item.Kind == model.StorageSpaceAccount && model.FilePermission(token.AccountAccessMode) != model.ReadWrite {
+		return false
+	}
+	spaces, err := callerSpaces(ctx, token)
+	if err != nil {
+		return false
+	}
+	for _, space := range spaces {
+		if space.Kind == item.Kind && space.Space == item.Space {
+			return true
+		}
+	}
+	return false
+}
+
+type RestoreTrashReq struct {
+	// Path is an optional logical destination; the original location is used when empty.
+	Path string `json:"path"`
+}
+
+// RestoreTrash moves a trashed item back to its original location or to a new path.
+func RestoreTrash(c *gin.Context) {
+	jwttoken, err := CheckJWTToken(c)
+	if err != nil {
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+		return
+	}
+	var req RestoreTrashReq
+	if c.Request.ContentLength != 0 {
+		if err = c.ShouldBindJSON(&req); err != nil {
+			resputil.HandleError(c, bizerr.BadRequest.InvalidRequest.Wrap(err, err.Error()))
+			return
+		}
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.ParameterError.Wrap(err, "invalid trash item id"))
+		return
+	}
+	t := query.StorageTrashItem
+	item, err := t.WithContext(c).Where(t.ID.Eq(uint(id)), t.Status.Eq(string(model.StorageTrashTrashed))).First()
+	if err == nil && !trashItemAllowed(c, item, jwttoken) {
+		err = errTrashItemNotFound
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, errTrashItemNotFound) {
+		resputil.HandleError(c, bizerr.NotFound.DataBaseNotFound.Wrap(err, errTrashItemNotFound.Error()))
+		return
+	} else if err != nil {
+		resputil.HandleError(c, bizerr.Internal.DatabaseError.Wrap(err, "failed to load trash item"))
+		return
+	}
+	realPath := item.OriginalPath
+	if req.Path != "" {
+		realPath, err = writableUploadPath(c, req.Path, jwttoken)
+		if errors.Is(err, errNoWritePermission) {
+			resputil.HTTPError(c, http.StatusUnauthorized, err.Error(), resputil.NotSpecified)
+			return
+		} else if err != nil {
+			resputil.HandleError(c, bizerr.BadRequest.ParameterError.Wrap(err, err.Error()))
+			return
+		}
+	}
+	err = restoreFromTrash(c, item, realPath)
+	switch {
+	case errors.Is(err, errRestoreTarget), errors.Is(err, errTrashItemNotFound):
+		resputil.HandleError(c, bizerr.Conflict.ResourceStatusError.Wrap(err, err.Error()))
+	case errors.Is(err, errStorageQuotaExceeded):
+		resputil.HTTPError(c, http.StatusInsufficientStorage, err.Error(), resputil.NotSpecified)
+	case err != nil:
+		resputil.HandleError(c, bizerr.Internal.FileSystemError.Wrap(err, "failed to restore trash item"))
+	default:
+		resp := newTrashItemResp(item, 0, false)
+		if req.Path != "" {
+			resp.Path = cleanURLPath(req.Path)
+			resp.Name = path.Base(resp.Path)
+		}
+		resputil.Success(c, resp)
+	}
+}
+
+// restoreFromTrash claims the item, so concurrent restores cannot both succeed, and moves it
+// to realPath. Missing parent directories are recreated.
+func restoreFromTrash(ctx context.Context, item *model.StorageTrashItem, realPath string) error {
+	dst := localPath(realPath)
+	if _, err := os.Lstat(dst); err == nil {
+		return errRestoreTarget
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	change := restoreQuotaChange(item, realPath)
+	if err := change.admit(ctx, 0); err != nil {
+		return err
+	}
+	t := query.StorageTrashItem
+	now := time.Now()
+	info, err := t.WithContext(ctx).Where(t.ID.Eq(item.ID), t.Status.Eq(string(model.StorageTrashTrashed))).
+		UpdateSimple(t.Status.Value(string(model.StorageTrashRestored)), t.RestoredAt.Value(now))
+	if err != nil {
+		return err
+	}
+	if info.RowsAffected == 0 {
+		return errTrashItemNotFound
+	}
+	if err = os.MkdirAll(filepath.Dir(dst), model.RWXFolderPerm); err == nil {
+		err = os.Rename(localPath(item.TrashPath), dst)
+	}
+	if err != nil {
+		if _, revertErr := t.WithContext(ctx).Where(t.ID.Eq(item.ID)).
+			UpdateSimple(t.Status.Value(string(model.StorageTrashTrashed)), t.RestoredAt.Null()); revertErr != nil {
+			klog.Warningf("revert trash item %d: %v", item.ID, revertErr)
+		}
+		return err
+	}
+	change.commit(ctx)
+	return nil
+}
+
+// trashWebDAVDelete answers a WebDAV DELETE in a user or account space by moving the target
+// into the trash instead of removing it.
+func trashWebDAVDelete(c *gin.Context, realPath string, space quotaSpace, token util.JWTMessage) {
+	_, err := moveToTrash(c, realPath, space, token.UserID)
+	if errors.Is(err, os.ErrNotExist) {
+		c.Status(http.StatusNotFound)
+		return
+	} else if err != nil {
+		klog.Warningf("move %s to trash: %v", realPath, err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func RegisterTrash(webdavGroup *gin.RouterGroup) {
+	webdavGroup.GET("/trash", ListTrash)
+	webdavGroup.POST("/trash/:id/restore", RestoreTrash)
+}
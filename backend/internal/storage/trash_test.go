@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/pkg/config"
+)
+
+func TestTrashLogicalPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		kind      model.StorageSpaceKind
+		original  string
+		spaceRoot string
+		want      string
+	}{
+		{name: "user file", kind: model.StorageSpaceUser, original: "users/alice/data/a.txt", spaceRoot: "users/alice", want: "user/data/a.txt"},
+		{name: "account dir", kind: model.StorageSpaceAccount, original: "/accounts/q1/ckpt", spaceRoot: "/accounts/q1", want: "account/ckpt"},
+		{name: "outside space", kind: model.StorageSpaceUser, original: "users/bob/a.txt", spaceRoot: "users/alice", want: "users/bob/a.txt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			item := &model.StorageTrashItem{Kind: tt.kind, OriginalPath: tt.original}
+			if got := trashLogicalPath(item, tt.spaceRoot); got != tt.want {
+				t.Fatalf("trashLogicalPath(%q) = %q, want %q", tt.original, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrashPathIsNotCharged(t *testing.T) {
+	t.Parallel()
+
+	got := trashPath(quotaSpace{Kind: model.StorageSpaceUser, Space: "alice"}, "abc")
+	if got != ".crater-trash/user/alice/abc" {
+		t.Fatalf("trashPath = %q", got)
+	}
+	if space, ok := spaceOfPath(got, "users", "accounts"); ok {
+		t.Fatalf("trash path charged to %+v", space)
+	}
+}
+
+func TestRestoreQuotaChange(t *testing.T) {
+	t.Parallel()
+
+	prefix := config.GetConfig().Storage.Prefix
+	alice := quotaSpace{Kind: model.StorageSpaceUser, Space: "alice"}
+	q1 := quotaSpace{Kind: model.StorageSpaceAccount, Space: "q1"}
+	item := &model.StorageTrashItem{Kind: alice.Kind, Space: alice.Space, Bytes: 30, Inodes: 3}
+
+	if got := restoreQuotaChange(item, prefix.User+"/alice/a.txt").deltas(spaceUsage{}); len(got) != 0 {
+		t.Fatalf("restore into the owning space deltas = %+v, want none", got)
+	}
+	got := restoreQuotaChange(item, prefix.Account+"/q1/a.txt").deltas(spaceUsage{})
+	if got[alice] != (spaceUsage{Bytes: -30, Inodes: -3}) || got[q1] != (spaceUsage{Bytes: 30, Inodes: 3}) {
+		t.Fatalf("restore into another space deltas = %+v", got)
+	}
+}
@@ -0,0 +1,480 @@
+package storage
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/klog/v2"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/internal/bizerr"
+	"github.com/raids-lab/crater/internal/resputil"
+	"github.com/raids-lab/crater/internal/util"
+)
+
+// Resumable uploads are staged under the storage root, outside every logical space, and
+// moved into place on completion. The offset of an upload is the size of its staged data.
+const (
+	uploadStagingDir   = ".crater-uploads"
+	uploadInfoFile     = "info.json"
+	uploadDataFile     = "data"
+	uploadOffsetHeader = "Upload-Offset"
+	// UploadRetention is how long an unfinished upload is kept after its last chunk.
+	UploadRetention = 7 * 24 * time.Hour
+)
+
+var (
+	errUploadNotFound       = errors.New("upload not found")
+	errUploadOffsetMismatch = errors.New("upload offset mismatch")
+	errUploadTooLarge       = errors.New("chunk exceeds the declared upload size")
+	errUploadIncomplete     = errors.New("upload is incomplete")
+	errUploadChecksum       = errors.New("upload checksum mismatch")
+	uploadIDPattern         = regexp.MustCompile(`^[0-9a-f]{32}$`)
+	uploadAdmitMu           sync.Mutex
+	sha256Pattern           = regexp.MustCompile(`^[0-9a-f]{64}$`)
+)
+
+// uploadInfo is persisted next to the staged data so uploads survive server restarts.
+type uploadInfo struct {
+	ID        string    `json:"id"`
+	UserID    uint      `json:"userId"`
+	Path      string    `json:"path"`
+	RealPath  string    `json:"realPath"`
+	Size      int64     `json:"size"`
+	SHA256    string    `json:"sha256"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// uploadStore keeps the staged uploads under one directory.
+type uploadStore struct {
+	root string
+}
+
+func defaultUploadStore() *uploadStore {
+	return &uploadStore{root: filepath.Join(storageRootDir, uploadStagingDir)}
+}
+
+func (s *uploadStore) dir(id string) string {
+	return filepath.Join(s.root, id)
+}
+
+func newRandomID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func (s *uploadStore) create(info *uploadInfo) error {
+	dir := s.dir(info.ID)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, uploadInfoFile), data, 0o600); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filepath.Join(dir, uploadDataFile), os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// load returns the upload and its current offset.
+func (s *uploadStore) load(id string) (*uploadInfo, int64, error) {
+	if !uploadIDPattern.MatchString(id) {
+		return nil, 0, errUploadNotFound
+	}
+	data, err := os.ReadFile(filepath.Join(s.dir(id), uploadInfoFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, 0, errUploadNotFound
+	} else if err != nil {
+		return nil, 0, err
+	}
+	var info uploadInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, 0, err
+	}
+	stat, err := os.Stat(filepath.Join(s.dir(id), uploadDataFile))
+	if err != nil {
+		return nil, 0, err
+	}
+	return &info, stat.Size(), nil
+}
+
+func (s *uploadStore) list(userID uint) ([]*uploadInfo, []int64, error) {
+	entries, err := os.ReadDir(s.root)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+	var infos []*uploadInfo
+	var offsets []int64
+	for _, entry := range entries {
+		info, offset, err := s.load(entry.Name())
+		if err != nil || info.UserID != userID {
+			continue
+		}
+		infos = append(infos, info)
+		offsets = append(offsets, offset)
+	}
+	return infos, offsets, nil
+}
+
+// write appends a chunk at offset. Whatever part of the chunk arrives before the client
+// disconnects is kept, so the next attempt resumes from the returned offset.
+func (s *uploadStore) write(info *uploadInfo, offset int64, chunk io.Reader) (int64, error) {
+	path := filepath.Join(s.dir(info.ID), uploadDataFile)
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	stat, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	if stat.Size() != offset {
+		return stat.Size(), errUploadOffsetMismatch
+	}
+	if _, err = f.Seek(offset, io.SeekStart); err != nil {
+		return offset, err
+	}
+	// Read one byte past the declared size to detect oversized chunks.
+	written, copyErr := io.Copy(f, io.LimitReader(chunk, info.Size-offset+1))
+	end := offset + written
+	if end > info.Size {
+		if err := f.Truncate(offset); err != nil {
+			return offset, err
+		}
+		return offset, errUploadTooLarge
+	}
+	return end, copyErr
+}
+
+// verify checks that the staged data is complete and matches the declared checksum.
+func (s *uploadStore) verify(info *uploadInfo) error {
+	f, err := os.Open(filepath.Join(s.dir(info.ID), uploadDataFile))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	hash := sha256.New()
+	size, err := io.Copy(hash, f)
+	if err != nil {
+		return err
+	}
+	if size != info.Size {
+		return fmt.Errorf("%w: %d of %d bytes", errUploadIncomplete, size, info.Size)
+	}
+	if sum := hex.EncodeToString(hash.Sum(nil)); sum != info.SHA256 {
+		return fmt.Errorf("%w: got %s", errUploadChecksum, sum)
+	}
+	return nil
+}
+
+// finalize moves the staged data to dest and drops the upload.
+func (s *uploadStore) finalize(info *uploadInfo, dest string) error {
+	if err := os.Rename(filepath.Join(s.dir(info.ID), uploadDataFile), dest); err != nil {
+		return err
+	}
+	return s.remove(info.ID)
+}
+
+func (s *uploadStore) remove(id string) error {
+	return os.RemoveAll(s.dir(id))
+}
+
+// reserved sums the declared size of the unfinished uploads into space. An admitted upload
+// holds its room until it completes, is aborted or goes stale.
+func (s *uploadStore) reserved(space quotaSpace) (spaceUsage, error) {
+	var usage spaceUsage
+	entries, err := os.ReadDir(s.root)
+	if errors.Is(err, os.ErrNotExist) {
+		return usage, nil
+	} else if err != nil {
+		return usage, err
+	}
+	for _, entry := range entries {
+		info, _, err := s.load(entry.Name())
+		if err != nil {
+			continue
+		}
+		if target, ok := chargedSpace(info.RealPath); ok && target == space {
+			usage.Bytes += info.Size
+			usage.Inodes++
+		}
+	}
+	return usage, nil
+}
+
+// removeStale drops uploads whose data has not been written since before.
+func (s *uploadStore) removeStale(before time.Time) {
+	entries, err := os.ReadDir(s.root)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		stat, err := os.Stat(filepath.Join(s.root, entry.Name(), uploadDataFile))
+		if err == nil && stat.ModTime().After(before) {
+			continue
+		}
+		if err := s.remove(entry.Name()); err != nil {
+			klog.Warningf("remove stale upload %s: %v", entry.Name(), err)
+		}
+	}
+}
+
+type CreateUploadReq struct {
+	// Path is the logical destination, such as user/checkpoints/model.bin.
+	Path   string `json:"path" binding:"required"`
+	Size   int64  `json:"size" binding:"min=0"`
+	SHA256 string `json:"sha256" binding:"required"`
+}
+
+type UploadResp struct {
+	ID        string    `json:"id"`
+	Path      string    `json:"path"`
+	Size      int64     `json:"size"`
+	Offset    int64     `json:"offset"`
+	SHA256    string    `json:"sha256"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func newUploadResp(info *uploadInfo, offset int64) UploadResp {
+	return UploadResp{ID: info.ID, Path: info.Path, Size: info.Size, Offset: offset, SHA256: info.SHA256, CreatedAt: info.CreatedAt}
+}
+
+// writableUploadPath resolves a logical destination the caller may write to.
+func writableUploadPath(c *gin.Context, path string, token util.JWTMessage) (string, error) {
+	if getFirstToken(path) == "" || len(splitURLPath(path)) < 2 {
+		return "", errors.New("the destination must be a file inside a space")
+	}
+	if GetPermission(path, token, c) != model.ReadWrite {
+		return "", errNoWritePermission
+	}
+	return Redirect(c, path, token)
+}
+
+// CreateUpload starts a resumable upload to a logical path.
+func CreateUpload(c *gin.Context) {
+	jwttoken, err := CheckJWTToken(c)
+	if err != nil {
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+		return
+	}
+	var req CreateUploadReq
+	if err = c.ShouldBindJSON(&req); err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.InvalidRequest.Wrap(err, err.Error()))
+		return
+	}
+	req.SHA256 = strings.ToLower(req.SHA256)
+	if !sha256Pattern.MatchString(req.SHA256) {
+		resputil.HandleError(c, bizerr.BadRequest.ParameterError.New("sha256 must be 64 hex characters"))
+		return
+	}
+	realPath, err := writableUploadPath(c, req.Path, jwttoken)
+	if errors.Is(err, errNoWritePermission) {
+		resputil.HTTPError(c, http.StatusUnauthorized, err.Error(), resputil.NotSpecified)
+		return
+	} else if err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.ParameterError.Wrap(err, err.Error()))
+		return
+	}
+	store := defaultUploadStore()
+	// Uploads into the same space are admitted one at a time, each against the room the
+	// unfinished ones already hold.
+	uploadAdmitMu.Lock()
+	defer uploadAdmitMu.Unlock()
+	change := prepareQuotaChange(http.MethodPut, realPath, "")
+	if space, ok := chargedSpace(realPath); ok && change != nil {
+		if change.reserved, err = store.reserved(space); err != nil {
+			resputil.HandleError(c, bizerr.Internal.FileSystemError.Wrap(err, "failed to list unfinished uploads"))
+			return
+		}
+	}
+	if err = change.admit(c, req.Size); err != nil {
+		resputil.HTTPError(c, http.StatusInsufficientStorage, err.Error(), resputil.NotSpecified)
+		return
+	}
+	id, err := newRandomID()
+	if err != nil {
+		resputil.HandleError(c, bizerr.Internal.ServiceError.Wrap(err, "failed to create upload"))
+		return
+	}
+	info := &uploadInfo{
+		ID:        id,
+		UserID:    jwttoken.UserID,
+		Path:      cleanURLPath(req.Path),
+		RealPath:  realPath,
+		Size:      req.Size,
+		SHA256:    req.SHA256,
+		CreatedAt: time.Now(),
+	}
+	if err = store.create(info); err != nil {
+		resputil.HandleError(c, bizerr.Internal.FileSystemError.Wrap(err, "failed to create upload"))
+		return
+	}
+	resputil.Success(c, newUploadResp(info, 0))
+}
+
+// loadOwnUpload resolves the :id parameter to an upload of the caller.
+func loadOwnUpload(c *gin.Context) (util.JWTMessage, *uploadInfo, int64, bool) {
+	jwttoken, err := CheckJWTToken(c)
+	if err != nil {
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+		return jwttoken, nil, 0, false
+	}
+	info, offset, err := defaultUploadStore().load(c.Param("id"))
+	if err == nil && info.UserID != jwttoken.UserID {
+		err = errUploadNotFound
+	}
+	if errors.Is(err, errUploadNotFound) {
+		resputil.HandleError(c, bizerr.NotFound.DataBaseNotFound.Wrap(err, err.Error()))
+		return jwttoken, nil, 0, false
+	} else if err != nil {
+		resputil.HandleError(c, bizerr.Internal.FileSystemError.Wrap(err, "failed to load upload"))
+		return jwttoken, nil, 0, false
+	}
+	return jwttoken, info, offset, true
+}
+
+// ListUploads lists the caller's unfinished uploads, so clients can resume them.
+func ListUploads(c *gin.Context) {
+	jwttoken, err := CheckJWTToken(c)
+	if err != nil {
+		resputil.Error(c, err.Error(), resputil.NotSpecified)
+		return
+	}
+	infos, offsets, err := defaultUploadStore().list(jwttoken.UserID)
+	if err != nil {
+		resputil.HandleError(c, bizerr.Internal.FileSystemError.Wrap(err, "failed to list uploads"))
+		return
+	}
+	resp := make([]UploadResp, 0, len(infos))
+	for i := range infos {
+		resp = append(resp, newUploadResp(infos[i], offsets[i]))
+	}
+	resputil.Success(c, resp)
+}
+
+// GetUpload returns the current offset of an upload.
+func GetUpload(c *gin.Context) {
+	_, info, offset, ok := loadOwnUpload(c)
+	if !ok {
+		return
+	}
+	resputil.Success(c, newUploadResp(info, offset))
+}
+
+// PatchUpload appends the request body at the offset given in the Upload-Offset header.
+func PatchUpload(c *gin.Context) {
+	_, info, _, ok := loadOwnUpload(c)
+	if !ok {
+		return
+	}
+	offset, err := strconv.ParseInt(c.GetHeader(uploadOffsetHeader), 10, 64)
+	if err != nil || offset < 0 {
+		resputil.HandleError(c, bizerr.BadRequest.MissingParameter.New("Upload-Offset header must be a non-negative integer"))
+		return
+	}
+	end, err := defaultUploadStore().write(info, offset, c.Request.Body)
+	c.Header(uploadOffsetHeader, strconv.FormatInt(end, 10))
+	switch {
+	case errors.Is(err, errUploadOffsetMismatch):
+		resputil.HandleError(c, bizerr.Conflict.ResourceStatusError.New(
+			fmt.Sprintf("%s: the upload is at offset %d", err.Error(), end)))
+	case errors.Is(err, errUploadTooLarge):
+		resputil.HandleError(c, bizerr.PayloadTooLarge.PayloadTooLarge.Wrap(err, err.Error()))
+	case err != nil:
+		resputil.HandleError(c, bizerr.Internal.FileSystemError.Wrap(err, "failed to write chunk"))
+	default:
+		resputil.Success(c, newUploadResp(info, end))
+	}
+}
+
+// CompleteUpload verifies the checksum and moves the upload to its destination.
+func CompleteUpload(c *gin.Context) {
+	jwttoken, info, offset, ok := loadOwnUpload(c)
+	if !ok {
+		return
+	}
+	// Permissions may have changed since the upload was created.
+	realPath, err := writableUploadPath(c, info.Path, jwttoken)
+	if errors.Is(err, errNoWritePermission) {
+		resputil.HTTPError(c, http.StatusUnauthorized, err.Error(), resputil.NotSpecified)
+		return
+	} else if err != nil {
+		resputil.HandleError(c, bizerr.BadRequest.ParameterError.Wrap(err, err.Error()))
+		return
+	}
+	store := defaultUploadStore()
+	if err = store.verify(info); errors.Is(err, errUploadIncomplete) || errors.Is(err, errUploadChecksum) {
+		resputil.HandleError(c, bizerr.Conflict.ResourceStatusError.Wrap(err, err.Error()))
+		return
+	} else if err != nil {
+		resputil.HandleError(c, bizerr.Internal.FileSystemError.Wrap(err, "failed to verify upload"))
+		return
+	}
+	change := prepareQuotaChange(http.MethodPut, realPath, "")
+	if err = change.admit(c, info.Size); err != nil {
+		resputil.HTTPError(c, http.StatusInsufficientStorage, err.Error(), resputil.NotSpecified)
+		return
+	}
+	dest := localPath(realPath)
+	if stat, statErr := os.Stat(filepath.Dir(dest)); statErr != nil || !stat.IsDir() {
+		resputil.HandleError(c, bizerr.Conflict.ResourceStatusError.New("the parent directory of the destination does not exist"))
+		return
+	}
+	if err = store.finalize(info, dest); err != nil {
+		resputil.HandleError(c, bizerr.Internal.FileSystemError.Wrap(err, "failed to move upload into place"))
+		return
+	}
+	if err = os.Chmod(dest, model.RWXFolderPerm); err != nil {
+		klog.Warningf("chmod uploaded file %s: %v", dest, err)
+	}
+	change.commit(c)
+	resputil.Success(c, newUploadResp(info, offset))
+}
+
+// AbortUpload drops an unfinished upload.
+func AbortUpload(c *gin.Context) {
+	_, info, _, ok := loadOwnUpload(c)
+	if !ok {
+		return
+	}
+	if err := defaultUploadStore().remove(info.ID); err != nil {
+		resputil.HandleError(c, bizerr.Internal.FileSystemError.Wrap(err, "failed to abort upload"))
+		return
+	}
+	resputil.Success(c, nil)
+}
+
+func RegisterUpload(webdavGroup *gin.RouterGroup) {
+	webdavGroup.POST("/uploads", CreateUpload)
+	webdavGroup.GET("/uploads", ListUploads)
+	webdavGroup.GET("/uploads/:id", GetUpload)
+	webdavGroup.PATCH("/uploads/:id", PatchUpload)
+	webdavGroup.POST("/uploads/:id/complete", CompleteUpload)
+	webdavGroup.DELETE("/uploads/:id", AbortUpload)
+}
@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/pkg/config"
+)
+
+func TestUploadStoreResume(t *testing.T) {
+	t.Parallel()
+
+	store := &uploadStore{root: t.TempDir()}
+	content := "hello resumable world"
+	sum := sha256.Sum256([]byte(content))
+	info := &uploadInfo{
+		ID:     strings.Repeat("a", 32),
+		UserID: 1,
+		Size:   int64(len(content)),
+		SHA256: hex.EncodeToString(sum[:]),
+	}
+	if err := store.create(info); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	end, err := store.write(info, 0, strings.NewReader(content[:6]))
+	if err != nil || end != 6 {
+		t.Fatalf("first chunk = %d, %v; want 6", end, err)
+	}
+	// A retried chunk with a stale offset reports where the upload really is.
+	if end, err = store.write(info, 0, strings.NewReader(content[:6])); !errors.Is(err, errUploadOffsetMismatch) || end != 6 {
+		t.Fatalf("stale chunk = %d, %v; want offset mismatch at 6", end, err)
+	}
+	if err = store.verify(info); !errors.Is(err, errUploadIncomplete) {
+		t.Fatalf("verify partial upload = %v, want incomplete", err)
+	}
+	if end, err = store.write(info, 6, strings.NewReader(content[6:]+"extra")); !errors.Is(err, errUploadTooLarge) || end != 6 {
+		t.Fatalf("oversized chunk = %d, %v; want too large at 6", end, err)
+	}
+
+	// The state survives a reload, as after a server restart.
+	loaded, offset, err := store.load(info.ID)
+	if err != nil || offset != 6 || loaded.SHA256 != info.SHA256 {
+		t.Fatalf("load = %+v, %d, %v", loaded, offset, err)
+	}
+	if end, err = store.write(loaded, 6, strings.NewReader(content[6:])); err != nil || end != info.Size {
+		t.Fatalf("last chunk = %d, %v", end, err)
+	}
+	if err = store.verify(loaded); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+
+	dest := filepath.Join(t.TempDir(), "out.txt")
+	if err = store.finalize(loaded, dest); err != nil {
+		t.Fatalf("finalize: %v", err)
+	}
+	if got, _ := os.ReadFile(dest); string(got) != content {
+		t.Fatalf("finalized content = %q", got)
+	}
+	if _, _, err = store.load(info.ID); !errors.Is(err, errUploadNotFound) {
+		t.Fatalf("load after finalize = %v, want not found", err)
+	}
+}
+
+func TestUploadStoreChecksumAndCleanup(t *testing.T) {
+	t.Parallel()
+
+	store := &uploadStore{root: t.TempDir()}
+	mine := &uploadInfo{ID: strings.Repeat("b", 32), UserID: 1, Size: 3, SHA256: strings.Repeat("0", 64)}
+	other := &uploadInfo{ID: strings.Repeat("c", 32), UserID: 2, Size: 3}
+	for _, info := range []*uploadInfo{mine, other} {
+		if err := store.create(info); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	}
+	if _, err := store.write(mine, 0, strings.NewReader("abc")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := store.verify(mine); !errors.Is(err, errUploadChecksum) {
+		t.Fatalf("verify = %v, want checksum mismatch", err)
+	}
+
+	infos, offsets, err := store.list(1)
+	if err != nil || len(infos) != 1 || infos[0].ID != mine.ID || offsets[0] != 3 {
+		t.Fatalf("list(1) = %v, %v, %v", infos, offsets, err)
+	}
+	if _, _, err = store.load("../" + mine.ID); !errors.Is(err, errUploadNotFound) {
+		t.Fatalf("load with a path = %v, want not found", err)
+	}
+
+	old := time.Now().Add(-2 * UploadRetention)
+	if err = os.Chtimes(filepath.Join(store.dir(other.ID), uploadDataFile), old, old); err != nil {
+		t.Fatal(err)
+	}
+	store.removeStale(time.Now().Add(-UploadRetention))
+	if _, _, err = store.load(other.ID); !errors.Is(err, errUploadNotFound) {
+		t.Fatalf("stale upload still present: %v", err)
+	}
+	if _, _, err = store.load(mine.ID); err != nil {
+		t.Fatalf("fresh upload removed: %v", err)
+	}
+}
+
+func TestUploadStoreReserved(t *testing.T) {
+	t.Parallel()
+
+	store := &uploadStore{root: t.TempDir()}
+	prefix := config.GetConfig().Storage.Prefix
+	for i, target := range []struct {
+		realPath string
+		size     int64
+	}{
+		{realPath: prefix.User + "/alice/a.bin", size: 10},
+		{realPath: prefix.User + "/alice/dir/b.bin", size: 5},
+		{realPath: prefix.User + "/bob/c.bin", size: 7},
+	} {
+		info := &uploadInfo{ID: strings.Repeat(string(rune('a'+i)), 32), UserID: 1, RealPath: target.realPath, Size: target.size}
+		if err := store.create(info); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	}
+	got, err := store.reserved(quotaSpace{Kind: model.StorageSpaceUser, Space: "alice"})
+	if err != nil || got != (spaceUsage{Bytes: 15, Inodes: 2}) {
+		t.Fatalf("reserved = %+v, %v; want the two uploads into alice", got, err)
+	}
+}
@@ -1,8 +1,19 @@
 package util
 
-import "strings"
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gen/field"
+)
 
 func ContainsPattern(search string) string {
 	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(strings.ToLower(search))
 	return "%" + escaped + "%"
 }
+
+// NotExpired matches dataset and image shares that have no expiry or whose expiry has not passed.
+// Expired shares stay in the table until the revoke-expired-shares cron job removes them.
+func NotExpired(expiresAt field.Time) field.Expr {
+	return field.Or(expiresAt.IsNull(), expiresAt.Gt(time.Now()))
+}
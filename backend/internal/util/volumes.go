@@ -34,6 +34,8 @@ type VolumeMount struct {
 	DatasetID uint       `json:"datasetID"`
 	SubPath   string     `json:"subPath"`
 	MountPath string     `json:"mountPath"`
+	// DatasetVersion mounts the read-only snapshot of a published version instead of the live dataset
+	DatasetVersion string `json:"datasetVersion,omitempty"`
 }
 
 // resolveVolumeMount resolves the subpath and volume type based on the mount configuration
@@ -54,6 +56,19 @@ func ResolveVolumeMount(c context.Context, token JWTMessage, vm VolumeMount, sou
 		if err != nil {
 			return v1.VolumeMount{}, err
 		}
+		// A dataset version is an immutable snapshot, so it is always mounted read-only
+		if vm.DatasetVersion != "" {
+			versionPath, err := GetSubPathByDatasetVersion(c, vm.DatasetID, vm.DatasetVersion)
+			if err != nil {
+				return v1.VolumeMount{}, err
+			}
+			return v1.VolumeMount{
+				Name:      roxPVCName,
+				SubPath:   versionPath,
+				MountPath: vm.MountPath,
+				ReadOnly:  true,
+			}, nil
+		}
 		// If editable is true, use RWX PVC
 		if editable {
 			return v1.VolumeMount{
@@ -184,7 +199,7 @@ func GetSubPathByDatasetVolume(c context.Context,
 	}
 	editable = dataset.Extra.Data().Editable
 	// Find()方法没找到不会报err，而是返回nil
-	accountDatasets, err := ad.WithContext(c).Where(ad.DatasetID.Eq(datasetID)).Find()
+	accountDatasets, err := ad.WithContext(c).Where(ad.DatasetID.Eq(datasetID), NotExpired(ad.ExpiresAt)).Find()
 	if err != nil {
 		return "", false, err
 	}
@@ -194,10 +209,24 @@ func GetSubPathByDatasetVolume(c context.Context,
 			return dataset.URL, editable, nil
 		}
 	}
-	_, err = ud.WithContext(c).Where(ud.UserID.Eq(userID), ud.DatasetID.Eq(datasetID)).First()
+	_, err = ud.WithContext(c).Where(ud.UserID.Eq(userID), ud.DatasetID.Eq(datasetID), NotExpired(ud.ExpiresAt)).First()
 	if err != nil {
 		return "", false, err
 	}
 
 	return dataset.URL, editable, nil
 }
+
+// GetSubPathByDatasetVersion returns the snapshot path of a ready dataset version. Access to the
+// dataset itself is checked by GetSubPathByDatasetVolume.
+func GetSubPathByDatasetVersion(c context.Context, datasetID uint, version string) (string, error) {
+	v := query.DatasetVersion
+	datasetVersion, err := v.WithContext(c).Where(v.DatasetID.Eq(datasetID), v.Version.Eq(version)).First()
+	if err != nil {
+		return "", fmt.Errorf("version %s of dataset %d: %w", version, datasetID, err)
+	}
+	if datasetVersion.Status != model.DatasetVersionReady {
+		return "", fmt.Errorf("version %s of dataset %d is %s", version, datasetID, datasetVersion.Status)
+	}
+	return datasetVersion.Path, nil
+}
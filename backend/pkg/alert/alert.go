@@ -20,6 +20,7 @@ import (
 type alertMgr struct {
 	handler alertHandlerInterface
 	err     error
+	webhook *WebhookAlerter
 }
 
 var (
@@ -35,9 +36,7 @@ func GetAlertMgr() AlertInterface {
 }
 
 func initAlertMgr() *alertMgr {
-	// 初始化选择具体要使用的 alert handler
-	// 目前只支持 SMTP，下一步支持 WPS Robot
-	// 后续可以考虑从 Config 中进行配置
+	// SMTP 作为默认渠道，webhook 渠道按用户偏好选择（见 model.NotificationPreference）
 	smtpHandler, err := newSMTPAlerter()
 	if err != nil {
 		klog.Error("Init alert mgr error")
@@ -45,6 +44,7 @@ func initAlertMgr() *alertMgr {
 	return &alertMgr{
 		handler: smtpHandler,
 		err:     err,
+		webhook: newWebhookAlerter(),
 	}
 }
 
@@ -122,7 +122,34 @@ func (a *alertMgr) sendJobNotification(
 	if a.err != nil {
 		return a.err
 	}
+	return a.notifyJobOnce(ctx, jobName, alertType.String(), condition, func(info *JobInformation) error {
+		return a.handler.SendMessageTo(ctx, &info.Receiver, subject, bodyFormatter(info))
+	})
+}
+
+// sendJobNotice 按用户的通知渠道偏好依次尝试发送，见 deliverNotice
+func (a *alertMgr) sendJobNotice(
+	ctx context.Context,
+	jobName string,
+	alertType model.AlertType,
+	noticeFormatter func(info *JobInformation) *Notice,
+) error {
+	return a.notifyJobOnce(ctx, jobName, alertType.String(), nil, func(info *JobInformation) error {
+		notice := noticeFormatter(info)
+		notice.Event = alertType.String()
+		return a.deliverNotice(ctx, &info.Receiver, notice)
+	})
+}
 
+// notifyJobOnce 负责条件判断、去重与发送记录，send 负责实际投递
+// alertKey 为去重使用的类型，写入 Alert.AlertType
+func (a *alertMgr) notifyJobOnce(
+	ctx context.Context,
+	jobName string,
+	alertKey string,
+	condition func(info *JobInformation) bool,
+	send func(info *JobInformation) error,
+) error {
 	info, err := a.getJobAlertInfo(ctx, jobName)
 	if err != nil {
 		return err
@@ -135,11 +162,11 @@ func (a *alertMgr) sendJobNotification(
 
 	// 检查是否已发送过
 	alertDB := query.Alert
-	record, alertErr := alertDB.WithContext(ctx).Where(alertDB.JobName.Eq(jobName), alertDB.AlertType.Eq(alertType.String())).First()
+	record, alertErr := alertDB.WithContext(ctx).Where(alertDB.JobName.Eq(jobName), alertDB.AlertType.Eq(alertKey)).First()
 
 	if alertErr == nil && !record.AllowRepeat {
 		// 该job该type的邮件已经发送过，且不允许再发送
-		klog.Infof("job %s type %s already sent", jobName, alertType.String())
+		klog.Infof("job %s type %s already sent", jobName, alertKey)
 		return nil
 	}
 
@@ -148,8 +175,7 @@ func (a *alertMgr) sendJobNotification(
 		return alertErr
 	}
 
-	body := bodyFormatter(info)
-	if err := a.handler.SendMessageTo(ctx, &info.Receiver, subject, body); err != nil {
+	if err := send(info); err != nil {
 		return err
 	}
 
@@ -158,7 +184,7 @@ func (a *alertMgr) sendJobNotification(
 		// 1. 邮件没发送过，创建新纪录
 		newRecord := &model.Alert{
 			JobName:        jobName,
-			AlertType:      alertType.String(),
+			AlertType:      alertKey,
 			AllowRepeat:    false,
 			AlertTimestamp: utils.GetLocalTime(),
 			SendCount:      1,
@@ -179,6 +205,36 @@ func (a *alertMgr) sendJobNotification(
 	return nil
 }
 
+// deliverNotice 沿用户的渠道链依次发送，任一渠道成功即返回；全部失败时返回合并后的错误
+func (a *alertMgr) deliverNotice(ctx context.Context, receiver *model.UserAttribute, notice *Notice) error {
+	var errs []error
+	for _, channel := range receiver.NotificationChain() {
+		var err error
+		if channel.Type == model.NotificationChannelEmail {
+			err = a.sendNoticeEmail(ctx, receiver, notice)
+		} else {
+			err = a.webhook.Send(ctx, channel, receiver, notice)
+		}
+		if err == nil {
+			return nil
+		}
+		klog.Warningf("notify %s via %s failed, trying next channel: %v", receiver.Name, channel.Type, err)
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+func (a *alertMgr) sendNoticeEmail(ctx context.Context, receiver *model.UserAttribute, notice *Notice) error {
+	if a.err != nil {
+		return a.err
+	}
+	if receiver.Email == nil {
+		return fmt.Errorf("%s does not have an email address", receiver.Name)
+	}
+	body := generateHTMLEmail(receiver.Nickname, notice.Title, notice.Message, notice.URL, notice.ButtonText)
+	return a.handler.SendMessageTo(ctx, receiver, notice.Subject, body)
+}
+
 // 作业开始通知，只有当作业创建和运行间隔超过 10 分钟时才发送
 func (a *alertMgr) JobRunningAlert(ctx context.Context, jobName string) error {
 	return a.sendJobNotification(ctx, jobName, "作业已开始运行", model.JobRunningAlert,
@@ -200,16 +256,15 @@ func (a *alertMgr) JobRunningAlert(ctx context.Context, jobName string) error {
 
 // 作业失败通知
 func (a *alertMgr) JobFailureAlert(ctx context.Context, jobName string) error {
-	return a.sendJobNotification(ctx, jobName, "作业运行失败", model.JobFailedAlert,
-		nil, // 无需额外判断
-		func(info *JobInformation) string {
-			return generateHTMLEmail(
-				info.Username,
-				"作业运行失败",
-				fmt.Sprintf("您的作业 <strong>%s</strong> (ID: %s) 运行失败。请查看日志了解详细信息。", info.Name, info.JobName),
-				info.jobURL,
-				"查看失败详情",
-			)
+	return a.sendJobNotice(ctx, jobName, model.JobFailedAlert,
+		func(info *JobInformation) *Notice {
+			return &Notice{
+				Subject:    "作业运行失败",
+				Title:      "作业运行失败",
+				Message:    fmt.Sprintf("您的作业 <strong>%s</strong> (ID: %s) 运行失败。请查看日志了解详细信息。", info.Name, info.JobName),
+				URL:        info.jobURL,
+				ButtonText: "查看失败详情",
+			}
 		},
 	)
 }
@@ -264,36 +319,34 @@ func (a *alertMgr) CleanJob(ctx context.Context, jobName string, _ map[string]an
 
 // RemindLowUsageJob 发送低资源使用率告警
 func (a *alertMgr) RemindLowUsageJob(ctx context.Context, jobName string, deleteTime time.Time, _ map[string]any) error {
-	return a.sendJobNotification(ctx, jobName, "警告：作业即将被删除 - GPU利用率过低", model.LowGPUJobRemindedAlert,
-		nil,
-		func(info *JobInformation) string {
+	return a.sendJobNotice(ctx, jobName, model.LowGPUJobRemindedAlert,
+		func(info *JobInformation) *Notice {
 			deleteTimeStr := deleteTime.Format("2006-01-02 15:04:05")
-			return generateHTMLEmail(
-				info.Username,
-				"警告：作业即将被删除",
-				fmt.Sprintf("您的作业 <strong>%s</strong> (ID: %s) 申请了GPU资源，但资源利用率持续过低。<br><br><strong style='color: #e74c3c;'>系统将于 %s 自动删除该作业</strong>。<br><br>如有特殊需求，请及时联系管理员锁定作业或调整您的作业以提高资源利用率。",
+			return &Notice{
+				Subject: "警告：作业即将被删除 - GPU利用率过低",
+				Title:   "警告：作业即将被删除",
+				Message: fmt.Sprintf("您的作业 <strong>%s</strong> (ID: %s) 申请了GPU资源，但资源利用率持续过低。<br><br><strong style='color: #e74c3c;'>系统将于 %s 自动删除该作业</strong>。<br><br>如有特殊需求，请及时联系管理员锁定作业或调整您的作业以提高资源利用率。",
 					info.Name, info.JobName, deleteTimeStr),
-				info.jobURL,
-				"立即查看作业",
-			)
+				URL:        info.jobURL,
+				ButtonText: "立即查看作业",
+			}
 		},
 	)
 }
 
 // RemindLongTimeRunningJob 发送长时间运行告警
 func (a *alertMgr) RemindLongTimeRunningJob(ctx context.Context, jobName string, deleteTime time.Time, _ map[string]any) error {
-	return a.sendJobNotification(ctx, jobName, "警告：作业即将被删除 - 运行时间过长", model.LongTimeJobRemindedAlert,
-		nil,
-		func(info *JobInformation) string {
+	return a.sendJobNotice(ctx, jobName, model.LongTimeJobRemindedAlert,
+		func(info *JobInformation) *Notice {
 			deleteTimeStr := deleteTime.Format("2006-01-02 15:04:05")
-			return generateHTMLEmail(
-				info.Username,
-				"警告：作业即将被删除",
-				fmt.Sprintf("您的作业 <strong>%s</strong> (ID: %s) 已运行较长时间，达到了平台设定的运行时间上限。<br><br><strong style='color: #e74c3c;'>系统将于 %s 自动删除该作业</strong>。<br><br>如有特殊需求，请及时联系管理员锁定作业或考虑对作业进行优化以减少运行时间。",
+			return &Notice{
+				Subject: "警告：作业即将被删除 - 运行时间过长",
+				Title:   "警告：作业即将被删除",
+				Message: fmt.Sprintf("您的作业 <strong>%s</strong> (ID: %s) 已运行较长时间，达到了平台设定的运行时间上限。<br><br><strong style='color: #e74c3c;'>系统将于 %s 自动删除该作业</strong>。<br><br>如有特殊需求，请及时联系管理员锁定作业或考虑对作业进行优化以减少运行时间。",
 					info.Name, info.JobName, deleteTimeStr),
-				info.jobURL,
-				"立即查看作业",
-			)
+				URL:        info.jobURL,
+				ButtonText: "立即查看作业",
+			}
 		},
 	)
 }
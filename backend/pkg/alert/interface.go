@@ -16,6 +16,9 @@ import (
 //  4. 作业因低利用率已经被释放通知
 //  5. 作业异常的资源使用警告
 //  6. 发送邮箱验证码
+//
+// 配置了通知规则（model.NotificationRule）的用户通过 NotifyJobEvent / NotifyUserEvent 按订阅、
+// 免打扰时段与摘要合并发送；未配置规则的用户保持原有行为。
 type AlertInterface interface {
 	JobRunningAlert(ctx context.Context, jobName string) error
 	JobFailureAlert(ctx context.Context, jobName string) error
@@ -25,9 +28,12 @@ type AlertInterface interface {
 	RemindLongTimeRunningJob(ctx context.Context, jobName string, deleteTime time.Time, extra map[string]any) error
 	RemindLowUsageJob(ctx context.Context, jobName string, deleteTime time.Time, extra map[string]any) error
 	SendVerificationCode(ctx context.Context, code string, receiver *model.UserAttribute) error
+	NotifyJobEvent(ctx context.Context, job *model.Job, event model.NotificationEvent) error
+	NotifyUserEvent(ctx context.Context, userID, accountID uint, event model.NotificationEvent, notice *Notice) error
+	FlushNotificationDigests(ctx context.Context) (int, error)
 }
 
-// alertHandlerInterface 是具体的通知组件对外部提供的接口，WPS Robot 或者 SMTP 邮件通知都应该实现这两个接口
+// alertHandlerInterface 是邮件通知组件对外部提供的接口，webhook 渠道见 WebhookAlerter
 type alertHandlerInterface interface {
 	SendMessageTo(ctx context.Context, receiver *model.UserAttribute, subject, body string) error
 }
@@ -0,0 +1,252 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"k8s.io/klog/v2"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/pkg/config"
+	"github.com/raids-lab/crater/pkg/utils"
+)
+
+const notificationDigestEvent = "notification_digest"
+
+// FindNotificationRule returns the account-specific rule if present, then the user-wide one.
+// It returns nil when the user has not configured any rule.
+func FindNotificationRule(ctx context.Context, q *query.Query, userID, accountID uint) (*model.NotificationRule, error) {
+	nr := q.NotificationRule
+	rules, err := nr.WithContext(ctx).
+		Where(nr.UserID.Eq(userID), nr.AccountID.In(0, accountID)).
+		Order(nr.AccountID.Desc()).
+		Find()
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	return rules[0], nil
+}
+
+// NotifyJobEvent 发送作业事件通知，已去重。未配置规则的用户只在作业开启通知时收到开始、失败、完成三类邮件
+func (a *alertMgr) NotifyJobEvent(ctx context.Context, job *model.Job, event model.NotificationEvent) error {
+	rule, err := FindNotificationRule(ctx, query.Q, job.UserID, job.AccountID)
+	if err != nil {
+		return err
+	}
+	if rule == nil {
+		return a.legacyJobEventAlert(ctx, job, event)
+	}
+	if !rule.Subscribes(event) {
+		return nil
+	}
+	return a.notifyJobOnce(ctx, job.JobName, string(event), nil, func(info *JobInformation) error {
+		return a.dispatchNotice(ctx, rule, job.UserID, &info.Receiver, jobEventNotice(info, event))
+	})
+}
+
+func (a *alertMgr) legacyJobEventAlert(ctx context.Context, job *model.Job, event model.NotificationEvent) error {
+	if !job.AlertEnabled {
+		return nil
+	}
+	//nolint:exhaustive // 其余事件只对配置了通知规则的用户生效
+	switch event {
+	case model.NotificationEventJobStarted:
+		return a.JobRunningAlert(ctx, job.JobName)
+	case model.NotificationEventJobFailed:
+		return a.JobFailureAlert(ctx, job.JobName)
+	case model.NotificationEventJobCompleted:
+		return a.JobCompleteAlert(ctx, job.JobName)
+	default:
+		return nil
+	}
+}
+
+func jobEventNotice(info *JobInformation, event model.NotificationEvent) *Notice {
+	notice := &Notice{Event: string(event), URL: info.jobURL, ButtonText: "查看作业详情"}
+	//nolint:exhaustive // 非作业事件不会走到这里
+	switch event {
+	case model.NotificationEventJobQueuedTooLong:
+		notice.Title = "作业排队时间过长"
+		notice.Message = fmt.Sprintf("您的作业 <strong>%s</strong> (ID: %s) 已排队 %s，仍未开始运行。",
+			info.Name, info.JobName, utils.GetLocalTime().Sub(info.CreationTimestamp).Round(time.Minute))
+	case model.NotificationEventJobStarted:
+		notice.Title = "作业已开始运行"
+		notice.Message = fmt.Sprintf("您的作业 <strong>%s</strong> (ID: %s) 已开始运行。", info.Name, info.JobName)
+	case model.NotificationEventJobFailed:
+		notice.Title = "作业运行失败"
+		notice.Message = fmt.Sprintf("您的作业 <strong>%s</strong> (ID: %s) 运行失败。请查看日志了解详细信息。", info.Name, info.JobName)
+		notice.ButtonText = "查看失败详情"
+	case model.NotificationEventJobCompleted:
+		notice.Title = "作业已成功完成"
+		notice.Message = fmt.Sprintf("您的作业 <strong>%s</strong> (ID: %s) 已成功运行完成。", info.Name, info.JobName)
+	case model.NotificationEventBackfillPreempted:
+		notice.Title = "作业已被抢占"
+		notice.Message = fmt.Sprintf("您的作业 <strong>%s</strong> (ID: %s) 以 backfill 方式运行，已被抢占以释放资源给排队中的作业。",
+			info.Name, info.JobName)
+	default:
+		notice.Title = string(event)
+	}
+	notice.Subject = notice.Title
+	return notice
+}
+
+// ruleOptionalEvents 由管理员配置触发（如账户预算）或需要用户处理（如数据集访问申请、镜像清理预告），
+// 未配置通知规则的用户也会直接收到
+var ruleOptionalEvents = []model.NotificationEvent{
+	model.NotificationEventBudgetThreshold,
+	model.NotificationEventBudgetCapReached,
+	model.NotificationEventApprovalRequested,
+	model.NotificationEventImageCleanup,
+}
+
+// NotifyUserEvent 发送与具体作业无关的通知，仅对订阅了该事件的用户生效，去重由调用方负责
+func (a *alertMgr) NotifyUserEvent(
+	ctx context.Context,
+	userID, accountID uint,
+	event model.NotificationEvent,
+	notice *Notice,
+) error {
+	rule, err := FindNotificationRule(ctx, query.Q, userID, accountID)
+	if err != nil {
+		return err
+	}
+	if rule == nil && !slices.Contains(ruleOptionalEvents, event) {
+		return nil
+	}
+	if rule != nil && !rule.Subscribes(event) {
+		return nil
+	}
+	u := query.User
+	user, err := u.WithContext(ctx).Where(u.ID.Eq(userID)).First()
+	if err != nil {
+		return err
+	}
+	receiver := user.Attributes.Data()
+	notice.Event = string(event)
+	if notice.Subject == "" {
+		notice.Subject = notice.Title
+	}
+	if rule == nil {
+		return a.deliverNotice(ctx, &receiver, notice)
+	}
+	return a.dispatchNotice(ctx, rule, userID, &receiver, notice)
+}
+
+// dispatchNotice 立即发送，或在免打扰时段、摘要合并时写入待发送队列
+func (a *alertMgr) dispatchNotice(
+	ctx context.Context,
+	rule *model.NotificationRule,
+	userID uint,
+	receiver *model.UserAttribute,
+	notice *Notice,
+) error {
+	now := utils.GetLocalTime()
+	deliverAt := rule.DeliverAt(now)
+	if !deliverAt.After(now) {
+		return a.deliverNotice(ctx, receiver, notice)
+	}
+	return query.NotificationDigestItem.WithContext(ctx).Create(&model.NotificationDigestItem{
+		UserID:       userID,
+		Event:        model.NotificationEvent(notice.Event),
+		Title:        notice.Title,
+		Message:      notice.Message,
+		URL:          notice.URL,
+		DeliverAfter: deliverAt,
+	})
+}
+
+// FlushNotificationDigests 将到期的待发送通知按用户合并为一条摘要发送，返回成功发送的用户数
+func (a *alertMgr) FlushNotificationDigests(ctx context.Context) (int, error) {
+	now := utils.GetLocalTime()
+	di := query.NotificationDigestItem
+	var userIDs []uint
+	if err := di.WithContext(ctx).
+		Where(di.SentAt.IsNull(), di.DeliverAfter.Lte(now)).
+		Distinct(di.UserID).
+		Pluck(di.UserID, &userIDs); err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	var errs []error
+	for _, userID := range userIDs {
+		if err := a.flushUserDigest(ctx, userID, now); err != nil {
+			klog.Warningf("flush notification digest for user %d failed: %v", userID, err)
+			errs = append(errs, err)
+			continue
+		}
+		sent++
+	}
+	return sent, errors.Join(errs...)
+}
+
+// flushUserDigest 一旦最早的一条到期，就把该用户所有待发送通知一并发出
+func (a *alertMgr) flushUserDigest(ctx context.Context, userID uint, now time.Time) error {
+	di := query.NotificationDigestItem
+	items, err := di.WithContext(ctx).
+		Where(di.UserID.Eq(userID), di.SentAt.IsNull()).
+		Order(di.CreatedAt).
+		Find()
+	if err != nil || len(items) == 0 {
+		return err
+	}
+	u := query.User
+	user, err := u.WithContext(ctx).Where(u.ID.Eq(userID)).First()
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		_, err = di.WithContext(ctx).Where(di.UserID.Eq(userID), di.SentAt.IsNull()).Update(di.SentAt, now)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	receiver := user.Attributes.Data()
+	if err := a.deliverNotice(ctx, &receiver, digestNotice(items)); err != nil {
+		return err
+	}
+
+	ids := make([]uint, len(items))
+	for i := range items {
+		ids[i] = items[i].ID
+	}
+	_, err = di.WithContext(ctx).Where(di.ID.In(ids...)).Update(di.SentAt, now)
+	return err
+}
+
+func digestNotice(items []*model.NotificationDigestItem) *Notice {
+	if len(items) == 1 {
+		return &Notice{
+			Event:      string(items[0].Event),
+			Subject:    items[0].Title,
+			Title:      items[0].Title,
+			Message:    items[0].Message,
+			URL:        items[0].URL,
+			ButtonText: "查看详情",
+		}
+	}
+	parts := make([]string, len(items))
+	for i, item := range items {
+		parts[i] = fmt.Sprintf("<strong>%s</strong> (%s)<br>%s", item.Title, item.CreatedAt.Format("01-02 15:04"), item.Message)
+		if item.URL != "" {
+			parts[i] += fmt.Sprintf("<br>%s", item.URL)
+		}
+	}
+	title := fmt.Sprintf("通知摘要（%d 条）", len(items))
+	return &Notice{
+		Event:      notificationDigestEvent,
+		Subject:    title,
+		Title:      title,
+		Message:    strings.Join(parts, "<br><br>"),
+		URL:        fmt.Sprintf("https://%s/portal", config.GetConfig().Host),
+		ButtonText: "打开 Crater",
+	}
+}
@@ -0,0 +1,61 @@
+package alert
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/datatypes"
+
+	"github.com/raids-lab/crater/dao/model"
+)
+
+func TestNotificationRuleDeliverAt(t *testing.T) {
+	loc := time.FixedZone("CST", 8*3600)
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, 8, 3, hour, minute, 0, 0, loc)
+	}
+	overnight := &model.NotificationRule{QuietStart: "22:00", QuietEnd: "08:00"}
+	daytime := &model.NotificationRule{QuietStart: "12:00", QuietEnd: "14:00", DigestMinutes: 30}
+
+	tests := []struct {
+		name string
+		rule *model.NotificationRule
+		now  time.Time
+		want time.Time
+	}{
+		{name: "outside overnight quiet hours", rule: overnight, now: at(10, 0), want: at(10, 0)},
+		{name: "late evening waits for next morning", rule: overnight, now: at(23, 30), want: at(8, 0).AddDate(0, 0, 1)},
+		{name: "early morning waits until end", rule: overnight, now: at(7, 15), want: at(8, 0)},
+		{name: "digest window", rule: daytime, now: at(9, 0), want: at(9, 30)},
+		{name: "digest window ending in quiet hours", rule: daytime, now: at(11, 45), want: at(14, 0)},
+		{name: "no rule options", rule: &model.NotificationRule{}, now: at(3, 0), want: at(3, 0)},
+	}
+	for _, tc := range tests {
+		if got := tc.rule.DeliverAt(tc.now); !got.Equal(tc.want) {
+			t.Fatalf("%s: DeliverAt() = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+
+	rule := &model.NotificationRule{Events: datatypes.NewJSONType([]model.NotificationEvent{model.NotificationEventJobFailed})}
+	if !rule.Subscribes(model.NotificationEventJobFailed) || rule.Subscribes(model.NotificationEventJobStarted) {
+		t.Fatal("Subscribes() does not follow the configured events")
+	}
+}
+
+func TestDigestNotice(t *testing.T) {
+	items := []*model.NotificationDigestItem{
+		{Event: model.NotificationEventJobFailed, Title: "作业运行失败", Message: "a", URL: "https://crater/a"},
+		{Event: model.NotificationEventJobCompleted, Title: "作业已成功完成", Message: "b"},
+	}
+	single := digestNotice(items[:1])
+	if single.Title != "作业运行失败" || single.URL != "https://crater/a" {
+		t.Fatalf("single digest = %+v, want the item itself", single)
+	}
+
+	combined := digestNotice(items)
+	if combined.Event != notificationDigestEvent || !strings.Contains(combined.Title, "2") ||
+		!strings.Contains(combined.Message, "作业运行失败") || !strings.Contains(combined.Message, "作业已成功完成") {
+		t.Fatalf("combined digest = %+v", combined)
+	}
+}
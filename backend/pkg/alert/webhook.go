@@ -0,0 +1,348 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/pkg/crypto"
+)
+
+const (
+	webhookTimeout       = 10 * time.Second
+	webhookMaxRespBodyKB = 64
+)
+
+// Notice 与渠道无关的通知内容，Message 可以包含简单的 HTML 标记，
+// 邮件渠道原样渲染，webhook 渠道会转换为纯文本
+type Notice struct {
+	Event      string
+	Subject    string
+	Title      string
+	Message    string
+	URL        string
+	ButtonText string
+}
+
+// WebhookAlerter 通过 webhook 发送通知，支持通用 JSON、Slack 兼容、企业微信、飞书与钉钉机器人
+type WebhookAlerter struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+func newWebhookAlerter() *WebhookAlerter {
+	// 沿用默认 Transport 的代理等设置，只替换拨号与 TLS 握手超时
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = webhookDialContext(proxyAddresses())
+	transport.TLSHandshakeTimeout = webhookTimeout
+	return &WebhookAlerter{
+		client: &http.Client{
+			Timeout:   webhookTimeout,
+			Transport: transport,
+		},
+		now: time.Now,
+	}
+}
+
+// webhookDialContext 在建立连接时再次校验地址，防止 DNS 重绑定或重定向绕过保存时的检查。
+// 经代理转发时拨号目标是代理本身（通常位于内网），放行代理地址，目标主机仍由保存时的检查把关。
+func webhookDialContext(proxies map[string]bool) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	guarded := &net.Dialer{Timeout: webhookTimeout, Control: publicAddressControl}
+	direct := &net.Dialer{Timeout: webhookTimeout}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if proxies[addr] {
+			return direct.DialContext(ctx, network, addr)
+		}
+		return guarded.DialContext(ctx, network, addr)
+	}
+}
+
+// proxyAddresses 返回环境变量中配置的代理的 host:port
+func proxyAddresses() map[string]bool {
+	addrs := map[string]bool{}
+	for _, key := range []string{"HTTPS_PROXY", "https_proxy", "HTTP_PROXY", "http_proxy"} {
+		raw := os.Getenv(key)
+		if raw == "" {
+			continue
+		}
+		if !strings.Contains(raw, "://") {
+			raw = "http://" + raw
+		}
+		proxy, err := url.Parse(raw)
+		if err != nil || proxy.Hostname() == "" {
+			continue
+		}
+		port := proxy.Port()
+		if port == "" {
+			switch proxy.Scheme {
+			case "https":
+				port = "443"
+			case "socks5", "socks5h":
+				port = "1080"
+			default:
+				port = "80"
+			}
+		}
+		addrs[net.JoinHostPort(proxy.Hostname(), port)] = true
+	}
+	return addrs
+}
+
+// lookupIPAddr 解析 webhook 主机，测试中可替换
+var lookupIPAddr = net.DefaultResolver.LookupIPAddr
+
+// sharedAddressSpace 运营商级 NAT 地址段（RFC 6598），常用于集群内部网络
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// isPublicIP 拒绝回环、私有、链路本地等内部地址，避免用户借 webhook 访问集群内部服务
+func isPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || sharedAddressSpace.Contains(ip))
+}
+
+func publicAddressControl(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+		return fmt.Errorf("webhook address %s is not a public address", host)
+	}
+	return nil
+}
+
+// checkWebhookTarget 解析 webhook 主机，任一地址不是公网地址即拒绝
+func checkWebhookTarget(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	addrs, err := lookupIPAddr(ctx, u.Hostname())
+	if err != nil {
+		return fmt.Errorf("resolve webhook host %s: %w", u.Hostname(), err)
+	}
+	for _, addr := range addrs {
+		if !isPublicIP(addr.IP) {
+			return fmt.Errorf("webhook host %s resolves to non-public address %s", u.Hostname(), addr.IP)
+		}
+	}
+	return nil
+}
+
+// PrepareNotificationPreference 校验用户提交的渠道并加密签名密钥后再保存。
+// 密钥为 model.NotificationSecretMask 时沿用 previous 中同类型、同地址渠道已保存的密钥。
+func PrepareNotificationPreference(
+	ctx context.Context,
+	preference, previous *model.NotificationPreference,
+) error {
+	if err := preference.Validate(); err != nil {
+		return err
+	}
+	if preference == nil {
+		return nil
+	}
+	for i := range preference.Channels {
+		channel := &preference.Channels[i]
+		if channel.Type == model.NotificationChannelEmail {
+			continue
+		}
+		if err := checkWebhookTarget(ctx, channel.URL); err != nil {
+			return err
+		}
+		switch channel.Secret {
+		case "":
+		case model.NotificationSecretMask:
+			stored, ok := previous.StoredSecret(channel.Type, channel.URL)
+			if !ok {
+				return fmt.Errorf("secret of %s channel must be provided again after its url changes", channel.Type)
+			}
+			channel.Secret = stored
+		default:
+			encrypted, err := crypto.Encrypt(channel.Secret)
+			if err != nil {
+				return fmt.Errorf("encrypt %s channel secret: %w", channel.Type, err)
+			}
+			channel.Secret = encrypted
+		}
+	}
+	return nil
+}
+
+// channelSecret 解密渠道的签名密钥，无法解密时视为加密前保存的明文密钥
+func channelSecret(channel *model.NotificationChannel) string {
+	if channel.Secret == "" {
+		return ""
+	}
+	if secret, err := crypto.Decrypt(channel.Secret); err == nil {
+		return secret
+	}
+	return channel.Secret
+}
+
+func (wa *WebhookAlerter) Send(ctx context.Context, channel model.NotificationChannel, receiver *model.UserAttribute, notice *Notice) error {
+	target, payload, err := wa.buildRequest(channel, receiver, notice)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := wa.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send %s notification: %w", channel.Type, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, webhookMaxRespBodyKB<<10))
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("send %s notification: unexpected status %d", channel.Type, resp.StatusCode)
+	}
+	return checkBotResponse(channel.Type, respBody)
+}
+
+func (wa *WebhookAlerter) buildRequest(
+	channel model.NotificationChannel,
+	receiver *model.UserAttribute,
+	notice *Notice,
+) (target string, payload any, err error) {
+	text := htmlToText(notice.Message)
+	target = channel.URL
+	secret := channelSecret(&channel)
+
+	switch channel.Type {
+	case model.NotificationChannelWebhook:
+		payload = map[string]any{
+			"event":   notice.Event,
+			"title":   notice.Title,
+			"text":    text,
+			"url":     notice.URL,
+			"user":    receiver.Name,
+			"sentAt":  wa.now().Format(time.RFC3339),
+			"subject": notice.Subject,
+		}
+	case model.NotificationChannelSlack:
+		payload = map[string]any{
+			"text": fmt.Sprintf("*%s*\n%s\n<%s|%s>", notice.Title, text, notice.URL, notice.ButtonText),
+		}
+	case model.NotificationChannelWeCom:
+		payload = map[string]any{
+			"msgtype": "markdown",
+			"markdown": map[string]string{
+				"content": markdownNotice(notice, text),
+			},
+		}
+	case model.NotificationChannelFeishu:
+		content := map[string]any{
+			"msg_type": "text",
+			"content": map[string]string{
+				"text": fmt.Sprintf("%s\n%s\n%s", notice.Title, text, notice.URL),
+			},
+		}
+		if secret != "" {
+			timestamp := strconv.FormatInt(wa.now().Unix(), 10)
+			content["timestamp"] = timestamp
+			content["sign"] = signFeishu(timestamp, secret)
+		}
+		payload = content
+	case model.NotificationChannelDingTalk:
+		payload = map[string]any{
+			"msgtype": "markdown",
+			"markdown": map[string]string{
+				"title": notice.Title,
+				"text":  markdownNotice(notice, text),
+			},
+		}
+		if secret != "" {
+			target, err = signDingTalkURL(channel.URL, secret, wa.now())
+		}
+	default:
+		err = fmt.Errorf("unsupported notification channel type %q", channel.Type)
+	}
+	return target, payload, err
+}
+
+func markdownNotice(notice *Notice, text string) string {
+	return fmt.Sprintf("### %s\n%s\n\n[%s](%s)", notice.Title, text, notice.ButtonText, notice.URL)
+}
+
+// signFeishu 飞书签名：以 timestamp + "\n" + secret 为密钥对空串做 HmacSHA256
+func signFeishu(timestamp, secret string) string {
+	mac := hmac.New(sha256.New, []byte(timestamp+"\n"+secret))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// signDingTalkURL 钉钉签名：以 secret 为密钥对 timestamp + "\n" + secret 做 HmacSHA256，附加在 URL 参数中
+func signDingTalkURL(rawURL, secret string, now time.Time) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	timestamp := strconv.FormatInt(now.UnixMilli(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "\n" + secret))
+	query := u.Query()
+	query.Set("timestamp", timestamp)
+	query.Set("sign", base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// checkBotResponse 机器人接口在 HTTP 200 中通过 errcode / code 返回业务错误
+func checkBotResponse(channelType model.NotificationChannelType, body []byte) error {
+	switch channelType {
+	case model.NotificationChannelWeCom, model.NotificationChannelFeishu, model.NotificationChannelDingTalk:
+	default:
+		return nil
+	}
+	var result struct {
+		ErrCode *int   `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+		Code    *int   `json:"code"`
+		Msg     string `json:"msg"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil
+	}
+	if result.ErrCode != nil && *result.ErrCode != 0 {
+		return fmt.Errorf("send %s notification: errcode %d: %s", channelType, *result.ErrCode, result.ErrMsg)
+	}
+	if result.Code != nil && *result.Code != 0 {
+		return fmt.Errorf("send %s notification: code %d: %s", channelType, *result.Code, result.Msg)
+	}
+	return nil
+}
+
+var (
+	htmlBreakPattern = regexp.MustCompile(`(?i)<br\s*/?>`)
+	htmlTagPattern   = regexp.MustCompile(`<[^>]*>`)
+)
+
+func htmlToText(message string) string {
+	text := htmlBreakPattern.ReplaceAllString(message, "\n")
+	text = htmlTagPattern.ReplaceAllString(text, "")
+	return strings.TrimSpace(html.UnescapeString(text))
+}
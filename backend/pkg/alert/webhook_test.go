@@ -0,0 +1,205 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/pkg/crypto"
+)
+
+func TestWebhookAlerterPayloads(t *testing.T) {
+	var gotPath, gotQuery string
+	var gotBody map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotQuery = r.URL.Path, r.URL.RawQuery
+		body, _ := io.ReadAll(r.Body)
+		gotBody = nil
+		_ = json.Unmarshal(body, &gotBody)
+		if strings.HasSuffix(r.URL.Path, "/broken") {
+			_, _ = w.Write([]byte(`{"errcode":310000,"errmsg":"keywords not in content"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"errcode":0,"code":0}`))
+	}))
+	defer server.Close()
+
+	wa := newWebhookAlerter()
+	// the test server listens on loopback, which the default transport refuses to dial
+	wa.client = server.Client()
+	wa.now = func() time.Time { return time.Unix(1700000000, 0) }
+	encrypted, err := crypto.Encrypt("s3cret")
+	if err != nil {
+		t.Fatalf("encrypt secret: %v", err)
+	}
+	receiver := &model.UserAttribute{Name: "alice"}
+	notice := &Notice{
+		Event:      "JobFailed",
+		Title:      "作业运行失败",
+		Message:    "您的作业 <strong>train</strong> 运行失败。<br>请查看日志",
+		URL:        "https://crater/jobs/train",
+		ButtonText: "查看",
+	}
+	ctx := context.Background()
+
+	if err := wa.Send(ctx, model.NotificationChannel{Type: model.NotificationChannelWebhook, URL: server.URL + "/hook"}, receiver, notice); err != nil {
+		t.Fatalf("generic webhook: %v", err)
+	}
+	if gotBody["event"] != "JobFailed" || gotBody["text"] != "您的作业 train 运行失败。\n请查看日志" || gotBody["user"] != "alice" {
+		t.Fatalf("generic payload = %v", gotBody)
+	}
+
+	if err := wa.Send(ctx, model.NotificationChannel{Type: model.NotificationChannelSlack, URL: server.URL}, receiver, notice); err != nil {
+		t.Fatalf("slack webhook: %v", err)
+	}
+	if text, _ := gotBody["text"].(string); !strings.Contains(text, "<https://crater/jobs/train|查看>") {
+		t.Fatalf("slack payload = %v", gotBody)
+	}
+
+	if err := wa.Send(ctx, model.NotificationChannel{Type: model.NotificationChannelWeCom, URL: server.URL}, receiver, notice); err != nil {
+		t.Fatalf("wecom webhook: %v", err)
+	}
+	if gotBody["msgtype"] != "markdown" {
+		t.Fatalf("wecom payload = %v", gotBody)
+	}
+
+	feishu := model.NotificationChannel{Type: model.NotificationChannelFeishu, URL: server.URL, Secret: encrypted}
+	if err := wa.Send(ctx, feishu, receiver, notice); err != nil {
+		t.Fatalf("feishu webhook: %v", err)
+	}
+	if gotBody["timestamp"] != "1700000000" || gotBody["sign"] != signFeishu("1700000000", "s3cret") {
+		t.Fatalf("feishu payload = %v", gotBody)
+	}
+
+	// secrets saved before they were encrypted are still used as plain text
+	dingtalk := model.NotificationChannel{Type: model.NotificationChannelDingTalk, URL: server.URL + "/robot/send?access_token=x", Secret: "s3cret"}
+	if err := wa.Send(ctx, dingtalk, receiver, notice); err != nil {
+		t.Fatalf("dingtalk webhook: %v", err)
+	}
+	if gotPath != "/robot/send" || !strings.Contains(gotQuery, "access_token=x") ||
+		!strings.Contains(gotQuery, "timestamp=1700000000000") || !strings.Contains(gotQuery, "sign=") {
+		t.Fatalf("dingtalk request = %s?%s", gotPath, gotQuery)
+	}
+
+	broken := model.NotificationChannel{Type: model.NotificationChannelDingTalk, URL: server.URL + "/broken"}
+	if err := wa.Send(ctx, broken, receiver, notice); err == nil || !strings.Contains(err.Error(), "310000") {
+		t.Fatalf("error = %v, want bot errcode", err)
+	}
+}
+
+func TestNotificationChain(t *testing.T) {
+	attr := model.UserAttribute{}
+	if chain := attr.NotificationChain(); len(chain) != 1 || chain[0].Type != model.NotificationChannelEmail {
+		t.Fatalf("default chain = %v", chain)
+	}
+
+	attr.Notification = &model.NotificationPreference{Channels: []model.NotificationChannel{
+		{Type: model.NotificationChannelFeishu, URL: "https://open.feishu.cn/hook"},
+		{Type: model.NotificationChannelSlack, URL: "https://hooks.slack.com/x"},
+	}}
+	chain := attr.NotificationChain()
+	if len(chain) != 3 || chain[0].Type != model.NotificationChannelFeishu || chain[2].Type != model.NotificationChannelEmail {
+		t.Fatalf("chain = %v, want feishu, slack, email fallback", chain)
+	}
+
+	if err := attr.Notification.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	invalid := &model.NotificationPreference{Channels: []model.NotificationChannel{{Type: model.NotificationChannelWeCom, URL: "file:///etc/passwd"}}}
+	if err := invalid.Validate(); err == nil {
+		t.Fatal("Validate() accepted a non-http webhook url")
+	}
+}
+
+func TestWebhookRejectsInternalAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		t.Error("the webhook reached a loopback server")
+	}))
+	defer server.Close()
+
+	notice := &Notice{Title: "t", Message: "m"}
+	channel := model.NotificationChannel{Type: model.NotificationChannelWebhook, URL: server.URL}
+	err := newWebhookAlerter().Send(context.Background(), channel, &model.UserAttribute{Name: "alice"}, notice)
+	if err == nil || !strings.Contains(err.Error(), "not a public address") {
+		t.Fatalf("Send() error = %v, want dial refused", err)
+	}
+
+	for ip, public := range map[string]bool{
+		"8.8.8.8": true, "2001:4860:4860::8888": true,
+		"127.0.0.1": false, "10.1.2.3": false, "172.16.0.1": false, "192.168.1.1": false,
+		"169.254.169.254": false, "100.64.0.1": false, "0.0.0.0": false, "::1": false, "fe80::1": false, "fd00::1": false,
+	} {
+		if got := isPublicIP(net.ParseIP(ip)); got != public {
+			t.Errorf("isPublicIP(%s) = %v, want %v", ip, got, public)
+		}
+	}
+}
+
+func TestWebhookTransportKeepsProxy(t *testing.T) {
+	transport, ok := newWebhookAlerter().client.Transport.(*http.Transport)
+	if !ok || transport.Proxy == nil {
+		t.Fatal("webhook transport dropped the proxy settings of http.DefaultTransport")
+	}
+
+	t.Setenv("HTTPS_PROXY", "https://proxy.internal")
+	t.Setenv("HTTP_PROXY", "10.0.0.8:3128")
+	got := proxyAddresses()
+	for _, addr := range []string{"proxy.internal:443", "10.0.0.8:3128"} {
+		if !got[addr] {
+			t.Errorf("proxyAddresses() = %v, missing %s", got, addr)
+		}
+	}
+}
+
+func TestPrepareNotificationPreference(t *testing.T) {
+	previousLookup := lookupIPAddr
+	t.Cleanup(func() { lookupIPAddr = previousLookup })
+	lookupIPAddr = func(_ context.Context, host string) ([]net.IPAddr, error) {
+		if host == "internal.example.com" {
+			return []net.IPAddr{{IP: net.ParseIP("10.0.0.8")}}, nil
+		}
+		return []net.IPAddr{{IP: net.ParseIP("203.0.113.10")}}, nil
+	}
+	ctx := context.Background()
+
+	internal := &model.NotificationPreference{Channels: []model.NotificationChannel{
+		{Type: model.NotificationChannelWebhook, URL: "https://internal.example.com/hook"},
+	}}
+	if err := PrepareNotificationPreference(ctx, internal, nil); err == nil {
+		t.Fatal("accepted a webhook resolving to a private address")
+	}
+
+	preference := &model.NotificationPreference{Channels: []model.NotificationChannel{
+		{Type: model.NotificationChannelEmail},
+		{Type: model.NotificationChannelFeishu, URL: "https://open.feishu.cn/hook", Secret: "s3cret"},
+	}}
+	if err := PrepareNotificationPreference(ctx, preference, nil); err != nil {
+		t.Fatalf("PrepareNotificationPreference() error = %v", err)
+	}
+	stored := preference.Channels[1].Secret
+	if stored == "s3cret" || channelSecret(&preference.Channels[1]) != "s3cret" {
+		t.Fatalf("secret must be stored encrypted, got %q", stored)
+	}
+
+	redacted := preference.Redacted()
+	if redacted.Channels[1].Secret != model.NotificationSecretMask || preference.Channels[1].Secret != stored {
+		t.Fatalf("redacted = %+v, original = %+v", redacted, preference)
+	}
+	if err := PrepareNotificationPreference(ctx, redacted, preference); err != nil || redacted.Channels[1].Secret != stored {
+		t.Fatalf("masked secret must keep the stored one, got %q, %v", redacted.Channels[1].Secret, err)
+	}
+
+	moved := &model.NotificationPreference{Channels: []model.NotificationChannel{
+		{Type: model.NotificationChannelFeishu, URL: "https://open.feishu.cn/other", Secret: model.NotificationSecretMask},
+	}}
+	if err := PrepareNotificationPreference(ctx, moved, preference); err == nil {
+		t.Fatal("masked secret was accepted for a new url")
+	}
+}
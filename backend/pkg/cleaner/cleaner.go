@@ -9,6 +9,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/raids-lab/crater/pkg/imageregistry"
 	"github.com/raids-lab/crater/pkg/monitor"
 	"github.com/raids-lab/crater/pkg/util"
 )
@@ -25,6 +26,9 @@ const (
 	CLEAN_LOW_GPU_USAGE_JOB     = "clean-low-gpu-util-job"
 	CLEAN_WAITING_JUPYTER_JOB   = "clean-waiting-jupyter"
 	CLEAN_WAITING_CUSTOM_JOB    = "clean-waiting-custom"
+	CLEAN_STORAGE_TRASH_JOB     = "clean-storage-trash"
+	REVOKE_EXPIRED_SHARES_JOB   = "revoke-expired-shares"
+	CLEAN_STALE_IMAGES_JOB      = "clean-stale-images"
 )
 
 // Clients 包含清理任务所需的所有客户端
@@ -32,6 +36,8 @@ type Clients struct {
 	Client     client.Client
 	KubeClient kubernetes.Interface
 	PromClient monitor.PrometheusInterface
+	// ImageRegistry lists and deletes the images of the inner registry; nil creates one from the config.
+	ImageRegistry imageregistry.ImageRegistryInterface
 }
 
 // GetCleanerFunc 根据作业名称返回对应的清理函数
@@ -72,6 +78,30 @@ func GetCleanerFunc(jobName string, clients *Clients, jobConfig datatypes.JSON)
 		f = func(ctx context.Context) (any, error) {
 			return CleanWaitingJobs(ctx, clients, req)
 		}
+	case CLEAN_STORAGE_TRASH_JOB:
+		req := &CleanStorageTrashRequest{}
+		if err := json.Unmarshal(jobConfig, req); err != nil {
+			return nil, err
+		}
+		f = func(ctx context.Context) (any, error) {
+			return CleanStorageTrash(ctx, req)
+		}
+	case REVOKE_EXPIRED_SHARES_JOB:
+		req := &RevokeExpiredSharesRequest{}
+		if err := json.Unmarshal(jobConfig, req); err != nil {
+			return nil, err
+		}
+		f = func(ctx context.Context) (any, error) {
+			return RevokeExpiredShares(ctx, req)
+		}
+	case CLEAN_STALE_IMAGES_JOB:
+		req := &ImageGCRequest{}
+		if err := json.Unmarshal(jobConfig, req); err != nil {
+			return nil, err
+		}
+		f = func(ctx context.Context) (any, error) {
+			return CleanStaleImages(ctx, clients, req)
+		}
 	default:
 		return nil, fmt.Errorf("unsupported cleaner job name: %s", jobName)
 	}
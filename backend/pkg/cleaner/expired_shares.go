@@ -0,0 +1,42 @@
+package cleaner
+
+import (
+	"context"
+
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/pkg/utils"
+)
+
+type RevokeExpiredSharesRequest struct{}
+
+// RevokeExpiredShares removes the dataset and image shares whose expiry has passed, such as those
+// created by approved access requests. Shares without an expiry are permanent.
+func RevokeExpiredShares(c context.Context, _ *RevokeExpiredSharesRequest) (map[string]any, error) {
+	now := utils.GetLocalTime()
+	ud := query.UserDataset
+	users, err := ud.WithContext(c).Where(ud.ExpiresAt.IsNotNull(), ud.ExpiresAt.Lte(now)).Delete()
+	if err != nil {
+		return nil, err
+	}
+	ad := query.AccountDataset
+	accounts, err := ad.WithContext(c).Where(ad.ExpiresAt.IsNotNull(), ad.ExpiresAt.Lte(now)).Delete()
+	if err != nil {
+		return nil, err
+	}
+	iu := query.ImageUser
+	imageUsers, err := iu.WithContext(c).Where(iu.ExpiresAt.IsNotNull(), iu.ExpiresAt.Lte(now)).Delete()
+	if err != nil {
+		return nil, err
+	}
+	ia := query.ImageAccount
+	imageAccounts, err := ia.WithContext(c).Where(ia.ExpiresAt.IsNotNull(), ia.ExpiresAt.Lte(now)).Delete()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{
+		"userDatasets":    users.RowsAffected,
+		"accountDatasets": accounts.RowsAffected,
+		"imageUsers":      imageUsers.RowsAffected,
+		"imageAccounts":   imageAccounts.RowsAffected,
+	}, nil
+}
@@ -0,0 +1,541 @@
+package cleaner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gen/field"
+	"k8s.io/klog/v2"
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/util"
+	"github.com/raids-lab/crater/pkg/alert"
+	"github.com/raids-lab/crater/pkg/config"
+	"github.com/raids-lab/crater/pkg/imageregistry"
+	"github.com/raids-lab/crater/pkg/utils"
+)
+
+// Reasons an image is selected by the image garbage collector.
+const (
+	ImageGCReasonUntagged   = "untagged"
+	ImageGCReasonSuperseded = "superseded"
+	ImageGCReasonUnused     = "unused"
+)
+
+// ImageGCRequest is the retention policy of the clean-stale-images job. Only images of the inner
+// registry are considered. An image is kept when any of these hold:
+//   - it is public or shared with a user or account, unless SkipShared is false;
+//   - an unfinished job uses it;
+//   - it was created or used by a job in the last UnusedDays days;
+//   - it is one of the KeepLastPerRepository newest images of its repository.
+//
+// Other images are deleted once a retention rule is configured. With DeleteUntagged, images
+// whose registry manifest no tag points at are not counted by KeepLastPerRepository and are
+// deleted even when it is the only rule. An image is never deleted while a kept image or a
+// registry tag outside the candidates points at its manifest. Owners are notified first; the
+// images are deleted by the first run after NoticeDays.
+type ImageGCRequest struct {
+	KeepLastPerRepository int   `json:"keepLastPerRepository" form:"keepLastPerRepository"`
+	UnusedDays            int   `json:"unusedDays" form:"unusedDays"`
+	DeleteUntagged        bool  `json:"deleteUntagged" form:"deleteUntagged"`
+	SkipShared            *bool `json:"skipShared" form:"skipShared"`
+	NoticeDays            int   `json:"noticeDays" form:"noticeDays"`
+	DryRun                bool  `json:"dryRun" form:"dryRun"`
+}
+
+// ImageGCCandidate is an image selected for deletion.
+type ImageGCCandidate struct {
+	ImageID     uint       `json:"imageId"`
+	ImageLink   string     `json:"imageLink"`
+	UserID      uint       `json:"userId"`
+	Username    string     `json:"username"`
+	Reason      string     `json:"reason"`
+	Size        int64      `json:"size"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	LastUsedAt  *time.Time `json:"lastUsedAt,omitempty"`
+	NotifiedAt  *time.Time `json:"notifiedAt,omitempty"`
+	DeleteAfter time.Time  `json:"deleteAfter"`
+}
+
+// ImageGCReport lists the images the policy selects, as the dry run of the job.
+type ImageGCReport struct {
+	Policy     ImageGCRequest     `json:"policy"`
+	Scanned    int                `json:"scanned"`
+	TotalSize  int64              `json:"totalSize"`
+	Candidates []ImageGCCandidate `json:"candidates"`
+}
+
+// Validate checks the policy and fills in the defaults.
+func (req *ImageGCRequest) Validate() error {
+	if req == nil {
+		return errors.New("invalid request")
+	}
+	if req.KeepLastPerRepository < 0 || req.UnusedDays < 0 || req.NoticeDays < 0 {
+		return errors.New("keepLastPerRepository, unusedDays and noticeDays must not be negative")
+	}
+	if req.KeepLastPerRepository == 0 && req.UnusedDays == 0 && !req.DeleteUntagged {
+		return errors.New("at least one of keepLastPerRepository, unusedDays and deleteUntagged must be set")
+	}
+	if req.SkipShared == nil {
+		skipShared := true
+		req.SkipShared = &skipShared
+	}
+	return nil
+}
+
+// imageGCState is what the policy is evaluated against.
+type imageGCState struct {
+	registry string
+	now      time.Time
+	images   []*model.Image
+	// shared holds the IDs of public images and of images with unexpired shares.
+	shared map[uint]bool
+	// inUse holds the image links of unfinished jobs.
+	inUse map[string]bool
+	// lastUsed maps image links to the creation time of the latest job using them, for the jobs
+	// created in the last UnusedDays days.
+	lastUsed map[string]time.Time
+	// manifests maps the links of the images, and of every tag listed in their repositories, to
+	// the digest link of the manifest they point at.
+	manifests map[string]string
+	// tagged holds the digest links of the manifests at least one registry tag points at.
+	tagged map[string]bool
+}
+
+// PlanImageGC evaluates the policy without changing anything. Marks of earlier runs supply the
+// notification and deletion times; other candidates would be deleted NoticeDays from now.
+func PlanImageGC(c context.Context, registry imageregistry.ImageRegistryInterface, req *ImageGCRequest) (*ImageGCReport, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	state, err := loadImageGCState(c, registry, req)
+	if err != nil {
+		return nil, err
+	}
+	candidates := planImageGC(req, state)
+	marks, err := loadImageGCMarks(c)
+	if err != nil {
+		return nil, err
+	}
+	report := &ImageGCReport{Policy: *req, Scanned: len(state.images), Candidates: candidates}
+	for i := range candidates {
+		if mark, ok := marks[candidates[i].ImageID]; ok {
+			notifiedAt := mark.NotifiedAt
+			candidates[i].NotifiedAt = &notifiedAt
+			candidates[i].DeleteAfter = mark.DeleteAfter
+		}
+		report.TotalSize += candidates[i].Size
+	}
+	return report, nil
+}
+
+// CleanStaleImages deletes the images of the inner registry that fall out of the retention
+// policy. A newly selected image is only marked and its owner notified; a later run deletes it
+// from the registry and the database once the notice period has passed.
+func CleanStaleImages(c context.Context, clients *Clients, req *ImageGCRequest) (map[string]any, error) {
+	report, err := PlanImageGC(c, imageGCRegistry(clients), req)
+	if err != nil {
+		return nil, err
+	}
+	if req.DryRun {
+		return map[string]any{
+			"scanned":    report.Scanned,
+			"totalSize":  report.TotalSize,
+			"candidates": report.Candidates,
+		}, nil
+	}
+
+	now := utils.GetLocalTime()
+	m := query.ImageGCMark
+	candidateIDs := make([]uint, 0, len(report.Candidates))
+	for i := range report.Candidates {
+		candidateIDs = append(candidateIDs, report.Candidates[i].ImageID)
+	}
+	released := m.WithContext(c).Unscoped()
+	if len(candidateIDs) > 0 {
+		released = released.Where(m.ImageID.NotIn(candidateIDs...))
+	}
+	if _, err := released.Delete(); err != nil {
+		return nil, err
+	}
+
+	notified := map[uint][]ImageGCCandidate{}
+	deleted, failed := []string{}, []string{}
+	for i := range report.Candidates {
+		candidate := &report.Candidates[i]
+		if candidate.NotifiedAt == nil {
+			if err := m.WithContext(c).Create(&model.ImageGCMark{
+				ImageID:     candidate.ImageID,
+				UserID:      candidate.UserID,
+				ImageLink:   candidate.ImageLink,
+				Reason:      candidate.Reason,
+				NotifiedAt:  now,
+				DeleteAfter: candidate.DeleteAfter,
+			}); err != nil {
+				klog.Errorf("mark image %d for deletion failed: %v", candidate.ImageID, err)
+				failed = append(failed, candidate.ImageLink)
+				continue
+			}
+			notified[candidate.UserID] = append(notified[candidate.UserID], *candidate)
+			continue
+		}
+		if now.Before(candidate.DeleteAfter) {
+			continue
+		}
+		if err := deleteStaleImage(c, clients, candidate); err != nil {
+			klog.Errorf("delete stale image %s failed: %v", candidate.ImageLink, err)
+			failed = append(failed, candidate.ImageLink)
+			continue
+		}
+		deleted = append(deleted, candidate.ImageLink)
+	}
+
+	reminded := []string{}
+	for userID, images := range notified {
+		if err := notifyImageCleanup(c, userID, images); err != nil {
+			klog.Errorf("notify user %d about image cleanup failed: %v", userID, err)
+		}
+		for i := range images {
+			reminded = append(reminded, images[i].ImageLink)
+		}
+	}
+	sort.Strings(reminded)
+	return map[string]any{
+		"candidates": len(report.Candidates),
+		"reminded":   reminded,
+		"deleted":    deleted,
+		"failed":     failed,
+	}, nil
+}
+
+// planImageGC applies the policy to the state and returns the selected images, oldest first.
+func planImageGC(req *ImageGCRequest, state *imageGCState) []ImageGCCandidate {
+	var cutoff time.Time
+	if req.UnusedDays > 0 {
+		cutoff = state.now.Add(-time.Duration(req.UnusedDays) * 24 * time.Hour)
+	}
+	deleteAfter := state.now.Add(time.Duration(req.NoticeDays) * 24 * time.Hour)
+
+	repositories := map[string][]*model.Image{}
+	for _, image := range state.images {
+		repository, _, ok := splitImageGCLink(image.ImageLink)
+		if !ok || !strings.HasPrefix(repository, state.registry+"/") {
+			continue
+		}
+		repositories[repository] = append(repositories[repository], image)
+	}
+
+	// Images of the same link share the registry artifact, so one kept record keeps them all.
+	kept := map[string]bool{}
+	selected := []ImageGCCandidate{}
+	for _, images := range repositories {
+		sort.Slice(images, func(i, j int) bool {
+			if images[i].CreatedAt.Equal(images[j].CreatedAt) {
+				return images[i].ID > images[j].ID
+			}
+			return images[i].CreatedAt.After(images[j].CreatedAt)
+		})
+		rank := 0
+		for _, image := range images {
+			manifest := state.manifests[image.ImageLink]
+			untagged := manifest != "" && !state.tagged[manifest]
+			newest := false
+			if !untagged || !req.DeleteUntagged {
+				newest = rank < req.KeepLastPerRepository
+				rank++
+			}
+
+			lastActivity := image.CreatedAt
+			var lastUsedAt *time.Time
+			if used, ok := state.lastUsed[image.ImageLink]; ok {
+				lastUsedAt = &used
+				if used.After(lastActivity) {
+					lastActivity = used
+				}
+			}
+			recent := req.UnusedDays > 0 && lastActivity.After(cutoff)
+
+			var reason string
+			switch {
+			case *req.SkipShared && (image.IsPublic || state.shared[image.ID]),
+				state.inUse[image.ImageLink], recent, newest:
+			case untagged && req.DeleteUntagged:
+				reason = ImageGCReasonUntagged
+			case req.KeepLastPerRepository > 0:
+				reason = ImageGCReasonSuperseded
+			case req.UnusedDays > 0:
+				reason = ImageGCReasonUnused
+			}
+			if reason == "" {
+				kept[image.ImageLink] = true
+				continue
+			}
+			selected = append(selected, ImageGCCandidate{
+				ImageID:     image.ID,
+				ImageLink:   image.ImageLink,
+				UserID:      image.UserID,
+				Username:    image.User.Name,
+				Reason:      reason,
+				Size:        image.Size,
+				CreatedAt:   image.CreatedAt,
+				LastUsedAt:  lastUsedAt,
+				DeleteAfter: deleteAfter,
+			})
+		}
+	}
+
+	// Registries delete whole manifests, so a manifest stays while a kept image or a tag no
+	// candidate stands for points at it.
+	selectedLinks := map[string]bool{}
+	for i := range selected {
+		selectedLinks[selected[i].ImageLink] = true
+	}
+	keptManifests := map[string]bool{}
+	for link := range kept {
+		if manifest := state.manifests[link]; manifest != "" {
+			keptManifests[manifest] = true
+		}
+	}
+	for link, manifest := range state.manifests {
+		if link != manifest && !selectedLinks[link] {
+			keptManifests[manifest] = true
+		}
+	}
+	candidates := make([]ImageGCCandidate, 0, len(selected))
+	for i := range selected {
+		link := selected[i].ImageLink
+		if !kept[link] && !keptManifests[state.manifests[link]] {
+			candidates = append(candidates, selected[i])
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].CreatedAt.Equal(candidates[j].CreatedAt) {
+			return candidates[i].ImageID < candidates[j].ImageID
+		}
+		return candidates[i].CreatedAt.Before(candidates[j].CreatedAt)
+	})
+	return candidates
+}
+
+func loadImageGCState(c context.Context, registry imageregistry.ImageRegistryInterface, req *ImageGCRequest) (*imageGCState, error) {
+	state := &imageGCState{
+		registry:  config.GetConfig().RegistryServer(),
+		now:       utils.GetLocalTime(),
+		shared:    map[uint]bool{},
+		inUse:     map[string]bool{},
+		lastUsed:  map[string]time.Time{},
+		manifests: map[string]string{},
+		tagged:    map[string]bool{},
+	}
+	i := query.Image
+	images, err := i.WithContext(c).Preload(i.User).Find()
+	if err != nil {
+		return nil, err
+	}
+	state.images = images
+	if err = state.loadManifests(c, registry); err != nil {
+		return nil, err
+	}
+
+	ia := query.ImageAccount
+	accountShares, err := ia.WithContext(c).Where(util.NotExpired(ia.ExpiresAt)).Find()
+	if err != nil {
+		return nil, err
+	}
+	for _, share := range accountShares {
+		state.shared[share.ImageID] = true
+	}
+	iu := query.ImageUser
+	userShares, err := iu.WithContext(c).Where(util.NotExpired(iu.ExpiresAt)).Find()
+	if err != nil {
+		return nil, err
+	}
+	for _, share := range userShares {
+		state.shared[share.ImageID] = true
+	}
+
+	j := query.Job
+	jobs := j.WithContext(c).Select(j.Status, j.CreationTimestamp, j.Attributes)
+	unfinished := j.Status.NotIn(finishedJobPhases()...)
+	if req.UnusedDays > 0 {
+		cutoff := state.now.Add(-time.Duration(req.UnusedDays) * 24 * time.Hour)
+		jobs = jobs.Where(field.Or(j.CreationTimestamp.Gte(cutoff), unfinished))
+	} else {
+		jobs = jobs.Where(unfinished)
+	}
+	records, err := jobs.Find()
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		finished := isFinishedJobPhase(record.Status)
+		for _, link := range jobImageLinks(record) {
+			if !finished {
+				state.inUse[link] = true
+			}
+			if used, ok := state.lastUsed[link]; !ok || record.CreationTimestamp.After(used) {
+				state.lastUsed[link] = record.CreationTimestamp
+			}
+		}
+	}
+	return state, nil
+}
+
+// loadManifests lists the repositories of the inner registry images.
+func (state *imageGCState) loadManifests(c context.Context, registry imageregistry.ImageRegistryInterface) error {
+	listed := map[string]bool{}
+	for _, image := range state.images {
+		repository, _, ok := splitImageGCLink(image.ImageLink)
+		if !ok || listed[repository] {
+			continue
+		}
+		name, inRegistry := strings.CutPrefix(repository, state.registry+"/")
+		if !inRegistry {
+			continue
+		}
+		listed[repository] = true
+		artifacts, err := registry.ListArtifacts(c, name)
+		if err != nil {
+			return fmt.Errorf("list artifacts of %s: %w", repository, err)
+		}
+		for _, artifact := range artifacts {
+			manifest := repository + "@" + artifact.Digest
+			state.manifests[manifest] = manifest
+			for _, tag := range artifact.Tags {
+				state.manifests[repository+":"+tag] = manifest
+				state.tagged[manifest] = true
+			}
+		}
+	}
+	return nil
+}
+
+// splitImageGCLink splits registry/project/name:tag or registry/project/name@digest into the
+// repository registry/project/name and the reference.
+func splitImageGCLink(link string) (repository, reference string, ok bool) {
+	if name, digest, found := strings.Cut(link, "@"); found {
+		return name, digest, strings.Count(name, "/") >= 2 && digest != ""
+	}
+	ip, project, name, tag, err := utils.SplitImageLink(link)
+	if err != nil {
+		return "", "", false
+	}
+	return strings.Join([]string{ip, project, name}, "/"), tag, true
+}
+
+func loadImageGCMarks(c context.Context) (map[uint]*model.ImageGCMark, error) {
+	m := query.ImageGCMark
+	marks, err := m.WithContext(c).Find()
+	if err != nil {
+		return nil, err
+	}
+	byImage := make(map[uint]*model.ImageGCMark, len(marks))
+	for _, mark := range marks {
+		byImage[mark.ImageID] = mark
+	}
+	return byImage, nil
+}
+
+func finishedJobPhases() []string {
+	return []string{
+		string(batch.Completed),
+		string(batch.Failed),
+		string(batch.Aborted),
+		string(batch.Terminated),
+		string(model.Deleted),
+		string(model.Freed),
+	}
+}
+
+func isFinishedJobPhase(phase batch.JobPhase) bool {
+	return slices.Contains(finishedJobPhases(), string(phase))
+}
+
+// jobImageLinks returns the images of the containers and init containers of a job.
+func jobImageLinks(job *model.Job) []string {
+	vcjob := job.Attributes.Data()
+	if vcjob == nil {
+		return nil
+	}
+	links := []string{}
+	for i := range vcjob.Spec.Tasks {
+		spec := &vcjob.Spec.Tasks[i].Template.Spec
+		for j := range spec.InitContainers {
+			links = append(links, spec.InitContainers[j].Image)
+		}
+		for j := range spec.Containers {
+			links = append(links, spec.Containers[j].Image)
+		}
+	}
+	return links
+}
+
+// imageGCRegistry returns the registry of the clients, or one created from the config.
+func imageGCRegistry(clients *Clients) imageregistry.ImageRegistryInterface {
+	if clients != nil && clients.ImageRegistry != nil {
+		return clients.ImageRegistry
+	}
+	return imageregistry.NewImageRegistry()
+}
+
+// deleteStaleImage removes the image from the registry, then its records.
+func deleteStaleImage(c context.Context, clients *Clients, candidate *ImageGCCandidate) error {
+	if err := imageGCRegistry(clients).DeleteImageFromProject(c, candidate.ImageLink); err != nil &&
+		!errors.Is(err, imageregistry.ErrImageNotInRegistry) {
+		return err
+	}
+	i := query.Image
+	var imageIDs []uint
+	if err := i.WithContext(c).Where(i.ImageLink.Eq(candidate.ImageLink)).Pluck(i.ID, &imageIDs); err != nil {
+		return err
+	}
+	if len(imageIDs) == 0 {
+		return nil
+	}
+	if _, err := i.WithContext(c).Where(i.ID.In(imageIDs...)).Delete(); err != nil {
+		return err
+	}
+	s := query.ImageScan
+	if _, err := s.WithContext(c).Where(s.ImageID.In(imageIDs...)).Delete(); err != nil {
+		return err
+	}
+	m := query.ImageGCMark
+	_, err := m.WithContext(c).Unscoped().Where(m.ImageID.In(imageIDs...)).Delete()
+	return err
+}
+
+// notifyImageCleanup tells an owner which of their images will be deleted, and when.
+func notifyImageCleanup(c context.Context, userID uint, images []ImageGCCandidate) error {
+	lines := make([]string, 0, len(images))
+	for i := range images {
+		lines = append(lines, fmt.Sprintf("<li>%s（%s，将于 %s 后删除）</li>",
+			images[i].ImageLink, imageGCReasonText(images[i].Reason), images[i].DeleteAfter.Format(time.DateTime)))
+	}
+	notice := &alert.Notice{
+		Title: "镜像即将被清理",
+		Message: fmt.Sprintf("根据镜像保留策略，您的 %d 个镜像将被删除：<ul>%s</ul>"+
+			"如需保留，请在删除前联系管理员。",
+			len(images), strings.Join(lines, "")),
+		URL:        fmt.Sprintf("https://%s/portal/env/images", config.GetConfig().Host),
+		ButtonText: "查看镜像",
+	}
+	return alert.GetAlertMgr().NotifyUserEvent(c, userID, 0, model.NotificationEventImageCleanup, notice)
+}
+
+func imageGCReasonText(reason string) string {
+	switch reason {
+	case ImageGCReasonUntagged:
+		return "没有标签指向该镜像"
+	case ImageGCReasonSuperseded:
+		return "已有更新的同名镜像"
+	default:
+		return "长期未被作业使用"
+	}
+}
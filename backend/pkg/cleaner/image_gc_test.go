@@ -0,0 +1,253 @@
+package cleaner
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	corev1 "k8s.io/api/core/v1"
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+
+	"github.com/raids-lab/crater/dao/model"
+)
+
+const testRegistry = "harbor.example.com"
+
+func testGCImage(id uint, link string, age time.Duration, now time.Time) *model.Image {
+	return &model.Image{
+		Model:     gorm.Model{ID: id, CreatedAt: now.Add(-age)},
+		UserID:    7,
+		User:      model.User{Name: "alice"},
+		ImageLink: link,
+		Size:      int64(id) * 100,
+	}
+}
+
+func newTestGCState(now time.Time, images ...*model.Image) *imageGCState {
+	return &imageGCState{
+		registry:  testRegistry,
+		now:       now,
+		images:    images,
+		shared:    map[uint]bool{},
+		inUse:     map[string]bool{},
+		lastUsed:  map[string]time.Time{},
+		manifests: map[string]string{},
+		tagged:    map[string]bool{},
+	}
+}
+
+// listManifest records a manifest of the registry listing and the tags pointing at it.
+func (state *imageGCState) listManifest(repository, digest string, tags ...string) {
+	manifest := repository + "@" + digest
+	state.manifests[manifest] = manifest
+	for _, tag := range tags {
+		state.manifests[repository+":"+tag] = manifest
+		state.tagged[manifest] = true
+	}
+}
+
+func candidateReasons(candidates []ImageGCCandidate) map[uint]string {
+	reasons := map[uint]string{}
+	for i := range candidates {
+		reasons[candidates[i].ImageID] = candidates[i].Reason
+	}
+	return reasons
+}
+
+func TestPlanImageGCKeepLastPerRepository(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	repo := testRegistry + "/user-alice/train:"
+	state := newTestGCState(now,
+		testGCImage(1, repo+"v1", 40*day, now),
+		testGCImage(2, repo+"v2", 30*day, now),
+		testGCImage(3, repo+"v3", 20*day, now),
+		testGCImage(4, repo+"v4", 10*day, now),
+		testGCImage(5, repo+"v5", 5*day, now),
+		testGCImage(6, "docker.io/library/ubuntu:22.04", 90*day, now),
+		// Same link as the newest image, so it shares the kept registry artifact.
+		testGCImage(7, repo+"v5", 50*day, now),
+	)
+	state.shared[2] = true
+	state.inUse[repo+"v3"] = true
+
+	skipShared := true
+	req := &ImageGCRequest{KeepLastPerRepository: 2, NoticeDays: 3, SkipShared: &skipShared}
+	candidates := planImageGC(req, state)
+	if got, want := candidateReasons(candidates), map[uint]string{1: ImageGCReasonSuperseded}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("candidates = %v, want %v", got, want)
+	}
+	if candidates[0].Username != "alice" || !candidates[0].DeleteAfter.Equal(now.Add(3*day)) {
+		t.Fatalf("unexpected candidate: %+v", candidates[0])
+	}
+
+	skipShared = false
+	candidates = planImageGC(req, state)
+	if got, want := candidateReasons(candidates), map[uint]string{
+		1: ImageGCReasonSuperseded, 2: ImageGCReasonSuperseded,
+	}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("candidates without skipShared = %v, want %v", got, want)
+	}
+	if candidates[0].ImageID != 1 {
+		t.Fatalf("candidates are not oldest first: %+v", candidates)
+	}
+}
+
+func TestPlanImageGCUnusedDays(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	repo := testRegistry + "/user-alice/"
+	state := newTestGCState(now,
+		testGCImage(1, repo+"old:v1", 60*day, now),
+		testGCImage(2, repo+"used:v1", 60*day, now),
+		testGCImage(3, repo+"new:v1", 10*day, now),
+	)
+	state.lastUsed[repo+"used:v1"] = now.Add(-5 * day)
+
+	req := &ImageGCRequest{UnusedDays: 30}
+	if err := req.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	candidates := planImageGC(req, state)
+	if got, want := candidateReasons(candidates), map[uint]string{1: ImageGCReasonUnused}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("candidates = %v, want %v", got, want)
+	}
+}
+
+func TestPlanImageGCIgnoresImageLabels(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	repo := testRegistry + "/user-alice/snapshot:"
+	labelled := testGCImage(1, repo+"a", 3*day, now)
+	labelled.Tags = datatypes.NewJSONType([]string{"keep"})
+	state := newTestGCState(now,
+		labelled,
+		testGCImage(2, repo+"b", 2*day, now),
+		testGCImage(3, repo+"c", 1*day, now),
+	)
+
+	// Image labels are descriptions shown in the portal, not registry tags, so unlabelled
+	// images take their places among the newest like any other.
+	req := &ImageGCRequest{KeepLastPerRepository: 2}
+	if err := req.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := candidateReasons(planImageGC(req, state)), map[uint]string{1: ImageGCReasonSuperseded}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("candidates = %v, want %v", got, want)
+	}
+}
+
+func TestPlanImageGCDeleteUntagged(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	repo := testRegistry + "/user-alice/snapshot"
+	state := newTestGCState(now,
+		testGCImage(1, repo+":a", 4*day, now),
+		// The tag b was pushed again, leaving the manifest of image 2 without tags.
+		testGCImage(2, repo+"@sha256:old", 3*day, now),
+		testGCImage(3, repo+"@sha256:used", 2*day, now),
+		testGCImage(4, repo+":b", 1*day, now),
+	)
+	state.listManifest(repo, "sha256:a", "a")
+	state.listManifest(repo, "sha256:old")
+	state.listManifest(repo, "sha256:used")
+	state.listManifest(repo, "sha256:b", "b")
+	state.inUse[repo+"@sha256:used"] = true
+
+	req := &ImageGCRequest{DeleteUntagged: true}
+	if err := req.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := candidateReasons(planImageGC(req, state)), map[uint]string{2: ImageGCReasonUntagged}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("candidates = %v, want %v", got, want)
+	}
+
+	// Untagged images do not take the places kept by keepLastPerRepository.
+	req = &ImageGCRequest{DeleteUntagged: true, KeepLastPerRepository: 2}
+	if err := req.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := candidateReasons(planImageGC(req, state)), map[uint]string{2: ImageGCReasonUntagged}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("candidates with keepLastPerRepository = %v, want %v", got, want)
+	}
+}
+
+func TestPlanImageGCKeepsSharedManifests(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	repo := testRegistry + "/user-alice/train"
+	state := newTestGCState(now,
+		testGCImage(1, repo+":v1", 40*day, now),
+		testGCImage(2, repo+":v2", 30*day, now),
+		testGCImage(3, repo+":v3", 20*day, now),
+		testGCImage(4, repo+":latest", 10*day, now),
+	)
+	// v1 and latest are the same manifest, and v2 also carries a tag without an image record.
+	state.listManifest(repo, "sha256:one", "v1", "latest")
+	state.listManifest(repo, "sha256:two", "v2", "nightly")
+	state.listManifest(repo, "sha256:three", "v3")
+
+	req := &ImageGCRequest{KeepLastPerRepository: 1}
+	if err := req.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := candidateReasons(planImageGC(req, state)), map[uint]string{3: ImageGCReasonSuperseded}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("candidates = %v, want %v", got, want)
+	}
+}
+
+func TestSplitImageGCLink(t *testing.T) {
+	for link, want := range map[string][2]string{
+		testRegistry + "/user-alice/train:v1":       {testRegistry + "/user-alice/train", "v1"},
+		testRegistry + "/user-alice/a/b@sha256:abc": {testRegistry + "/user-alice/a/b", "sha256:abc"},
+		testRegistry + "/user-alice/a/b:v1":         {testRegistry + "/user-alice/a/b", "v1"},
+	} {
+		repository, reference, ok := splitImageGCLink(link)
+		if !ok || repository != want[0] || reference != want[1] {
+			t.Errorf("splitImageGCLink(%q) = %q, %q, %v; want %q, %q", link, repository, reference, ok, want[0], want[1])
+		}
+	}
+	for _, link := range []string{"ubuntu@sha256:abc", "ubuntu:22.04", testRegistry + "/train@"} {
+		if _, _, ok := splitImageGCLink(link); ok {
+			t.Errorf("splitImageGCLink(%q) should fail", link)
+		}
+	}
+}
+
+func TestImageGCRequestValidate(t *testing.T) {
+	for _, req := range []*ImageGCRequest{
+		nil,
+		{},
+		{KeepLastPerRepository: -1, DeleteUntagged: true},
+		{UnusedDays: 30, NoticeDays: -1},
+	} {
+		if err := req.Validate(); err == nil {
+			t.Errorf("Validate(%+v) succeeded, want an error", req)
+		}
+	}
+	req := &ImageGCRequest{KeepLastPerRepository: 3}
+	if err := req.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if req.SkipShared == nil || !*req.SkipShared {
+		t.Fatal("skipShared should default to true")
+	}
+}
+
+func TestJobImageLinks(t *testing.T) {
+	vcjob := &batch.Job{Spec: batch.JobSpec{Tasks: []batch.TaskSpec{{
+		Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Image: "busybox:latest"}},
+			Containers:     []corev1.Container{{Image: testRegistry + "/user-alice/train:v1"}},
+		}},
+	}}}}
+	job := &model.Job{Attributes: datatypes.NewJSONType(vcjob)}
+	if got, want := jobImageLinks(job), []string{"busybox:latest", testRegistry + "/user-alice/train:v1"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("jobImageLinks = %v, want %v", got, want)
+	}
+	if links := jobImageLinks(&model.Job{}); len(links) != 0 {
+		t.Fatalf("jobImageLinks of a job without attributes = %v", links)
+	}
+}
@@ -0,0 +1,35 @@
+package cleaner
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/pkg/utils"
+)
+
+type CleanStorageTrashRequest struct {
+	RetentionDays int `json:"retentionDays" form:"retentionDays" binding:"required"`
+}
+
+// CleanStorageTrash expires trashed files older than the retention period. The backend does not
+// mount the shared filesystem, so it only marks them; the storage server removes the data.
+func CleanStorageTrash(c context.Context, req *CleanStorageTrashRequest) (map[string]any, error) {
+	if req == nil {
+		return nil, errors.New("invalid request")
+	}
+	if req.RetentionDays <= 0 {
+		return nil, errors.New("retentionDays must be greater than 0")
+	}
+	cutoff := utils.GetLocalTime().Add(-time.Duration(req.RetentionDays) * 24 * time.Hour)
+	t := query.StorageTrashItem
+	info, err := t.WithContext(c).
+		Where(t.Status.Eq(string(model.StorageTrashTrashed)), t.TrashedAt.Lt(cutoff)).
+		UpdateSimple(t.Status.Value(string(model.StorageTrashExpired)))
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"expired": info.RowsAffected}, nil
+}
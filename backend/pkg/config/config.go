@@ -159,8 +159,12 @@ type Config struct {
 		// Optional: Defaults to false if not specified.
 		Enable bool `json:"enable"`
 
+		// Type selects the registry backend: "harbor" or "oci" (any OCI Distribution / Docker Registry v2 server).
+		// Optional: Defaults to "harbor" if not specified.
+		Type string `json:"type"`
+
 		// Harbor contains configuration for Harbor container registry integration.
-		// Required if Registry.Enable is true: All Harbor fields must be specified.
+		// Required if Registry.Enable is true and Type is "harbor": All Harbor fields must be specified.
 		Harbor struct {
 			// Server is the Harbor registry server URL.
 			// Required: Must be a valid Harbor instance URL.
@@ -175,6 +179,28 @@ type Config struct {
 			Password string `json:"password"`
 		} `json:"harbor"`
 
+		// OCI contains configuration for a plain OCI Distribution registry, such as registry:2 or Zot.
+		// Required if Registry.Enable is true and Type is "oci": Server must be specified.
+		OCI struct {
+			// Server is the registry host (and optional port) used in image links, e.g. registry.example.com:5000.
+			// Required: Must be reachable by the backend and by the build jobs.
+			Server string `json:"server"`
+
+			// Insecure talks plain HTTP to the registry instead of HTTPS.
+			// Optional: Defaults to false.
+			Insecure bool `json:"insecure"`
+
+			// User and Password enable basic authentication, also used to obtain bearer tokens
+			// when the registry delegates to a token service.
+			// Optional: If not specified, requests are anonymous.
+			User     string `json:"user"`
+			Password string `json:"password"`
+
+			// Token is a static bearer token sent with every request.
+			// Optional: Takes precedence over User and Password.
+			Token string `json:"token"`
+		} `json:"oci"`
+
 		// BuildTools contains configuration for container image building tools and proxies.
 		// Required if Registry.Enable is true.
 		BuildTools struct {
@@ -208,10 +234,42 @@ type Config struct {
 				// Envd image for environment-based development builds.
 				// Required if Registry.Enable is true.
 				Envd string `json:"envd"`
+
+				// Git image that clones repositories into the context of Git source builds.
+				// Optional: Defaults to "docker.io/alpine/git:v2.47.2" if not specified.
+				Git string `json:"git"`
 			} `json:"images"`
+
+			// Cache configures the registry-backed BuildKit cache of Dockerfile and pip/apt builds.
+			// Each user gets a cache repository in their own project, tagged by image name, so
+			// rebuilds of an image reuse its layers. The cache counts against the project quota.
+			// Optional: If Enable is false, builds neither import nor export a cache.
+			Cache struct {
+				// Enable adds --cache-from and --cache-to to Dockerfile and pip/apt builds.
+				// Optional: Defaults to false if not specified.
+				Enable bool `json:"enable"`
+
+				// Repository is the name of the cache repository in each user's project.
+				// Optional: Defaults to "buildcache" if not specified.
+				Repository string `json:"repository"`
+			} `json:"cache"`
 		} `json:"buildTools"`
 	} `json:"registry"`
 
+	// ImageScan configures the package inventory and vulnerability scan of built and uploaded images.
+	// Optional: If Enable is false, images are not scanned.
+	ImageScan struct {
+		// Enable starts the image scanner. Images are read from their registries, so inner
+		// images use the registry credentials above and other images are pulled anonymously.
+		// Optional: Defaults to false if not specified.
+		Enable bool `json:"enable"`
+
+		// VulnerabilityDB is the path of an offline vulnerability database in JSON. The file is
+		// reloaded when it changes, so it can be refreshed from a mounted ConfigMap or volume.
+		// Optional: If not specified, scans only record the package inventory.
+		VulnerabilityDB string `json:"vulnerabilityDB"`
+	} `json:"imageScan"`
+
 	// SMTP contains configuration for email notifications via SMTP.
 	// Optional: If Enable is false, email notifications will be disabled.
 	SMTP struct {
@@ -439,14 +497,27 @@ func (c *Config) ValidateConfig() error {
 
 	// Validate conditional configurations
 	if c.Registry.Enable {
-		if c.Registry.Harbor.Server == "" {
-			errors = append(errors, "registry.harbor.server is required when registry is enabled")
-		}
-		if c.Registry.Harbor.User == "" {
-			errors = append(errors, "registry.harbor.user is required when registry is enabled")
-		}
-		if c.Registry.Harbor.Password == "" {
-			errors = append(errors, "registry.harbor.password is required when registry is enabled")
+		switch c.RegistryType() {
+		case RegistryTypeHarbor:
+			if c.Registry.Harbor.Server == "" {
+				errors = append(errors, "registry.harbor.server is required when registry is enabled")
+			}
+			if c.Registry.Harbor.User == "" {
+				errors = append(errors, "registry.harbor.user is required when registry is enabled")
+			}
+			if c.Registry.Harbor.Password == "" {
+				errors = append(errors, "registry.harbor.password is required when registry is enabled")
+			}
+		case RegistryTypeOCI:
+			if c.Registry.OCI.Server == "" {
+				errors = append(errors, "registry.oci.server is required when registry type is oci")
+			}
+			if (c.Registry.OCI.User == "") != (c.Registry.OCI.Password == "") {
+				errors = append(errors, "registry.oci.user and registry.oci.password must be set together")
+			}
+		default:
+			errors = append(errors, fmt.Sprintf("registry.type must be %q or %q, got %q",
+				RegistryTypeHarbor, RegistryTypeOCI, c.Registry.Type))
 		}
 		if c.Registry.BuildTools.Images.Buildx == "" {
 			errors = append(errors, "registry.buildTools.images.buildx is required when registry is enabled")
@@ -625,7 +696,12 @@ func (c *Config) PrintConfig() {
 
 	// Registry
 	if c.Registry.Enable {
-		klog.Infof("Registry: Enabled (Harbor: %s, User: %s)", c.Registry.Harbor.Server, c.Registry.Harbor.User)
+		if c.RegistryType() == RegistryTypeOCI {
+			klog.Infof("Registry: Enabled (OCI: %s, Insecure: %t, User: %s)",
+				c.Registry.OCI.Server, c.Registry.OCI.Insecure, c.Registry.OCI.User)
+		} else {
+			klog.Infof("Registry: Enabled (Harbor: %s, User: %s)", c.Registry.Harbor.Server, c.Registry.Harbor.User)
+		}
 		klog.Infof("Build Tools: Buildx=%s, Nerdctl=%s, Envd=%s",
 			c.Registry.BuildTools.Images.Buildx, c.Registry.BuildTools.Images.Nerdctl, c.Registry.BuildTools.Images.Envd)
 		if c.Registry.BuildTools.ProxyConfig.HTTPProxy != "" || c.Registry.BuildTools.ProxyConfig.HTTPSProxy != "" {
@@ -0,0 +1,25 @@
+package config
+
+import "strings"
+
+const (
+	RegistryTypeHarbor = "harbor"
+	RegistryTypeOCI    = "oci"
+)
+
+// RegistryType returns the configured registry backend, defaulting to Harbor.
+func (c *Config) RegistryType() string {
+	registryType := strings.ToLower(strings.TrimSpace(c.Registry.Type))
+	if registryType == "" {
+		return RegistryTypeHarbor
+	}
+	return registryType
+}
+
+// RegistryServer returns the registry host used as the prefix of built image links.
+func (c *Config) RegistryServer() string {
+	if c.RegistryType() == RegistryTypeOCI {
+		return strings.TrimSuffix(c.Registry.OCI.Server, "/")
+	}
+	return c.Registry.Harbor.Server
+}
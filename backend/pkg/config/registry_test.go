@@ -0,0 +1,18 @@
+package config
+
+import "testing"
+
+func TestRegistryTypeAndServer(t *testing.T) {
+	var harborConfig Config
+	harborConfig.Registry.Harbor.Server = "harbor.example.com"
+	if harborConfig.RegistryType() != RegistryTypeHarbor || harborConfig.RegistryServer() != "harbor.example.com" {
+		t.Fatalf("unexpected default registry: %q %q", harborConfig.RegistryType(), harborConfig.RegistryServer())
+	}
+
+	var ociConfig Config
+	ociConfig.Registry.Type = " OCI "
+	ociConfig.Registry.OCI.Server = "registry.lab:5000/"
+	if ociConfig.RegistryType() != RegistryTypeOCI || ociConfig.RegistryServer() != "registry.lab:5000" {
+		t.Fatalf("unexpected oci registry: %q %q", ociConfig.RegistryType(), ociConfig.RegistryServer())
+	}
+}
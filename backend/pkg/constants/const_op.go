@@ -10,6 +10,15 @@ const (
 	OpTypeUpdateVPA           = "UpdateVPA"
 	OpTypeDeleteJob           = "DeleteJob"
 
+	// Manual billing balance mutations; the amounts themselves live in the billing ledger.
+	OpTypeAdjustExtraBalance = "AdjustExtraBalance"
+	OpTypeGrantExtraBalance  = "GrantExtraBalance"
+	// Billing budgets of accounts and their members.
+	OpTypeUpdateBillingBudget = "UpdateBillingBudget"
+	OpTypeDeleteBillingBudget = "DeleteBillingBudget"
+	// Fair-share weights used by the prequeue activation policy.
+	OpTypeUpdateFairShare = "UpdateFairShare"
+
 	// Execution Status
 	OpStatusSuccess = "Success"
 	OpStatusFailed  = "Failed"
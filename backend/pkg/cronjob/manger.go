@@ -29,6 +29,7 @@ func NewCronJobManager(
 	promClient monitor.PrometheusInterface,
 	gpuAnalysisService patrol.GpuAnalysisServiceInterface,
 	billingService patrol.BillingServiceInterface,
+	notificationService patrol.NotificationServiceInterface,
 ) *CronJobManager {
 	return &CronJobManager{
 		Client:     cli,
@@ -40,11 +41,12 @@ func NewCronJobManager(
 			PromClient: promClient,
 		},
 		patrolClients: &patrol.Clients{
-			Client:             cli,
-			KubeClient:         kubeClient,
-			PromClient:         promClient,
-			GpuAnalysisService: gpuAnalysisService,
-			BillingService:     billingService,
+			Client:              cli,
+			KubeClient:          kubeClient,
+			PromClient:          promClient,
+			GpuAnalysisService:  gpuAnalysisService,
+			BillingService:      billingService,
+			NotificationService: notificationService,
 		},
 		cron: cron.New(cron.WithLocation(time.Local)),
 	}
@@ -2,6 +2,17 @@ package imageregistry
 
 import (
 	"context"
+	"errors"
+
+	"github.com/raids-lab/crater/pkg/config"
+)
+
+var (
+	// ErrUnsupported is returned by registries without projects, users or quotas.
+	ErrUnsupported = errors.New("operation not supported by the image registry")
+
+	// ErrImageNotInRegistry is returned for image links that belong to another registry.
+	ErrImageNotInRegistry = errors.New("image is not in inner registry")
 )
 
 type ImageRegistryInterface interface {
@@ -21,6 +32,12 @@ type ImageRegistryInterface interface {
 	// GetImageSize gets the size of the image.
 	GetImageSize(ctx context.Context, fullImageName string) (int64, error)
 
+	// ImageExists checks whether the image tag exists in the registry.
+	ImageExists(ctx context.Context, fullImageURL string) (bool, error)
+
+	// ListArtifacts lists the manifests of a repository, given as project/name, with their tags.
+	ListArtifacts(ctx context.Context, repository string) ([]RegistryArtifact, error)
+
 	CheckOrCreateUser(ctx context.Context, userName string) (string, error)
 
 	CheckUserExist(ctx context.Context, userName string) bool
@@ -35,7 +52,14 @@ type ImageRegistryInterface interface {
 
 	GetProjectDetail(c context.Context, userName string) (PorjetcDetail, error)
 
-	GetHarborIP() string
+	// GetRegistryURL returns the URL of the registry server.
+	GetRegistryURL() string
+}
+
+// RegistryArtifact is a manifest of a repository and the tags pointing at it.
+type RegistryArtifact struct {
+	Digest string
+	Tags   []string
 }
 
 type PorjetcDetail struct {
@@ -50,6 +74,9 @@ type ImageRegistry struct {
 }
 
 func NewImageRegistry() ImageRegistryInterface {
+	if config.GetConfig().RegistryType() == config.RegistryTypeOCI {
+		return NewOCIRegistry(NewOCIClient())
+	}
 	harborClient := NewHarborClient()
 	return &ImageRegistry{
 		harborClient: &harborClient,
@@ -0,0 +1,340 @@
+package imageregistry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/raids-lab/crater/dao/query"
+)
+
+// ociUsageCacheTTL bounds how stale the project usage shown to users may be; summing it
+// walks the whole catalog.
+const ociUsageCacheTTL = 5 * time.Minute
+
+// ErrImageDigestShared is returned when an image cannot be removed without removing other
+// tags or images that point at the same manifest.
+var ErrImageDigestShared = errors.New("image manifest is shared with other tags or images")
+
+// OCIRegistry implements ImageRegistryInterface on a plain OCI Distribution registry.
+// Such registries have no projects, users or quotas: project creation is a no-op,
+// credential and quota updates return ErrUnsupported, and project usage is summed
+// from the manifests of the user's repositories.
+type OCIRegistry struct {
+	client *OCIClient
+
+	mu    sync.Mutex
+	usage map[string]ociProjectUsage
+}
+
+type ociProjectUsage struct {
+	used, images int64
+	at           time.Time
+}
+
+func NewOCIRegistry(client *OCIClient) ImageRegistryInterface {
+	return &OCIRegistry{client: client, usage: map[string]ociProjectUsage{}}
+}
+
+// parseImageReference splits registry/repository:tag or registry/repository@digest.
+func (r *OCIRegistry) parseImageReference(fullImageURL string) (repository, reference string, err error) {
+	prefix := r.client.RegistryServer + "/"
+	if !strings.HasPrefix(fullImageURL, prefix) {
+		return "", "", fmt.Errorf("%w: %s", ErrImageNotInRegistry, fullImageURL)
+	}
+	name := strings.TrimPrefix(fullImageURL, prefix)
+	if repository, reference, found := strings.Cut(name, "@"); found {
+		if repository == "" || reference == "" {
+			return "", "", fmt.Errorf("invalid full image url: %s", fullImageURL)
+		}
+		return repository, reference, nil
+	}
+	repository, reference = name, "latest"
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		repository, reference = name[:i], name[i+1:]
+	}
+	if repository == "" || reference == "" {
+		return "", "", fmt.Errorf("invalid full image url: %s", fullImageURL)
+	}
+	return repository, reference, nil
+}
+
+func (r *OCIRegistry) CheckOrCreateProjectForUser(_ context.Context, _ string) error {
+	// Repositories are created on first push.
+	return nil
+}
+
+func (r *OCIRegistry) UpdateQuotaForProject(_ context.Context, _ string, _ int64) error {
+	return ErrUnsupported
+}
+
+// DeleteImageFromProject removes a tag, or a manifest referenced by digest. Registries that
+// cannot delete tags only delete whole manifests, which would take every other tag of the
+// manifest with it, so such deletions are refused while the manifest is shared.
+func (r *OCIRegistry) DeleteImageFromProject(c context.Context, fullImageURL string) error {
+	repository, reference, err := r.parseImageReference(fullImageURL)
+	if err != nil {
+		return err
+	}
+	defer r.forgetUsage(repository)
+	tag := ""
+	digest := reference
+	if !strings.Contains(reference, ":") {
+		tag = reference
+		if digest, err = r.client.GetManifestDigest(c, repository, tag); err != nil {
+			if isRegistryNotFound(err) {
+				klog.Infof("image %s already removed from registry", fullImageURL)
+				return nil
+			}
+			return err
+		}
+		err = r.client.DeleteManifest(c, repository, tag)
+		if err == nil || isRegistryNotFound(err) {
+			return nil
+		}
+		if !isTagDeletionUnsupported(err) {
+			return err
+		}
+	}
+	if err = r.checkDigestUnshared(c, fullImageURL, repository, digest, tag); err != nil {
+		return err
+	}
+	// Blobs are reclaimed by the registry's own GC.
+	if err = r.client.DeleteManifest(c, repository, digest); err != nil && !isRegistryNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// isTagDeletionUnsupported reports the answers of registries, such as registry:2, that only
+// delete manifests by digest.
+func isTagDeletionUnsupported(err error) bool {
+	var statusErr *ociStatusError
+	return errors.As(err, &statusErr) &&
+		(statusErr.StatusCode == http.StatusBadRequest || statusErr.StatusCode == http.StatusMethodNotAllowed)
+}
+
+// checkDigestUnshared returns ErrImageDigestShared when a tag other than tag, or an image
+// record other than fullImageURL, points at digest.
+func (r *OCIRegistry) checkDigestUnshared(c context.Context, fullImageURL, repository, digest, tag string) error {
+	tags, err := r.client.ListTags(c, repository)
+	if err != nil {
+		return err
+	}
+	for _, other := range tags {
+		if other == tag {
+			continue
+		}
+		otherDigest, err := r.client.GetManifestDigest(c, repository, other)
+		if isRegistryNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if otherDigest == digest {
+			return fmt.Errorf("%w: %s is also tagged %s", ErrImageDigestShared, fullImageURL, other)
+		}
+	}
+	digestLink := r.client.RegistryServer + "/" + repository + "@" + digest
+	if digestLink == fullImageURL {
+		return nil
+	}
+	i := query.Image
+	count, err := i.WithContext(c).Where(i.ImageLink.Eq(digestLink)).Count()
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: %s is also used as %s", ErrImageDigestShared, fullImageURL, digestLink)
+	}
+	return nil
+}
+
+func (r *OCIRegistry) GetImageSize(c context.Context, fullImageName string) (int64, error) {
+	repository, reference, err := r.parseImageReference(fullImageName)
+	if err != nil {
+		return 0, err
+	}
+	return r.manifestSize(c, repository, reference)
+}
+
+// manifestSize sums the config and layers of a manifest, or of every platform of an index.
+func (r *OCIRegistry) manifestSize(c context.Context, repository, reference string) (int64, error) {
+	manifest, err := r.client.GetManifest(c, repository, reference)
+	if err != nil {
+		klog.Errorf("get image manifest failed! err:%+v", err)
+		return 0, err
+	}
+	if len(manifest.Manifests) > 0 {
+		var total int64
+		for _, child := range manifest.Manifests {
+			size, err := r.manifestSize(c, repository, child.Digest)
+			if err != nil {
+				return 0, err
+			}
+			total += size
+		}
+		return total, nil
+	}
+	size := manifest.Config.Size
+	for _, layer := range manifest.Layers {
+		size += layer.Size
+	}
+	return size, nil
+}
+
+func (r *OCIRegistry) ImageExists(c context.Context, fullImageURL string) (bool, error) {
+	repository, reference, err := r.parseImageReference(fullImageURL)
+	if err != nil {
+		return false, err
+	}
+	if _, err = r.client.GetManifestDigest(c, repository, reference); err != nil {
+		if isRegistryNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// ListArtifacts groups the tags of a repository by manifest. The distribution API cannot list
+// manifests without tags, so only tagged ones are reported.
+func (r *OCIRegistry) ListArtifacts(c context.Context, repository string) ([]RegistryArtifact, error) {
+	tags, err := r.client.ListTags(c, repository)
+	if err != nil {
+		return nil, err
+	}
+	artifacts := []RegistryArtifact{}
+	index := map[string]int{}
+	for _, tag := range tags {
+		digest, err := r.client.GetManifestDigest(c, repository, tag)
+		if isRegistryNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		i, ok := index[digest]
+		if !ok {
+			i = len(artifacts)
+			index[digest] = i
+			artifacts = append(artifacts, RegistryArtifact{Digest: digest})
+		}
+		artifacts[i].Tags = append(artifacts[i].Tags, tag)
+	}
+	return artifacts, nil
+}
+
+func (r *OCIRegistry) CheckOrCreateUser(_ context.Context, _ string) (string, error) {
+	return "", ErrUnsupported
+}
+
+func (r *OCIRegistry) CheckUserExist(_ context.Context, _ string) bool {
+	return false
+}
+
+func (r *OCIRegistry) AddProjectMember(_ context.Context, _ string) error {
+	return ErrUnsupported
+}
+
+func (r *OCIRegistry) CreateUser(_ context.Context, _ string) (string, error) {
+	return "", ErrUnsupported
+}
+
+func (r *OCIRegistry) DeleteUser(_ context.Context, _ string) error {
+	return nil
+}
+
+// GetProjectQuota reports the size of the repositories under projectName/. The registry
+// enforces no quota, so the nominal default quota is returned as the total.
+func (r *OCIRegistry) GetProjectQuota(c context.Context, projectName string) (used, total int64, err error) {
+	used, _, err = r.cachedProjectUsage(c, projectName)
+	if err != nil {
+		return 0, 0, err
+	}
+	return used, DefaultQuotaSize, nil
+}
+
+func (r *OCIRegistry) GetProjectDetail(c context.Context, userName string) (PorjetcDetail, error) {
+	projectName := fmt.Sprintf("user-%s", userName)
+	used, images, err := r.cachedProjectUsage(c, projectName)
+	if err != nil {
+		return PorjetcDetail{}, err
+	}
+	return PorjetcDetail{
+		ProjectName: projectName,
+		UsedSize:    used,
+		TotalSize:   DefaultQuotaSize,
+		ImageNumber: images,
+	}, nil
+}
+
+// cachedProjectUsage returns the usage of projectName computed within ociUsageCacheTTL, or
+// computes it again.
+func (r *OCIRegistry) cachedProjectUsage(c context.Context, projectName string) (used, images int64, err error) {
+	r.mu.Lock()
+	cached, ok := r.usage[projectName]
+	r.mu.Unlock()
+	if ok && time.Since(cached.at) < ociUsageCacheTTL {
+		return cached.used, cached.images, nil
+	}
+	if used, images, err = r.projectUsage(c, projectName); err != nil {
+		return 0, 0, err
+	}
+	r.mu.Lock()
+	if r.usage == nil {
+		r.usage = map[string]ociProjectUsage{}
+	}
+	r.usage[projectName] = ociProjectUsage{used: used, images: images, at: time.Now()}
+	r.mu.Unlock()
+	return used, images, nil
+}
+
+// forgetUsage drops the cached usage of the project holding repository.
+func (r *OCIRegistry) forgetUsage(repository string) {
+	projectName, _, _ := strings.Cut(repository, "/")
+	r.mu.Lock()
+	delete(r.usage, projectName)
+	r.mu.Unlock()
+}
+
+// projectUsage walks the catalog for repositories under projectName/. Layers shared
+// between tags are counted once per tag, so the result is an upper bound.
+func (r *OCIRegistry) projectUsage(c context.Context, projectName string) (used, images int64, err error) {
+	repositories, err := r.client.ListRepositories(c)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, repository := range repositories {
+		if !strings.HasPrefix(repository, projectName+"/") {
+			continue
+		}
+		tags, err := r.client.ListTags(c, repository)
+		if err != nil {
+			return 0, 0, err
+		}
+		for _, tag := range tags {
+			size, err := r.manifestSize(c, repository, tag)
+			if isRegistryNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return 0, 0, err
+			}
+			used += size
+			images++
+		}
+	}
+	return used, images, nil
+}
+
+func (r *OCIRegistry) GetRegistryURL() string {
+	return r.client.baseURL
+}
@@ -0,0 +1,327 @@
+package imageregistry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+)
+
+// fakeDistribution is a minimal in-memory stand-in for registry:2.
+type fakeDistribution struct {
+	mu        sync.Mutex
+	manifests map[string]map[string][]byte // repository -> digest -> manifest
+	tags      map[string]map[string]string // repository -> tag -> digest
+	token     string                       // when set, requests need this bearer token
+	realm     string
+}
+
+func newFakeDistribution() *fakeDistribution {
+	return &fakeDistribution{manifests: map[string]map[string][]byte{}, tags: map[string]map[string]string{}}
+}
+
+func (f *fakeDistribution) push(repository, tag string, manifest any) string {
+	body, _ := json.Marshal(manifest)
+	sum := sha256.Sum256(body)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.manifests[repository] == nil {
+		f.manifests[repository] = map[string][]byte{}
+		f.tags[repository] = map[string]string{}
+	}
+	f.manifests[repository][digest] = body
+	if tag != "" {
+		f.tags[repository][tag] = digest
+	}
+	return digest
+}
+
+func (f *fakeDistribution) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/token" {
+		_ = json.NewEncoder(w).Encode(map[string]string{"token": f.token})
+		return
+	}
+	if f.token != "" && r.Header.Get("Authorization") != "Bearer "+f.token {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s",service="fake"`, f.realm))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	path := strings.TrimPrefix(r.URL.Path, "/v2/")
+	if path == "_catalog" {
+		repositories := make([]string, 0, len(f.manifests))
+		for repository := range f.manifests {
+			repositories = append(repositories, repository)
+		}
+		_ = json.NewEncoder(w).Encode(map[string][]string{"repositories": repositories})
+		return
+	}
+	if repository, found := strings.CutSuffix(path, "/tags/list"); found {
+		if f.tags[repository] == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		tags := []string{}
+		for tag := range f.tags[repository] {
+			tags = append(tags, tag)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"name": repository, "tags": tags})
+		return
+	}
+	i := strings.LastIndex(path, "/manifests/")
+	if i < 0 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	repository, reference := path[:i], path[i+len("/manifests/"):]
+	digest := reference
+	if tagged, ok := f.tags[repository][reference]; ok {
+		digest = tagged
+	}
+	body, ok := f.manifests[repository][digest]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	switch r.Method {
+	case http.MethodDelete:
+		if digest != reference {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		delete(f.manifests[repository], digest)
+		for tag, tagged := range f.tags[repository] {
+			if tagged == digest {
+				delete(f.tags[repository], tag)
+			}
+		}
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		w.Header().Set("Docker-Content-Digest", digest)
+		w.Header().Set("Content-Type", mediaTypeOCIManifest)
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(body)
+		}
+	}
+}
+
+func newTestOCIRegistry(t *testing.T, fake *fakeDistribution) (*OCIRegistry, string) {
+	t.Helper()
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	fake.realm = server.URL + "/token"
+	host := strings.TrimPrefix(server.URL, "http://")
+	return NewOCIRegistry(NewOCIClientForServer(host, true, "robot", "secret", "")).(*OCIRegistry), host
+}
+
+// useTestImageDB points the default query at an in-memory database holding image records of links.
+func useTestImageDB(t *testing.T, name string, links ...string) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+name+"?mode=memory&cache=shared"), &gorm.Config{
+		DisableForeignKeyConstraintWhenMigrating: true,
+		IgnoreRelationshipsWhenMigrating:         true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = db.AutoMigrate(&model.Image{}); err != nil {
+		t.Fatal(err)
+	}
+	for _, link := range links {
+		if err = db.Create(&model.Image{ImageLink: link}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	query.SetDefault(db)
+}
+
+func imageManifest(configSize int64, layers ...int64) OCIManifest {
+	manifest := OCIManifest{MediaType: mediaTypeOCIManifest, Config: OCIDescriptor{Size: configSize}}
+	for _, size := range layers {
+		manifest.Layers = append(manifest.Layers, OCIDescriptor{Size: size})
+	}
+	return manifest
+}
+
+func TestOCIRegistryImageLifecycle(t *testing.T) {
+	fake := newFakeDistribution()
+	fake.push("user-alice/torch", "v1", imageManifest(10, 100, 200))
+	amd := fake.push("user-alice/multi", "", imageManifest(1, 10))
+	arm := fake.push("user-alice/multi", "", imageManifest(2, 20))
+	fake.push("user-alice/multi", "latest", OCIManifest{
+		MediaType: mediaTypeOCIIndex,
+		Manifests: []OCIDescriptor{{Digest: amd}, {Digest: arm}},
+	})
+	fake.push("user-bob/other", "v1", imageManifest(5, 5))
+	registry, host := newTestOCIRegistry(t, fake)
+	ctx := context.Background()
+	useTestImageDB(t, "oci_image_lifecycle")
+
+	if size, err := registry.GetImageSize(ctx, host+"/user-alice/torch:v1"); err != nil || size != 310 {
+		t.Fatalf("GetImageSize(torch) = %d, %v", size, err)
+	}
+	if size, err := registry.GetImageSize(ctx, host+"/user-alice/multi"); err != nil || size != 33 {
+		t.Fatalf("GetImageSize(multi index) = %d, %v", size, err)
+	}
+	if _, err := registry.GetImageSize(ctx, "docker.io/library/ubuntu:22.04"); !errors.Is(err, ErrImageNotInRegistry) {
+		t.Fatalf("foreign image should not be in registry, got %v", err)
+	}
+
+	detail, err := registry.GetProjectDetail(ctx, "alice")
+	if err != nil || detail.UsedSize != 343 || detail.ImageNumber != 2 || detail.TotalSize != DefaultQuotaSize {
+		t.Fatalf("GetProjectDetail = %+v, %v", detail, err)
+	}
+
+	if err = registry.DeleteImageFromProject(ctx, host+"/user-alice/torch:v1"); err != nil {
+		t.Fatalf("DeleteImageFromProject: %v", err)
+	}
+	if exist, err := registry.ImageExists(ctx, host+"/user-alice/torch:v1"); err != nil || exist {
+		t.Fatalf("deleted image still exists: %v, %v", exist, err)
+	}
+	if err = registry.DeleteImageFromProject(ctx, host+"/user-alice/torch:v1"); err != nil {
+		t.Fatalf("deleting a missing image should succeed: %v", err)
+	}
+	if exist, err := registry.ImageExists(ctx, host+"/user-bob/other:v1"); err != nil || !exist {
+		t.Fatalf("ImageExists(other) = %v, %v", exist, err)
+	}
+}
+
+func TestOCIRegistryRefusesSharedDigest(t *testing.T) {
+	fake := newFakeDistribution()
+	fake.push("user-alice/torch", "v1", imageManifest(10, 100))
+	fake.push("user-alice/torch", "stable", imageManifest(10, 100))
+	pinned := fake.push("user-alice/cuda", "v1", imageManifest(1, 2))
+	fake.push("user-alice/cuda", "v2", imageManifest(3, 4))
+	registry, host := newTestOCIRegistry(t, fake)
+	useTestImageDB(t, "oci_shared_digest", host+"/user-alice/cuda@"+pinned)
+	ctx := context.Background()
+
+	if err := registry.DeleteImageFromProject(ctx, host+"/user-alice/torch:v1"); !errors.Is(err, ErrImageDigestShared) {
+		t.Fatalf("deleting a tag sharing its manifest should be refused, got %v", err)
+	}
+	if exist, err := registry.ImageExists(ctx, host+"/user-alice/torch:stable"); err != nil || !exist {
+		t.Fatalf("the other tag must survive: %v, %v", exist, err)
+	}
+	if err := registry.DeleteImageFromProject(ctx, host+"/user-alice/cuda:v1"); !errors.Is(err, ErrImageDigestShared) {
+		t.Fatalf("deleting a manifest pinned by an image record should be refused, got %v", err)
+	}
+	if err := registry.DeleteImageFromProject(ctx, host+"/user-alice/cuda:v2"); err != nil {
+		t.Fatalf("DeleteImageFromProject(unshared): %v", err)
+	}
+}
+
+func TestOCIRegistryListArtifacts(t *testing.T) {
+	fake := newFakeDistribution()
+	shared := fake.push("user-alice/torch", "v1", imageManifest(10, 100))
+	fake.push("user-alice/torch", "latest", imageManifest(10, 100))
+	other := fake.push("user-alice/torch", "v2", imageManifest(1, 2))
+	fake.push("user-alice/torch", "", imageManifest(3, 4))
+	registry, _ := newTestOCIRegistry(t, fake)
+
+	artifacts, err := registry.ListArtifacts(context.Background(), "user-alice/torch")
+	if err != nil {
+		t.Fatalf("ListArtifacts: %v", err)
+	}
+	tags := map[string][]string{}
+	for _, artifact := range artifacts {
+		slices.Sort(artifact.Tags)
+		tags[artifact.Digest] = artifact.Tags
+	}
+	if len(tags) != 2 || !slices.Equal(tags[shared], []string{"latest", "v1"}) || !slices.Equal(tags[other], []string{"v2"}) {
+		t.Fatalf("ListArtifacts = %+v", artifacts)
+	}
+	if artifacts, err = registry.ListArtifacts(context.Background(), "user-alice/missing"); err != nil || len(artifacts) != 0 {
+		t.Fatalf("ListArtifacts(missing) = %+v, %v", artifacts, err)
+	}
+}
+
+func TestOCIRegistryCachesProjectUsage(t *testing.T) {
+	fake := newFakeDistribution()
+	fake.push("user-alice/torch", "v1", imageManifest(10, 100))
+	registry, host := newTestOCIRegistry(t, fake)
+	useTestImageDB(t, "oci_usage_cache")
+	ctx := context.Background()
+
+	if used, _, err := registry.GetProjectQuota(ctx, "user-alice"); err != nil || used != 110 {
+		t.Fatalf("GetProjectQuota = %d, %v", used, err)
+	}
+	fake.push("user-alice/cuda", "v1", imageManifest(1, 2))
+	if used, _, err := registry.GetProjectQuota(ctx, "user-alice"); err != nil || used != 110 {
+		t.Fatalf("usage should be served from the cache, got %d, %v", used, err)
+	}
+	if err := registry.DeleteImageFromProject(ctx, host+"/user-alice/torch:v1"); err != nil {
+		t.Fatalf("DeleteImageFromProject: %v", err)
+	}
+	if used, _, err := registry.GetProjectQuota(ctx, "user-alice"); err != nil || used != 3 {
+		t.Fatalf("usage should be recomputed after a deletion, got %d, %v", used, err)
+	}
+}
+
+func TestOCIRegistryBearerChallenge(t *testing.T) {
+	fake := newFakeDistribution()
+	fake.token = "issued-token"
+	fake.push("user-alice/torch", "v1", imageManifest(1, 2))
+	registry, host := newTestOCIRegistry(t, fake)
+
+	if size, err := registry.GetImageSize(context.Background(), host+"/user-alice/torch:v1"); err != nil || size != 3 {
+		t.Fatalf("GetImageSize with bearer challenge = %d, %v", size, err)
+	}
+}
+
+func TestOCIRegistryWithoutProjects(t *testing.T) {
+	registry, _ := newTestOCIRegistry(t, newFakeDistribution())
+	ctx := context.Background()
+	if err := registry.CheckOrCreateProjectForUser(ctx, "alice"); err != nil {
+		t.Fatalf("CheckOrCreateProjectForUser: %v", err)
+	}
+	if err := registry.UpdateQuotaForProject(ctx, "user-alice", 1); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("UpdateQuotaForProject should be unsupported, got %v", err)
+	}
+	if _, err := registry.CreateUser(ctx, "alice"); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("CreateUser should be unsupported, got %v", err)
+	}
+}
+
+func TestParseImageReference(t *testing.T) {
+	registry := &OCIRegistry{client: NewOCIClientForServer("registry.lab:5000", false, "", "", "")}
+	for link, want := range map[string]string{
+		"registry.lab:5000/user-a/torch:v1":            "user-a/torch v1",
+		"registry.lab:5000/user-a/nested/torch":        "user-a/nested/torch latest",
+		"registry.lab:5000/user-a/torch@sha256:abcdef": "user-a/torch sha256:abcdef",
+	} {
+		repository, reference, err := registry.parseImageReference(link)
+		if err != nil || repository+" "+reference != want {
+			t.Fatalf("parseImageReference(%q) = %q %q %v, want %q", link, repository, reference, err, want)
+		}
+	}
+	if _, _, err := registry.parseImageReference("registry.lab/user-a/torch:v1"); !errors.Is(err, ErrImageNotInRegistry) {
+		t.Fatalf("other host should not be in registry, got %v", err)
+	}
+}
+
+func TestParseAuthChallenge(t *testing.T) {
+	params := parseAuthChallenge(`realm="https://auth.example/token",service="registry",scope="repository:a/b:pull,push"`)
+	if params["realm"] != "https://auth.example/token" || params["service"] != "registry" ||
+		params["scope"] != "repository:a/b:pull,push" {
+		t.Fatalf("unexpected challenge params: %v", params)
+	}
+	if next := nextLink(`</v2/_catalog?last=b&n=2>; rel="next"`); next != "/v2/_catalog?last=b&n=2" {
+		t.Fatalf("unexpected next link %q", next)
+	}
+}
@@ -0,0 +1,396 @@
+package imageregistry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/raids-lab/crater/pkg/config"
+)
+
+const (
+	mediaTypeDockerManifest     = "application/vnd.docker.distribution.manifest.v2+json"
+	mediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+	mediaTypeOCIManifest        = "application/vnd.oci.image.manifest.v1+json"
+	mediaTypeOCIIndex           = "application/vnd.oci.image.index.v1+json"
+
+	ociRequestTimeout = 30 * time.Second
+	ociCatalogPage    = 1000
+	ociErrorBodyLimit = 512
+)
+
+var manifestAcceptTypes = []string{
+	mediaTypeOCIIndex, mediaTypeDockerManifestList, mediaTypeOCIManifest, mediaTypeDockerManifest,
+}
+
+// OCIClient talks to any registry implementing the OCI Distribution API (Docker Registry v2).
+// Requests carry a static bearer token or basic credentials; when the registry answers with a
+// bearer challenge, a token is fetched from its token service and cached per scope.
+type OCIClient struct {
+	// RegistryServer is the host (and optional port) used as the prefix of image links.
+	RegistryServer string
+
+	baseURL    string
+	user       string
+	password   string
+	token      string
+	httpClient *http.Client
+
+	mu     sync.Mutex
+	tokens map[string]string
+}
+
+// OCIDescriptor references a blob or a manifest.
+type OCIDescriptor struct {
+	MediaType string       `json:"mediaType"`
+	Digest    string       `json:"digest"`
+	Size      int64        `json:"size"`
+	Platform  *OCIPlatform `json:"platform,omitempty"`
+}
+
+// OCIPlatform is the platform of a manifest referenced by an index.
+type OCIPlatform struct {
+	OS           string `json:"os"`
+	Architecture string `json:"architecture"`
+	Variant      string `json:"variant,omitempty"`
+}
+
+// OCIManifest is an image manifest or, when Manifests is set, an index of per-platform manifests.
+type OCIManifest struct {
+	MediaType string          `json:"mediaType"`
+	Config    OCIDescriptor   `json:"config"`
+	Layers    []OCIDescriptor `json:"layers"`
+	Manifests []OCIDescriptor `json:"manifests"`
+}
+
+// ociStatusError is returned for unexpected registry responses.
+type ociStatusError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Body       string
+}
+
+func (e *ociStatusError) Error() string {
+	return fmt.Sprintf("registry %s %s returned %d: %s", e.Method, e.Path, e.StatusCode, e.Body)
+}
+
+func isRegistryNotFound(err error) bool {
+	var statusErr *ociStatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound
+}
+
+// dockerHubRegistry is where image links without a registry host, or on docker.io, are pulled from.
+const dockerHubRegistry = "registry-1.docker.io"
+
+// NewPullClient returns a client for reading images from registryHost. The inner registry is
+// accessed with the configured credentials; other registries are accessed anonymously.
+func NewPullClient(registryHost string) *OCIClient {
+	cfg := config.GetConfig()
+	if cfg.Registry.Enable && registryHost == cfg.RegistryServer() {
+		if cfg.RegistryType() == config.RegistryTypeOCI {
+			return NewOCIClient()
+		}
+		return NewOCIClientForServer(registryHost, false, cfg.Registry.Harbor.User, cfg.Registry.Harbor.Password, "")
+	}
+	if registryHost == "docker.io" || registryHost == "index.docker.io" {
+		registryHost = dockerHubRegistry
+	}
+	return NewOCIClientForServer(registryHost, false, "", "", "")
+}
+
+func NewOCIClient() *OCIClient {
+	ociConfig := config.GetConfig().Registry.OCI
+	return NewOCIClientForServer(config.GetConfig().RegistryServer(), ociConfig.Insecure,
+		ociConfig.User, ociConfig.Password, ociConfig.Token)
+}
+
+// NewOCIClientForServer returns a client for server; insecure selects plain HTTP.
+func NewOCIClientForServer(server string, insecure bool, user, password, token string) *OCIClient {
+	// Layers can take long to stream, so only the wait for response headers is bounded.
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = ociRequestTimeout
+	scheme := "https"
+	if insecure {
+		scheme = "http"
+	}
+	return &OCIClient{
+		RegistryServer: server,
+		baseURL:        fmt.Sprintf("%s://%s", scheme, server),
+		user:           user,
+		password:       password,
+		token:          token,
+		httpClient:     &http.Client{Transport: transport},
+		tokens:         map[string]string{},
+	}
+}
+
+// do sends a registry request, answering a bearer challenge once if needed.
+// The caller owns the response body.
+func (o *OCIClient) do(ctx context.Context, method, path, scope string, accept []string) (*http.Response, error) {
+	resp, err := o.send(ctx, method, path, scope, accept)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusUnauthorized || o.token != "" {
+		return resp, nil
+	}
+	challenge := resp.Header.Get("WWW-Authenticate")
+	resp.Body.Close()
+	if !strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+		return nil, &ociStatusError{Method: method, Path: path, StatusCode: http.StatusUnauthorized, Body: challenge}
+	}
+	if err = o.fetchToken(ctx, challenge, scope); err != nil {
+		return nil, err
+	}
+	return o.send(ctx, method, path, scope, accept)
+}
+
+func (o *OCIClient) send(ctx context.Context, method, path, scope string, accept []string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, o.baseURL+path, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	if len(accept) > 0 {
+		req.Header.Set("Accept", strings.Join(accept, ", "))
+	}
+	o.mu.Lock()
+	cached := o.tokens[scope]
+	o.mu.Unlock()
+	switch {
+	case o.token != "":
+		req.Header.Set("Authorization", "Bearer "+o.token)
+	case cached != "":
+		req.Header.Set("Authorization", "Bearer "+cached)
+	case o.user != "":
+		req.SetBasicAuth(o.user, o.password)
+	}
+	return o.httpClient.Do(req)
+}
+
+// fetchToken follows a `Bearer realm="...",service="...",scope="..."` challenge.
+func (o *OCIClient) fetchToken(ctx context.Context, challenge, scope string) error {
+	params := parseAuthChallenge(challenge[len("bearer "):])
+	realm := params["realm"]
+	if realm == "" {
+		return fmt.Errorf("registry bearer challenge without realm: %s", challenge)
+	}
+	query := url.Values{}
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	if challengeScope := params["scope"]; challengeScope != "" {
+		query.Set("scope", challengeScope)
+	} else if scope != "" {
+		query.Set("scope", scope)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm+"?"+query.Encode(), http.NoBody)
+	if err != nil {
+		return err
+	}
+	if o.user != "" {
+		req.SetBasicAuth(o.user, o.password)
+	}
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return newOCIStatusError(resp, http.MethodGet, realm)
+	}
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("decode registry token: %w", err)
+	}
+	token := body.Token
+	if token == "" {
+		token = body.AccessToken
+	}
+	if token == "" {
+		return fmt.Errorf("registry token service %s returned no token", realm)
+	}
+	o.mu.Lock()
+	o.tokens[scope] = token
+	o.mu.Unlock()
+	return nil
+}
+
+// parseAuthChallenge parses the comma separated key="value" pairs of a WWW-Authenticate header.
+func parseAuthChallenge(params string) map[string]string {
+	result := map[string]string{}
+	for params != "" {
+		params = strings.TrimLeft(params, " ,")
+		key, rest, found := strings.Cut(params, "=")
+		if !found {
+			break
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				value, rest = rest[1:], ""
+			} else {
+				value, rest = rest[1:end+1], rest[end+2:]
+			}
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+		result[key] = value
+		params = rest
+	}
+	return result
+}
+
+func newOCIStatusError(resp *http.Response, method, path string) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, ociErrorBodyLimit))
+	return &ociStatusError{Method: method, Path: path, StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(body))}
+}
+
+func repositoryScope(repository, actions string) string {
+	return fmt.Sprintf("repository:%s:%s", repository, actions)
+}
+
+// GetManifest fetches a manifest or an index by tag or digest.
+func (o *OCIClient) GetManifest(ctx context.Context, repository, reference string) (*OCIManifest, error) {
+	path := fmt.Sprintf("/v2/%s/manifests/%s", repository, reference)
+	resp, err := o.do(ctx, http.MethodGet, path, repositoryScope(repository, "pull"), manifestAcceptTypes)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, newOCIStatusError(resp, http.MethodGet, path)
+	}
+	manifest := &OCIManifest{}
+	if err = json.NewDecoder(resp.Body).Decode(manifest); err != nil {
+		return nil, fmt.Errorf("decode manifest %s:%s: %w", repository, reference, err)
+	}
+	return manifest, nil
+}
+
+// GetBlob streams a blob, such as an image config or a layer. The caller closes the reader.
+func (o *OCIClient) GetBlob(ctx context.Context, repository, digest string) (io.ReadCloser, error) {
+	path := fmt.Sprintf("/v2/%s/blobs/%s", repository, digest)
+	resp, err := o.do(ctx, http.MethodGet, path, repositoryScope(repository, "pull"), nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, newOCIStatusError(resp, http.MethodGet, path)
+	}
+	return resp.Body, nil
+}
+
+// GetManifestDigest resolves a tag to the digest of its manifest.
+func (o *OCIClient) GetManifestDigest(ctx context.Context, repository, reference string) (string, error) {
+	path := fmt.Sprintf("/v2/%s/manifests/%s", repository, reference)
+	resp, err := o.do(ctx, http.MethodHead, path, repositoryScope(repository, "pull"), manifestAcceptTypes)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", newOCIStatusError(resp, http.MethodHead, path)
+	}
+	digest := resp.Header.Get("Docker-Content-Digest")
+	if digest == "" {
+		return "", fmt.Errorf("registry returned no digest for %s:%s", repository, reference)
+	}
+	return digest, nil
+}
+
+// DeleteManifest deletes a manifest by digest. The registry must allow deletion
+// (REGISTRY_STORAGE_DELETE_ENABLED=true for registry:2).
+func (o *OCIClient) DeleteManifest(ctx context.Context, repository, digest string) error {
+	path := fmt.Sprintf("/v2/%s/manifests/%s", repository, digest)
+	resp, err := o.do(ctx, http.MethodDelete, path, repositoryScope(repository, "delete"), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusOK {
+		return newOCIStatusError(resp, http.MethodDelete, path)
+	}
+	return nil
+}
+
+// ListRepositories pages through the registry catalog.
+func (o *OCIClient) ListRepositories(ctx context.Context) ([]string, error) {
+	var repositories []string
+	path := fmt.Sprintf("/v2/_catalog?n=%d", ociCatalogPage)
+	for path != "" {
+		var page struct {
+			Repositories []string `json:"repositories"`
+		}
+		next, err := o.getJSON(ctx, path, "registry:catalog:*", &page)
+		if err != nil {
+			return nil, err
+		}
+		repositories = append(repositories, page.Repositories...)
+		path = next
+	}
+	return repositories, nil
+}
+
+// ListTags lists the tags of a repository; a missing repository has no tags.
+func (o *OCIClient) ListTags(ctx context.Context, repository string) ([]string, error) {
+	var tags []string
+	path := fmt.Sprintf("/v2/%s/tags/list", repository)
+	for path != "" {
+		var page struct {
+			Tags []string `json:"tags"`
+		}
+		next, err := o.getJSON(ctx, path, repositoryScope(repository, "pull"), &page)
+		if isRegistryNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, page.Tags...)
+		path = next
+	}
+	return tags, nil
+}
+
+// getJSON decodes a paginated response and returns the path of the next page, if any.
+func (o *OCIClient) getJSON(ctx context.Context, path, scope string, out any) (string, error) {
+	resp, err := o.do(ctx, http.MethodGet, path, scope, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", newOCIStatusError(resp, http.MethodGet, path)
+	}
+	if err = json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return "", fmt.Errorf("decode registry response %s: %w", path, err)
+	}
+	return nextLink(resp.Header.Get("Link")), nil
+}
+
+// nextLink extracts the path of a `<...>; rel="next"` Link header.
+func nextLink(header string) string {
+	target, params, found := strings.Cut(header, ";")
+	if !found || !strings.Contains(params, `rel="next"`) {
+		return ""
+	}
+	target = strings.Trim(strings.TrimSpace(target), "<>")
+	if parsed, err := url.Parse(target); err == nil && parsed.IsAbs() {
+		return parsed.RequestURI()
+	}
+	return target
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"math"
 	"regexp"
+	"slices"
 	"strings"
 
 	harbormodelv2 "github.com/mittwald/goharbor-client/v5/apiv2/model"
@@ -58,7 +59,7 @@ func (r *ImageRegistry) getImageInfo(fullImageURL string) (projectName, imageNam
 	// check if full image url starts with inner registry
 	if !strings.HasPrefix(fullImageURL, r.harborClient.RegistryServer) {
 		// skip delete if image is not in inner registry
-		return "", "", "", fmt.Errorf("image is not in inner registry: %s", fullImageURL)
+		return "", "", "", fmt.Errorf("%w: %s", ErrImageNotInRegistry, fullImageURL)
 	}
 
 	regexPattern := fmt.Sprintf(`%s/(.*?)/(.*?):(.*?)$`, r.harborClient.RegistryServer)
@@ -108,6 +109,56 @@ func (r *ImageRegistry) GetImageSize(c context.Context, fullImageName string) (i
 	return imageArtifact.Size, nil
 }
 
+func (r *ImageRegistry) ImageExists(c context.Context, fullImageURL string) (bool, error) {
+	projectName, imageName, imageTag, err := r.getImageInfo(fullImageURL)
+	if err != nil {
+		return false, err
+	}
+	// The not-found error of the artifact API is internal to the client library, so the registry API is
+	// asked instead to tell it apart from auth and transport failures.
+	client := NewPullClient(r.harborClient.RegistryServer)
+	if _, err = client.GetManifestDigest(c, projectName+"/"+imageName, imageTag); err != nil {
+		if isRegistryNotFound(err) {
+			return false, nil
+		}
+		klog.Warningf("get image manifest failed, err: %+v", err)
+		return false, err
+	}
+	return true, nil
+}
+
+// ListArtifacts lists the artifacts of a repository from the harbor API, including those
+// without tags. A missing project or repository has no artifacts.
+func (r *ImageRegistry) ListArtifacts(c context.Context, repository string) ([]RegistryArtifact, error) {
+	projectName, repositoryName, found := strings.Cut(repository, "/")
+	if !found {
+		return nil, fmt.Errorf("invalid repository: %s", repository)
+	}
+	if exist, err := r.harborClient.ProjectExists(c, projectName); err != nil || !exist {
+		return nil, err
+	}
+	repositories, err := r.harborClient.ListRepositories(c, projectName)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.ContainsFunc(repositories, func(repo *harbormodelv2.Repository) bool { return repo.Name == repository }) {
+		return nil, nil
+	}
+	artifacts, err := r.harborClient.ListArtifacts(c, projectName, repositoryName)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]RegistryArtifact, 0, len(artifacts))
+	for _, artifact := range artifacts {
+		item := RegistryArtifact{Digest: artifact.Digest}
+		for _, tag := range artifact.Tags {
+			item.Tags = append(item.Tags, tag.Name)
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
 // GenerateRandomPassword generates a random 10-character password
 func GenerateRandomPassword(length int) (string, error) {
 	bytes := make([]byte, length)
@@ -217,6 +268,6 @@ func (r *ImageRegistry) GetProjectQuota(c context.Context, projectName string) (
 	return quota.Used["storage"], quota.Hard["storage"], nil
 }
 
-func (r *ImageRegistry) GetHarborIP() string {
+func (r *ImageRegistry) GetRegistryURL() string {
 	return "https://" + r.harborClient.RegistryServer
 }
@@ -0,0 +1,310 @@
+package imagescan
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/raids-lab/crater/dao/model"
+)
+
+const (
+	osReleasePath    = "etc/os-release"
+	osReleaseLibPath = "usr/lib/os-release"
+	dpkgStatusPath   = "var/lib/dpkg/status"
+	dpkgStatusDir    = "var/lib/dpkg/status.d/"
+	apkInstalledPath = "lib/apk/db/installed"
+
+	whiteoutPrefix = ".wh."
+	opaqueWhiteout = ".wh..wh..opq"
+
+	// maxMetadataFileBytes bounds the package databases read into memory.
+	maxMetadataFileBytes = 64 << 20
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+	errUnsupportedLayer = errors.New("unsupported layer compression")
+)
+
+// OSRelease identifies the distribution of an image from os-release.
+type OSRelease struct {
+	ID         string
+	VersionID  string
+	PrettyName string
+}
+
+// inventory collects package databases while layers are applied in order. Whiteouts of
+// later layers remove what earlier layers added, as they would in the container filesystem.
+type inventory struct {
+	files  map[string][]byte
+	python map[string]model.ImageScanPackage
+}
+
+func newInventory() *inventory {
+	return &inventory{files: map[string][]byte{}, python: map[string]model.ImageScanPackage{}}
+}
+
+// readLayer applies a gzip-compressed or uncompressed layer tar.
+func (inv *inventory) readLayer(r io.Reader) error {
+	br := bufio.NewReader(r)
+	magic, _ := br.Peek(len(zstdMagic))
+	var layer io.Reader = br
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		layer = gz
+	case bytes.HasPrefix(magic, zstdMagic):
+		return errUnsupportedLayer
+	}
+
+	tr := tar.NewReader(layer)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read layer: %w", err)
+		}
+		name := strings.TrimPrefix(path.Clean("/"+hdr.Name), "/")
+		dir, base := path.Split(name)
+		switch {
+		case base == opaqueWhiteout:
+			inv.remove(strings.TrimSuffix(dir, "/"))
+		case strings.HasPrefix(base, whiteoutPrefix):
+			inv.remove(dir + strings.TrimPrefix(base, whiteoutPrefix))
+		default:
+			if err := inv.add(name, hdr, tr); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (inv *inventory) add(name string, hdr *tar.Header, content io.Reader) error {
+	if key, pkg, ok := pythonPackage(name); ok {
+		inv.python[key] = pkg
+		return nil
+	}
+	if !isMetadataFile(name) {
+		return nil
+	}
+	if hdr.Typeflag != tar.TypeReg {
+		// A link or directory replaces the file of a lower layer.
+		delete(inv.files, name)
+		return nil
+	}
+	data, err := io.ReadAll(io.LimitReader(content, maxMetadataFileBytes))
+	if err != nil {
+		return fmt.Errorf("read %s: %w", name, err)
+	}
+	inv.files[name] = data
+	return nil
+}
+
+// remove drops a path and everything below it.
+func (inv *inventory) remove(target string) {
+	under := func(name string) bool {
+		return target == "" || name == target || strings.HasPrefix(name, target+"/")
+	}
+	for name := range inv.files {
+		if under(name) {
+			delete(inv.files, name)
+		}
+	}
+	for name := range inv.python {
+		if under(name) {
+			delete(inv.python, name)
+		}
+	}
+}
+
+func isMetadataFile(name string) bool {
+	switch name {
+	case osReleasePath, osReleaseLibPath, dpkgStatusPath, apkInstalledPath:
+		return true
+	}
+	return strings.HasPrefix(name, dpkgStatusDir) && !strings.HasSuffix(name, ".md5sums")
+}
+
+// pythonPackage recognizes the metadata directories of installed distributions, such as
+// usr/lib/python3/dist-packages/numpy-1.26.4.dist-info, and returns the directory as key.
+func pythonPackage(name string) (key string, pkg model.ImageScanPackage, ok bool) {
+	for _, marker := range []string{"/site-packages/", "/dist-packages/"} {
+		i := strings.Index(name, marker)
+		if i < 0 {
+			continue
+		}
+		prefix := name[:i+len(marker)]
+		entry, _, _ := strings.Cut(name[len(prefix):], "/")
+		var stem string
+		switch {
+		case strings.HasSuffix(entry, ".dist-info"):
+			stem = strings.TrimSuffix(entry, ".dist-info")
+		case strings.HasSuffix(entry, ".egg-info"):
+			stem = strings.TrimSuffix(entry, ".egg-info")
+		default:
+			return "", pkg, false
+		}
+		// Metadata directories escape "-" in names and versions, so the first "-" separates them.
+		distribution, version, found := strings.Cut(stem, "-")
+		if !found || distribution == "" || version == "" {
+			return "", pkg, false
+		}
+		version, _, _ = strings.Cut(version, "-") // drop the -py3.x suffix of eggs
+		return prefix + entry, model.ImageScanPackage{
+			Type:    model.ScanPackagePyPI,
+			Name:    normalizePythonName(distribution),
+			Version: version,
+		}, true
+	}
+	return "", pkg, false
+}
+
+// normalizePythonName applies the PEP 503 name normalization.
+func normalizePythonName(name string) string {
+	name = strings.ToLower(name)
+	return strings.NewReplacer("_", "-", ".", "-").Replace(name)
+}
+
+// result returns the distribution and the installed packages sorted by type and name.
+func (inv *inventory) result() (OSRelease, []model.ImageScanPackage) {
+	osRelease := inv.files[osReleasePath]
+	if osRelease == nil {
+		osRelease = inv.files[osReleaseLibPath]
+	}
+	var packages []model.ImageScanPackage
+	packages = append(packages, parseDpkgStatus(inv.files[dpkgStatusPath], true)...)
+	names := make([]string, 0, len(inv.files))
+	for name := range inv.files {
+		if strings.HasPrefix(name, dpkgStatusDir) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		// Distroless images keep one status file per package without a Status field.
+		packages = append(packages, parseDpkgStatus(inv.files[name], false)...)
+	}
+	packages = append(packages, parseApkInstalled(inv.files[apkInstalledPath])...)
+	for _, pkg := range inv.python {
+		packages = append(packages, pkg)
+	}
+	return parseOSRelease(osRelease), dedupePackages(packages)
+}
+
+func dedupePackages(packages []model.ImageScanPackage) []model.ImageScanPackage {
+	sort.Slice(packages, func(i, j int) bool {
+		a, b := packages[i], packages[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Version < b.Version
+	})
+	result := packages[:0]
+	for i, pkg := range packages {
+		if i > 0 && pkg.Type == packages[i-1].Type && pkg.Name == packages[i-1].Name &&
+			pkg.Version == packages[i-1].Version {
+			continue
+		}
+		result = append(result, pkg)
+	}
+	return result
+}
+
+func parseOSRelease(data []byte) OSRelease {
+	var release OSRelease
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, found := strings.Cut(strings.TrimSpace(line), "=")
+		if !found {
+			continue
+		}
+		value = strings.Trim(value, `"'`)
+		switch key {
+		case "ID":
+			release.ID = strings.ToLower(value)
+		case "VERSION_ID":
+			release.VersionID = value
+		case "PRETTY_NAME":
+			release.PrettyName = value
+		}
+	}
+	if release.PrettyName == "" {
+		release.PrettyName = strings.TrimSpace(release.ID + " " + release.VersionID)
+	}
+	return release
+}
+
+// parseDpkgStatus reads the stanzas of a dpkg status file. With requireInstalled, only
+// packages whose Status ends in "installed" are returned.
+func parseDpkgStatus(data []byte, requireInstalled bool) []model.ImageScanPackage {
+	var packages []model.ImageScanPackage
+	for _, stanza := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n\n") {
+		fields := controlFields(stanza, ":")
+		if fields["Package"] == "" || fields["Version"] == "" {
+			continue
+		}
+		if requireInstalled && !strings.HasSuffix(fields["Status"], " installed") {
+			continue
+		}
+		// Source may carry the source version in parentheses: "openssl (3.0.2-0ubuntu1)".
+		source, _, _ := strings.Cut(fields["Source"], " ")
+		packages = append(packages, model.ImageScanPackage{
+			Type:    model.ScanPackageDeb,
+			Name:    fields["Package"],
+			Version: fields["Version"],
+			Source:  source,
+		})
+	}
+	return packages
+}
+
+// parseApkInstalled reads the P: (name), V: (version) and o: (origin) records of the apk database.
+func parseApkInstalled(data []byte) []model.ImageScanPackage {
+	var packages []model.ImageScanPackage
+	for _, record := range strings.Split(string(data), "\n\n") {
+		fields := controlFields(record, ":")
+		if fields["P"] == "" || fields["V"] == "" {
+			continue
+		}
+		packages = append(packages, model.ImageScanPackage{
+			Type:    model.ScanPackageApk,
+			Name:    fields["P"],
+			Version: fields["V"],
+			Source:  fields["o"],
+		})
+	}
+	return packages
+}
+
+// controlFields parses single-line "Key<sep> value" fields; continuation lines are skipped.
+func controlFields(stanza, sep string) map[string]string {
+	fields := map[string]string{}
+	for _, line := range strings.Split(stanza, "\n") {
+		if line == "" || line[0] == ' ' || line[0] == '\t' {
+			continue
+		}
+		key, value, found := strings.Cut(line, sep)
+		if found {
+			fields[key] = strings.TrimSpace(value)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,153 @@
+package imagescan
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"github.com/raids-lab/crater/dao/model"
+)
+
+type layerFile struct {
+	name    string
+	content string
+	link    bool
+}
+
+func buildLayer(t *testing.T, compress bool, files ...layerFile) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, file := range files {
+		hdr := &tar.Header{Name: file.name, Mode: 0o644, Size: int64(len(file.content)), Typeflag: tar.TypeReg}
+		if file.link {
+			hdr = &tar.Header{Name: file.name, Linkname: file.content, Typeflag: tar.TypeSymlink}
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatalf("write header: %v", err)
+		}
+		if !file.link {
+			if _, err := tw.Write([]byte(file.content)); err != nil {
+				t.Fatalf("write content: %v", err)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("close tar: %v", err)
+	}
+	if !compress {
+		return buf.Bytes()
+	}
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	_, _ = zw.Write(buf.Bytes())
+	_ = zw.Close()
+	return gz.Bytes()
+}
+
+const testDpkgStatus = `Package: openssl
+Status: install ok installed
+Version: 3.0.2-0ubuntu1.10
+Description: Secure Sockets Layer toolkit
+ continuation line
+
+Package: libssl3
+Status: install ok installed
+Source: openssl (3.0.2-0ubuntu1.10)
+Version: 3.0.2-0ubuntu1.10
+
+Package: removed-tool
+Status: deinstall ok config-files
+Version: 1.0
+`
+
+func ubuntuBaseLayer(t *testing.T) []byte {
+	return buildLayer(t, true,
+		layerFile{name: "usr/lib/os-release", content: "ID=ubuntu\nVERSION_ID=\"22.04\"\nPRETTY_NAME=\"Ubuntu 22.04.4 LTS\"\n"},
+		layerFile{name: "etc/os-release", content: "../usr/lib/os-release", link: true},
+		layerFile{name: "./var/lib/dpkg/status", content: testDpkgStatus},
+		layerFile{name: "usr/lib/python3/dist-packages/requests-2.25.1.egg-info/PKG-INFO", content: "Name: requests"},
+		layerFile{name: "usr/local/lib/python3.10/site-packages/NumPy-1.26.4.dist-info/METADATA", content: "Name: numpy"},
+		layerFile{name: "usr/local/lib/python3.10/site-packages/numpy/__init__.py"},
+	)
+}
+
+func packageList(packages []model.ImageScanPackage) []string {
+	result := make([]string, 0, len(packages))
+	for _, pkg := range packages {
+		result = append(result, pkg.Type+":"+pkg.Name+"@"+pkg.Version)
+	}
+	return result
+}
+
+func TestInventoryAppliesLayersAndWhiteouts(t *testing.T) {
+	inv := newInventory()
+	if err := inv.readLayer(bytes.NewReader(ubuntuBaseLayer(t))); err != nil {
+		t.Fatalf("read base layer: %v", err)
+	}
+	upgrade := buildLayer(t, false,
+		layerFile{name: "usr/local/lib/python3.10/site-packages/.wh.NumPy-1.26.4.dist-info"},
+		layerFile{name: "usr/local/lib/python3.10/site-packages/numpy-2.0.0.dist-info/METADATA", content: "Name: numpy"},
+		layerFile{name: "usr/local/lib/python3.10/site-packages/torch_cuda-2.3.0.dist-info/RECORD"},
+		layerFile{name: "usr/lib/python3/dist-packages/.wh..wh..opq"},
+	)
+	if err := inv.readLayer(bytes.NewReader(upgrade)); err != nil {
+		t.Fatalf("read upgrade layer: %v", err)
+	}
+
+	release, packages := inv.result()
+	if release.ID != "ubuntu" || release.VersionID != "22.04" || release.PrettyName != "Ubuntu 22.04.4 LTS" {
+		t.Fatalf("unexpected os release: %+v", release)
+	}
+	got := packageList(packages)
+	want := []string{
+		"deb:libssl3@3.0.2-0ubuntu1.10",
+		"deb:openssl@3.0.2-0ubuntu1.10",
+		"pypi:numpy@2.0.0",
+		"pypi:torch-cuda@2.3.0",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("packages = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("packages = %v, want %v", got, want)
+		}
+	}
+	if packages[0].Source != "openssl" {
+		t.Fatalf("libssl3 should keep its source package, got %+v", packages[0])
+	}
+}
+
+func TestInventoryReadsAlpineAndDistroless(t *testing.T) {
+	inv := newInventory()
+	layer := buildLayer(t, true,
+		layerFile{name: "etc/os-release", content: "ID=alpine\nVERSION_ID=3.19.1\n"},
+		layerFile{name: "lib/apk/db/installed", content: "C:Q1abc\nP:musl\nV:1.2.4_git20230717-r4\no:musl\n\nP:libcrypto3\nV:3.1.4-r5\no:openssl\n"},
+		layerFile{name: "var/lib/dpkg/status.d/tzdata", content: "Package: tzdata\nVersion: 2024a-0+deb12u1\n"},
+		layerFile{name: "var/lib/dpkg/status.d/tzdata.md5sums", content: "abc  usr/share/zoneinfo"},
+	)
+	if err := inv.readLayer(bytes.NewReader(layer)); err != nil {
+		t.Fatalf("read layer: %v", err)
+	}
+	release, packages := inv.result()
+	if release.PrettyName != "alpine 3.19.1" {
+		t.Fatalf("unexpected os release: %+v", release)
+	}
+	got := packageList(packages)
+	if len(got) != 3 || got[0] != "apk:libcrypto3@3.1.4-r5" || got[1] != "apk:musl@1.2.4_git20230717-r4" ||
+		got[2] != "deb:tzdata@2024a-0+deb12u1" {
+		t.Fatalf("unexpected packages: %v", got)
+	}
+	if packages[0].Source != "openssl" {
+		t.Fatalf("apk origin should be the source package, got %+v", packages[0])
+	}
+}
+
+func TestInventoryRejectsZstdLayers(t *testing.T) {
+	inv := newInventory()
+	if err := inv.readLayer(bytes.NewReader([]byte{0x28, 0xb5, 0x2f, 0xfd, 0})); err != errUnsupportedLayer {
+		t.Fatalf("zstd layer error = %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+package imagescan
+
+import (
+	"fmt"
+	"strings"
+)
+
+const defaultRegistryHost = "docker.io"
+
+// ParseImageReference splits an image link into registry host, repository and tag or digest,
+// following the Docker conventions for links without a host or namespace.
+func ParseImageReference(link string) (host, repository, reference string, err error) {
+	name := strings.TrimSpace(link)
+	if name == "" {
+		return "", "", "", fmt.Errorf("empty image link")
+	}
+	if before, digest, found := strings.Cut(name, "@"); found {
+		name, reference = before, digest
+	}
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		if reference == "" {
+			reference = name[i+1:]
+		}
+		name = name[:i]
+	}
+	if reference == "" {
+		reference = "latest"
+	}
+
+	host = defaultRegistryHost
+	if first, rest, found := strings.Cut(name, "/"); found &&
+		(strings.ContainsAny(first, ".:") || first == "localhost") {
+		host, name = first, rest
+	}
+	if host == defaultRegistryHost && !strings.Contains(name, "/") {
+		name = "library/" + name
+	}
+	if name == "" || strings.Contains(name, "//") {
+		return "", "", "", fmt.Errorf("invalid image link: %s", link)
+	}
+	return host, name, reference, nil
+}
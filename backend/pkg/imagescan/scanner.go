@@ -0,0 +1,353 @@
+package imagescan
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/pkg/config"
+	"github.com/raids-lab/crater/pkg/imageregistry"
+)
+
+const (
+	pollInterval = 15 * time.Second
+	scanTimeout  = 30 * time.Minute
+
+	defaultPlatformOS   = "linux"
+	defaultPlatformArch = "amd64"
+)
+
+// ErrScanDisabled is returned when a scan is requested while the scanner is disabled.
+var ErrScanDisabled = errors.New("image scanning is disabled")
+
+// RequestScan queues a (re)scan of an image; the scanner picks it up on its next poll.
+func RequestScan(ctx context.Context, imageID uint, imageLink string) error {
+	if !config.GetConfig().ImageScan.Enable {
+		return ErrScanDisabled
+	}
+	now := time.Now()
+	return query.ImageScan.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "image_id"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"status":       model.ImageScanPending,
+			"message":      "",
+			"image_link":   imageLink,
+			"requested_at": now,
+			"updated_at":   now,
+			"deleted_at":   nil,
+		}),
+	}).Create(&model.ImageScan{
+		ImageID:     imageID,
+		ImageLink:   imageLink,
+		Status:      model.ImageScanPending,
+		RequestedAt: now,
+	})
+}
+
+// BlocksPublishing tells whether a scan forbids making its image public under the threshold
+// severity, and why. An empty threshold never blocks; a missing or unfinished scan always does.
+func BlocksPublishing(threshold model.ScanSeverity, scan *model.ImageScan) (string, bool) {
+	if threshold == "" {
+		return "", false
+	}
+	if scan == nil {
+		return "the image has not been scanned", true
+	}
+	switch scan.Status {
+	case model.ImageScanSucceeded:
+	case model.ImageScanFailed:
+		return "the last scan of the image failed: " + scan.Message, true
+	default:
+		return "the scan of the image has not finished", true
+	}
+	if scan.DatabaseVersion == "" {
+		return "the image was scanned without a vulnerability database", true
+	}
+	if scan.MaxSeverity != "" && scan.MaxSeverity.Rank() >= threshold.Rank() {
+		return fmt.Sprintf("the image has %s vulnerabilities (critical %d, high %d, medium %d, low %d), "+
+			"publishing is blocked at %s and above",
+			scan.MaxSeverity, scan.Critical, scan.High, scan.Medium, scan.Low, threshold), true
+	}
+	return "", false
+}
+
+// Scanner scans the images with pending scans one at a time.
+type Scanner struct {
+	logger     logr.Logger
+	dbPath     string
+	pullClient func(host string) *imageregistry.OCIClient
+
+	mu        sync.Mutex
+	db        *VulnerabilityDB
+	dbModTime time.Time
+}
+
+func New() *Scanner {
+	return &Scanner{
+		logger:     ctrl.Log.WithName("image-scanner"),
+		dbPath:     config.GetConfig().ImageScan.VulnerabilityDB,
+		pullClient: imageregistry.NewPullClient,
+	}
+}
+
+func (s *Scanner) NeedLeaderElection() bool {
+	return true
+}
+
+// Start polls for pending scans under the manager lifecycle.
+func (s *Scanner) Start(ctx context.Context) error {
+	// Scans interrupted by a restart are retried.
+	is := query.ImageScan
+	if _, err := is.WithContext(ctx).Where(is.Status.Eq(string(model.ImageScanRunning))).
+		Update(is.Status, model.ImageScanPending); err != nil {
+		s.logger.Error(err, "failed to requeue interrupted image scans")
+	}
+	s.logger.Info("image scanner started", "vulnerabilityDB", s.dbPath)
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		if s.scanNext(ctx) {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// scanNext claims and runs the oldest pending scan; it returns false when there is none.
+func (s *Scanner) scanNext(ctx context.Context) bool {
+	is := query.ImageScan
+	scan, err := is.WithContext(ctx).Where(is.Status.Eq(string(model.ImageScanPending))).
+		Order(is.RequestedAt).First()
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) && ctx.Err() == nil {
+			s.logger.Error(err, "failed to fetch pending image scans")
+		}
+		return false
+	}
+	result, err := is.WithContext(ctx).
+		Where(is.ID.Eq(scan.ID), is.Status.Eq(string(model.ImageScanPending))).
+		Update(is.Status, model.ImageScanRunning)
+	if err != nil {
+		s.logger.Error(err, "failed to claim image scan", "imageID", scan.ImageID)
+		return false
+	}
+	if result.RowsAffected == 0 {
+		return true
+	}
+
+	updates := s.runScan(ctx, scan)
+	if _, err = is.WithContext(ctx).
+		Where(is.ID.Eq(scan.ID), is.Status.Eq(string(model.ImageScanRunning))).
+		Updates(updates); err != nil {
+		s.logger.Error(err, "failed to save image scan", "imageID", scan.ImageID)
+	}
+	return ctx.Err() == nil
+}
+
+// runScan scans the current link of the image and returns the columns to store.
+func (s *Scanner) runScan(ctx context.Context, scan *model.ImageScan) map[string]any {
+	now := time.Now()
+	failed := func(err error) map[string]any {
+		s.logger.Info("image scan failed", "imageID", scan.ImageID, "error", err.Error())
+		return map[string]any{"status": model.ImageScanFailed, "message": err.Error(), "scanned_at": now}
+	}
+	im := query.Image
+	image, err := im.WithContext(ctx).Where(im.ID.Eq(scan.ImageID)).First()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return failed(errors.New("the image no longer exists"))
+		}
+		return failed(err)
+	}
+
+	scanCtx, cancel := context.WithTimeout(ctx, scanTimeout)
+	defer cancel()
+	report, err := s.scanImage(scanCtx, image.ImageLink)
+	if err != nil {
+		return failed(err)
+	}
+
+	findings := []model.ImageScanFinding{}
+	databaseVersion := ""
+	if db, dbErr := s.vulnerabilityDB(); dbErr != nil {
+		report.notes = append(report.notes, dbErr.Error())
+	} else if db != nil {
+		findings = db.Match(report.release, report.packages)
+		databaseVersion = db.Version
+		if databaseVersion == "" {
+			databaseVersion = "unversioned"
+		}
+	}
+	counts := map[model.ScanSeverity]int{}
+	var maxSeverity model.ScanSeverity
+	for _, finding := range findings {
+		counts[finding.Severity]++
+		if maxSeverity == "" || finding.Severity.Rank() > maxSeverity.Rank() {
+			maxSeverity = finding.Severity
+		}
+	}
+	s.logger.Info("image scanned", "imageID", scan.ImageID, "packages", len(report.packages),
+		"findings", len(findings))
+	return map[string]any{
+		"status":           model.ImageScanSucceeded,
+		"message":          strings.Join(report.notes, "; "),
+		"scanned_at":       now,
+		"image_link":       image.ImageLink,
+		"digest":           report.digest,
+		"os":               report.release.PrettyName,
+		"arch":             report.arch,
+		"packages":         datatypes.NewJSONType(report.packages),
+		"findings":         datatypes.NewJSONType(findings),
+		"package_count":    len(report.packages),
+		"critical":         counts[model.ScanSeverityCritical],
+		"high":             counts[model.ScanSeverityHigh],
+		"medium":           counts[model.ScanSeverityMedium],
+		"low":              counts[model.ScanSeverityLow],
+		"unknown":          counts[model.ScanSeverityUnknown],
+		"max_severity":     maxSeverity,
+		"database_version": databaseVersion,
+	}
+}
+
+// vulnerabilityDB returns the database, reloading the file when it changed. It returns nil
+// without an error when no database is configured.
+func (s *Scanner) vulnerabilityDB() (*VulnerabilityDB, error) {
+	if s.dbPath == "" {
+		return nil, nil
+	}
+	info, err := os.Stat(s.dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("vulnerability database unavailable: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.db != nil && info.ModTime().Equal(s.dbModTime) {
+		return s.db, nil
+	}
+	data, err := os.ReadFile(s.dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("vulnerability database unavailable: %w", err)
+	}
+	db, err := ParseVulnerabilityDB(data)
+	if err != nil {
+		return nil, err
+	}
+	s.db, s.dbModTime = db, info.ModTime()
+	s.logger.Info("vulnerability database loaded", "version", db.Version, "entries", len(db.Vulnerabilities))
+	return db, nil
+}
+
+type imageReport struct {
+	digest   string
+	arch     string
+	release  OSRelease
+	packages []model.ImageScanPackage
+	notes    []string
+}
+
+// scanImage pulls the manifest, config and layers of an image and reads its packages.
+func (s *Scanner) scanImage(ctx context.Context, imageLink string) (*imageReport, error) {
+	host, repository, reference, err := ParseImageReference(imageLink)
+	if err != nil {
+		return nil, err
+	}
+	client := s.pullClient(host)
+	digest, err := client.GetManifestDigest(ctx, repository, reference)
+	if err != nil {
+		return nil, fmt.Errorf("resolve %s: %w", imageLink, err)
+	}
+	manifest, err := client.GetManifest(ctx, repository, digest)
+	if err != nil {
+		return nil, err
+	}
+	report := &imageReport{digest: digest}
+	if len(manifest.Manifests) > 0 {
+		platform, ok := selectPlatform(manifest.Manifests)
+		if !ok {
+			return nil, fmt.Errorf("image index %s has no platform manifest", imageLink)
+		}
+		if manifest, err = client.GetManifest(ctx, repository, platform.Digest); err != nil {
+			return nil, err
+		}
+	}
+
+	configBlob, err := client.GetBlob(ctx, repository, manifest.Config.Digest)
+	if err != nil {
+		return nil, fmt.Errorf("read image config: %w", err)
+	}
+	var imageConfig struct {
+		OS           string `json:"os"`
+		Architecture string `json:"architecture"`
+	}
+	err = json.NewDecoder(configBlob).Decode(&imageConfig)
+	configBlob.Close()
+	if err != nil {
+		return nil, fmt.Errorf("decode image config: %w", err)
+	}
+	report.arch = imageConfig.Architecture
+
+	inv := newInventory()
+	skipped := 0
+	for _, layer := range manifest.Layers {
+		blob, err := client.GetBlob(ctx, repository, layer.Digest)
+		if err != nil {
+			return nil, fmt.Errorf("read layer %s: %w", layer.Digest, err)
+		}
+		err = inv.readLayer(blob)
+		blob.Close()
+		if errors.Is(err, errUnsupportedLayer) {
+			skipped++
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("layer %s: %w", layer.Digest, err)
+		}
+	}
+	if skipped > 0 {
+		report.notes = append(report.notes, fmt.Sprintf("%d zstd layers were not scanned", skipped))
+	}
+	report.release, report.packages = inv.result()
+	if report.packages == nil {
+		report.packages = []model.ImageScanPackage{}
+	}
+	return report, nil
+}
+
+// selectPlatform prefers linux/amd64 and skips attestation manifests of unknown platform.
+func selectPlatform(manifests []imageregistry.OCIDescriptor) (imageregistry.OCIDescriptor, bool) {
+	var fallback *imageregistry.OCIDescriptor
+	for i := range manifests {
+		platform := manifests[i].Platform
+		if platform == nil || platform.OS == "unknown" {
+			continue
+		}
+		if platform.OS == defaultPlatformOS && platform.Architecture == defaultPlatformArch {
+			return manifests[i], true
+		}
+		if fallback == nil {
+			fallback = &manifests[i]
+		}
+	}
+	if fallback == nil {
+		return imageregistry.OCIDescriptor{}, false
+	}
+	return *fallback, true
+}
@@ -0,0 +1,169 @@
+package imagescan
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/pkg/imageregistry"
+)
+
+// fakeRegistry serves manifests and blobs of a single repository like registry:2.
+type fakeRegistry struct {
+	blobs map[string][]byte
+	tags  map[string]string
+}
+
+func (f *fakeRegistry) add(data []byte) string {
+	sum := sha256.Sum256(data)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+	f.blobs[digest] = data
+	return digest
+}
+
+func (f *fakeRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v2/"), "/")
+	if len(parts) < 3 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	reference := parts[len(parts)-1]
+	if digest, ok := f.tags[reference]; ok {
+		reference = digest
+	}
+	data, ok := f.blobs[reference]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Docker-Content-Digest", reference)
+	if r.Method == http.MethodGet {
+		_, _ = w.Write(data)
+	}
+}
+
+func newTestScanner(t *testing.T, registry *fakeRegistry) (*Scanner, string) {
+	t.Helper()
+	server := httptest.NewServer(registry)
+	t.Cleanup(server.Close)
+	host := strings.TrimPrefix(server.URL, "http://")
+	return &Scanner{
+		pullClient: func(string) *imageregistry.OCIClient {
+			return imageregistry.NewOCIClientForServer(host, true, "", "", "")
+		},
+	}, host
+}
+
+func TestScanImageReadsPlatformManifest(t *testing.T) {
+	registry := &fakeRegistry{blobs: map[string][]byte{}, tags: map[string]string{}}
+	manifest := func(arch string, layers ...[]byte) string {
+		config, _ := json.Marshal(map[string]string{"os": "linux", "architecture": arch})
+		m := imageregistry.OCIManifest{Config: imageregistry.OCIDescriptor{Digest: registry.add(config)}}
+		for _, layer := range layers {
+			m.Layers = append(m.Layers, imageregistry.OCIDescriptor{Digest: registry.add(layer)})
+		}
+		data, _ := json.Marshal(m)
+		return registry.add(data)
+	}
+	armDigest := manifest("arm64", buildLayer(t, true, layerFile{name: "etc/os-release", content: "ID=debian\n"}))
+	amdDigest := manifest("amd64", ubuntuBaseLayer(t), []byte{0x28, 0xb5, 0x2f, 0xfd, 0})
+	index, _ := json.Marshal(imageregistry.OCIManifest{Manifests: []imageregistry.OCIDescriptor{
+		{Digest: "sha256:attestation", Platform: &imageregistry.OCIPlatform{OS: "unknown", Architecture: "unknown"}},
+		{Digest: armDigest, Platform: &imageregistry.OCIPlatform{OS: "linux", Architecture: "arm64"}},
+		{Digest: amdDigest, Platform: &imageregistry.OCIPlatform{OS: "linux", Architecture: "amd64"}},
+	}})
+	registry.tags["v1"] = registry.add(index)
+
+	scanner, host := newTestScanner(t, registry)
+	report, err := scanner.scanImage(context.Background(), host+"/user-alice/torch:v1")
+	if err != nil {
+		t.Fatalf("scanImage: %v", err)
+	}
+	if report.digest != registry.tags["v1"] || report.arch != "amd64" || report.release.ID != "ubuntu" {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if len(report.packages) != 4 || len(report.notes) != 1 {
+		t.Fatalf("unexpected packages %v or notes %v", packageList(report.packages), report.notes)
+	}
+
+	if _, err = scanner.scanImage(context.Background(), host+"/user-alice/torch:missing"); err == nil {
+		t.Fatal("scanning a missing tag should fail")
+	}
+}
+
+func TestScannerReloadsVulnerabilityDB(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vulndb.json")
+	if err := os.WriteFile(path, []byte(`{"version": "v1", "vulnerabilities": []}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	scanner := &Scanner{dbPath: path}
+	db, err := scanner.vulnerabilityDB()
+	if err != nil || db.Version != "v1" {
+		t.Fatalf("load = %+v, %v", db, err)
+	}
+	if err = os.WriteFile(path, []byte(`{"version": "v2", "vulnerabilities": []}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err = os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if db, err = scanner.vulnerabilityDB(); err != nil || db.Version != "v2" {
+		t.Fatalf("reload = %+v, %v", db, err)
+	}
+	if db, err = (&Scanner{}).vulnerabilityDB(); err != nil || db != nil {
+		t.Fatalf("no database configured = %+v, %v", db, err)
+	}
+}
+
+func TestParseImageReference(t *testing.T) {
+	for link, want := range map[string]string{
+		"ubuntu":                                "docker.io library/ubuntu latest",
+		"pytorch/pytorch:2.3.0-cuda12.1":        "docker.io pytorch/pytorch 2.3.0-cuda12.1",
+		"registry.lab:5000/user-a/torch:v1":     "registry.lab:5000 user-a/torch v1",
+		"localhost/user-a/torch@sha256:abcdef":  "localhost user-a/torch sha256:abcdef",
+		"harbor.example.com/crater/base:cuda12": "harbor.example.com crater/base cuda12",
+	} {
+		host, repository, reference, err := ParseImageReference(link)
+		if err != nil || host+" "+repository+" "+reference != want {
+			t.Fatalf("ParseImageReference(%q) = %q %q %q %v, want %q", link, host, repository, reference, err, want)
+		}
+	}
+	if _, _, _, err := ParseImageReference(" "); err == nil {
+		t.Fatal("empty link should be rejected")
+	}
+}
+
+func TestBlocksPublishing(t *testing.T) {
+	scanned := func(maxSeverity model.ScanSeverity) *model.ImageScan {
+		return &model.ImageScan{Status: model.ImageScanSucceeded, DatabaseVersion: "v1", MaxSeverity: maxSeverity}
+	}
+	for _, tc := range []struct {
+		name      string
+		threshold model.ScanSeverity
+		scan      *model.ImageScan
+		blocked   bool
+	}{
+		{name: "no policy", scan: nil},
+		{name: "not scanned", threshold: model.ScanSeverityHigh, scan: nil, blocked: true},
+		{name: "pending", threshold: model.ScanSeverityHigh, scan: &model.ImageScan{Status: model.ImageScanPending}, blocked: true},
+		{name: "no database", threshold: model.ScanSeverityHigh, scan: &model.ImageScan{Status: model.ImageScanSucceeded}, blocked: true},
+		{name: "clean", threshold: model.ScanSeverityHigh, scan: scanned("")},
+		{name: "below", threshold: model.ScanSeverityHigh, scan: scanned(model.ScanSeverityMedium)},
+		{name: "at threshold", threshold: model.ScanSeverityHigh, scan: scanned(model.ScanSeverityHigh), blocked: true},
+		{name: "above", threshold: model.ScanSeverityHigh, scan: scanned(model.ScanSeverityCritical), blocked: true},
+	} {
+		if reason, blocked := BlocksPublishing(tc.threshold, tc.scan); blocked != tc.blocked || blocked == (reason == "") {
+			t.Fatalf("%s: BlocksPublishing = %q, %v", tc.name, reason, blocked)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package imagescan
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/raids-lab/crater/dao/model"
+)
+
+// VulnerabilityDB is the offline vulnerability database matched against image packages.
+// It is a JSON file such as:
+//
+//	{
+//	  "version": "2026-10-01",
+//	  "vulnerabilities": [
+//	    {"id": "CVE-2024-6119", "ecosystem": "deb", "distro": "ubuntu:22.04", "package": "openssl",
+//	     "fixed": "3.0.2-0ubuntu1.18", "severity": "HIGH", "title": "..."}
+//	  ]
+//	}
+//
+// Ecosystem is deb, apk or pypi. Deb and apk entries may name a binary or a source package,
+// and Distro limits them to an os-release ID, optionally with VERSION_ID. Versions from
+// Introduced (inclusive) up to Fixed (exclusive) are affected; an empty Fixed means no fix yet.
+type VulnerabilityDB struct {
+	Version         string          `json:"version"`
+	Vulnerabilities []Vulnerability `json:"vulnerabilities"`
+
+	index map[string][]*Vulnerability
+}
+
+type Vulnerability struct {
+	ID         string `json:"id"`
+	Ecosystem  string `json:"ecosystem"`
+	Distro     string `json:"distro,omitempty"`
+	Package    string `json:"package"`
+	Introduced string `json:"introduced,omitempty"`
+	Fixed      string `json:"fixed,omitempty"`
+	Severity   string `json:"severity"`
+	Title      string `json:"title,omitempty"`
+}
+
+func ParseVulnerabilityDB(data []byte) (*VulnerabilityDB, error) {
+	db := &VulnerabilityDB{}
+	if err := json.Unmarshal(data, db); err != nil {
+		return nil, fmt.Errorf("decode vulnerability database: %w", err)
+	}
+	db.index = map[string][]*Vulnerability{}
+	for i := range db.Vulnerabilities {
+		v := &db.Vulnerabilities[i]
+		v.Ecosystem = strings.ToLower(strings.TrimSpace(v.Ecosystem))
+		switch v.Ecosystem {
+		case model.ScanPackageDeb, model.ScanPackageApk, model.ScanPackagePyPI:
+		default:
+			return nil, fmt.Errorf("vulnerability %s: unknown ecosystem %q", v.ID, v.Ecosystem)
+		}
+		if v.ID == "" || v.Package == "" {
+			return nil, fmt.Errorf("vulnerability entry %d: id and package are required", i)
+		}
+		severity, ok := model.ParseScanSeverity(v.Severity)
+		if !ok && strings.TrimSpace(v.Severity) != "" {
+			return nil, fmt.Errorf("vulnerability %s: unknown severity %q", v.ID, v.Severity)
+		}
+		if !ok {
+			severity = model.ScanSeverityUnknown
+		}
+		v.Severity = string(severity)
+		key := packageKey(v.Ecosystem, v.Package)
+		db.index[key] = append(db.index[key], v)
+	}
+	return db, nil
+}
+
+func packageKey(ecosystem, name string) string {
+	if ecosystem == model.ScanPackagePyPI {
+		name = normalizePythonName(name)
+	}
+	return ecosystem + "/" + strings.ToLower(name)
+}
+
+// Match returns the findings for packages of a distribution, most severe first.
+func (db *VulnerabilityDB) Match(release OSRelease, packages []model.ImageScanPackage) []model.ImageScanFinding {
+	var findings []model.ImageScanFinding
+	for _, pkg := range packages {
+		names := []string{pkg.Name}
+		if pkg.Source != "" && pkg.Source != pkg.Name {
+			names = append(names, pkg.Source)
+		}
+		seen := map[string]bool{}
+		for _, name := range names {
+			for _, v := range db.index[packageKey(pkg.Type, name)] {
+				if seen[v.ID] || !v.appliesTo(release) || !v.affects(pkg.Type, pkg.Version) {
+					continue
+				}
+				seen[v.ID] = true
+				findings = append(findings, model.ImageScanFinding{
+					ID:           v.ID,
+					Severity:     model.ScanSeverity(v.Severity),
+					Type:         pkg.Type,
+					Package:      pkg.Name,
+					Version:      pkg.Version,
+					FixedVersion: v.Fixed,
+					Title:        v.Title,
+				})
+			}
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		if ri, rj := findings[i].Severity.Rank(), findings[j].Severity.Rank(); ri != rj {
+			return ri > rj
+		}
+		if findings[i].Package != findings[j].Package {
+			return findings[i].Package < findings[j].Package
+		}
+		return findings[i].ID < findings[j].ID
+	})
+	return findings
+}
+
+func (v *Vulnerability) appliesTo(release OSRelease) bool {
+	if v.Distro == "" || v.Ecosystem == model.ScanPackagePyPI {
+		return true
+	}
+	id, version, _ := strings.Cut(strings.ToLower(v.Distro), ":")
+	return id == release.ID && (version == "" || version == release.VersionID)
+}
+
+func (v *Vulnerability) affects(ecosystem, version string) bool {
+	if v.Introduced != "" && compareVersions(ecosystem, version, v.Introduced) < 0 {
+		return false
+	}
+	return v.Fixed == "" || compareVersions(ecosystem, version, v.Fixed) < 0
+}
+
+var (
+	pythonPreRelease = regexp.MustCompile(`([0-9])[-_.]?(a|alpha|b|beta|c|rc|pre|preview|dev)([0-9]*)`)
+	apkPreRelease    = regexp.MustCompile(`_(alpha|beta|pre|rc)`)
+)
+
+// compareVersions orders versions with the dpkg algorithm. Pre-releases of Python and Alpine
+// versions are rewritten with "~" first so that they sort before the release.
+func compareVersions(ecosystem, a, b string) int {
+	switch ecosystem {
+	case model.ScanPackagePyPI:
+		normalize := func(version string) string {
+			version = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(version)), "v")
+			return pythonPreRelease.ReplaceAllString(version, "$1~$2$3")
+		}
+		return compareVersionPart(normalize(a), normalize(b))
+	case model.ScanPackageApk:
+		a, b = apkPreRelease.ReplaceAllString(a, "~$1"), apkPreRelease.ReplaceAllString(b, "~$1")
+	}
+	return compareDebianVersions(a, b)
+}
+
+// compareDebianVersions compares [epoch:]upstream[-revision] versions.
+func compareDebianVersions(a, b string) int {
+	epochA, upstreamA, revisionA := splitDebianVersion(a)
+	epochB, upstreamB, revisionB := splitDebianVersion(b)
+	if epochA != epochB {
+		return compareVersionPart(epochA, epochB)
+	}
+	if c := compareVersionPart(upstreamA, upstreamB); c != 0 {
+		return c
+	}
+	return compareVersionPart(revisionA, revisionB)
+}
+
+func splitDebianVersion(version string) (epoch, upstream, revision string) {
+	upstream = strings.TrimSpace(version)
+	epoch = "0"
+	if before, after, found := strings.Cut(upstream, ":"); found && isDigits(before) {
+		epoch, upstream = strings.TrimLeft(before, "0"), after
+		if epoch == "" {
+			epoch = "0"
+		}
+	}
+	if i := strings.LastIndex(upstream, "-"); i >= 0 {
+		upstream, revision = upstream[:i], upstream[i+1:]
+	}
+	return epoch, upstream, revision
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// compareVersionPart is dpkg's verrevcmp: non-digit runs compare with letters before other
+// characters and "~" before everything, digit runs compare numerically.
+func compareVersionPart(a, b string) int {
+	order := func(s string, i int) int {
+		if i >= len(s) {
+			return 0
+		}
+		c := s[i]
+		switch {
+		case c >= '0' && c <= '9':
+			return 0
+		case (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+			return int(c)
+		case c == '~':
+			return -1
+		default:
+			return int(c) + 256
+		}
+	}
+	isDigit := func(s string, i int) bool { return i < len(s) && s[i] >= '0' && s[i] <= '9' }
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		for (i < len(a) && !isDigit(a, i)) || (j < len(b) && !isDigit(b, j)) {
+			if oa, ob := order(a, i), order(b, j); oa != ob {
+				return sign(oa - ob)
+			}
+			i++
+			j++
+		}
+		for i < len(a) && a[i] == '0' {
+			i++
+		}
+		for j < len(b) && b[j] == '0' {
+			j++
+		}
+		firstDiff := 0
+		for isDigit(a, i) && isDigit(b, j) {
+			if firstDiff == 0 {
+				firstDiff = int(a[i]) - int(b[j])
+			}
+			i++
+			j++
+		}
+		if isDigit(a, i) {
+			return 1
+		}
+		if isDigit(b, j) {
+			return -1
+		}
+		if firstDiff != 0 {
+			return sign(firstDiff)
+		}
+	}
+	return 0
+}
+
+func sign(v int) int {
+	switch {
+	case v < 0:
+		return -1
+	case v > 0:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,84 @@
+package imagescan
+
+import (
+	"testing"
+
+	"github.com/raids-lab/crater/dao/model"
+)
+
+func TestCompareVersions(t *testing.T) {
+	for _, tc := range []struct {
+		ecosystem, a, b string
+		want            int
+	}{
+		{model.ScanPackageDeb, "3.0.2-0ubuntu1.10", "3.0.2-0ubuntu1.8", 1},
+		{model.ScanPackageDeb, "1:1.0", "2.0", 1},
+		{model.ScanPackageDeb, "1.0~rc1-1", "1.0-1", -1},
+		{model.ScanPackageDeb, "2.36-9+deb12u4", "2.36-9+deb12u4", 0},
+		{model.ScanPackageDeb, "1.10", "1.9", 1},
+		{model.ScanPackageApk, "3.1.4-r5", "3.1.4-r10", -1},
+		{model.ScanPackageApk, "1.2.4_rc1-r0", "1.2.4-r0", -1},
+		{model.ScanPackagePyPI, "2.0.0rc1", "2.0.0", -1},
+		{model.ScanPackagePyPI, "1.26.4", "1.26.10", -1},
+		{model.ScanPackagePyPI, "2.0.0.post1", "2.0.0", 1},
+		{model.ScanPackagePyPI, "v2.31.0", "2.31.0", 0},
+	} {
+		if got := compareVersions(tc.ecosystem, tc.a, tc.b); got != tc.want {
+			t.Fatalf("compareVersions(%s, %q, %q) = %d, want %d", tc.ecosystem, tc.a, tc.b, got, tc.want)
+		}
+		if got := compareVersions(tc.ecosystem, tc.b, tc.a); got != -tc.want {
+			t.Fatalf("compareVersions(%s, %q, %q) = %d, want %d", tc.ecosystem, tc.b, tc.a, got, -tc.want)
+		}
+	}
+}
+
+const testVulnerabilityDB = `{
+  "version": "2026-10-01",
+  "vulnerabilities": [
+    {"id": "CVE-1", "ecosystem": "deb", "distro": "ubuntu:22.04", "package": "openssl", "fixed": "3.0.2-0ubuntu1.12", "severity": "high"},
+    {"id": "CVE-2", "ecosystem": "deb", "distro": "debian", "package": "openssl", "fixed": "3.0.11-1", "severity": "CRITICAL"},
+    {"id": "CVE-3", "ecosystem": "pypi", "package": "Torch_CUDA", "introduced": "2.0", "fixed": "2.3.1", "severity": "MEDIUM"},
+    {"id": "CVE-4", "ecosystem": "pypi", "package": "numpy", "fixed": "1.22.0", "severity": "LOW"},
+    {"id": "CVE-5", "ecosystem": "pypi", "package": "pillow"}
+  ]
+}`
+
+func TestVulnerabilityDBMatch(t *testing.T) {
+	db, err := ParseVulnerabilityDB([]byte(testVulnerabilityDB))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	findings := db.Match(OSRelease{ID: "ubuntu", VersionID: "22.04"}, []model.ImageScanPackage{
+		{Type: model.ScanPackageDeb, Name: "openssl", Version: "3.0.2-0ubuntu1.10"},
+		{Type: model.ScanPackageDeb, Name: "libssl3", Version: "3.0.2-0ubuntu1.10", Source: "openssl"},
+		{Type: model.ScanPackagePyPI, Name: "torch-cuda", Version: "2.3.0"},
+		{Type: model.ScanPackagePyPI, Name: "numpy", Version: "1.26.4"},
+		{Type: model.ScanPackagePyPI, Name: "pillow", Version: "10.0.0"},
+	})
+	var got []string
+	for _, finding := range findings {
+		got = append(got, finding.ID+":"+finding.Package+":"+string(finding.Severity))
+	}
+	want := []string{"CVE-1:libssl3:HIGH", "CVE-1:openssl:HIGH", "CVE-3:torch-cuda:MEDIUM", "CVE-5:pillow:UNKNOWN"}
+	if len(got) != len(want) {
+		t.Fatalf("findings = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("findings = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestParseVulnerabilityDBRejectsBadEntries(t *testing.T) {
+	for _, data := range []string{
+		`{"vulnerabilities": [{"id": "X", "ecosystem": "npm", "package": "a"}]}`,
+		`{"vulnerabilities": [{"id": "X", "ecosystem": "deb", "package": "a", "severity": "severe"}]}`,
+		`{"vulnerabilities": [{"ecosystem": "deb", "package": "a"}]}`,
+		`not json`,
+	} {
+		if _, err := ParseVulnerabilityDB([]byte(data)); err == nil {
+			t.Fatalf("expected an error for %s", data)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	batchv1 "k8s.io/api/batch/v1"
@@ -17,6 +18,22 @@ import (
 )
 
 func (b *imagePacker) CreateFromDockerfile(c context.Context, data *BuildKitReq) error {
+	platforms, err := NormalizePlatforms(data.Archs)
+	if err != nil {
+		return err
+	}
+	data.Archs = platforms
+	if data.Git != nil {
+		if err = data.Git.Normalize(); err != nil {
+			return err
+		}
+		if data.Git.CredentialSecret != "" {
+			if err = b.checkGitCredentialSecret(c, data); err != nil {
+				return err
+			}
+		}
+	}
+
 	// Generate volumes and volumeMounts from request
 	volumes, volumeMounts, buildContext, err := b.generateVolumesAndMounts(c, data)
 	if err != nil {
@@ -24,6 +41,11 @@ func (b *imagePacker) CreateFromDockerfile(c context.Context, data *BuildKitReq)
 	}
 
 	buildkitContainer := b.generateBuildKitContainer(data, volumeMounts, buildContext)
+	if data.Git != nil {
+		// The Dockerfile comes from the cloned repository, so there is no ConfigMap to own
+		_, err = b.createJob(c, data, buildkitContainer, volumes)
+		return err
+	}
 	var configMap *corev1.ConfigMap
 	if configMap, err = b.createDockerfileConfigMap(c, data); err != nil {
 		return err
@@ -70,16 +92,6 @@ func (b *imagePacker) generateVolumesAndMounts(c context.Context, data *BuildKit
 				},
 			},
 		},
-		{
-			Name: "configmap-volume",
-			VolumeSource: corev1.VolumeSource{
-				ConfigMap: &corev1.ConfigMapVolumeSource{
-					LocalObjectReference: corev1.LocalObjectReference{
-						Name: data.JobName,
-					},
-				},
-			},
-		},
 	}
 
 	// Initialize default volumeMounts for buildkit container
@@ -90,11 +102,30 @@ func (b *imagePacker) generateVolumesAndMounts(c context.Context, data *BuildKit
 			SubPath:   "config.json",
 			ReadOnly:  true,
 		},
-		{
+	}
+	if data.Git != nil {
+		// The git-clone init container fills the workspace with the repository
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      "workspace",
+			MountPath: "/workspace",
+			ReadOnly:  true,
+		})
+	} else {
+		volumes = append(volumes, corev1.Volume{
+			Name: "configmap-volume",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: data.JobName,
+					},
+				},
+			},
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
 			Name:      "configmap-volume",
 			MountPath: "/workspace",
 			ReadOnly:  true,
-		},
+		})
 	}
 	buildContext := make(map[string]string)
 	// Process user-defined volume mounts
@@ -128,36 +159,7 @@ func (b *imagePacker) generateBuildKitContainer(
 	volumeMounts []corev1.VolumeMount,
 	buildContext map[string]string,
 ) []corev1.Container {
-	output := fmt.Sprintf("type=image,name=%s,push=true", data.ImageLink)
-	archs := strings.Join(data.Archs, ",")
-
-	// 判断archs字符串是否包含arm
-	appendArmCmd := ""
-	if strings.Contains(archs, "arm") {
-		buildkitdArmNameSpace := fmt.Sprintf("%s.%s", buildkitdArmName, config.GetConfig().Namespaces.Image)
-		appendArmCmd = fmt.Sprintf(`docker buildx create \
-		--name multi-platform-builder \
-		--append \
-		--node arm-node \
-		--driver remote tcp://%s:1234 && \
-		`, buildkitdArmNameSpace)
-	}
-
-	buildkitdAmdNameSpace := fmt.Sprintf("%s.%s", buildkitdAmdName, config.GetConfig().Namespaces.Image)
-	// 构建完整的命令
-	cmd := fmt.Sprintf(`
-		docker buildx create \
-		--name multi-platform-builder \
-		--node amd-node \
-		--driver remote tcp://%s:1234 && \
-		%s	docker buildx use multi-platform-builder && \
-		docker buildx build --progress plain --platform %s \
-		--file /workspace/Dockerfile --output %s \
-	`, buildkitdAmdNameSpace, appendArmCmd, archs, output)
-	for key, value := range buildContext {
-		cmd += fmt.Sprintf(" --build-context %s=%s", key, value)
-	}
-	cmd += " /workspace"
+	cmd := buildxCommand(data, buildContext)
 
 	setupCommands := []string{
 		"/bin/sh",
@@ -182,6 +184,45 @@ func (b *imagePacker) generateBuildKitContainer(
 	return buildkitContainer
 }
 
+// buildxCommand creates a builder with one remote node per requested platform and builds them
+// in a single run, so several platforms are pushed as one manifest list.
+func buildxCommand(data *BuildKitReq, buildContext map[string]string) string {
+	contextDir, dockerfile := "/workspace", "/workspace/Dockerfile"
+	if data.Git != nil {
+		contextDir, dockerfile = data.Git.contextDir(), data.Git.dockerfilePath()
+	}
+	var cmd strings.Builder
+	for i, node := range buildkitdNodes(data.Archs) {
+		appendFlag := ""
+		if i > 0 {
+			appendFlag = " --append"
+		}
+		fmt.Fprintf(&cmd, "docker buildx create --name multi-platform-builder%s --node %s --platform %s "+
+			"--driver remote tcp://%s:1234 && ", appendFlag, node.name, node.platform, node.service)
+	}
+	cmd.WriteString("docker buildx use multi-platform-builder && ")
+	fmt.Fprintf(&cmd, "docker buildx build --progress plain --platform %s --file %s "+
+		"--output type=image,name=%s,push=true", strings.Join(data.Archs, ","), dockerfile, data.ImageLink)
+	if data.CacheRef != "" {
+		// image-manifest stores the cache as an OCI image, which Harbor and other registries accept.
+		fmt.Fprintf(&cmd, " --cache-from type=registry,ref=%s", data.CacheRef)
+		fmt.Fprintf(&cmd, " --cache-to type=registry,ref=%s,mode=max,image-manifest=true,oci-mediatypes=true", data.CacheRef)
+	}
+	if data.NoCache {
+		cmd.WriteString(" --no-cache")
+	}
+	keys := make([]string, 0, len(buildContext))
+	for key := range buildContext {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&cmd, " --build-context %s=%s", key, buildContext[key])
+	}
+	cmd.WriteString(" " + contextDir)
+	return cmd.String()
+}
+
 func (b *imagePacker) DeleteJob(c context.Context, jobName, ns string) error {
 	job := &batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
@@ -257,6 +298,10 @@ func (b *imagePacker) createJob(
 			AnnotationKeyArchs:       string(archString),
 		},
 	}
+	var initContainers []corev1.Container
+	if data.Git != nil {
+		initContainers = append(initContainers, gitCloneContainer(data.Git))
+	}
 	jobSpec := batchv1.JobSpec{
 		Template: corev1.PodTemplateSpec{
 			ObjectMeta: metav1.ObjectMeta{
@@ -267,9 +312,10 @@ func (b *imagePacker) createJob(
 				},
 			},
 			Spec: corev1.PodSpec{
-				RestartPolicy: corev1.RestartPolicyNever,
-				Containers:    buildkitContainer,
-				Volumes:       volumes,
+				RestartPolicy:  corev1.RestartPolicyNever,
+				InitContainers: initContainers,
+				Containers:     buildkitContainer,
+				Volumes:        volumes,
 				SecurityContext: &corev1.PodSecurityContext{
 					SeccompProfile: &corev1.SeccompProfile{
 						Type: corev1.SeccompProfileTypeUnconfined,
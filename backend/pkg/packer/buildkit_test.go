@@ -0,0 +1,96 @@
+package packer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/raids-lab/crater/pkg/config"
+)
+
+func TestNormalizePlatforms(t *testing.T) {
+	for _, tt := range []struct {
+		archs []string
+		want  []string
+	}{
+		{nil, []string{PlatformAmd64}},
+		{[]string{"linux/arm64"}, []string{PlatformArm64}},
+		{[]string{"aarch64", " AMD64 ", "linux/amd64"}, []string{PlatformAmd64, PlatformArm64}},
+		{[]string{"x86_64", "linux/arm64/v8"}, []string{PlatformAmd64, PlatformArm64}},
+	} {
+		got, err := NormalizePlatforms(tt.archs)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("NormalizePlatforms(%q) = %v, %v; want %v", tt.archs, got, err, tt.want)
+		}
+	}
+	if _, err := NormalizePlatforms([]string{"linux/amd64", "linux/riscv64"}); err == nil {
+		t.Fatal("NormalizePlatforms accepted linux/riscv64")
+	}
+}
+
+func TestBuildxCommandFansOutToPlatformDaemons(t *testing.T) {
+	cmd := buildxCommand(&BuildKitReq{
+		ImageLink: "harbor.example.com/user-alice/train:v1",
+		Archs:     []string{PlatformAmd64, PlatformArm64},
+		CacheRef:  "harbor.example.com/user-alice/buildcache:train",
+	}, map[string]string{"project1": "/data/b", "project0": "/data/a"})
+
+	amd := strings.Index(cmd, "--node amd-node --platform linux/amd64 --driver remote tcp://buildkitd-x86.")
+	arm := strings.Index(cmd, "--append --node arm-node --platform linux/arm64 --driver remote tcp://buildkitd-arm.")
+	if amd < 0 || arm < amd {
+		t.Fatalf("builder must start on the x86 daemon and append the arm daemon: %s", cmd)
+	}
+	for _, want := range []string{
+		"--platform linux/amd64,linux/arm64 --file /workspace/Dockerfile",
+		"--output type=image,name=harbor.example.com/user-alice/train:v1,push=true",
+		"--cache-from type=registry,ref=harbor.example.com/user-alice/buildcache:train",
+		"--cache-to type=registry,ref=harbor.example.com/user-alice/buildcache:train,mode=max",
+		"--build-context project0=/data/a --build-context project1=/data/b /workspace",
+	} {
+		if !strings.Contains(cmd, want) {
+			t.Errorf("command must contain %q: %s", want, cmd)
+		}
+	}
+	if strings.Contains(cmd, "--no-cache") {
+		t.Errorf("command must not skip the cache: %s", cmd)
+	}
+}
+
+func TestBuildxCommandSinglePlatformWithoutCache(t *testing.T) {
+	cmd := buildxCommand(&BuildKitReq{
+		ImageLink: "harbor.example.com/user-alice/train:v2",
+		Archs:     []string{PlatformArm64},
+		NoCache:   true,
+	}, nil)
+	if strings.Contains(cmd, "amd-node") || strings.Contains(cmd, "--append") {
+		t.Fatalf("arm64 builds must only use the arm daemon: %s", cmd)
+	}
+	if !strings.Contains(cmd, "--name multi-platform-builder --node arm-node --platform linux/arm64") {
+		t.Fatalf("builder must be created on the arm daemon: %s", cmd)
+	}
+	if strings.Contains(cmd, "--cache-from") || !strings.HasSuffix(cmd, "--no-cache /workspace") {
+		t.Fatalf("unexpected cache flags: %s", cmd)
+	}
+}
+
+func TestBuildCacheRef(t *testing.T) {
+	cacheConfig := &config.GetConfig().Registry.BuildTools.Cache
+	previous := *cacheConfig
+	t.Cleanup(func() { *cacheConfig = previous })
+
+	cacheConfig.Enable = false
+	if ref := BuildCacheRef("alice", "train"); ref != "" {
+		t.Fatalf("disabled cache returned %q", ref)
+	}
+
+	cacheConfig.Enable = true
+	cacheConfig.Repository = ""
+	server := config.GetConfig().RegistryServer()
+	if got, want := BuildCacheRef("alice", "team/train"), server+"/user-alice/buildcache:team-train"; got != want {
+		t.Fatalf("BuildCacheRef = %q, want %q", got, want)
+	}
+	cacheConfig.Repository = "cache"
+	if got, want := BuildCacheRef("alice", "train"), server+"/user-alice/cache:train"; got != want {
+		t.Fatalf("BuildCacheRef = %q, want %q", got, want)
+	}
+}
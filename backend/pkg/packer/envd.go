@@ -93,27 +93,7 @@ func (b *imagePacker) generateEnvdContainer(data *EnvdReq) []corev1.Container {
 			Value: "/.docker",
 		},
 	}
-	httpsProxy := config.GetConfig().Registry.BuildTools.ProxyConfig.HTTPSProxy
-	if httpsProxy != "" {
-		envVars = append(envVars, corev1.EnvVar{
-			Name:  "HTTPS_PROXY",
-			Value: httpsProxy,
-		})
-	}
-	httpProxy := config.GetConfig().Registry.BuildTools.ProxyConfig.HTTPProxy
-	if httpProxy != "" {
-		envVars = append(envVars, corev1.EnvVar{
-			Name:  "HTTP_PROXY",
-			Value: httpProxy,
-		})
-	}
-	noProxy := config.GetConfig().Registry.BuildTools.ProxyConfig.NoProxy
-	if noProxy != "" {
-		envVars = append(envVars, corev1.EnvVar{
-			Name:  "NO_PROXY",
-			Value: fmt.Sprintf("$(NO_PROXY),%s", noProxy),
-		})
-	}
+	envVars = append(envVars, proxyEnvVars()...)
 	envdContainer := []corev1.Container{
 		{
 			Name:  "buildkit",
@@ -224,3 +204,31 @@ func (b *imagePacker) createEnvdJob(
 	fmt.Printf("job: %+v\n", job)
 	return job, err
 }
+
+// proxyEnvVars returns the proxy settings of build environments.
+func proxyEnvVars() []corev1.EnvVar {
+	proxyConfig := config.GetConfig().Registry.BuildTools.ProxyConfig
+	envVars := []corev1.EnvVar{}
+	httpsProxy := proxyConfig.HTTPSProxy
+	if httpsProxy != "" {
+		envVars = append(envVars, corev1.EnvVar{
+			Name:  "HTTPS_PROXY",
+			Value: httpsProxy,
+		})
+	}
+	httpProxy := proxyConfig.HTTPProxy
+	if httpProxy != "" {
+		envVars = append(envVars, corev1.EnvVar{
+			Name:  "HTTP_PROXY",
+			Value: httpProxy,
+		})
+	}
+	noProxy := proxyConfig.NoProxy
+	if noProxy != "" {
+		envVars = append(envVars, corev1.EnvVar{
+			Name:  "NO_PROXY",
+			Value: fmt.Sprintf("$(NO_PROXY),%s", noProxy),
+		})
+	}
+	return envVars
+}
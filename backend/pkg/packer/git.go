@@ -0,0 +1,235 @@
+package packer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/raids-lab/crater/pkg/config"
+	"github.com/raids-lab/crater/pkg/crclient"
+)
+
+const (
+	defaultGitImage       = "docker.io/alpine/git:v2.47.2"
+	defaultGitDockerfile  = "Dockerfile"
+	gitCloneContainerName = "git-clone"
+	gitCloneDir           = "/workspace/repo"
+
+	// GitTemplateCommitKey is the key of the cloned commit in the template of a Git source build.
+	GitTemplateCommitKey = "commit"
+
+	// LabelKeyGitCredential marks the basic-auth Secrets users save for Git source builds.
+	LabelKeyGitCredential = "crater.raids.io/git-credential"
+)
+
+// ErrInvalidGitCredential is returned when the credential secret of a Git source build cannot be used.
+var ErrInvalidGitCredential = errors.New("invalid git credential secret")
+
+var (
+	gitRefPattern  = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._/-]*$`)
+	gitPathPattern = regexp.MustCompile(`^[A-Za-z0-9._/-]+$`)
+	gitSHAPattern  = regexp.MustCompile(`^[0-9a-f]{40}([0-9a-f]{24})?$`)
+)
+
+// GitSource is a Git repository cloned into the build context instead of a Dockerfile in a ConfigMap.
+type GitSource struct {
+	Repo string // http(s) URL of the repository
+	Ref  string // Branch, tag or commit; empty means the default branch
+	// Subdir is the build context inside the repository; empty means the repository root.
+	Subdir string
+	// Dockerfile is the Dockerfile path relative to Subdir, "Dockerfile" if empty.
+	Dockerfile string
+	// CredentialSecret is a basic-auth Secret in the build namespace labelled with its owner,
+	// as saved through POST /v1/images/git/credential.
+	CredentialSecret string
+}
+
+// Normalize validates the source and cleans its paths so they can be recorded and passed to the shell.
+func (g *GitSource) Normalize() error {
+	g.Repo = strings.TrimSpace(g.Repo)
+	repo, err := url.Parse(g.Repo)
+	if err != nil || (repo.Scheme != "https" && repo.Scheme != "http") || repo.Host == "" {
+		return fmt.Errorf("repository %q must be an http or https URL", g.Repo)
+	}
+	if repo.User != nil {
+		return fmt.Errorf("repository URL must not contain credentials, use a credential secret instead")
+	}
+
+	g.Ref = strings.TrimSpace(g.Ref)
+	if g.Ref != "" && (!gitRefPattern.MatchString(g.Ref) || strings.Contains(g.Ref, "..")) {
+		return fmt.Errorf("invalid git ref %q", g.Ref)
+	}
+	if g.Subdir, err = cleanGitPath("subdirectory", g.Subdir); err != nil {
+		return err
+	}
+	if g.Dockerfile, err = cleanGitPath("dockerfile", g.Dockerfile); err != nil {
+		return err
+	}
+	if g.Dockerfile == "" {
+		g.Dockerfile = defaultGitDockerfile
+	}
+	g.CredentialSecret = strings.TrimSpace(g.CredentialSecret)
+	return nil
+}
+
+// cleanGitPath returns p relative to the repository, or an error if it leaves the repository.
+func cleanGitPath(field, p string) (string, error) {
+	p = strings.TrimSpace(p)
+	if p == "" {
+		return "", nil
+	}
+	if !gitPathPattern.MatchString(p) || strings.HasPrefix(p, "/") {
+		return "", fmt.Errorf("invalid %s %q", field, p)
+	}
+	p = path.Clean(p)
+	if p == ".." || strings.HasPrefix(p, "../") {
+		return "", fmt.Errorf("%s %q is outside the repository", field, p)
+	}
+	if p == "." {
+		return "", nil
+	}
+	return p, nil
+}
+
+func (g *GitSource) contextDir() string {
+	return path.Join(gitCloneDir, g.Subdir)
+}
+
+func (g *GitSource) dockerfilePath() string {
+	return path.Join(g.contextDir(), g.Dockerfile)
+}
+
+// checkGitCredentialSecret makes sure the credential secret belongs to the user, so a build cannot
+// send other users' or the platform's credentials to a repository of its choice.
+func (b *imagePacker) checkGitCredentialSecret(c context.Context, data *BuildKitReq) error {
+	name := data.Git.CredentialSecret
+	secret := &corev1.Secret{}
+	if err := b.client.Get(c, client.ObjectKey{Namespace: data.Namespace, Name: name}, secret); err != nil {
+		return fmt.Errorf("%w: get secret %s: %w", ErrInvalidGitCredential, name, err)
+	}
+	if secret.Labels[crclient.LabelKeyTaskUser] != data.UserName {
+		return fmt.Errorf("%w: secret %s does not belong to user %s", ErrInvalidGitCredential, name, data.UserName)
+	}
+	if len(secret.Data[corev1.BasicAuthUsernameKey]) == 0 || len(secret.Data[corev1.BasicAuthPasswordKey]) == 0 {
+		return fmt.Errorf("%w: secret %s must contain %s and %s", ErrInvalidGitCredential, name,
+			corev1.BasicAuthUsernameKey, corev1.BasicAuthPasswordKey)
+	}
+	return nil
+}
+
+// gitCloneContainer fetches the ref into the workspace and writes the commit to its termination
+// message, from which the reconciler records it in the build template.
+func gitCloneContainer(source *GitSource) corev1.Container {
+	ref := source.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+	script := []string{
+		"set -e",
+		"git init -q " + gitCloneDir,
+		"cd " + gitCloneDir,
+		`git remote add origin "$GIT_REPO"`,
+	}
+	env := []corev1.EnvVar{
+		{Name: "GIT_REPO", Value: source.Repo},
+		{Name: "GIT_REF", Value: ref},
+		{Name: "GIT_TERMINAL_PROMPT", Value: "0"},
+		{Name: "HOME", Value: "/tmp"},
+	}
+	if source.CredentialSecret != "" {
+		// The helper only references the variables, so the password never reaches .git/config.
+		script = append(script,
+			`git config credential.helper '!f() { echo "username=${GIT_USERNAME}"; echo "password=${GIT_PASSWORD}"; }; f'`)
+		env = append(env,
+			secretEnvVar("GIT_USERNAME", source.CredentialSecret, corev1.BasicAuthUsernameKey),
+			secretEnvVar("GIT_PASSWORD", source.CredentialSecret, corev1.BasicAuthPasswordKey),
+		)
+	}
+	script = append(script,
+		`git fetch -q --depth 1 origin "$GIT_REF"`,
+		"git checkout -q FETCH_HEAD",
+		"git rev-parse HEAD > /dev/termination-log",
+	)
+
+	image := config.GetConfig().Registry.BuildTools.Images.Git
+	if image == "" {
+		image = defaultGitImage
+	}
+	return corev1.Container{
+		Name:    gitCloneContainerName,
+		Image:   image,
+		Command: []string{"/bin/sh", "-c", strings.Join(script, "\n")},
+		Env:     append(env, proxyEnvVars()...),
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      "workspace",
+				MountPath: "/workspace",
+			},
+		},
+	}
+}
+
+func secretEnvVar(name, secret, key string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secret},
+				Key:                  key,
+			},
+		},
+	}
+}
+
+// GitCommitFromPod returns the commit cloned by the pod of a Git source build, or an empty string
+// if the clone has not finished.
+func GitCommitFromPod(pod *corev1.Pod) string {
+	for i := range pod.Status.InitContainerStatuses {
+		status := &pod.Status.InitContainerStatuses[i]
+		if status.Name != gitCloneContainerName || status.State.Terminated == nil ||
+			status.State.Terminated.ExitCode != 0 {
+			continue
+		}
+		if commit := strings.TrimSpace(status.State.Terminated.Message); gitSHAPattern.MatchString(commit) {
+			return commit
+		}
+	}
+	return ""
+}
+
+// GitCommitFromTemplate returns the commit recorded in the template of a Git source build.
+func GitCommitFromTemplate(template string) string {
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(template), &fields); err != nil {
+		return ""
+	}
+	commit, _ := fields[GitTemplateCommitKey].(string)
+	return commit
+}
+
+// RecordGitCommit returns the template with the cloned commit added, keeping the other fields.
+func RecordGitCommit(template, commit string) (string, error) {
+	fields := map[string]any{}
+	if strings.TrimSpace(template) != "" {
+		if err := json.Unmarshal([]byte(template), &fields); err != nil {
+			return "", fmt.Errorf("decode build template: %w", err)
+		}
+	}
+	if fields == nil {
+		fields = map[string]any{}
+	}
+	fields[GitTemplateCommitKey] = commit
+	recorded, err := json.Marshal(fields)
+	if err != nil {
+		return "", err
+	}
+	return string(recorded), nil
+}
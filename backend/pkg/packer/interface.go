@@ -19,6 +19,7 @@ type ImageRegistrySecret struct {
 
 type BuildKitReq struct {
 	UserID       uint
+	UserName     string
 	Namespace    string
 	JobName      string
 	Dockerfile   *string
@@ -29,9 +30,12 @@ type BuildKitReq struct {
 	Tags         []string
 	Template     string
 	BuildSource  model.BuildSource
-	Archs        []string
+	Archs        []string // Build platforms from NormalizePlatforms, pushed as one manifest list
 	VolumeMounts []util.VolumeMount
 	Token        util.JWTMessage // Token information for volume resolution
+	CacheRef     string          // Registry build cache from BuildCacheRef; empty disables the cache
+	NoCache      bool            // Ignore cached layers, but still refresh the cache
+	Git          *GitSource      // Build context cloned from a repository; nil uses the Dockerfile ConfigMap
 }
 
 type SnapshotReq struct {
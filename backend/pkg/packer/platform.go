@@ -0,0 +1,108 @@
+package packer
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/raids-lab/crater/pkg/config"
+)
+
+const (
+	PlatformAmd64 = "linux/amd64"
+	PlatformArm64 = "linux/arm64"
+
+	defaultBuildCacheRepository = "buildcache"
+	maxImageTagLength           = 128
+)
+
+// SupportedPlatforms are the platforms of the buildkitd daemons, in build order.
+var SupportedPlatforms = []string{PlatformAmd64, PlatformArm64}
+
+// platformAliases maps the architecture names users write to the build platforms.
+var platformAliases = map[string]string{
+	"amd64":          PlatformAmd64,
+	"x86_64":         PlatformAmd64,
+	"linux/amd64":    PlatformAmd64,
+	"arm64":          PlatformArm64,
+	"aarch64":        PlatformArm64,
+	"linux/arm64":    PlatformArm64,
+	"linux/arm64/v8": PlatformArm64,
+}
+
+var invalidTagChars = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// NormalizePlatforms maps the requested architectures to build platforms, dropping duplicates
+// and ordering them like SupportedPlatforms. No architectures means linux/amd64.
+func NormalizePlatforms(archs []string) ([]string, error) {
+	requested := map[string]bool{}
+	for _, arch := range archs {
+		platform, ok := platformAliases[strings.ToLower(strings.TrimSpace(arch))]
+		if !ok {
+			return nil, fmt.Errorf("unsupported architecture %q, expected one of %s",
+				arch, strings.Join(SupportedPlatforms, ", "))
+		}
+		requested[platform] = true
+	}
+	if len(requested) == 0 {
+		return []string{PlatformAmd64}, nil
+	}
+	platforms := make([]string, 0, len(requested))
+	for _, platform := range SupportedPlatforms {
+		if requested[platform] {
+			platforms = append(platforms, platform)
+		}
+	}
+	return platforms, nil
+}
+
+// BuildCacheRef returns the registry cache of a user's image, or an empty string when the build
+// cache is disabled. All tags and platforms of an image share one cache tag.
+func BuildCacheRef(username, imageName string) string {
+	cacheConfig := config.GetConfig().Registry.BuildTools.Cache
+	if !cacheConfig.Enable {
+		return ""
+	}
+	repository := cacheConfig.Repository
+	if repository == "" {
+		repository = defaultBuildCacheRepository
+	}
+	tag := strings.Trim(invalidTagChars.ReplaceAllString(imageName, "-"), "-.")
+	if tag == "" {
+		tag = "latest"
+	}
+	if len(tag) > maxImageTagLength {
+		tag = tag[:maxImageTagLength]
+	}
+	return fmt.Sprintf("%s/user-%s/%s:%s", config.GetConfig().RegistryServer(), username, repository, tag)
+}
+
+// buildkitdNode is the buildkitd daemon building one platform.
+type buildkitdNode struct {
+	name     string
+	service  string
+	platform string
+}
+
+// buildkitdNodes returns the daemons of the platforms, so each platform is built natively
+// instead of emulated on the other daemon.
+func buildkitdNodes(platforms []string) []buildkitdNode {
+	namespace := config.GetConfig().Namespaces.Image
+	nodes := []buildkitdNode{}
+	if slices.Contains(platforms, PlatformAmd64) {
+		nodes = append(nodes, buildkitdNode{
+			name:     "amd-node",
+			service:  fmt.Sprintf("%s.%s", buildkitdAmdName, namespace),
+			platform: PlatformAmd64,
+		})
+	}
+	if slices.Contains(platforms, PlatformArm64) {
+		nodes = append(nodes, buildkitdNode{
+			name:     "arm-node",
+			service:  fmt.Sprintf("%s.%s", buildkitdArmName, namespace),
+			platform: PlatformArm64,
+		})
+	}
+	return nodes
+}
@@ -12,3 +12,19 @@ func RunTriggerBillingBaseLoop(ctx context.Context, clients *Clients) (any, erro
 	}
 	return clients.BillingService.RunBaseLoopOnce(ctx)
 }
+
+// RunBillingLedgerReconcile is the patrol entry for billing ledger reconciliation.
+func RunBillingLedgerReconcile(ctx context.Context, clients *Clients) (any, error) {
+	if clients.BillingService == nil {
+		return nil, fmt.Errorf("billing service is not initialized in patrol clients")
+	}
+	return clients.BillingService.ReconcileLedgerOnce(ctx)
+}
+
+// RunNotificationDispatch is the patrol entry for notification rules.
+func RunNotificationDispatch(ctx context.Context, clients *Clients) (any, error) {
+	if clients.NotificationService == nil {
+		return nil, fmt.Errorf("notification service is not initialized in patrol clients")
+	}
+	return clients.NotificationService.RunDispatchOnce(ctx)
+}
@@ -18,6 +18,10 @@ const (
 	TRIGGER_GPU_ANALYSIS_JOB = "trigger-gpu-analysis-job"
 	// Billing 基础循环
 	TRIGGER_BILLING_BASE_LOOP_JOB = "biling-base-loop"
+	// 通知规则：排队过久、余额不足检测与摘要发送
+	TRIGGER_NOTIFICATION_DISPATCH_JOB = "notification-dispatch"
+	// Billing 账本对账：按账本重算余额并标记偏差
+	TRIGGER_BILLING_LEDGER_RECONCILE_JOB = "billing-ledger-reconcile"
 	// 未来可以扩展其他巡检任务，例如：
 	// CHECK_NODE_HEALTH = "check-node-health"
 )
@@ -28,15 +32,21 @@ type GpuAnalysisServiceInterface interface {
 
 type BillingServiceInterface interface {
 	RunBaseLoopOnce(ctx context.Context) (any, error)
+	ReconcileLedgerOnce(ctx context.Context) (any, error)
+}
+
+type NotificationServiceInterface interface {
+	RunDispatchOnce(ctx context.Context) (any, error)
 }
 
 // Clients 包含巡检任务所需的客户端
 type Clients struct {
-	Client             client.Client
-	KubeClient         kubernetes.Interface
-	PromClient         monitor.PrometheusInterface
-	GpuAnalysisService GpuAnalysisServiceInterface
-	BillingService     BillingServiceInterface
+	Client              client.Client
+	KubeClient          kubernetes.Interface
+	PromClient          monitor.PrometheusInterface
+	GpuAnalysisService  GpuAnalysisServiceInterface
+	BillingService      BillingServiceInterface
+	NotificationService NotificationServiceInterface
 }
 
 func NewPatrolClients(
@@ -45,13 +55,15 @@ func NewPatrolClients(
 	promClient monitor.PrometheusInterface,
 	gpuAnalysisService GpuAnalysisServiceInterface,
 	billingService BillingServiceInterface,
+	notificationService NotificationServiceInterface,
 ) *Clients {
 	return &Clients{
-		Client:             cli,
-		KubeClient:         kubeClient,
-		PromClient:         promClient,
-		GpuAnalysisService: gpuAnalysisService,
-		BillingService:     billingService,
+		Client:              cli,
+		KubeClient:          kubeClient,
+		PromClient:          promClient,
+		GpuAnalysisService:  gpuAnalysisService,
+		BillingService:      billingService,
+		NotificationService: notificationService,
 	}
 }
 
@@ -74,6 +86,14 @@ func GetPatrolFunc(jobName string, clients *Clients, jobConfig datatypes.JSON) (
 		f = func(ctx context.Context) (any, error) {
 			return RunTriggerBillingBaseLoop(ctx, clients)
 		}
+	case TRIGGER_NOTIFICATION_DISPATCH_JOB:
+		f = func(ctx context.Context) (any, error) {
+			return RunNotificationDispatch(ctx, clients)
+		}
+	case TRIGGER_BILLING_LEDGER_RECONCILE_JOB:
+		f = func(ctx context.Context) (any, error) {
+			return RunBillingLedgerReconcile(ctx, clients)
+		}
 
 	default:
 		return nil, fmt.Errorf("unsupported patrol job name: %s", jobName)
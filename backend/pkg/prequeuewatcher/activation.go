@@ -2,7 +2,9 @@ package prequeuewatcher
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"sort"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
@@ -29,6 +31,20 @@ type timedOutNormalBlocker struct {
 
 type timedOutNormalBlockers map[blockingScope][]timedOutNormalBlocker
 
+// notHeldClause hides prequeue rows whose workflow parents have not finished or whose
+// job array has no free parallelism slot. Held rows are neither activation candidates
+// nor fairness blockers.
+const notHeldClause = `NOT EXISTS (
+	SELECT 1 FROM workflow_nodes
+	WHERE workflow_nodes.job_name = jobs.job_name
+		AND workflow_nodes.held = @held
+		AND workflow_nodes.deleted_at IS NULL)
+	AND NOT EXISTS (
+	SELECT 1 FROM job_array_tasks
+	WHERE job_array_tasks.job_name = jobs.job_name
+		AND job_array_tasks.held = @held
+		AND job_array_tasks.deleted_at IS NULL)`
+
 func (w *PrequeueWatcher) activateNextPrequeueBatch(ctx context.Context, remaining int) (bool, error) {
 	cfg, err := w.configService.GetPrequeueConfig(ctx)
 	if err != nil {
@@ -100,7 +116,8 @@ func (w *PrequeueWatcher) restoreJobForActivation(ctx context.Context, candidate
 	return job, nil
 }
 
-// selectActivationCandidates applies quota and timeout blockers while preserving FCFS order.
+// selectActivationCandidates applies quota and timeout blockers while preserving FCFS order,
+// or fair-share order when the activation policy asks for it.
 //
 //nolint:gocyclo // Candidate filtering keeps quota and timeout ordering together.
 func (w *PrequeueWatcher) selectActivationCandidates(
@@ -134,11 +151,22 @@ func (w *PrequeueWatcher) selectActivationCandidates(
 		pageSize = defaultPageSize
 	}
 
+	listPage := w.listPrequeueJobPage
+	if cfg.FairShareEnabled() && w.queueQuotaSvc != nil {
+		tiers, err := w.listFairSharePrequeueTiers(ctx, cfg, now)
+		if err != nil {
+			return nil, false, err
+		}
+		listPage = func(ctx context.Context, offset, limit int) ([]*model.Job, error) {
+			return w.listFairSharePrequeueJobPage(ctx, tiers, offset, limit)
+		}
+	}
+
 	selected := make([]*model.Job, 0, limit)
 	selectedResources := make(map[string]v1.ResourceList)
 	offset := 0
 	for {
-		page, err := w.listPrequeueJobPage(ctx, offset, pageSize)
+		page, err := listPage(ctx, offset, pageSize)
 		if err != nil {
 			return nil, false, err
 		}
@@ -245,6 +273,7 @@ func (w *PrequeueWatcher) listPrequeueJobPage(
 	err := w.q.Job.WithContext(ctx).UnderlyingDB().
 		Model(&model.Job{}).
 		Where("status = ?", model.Prequeue).
+		Where(notHeldClause, sql.Named("held", true)).
 		Order("creation_timestamp ASC").
 		Offset(offset).
 		Limit(limit).
@@ -252,6 +281,109 @@ func (w *PrequeueWatcher) listPrequeueJobPage(
 	return records, err
 }
 
+// fairShareTier holds the account members whose prequeue jobs share one fair-share priority;
+// their jobs compete in creation order.
+type fairShareTier struct {
+	scopes [][]any // (account_id, user_id) pairs
+	count  int
+}
+
+type prequeueScopeCount struct {
+	AccountID uint
+	UserID    uint
+	Count     int
+}
+
+// listFairSharePrequeueTiers groups the members with activatable prequeue jobs by descending
+// fair-share priority, so a page of candidates can be loaded without reading the whole queue.
+func (w *PrequeueWatcher) listFairSharePrequeueTiers(
+	ctx context.Context,
+	cfg *model.PrequeueRuntimeConfig,
+	now time.Time,
+) ([]fairShareTier, error) {
+	scopes, err := w.countActivatablePrequeueJobsByMember(ctx)
+	if err != nil || len(scopes) == 0 {
+		return nil, err
+	}
+	snapshot, err := w.queueQuotaSvc.LoadFairShareSnapshot(
+		ctx,
+		time.Duration(cfg.FairShareHalfLifeHours)*time.Hour,
+		now,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return groupFairShareTiers(scopes, snapshot), nil
+}
+
+func (w *PrequeueWatcher) countActivatablePrequeueJobsByMember(ctx context.Context) ([]prequeueScopeCount, error) {
+	scopes := make([]prequeueScopeCount, 0)
+	err := w.q.Job.WithContext(ctx).UnderlyingDB().
+		Model(&model.Job{}).
+		Select("account_id, user_id, COUNT(*) AS count").
+		Where("status = ?", model.Prequeue).
+		Where(notHeldClause, sql.Named("held", true)).
+		Group("account_id, user_id").
+		Scan(&scopes).Error
+	return scopes, err
+}
+
+func groupFairShareTiers(scopes []prequeueScopeCount, snapshot *service.FairShareSnapshot) []fairShareTier {
+	priority := func(scope prequeueScopeCount) float64 {
+		return snapshot.Priority(scope.AccountID, scope.UserID)
+	}
+	sort.SliceStable(scopes, func(i, j int) bool {
+		return priority(scopes[i]) > priority(scopes[j])
+	})
+
+	tiers := make([]fairShareTier, 0, len(scopes))
+	for i, scope := range scopes {
+		if i == 0 || priority(scope) != priority(scopes[i-1]) {
+			tiers = append(tiers, fairShareTier{})
+		}
+		tier := &tiers[len(tiers)-1]
+		tier.scopes = append(tier.scopes, []any{scope.AccountID, scope.UserID})
+		tier.count += scope.Count
+	}
+	return tiers
+}
+
+// listFairSharePrequeueJobPage returns the activatable prequeue jobs at offset in fair-share
+// order: tier by tier, and in creation order inside a tier.
+func (w *PrequeueWatcher) listFairSharePrequeueJobPage(
+	ctx context.Context,
+	tiers []fairShareTier,
+	offset int,
+	limit int,
+) ([]*model.Job, error) {
+	records := make([]*model.Job, 0, limit)
+	for _, tier := range tiers {
+		if len(records) >= limit {
+			break
+		}
+		if offset >= tier.count {
+			offset -= tier.count
+			continue
+		}
+		page := make([]*model.Job, 0, limit-len(records))
+		err := w.q.Job.WithContext(ctx).UnderlyingDB().
+			Model(&model.Job{}).
+			Where("status = ?", model.Prequeue).
+			Where(notHeldClause, sql.Named("held", true)).
+			Where("(account_id, user_id) IN ?", tier.scopes).
+			Order("creation_timestamp ASC").
+			Offset(offset).
+			Limit(limit - len(records)).
+			Find(&page).Error
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, page...)
+		offset = 0
+	}
+	return records, nil
+}
+
 func (w *PrequeueWatcher) loadTimedOutNormalBlockersByStatus(
 	ctx context.Context,
 	status batch.JobPhase,
@@ -312,6 +444,9 @@ func (w *PrequeueWatcher) listNormalJobsByStatusPage(
 	if len(jobTypes) > 0 {
 		queryBuilder = queryBuilder.Where("job_type IN ?", jobTypes)
 	}
+	if status == model.Prequeue {
+		queryBuilder = queryBuilder.Where(notHeldClause, sql.Named("held", true))
+	}
 
 	page := make([]*model.Job, 0, limit)
 	if err := queryBuilder.Find(&page).Error; err != nil {
@@ -325,26 +460,36 @@ func (w *PrequeueWatcher) candidateFitsQueueQuota(
 	candidate *model.Job,
 	selectedResources map[string]v1.ResourceList,
 ) (bool, error) {
+	limitCheck, err := w.checkCandidateQueueQuota(ctx, w.currentRuntimeConfig(), candidate, selectedResources)
+	if err != nil {
+		return false, err
+	}
+	return limitCheck == nil || !limitCheck.Enabled || !limitCheck.Exceeded, nil
+}
+
+// checkCandidateQueueQuota returns nil when queue quota does not apply to the candidate.
+func (w *PrequeueWatcher) checkCandidateQueueQuota(
+	ctx context.Context,
+	cfg *model.PrequeueRuntimeConfig,
+	candidate *model.Job,
+	selectedResources map[string]v1.ResourceList,
+) (*service.ResourceLimitCheckResult, error) {
 	if candidate == nil {
-		return true, nil
+		return nil, nil
 	}
 	if (candidate.ScheduleType != nil && *candidate.ScheduleType == model.ScheduleTypeBackfill) ||
-		!w.currentRuntimeConfig().QueueQuotaEnabled || w.queueQuotaSvc == nil {
-		return true, nil
+		!cfg.QueueQuotaEnabled || w.queueQuotaSvc == nil {
+		return nil, nil
 	}
 
 	key := fmt.Sprintf("%d:%d:%s", candidate.AccountID, candidate.UserID, candidate.Queue)
 	projected := utils.SumResources(selectedResources[key], candidate.Resources.Data())
 	resourceMap := utils.ToStringMap(projected)
-	limitCheck, err := w.queueQuotaSvc.CheckUserResourceLimit(
+	return w.queueQuotaSvc.CheckUserResourceLimit(
 		ctx,
 		candidate.UserID,
 		candidate.AccountID,
 		candidate.Queue,
 		resourceMap,
 	)
-	if err != nil {
-		return false, err
-	}
-	return !limitCheck.Enabled || !limitCheck.Exceeded, nil
 }
@@ -15,13 +15,17 @@
 package prequeuewatcher
 
 import (
+	"fmt"
+	"slices"
 	"testing"
+	"time"
 
 	"gorm.io/datatypes"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
 	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
@@ -140,3 +144,119 @@ func supportedBandwidthFlannelClient() *fake.Clientset {
 		},
 	)
 }
+
+func TestSelectActivationCandidatesFairShareOrder(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:prequeue_fair_share_order?mode=memory&cache=shared"), &gorm.Config{
+		DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Migrator().CreateTable(
+		&model.Account{}, &model.UserAccount{}, &model.Job{}, &model.WorkflowNode{}, &model.JobArrayTask{},
+	); err != nil {
+		t.Fatal(err)
+	}
+	q := query.Use(db)
+	now := time.Now()
+	gpus := datatypes.NewJSONType(corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("4")})
+	for _, job := range []*model.Job{
+		{
+			JobName: "heavy-running", AccountID: 1, UserID: 10, Status: batch.Running,
+			CreationTimestamp: now.Add(-2 * time.Hour), RunningTimestamp: now.Add(-2 * time.Hour), Resources: gpus,
+		},
+		{JobName: "heavy-wait", AccountID: 1, UserID: 10, Status: model.Prequeue, CreationTimestamp: now.Add(-time.Minute)},
+		{JobName: "light-wait", AccountID: 2, UserID: 20, Status: model.Prequeue, CreationTimestamp: now},
+	} {
+		if err := db.Create(job).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	watcher := &PrequeueWatcher{q: q, queueQuotaSvc: service.NewPrequeueService(q, nil)}
+	names := func() []string {
+		candidates, _, err := watcher.selectActivationCandidates(t.Context(), 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := make([]string, 0, len(candidates))
+		for _, candidate := range candidates {
+			result = append(result, candidate.JobName)
+		}
+		return result
+	}
+
+	if got := names(); !slices.Equal(got, []string{"heavy-wait", "light-wait"}) {
+		t.Fatalf("fifo order = %v", got)
+	}
+	cfg := model.NewPrequeueRuntimeConfig()
+	cfg.ActivationPolicy = model.PrequeueActivationPolicyFairShare
+	if err := watcher.applyRuntimeConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if got := names(); !slices.Equal(got, []string{"light-wait", "heavy-wait"}) {
+		t.Fatalf("fair share order = %v", got)
+	}
+}
+
+func TestListFairSharePrequeueJobPage(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:prequeue_fair_share_page?mode=memory&cache=shared"), &gorm.Config{
+		DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Migrator().CreateTable(
+		&model.Account{}, &model.UserAccount{}, &model.Job{}, &model.WorkflowNode{}, &model.JobArrayTask{},
+	); err != nil {
+		t.Fatal(err)
+	}
+	q := query.Use(db)
+	now := time.Now()
+	gpus := datatypes.NewJSONType(corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("4")})
+	jobs := []*model.Job{{
+		JobName: "heavy-running", AccountID: 1, UserID: 10, Status: batch.Running,
+		CreationTimestamp: now.Add(-2 * time.Hour), RunningTimestamp: now.Add(-2 * time.Hour), Resources: gpus,
+	}}
+	// Idle members share the top priority, so their jobs interleave by creation time.
+	for i, scope := range [][2]uint{{1, 10}, {2, 20}, {3, 30}, {1, 10}, {3, 30}, {2, 20}} {
+		jobs = append(jobs, &model.Job{
+			JobName: fmt.Sprintf("wait-%d-%d", scope[1], i), AccountID: scope[0], UserID: scope[1],
+			Status: model.Prequeue, CreationTimestamp: now.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	for _, job := range jobs {
+		if err := db.Create(job).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	watcher := &PrequeueWatcher{q: q, queueQuotaSvc: service.NewPrequeueService(q, nil)}
+	cfg := model.NewPrequeueRuntimeConfig()
+	cfg.ActivationPolicy = model.PrequeueActivationPolicyFairShare
+	tiers, err := watcher.listFairSharePrequeueTiers(t.Context(), cfg, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tiers) != 2 || tiers[0].count != 4 || tiers[1].count != 2 {
+		t.Fatalf("tiers = %+v, want idle members before the heavy one", tiers)
+	}
+
+	var got []string
+	for offset := 0; ; offset += 4 {
+		page, err := watcher.listFairSharePrequeueJobPage(t.Context(), tiers, offset, 4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(page) == 0 {
+			break
+		}
+		for _, job := range page {
+			got = append(got, job.JobName)
+		}
+	}
+	want := []string{"wait-20-1", "wait-30-2", "wait-30-4", "wait-20-5", "wait-10-0", "wait-10-3"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("fair share pages = %v, want %v", got, want)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
 
 	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/pkg/alert"
 	"github.com/raids-lab/crater/pkg/config"
 	"github.com/raids-lab/crater/pkg/utils"
 	vcjobadmission "github.com/raids-lab/crater/pkg/vcjob/admission"
@@ -129,9 +130,15 @@ func (w *PrequeueWatcher) deletePreemptedBackfillJobs(
 			},
 		}
 		err := w.k8sClient.Delete(ctx, job)
-		if err != nil && !apierrors.IsNotFound(err) {
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
 			return true, err
 		}
+		if err := alert.GetAlertMgr().NotifyJobEvent(ctx, record, model.NotificationEventBackfillPreempted); err != nil {
+			w.logger.Error(err, "failed to notify preempted backfill job", "job", record.JobName)
+		}
 	}
 
 	return true, nil
@@ -0,0 +1,510 @@
+package prequeuewatcher
+
+import (
+	"container/heap"
+	"context"
+	"database/sql"
+	"fmt"
+	"maps"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/internal/service"
+	vcjobservice "github.com/raids-lab/crater/internal/service/vcjob"
+	"github.com/raids-lab/crater/pkg/utils"
+	"github.com/raids-lab/crater/pkg/vcjob/admission"
+)
+
+// QueueBlockReason explains why a prequeued job has not been activated yet.
+type QueueBlockReason string
+
+const (
+	// QueueBlockReasonHeld waits for workflow parents or a free job array slot.
+	QueueBlockReasonHeld QueueBlockReason = "held"
+	// QueueBlockReasonQuota waits for the user's own jobs to release queue quota.
+	QueueBlockReasonQuota QueueBlockReason = "quota"
+	// QueueBlockReasonTimedOutBlocker yields to a normal job that exceeded its waiting tolerance.
+	QueueBlockReasonTimedOutBlocker QueueBlockReason = "timed_out_blocker"
+	// QueueBlockReasonNodeFit cannot be placed on any node even with the cluster empty.
+	QueueBlockReasonNodeFit QueueBlockReason = "node_fit"
+	// QueueBlockReasonWaiting is ready and waits for its turn in an activation round.
+	QueueBlockReasonWaiting QueueBlockReason = "waiting"
+)
+
+// queueHistoryWindow bounds the completed jobs used for duration estimates.
+const queueHistoryWindow = 30 * 24 * time.Hour
+
+// QueuedJobStatus is the queue view of one prequeued job.
+type QueuedJobStatus struct {
+	Job *model.Job
+	// Position is 1-based inside the job's account and Volcano queue; held jobs come last.
+	Position  int
+	ScopeSize int
+	Reason    QueueBlockReason
+	Detail    string
+	// EstimatedStartAt is nil when there is no history to estimate from or the job cannot fit.
+	EstimatedStartAt  *time.Time
+	FairSharePriority *float64
+}
+
+// QueueStatusQuery selects one page of the prequeued jobs to report, in activation order.
+// Jobs outside the page still count towards positions and estimates.
+type QueueStatusQuery struct {
+	// UserID and AccountID restrict the report to the jobs of one account member when set.
+	UserID    uint
+	AccountID uint
+	Offset    int
+	Limit     int
+}
+
+func (query *QueueStatusQuery) includes(job *model.Job) bool {
+	return (query.UserID == 0 || job.UserID == query.UserID) &&
+		(query.AccountID == 0 || job.AccountID == query.AccountID)
+}
+
+func (query *QueueStatusQuery) where(db *gorm.DB) *gorm.DB {
+	if query.UserID != 0 {
+		db = db.Where("user_id = ?", query.UserID)
+	}
+	if query.AccountID != 0 {
+		db = db.Where("account_id = ?", query.AccountID)
+	}
+	return db
+}
+
+// QueueStatus reports position, blocking reason and estimated start time of one page of
+// prequeued jobs, and how many jobs the query matches. The queue is read page by page only up
+// to the last reported job, and only reported jobs are checked against quota and node fit.
+func (w *PrequeueWatcher) QueueStatus(
+	ctx context.Context,
+	query QueueStatusQuery,
+) ([]*QueuedJobStatus, int64, error) {
+	cfg, err := w.configService.GetPrequeueConfig(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	now := utils.GetLocalTime()
+
+	activeScopes, err := w.countPrequeueJobsByScope(ctx, false)
+	if err != nil {
+		return nil, 0, err
+	}
+	heldScopes, err := w.countPrequeueJobsByScope(ctx, true)
+	if err != nil {
+		return nil, 0, err
+	}
+	var matchedActive, matchedHeld int64
+	if err := query.where(w.prequeueJobs(ctx, false)).Count(&matchedActive).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.where(w.prequeueJobs(ctx, true)).Count(&matchedHeld).Error; err != nil {
+		return nil, 0, err
+	}
+	total := matchedActive + matchedHeld
+	if query.Limit <= 0 || int64(query.Offset) >= total {
+		return []*QueuedJobStatus{}, total, nil
+	}
+
+	report := &queueReport{
+		query:     query,
+		skip:      query.Offset,
+		scopeSize: maps.Clone(activeScopes),
+		position:  make(map[blockingScope]int),
+		reported:  make([]*QueuedJobStatus, 0, query.Limit),
+	}
+	for scope, count := range heldScopes {
+		report.scopeSize[scope] += count
+	}
+	var snapshot *service.FairShareSnapshot
+	if cfg.FairShareEnabled() && w.queueQuotaSvc != nil {
+		snapshot, err = w.queueQuotaSvc.LoadFairShareSnapshot(
+			ctx,
+			time.Duration(cfg.FairShareHalfLifeHours)*time.Hour,
+			now,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	var walked []*QueuedJobStatus
+	if int64(query.Offset) < matchedActive {
+		if walked, err = w.walkActivatableQueue(ctx, cfg, snapshot, report, now); err != nil {
+			return nil, 0, err
+		}
+	} else {
+		report.skip -= int(matchedActive)
+	}
+	if !report.full() {
+		// Held jobs come after every activatable job of their scope.
+		report.position = maps.Clone(activeScopes)
+		if err := w.walkHeldQueue(ctx, report); err != nil {
+			return nil, 0, err
+		}
+	}
+	if snapshot != nil {
+		for _, status := range report.reported {
+			priority := snapshot.Priority(status.Job.AccountID, status.Job.UserID)
+			status.FairSharePriority = &priority
+		}
+	}
+
+	if len(walked) > 0 {
+		estimator, err := w.loadStartEstimator(ctx, now)
+		if err != nil {
+			return nil, 0, err
+		}
+		estimator.estimate(walked)
+	}
+	return report.reported, total, nil
+}
+
+// queueReport collects the requested page while the queue is walked in activation order.
+type queueReport struct {
+	query     QueueStatusQuery
+	skip      int
+	scopeSize map[blockingScope]int
+	position  map[blockingScope]int
+	reported  []*QueuedJobStatus
+}
+
+// add places the next job of the queue and reports whether it belongs to the page.
+func (r *queueReport) add(job *model.Job, reason QueueBlockReason) (*QueuedJobStatus, bool) {
+	scope := blockingScope{accountID: job.AccountID, queue: job.Queue}
+	r.position[scope]++
+	status := &QueuedJobStatus{Job: job, Position: r.position[scope], ScopeSize: r.scopeSize[scope], Reason: reason}
+	if !r.query.includes(job) {
+		return status, false
+	}
+	if r.skip > 0 {
+		r.skip--
+		return status, false
+	}
+	r.reported = append(r.reported, status)
+	return status, true
+}
+
+func (r *queueReport) full() bool {
+	return len(r.reported) >= r.query.Limit
+}
+
+// walkActivatableQueue reads activatable jobs in activation order until the page is full and
+// explains the reported ones. Every walked job is returned, since all of them take a slot
+// ahead of the reported jobs in the estimate.
+func (w *PrequeueWatcher) walkActivatableQueue(
+	ctx context.Context,
+	cfg *model.PrequeueRuntimeConfig,
+	snapshot *service.FairShareSnapshot,
+	report *queueReport,
+	now time.Time,
+) ([]*QueuedJobStatus, error) {
+	pendingBlockers := timedOutNormalBlockers{}
+	prequeueBlockers := timedOutNormalBlockers{}
+	var err error
+	if cfg.ShouldBlockByTimedOutPendingNormalJob() {
+		if pendingBlockers, err = w.loadTimedOutNormalBlockersByStatus(ctx, batch.Pending, now); err != nil {
+			return nil, err
+		}
+		if prequeueBlockers, err = w.loadTimedOutNormalBlockersByStatus(ctx, model.Prequeue, now); err != nil {
+			return nil, err
+		}
+	}
+
+	listPage := w.listPrequeueJobPage
+	if snapshot != nil {
+		scopes, err := w.countActivatablePrequeueJobsByMember(ctx)
+		if err != nil {
+			return nil, err
+		}
+		tiers := groupFairShareTiers(scopes, snapshot)
+		listPage = func(ctx context.Context, offset, limit int) ([]*model.Job, error) {
+			return w.listFairSharePrequeueJobPage(ctx, tiers, offset, limit)
+		}
+	}
+
+	walked := make([]*QueuedJobStatus, 0)
+	for offset := 0; !report.full(); {
+		page, err := listPage(ctx, offset, defaultPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, job := range page {
+			status, included := report.add(job, QueueBlockReasonWaiting)
+			walked = append(walked, status)
+			if included {
+				if err := w.explainQueuedJob(ctx, cfg, status, pendingBlockers, prequeueBlockers, now); err != nil {
+					return nil, err
+				}
+			}
+			if report.full() {
+				break
+			}
+		}
+		if len(page) < defaultPageSize {
+			break
+		}
+		offset += len(page)
+	}
+	return walked, nil
+}
+
+// walkHeldQueue reads held jobs in creation order until the page is full.
+func (w *PrequeueWatcher) walkHeldQueue(ctx context.Context, report *queueReport) error {
+	for offset := 0; !report.full(); {
+		page := make([]*model.Job, 0, defaultPageSize)
+		err := w.prequeueJobs(ctx, true).
+			Order("creation_timestamp ASC").
+			Offset(offset).
+			Limit(defaultPageSize).
+			Find(&page).Error
+		if err != nil {
+			return err
+		}
+		for _, job := range page {
+			report.add(job, QueueBlockReasonHeld)
+			if report.full() {
+				break
+			}
+		}
+		if len(page) < defaultPageSize {
+			return nil
+		}
+		offset += len(page)
+	}
+	return nil
+}
+
+// prequeueJobs selects the prequeue rows that are held, or the ones that are not.
+func (w *PrequeueWatcher) prequeueJobs(ctx context.Context, held bool) *gorm.DB {
+	clause := notHeldClause
+	if held {
+		clause = "NOT (" + notHeldClause + ")"
+	}
+	return w.q.Job.WithContext(ctx).UnderlyingDB().
+		Model(&model.Job{}).
+		Where("status = ?", model.Prequeue).
+		Where(clause, sql.Named("held", true))
+}
+
+type prequeueQueueCount struct {
+	AccountID uint
+	Queue     string
+	Count     int
+}
+
+func (w *PrequeueWatcher) countPrequeueJobsByScope(ctx context.Context, held bool) (map[blockingScope]int, error) {
+	rows := make([]prequeueQueueCount, 0)
+	if err := w.prequeueJobs(ctx, held).
+		Select("account_id, queue, COUNT(*) AS count").
+		Group("account_id, queue").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	counts := make(map[blockingScope]int, len(rows))
+	for _, row := range rows {
+		counts[blockingScope{accountID: row.AccountID, queue: row.Queue}] = row.Count
+	}
+	return counts, nil
+}
+
+func (w *PrequeueWatcher) explainQueuedJob(
+	ctx context.Context,
+	cfg *model.PrequeueRuntimeConfig,
+	status *QueuedJobStatus,
+	pendingBlockers timedOutNormalBlockers,
+	prequeueBlockers timedOutNormalBlockers,
+	now time.Time,
+) error {
+	limitCheck, err := w.checkCandidateQueueQuota(ctx, cfg, status.Job, nil)
+	if err != nil {
+		return err
+	}
+	if limitCheck != nil && limitCheck.Enabled && limitCheck.Exceeded {
+		status.Reason = QueueBlockReasonQuota
+		status.Detail = formatExceededQuota(limitCheck.Details)
+		return nil
+	}
+	if isCandidateBlockedByTimedOutBlockers(status.Job, cfg.BackfillEnabled, pendingBlockers, prequeueBlockers, now) {
+		status.Reason = QueueBlockReasonTimedOutBlocker
+		status.Detail = "a normal job in the same account and resource domain exceeded its waiting tolerance"
+		return nil
+	}
+	if w.k8sClient == nil {
+		return nil
+	}
+	job, err := vcjobservice.RestoreJobFromRecord(status.Job)
+	if err != nil {
+		return err
+	}
+	result, err := admission.CheckJobAdmission(ctx, w.k8sClient, job)
+	if err != nil {
+		return err
+	}
+	if !result.Accepted {
+		status.Reason = QueueBlockReasonNodeFit
+		status.Detail = result.Reason
+	}
+	return nil
+}
+
+func formatExceededQuota(details []service.ResourceLimitDetail) string {
+	parts := make([]string, 0, len(details))
+	for _, detail := range details {
+		if detail.Exceeded {
+			parts = append(parts, fmt.Sprintf("%s %s/%s", detail.Resource, detail.Used, detail.Limit))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// startEstimator simulates slot hand-over per resource domain: every queued job takes the
+// slot of the earliest finishing job ahead of it and keeps it for its owner's typical duration.
+type startEstimator struct {
+	now          time.Time
+	userDuration map[uint]time.Duration
+	duration     time.Duration
+	slots        map[string]*endTimeHeap
+	pending      []*model.Job
+}
+
+type durationRecord struct {
+	UserID             uint
+	RunningTimestamp   time.Time
+	CompletedTimestamp time.Time
+}
+
+func (w *PrequeueWatcher) loadStartEstimator(ctx context.Context, now time.Time) (*startEstimator, error) {
+	db := w.q.Job.WithContext(ctx).UnderlyingDB()
+	history := make([]durationRecord, 0)
+	if err := db.Model(&model.Job{}).
+		Select("user_id", "running_timestamp", "completed_timestamp").
+		Where("status IN ?", []batch.JobPhase{batch.Completed, batch.Failed, batch.Terminated, batch.Aborted}).
+		Where("running_timestamp > ? AND completed_timestamp >= ?", time.Time{}, now.Add(-queueHistoryWindow)).
+		Find(&history).Error; err != nil {
+		return nil, err
+	}
+	running := make([]*model.Job, 0)
+	if err := db.Model(&model.Job{}).
+		Select("id", "user_id", "running_timestamp", "resources").
+		Where("status = ?", batch.Running).
+		Find(&running).Error; err != nil {
+		return nil, err
+	}
+	pending := make([]*model.Job, 0)
+	if err := db.Model(&model.Job{}).
+		Select("id", "user_id", "resources").
+		Where("status = ?", batch.Pending).
+		Order("creation_timestamp ASC").
+		Find(&pending).Error; err != nil {
+		return nil, err
+	}
+	return newStartEstimator(now, history, running, pending), nil
+}
+
+func newStartEstimator(now time.Time, history []durationRecord, running, pending []*model.Job) *startEstimator {
+	e := &startEstimator{
+		now:          now,
+		userDuration: make(map[uint]time.Duration),
+		slots:        make(map[string]*endTimeHeap),
+		pending:      pending,
+	}
+	var total time.Duration
+	count := 0
+	userTotals := make(map[uint]time.Duration)
+	userCounts := make(map[uint]int)
+	for i := range history {
+		elapsed := history[i].CompletedTimestamp.Sub(history[i].RunningTimestamp)
+		if elapsed <= 0 {
+			continue
+		}
+		total += elapsed
+		count++
+		userTotals[history[i].UserID] += elapsed
+		userCounts[history[i].UserID]++
+	}
+	if count > 0 {
+		e.duration = total / time.Duration(count)
+	}
+	for userID, sum := range userTotals {
+		e.userDuration[userID] = sum / time.Duration(userCounts[userID])
+	}
+
+	for _, job := range running {
+		end := e.now
+		if d, ok := e.durationOf(job.UserID); ok && job.RunningTimestamp.Add(d).After(e.now) {
+			end = job.RunningTimestamp.Add(d)
+		}
+		e.slotsOf(utils.GetJobResourceDomain(job)).push(end)
+	}
+	return e
+}
+
+func (e *startEstimator) durationOf(userID uint) (time.Duration, bool) {
+	if d, ok := e.userDuration[userID]; ok {
+		return d, true
+	}
+	return e.duration, e.duration > 0
+}
+
+func (e *startEstimator) slotsOf(domain string) *endTimeHeap {
+	slots, ok := e.slots[domain]
+	if !ok {
+		slots = &endTimeHeap{}
+		e.slots[domain] = slots
+	}
+	return slots
+}
+
+// estimate fills EstimatedStartAt in queue order. Pending Volcano jobs are served first.
+// A domain without running jobs has free capacity, so its jobs are estimated to start now.
+func (e *startEstimator) estimate(statuses []*QueuedJobStatus) {
+	if e.duration <= 0 {
+		return
+	}
+	for _, job := range e.pending {
+		e.take(job)
+	}
+	for _, status := range statuses {
+		if status.Reason == QueueBlockReasonHeld || status.Reason == QueueBlockReasonNodeFit {
+			continue
+		}
+		start := e.take(status.Job)
+		status.EstimatedStartAt = &start
+	}
+}
+
+func (e *startEstimator) take(job *model.Job) time.Time {
+	slots := e.slotsOf(utils.GetJobResourceDomain(job))
+	if slots.Len() == 0 {
+		return e.now
+	}
+	start := heap.Pop(slots).(time.Time)
+	if start.Before(e.now) {
+		start = e.now
+	}
+	d, _ := e.durationOf(job.UserID)
+	heap.Push(slots, start.Add(d))
+	return start
+}
+
+type endTimeHeap []time.Time
+
+func (h endTimeHeap) Len() int           { return len(h) }
+func (h endTimeHeap) Less(i, j int) bool { return h[i].Before(h[j]) }
+func (h endTimeHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *endTimeHeap) Push(x any) { *h = append(*h, x.(time.Time)) }
+
+func (h *endTimeHeap) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+func (h *endTimeHeap) push(t time.Time) { heap.Push(h, t) }
@@ -0,0 +1,118 @@
+package prequeuewatcher
+
+import (
+	"testing"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	batch "volcano.sh/apis/pkg/apis/batch/v1alpha1"
+
+	"github.com/raids-lab/crater/dao/model"
+	"github.com/raids-lab/crater/dao/query"
+	"github.com/raids-lab/crater/internal/service"
+	"github.com/raids-lab/crater/pkg/utils"
+)
+
+func TestQueueStatusPositionsReasonsAndEstimates(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:prequeue_queue_status?mode=memory&cache=shared"), &gorm.Config{
+		DisableForeignKeyConstraintWhenMigrating: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Migrator().CreateTable(
+		&model.SystemConfig{}, &model.PrequeueConfig{}, &model.Job{}, &model.WorkflowNode{}, &model.JobArrayTask{},
+	); err != nil {
+		t.Fatal(err)
+	}
+	q := query.Use(db)
+	now := utils.GetLocalTime()
+	gpus := datatypes.NewJSONType(corev1.ResourceList{"nvidia.com/gpu": resource.MustParse("1")})
+	for _, job := range []*model.Job{
+		{
+			JobName: "history", UserID: 10, AccountID: 1, Status: batch.Completed, Resources: gpus,
+			CreationTimestamp: now.Add(-5 * time.Hour), RunningTimestamp: now.Add(-4 * time.Hour),
+			CompletedTimestamp: now.Add(-2 * time.Hour),
+		},
+		{
+			JobName: "running", UserID: 10, AccountID: 1, Status: batch.Running, Resources: gpus,
+			CreationTimestamp: now.Add(-time.Hour), RunningTimestamp: now.Add(-time.Hour),
+		},
+		{JobName: "first", UserID: 10, AccountID: 1, Status: model.Prequeue, Resources: gpus, CreationTimestamp: now.Add(-3 * time.Minute)},
+		{JobName: "held", UserID: 20, AccountID: 1, Status: model.Prequeue, Resources: gpus, CreationTimestamp: now.Add(-2 * time.Minute)},
+		{JobName: "second", UserID: 20, AccountID: 1, Status: model.Prequeue, Resources: gpus, CreationTimestamp: now.Add(-time.Minute)},
+	} {
+		if err := db.Create(job).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.Create(&model.WorkflowNode{WorkflowID: 1, Name: "n", JobName: "held", Held: true}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	watcher := &PrequeueWatcher{q: q, configService: service.NewConfigService(q)}
+	statuses, total, err := watcher.QueueStatus(t.Context(), QueueStatusQuery{Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 3 || total != 3 {
+		t.Fatalf("got %d of %d statuses, want 3", len(statuses), total)
+	}
+	byName := map[string]*QueuedJobStatus{}
+	for _, status := range statuses {
+		byName[status.Job.JobName] = status
+	}
+
+	first, second, held := byName["first"], byName["second"], byName["held"]
+	if first.Position != 1 || second.Position != 2 || held.Position != 3 || first.ScopeSize != 3 {
+		t.Fatalf("positions = %d/%d/%d of %d", first.Position, second.Position, held.Position, first.ScopeSize)
+	}
+	if first.Reason != QueueBlockReasonWaiting || held.Reason != QueueBlockReasonHeld {
+		t.Fatalf("reasons = %s/%s", first.Reason, held.Reason)
+	}
+	// The running job is expected to take the user's 2h average, so it frees its GPU in 1h;
+	// "first" then holds it for 2h before "second" gets it.
+	assertEstimate := func(status *QueuedJobStatus, want time.Duration) {
+		t.Helper()
+		if status.EstimatedStartAt == nil {
+			t.Fatalf("%s has no estimate", status.Job.JobName)
+		}
+		if got := status.EstimatedStartAt.Sub(now); got < want-time.Second || got > want+time.Second {
+			t.Fatalf("%s starts in %v, want %v", status.Job.JobName, got, want)
+		}
+	}
+	assertEstimate(first, time.Hour)
+	assertEstimate(second, 3*time.Hour)
+	if held.EstimatedStartAt != nil {
+		t.Fatalf("held job should have no estimate")
+	}
+
+	own, total, err := watcher.QueueStatus(t.Context(), QueueStatusQuery{UserID: 20, AccountID: 1, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(own) != 2 || total != 2 || own[0].Job.JobName != "second" || own[0].Position != 2 {
+		t.Fatalf("filtered statuses = %+v of %d", own, total)
+	}
+
+	// Jobs ahead of the page still take their slot in the estimate.
+	page, total, err := watcher.QueueStatus(t.Context(), QueueStatusQuery{Offset: 1, Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 1 || total != 3 || page[0].Job.JobName != "second" {
+		t.Fatalf("second page = %+v of %d", page, total)
+	}
+	assertEstimate(page[0], 3*time.Hour)
+	page, _, err = watcher.QueueStatus(t.Context(), QueueStatusQuery{Offset: 2, Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 1 || page[0].Job.JobName != "held" || page[0].Position != 3 || page[0].ScopeSize != 3 {
+		t.Fatalf("third page = %+v", page)
+	}
+}
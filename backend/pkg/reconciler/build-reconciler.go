@@ -40,6 +40,7 @@ import (
 	"github.com/raids-lab/crater/dao/query"
 	"github.com/raids-lab/crater/pkg/config"
 	"github.com/raids-lab/crater/pkg/imageregistry"
+	"github.com/raids-lab/crater/pkg/imagescan"
 	"github.com/raids-lab/crater/pkg/packer"
 )
 
@@ -131,6 +132,13 @@ func (r *BuildKitReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		return ctrl.Result{Requeue: true}, err
 	}
 
+	// record the cloned commit of git builds once the clone has finished
+	if kaniko.BuildSource == model.GitRepo && packer.GitCommitFromTemplate(kaniko.Template) == "" {
+		if err = r.recordGitCommit(ctx, &job, kaniko); err != nil {
+			logger.Error(err, "record git commit failed")
+		}
+	}
+
 	// 6. get the job status from k8s job
 	oldStatus := kaniko.Status
 	jobStatus := r.getJobBuildStatus(ctx, &job)
@@ -293,6 +301,34 @@ func (r *BuildKitReconciler) createImageRecord(ctx context.Context, kaniko *mode
 	}
 
 	logger.Info("Image record created successfully: " + kaniko.ImagePackName)
+	if err = imagescan.RequestScan(ctx, image.ID, image.ImageLink); err != nil && !errors.Is(err, imagescan.ErrScanDisabled) {
+		logger.Error(err, "request image scan failed", "image", image.ImageLink)
+	}
+	return nil
+}
+
+// recordGitCommit adds the commit written by the git-clone init container to the kaniko template.
+func (r *BuildKitReconciler) recordGitCommit(ctx context.Context, job *batchv1.Job, kaniko *model.Kaniko) error {
+	podList := &v1.PodList{}
+	if err := r.List(ctx, podList, client.InNamespace(job.Namespace), client.MatchingLabels{"job-name": job.Name}); err != nil {
+		return err
+	}
+	for i := range podList.Items {
+		commit := packer.GitCommitFromPod(&podList.Items[i])
+		if commit == "" {
+			continue
+		}
+		template, err := packer.RecordGitCommit(kaniko.Template, commit)
+		if err != nil {
+			return err
+		}
+		k := query.Kaniko
+		if _, err = k.WithContext(ctx).Where(k.ImagePackName.Eq(kaniko.ImagePackName)).Update(k.Template, template); err != nil {
+			return err
+		}
+		kaniko.Template = template
+		return nil
+	}
 	return nil
 }
 
@@ -175,6 +175,14 @@ func (r *ModelDownloadReconciler) syncDownloadWithJob(
 	}
 
 	if newStatus == model.ModelDownloadStatusReady && download.Status != model.ModelDownloadStatusReady {
+		// Until the final result replaces it with the size on disk, SizeBytes holds the
+		// repository total announced by the job, which the dataset verification checks against.
+		// Once the downloaded bytes caught up with it (or after a retry, when both hold the
+		// final du size), there is nothing left to compare.
+		expectedSize := download.SizeBytes
+		if download.DownloadedBytes >= expectedSize {
+			expectedSize = 0
+		}
 		if err := r.extractFinalResult(ctx, job, download); err != nil {
 			logger.Error(err, "failed to extract final result")
 		}
@@ -191,6 +199,9 @@ func (r *ModelDownloadReconciler) syncDownloadWithJob(
 			logger.Error(err, "failed to create dataset for model")
 			return ctrl.Result{RequeueAfter: progressRequeueInterval}, err
 		}
+		if err := r.requestDatasetVerification(ctx, download, expectedSize); err != nil {
+			logger.Error(err, "failed to request verification of the downloaded dataset")
+		}
 	}
 
 	// When a download fails, capture the reason from pod logs so the user can
@@ -249,6 +260,11 @@ func (r *ModelDownloadReconciler) persistRepositoryMetadata(
 	ctx context.Context, download *model.ModelDownload, logs string,
 ) error {
 	organization := strings.SplitN(download.Name, "/", 2)[0]
+	if download.Source.IsRemoteURL() {
+		// Buckets and plain URLs have no repository page or metadata to mirror.
+		return query.GetDB().WithContext(ctx).Model(&model.ModelDownload{}).
+			Where("id = ?", download.ID).Update("organization", organization).Error
+	}
 	metadata := parseRepositoryMetadata(logs)
 	logoURL, logoData, logoContentType, logoErr := resolveRepositoryLogo(ctx, download, metadata.LogoURL)
 	if logoErr != nil {
@@ -465,6 +481,13 @@ func (r *ModelDownloadReconciler) updateDownloadStatus(
 
 		switch status {
 		case model.ModelDownloadStatusReady:
+			// A finished bucket download is never resubmitted, so its credentials can go.
+			if download.S3AccessKey != "" {
+				if err := tx.Model(&model.ModelDownload{}).Where("id = ?", download.ID).
+					Updates(map[string]any{"s3_access_key": "", "s3_secret_key": ""}).Error; err != nil {
+					return err
+				}
+			}
 			return service.CompleteModelDownloadQuotaReservation(ctx, tx, download.ID, time.Now())
 		case model.ModelDownloadStatusFailed:
 			return service.ReleaseModelDownloadQuotaReservation(ctx, tx, download.ID)
@@ -705,6 +728,22 @@ var downloadFailureRules = []downloadFailureRule{
 		keywords: []string{"revision_not_found"},
 		reason:   "Download failed: the requested revision does not exist. Check the source branches or leave revision empty to use its default.",
 	},
+	{
+		keywords: []string{"checksum_mismatch"},
+		reason:   "Download failed: the downloaded file does not match the expected checksum.",
+	},
+	{
+		keywords: []string{"unsupported_archive", "unsafe_archive"},
+		reason:   "Download failed: the file is not a zip or tar archive that can be extracted safely.",
+	},
+	{
+		keywords: []string{"s3_no_objects", "nosuchbucket", "nosuchkey"},
+		reason:   "Download failed: no objects were found at the S3 URL. Check the bucket and prefix.",
+	},
+	{
+		keywords: []string{"invalidaccesskeyid", "signaturedoesnotmatch"},
+		reason:   "Download failed: the S3 access key or secret key was rejected.",
+	},
 	{
 		keywords: []string{"gated", "awaiting a review", "access to model", "you must be authenticated"},
 		reason:   "Download failed: this repository is gated and requires authorization/login on the source site.",
@@ -772,6 +811,10 @@ func formatSpeed(bytesPerSec int64) string {
 // downloadSourceURL returns the public page of the downloaded repo on its
 // source site, used as the dataset's WebURL and description fallback.
 func downloadSourceURL(download *model.ModelDownload) string {
+	if download.Source.IsRemoteURL() {
+		// Set on creation to the URL without its query, which may hold a presigned token.
+		return download.SourceURL
+	}
 	if download.Source == model.ModelSourceHuggingFace {
 		endpoint := config.GetConfig().HuggingFaceDownloadEndpoint()
 		if download.Category == model.DownloadCategoryDataset {
@@ -794,18 +837,22 @@ func datasetDescriptionForDownload(download *model.ModelDownload, readmeDesc str
 		return readmeDesc
 	}
 
-	var sourceLabel string
-	if download.Source == model.ModelSourceModelScope {
-		sourceLabel = "ModelScope"
-	} else {
-		sourceLabel = "HuggingFace"
-	}
 	var resourceLabel string
 	if download.Category == model.DownloadCategoryDataset {
 		resourceLabel = "数据集"
 	} else {
 		resourceLabel = "模型"
 	}
+	if download.Source.IsRemoteURL() {
+		return fmt.Sprintf("从 %s 导入的%s %s", download.SourceURL, resourceLabel, download.Name)
+	}
+
+	var sourceLabel string
+	if download.Source == model.ModelSourceModelScope {
+		sourceLabel = "ModelScope"
+	} else {
+		sourceLabel = "HuggingFace"
+	}
 
 	desc := fmt.Sprintf("%s 上的%s %s", sourceLabel, resourceLabel, download.Name)
 	if download.Revision != "" {
@@ -979,6 +1026,25 @@ func (r *ModelDownloadReconciler) createDatasetForModelTx(
 	return nil
 }
 
+// requestDatasetVerification has the storage server index the downloaded files and check them for
+// partial downloads before anyone relies on them.
+func (r *ModelDownloadReconciler) requestDatasetVerification(
+	ctx context.Context, download *model.ModelDownload, expectedSize int64,
+) error {
+	dataType := model.DataTypeModel
+	if download.Category == model.DownloadCategoryDataset {
+		dataType = model.DataTypeDataset
+	}
+	d := query.Dataset
+	dataset, err := d.WithContext(ctx).
+		Where(d.URL.Eq(r.convertToPhysicalPath(download.Path)), d.Type.Eq(string(dataType))).
+		First()
+	if err != nil {
+		return fmt.Errorf("failed to query dataset for download %d: %w", download.ID, err)
+	}
+	return service.RequestDatasetVerification(ctx, query.GetDB(), dataset.ID, expectedSize)
+}
+
 func (r *ModelDownloadReconciler) ensureDatasetAssociations(
 	ctx context.Context, txQuery *query.Query, datasetID, userID uint,
 ) error {
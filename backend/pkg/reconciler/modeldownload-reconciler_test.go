@@ -39,6 +39,9 @@ func TestClassifyDownloadFailure(t *testing.T) {
 		{name: "validated missing revision", logs: "[ERROR] revision_not_found: 'main'", want: "requested revision does not exist"},
 		{name: "storage", logs: "write failed: no space left on device", want: "no space left"},
 		{name: "network", logs: "connection reset by peer", want: "network error"},
+		{name: "checksum", logs: "[ERROR] checksum_mismatch: expected sha256:00", want: "expected checksum"},
+		{name: "s3 credentials", logs: "An error occurred (InvalidAccessKeyId) when calling ListObjectsV2", want: "access key"},
+		{name: "empty s3 prefix", logs: "[ERROR] s3_no_objects: no objects found under s3://b/p", want: "bucket and prefix"},
 		{name: "fallback", logs: "trace\ncustom downloader error\n", want: "custom downloader error"},
 	}
 
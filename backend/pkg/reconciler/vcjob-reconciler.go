@@ -62,6 +62,8 @@ type VcJobReconciler struct {
 	kubeClient       kubernetes.Interface
 	prequeueWatcher  *prequeuewatcher.PrequeueWatcher
 	billingService   *service.BillingService
+	workflowService  *service.WorkflowService
+	jobArrayService  *service.JobArrayService
 }
 
 // NewVcJobReconciler returns a new reconcile.Reconciler
@@ -72,6 +74,8 @@ func NewVcJobReconciler(
 	kubeClient kubernetes.Interface,
 	prequeueWatcher *prequeuewatcher.PrequeueWatcher,
 	billingService *service.BillingService,
+	workflowService *service.WorkflowService,
+	jobArrayService *service.JobArrayService,
 ) *VcJobReconciler {
 	return &VcJobReconciler{
 		Client:           crClient,
@@ -81,6 +85,8 @@ func NewVcJobReconciler(
 		kubeClient:       kubeClient,
 		prequeueWatcher:  prequeueWatcher,
 		billingService:   billingService,
+		workflowService:  workflowService,
+		jobArrayService:  jobArrayService,
 	}
 }
 
@@ -143,6 +149,10 @@ func (r *VcJobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 				logger.Error(err, "unable to update job profile data")
 				return ctrl.Result{Requeue: true}, err
 			}
+			if err = r.releaseHeldJobs(ctx, req.Name); err != nil {
+				logger.Error(err, "unable to release held jobs")
+				return ctrl.Result{Requeue: true}, err
+			}
 			r.notifyPrequeue()
 			return ctrl.Result{}, nil
 		}
@@ -192,6 +202,10 @@ func (r *VcJobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 				logger.Error(settleErr, "billing final settlement hook failed")
 			}
 		}
+		if err = r.releaseHeldJobs(ctx, req.Name); err != nil {
+			logger.Error(err, "unable to release held jobs")
+			return ctrl.Result{Requeue: true}, err
+		}
 		r.notifyPrequeue()
 		return ctrl.Result{}, nil
 	}
@@ -219,29 +233,10 @@ func (r *VcJobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		return ctrl.Result{}, nil
 	}
 
-	// if job found: before updating, check previous status, and send email
-	if oldRecord.AlertEnabled {
-		alertMgr := alert.GetAlertMgr()
-
-		// send email after pending
-		if job.Status.State.Phase == batch.Running && oldRecord.Status != batch.Running {
-			if err = alertMgr.JobRunningAlert(ctx, job.Name); err != nil {
-				logger.Error(err, "fail to send email")
-			}
-		}
-
-		// alert job failure
-		if job.Status.State.Phase == batch.Failed && oldRecord.Status != batch.Failed {
-			if err = alertMgr.JobFailureAlert(ctx, job.Name); err != nil {
-				logger.Error(err, "fail to send email")
-			}
-		}
-
-		// alert job complete
-		if job.Status.State.Phase == batch.Completed && oldRecord.Status != batch.Completed {
-			if err = alertMgr.JobCompleteAlert(ctx, job.Name); err != nil {
-				logger.Error(err, "fail to send email")
-			}
+	// if job found: before updating, check previous status, and notify the owner
+	if event, ok := jobPhaseNotificationEvent(oldRecord.Status, job.Status.State.Phase); ok {
+		if err = alert.GetAlertMgr().NotifyJobEvent(ctx, oldRecord, event); err != nil {
+			logger.Error(err, "fail to send job notification", "event", event)
 		}
 	}
 
@@ -281,6 +276,14 @@ func (r *VcJobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		r.cancelPendingApprovalOrders(ctx, job.Name, fmt.Sprintf("job is not running (status: %s), order is canceled", job.Status.State.Phase))
 	}
 
+	// Releasing is idempotent, so a failed attempt is simply retried on requeue.
+	if isReleasedJobPhase(job.Status.State.Phase) {
+		if err = r.releaseHeldJobs(ctx, job.Name); err != nil {
+			logger.Error(err, "unable to release held jobs")
+			return ctrl.Result{Requeue: true}, err
+		}
+	}
+
 	if shouldActivatePrequeueOnPhaseChange(oldRecord.Status, job.Status.State.Phase) {
 		r.notifyPrequeue()
 	}
@@ -303,6 +306,23 @@ func isReleasedJobPhase(status batch.JobPhase) bool {
 		status == batch.Terminated
 }
 
+// releaseHeldJobs lets the jobs waiting on a finished job enter the prequeue: downstream
+// workflow nodes and the next elements of its job array.
+func (r *VcJobReconciler) releaseHeldJobs(ctx context.Context, jobName string) error {
+	workflowReleased, err := r.workflowService.ReleaseDependents(ctx, jobName)
+	if err != nil {
+		return err
+	}
+	arrayReleased, err := r.jobArrayService.ReleaseNext(ctx, jobName)
+	if err != nil {
+		return err
+	}
+	if workflowReleased || arrayReleased {
+		r.notifyPrequeue()
+	}
+	return nil
+}
+
 func (r *VcJobReconciler) notifyPrequeue() {
 	if r.prequeueWatcher == nil {
 		return
@@ -449,6 +469,7 @@ func (r *VcJobReconciler) generateCreateJobModel(ctx context.Context, job *batch
 		Resources:               datatypes.NewJSONType(resources),
 		Attributes:              datatypes.NewJSONType(job),
 		Template:                job.Annotations[vcjob.AnnotationKeyTaskTemplate],
+		DatasetVersions:         vcjobservice.MountedDatasetVersions(job),
 		AlertEnabled:            alertEnabled,
 	}, nil
 }
@@ -602,3 +623,21 @@ func (r *VcJobReconciler) generateUpdateJobModel(ctx context.Context, job *batch
 		WaitingToleranceSeconds: waitingToleranceSeconds,
 	}
 }
+
+// jobPhaseNotificationEvent maps a phase transition to the notification event it raises.
+func jobPhaseNotificationEvent(oldPhase, newPhase batch.JobPhase) (model.NotificationEvent, bool) {
+	if oldPhase == newPhase {
+		return "", false
+	}
+	//nolint:exhaustive // other phases do not raise notifications
+	switch newPhase {
+	case batch.Running:
+		return model.NotificationEventJobStarted, true
+	case batch.Failed:
+		return model.NotificationEventJobFailed, true
+	case batch.Completed:
+		return model.NotificationEventJobCompleted, true
+	default:
+		return "", false
+	}
+}
@@ -44,7 +44,7 @@ func GetImageNameAndTag(imageLink string) (name, tag string, err error) {
 }
 
 func GenerateNewImageLinkForEnvdBuild(username, python, base, imageName, imageTag string) (newImageLink string, err error) {
-	registryServer := config.GetConfig().Registry.Harbor.Server
+	registryServer := config.GetConfig().RegistryServer()
 	registryProject := fmt.Sprintf("user-%s", username)
 	if imageName == "" {
 		imageName = "envd"
@@ -61,7 +61,7 @@ func GenerateNewImageLinkForEnvdBuild(username, python, base, imageName, imageTa
 }
 
 func GenerateNewImageLinkForDockerfileBuild(imageLink, username, imageName, imageTag string) (newImageLink string, err error) {
-	registryServer := config.GetConfig().Registry.Harbor.Server
+	registryServer := config.GetConfig().RegistryServer()
 	registryProject := fmt.Sprintf("user-%s", username)
 	loc, _ := time.LoadLocation("Asia/Shanghai")
 	if imageName == "" {
@@ -53,11 +53,23 @@ Generate the url of main project
 Generate dockerconfigjson
 */}}
 {{- define "dockerconfigjson" -}}
-{{- $registry := .Values.backendConfig.registry.harbor.server -}}
-{{- $username := .Values.backendConfig.registry.harbor.user -}}
-{{- $password := .Values.backendConfig.registry.harbor.password -}}
+{{- $registryConfig := .Values.backendConfig.registry -}}
+{{- if eq (lower (default "harbor" $registryConfig.type)) "oci" -}}
+{{- $oci := $registryConfig.oci -}}
+{{- if $oci.user -}}
+{{- printf "{\"auths\":{\"%s\":{\"username\":\"%s\",\"password\":\"%s\",\"auth\":\"%s\"}}}" $oci.server $oci.user $oci.password (printf "%s:%s" $oci.user $oci.password | b64enc) | b64enc -}}
+{{- else if $oci.token -}}
+{{- printf "{\"auths\":{\"%s\":{\"registrytoken\":\"%s\"}}}" $oci.server $oci.token | b64enc -}}
+{{- else -}}
+{{- printf "{\"auths\":{\"%s\":{}}}" $oci.server | b64enc -}}
+{{- end -}}
+{{- else -}}
+{{- $registry := $registryConfig.harbor.server -}}
+{{- $username := $registryConfig.harbor.user -}}
+{{- $password := $registryConfig.harbor.password -}}
 {{- printf "{\"auths\":{\"%s\":{\"username\":\"%s\",\"password\":\"%s\",\"auth\":\"%s\"}}}" $registry $username $password (printf "%s:%s" $username $password | b64enc) | b64enc -}}
 {{- end -}}
+{{- end -}}
 
 {{/*
 Generate backend config with images from top-level images section
@@ -70,6 +82,7 @@ Generate backend config with images from top-level images section
     "buildx" (printf "%s:%s" .Values.images.buildx.repository .Values.images.buildx.tag)
     "nerdctl" (printf "%s:%s" .Values.images.nerdctl.repository .Values.images.nerdctl.tag)
     "envd" (printf "%s:%s" .Values.images.envd.repository .Values.images.envd.tag)
+    "git" (printf "%s:%s" .Values.images.git.repository .Values.images.git.tag)
   ) -}}
   {{- $_ := set $config.registry "buildTools" $buildTools -}}
 {{- end -}}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/raids-lab/crater/cli/internal/api"
+	"github.com/raids-lab/crater/cli/internal/i18n"
+	"github.com/raids-lab/crater/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var adminAccountFairShareCmd = &cobra.Command{Use: "fair-share", Short: "View and set fair-share weights of an account"}
+var adminAccountFairShareGetCmd = &cobra.Command{Use: "get <id>", Short: "Show shares, decayed GPU-hours and priorities", Args: exactArgs(1, "id"), RunE: runAdminAccountFairShareGet}
+var adminAccountFairShareSetCmd = &cobra.Command{Use: "set <id>", Short: "Set the share of an account or one of its members", Args: exactArgs(1, "id"), RunE: runAdminAccountFairShareSet}
+
+func runAdminAccountFairShareGet(cmd *cobra.Command, args []string) error {
+	id, err := requiredUintArg(args, "account_label_id", "id")
+	if err != nil {
+		return err
+	}
+	path := fmt.Sprintf("%s/%s/fair-share", api.AdminAccountsPrefix, api.UintPath(id))
+	return runRawRead(cmd, rawReadSpec{PayloadKey: "fair_share", Path: path, Params: noParams, Table: printFairShareReport})
+}
+
+func runAdminAccountFairShareSet(cmd *cobra.Command, args []string) error {
+	id, err := requiredUintArg(args, "account_label_id", "id")
+	if err != nil {
+		return err
+	}
+	req, err := fairShareRequestFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	client, err := activeAPIClient()
+	if err != nil {
+		return err
+	}
+	data, err := client.UpdateFairShare(id, req)
+	if err != nil {
+		return cliErrFromAPI(err)
+	}
+	if outputJSON {
+		return output.WriteSuccessJSON(os.Stdout, output.SuccessEnvelope(map[string]interface{}{"fair_share": data}))
+	}
+	printFairShareReport(data)
+	return nil
+}
+
+// fairShareRequestFromFlags maps --share 0 to a nil share, which restores the default of 1.
+func fairShareRequestFromFlags(cmd *cobra.Command) (api.FairShareRequest, error) {
+	user, _ := cmd.Flags().GetUint("user")
+	req := api.FairShareRequest{UserID: user}
+	if !cmd.Flags().Changed("share") {
+		return req, errUsageFromIssues([]usageIssue{missingIssue("share", "fair_share_label_share")})
+	}
+	share, _ := cmd.Flags().GetInt("share")
+	if share < 0 {
+		return req, errUsageFromIssues([]usageIssue{invalidIssue("share", i18n.T("err_invalid_fair_share", share))})
+	}
+	if share > 0 {
+		req.Share = &share
+	}
+	return req, nil
+}
+
+func printFairShareReport(data interface{}) {
+	report := rawMap(data)
+	account := rawMap(report["account"])
+	fmt.Printf("%s: %s\n", i18n.T("fair_share_policy"), rawString(report, "policy"))
+	fmt.Printf("%s: %sh\n", i18n.T("fair_share_half_life"), rawString(report, "halfLifeHours"))
+	fmt.Printf("%s: %s  %s: %s  %s: %s\n",
+		i18n.T("fair_share_table_share"), rawString(account, "share"),
+		i18n.T("fair_share_table_usage"), fairShareNumber(account, "usageGpuHours", 2),
+		i18n.T("fair_share_table_factor"), fairShareNumber(account, "factor", 4))
+	fmt.Println()
+	fmt.Printf("%s %s %s %s %s\n",
+		i18n.PadRight("USER_ID", 10),
+		i18n.PadRight("USERNAME", 24),
+		i18n.PadRight(i18n.T("fair_share_table_share"), 8),
+		i18n.PadRight(i18n.T("fair_share_table_usage"), 14),
+		i18n.T("fair_share_table_priority"))
+	members, _ := report["members"].([]interface{})
+	for _, item := range members {
+		row := rawMap(item)
+		share := rawString(row, "share")
+		if rawString(row, "configured") != "true" {
+			share += "*"
+		}
+		fmt.Printf("%s %s %s %s %s\n",
+			i18n.PadRight(rawString(row, "userId"), 10),
+			i18n.PadRight(rawString(row, "name"), 24),
+			i18n.PadRight(share, 8),
+			i18n.PadRight(fairShareNumber(row, "usageGpuHours", 2), 14),
+			fairShareNumber(row, "priority", 4))
+	}
+}
+
+func fairShareNumber(row map[string]interface{}, key string, precision int) string {
+	value, ok := row[key].(float64)
+	if !ok {
+		return rawString(row, key)
+	}
+	return strconv.FormatFloat(value, 'f', precision, 64)
+}
+
+func init() {
+	adminAccountFairShareSetCmd.Flags().Uint("user", 0, "Member user ID (0 sets the share of the account itself)")
+	adminAccountFairShareSetCmd.Flags().Int("share", 0, "Positive share weight; 0 restores the default share of 1")
+	adminAccountFairShareCmd.AddCommand(adminAccountFairShareGetCmd, adminAccountFairShareSetCmd)
+	adminAccountCmd.AddCommand(adminAccountFairShareCmd)
+}
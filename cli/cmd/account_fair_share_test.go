@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/raids-lab/crater/cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+func TestFairShareRequestFromFlags(t *testing.T) {
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{Use: "set"}
+		cmd.Flags().Uint("user", 0, "")
+		cmd.Flags().Int("share", 0, "")
+		if err := cmd.Flags().Parse(args); err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		return cmd
+	}
+
+	req, err := fairShareRequestFromFlags(newCmd("--user", "7", "--share", "3"))
+	if err != nil || req.UserID != 7 || req.Share == nil || *req.Share != 3 {
+		t.Fatalf("req = %+v, err = %v", req, err)
+	}
+	req, err = fairShareRequestFromFlags(newCmd("--share", "0"))
+	if err != nil || req.Share != nil {
+		t.Fatalf("--share 0 should reset to the default, req = %+v, err = %v", req, err)
+	}
+	if _, err := fairShareRequestFromFlags(newCmd("--share", "-1")); err == nil {
+		t.Fatal("negative share should be rejected")
+	}
+	if _, err := fairShareRequestFromFlags(newCmd()); err == nil {
+		t.Fatal("missing --share should be rejected")
+	}
+}
+
+func TestFormatJobListStatus(t *testing.T) {
+	priority := 0.4167
+	if got := formatJobListStatus(api.JobInfo{Status: "Prequeue", FairSharePriority: &priority}); got != "Prequeue(0.42)" {
+		t.Fatalf("status = %q", got)
+	}
+	if got := formatJobListStatus(api.JobInfo{Status: "Running"}); got != "Running" {
+		t.Fatalf("status = %q", got)
+	}
+}
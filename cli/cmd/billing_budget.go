@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/raids-lab/crater/cli/internal/api"
+	"github.com/raids-lab/crater/cli/internal/i18n"
+	"github.com/raids-lab/crater/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var budgetCapActions = []string{"stop", "suspend"}
+
+var billingBudgetCmd = &cobra.Command{Use: "budget", Short: "Manage account budgets and spending caps"}
+var billingBudgetLsCmd = &cobra.Command{Use: "ls", Short: "List budgets of an account with current spend", Args: noArgs, RunE: runBillingBudgetLs(api.AccountsPrefix)}
+var billingBudgetSetCmd = &cobra.Command{Use: "set", Short: "Create or update a budget", Args: noArgs, RunE: runBillingBudgetSet(api.AccountsPrefix)}
+var billingBudgetDeleteCmd = &cobra.Command{Use: "delete", Short: "Delete a budget", Args: noArgs, RunE: runBillingBudgetDelete(api.AccountsPrefix)}
+var adminBillingBudgetCmd = &cobra.Command{Use: "budget", Short: "Manage budgets of any account"}
+var adminBillingBudgetLsCmd = &cobra.Command{Use: "ls", Short: "List budgets of an account with current spend", Args: noArgs, RunE: runBillingBudgetLs(api.AdminAccountsPrefix)}
+var adminBillingBudgetSetCmd = &cobra.Command{Use: "set", Short: "Create or update a budget", Args: noArgs, RunE: runBillingBudgetSet(api.AdminAccountsPrefix)}
+var adminBillingBudgetDeleteCmd = &cobra.Command{Use: "delete", Short: "Delete a budget", Args: noArgs, RunE: runBillingBudgetDelete(api.AdminAccountsPrefix)}
+
+func runBillingBudgetLs(prefix string) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		account, err := budgetAccountFlag(cmd)
+		if err != nil {
+			return err
+		}
+		path := fmt.Sprintf("%s/%s/billing/budgets", prefix, api.UintPath(account))
+		return runRawRead(cmd, rawReadSpec{PayloadKey: "billing_budgets", Path: path, Params: noParams, Table: printBillingBudgets})
+	}
+}
+
+func runBillingBudgetSet(prefix string) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		account, err := budgetAccountFlag(cmd)
+		if err != nil {
+			return err
+		}
+		req, err := billingBudgetFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+		client, err := activeAPIClient()
+		if err != nil {
+			return err
+		}
+		data, err := client.UpsertBillingBudget(prefix, account, req)
+		if err != nil {
+			return cliErrFromAPI(err)
+		}
+		if outputJSON {
+			return output.WriteSuccessJSON(os.Stdout, output.SuccessEnvelope(map[string]interface{}{"billing_budget": data}))
+		}
+		fmt.Println(i18n.T("billing_budget_set_success", budgetScope(rawString(data, "userId"), rawString(data, "username"))))
+		return nil
+	}
+}
+
+func runBillingBudgetDelete(prefix string) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		account, err := budgetAccountFlag(cmd)
+		if err != nil {
+			return err
+		}
+		user, _ := cmd.Flags().GetUint("user")
+		if err := requireConfirmation(cmd); err != nil {
+			return err
+		}
+		client, err := activeAPIClient()
+		if err != nil {
+			return err
+		}
+		if err := client.DeleteBillingBudget(prefix, account, user); err != nil {
+			return cliErrFromAPI(err)
+		}
+		if outputJSON {
+			return output.WriteSuccessJSON(os.Stdout, output.SuccessEnvelope(map[string]interface{}{"accountId": account, "userId": user, "deleted": true}))
+		}
+		fmt.Println(i18n.T("billing_budget_delete_success", budgetScope(api.UintPath(user), "")))
+		return nil
+	}
+}
+
+func budgetAccountFlag(cmd *cobra.Command) (uint, error) {
+	account, _ := cmd.Flags().GetUint("account")
+	if account == 0 {
+		return 0, errUsageFromIssues([]usageIssue{missingIssue("account", "billing_budget_label_account")})
+	}
+	return account, nil
+}
+
+// billingBudgetFromFlags validates thresholds, cap and action locally; the
+// server applies the 50/80/100 default when --alert is omitted.
+func billingBudgetFromFlags(cmd *cobra.Command) (api.BillingBudgetRequest, error) {
+	user, _ := cmd.Flags().GetUint("user")
+	alerts, _ := cmd.Flags().GetIntSlice("alert")
+	hardCap, _ := cmd.Flags().GetString("hard-cap")
+	action, _ := cmd.Flags().GetString("action")
+
+	req := api.BillingBudgetRequest{
+		UserID:        user,
+		AlertPercents: alerts,
+		HardCap:       strings.TrimSpace(hardCap),
+		CapAction:     strings.ToLower(strings.TrimSpace(action)),
+	}
+	var issues []usageIssue
+	for _, pct := range alerts {
+		if pct <= 0 || pct > 1000 {
+			issues = append(issues, invalidIssue("alert", i18n.T("err_invalid_budget_percent", pct)))
+		}
+	}
+	if req.HardCap != "" {
+		if v, err := strconv.ParseFloat(req.HardCap, 64); err != nil || v <= 0 {
+			issues = append(issues, invalidIssue("hard-cap", i18n.T("err_invalid_budget_cap", req.HardCap)))
+		}
+	}
+	if req.CapAction != "" && !slices.Contains(budgetCapActions, req.CapAction) {
+		issues = append(issues, invalidIssue("action", i18n.T("err_invalid_budget_action", req.CapAction, strings.Join(budgetCapActions, ", "))))
+	}
+	if len(issues) > 0 {
+		return req, errUsageFromIssues(issues)
+	}
+	return req, nil
+}
+
+func budgetScope(userID, username string) string {
+	if userID == "" || userID == "0" {
+		return i18n.T("billing_budget_scope_account")
+	}
+	if username == "" {
+		username = userID
+	}
+	return i18n.T("billing_budget_scope_user", username)
+}
+
+func printBillingBudgets(data interface{}) {
+	fmt.Printf("%s %s %s %s %s %s %s\n",
+		i18n.PadRight(i18n.T("billing_budget_table_scope"), 20),
+		i18n.PadRight(i18n.T("billing_budget_table_alerts"), 14),
+		i18n.PadRight(i18n.T("billing_budget_table_cap"), 12),
+		i18n.PadRight(i18n.T("billing_budget_table_issued"), 12),
+		i18n.PadRight(i18n.T("billing_budget_table_spent"), 12),
+		i18n.PadRight(i18n.T("billing_budget_table_projected"), 12),
+		i18n.T("billing_budget_table_action"))
+	for _, row := range rawList(data) {
+		var alerts []string
+		if items, ok := row["alertPercents"].([]interface{}); ok {
+			for _, item := range items {
+				alerts = append(alerts, fmt.Sprint(item)+"%")
+			}
+		}
+		hardCap := rawString(row, "hardCap")
+		if hardCap != "" && rawString(row, "capReached") == "true" {
+			hardCap += "!"
+		}
+		action := ""
+		if hardCap != "" {
+			action = rawString(row, "capAction")
+		}
+		fmt.Printf("%s %s %s %s %s %s %s\n",
+			i18n.PadRight(budgetScope(rawString(row, "userId"), rawString(row, "username")), 20),
+			i18n.PadRight(emptyDash(strings.Join(alerts, ",")), 14),
+			i18n.PadRight(emptyDash(hardCap), 12),
+			i18n.PadRight(rawString(row, "issueAmount"), 12),
+			i18n.PadRight(rawString(row, "spent"), 12),
+			i18n.PadRight(rawString(row, "projected"), 12),
+			emptyDash(action))
+	}
+}
+
+func addBillingBudgetFlags(ls, set, del *cobra.Command) {
+	for _, cmd := range []*cobra.Command{ls, set, del} {
+		cmd.Flags().Uint("account", 0, "Account ID the budget belongs to")
+	}
+	set.Flags().Uint("user", 0, "User ID of a member budget (0 for the account-wide budget)")
+	set.Flags().IntSlice("alert", nil, "Alert thresholds in percent of the period issuance, comma separated (default 50,80,100)")
+	set.Flags().String("hard-cap", "", "Points the scope may spend per issuance period; empty disables the cap")
+	set.Flags().String("action", "", "What happens to running jobs at the cap: stop or suspend (default stop)")
+	del.Flags().Uint("user", 0, "User ID of the member budget to delete (0 for the account-wide budget)")
+	del.Flags().BoolP("yes", "y", false, "Skip confirmation")
+}
+
+func init() {
+	addBillingBudgetFlags(billingBudgetLsCmd, billingBudgetSetCmd, billingBudgetDeleteCmd)
+	billingBudgetCmd.AddCommand(billingBudgetLsCmd, billingBudgetSetCmd, billingBudgetDeleteCmd)
+	billingCmd.AddCommand(billingBudgetCmd)
+
+	addBillingBudgetFlags(adminBillingBudgetLsCmd, adminBillingBudgetSetCmd, adminBillingBudgetDeleteCmd)
+	adminBillingBudgetCmd.AddCommand(adminBillingBudgetLsCmd, adminBillingBudgetSetCmd, adminBillingBudgetDeleteCmd)
+	adminBillingCmd.AddCommand(adminBillingBudgetCmd)
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestBillingBudgetFromFlags(t *testing.T) {
+	newCmd := func(args ...string) *cobra.Command {
+		ls, set, del := &cobra.Command{Use: "ls"}, &cobra.Command{Use: "set"}, &cobra.Command{Use: "delete"}
+		addBillingBudgetFlags(ls, set, del)
+		if err := set.Flags().Parse(args); err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		return set
+	}
+
+	req, err := billingBudgetFromFlags(newCmd("--account", "2", "--user", "7", "--alert", "50,90", "--hard-cap", "120.5", "--action", "Suspend"))
+	if err != nil {
+		t.Fatalf("billingBudgetFromFlags() error = %v", err)
+	}
+	if req.UserID != 7 || len(req.AlertPercents) != 2 || req.HardCap != "120.5" || req.CapAction != "suspend" {
+		t.Fatalf("req = %+v", req)
+	}
+
+	_, err = billingBudgetFromFlags(newCmd("--alert", "0,80", "--hard-cap", "-3", "--action", "pause"))
+	if err == nil || !strings.Contains(err.Error(), "pause") {
+		t.Fatalf("error = %v, want invalid action", err)
+	}
+}
@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/raids-lab/crater/cli/internal/api"
+	"github.com/raids-lab/crater/cli/internal/clierror"
+	"github.com/raids-lab/crater/cli/internal/i18n"
+	"github.com/raids-lab/crater/cli/internal/output"
+	"github.com/raids-lab/crater/cli/pkg/errorcodes"
+	"github.com/spf13/cobra"
+)
+
+const (
+	statementFormatTable = "table"
+	statementFormatCSV   = "csv"
+	statementFormatJSON  = "json"
+)
+
+var billingStatementCmd = &cobra.Command{Use: "statement", Short: "Export a billing statement for a period", Args: noArgs, RunE: runBillingStatement}
+var adminBillingStatementCmd = &cobra.Command{Use: "statement", Short: "Export the billing statement of a user or an account", Args: noArgs, RunE: runAdminBillingStatement}
+
+func runBillingStatement(cmd *cobra.Command, _ []string) error {
+	account, _ := cmd.Flags().GetUint("account")
+	allMembers, _ := cmd.Flags().GetBool("all-members")
+	if allMembers && account == 0 {
+		return errUsageFromIssues([]usageIssue{missingIssue("account", "billing_statement_label_account")})
+	}
+	params, format, err := billingStatementParams(cmd)
+	if err != nil {
+		return err
+	}
+	if allMembers {
+		return writeBillingStatement(cmd, fmt.Sprintf("%s/%s/billing/statement", api.AccountsPrefix, api.UintPath(account)), params, format)
+	}
+	if account != 0 {
+		params["accountId"] = api.UintPath(account)
+	}
+	return writeBillingStatement(cmd, api.ContextPrefix+"/billing/statement", params, format)
+}
+
+func runAdminBillingStatement(cmd *cobra.Command, _ []string) error {
+	user := getStringParam(cmd, "user")
+	account, _ := cmd.Flags().GetUint("account")
+	if user == "" && account == 0 {
+		return errUsageFromIssues([]usageIssue{missingIssue("user", "billing_statement_label_target")})
+	}
+	params, format, err := billingStatementParams(cmd)
+	if err != nil {
+		return err
+	}
+	if user == "" {
+		return writeBillingStatement(cmd, fmt.Sprintf("%s/%s/billing/statement", api.AdminAccountsPrefix, api.UintPath(account)), params, format)
+	}
+	if account != 0 {
+		params["accountId"] = api.UintPath(account)
+	}
+	return writeBillingStatement(cmd, api.NamedPath(api.AdminUsersPrefix, user, "billing/statement"), params, format)
+}
+
+// billingStatementParams validates the period and output format flags.
+func billingStatementParams(cmd *cobra.Command) (map[string]string, string, error) {
+	from := getStringParam(cmd, "from")
+	to := getStringParam(cmd, "to")
+	format := strings.ToLower(getStringParam(cmd, "format"))
+	if outputJSON {
+		format = statementFormatJSON
+	}
+	var issues []usageIssue
+	if from != "" && !validStatementDate(from) {
+		issues = append(issues, invalidIssue("from", i18n.T("err_invalid_statement_date", from)))
+	}
+	if to != "" && !validStatementDate(to) {
+		issues = append(issues, invalidIssue("to", i18n.T("err_invalid_statement_date", to)))
+	}
+	switch format {
+	case statementFormatTable, statementFormatCSV, statementFormatJSON:
+	default:
+		issues = append(issues, invalidIssue("format", i18n.T("err_invalid_statement_format", format)))
+	}
+	if len(issues) > 0 {
+		return nil, "", errUsageFromIssues(issues)
+	}
+	params := map[string]string{"from": from, "to": to}
+	if format == statementFormatCSV {
+		params["format"] = statementFormatCSV
+	}
+	return params, format, nil
+}
+
+func validStatementDate(v string) bool {
+	if _, err := time.Parse("2006-01-02", v); err == nil {
+		return true
+	}
+	_, err := time.Parse(time.RFC3339, v)
+	return err == nil
+}
+
+func writeBillingStatement(cmd *cobra.Command, path string, params map[string]string, format string) error {
+	client, err := activeAPIClient()
+	if err != nil {
+		return err
+	}
+	outputPath := getStringParam(cmd, "output")
+	if format == statementFormatCSV {
+		data, err := client.GetRawBytes(path, params)
+		if err != nil {
+			return cliErrFromAPI(err)
+		}
+		if outputPath == "" {
+			_, err = os.Stdout.Write(data)
+			return err
+		}
+		if err := os.WriteFile(outputPath, data, 0o600); err != nil {
+			return &clierror.Error{Category: errorcodes.CategorySystem, Code: errorcodes.ErrCommandExecution, Message: i18n.T("err_write_file", outputPath, err.Error())}
+		}
+		fmt.Fprintln(os.Stderr, i18n.T("billing_statement_saved", outputPath))
+		return nil
+	}
+
+	data, err := client.GetRaw(path, params)
+	if err != nil {
+		return cliErrFromAPI(err)
+	}
+	if format == statementFormatJSON {
+		return output.WriteSuccessJSON(os.Stdout, output.SuccessEnvelope(map[string]interface{}{"statement": data}))
+	}
+	printBillingStatement(data)
+	return nil
+}
+
+func printBillingStatement(data interface{}) {
+	st := rawMap(data)
+	if st == nil {
+		printRawObject(data)
+		return
+	}
+	fmt.Printf("%s: %s ~ %s\n", i18n.T("billing_statement_period"), rawString(st, "from"), rawString(st, "to"))
+	fmt.Printf("%s: %s  %s: %s  %s: %s  %s: %s\n",
+		i18n.T("billing_statement_issued"), rawString(st, "issued"),
+		i18n.T("billing_statement_adjusted"), rawString(st, "adjusted"),
+		i18n.T("billing_statement_settled"), rawString(st, "settled"),
+		i18n.T("billing_statement_net"), rawString(st, "net"))
+	entries := rawList(st["entries"])
+	if len(entries) == 0 {
+		return
+	}
+	fmt.Println()
+	fmt.Printf("%s %s %s %s %s %s\n",
+		i18n.PadRight(i18n.T("billing_statement_table_time"), 26),
+		i18n.PadRight(i18n.T("billing_statement_table_type"), 12),
+		i18n.PadRight(i18n.T("billing_statement_table_user"), 14),
+		i18n.PadRight(i18n.T("billing_statement_table_account"), 16),
+		i18n.PadRight(i18n.T("billing_statement_table_amount"), 12),
+		i18n.T("billing_statement_table_detail"))
+	for _, entry := range entries {
+		detail := rawString(entry, "description")
+		if job := rawString(entry, "jobName"); job != "" {
+			detail = job + " " + detail
+		}
+		fmt.Printf("%s %s %s %s %s %s\n",
+			i18n.PadRight(rawString(entry, "time"), 26),
+			i18n.PadRight(rawString(entry, "type"), 12),
+			i18n.PadRight(emptyDash(rawString(entry, "username")), 14),
+			i18n.PadRight(emptyDash(rawString(entry, "accountName")), 16),
+			i18n.PadRight(rawString(entry, "amount"), 12),
+			emptyDash(strings.TrimSpace(detail)))
+	}
+}
+
+func addBillingStatementFlags(cmd *cobra.Command) {
+	cmd.Flags().String("from", "", "Start date (YYYY-MM-DD or RFC3339), defaults to the start of this month")
+	cmd.Flags().String("to", "", "End date (YYYY-MM-DD is inclusive), defaults to now")
+	cmd.Flags().String("format", statementFormatTable, "Output format: table, csv or json")
+	cmd.Flags().String("output", "", "Write the CSV statement to a file instead of stdout")
+}
+
+func init() {
+	addBillingStatementFlags(billingStatementCmd)
+	billingStatementCmd.Flags().Uint("account", 0, "Limit the statement to one account")
+	billingStatementCmd.Flags().Bool("all-members", false, "Export every member of --account (account administrators only)")
+	billingCmd.AddCommand(billingStatementCmd)
+
+	addBillingStatementFlags(adminBillingStatementCmd)
+	adminBillingStatementCmd.Flags().String("user", "", "Username to export")
+	adminBillingStatementCmd.Flags().Uint("account", 0, "Account ID to export, or limit --user to one account")
+	adminBillingCmd.AddCommand(adminBillingStatementCmd)
+}
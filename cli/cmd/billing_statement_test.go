@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestBillingStatementParams(t *testing.T) {
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{Use: "statement"}
+		addBillingStatementFlags(cmd)
+		if err := cmd.Flags().Parse(args); err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		return cmd
+	}
+
+	params, format, err := billingStatementParams(newCmd("--from", "2026-09-01", "--to", "2026-09-30", "--format", "CSV"))
+	if err != nil {
+		t.Fatalf("billingStatementParams() error = %v", err)
+	}
+	if format != statementFormatCSV || params["format"] != "csv" || params["from"] != "2026-09-01" || params["to"] != "2026-09-30" {
+		t.Fatalf("params = %v, format = %q", params, format)
+	}
+
+	if _, format, err = billingStatementParams(newCmd()); err != nil || format != statementFormatTable {
+		t.Fatalf("defaults: format = %q, err = %v", format, err)
+	}
+
+	_, _, err = billingStatementParams(newCmd("--from", "09/01/2026", "--format", "xlsx"))
+	if err == nil || !strings.Contains(err.Error(), "09/01/2026") {
+		t.Fatalf("error = %v, want invalid date", err)
+	}
+}
@@ -0,0 +1,294 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/raids-lab/crater/cli/internal/api"
+	"github.com/raids-lab/crater/cli/internal/i18n"
+	"github.com/raids-lab/crater/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// notificationEvents mirrors the events accepted by /api/v1/notifications/rules.
+var notificationEvents = []string{
+	"job_queued_too_long",
+	"job_started",
+	"job_failed",
+	"job_completed",
+	"backfill_preempted",
+	"billing_balance_low",
+	"approval_order_reviewed",
+	"approval_order_requested",
+	"billing_budget_threshold",
+	"billing_budget_cap_reached",
+	"image_cleanup_scheduled",
+}
+
+var notificationsCmd = &cobra.Command{
+	Use:   "notifications",
+	Short: "Manage notification rules and channels",
+	Long:  "Subscribe to job, billing and approval events per user or per account, with quiet hours and digest batching.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 0 {
+			return errUnknownSubcommand(cmd, args[0])
+		}
+		return cmd.Help()
+	},
+}
+
+var notificationsLsCmd = &cobra.Command{Use: "ls", Short: "List notification rules", Args: noArgs, RunE: runNotificationsLs}
+var notificationsEventsCmd = &cobra.Command{Use: "events", Short: "List subscribable notification events", Args: noArgs, RunE: runNotificationsEvents}
+var notificationsSetCmd = &cobra.Command{Use: "set", Short: "Create or update a notification rule", Args: noArgs, RunE: runNotificationsSet}
+var notificationsDeleteCmd = &cobra.Command{Use: "delete", Short: "Delete a notification rule", Args: noArgs, RunE: runNotificationsDelete}
+var notificationsChannelsCmd = &cobra.Command{Use: "channels", Short: "Show notification channels", Args: noArgs, RunE: runNotificationsChannels}
+var notificationsChannelsSetCmd = &cobra.Command{Use: "set", Short: "Replace notification channels", Args: noArgs, RunE: runNotificationsChannelsSet}
+
+func runNotificationsLs(cmd *cobra.Command, _ []string) error {
+	return runRawRead(cmd, rawReadSpec{PayloadKey: "notification_rules", Path: api.NotificationsPrefix + "/rules", Params: noParams, Table: printNotificationRules})
+}
+
+func runNotificationsEvents(cmd *cobra.Command, _ []string) error {
+	return runRawRead(cmd, rawReadSpec{PayloadKey: "events", Path: api.NotificationsPrefix + "/events", Params: noParams, Table: func(data interface{}) {
+		if items, ok := data.([]interface{}); ok {
+			for _, item := range items {
+				fmt.Println(item)
+			}
+		}
+	}})
+}
+
+func runNotificationsSet(cmd *cobra.Command, _ []string) error {
+	req, err := notificationRuleFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	client, err := activeAPIClient()
+	if err != nil {
+		return err
+	}
+	data, err := client.UpsertNotificationRule(req)
+	if err != nil {
+		return cliErrFromAPI(err)
+	}
+	if outputJSON {
+		return output.WriteSuccessJSON(os.Stdout, output.SuccessEnvelope(map[string]interface{}{"notification_rule": data}))
+	}
+	fmt.Println(i18n.T("notifications_set_success", notificationScope(rawString(data, "accountId"))))
+	return nil
+}
+
+// notificationRuleFromFlags builds the rule request and validates it locally so
+// typos in event names fail before reaching the server.
+func notificationRuleFromFlags(cmd *cobra.Command) (api.NotificationRuleRequest, error) {
+	account, _ := cmd.Flags().GetUint("account")
+	events, _ := cmd.Flags().GetStringSlice("events")
+	queued, _ := cmd.Flags().GetInt("queued-minutes")
+	threshold, _ := cmd.Flags().GetString("balance-threshold")
+	quiet, _ := cmd.Flags().GetString("quiet")
+	digest, _ := cmd.Flags().GetInt("digest")
+
+	req := api.NotificationRuleRequest{
+		AccountID:            account,
+		QueuedTooLongMinutes: queued,
+		BalanceLowThreshold:  strings.TrimSpace(threshold),
+		DigestMinutes:        digest,
+	}
+	var issues []usageIssue
+	for _, event := range events {
+		event = strings.TrimSpace(event)
+		if event == "" {
+			continue
+		}
+		if !slices.Contains(notificationEvents, event) {
+			issues = append(issues, invalidIssue("events", i18n.T("err_invalid_notification_event", event, strings.Join(notificationEvents, ", "))))
+			continue
+		}
+		req.Events = append(req.Events, event)
+	}
+	if len(events) == 0 {
+		issues = append(issues, missingIssue("events", "notifications_label_events"))
+	}
+	if queued < 0 {
+		issues = append(issues, invalidIssue("queued-minutes", i18n.T("err_notification_negative", "queued-minutes")))
+	}
+	if digest < 0 {
+		issues = append(issues, invalidIssue("digest", i18n.T("err_notification_negative", "digest")))
+	}
+	if quiet = strings.TrimSpace(quiet); quiet != "" {
+		start, end, ok := strings.Cut(quiet, "-")
+		if !ok || !validClock(start) || !validClock(end) {
+			issues = append(issues, invalidIssue("quiet", i18n.T("err_invalid_quiet_hours", quiet)))
+		} else {
+			req.QuietStart, req.QuietEnd = strings.TrimSpace(start), strings.TrimSpace(end)
+		}
+	}
+	if len(issues) > 0 {
+		return req, errUsageFromIssues(issues)
+	}
+	return req, nil
+}
+
+func validClock(v string) bool {
+	_, err := time.Parse("15:04", strings.TrimSpace(v))
+	return err == nil
+}
+
+func runNotificationsDelete(cmd *cobra.Command, _ []string) error {
+	account, _ := cmd.Flags().GetUint("account")
+	if err := requireConfirmation(cmd); err != nil {
+		return err
+	}
+	client, err := activeAPIClient()
+	if err != nil {
+		return err
+	}
+	if err := client.DeleteNotificationRule(account); err != nil {
+		return cliErrFromAPI(err)
+	}
+	if outputJSON {
+		return output.WriteSuccessJSON(os.Stdout, output.SuccessEnvelope(map[string]interface{}{"accountId": account, "deleted": true}))
+	}
+	fmt.Println(i18n.T("notifications_delete_success", notificationScope(api.UintPath(account))))
+	return nil
+}
+
+func runNotificationsChannels(cmd *cobra.Command, _ []string) error {
+	return runRawRead(cmd, rawReadSpec{PayloadKey: "notification_channels", Path: api.NotificationsPrefix + "/channels", Params: noParams, Table: printNotificationChannels})
+}
+
+func runNotificationsChannelsSet(cmd *cobra.Command, _ []string) error {
+	pref, err := notificationChannelsFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	client, err := activeAPIClient()
+	if err != nil {
+		return err
+	}
+	if err := client.UpdateNotificationChannels(pref); err != nil {
+		return cliErrFromAPI(err)
+	}
+	if outputJSON {
+		return output.WriteSuccessJSON(os.Stdout, output.SuccessEnvelope(map[string]interface{}{"notification_channels": pref}))
+	}
+	fmt.Println(i18n.T("notifications_channels_set_success", len(pref.Channels)))
+	return nil
+}
+
+// notificationChannelsFromFlags reads TYPE=URL pairs from --channel, or the full
+// preference from --file when secrets are needed. --clear resets to email only.
+func notificationChannelsFromFlags(cmd *cobra.Command) (api.NotificationPreference, error) {
+	pref := api.NotificationPreference{Channels: []api.NotificationChannel{}}
+	channels, _ := cmd.Flags().GetStringArray("channel")
+	file, _ := cmd.Flags().GetString("file")
+	clear, _ := cmd.Flags().GetBool("clear")
+
+	switch {
+	case strings.TrimSpace(file) != "":
+		if len(channels) > 0 || clear {
+			return pref, errUsageFromIssues([]usageIssue{invalidIssue("file", i18n.T("err_notification_channel_source"))})
+		}
+		if err := readJSONFile(file, &pref); err != nil {
+			return pref, err
+		}
+		return pref, nil
+	case clear:
+		if len(channels) > 0 {
+			return pref, errUsageFromIssues([]usageIssue{invalidIssue("clear", i18n.T("err_notification_channel_source"))})
+		}
+		return pref, nil
+	case len(channels) == 0:
+		return pref, errUsageFromIssues([]usageIssue{missingIssue("channel", "notifications_label_channel")})
+	}
+
+	var issues []usageIssue
+	for _, raw := range channels {
+		kind, url, ok := strings.Cut(raw, "=")
+		kind, url = strings.TrimSpace(kind), strings.TrimSpace(url)
+		if !ok || kind == "" || (kind != "email" && url == "") {
+			issues = append(issues, invalidIssue("channel", i18n.T("err_invalid_notification_channel", raw)))
+			continue
+		}
+		pref.Channels = append(pref.Channels, api.NotificationChannel{Type: kind, URL: url})
+	}
+	if len(issues) > 0 {
+		return pref, errUsageFromIssues(issues)
+	}
+	return pref, nil
+}
+
+func notificationScope(accountID string) string {
+	if accountID == "" || accountID == "0" {
+		return i18n.T("notifications_scope_user")
+	}
+	return i18n.T("notifications_scope_account", accountID)
+}
+
+func printNotificationRules(data interface{}) {
+	fmt.Printf("%s %s %s %s %s %s\n",
+		i18n.PadRight(i18n.T("notifications_table_scope"), 14),
+		i18n.PadRight(i18n.T("notifications_table_quiet"), 13),
+		i18n.PadRight(i18n.T("notifications_table_digest"), 8),
+		i18n.PadRight(i18n.T("notifications_table_queued"), 8),
+		i18n.PadRight(i18n.T("notifications_table_balance"), 10),
+		i18n.T("notifications_table_events"))
+	for _, row := range rawList(data) {
+		quiet := ""
+		if rawString(row, "quietStart") != "" {
+			quiet = rawString(row, "quietStart") + "-" + rawString(row, "quietEnd")
+		}
+		var events []string
+		if items, ok := row["events"].([]interface{}); ok {
+			for _, item := range items {
+				events = append(events, fmt.Sprint(item))
+			}
+		}
+		fmt.Printf("%s %s %s %s %s %s\n",
+			i18n.PadRight(notificationScope(rawString(row, "accountId")), 14),
+			i18n.PadRight(emptyDash(quiet), 13),
+			i18n.PadRight(rawString(row, "digestMinutes"), 8),
+			i18n.PadRight(rawString(row, "queuedTooLongMinutes"), 8),
+			i18n.PadRight(rawString(row, "balanceLowThreshold"), 10),
+			emptyDash(strings.Join(events, ",")))
+	}
+}
+
+func printNotificationChannels(data interface{}) {
+	pref := rawMap(data)
+	channels := rawList(pref["channels"])
+	if len(channels) == 0 {
+		fmt.Println(i18n.T("notifications_channels_empty"))
+		return
+	}
+	fmt.Printf("%s %s %s\n", i18n.PadRight("#", 4), i18n.PadRight(i18n.T("notifications_table_type"), 10), "URL")
+	for i, channel := range channels {
+		fmt.Printf("%s %s %s\n", i18n.PadRight(fmt.Sprint(i+1), 4), i18n.PadRight(rawString(channel, "type"), 10), emptyDash(rawString(channel, "url")))
+	}
+}
+
+func addNotificationRuleFlags(cmd *cobra.Command) {
+	cmd.Flags().Uint("account", 0, "Account ID the rule applies to (0 for all accounts)")
+	cmd.Flags().StringSlice("events", nil, "Subscribed events, comma separated")
+	cmd.Flags().Int("queued-minutes", 0, "Minutes a job may wait in queue before job_queued_too_long fires")
+	cmd.Flags().String("balance-threshold", "", "Points below which billing_balance_low fires")
+	cmd.Flags().String("quiet", "", "Quiet hours as HH:MM-HH:MM; notifications are held until they end")
+	cmd.Flags().Int("digest", 0, "Batch notifications into a digest every N minutes (0 sends immediately)")
+}
+
+func init() {
+	addNotificationRuleFlags(notificationsSetCmd)
+	notificationsDeleteCmd.Flags().Uint("account", 0, "Account ID of the rule to delete (0 for the user-wide rule)")
+	notificationsDeleteCmd.Flags().BoolP("yes", "y", false, "Skip confirmation")
+
+	notificationsChannelsSetCmd.Flags().StringArray("channel", nil, "Channel as TYPE=URL in fallback order, repeatable")
+	notificationsChannelsSetCmd.Flags().String("file", "", "JSON file with the full channel list, including secrets")
+	notificationsChannelsSetCmd.Flags().Bool("clear", false, "Remove all channels and fall back to email")
+
+	notificationsChannelsCmd.AddCommand(notificationsChannelsSetCmd)
+	notificationsCmd.AddCommand(notificationsLsCmd, notificationsEventsCmd, notificationsSetCmd, notificationsDeleteCmd, notificationsChannelsCmd)
+	configCmd.AddCommand(notificationsCmd)
+}
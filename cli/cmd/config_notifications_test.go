@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestNotificationRuleFromFlags(t *testing.T) {
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{Use: "set"}
+		addNotificationRuleFlags(cmd)
+		if err := cmd.Flags().Parse(args); err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		return cmd
+	}
+
+	req, err := notificationRuleFromFlags(newCmd("--account", "3", "--events", "job_failed,job_completed", "--quiet", "22:00-08:00", "--digest", "30"))
+	if err != nil {
+		t.Fatalf("notificationRuleFromFlags() error = %v", err)
+	}
+	if req.AccountID != 3 || len(req.Events) != 2 || req.QuietStart != "22:00" || req.QuietEnd != "08:00" || req.DigestMinutes != 30 {
+		t.Fatalf("req = %+v", req)
+	}
+
+	_, err = notificationRuleFromFlags(newCmd("--events", "job_exploded", "--quiet", "25:00-08:00"))
+	if err == nil || !strings.Contains(err.Error(), "job_exploded") {
+		t.Fatalf("error = %v, want invalid event", err)
+	}
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/raids-lab/crater/cli/internal/api"
+	"github.com/raids-lab/crater/cli/internal/i18n"
+	"github.com/raids-lab/crater/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var datasetManifestCmd = &cobra.Command{
+	Use:   "manifest <id>",
+	Short: "Show the content manifest and integrity of a dataset",
+	Long: "The storage server indexes every dataset and model in the background: file count, size, a digest over all files " +
+		"and, with --files, the path, size, sha256 and mtime of each file. The integrity is the result of the last verification.",
+	Args: exactArgs(1, "id"),
+	RunE: runDatasetManifest,
+}
+var datasetVerifyCmd = &cobra.Command{Use: "verify <id>", Short: "Rehash a dataset and compare it with its manifest", Args: exactArgs(1, "id"), RunE: runDatasetVerify}
+
+func runDatasetManifest(cmd *cobra.Command, args []string) error {
+	id, err := requiredUintArg(args, "dataset_label_id", "id")
+	if err != nil {
+		return err
+	}
+	files, _ := cmd.Flags().GetBool("files")
+	client, err := activeAPIClient()
+	if err != nil {
+		return err
+	}
+	data, err := client.GetRaw(fmt.Sprintf("%s/detail/%s", api.DatasetPrefix, api.UintPath(id)), map[string]string{"files": strconv.FormatBool(files)})
+	if err != nil {
+		return cliErrFromAPI(err)
+	}
+	var manifest interface{}
+	if datasets := rawList(data); len(datasets) > 0 {
+		manifest = datasets[0]["manifest"]
+	}
+	if outputJSON {
+		return output.WriteSuccessJSON(os.Stdout, output.SuccessEnvelope(map[string]interface{}{"manifest": manifest}))
+	}
+	if manifest == nil {
+		fmt.Println(i18n.T("dataset_manifest_none", id))
+		return nil
+	}
+	printDatasetManifest(rawMap(manifest))
+	return nil
+}
+
+func runDatasetVerify(_ *cobra.Command, args []string) error {
+	id, err := requiredUintArg(args, "dataset_label_id", "id")
+	if err != nil {
+		return err
+	}
+	client, err := activeAPIClient()
+	if err != nil {
+		return err
+	}
+	data, err := client.VerifyDataset(id)
+	if err != nil {
+		return cliErrFromAPI(err)
+	}
+	if outputJSON {
+		return output.WriteSuccessJSON(os.Stdout, output.SuccessEnvelope(map[string]interface{}{"manifest": data}))
+	}
+	fmt.Println(i18n.T("dataset_verify_queued", id, id))
+	return nil
+}
+
+func printDatasetManifest(manifest map[string]interface{}) {
+	count, _ := manifest["fileCount"].(float64)
+	size, _ := manifest["sizeBytes"].(float64)
+	fmt.Printf("%s: %s\n", i18n.T("table_status"), rawString(manifest, "status"))
+	if message := rawString(manifest, "message"); message != "" {
+		fmt.Printf("%s: %s\n", i18n.T("dataset_version_message"), message)
+	}
+	fmt.Printf("%s: %d (%s)\n", i18n.T("dataset_version_table_files"), int64(count), formatBytes(int64(size)))
+	fmt.Printf("%s: %s\n", i18n.T("dataset_manifest_digest"), emptyDash(rawString(manifest, "digest")))
+	fmt.Printf("%s: %s\n", i18n.T("dataset_manifest_indexed"), formatTrashTime(rawString(manifest, "indexedAt")))
+	integrity := rawString(manifest, "integrity")
+	if requested, _ := manifest["verifyRequested"].(bool); requested {
+		integrity += " (" + i18n.T("dataset_manifest_verify_pending") + ")"
+	}
+	fmt.Printf("%s: %s\n", i18n.T("dataset_manifest_integrity"), integrity)
+	fmt.Printf("%s: %s\n", i18n.T("dataset_manifest_verified"), formatTrashTime(rawString(manifest, "verifiedAt")))
+
+	if problems := rawList(manifest["problems"]); len(problems) > 0 {
+		fmt.Println()
+		fmt.Printf("%s %s\n", i18n.PadRight(i18n.T("storage_table_path"), 48), i18n.T("dataset_manifest_problem"))
+		for _, problem := range problems {
+			fmt.Printf("%s %s\n", i18n.PadRight(emptyDash(rawString(problem, "path")), 48), rawString(problem, "problem"))
+		}
+	}
+	if files := rawList(manifest["files"]); len(files) > 0 {
+		fmt.Println()
+		fmt.Printf("%s %s %s %s\n",
+			i18n.PadRight(i18n.T("storage_table_path"), 48),
+			i18n.PadRight(i18n.T("storage_table_bytes"), 12),
+			i18n.PadRight(i18n.T("dataset_manifest_mtime"), 18),
+			"SHA256")
+		for _, entry := range files {
+			entrySize, _ := entry["size"].(float64)
+			fmt.Printf("%s %s %s %s\n",
+				i18n.PadRight(rawString(entry, "path"), 48),
+				i18n.PadRight(formatBytes(int64(entrySize)), 12),
+				i18n.PadRight(formatTrashTime(rawString(entry, "mtime")), 18),
+				rawString(entry, "sha256"))
+		}
+	}
+}
+
+func init() {
+	datasetManifestCmd.Flags().Bool("files", false, "Also list every file with its size, mtime and sha256")
+	datasetCmd.AddCommand(datasetManifestCmd, datasetVerifyCmd)
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/raids-lab/crater/cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+var datasetRequestCmd = &cobra.Command{
+	Use:   "request <id>",
+	Short: "Request read access to a dataset",
+	Long: "Submit a dataset access request for yourself or, with --account, for a queue you belong to. The request is " +
+		"routed to the dataset owner; once approved the dataset can be mounted until the optional expiry.",
+	Args: exactArgs(1, "id"),
+	RunE: runDatasetRequest,
+}
+
+func runDatasetRequest(cmd *cobra.Command, args []string) error {
+	id, err := requiredUintArg(args, "dataset_label_id", "id")
+	if err != nil {
+		return err
+	}
+	reason, _ := cmd.Flags().GetString("reason")
+	accountID, _ := cmd.Flags().GetUint("account")
+	reason = strings.TrimSpace(reason)
+	expiresAt, issues := collectExpiresFlag(cmd)
+	if reason == "" {
+		issues = append(issues, missingIssue("reason", "order_label_reason"))
+	}
+	if len(issues) > 0 {
+		return errUsageFromIssues(issues)
+	}
+	client, err := activeAPIClient()
+	if err != nil {
+		return err
+	}
+	// The server names dataset orders after the dataset.
+	message, err := client.CreateApprovalOrder(api.ApprovalOrderRequest{
+		Name:      fmt.Sprintf("dataset-%d", id),
+		Type:      "dataset",
+		TypeID:    id,
+		Reason:    reason,
+		AccountID: accountID,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return cliErrFromAPI(err)
+	}
+	return writeOrderMessage("order_submit_success", message)
+}
+
+func init() {
+	datasetRequestCmd.Flags().String("reason", "", "Why you need the dataset")
+	datasetRequestCmd.Flags().Uint("account", 0, "Request access for this queue (account ID) instead of yourself")
+	datasetRequestCmd.Flags().String("expires", "", "When the access should end (RFC3339 or YYYY-MM-DD); omit for permanent access")
+	datasetCmd.AddCommand(datasetRequestCmd)
+}
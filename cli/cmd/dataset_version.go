@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/raids-lab/crater/cli/internal/api"
+	"github.com/raids-lab/crater/cli/internal/i18n"
+	"github.com/raids-lab/crater/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var datasetVersionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "List, inspect and publish dataset versions",
+	Long: "A dataset version is a read-only snapshot of the dataset content with a manifest of paths, sizes and sha256 hashes. " +
+		"Jobs mount it with --dataset id@version:mountPath and keep seeing the same files after the dataset changes.",
+}
+var datasetVersionLsCmd = &cobra.Command{Use: "ls <id>", Short: "List the versions of a dataset", Args: exactArgs(1, "id"), RunE: runDatasetVersionLs}
+var datasetVersionGetCmd = &cobra.Command{Use: "get <id> <version>", Short: "Show a dataset version and its manifest", Args: exactArgs(2, "id", "version"), RunE: runDatasetVersionGet}
+var datasetVersionPublishCmd = &cobra.Command{Use: "publish <id> <version>", Short: "Publish the current content of a dataset as a version", Args: exactArgs(2, "id", "version"), RunE: runDatasetVersionPublish}
+
+func runDatasetVersionLs(cmd *cobra.Command, args []string) error {
+	id, err := requiredUintArg(args, "dataset_label_id", "id")
+	if err != nil {
+		return err
+	}
+	return runRawRead(cmd, rawReadSpec{PayloadKey: "versions", Path: fmt.Sprintf("%s/%s/versions", api.DatasetPrefix, api.UintPath(id)), Params: noParams, Table: printDatasetVersionTable})
+}
+
+func runDatasetVersionGet(cmd *cobra.Command, args []string) error {
+	id, err := requiredUintArg(args, "dataset_label_id", "id")
+	if err != nil {
+		return err
+	}
+	path := fmt.Sprintf("%s/%s/versions/%s", api.DatasetPrefix, api.UintPath(id), url.PathEscape(args[1]))
+	return runRawRead(cmd, rawReadSpec{PayloadKey: "version", Path: path, Params: noParams, Table: printDatasetVersionDetail})
+}
+
+func runDatasetVersionPublish(cmd *cobra.Command, args []string) error {
+	id, err := requiredUintArg(args, "dataset_label_id", "id")
+	if err != nil {
+		return err
+	}
+	version := strings.TrimSpace(args[1])
+	if version == "" {
+		return errUsageFromIssues([]usageIssue{invalidIssue("version", i18n.T("err_value_empty", "version"))})
+	}
+	hardlink, _ := cmd.Flags().GetBool("hardlink")
+	client, err := activeAPIClient()
+	if err != nil {
+		return err
+	}
+	data, err := client.PublishDatasetVersion(id, api.PublishDatasetVersionRequest{
+		Version:  version,
+		Describe: getStringParam(cmd, "describe"),
+		Hardlink: hardlink,
+	})
+	if err != nil {
+		return cliErrFromAPI(err)
+	}
+	if outputJSON {
+		return output.WriteSuccessJSON(os.Stdout, output.SuccessEnvelope(map[string]interface{}{"version": data}))
+	}
+	fmt.Println(i18n.T("dataset_version_published", version, id, id, version))
+	return nil
+}
+
+func printDatasetVersionTable(data interface{}) {
+	fmt.Printf("%s %s %s %s %s %s\n",
+		i18n.PadRight(i18n.T("dataset_version_table_version"), 20),
+		i18n.PadRight(i18n.T("table_status"), 10),
+		i18n.PadRight(i18n.T("dataset_version_table_files"), 10),
+		i18n.PadRight(i18n.T("storage_table_bytes"), 12),
+		i18n.PadRight(i18n.T("dataset_version_table_published"), 18),
+		i18n.T("table_owner"))
+	for _, row := range rawList(data) {
+		size, _ := row["sizeBytes"].(float64)
+		fmt.Printf("%s %s %s %s %s %s\n",
+			i18n.PadRight(rawString(row, "version"), 20),
+			i18n.PadRight(rawString(row, "status"), 10),
+			i18n.PadRight(rawString(row, "fileCount"), 10),
+			i18n.PadRight(formatBytes(int64(size)), 12),
+			i18n.PadRight(formatTrashTime(rawString(row, "createdAt")), 18),
+			emptyDash(rawNestedString(row, "userInfo", "nickname")))
+	}
+}
+
+func printDatasetVersionDetail(data interface{}) {
+	version := rawMap(data)
+	files, _ := version["fileCount"].(float64)
+	size, _ := version["sizeBytes"].(float64)
+	fmt.Printf("%s: %s\n", i18n.T("dataset_version_table_version"), rawString(version, "version"))
+	fmt.Printf("%s: %s\n", i18n.T("table_status"), rawString(version, "status"))
+	if message := rawString(version, "message"); message != "" {
+		fmt.Printf("%s: %s\n", i18n.T("dataset_version_message"), message)
+	}
+	fmt.Printf("%s: %d (%s)\n", i18n.T("dataset_version_table_files"), int64(files), formatBytes(int64(size)))
+	if describe := rawString(version, "describe"); describe != "" {
+		fmt.Printf("%s: %s\n", i18n.T("dataset_version_describe"), describe)
+	}
+	manifest, _ := version["manifest"].([]interface{})
+	if len(manifest) == 0 {
+		return
+	}
+	fmt.Println()
+	fmt.Printf("%s %s %s\n",
+		i18n.PadRight(i18n.T("storage_table_path"), 48),
+		i18n.PadRight(i18n.T("storage_table_bytes"), 12),
+		"SHA256")
+	for _, raw := range manifest {
+		entry, _ := raw.(map[string]interface{})
+		entrySize, _ := entry["size"].(float64)
+		fmt.Printf("%s %s %s\n",
+			i18n.PadRight(rawString(entry, "path"), 48),
+			i18n.PadRight(formatBytes(int64(entrySize)), 12),
+			rawString(entry, "sha256"))
+	}
+}
+
+func init() {
+	datasetVersionPublishCmd.Flags().String("describe", "", "Description of the version")
+	datasetVersionPublishCmd.Flags().Bool("hardlink", false, "Hardlink the files instead of copying them; saves space, but files modified in place change in the version too")
+	datasetVersionCmd.AddCommand(datasetVersionLsCmd, datasetVersionGetCmd, datasetVersionPublishCmd)
+	datasetCmd.AddCommand(datasetVersionCmd)
+}
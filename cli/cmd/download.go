@@ -46,6 +46,7 @@ type downloadCreateInput struct {
 	source   string
 	revision string
 	token    string
+	remote   remoteDownloadInput
 }
 
 var downloadCmd = &cobra.Command{
@@ -61,12 +62,14 @@ var downloadCmd = &cobra.Command{
 }
 
 var downloadCreateCmd = &cobra.Command{
-	Use:   "create",
+	Use:   "create [model|dataset]",
 	Short: "Create a model or dataset download task",
-	Long:  "Submit a platform-side download task for a ModelScope or HuggingFace model or dataset.",
+	Long: "Submit a platform-side download task for a ModelScope or HuggingFace model or dataset, or import one " +
+		"from an s3:// bucket prefix or an http(s):// URL passed as --source. The category may be given as an " +
+		"argument or with --category.",
 	Args: func(cmd *cobra.Command, args []string) error {
-		if len(args) > 0 {
-			return errTooManyArgs(cmd, len(args), 0)
+		if len(args) > 1 {
+			return errTooManyArgs(cmd, len(args), 1)
 		}
 		return nil
 	},
@@ -197,6 +200,7 @@ func readDownloadCreateInput(cmd *cobra.Command) downloadCreateInput {
 		source:   strings.TrimSpace(source),
 		revision: strings.TrimSpace(revision),
 		token:    strings.TrimSpace(token),
+		remote:   readRemoteDownloadInput(cmd),
 	}
 }
 
@@ -221,13 +225,14 @@ func normalizeDownloadSource(v string) string {
 
 func collectDownloadCreateUsageIssues(in downloadCreateInput) []usageIssue {
 	var issues []usageIssue
-	if in.name == "" {
+	// The platform names s3 and http downloads after their URL when --name is omitted.
+	if in.name == "" && remoteDownloadSource(in.source) == "" {
 		issues = append(issues, usageIssue{
 			Code:    errorcodes.ErrMissingRequiredFlag,
 			Message: i18n.T("err_missing_flag_non_interactive", i18n.T("download_label_name"), "name"),
 			Field:   "name",
 		})
-	} else if !modelNamePattern.MatchString(in.name) {
+	} else if in.name != "" && !modelNamePattern.MatchString(in.name) {
 		issues = append(issues, usageIssue{
 			Code:    errorcodes.ErrInvalidFlagValue,
 			Message: i18n.T("err_invalid_download_name", in.name),
@@ -253,7 +258,7 @@ func collectDownloadCreateUsageIssues(in downloadCreateInput) []usageIssue {
 			Message: i18n.T("err_missing_flag_non_interactive", i18n.T("download_label_source"), "source"),
 			Field:   "source",
 		})
-	} else if !downloadSourceValid(in.source) {
+	} else if !downloadSourceValid(in.source) && remoteDownloadSource(in.source) == "" {
 		issues = append(issues, usageIssue{
 			Code:    errorcodes.ErrInvalidFlagValue,
 			Message: i18n.T("err_invalid_download_source", in.source),
@@ -362,10 +367,19 @@ func activeModelDownloadClient() (api.ModelDownloadClient, error) {
 	return api.NewModelDownloadClient(active.PlatformURL, token), nil
 }
 
-func runDownloadCreate(cmd *cobra.Command, _ []string) error {
+func runDownloadCreate(cmd *cobra.Command, args []string) error {
 	in := readDownloadCreateInput(cmd)
-	issues := collectDownloadCreateUsageIssues(in)
+	var issues []usageIssue
+	if len(args) > 0 {
+		category := strings.TrimSpace(args[0])
+		if in.category != "" && in.category != category {
+			issues = append(issues, invalidIssue("category", i18n.T("err_download_category_conflict", category, in.category)))
+		}
+		in.category = category
+	}
+	issues = append(issues, collectDownloadCreateUsageIssues(in)...)
 	issues = append(issues, collectTokenUsageIssues(cmd)...)
+	issues = append(issues, collectRemoteDownloadUsageIssues(cmd, in)...)
 	if len(issues) > 0 {
 		return errUsageFromIssues(issues)
 	}
@@ -374,6 +388,9 @@ func runDownloadCreate(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 	in.token = token
+	if in.remote.secretKey, err = resolveDownloadSecretKey(cmd, in.remote.secretKey); err != nil {
+		return err
+	}
 	return submitDownload(cmd, in)
 }
 
@@ -391,9 +408,11 @@ func runDownloadShortcut(cmd *cobra.Command, args []string, category string) err
 		source:   strings.TrimSpace(source),
 		revision: strings.TrimSpace(revision),
 		token:    strings.TrimSpace(token),
+		remote:   readRemoteDownloadInput(cmd),
 	}
 	issues := collectDownloadShortcutUsageIssues(in)
 	issues = append(issues, collectTokenUsageIssues(cmd)...)
+	issues = append(issues, collectRemoteDownloadUsageIssues(cmd, in)...)
 	if len(issues) > 0 {
 		return errUsageFromIssues(issues)
 	}
@@ -402,6 +421,9 @@ func runDownloadShortcut(cmd *cobra.Command, args []string, category string) err
 		return err
 	}
 	in.token = resolvedToken
+	if in.remote.secretKey, err = resolveDownloadSecretKey(cmd, in.remote.secretKey); err != nil {
+		return err
+	}
 	return submitDownload(cmd, in)
 }
 
@@ -411,13 +433,15 @@ func submitDownload(cmd *cobra.Command, in downloadCreateInput) error {
 		return err
 	}
 
-	download, msg, err := client.CreateDownload(api.CreateModelDownloadReq{
+	req := api.CreateModelDownloadReq{
 		Name:     in.name,
 		Revision: in.revision,
 		Source:   normalizeDownloadSource(in.source),
 		Category: in.category,
 		Token:    in.token,
-	})
+	}
+	applyRemoteDownloadRequest(&req, in)
+	download, msg, err := client.CreateDownload(req)
 	if err != nil {
 		return cliErrFromAPI(err)
 	}
@@ -635,13 +659,13 @@ func parseDownloadID(args []string) (uint, error) {
 
 func collectDownloadShortcutUsageIssues(in downloadCreateInput) []usageIssue {
 	var issues []usageIssue
-	if in.name == "" {
+	if in.name == "" && remoteDownloadSource(in.source) == "" {
 		issues = append(issues, usageIssue{
 			Code:    errorcodes.ErrMissingRequiredFlag,
 			Message: i18n.T("err_missing_download_name_arg"),
 			Field:   "name",
 		})
-	} else if !modelNamePattern.MatchString(in.name) {
+	} else if in.name != "" && !modelNamePattern.MatchString(in.name) {
 		issues = append(issues, usageIssue{
 			Code:    errorcodes.ErrInvalidFlagValue,
 			Message: i18n.T("err_invalid_download_name", in.name),
@@ -654,7 +678,7 @@ func collectDownloadShortcutUsageIssues(in downloadCreateInput) []usageIssue {
 			Message: i18n.T("err_missing_flag_non_interactive", i18n.T("download_label_source"), "source"),
 			Field:   "source",
 		})
-	} else if !downloadSourceValid(in.source) {
+	} else if !downloadSourceValid(in.source) && remoteDownloadSource(in.source) == "" {
 		issues = append(issues, usageIssue{
 			Code:    errorcodes.ErrInvalidFlagValue,
 			Message: i18n.T("err_invalid_download_source", in.source),
@@ -816,11 +840,12 @@ func addDownloadCreateFlags(cmd *cobra.Command, includeCategory bool) {
 	if includeCategory {
 		cmd.Flags().String("name", "", "Resource name in owner/name format")
 	}
-	cmd.Flags().String("source", downloadSourceDefault, "Download source (modelscope | ms | huggingface | hf)")
+	cmd.Flags().String("source", downloadSourceDefault, "Download source (modelscope | ms | huggingface | hf), or an s3:// or http(s):// URL")
 	cmd.Flags().String("revision", "", "Optional branch, tag, or revision")
 	cmd.Flags().String("token", "", "Optional access token for gated or private repositories")
 	cmd.Flags().String("token-env", "", "Read the optional repository access token from an environment variable")
 	cmd.Flags().Bool("token-stdin", false, "Read the optional repository access token from stdin")
+	addRemoteDownloadFlags(cmd)
 	cmd.Flags().Bool("wait", false, "Wait until the download reaches Ready, Failed, or Paused")
 	cmd.Flags().Duration("poll-interval", 5*time.Second, "Polling interval for --wait")
 	cmd.Flags().Duration("timeout", 0, "Maximum time to wait; 0 means no timeout")
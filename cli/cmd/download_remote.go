@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/raids-lab/crater/cli/internal/api"
+	"github.com/raids-lab/crater/cli/internal/i18n"
+	"github.com/raids-lab/crater/cli/pkg/errorcodes"
+	"github.com/spf13/cobra"
+)
+
+const (
+	downloadSourceS3   = "s3"
+	downloadSourceHTTP = "http"
+)
+
+var (
+	downloadS3Flags       = []string{"endpoint", "region", "access-key", "secret-key", "secret-key-env"}
+	downloadHTTPFlags     = []string{"extract", "checksum"}
+	downloadChecksumRegex = regexp.MustCompile(`^(?i:sha256:)?[0-9A-Fa-f]{64}$`)
+)
+
+// remoteDownloadInput holds the flags of s3:// and http(s):// sources.
+type remoteDownloadInput struct {
+	endpoint  string
+	region    string
+	accessKey string
+	secretKey string
+	extract   bool
+	checksum  string
+}
+
+// remoteDownloadSource maps an s3:// or http(s):// --source to the platform source name,
+// and returns "" for hub sources.
+func remoteDownloadSource(source string) string {
+	lower := strings.ToLower(source)
+	switch {
+	case strings.HasPrefix(lower, "s3://"):
+		return downloadSourceS3
+	case strings.HasPrefix(lower, "http://"), strings.HasPrefix(lower, "https://"):
+		return downloadSourceHTTP
+	default:
+		return ""
+	}
+}
+
+func readRemoteDownloadInput(cmd *cobra.Command) remoteDownloadInput {
+	endpoint, _ := cmd.Flags().GetString("endpoint")
+	region, _ := cmd.Flags().GetString("region")
+	accessKey, _ := cmd.Flags().GetString("access-key")
+	secretKey, _ := cmd.Flags().GetString("secret-key")
+	extract, _ := cmd.Flags().GetBool("extract")
+	checksum, _ := cmd.Flags().GetString("checksum")
+	return remoteDownloadInput{
+		endpoint:  strings.TrimSpace(endpoint),
+		region:    strings.TrimSpace(region),
+		accessKey: strings.TrimSpace(accessKey),
+		secretKey: strings.TrimSpace(secretKey),
+		extract:   extract,
+		checksum:  strings.TrimSpace(checksum),
+	}
+}
+
+// collectRemoteDownloadUsageIssues rejects flags that do not apply to the chosen source.
+func collectRemoteDownloadUsageIssues(cmd *cobra.Command, in downloadCreateInput) []usageIssue {
+	var issues []usageIssue
+	unsupported := func(flags []string, source string) {
+		for _, name := range flags {
+			if flagChanged(cmd, name) {
+				issues = append(issues, invalidIssue(name, i18n.T("err_download_flag_source", name, source)))
+			}
+		}
+	}
+	remote := remoteDownloadSource(in.source)
+	switch remote {
+	case downloadSourceS3:
+		unsupported(append([]string{"token", "token-env", "token-stdin"}, downloadHTTPFlags...), remote)
+		hasSecret := in.remote.secretKey != "" || flagChanged(cmd, "secret-key-env")
+		if (in.remote.accessKey != "") != hasSecret {
+			issues = append(issues, invalidIssue("access-key", i18n.T("err_download_s3_keys_pair")))
+		}
+	case downloadSourceHTTP:
+		unsupported(downloadS3Flags, remote)
+		if in.remote.checksum != "" && !downloadChecksumRegex.MatchString(in.remote.checksum) {
+			issues = append(issues, invalidIssue("checksum", i18n.T("err_download_checksum", in.remote.checksum)))
+		}
+	default:
+		unsupported(append(append([]string{}, downloadS3Flags...), downloadHTTPFlags...), normalizeDownloadSource(in.source))
+	}
+	if remote != "" && in.revision != "" {
+		issues = append(issues, invalidIssue("revision", i18n.T("err_download_flag_source", "revision", remote)))
+	}
+	if flagChanged(cmd, "secret-key") && flagChanged(cmd, "secret-key-env") {
+		issues = append(issues, invalidIssue("secret-key", i18n.T("err_download_secret_key_source_conflict")))
+	}
+	return issues
+}
+
+// resolveDownloadSecretKey reads --secret-key-env so the secret stays out of shell history.
+func resolveDownloadSecretKey(cmd *cobra.Command, current string) (string, error) {
+	if !flagChanged(cmd, "secret-key-env") {
+		return current, nil
+	}
+	envName, _ := cmd.Flags().GetString("secret-key-env")
+	envName = strings.TrimSpace(envName)
+	if envName == "" {
+		return "", errUsageFromIssues([]usageIssue{{
+			Code:    errorcodes.ErrMissingRequiredFlag,
+			Message: i18n.T("err_missing_required", i18n.T("download_label_secret_key_env"), "secret-key-env"),
+			Field:   "secret-key-env",
+		}})
+	}
+	v, ok := os.LookupEnv(envName)
+	if !ok || strings.TrimSpace(v) == "" {
+		return "", errUsageFromIssues([]usageIssue{{
+			Code:    errorcodes.ErrMissingRequiredFlag,
+			Message: i18n.T("err_download_secret_key_env_missing", envName),
+			Field:   "secret-key-env",
+		}})
+	}
+	return strings.TrimSpace(v), nil
+}
+
+func applyRemoteDownloadRequest(req *api.CreateModelDownloadReq, in downloadCreateInput) {
+	remote := remoteDownloadSource(in.source)
+	if remote == "" {
+		return
+	}
+	req.Source = remote
+	req.URL = in.source
+	req.Endpoint = in.remote.endpoint
+	req.Region = in.remote.region
+	req.AccessKey = in.remote.accessKey
+	req.SecretKey = in.remote.secretKey
+	req.Extract = in.remote.extract
+	req.Checksum = in.remote.checksum
+}
+
+func addRemoteDownloadFlags(cmd *cobra.Command) {
+	cmd.Flags().String("endpoint", "", "S3-compatible service URL for s3:// sources, such as a MinIO endpoint")
+	cmd.Flags().String("region", "", "Bucket region for s3:// sources")
+	cmd.Flags().String("access-key", "", "Access key ID for s3:// sources; stored encrypted until the download is ready")
+	cmd.Flags().String("secret-key", "", "Secret access key for s3:// sources")
+	cmd.Flags().String("secret-key-env", "", "Read the secret access key for s3:// sources from an environment variable")
+	cmd.Flags().Bool("extract", false, "Extract the zip or tar archive fetched from an http(s):// source")
+	cmd.Flags().String("checksum", "", "Expected sha256 of the file fetched from an http(s):// source")
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/raids-lab/crater/cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+func newRemoteDownloadTestCmd(t *testing.T, args ...string) *cobra.Command {
+	t.Helper()
+	cmd := &cobra.Command{Use: "create"}
+	addDownloadCreateFlags(cmd, true)
+	if err := cmd.ParseFlags(args); err != nil {
+		t.Fatalf("parse flags: %v", err)
+	}
+	return cmd
+}
+
+func remoteIssueFields(issues []usageIssue) string {
+	fields := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		fields = append(fields, issue.Field)
+	}
+	return strings.Join(fields, ",")
+}
+
+func TestRemoteDownloadSource(t *testing.T) {
+	for source, want := range map[string]string{
+		"s3://bucket/prefix":         downloadSourceS3,
+		"HTTPS://host/file.tar.gz":   downloadSourceHTTP,
+		"http://host/file":           downloadSourceHTTP,
+		downloadSourceHuggingFace:    "",
+		downloadSourceAliasMS:        "",
+		"ftp://host/file.tar.gz":     "",
+		"s3-bucket-without-a-scheme": "",
+	} {
+		if got := remoteDownloadSource(source); got != want {
+			t.Fatalf("remoteDownloadSource(%q) = %q, want %q", source, got, want)
+		}
+	}
+}
+
+func TestCollectRemoteDownloadUsageIssues(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		args []string
+		want string
+	}{
+		{name: "s3 with keys", args: []string{"--source", "s3://b/p", "--access-key", "a", "--secret-key", "s"}},
+		{name: "s3 without keys", args: []string{"--source", "s3://b/p", "--endpoint", "http://minio:9000"}},
+		{name: "s3 half keys", args: []string{"--source", "s3://b/p", "--access-key", "a"}, want: "access-key"},
+		{name: "s3 with extract", args: []string{"--source", "s3://b/p", "--extract"}, want: "extract"},
+		{name: "s3 with token", args: []string{"--source", "s3://b/p", "--token", "t"}, want: "token"},
+		{name: "http with endpoint", args: []string{"--source", "https://h/f.zip", "--endpoint", "x"}, want: "endpoint"},
+		{name: "http bad checksum", args: []string{"--source", "https://h/f.zip", "--checksum", "md5:1"}, want: "checksum"},
+		{name: "http revision", args: []string{"--source", "https://h/f.zip", "--revision", "main"}, want: "revision"},
+		{name: "hub with extract", args: []string{"--source", "hf", "--extract"}, want: "extract"},
+	} {
+		cmd := newRemoteDownloadTestCmd(t, tc.args...)
+		in := readDownloadCreateInput(cmd)
+		if got := remoteIssueFields(collectRemoteDownloadUsageIssues(cmd, in)); got != tc.want {
+			t.Fatalf("%s: issue fields = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestRemoteDownloadAllowsMissingName(t *testing.T) {
+	cmd := newRemoteDownloadTestCmd(t, "--category", "dataset", "--source", "s3://team-data/corpora/")
+	if issues := collectDownloadCreateUsageIssues(readDownloadCreateInput(cmd)); len(issues) != 0 {
+		t.Fatalf("s3 download without --name should be valid, got %#v", issues)
+	}
+	cmd = newRemoteDownloadTestCmd(t, "--category", "dataset", "--source", "hf")
+	if got := remoteIssueFields(collectDownloadCreateUsageIssues(readDownloadCreateInput(cmd))); got != "name" {
+		t.Fatalf("hub download without --name should require it, got %q", got)
+	}
+}
+
+func TestApplyRemoteDownloadRequest(t *testing.T) {
+	cmd := newRemoteDownloadTestCmd(t,
+		"--category", "dataset", "--source", "https://files.example.com/imagenet.tar.gz",
+		"--extract", "--checksum", "sha256:"+strings.Repeat("a", 64))
+	in := readDownloadCreateInput(cmd)
+	req := api.CreateModelDownloadReq{Source: normalizeDownloadSource(in.source), Category: in.category}
+	applyRemoteDownloadRequest(&req, in)
+	if req.Source != downloadSourceHTTP || req.URL != "https://files.example.com/imagenet.tar.gz" ||
+		!req.Extract || req.Checksum == "" {
+		t.Fatalf("unexpected request: %#v", req)
+	}
+
+	cmd = newRemoteDownloadTestCmd(t, "--category", "model", "--source", "hf")
+	in = readDownloadCreateInput(cmd)
+	req = api.CreateModelDownloadReq{Source: normalizeDownloadSource(in.source)}
+	applyRemoteDownloadRequest(&req, in)
+	if req.Source != downloadSourceHuggingFace || req.URL != "" {
+		t.Fatalf("hub request should be unchanged: %#v", req)
+	}
+}
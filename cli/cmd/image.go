@@ -40,6 +40,7 @@ var imageBuildCmd = imageCommandGroup("build", "Manage image builds")
 var imageBuildLsCmd = &cobra.Command{Use: "ls", Short: "List image build records", Args: noArgs, RunE: runImageBuildLs}
 var imageBuildPipAptCmd = &cobra.Command{Use: "pip-apt", Short: "Build an image from base image plus pip/apt packages", Args: noArgs, RunE: runImageBuildPipApt}
 var imageBuildDockerfileCmd = &cobra.Command{Use: "dockerfile", Short: "Build an image from Dockerfile", Args: noArgs, RunE: runImageBuildDockerfile}
+var imageBuildGitCmd = &cobra.Command{Use: "git", Short: "Build an image from a Dockerfile in a Git repository", Args: noArgs, RunE: runImageBuildGit}
 var imageBuildEnvdCmd = &cobra.Command{Use: "envd", Short: "Build an image from envd", Args: noArgs, RunE: runImageBuildEnvd}
 var imageBuildRemoveCmd = &cobra.Command{Use: "remove", Short: "Cancel or remove image build records", Args: noArgs, RunE: runImageBuildRemove}
 var imageBuildGetCmd = &cobra.Command{Use: "get <name>", Short: "Get an image build record", Args: exactArgs(1, "name"), RunE: runImageBuildGet}
@@ -163,6 +164,19 @@ func runImageBuildDockerfile(cmd *cobra.Command, _ []string) error {
 	return writeImageMessage(msg, err)
 }
 
+func runImageBuildGit(cmd *cobra.Command, _ []string) error {
+	req, err := collectGitBuild(cmd)
+	if err != nil {
+		return err
+	}
+	client, err := activeAPIClient()
+	if err != nil {
+		return err
+	}
+	msg, err := client.CreateGit(req)
+	return writeImageMessage(msg, err)
+}
+
 func runImageBuildEnvd(cmd *cobra.Command, _ []string) error {
 	req, err := collectEnvdBuild(cmd)
 	if err != nil {
@@ -563,11 +577,15 @@ func runImageShareAdd(cmd *cobra.Command, args []string) error {
 	if err := validateEnum("share-type", shareType, imageShareTypes); err != nil {
 		return err
 	}
+	expiresAt, issues := collectExpiresFlag(cmd)
+	if len(issues) > 0 {
+		return errUsageFromIssues(issues)
+	}
 	client, err := activeAPIClient()
 	if err != nil {
 		return err
 	}
-	msg, err := client.ShareImage(api.ImageShareRequest{ImageID: imageID, IDList: targets, Type: shareType})
+	msg, err := client.ShareImage(api.ImageShareRequest{ImageID: imageID, IDList: targets, Type: shareType, ExpiresAt: expiresAt})
 	return writeImageMessage(msg, err)
 }
 
@@ -815,7 +833,8 @@ func collectPipAptBuild(cmd *cobra.Command) (api.PipAptBuildRequest, error) {
 	}
 	requirements, _ := cmd.Flags().GetString("requirements")
 	packages, _ := cmd.Flags().GetString("packages")
-	return api.PipAptBuildRequest{Image: image, Requirements: requirements, Packages: packages, Description: description, Name: name, Tag: tag, Tags: tags, Template: template, Archs: archs}, nil
+	noCache, _ := cmd.Flags().GetBool("no-cache")
+	return api.PipAptBuildRequest{Image: image, Requirements: requirements, Packages: packages, Description: description, Name: name, Tag: tag, Tags: tags, Template: template, Archs: archs, NoCache: noCache}, nil
 }
 
 func collectDockerfileBuild(cmd *cobra.Command) (api.DockerfileBuildRequest, error) {
@@ -827,7 +846,37 @@ func collectDockerfileBuild(cmd *cobra.Command) (api.DockerfileBuildRequest, err
 	if err != nil {
 		return api.DockerfileBuildRequest{}, err
 	}
-	return api.DockerfileBuildRequest{Dockerfile: dockerfile, Description: description, Name: name, Tag: tag, Tags: tags, Template: template, Archs: archs}, nil
+	noCache, _ := cmd.Flags().GetBool("no-cache")
+	return api.DockerfileBuildRequest{Dockerfile: dockerfile, Description: description, Name: name, Tag: tag, Tags: tags, Template: template, Archs: archs, NoCache: noCache}, nil
+}
+
+func collectGitBuild(cmd *cobra.Command) (api.GitBuildRequest, error) {
+	name, tag, description, tags, archs, _, err := commonBuildFlags(cmd)
+	if err != nil {
+		return api.GitBuildRequest{}, err
+	}
+	repo, _ := cmd.Flags().GetString("repo")
+	if strings.TrimSpace(repo) == "" {
+		return api.GitBuildRequest{}, errUsageFromIssues([]usageIssue{missingIssue("repo", "image_flag_repo")})
+	}
+	ref, _ := cmd.Flags().GetString("ref")
+	subdir, _ := cmd.Flags().GetString("subdir")
+	dockerfile, _ := cmd.Flags().GetString("dockerfile")
+	credentialSecret, _ := cmd.Flags().GetString("credential-secret")
+	noCache, _ := cmd.Flags().GetBool("no-cache")
+	return api.GitBuildRequest{
+		Description:      description,
+		Repo:             strings.TrimSpace(repo),
+		Ref:              ref,
+		Subdir:           subdir,
+		Dockerfile:       dockerfile,
+		CredentialSecret: credentialSecret,
+		Name:             name,
+		Tag:              tag,
+		Tags:             tags,
+		Archs:            archs,
+		NoCache:          noCache,
+	}, nil
 }
 
 func collectEnvdBuild(cmd *cobra.Command) (api.EnvdBuildRequest, error) {
@@ -868,6 +917,11 @@ func commonBuildFlags(cmd *cobra.Command) (string, string, string, []string, []s
 	if strings.TrimSpace(tag) == "" {
 		issues = append(issues, missingIssue("tag", "image_flag_tag"))
 	}
+	for _, arch := range archs {
+		if !slices.Contains(imageArchitectures, arch) {
+			issues = append(issues, invalidIssue("archs", i18n.T("err_invalid_image_arch", arch)))
+		}
+	}
 	if len(issues) > 0 {
 		return "", "", "", nil, nil, "", errUsageFromIssues(issues)
 	}
@@ -1032,16 +1086,16 @@ func printGrantTable(resp *api.ImageGrantResponse) {
 }
 
 func printUserGrantTable(items []api.ImageGrantedUser) {
-	fmt.Printf("%s %s %s\n", i18n.PadRight(i18n.T("table_id"), 8), i18n.PadRight(i18n.T("table_username"), 24), i18n.PadRight(i18n.T("table_nickname"), 24))
+	fmt.Printf("%s %s %s %s\n", i18n.PadRight(i18n.T("table_id"), 8), i18n.PadRight(i18n.T("table_username"), 24), i18n.PadRight(i18n.T("table_nickname"), 24), i18n.T("share_table_expires"))
 	for _, item := range items {
-		fmt.Printf("%s %s %s\n", i18n.PadRight(strconv.FormatUint(uint64(item.ID), 10), 8), i18n.PadRight(item.Name, 24), i18n.PadRight(item.Nickname, 24))
+		fmt.Printf("%s %s %s %s\n", i18n.PadRight(strconv.FormatUint(uint64(item.ID), 10), 8), i18n.PadRight(item.Name, 24), i18n.PadRight(item.Nickname, 24), formatShareExpiry(item.ExpiresAt))
 	}
 }
 
 func printAccountGrantTable(items []api.ImageGrantedAccount) {
-	fmt.Printf("%s %s\n", i18n.PadRight(i18n.T("table_id"), 8), i18n.PadRight(i18n.T("table_name"), 24))
+	fmt.Printf("%s %s %s\n", i18n.PadRight(i18n.T("table_id"), 8), i18n.PadRight(i18n.T("table_name"), 24), i18n.T("share_table_expires"))
 	for _, item := range items {
-		fmt.Printf("%s %s\n", i18n.PadRight(strconv.FormatUint(uint64(item.ID), 10), 8), i18n.PadRight(item.Name, 24))
+		fmt.Printf("%s %s %s\n", i18n.PadRight(strconv.FormatUint(uint64(item.ID), 10), 8), i18n.PadRight(item.Name, 24), formatShareExpiry(item.ExpiresAt))
 	}
 }
 
@@ -1064,9 +1118,12 @@ func init() {
 	imageBuildPipAptCmd.Flags().String("image", "", "Base image")
 	imageBuildPipAptCmd.Flags().String("packages", "", "APT packages")
 	imageBuildPipAptCmd.Flags().String("requirements", "", "Python requirements")
+	imageBuildPipAptCmd.Flags().Bool("no-cache", false, "Ignore cached layers; the build cache is still refreshed")
 	addCommonBuildFlags(imageBuildDockerfileCmd)
 	imageBuildDockerfileCmd.Flags().String("dockerfile", "", "Dockerfile content")
 	imageBuildDockerfileCmd.Flags().String("file", "", "Read Dockerfile from file")
+	imageBuildDockerfileCmd.Flags().Bool("no-cache", false, "Ignore cached layers; the build cache is still refreshed")
+	addGitBuildFlags(imageBuildGitCmd)
 	addCommonBuildFlags(imageBuildEnvdCmd)
 	imageBuildEnvdCmd.Flags().String("envd", "", "envd content")
 	imageBuildEnvdCmd.Flags().String("file", "", "Read envd from file")
@@ -1074,7 +1131,7 @@ func init() {
 	imageBuildEnvdCmd.Flags().String("base", "", "envd base image")
 	imageBuildEnvdCmd.Flags().String("build-source", "EnvdAdvanced", "envd build source")
 	imageBuildRemoveCmd.Flags().String("ids", "", "Comma-separated IDs")
-	imageBuildCmd.AddCommand(imageBuildLsCmd, imageBuildPipAptCmd, imageBuildDockerfileCmd, imageBuildEnvdCmd, imageBuildRemoveCmd, imageBuildGetCmd, imageBuildTemplateCmd, imageBuildPodCmd)
+	imageBuildCmd.AddCommand(imageBuildLsCmd, imageBuildPipAptCmd, imageBuildDockerfileCmd, imageBuildGitCmd, imageBuildEnvdCmd, imageBuildRemoveCmd, imageBuildGetCmd, imageBuildTemplateCmd, imageBuildPodCmd)
 
 	imageLsCmd.Flags().Bool("available", false, "List images available for creating jobs")
 	imageLsCmd.Flags().String("type", "", "Filter by job type")
@@ -1100,6 +1157,7 @@ func init() {
 
 	imageShareAddCmd.Flags().String("ids", "", "Comma-separated target IDs")
 	imageShareAddCmd.Flags().String("share-type", "user", "Share type")
+	imageShareAddCmd.Flags().String("expires", "", "When the share ends (RFC3339 or YYYY-MM-DD); omit for a permanent share")
 	imageShareRemoveCmd.Flags().Uint("target-id", 0, "Share target ID")
 	imageShareRemoveCmd.Flags().String("share-type", "user", "Share type")
 	imageShareUsersCmd.Flags().String("name", "", "Filter by username or nickname")
@@ -1125,6 +1183,9 @@ func init() {
 	completion.RegisterFlagValue([]string{"image", "ls"}, "type", staticValueCompleter(imageTaskFilterTypes, nil))
 	completion.RegisterFlagValue([]string{"image", "ls"}, "arch", staticValueCompleter(imageArchitectures, nil))
 	completion.RegisterFlagValue([]string{"image", "arch"}, "archs", staticValueCompleter(imageArchitectures, nil))
+	for _, build := range []string{"pip-apt", "dockerfile", "git"} {
+		completion.RegisterFlagValue([]string{"image", "build", build}, "archs", staticValueCompleter(imageArchitectures, nil))
+	}
 	completion.RegisterFlagValue([]string{"admin", "image", "arch"}, "archs", staticValueCompleter(imageArchitectures, nil))
 	completion.RegisterFlagValue([]string{"image", "ls"}, "visibility", staticValueCompleter(imageVisibilityTypes, nil))
 	completion.RegisterFlagValue([]string{"image", "upload"}, "type", staticValueCompleter(imageTaskWriteTypes, nil))
@@ -1141,6 +1202,22 @@ func init() {
 	adminCmd.AddCommand(adminImageCmd)
 }
 
+// addGitBuildFlags has no --template: the server records the repository and the cloned commit
+// as the template of git builds.
+func addGitBuildFlags(cmd *cobra.Command) {
+	cmd.Flags().String("name", "", "Image name")
+	cmd.Flags().String("tag", "", "Image tag")
+	cmd.Flags().String("description", "", "Description")
+	cmd.Flags().String("tags", "", "Comma-separated tags")
+	cmd.Flags().String("archs", "", "Comma-separated architectures")
+	cmd.Flags().String("repo", "", "Git repository URL (http or https)")
+	cmd.Flags().String("ref", "", "Branch, tag or commit; defaults to the default branch")
+	cmd.Flags().String("subdir", "", "Build context directory inside the repository")
+	cmd.Flags().String("dockerfile", "", "Dockerfile path relative to --subdir (default Dockerfile)")
+	cmd.Flags().String("credential-secret", "", "Git credential saved with image git-credential set, for private repositories")
+	cmd.Flags().Bool("no-cache", false, "Ignore cached layers; the build cache is still refreshed")
+}
+
 func addCommonBuildFlags(cmd *cobra.Command) {
 	cmd.Flags().String("name", "", "Image name")
 	cmd.Flags().String("tag", "", "Image tag")
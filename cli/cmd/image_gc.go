@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/raids-lab/crater/cli/internal/api"
+	"github.com/raids-lab/crater/cli/internal/i18n"
+	"github.com/raids-lab/crater/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var adminImageGCReportCmd = &cobra.Command{Use: "gc-report", Short: "Show the images the clean-stale-images cron job would delete", Args: noArgs, RunE: runAdminImageGCReport}
+
+// imageGCPolicyFlags maps the policy override flags to the query parameters of the report.
+var imageGCPolicyFlags = []struct{ flag, param string }{
+	{"keep-last", "keepLastPerRepository"},
+	{"unused-days", "unusedDays"},
+	{"delete-untagged", "deleteUntagged"},
+	{"skip-shared", "skipShared"},
+	{"notice-days", "noticeDays"},
+}
+
+func runAdminImageGCReport(cmd *cobra.Command, _ []string) error {
+	params := map[string]string{}
+	for _, policy := range imageGCPolicyFlags {
+		if !flagChanged(cmd, policy.flag) {
+			continue
+		}
+		switch policy.flag {
+		case "delete-untagged", "skip-shared":
+			value, _ := cmd.Flags().GetBool(policy.flag)
+			params[policy.param] = strconv.FormatBool(value)
+		default:
+			value, _ := cmd.Flags().GetInt(policy.flag)
+			if value < 0 {
+				return errUsageFromIssues([]usageIssue{invalidIssue(policy.flag, i18n.T("err_invalid_image_value", policy.flag, strconv.Itoa(value)))})
+			}
+			params[policy.param] = strconv.Itoa(value)
+		}
+	}
+	client, err := activeAPIClient()
+	if err != nil {
+		return err
+	}
+	report, err := client.GetImageGCReport(params)
+	if err != nil {
+		return cliErrFromAPI(err)
+	}
+	if outputJSON {
+		return output.WriteSuccessJSON(os.Stdout, output.SuccessEnvelope(map[string]interface{}{"gc_report": report}))
+	}
+	printImageGCReport(report)
+	return nil
+}
+
+func printImageGCReport(report *api.ImageGCReport) {
+	policy := report.Policy
+	skipShared := policy.SkipShared == nil || *policy.SkipShared
+	fmt.Printf("%s: %s (%s)\n", i18n.T("image_gc_output_schedule"), emptyDash(report.Schedule), emptyDash(report.JobStatus))
+	fmt.Printf("%s: keepLastPerRepository=%d unusedDays=%d deleteUntagged=%t skipShared=%t noticeDays=%d\n",
+		i18n.T("image_gc_output_policy"), policy.KeepLastPerRepository, policy.UnusedDays, policy.DeleteUntagged, skipShared, policy.NoticeDays)
+	fmt.Printf("%s: %d/%d, %s\n", i18n.T("image_gc_output_candidates"), len(report.Candidates), report.Scanned, formatBytes(report.TotalSize))
+	if len(report.Candidates) == 0 {
+		return
+	}
+	fmt.Println()
+	fmt.Printf("%s %s %s %s %s %s %s %s\n", i18n.PadRight(i18n.T("table_id"), 6), i18n.PadRight(i18n.T("table_owner"), 14), i18n.PadRight(i18n.T("table_reason"), 12), i18n.PadRight(i18n.T("table_size"), 10), i18n.PadRight(i18n.T("table_created"), 17), i18n.PadRight(i18n.T("image_gc_table_last_used"), 17), i18n.PadRight(i18n.T("image_gc_table_delete_after"), 17), i18n.T("table_image"))
+	for _, candidate := range report.Candidates {
+		lastUsed := "-"
+		if candidate.LastUsedAt != nil {
+			lastUsed = candidate.LastUsedAt.Format("2006-01-02 15:04")
+		}
+		deleteAfter := candidate.DeleteAfter.Format("2006-01-02 15:04")
+		if candidate.NotifiedAt == nil {
+			deleteAfter += "*"
+		}
+		fmt.Printf("%s %s %s %s %s %s %s %s\n", i18n.PadRight(strconv.FormatUint(uint64(candidate.ImageID), 10), 6), i18n.PadRight(emptyDash(candidate.Username), 14), i18n.PadRight(candidate.Reason, 12), i18n.PadRight(formatBytes(candidate.Size), 10), i18n.PadRight(candidate.CreatedAt.Format("2006-01-02 15:04"), 17), i18n.PadRight(lastUsed, 17), i18n.PadRight(deleteAfter, 17), candidate.ImageLink)
+	}
+	fmt.Println()
+	fmt.Println(i18n.T("image_gc_output_not_notified"))
+}
+
+func init() {
+	adminImageGCReportCmd.Flags().Int("keep-last", 0, "Override the number of newest images kept per repository")
+	adminImageGCReportCmd.Flags().Int("unused-days", 0, "Override the days after which images no job used are deleted")
+	adminImageGCReportCmd.Flags().Bool("delete-untagged", false, "Override whether images no registry tag points at are deleted")
+	adminImageGCReportCmd.Flags().Bool("skip-shared", true, "Override whether public and shared images are kept")
+	adminImageGCReportCmd.Flags().Int("notice-days", 0, "Override the days between notifying owners and deleting")
+
+	adminImageCmd.AddCommand(adminImageGCReportCmd)
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/raids-lab/crater/cli/internal/api"
+	"github.com/raids-lab/crater/cli/internal/clierror"
+	"github.com/raids-lab/crater/cli/internal/i18n"
+	"github.com/raids-lab/crater/cli/internal/output"
+	"github.com/raids-lab/crater/cli/pkg/errorcodes"
+	"github.com/spf13/cobra"
+)
+
+var imageGitCredentialCmd = imageCommandGroup("git-credential", "Manage credentials for building from private Git repositories")
+var imageGitCredentialLsCmd = &cobra.Command{Use: "ls", Short: "List your Git credentials", Args: noArgs, RunE: runImageGitCredentialLs}
+var imageGitCredentialSetCmd = &cobra.Command{Use: "set <name>", Short: "Create or update a Git credential, reading the password or token from stdin", Args: exactArgs(1, "name"), RunE: runImageGitCredentialSet}
+var imageGitCredentialRmCmd = &cobra.Command{Use: "rm <name>", Short: "Delete a Git credential", Args: exactArgs(1, "name"), RunE: runImageGitCredentialRm}
+
+func runImageGitCredentialLs(_ *cobra.Command, _ []string) error {
+	client, err := activeAPIClient()
+	if err != nil {
+		return err
+	}
+	credentials, err := client.ListGitCredentials()
+	if err != nil {
+		return cliErrFromAPI(err)
+	}
+	if outputJSON {
+		return output.WriteSuccessJSON(os.Stdout, output.SuccessEnvelope(map[string]interface{}{"git_credentials": credentials}))
+	}
+	printGitCredentialTable(credentials)
+	return nil
+}
+
+func runImageGitCredentialSet(cmd *cobra.Command, args []string) error {
+	name, err := requiredArg(args, "image_label_git_credential", "name")
+	if err != nil {
+		return err
+	}
+	username, _ := cmd.Flags().GetString("username")
+	if strings.TrimSpace(username) == "" {
+		return errUsageFromIssues([]usageIssue{missingIssue("username", "image_git-credential_set_flag_username")})
+	}
+	password, err := readGitCredentialPassword(os.Stdin)
+	if err != nil {
+		return err
+	}
+	client, err := activeAPIClient()
+	if err != nil {
+		return err
+	}
+	req := api.GitCredentialRequest{Name: name, Username: strings.TrimSpace(username), Password: password}
+	credential, err := client.SaveGitCredential(req)
+	if err != nil {
+		return cliErrFromAPI(err)
+	}
+	if outputJSON {
+		return output.WriteSuccessJSON(os.Stdout, output.SuccessEnvelope(map[string]interface{}{"git_credential": credential}))
+	}
+	printGitCredentialTable([]api.GitCredential{*credential})
+	return nil
+}
+
+func runImageGitCredentialRm(_ *cobra.Command, args []string) error {
+	name, err := requiredArg(args, "image_label_git_credential", "name")
+	if err != nil {
+		return err
+	}
+	client, err := activeAPIClient()
+	if err != nil {
+		return err
+	}
+	return writeImageMessage(client.DeleteGitCredential(name))
+}
+
+// readGitCredentialPassword keeps the password or access token out of the shell history.
+func readGitCredentialPassword(r io.Reader) (string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", &clierror.Error{
+			Category: errorcodes.CategorySystem,
+			Code:     errorcodes.ErrCommandExecution,
+			Message:  i18n.T("err_image_git_credential_stdin_read", err.Error()),
+		}
+	}
+	password := strings.TrimSpace(string(data))
+	if password == "" {
+		return "", errUsageFromIssues([]usageIssue{{
+			Code:    errorcodes.ErrMissingRequiredFlag,
+			Message: i18n.T("err_image_git_credential_stdin_empty"),
+			Field:   "password",
+		}})
+	}
+	return password, nil
+}
+
+func printGitCredentialTable(items []api.GitCredential) {
+	fmt.Printf("%s %s %s\n", i18n.PadRight(i18n.T("table_name"), 28), i18n.PadRight(i18n.T("table_username"), 24), i18n.T("table_created_at"))
+	for _, item := range items {
+		fmt.Printf("%s %s %s\n", i18n.PadRight(item.Name, 28), i18n.PadRight(item.Username, 24), item.CreatedAt.Format("2006-01-02 15:04:05"))
+	}
+}
+
+func init() {
+	imageGitCredentialSetCmd.Flags().String("username", "", "Username of the Git server")
+
+	imageGitCredentialCmd.AddCommand(imageGitCredentialLsCmd, imageGitCredentialSetCmd, imageGitCredentialRmCmd)
+	imageCmd.AddCommand(imageGitCredentialCmd)
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/raids-lab/crater/cli/internal/api"
+	"github.com/raids-lab/crater/cli/internal/completion"
+	"github.com/raids-lab/crater/cli/internal/i18n"
+	"github.com/raids-lab/crater/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// imageScanSeverities is ordered from the least to the most severe.
+var imageScanSeverities = []string{"UNKNOWN", "LOW", "MEDIUM", "HIGH", "CRITICAL"}
+
+var imageScanCmd = &cobra.Command{Use: "scan <id>", Short: "Show the package and vulnerability scan of an image", Args: exactArgs(1, "id"), RunE: runImageScan}
+var adminImageScanCmd = &cobra.Command{Use: "scan <id>", Short: "Show the package and vulnerability scan of any image", Args: exactArgs(1, "id"), RunE: runAdminImageScan}
+var adminImageScanPolicyCmd = &cobra.Command{Use: "scan-policy", Short: "View or set the scan severity that blocks publishing images", Args: noArgs, RunE: runAdminImageScanPolicy}
+
+func runImageScan(cmd *cobra.Command, args []string) error {
+	return runImageScanFor(cmd, args, false)
+}
+
+func runAdminImageScan(cmd *cobra.Command, args []string) error {
+	return runImageScanFor(cmd, args, true)
+}
+
+func runImageScanFor(cmd *cobra.Command, args []string, admin bool) error {
+	id, err := requiredUintArg(args, "image_label_id", "id")
+	if err != nil {
+		return err
+	}
+	severity, _ := cmd.Flags().GetString("severity")
+	severity = strings.ToUpper(strings.TrimSpace(severity))
+	if severity != "" && !slices.Contains(imageScanSeverities, severity) {
+		return errUsageFromIssues([]usageIssue{invalidIssue("severity", i18n.T("err_invalid_image_value", "severity", severity))})
+	}
+	client, err := activeAPIClient()
+	if err != nil {
+		return err
+	}
+	if rescan, _ := cmd.Flags().GetBool("rescan"); rescan {
+		msg, err := client.RescanImage(id, admin)
+		return writeImageMessage(msg, err)
+	}
+	packages, _ := cmd.Flags().GetBool("packages")
+	report, err := client.GetImageScan(id, packages, admin)
+	if err != nil {
+		return cliErrFromAPI(err)
+	}
+	report.Findings = filterScanFindings(report.Findings, severity)
+	if outputJSON {
+		return output.WriteSuccessJSON(os.Stdout, output.SuccessEnvelope(map[string]interface{}{"scan": report}))
+	}
+	printImageScan(report)
+	return nil
+}
+
+func runAdminImageScanPolicy(cmd *cobra.Command, _ []string) error {
+	client, err := activeAPIClient()
+	if err != nil {
+		return err
+	}
+	if flagChanged(cmd, "block-severity") {
+		severity, _ := cmd.Flags().GetString("block-severity")
+		severity = strings.ToUpper(strings.TrimSpace(severity))
+		if severity != "NONE" && !slices.Contains(imageScanSeverities, severity) {
+			return errUsageFromIssues([]usageIssue{invalidIssue("block-severity", i18n.T("err_invalid_image_value", "block-severity", severity))})
+		}
+		if _, err := client.UpdateImageScanConfig(api.ImageScanConfigRequest{BlockPublicSeverity: severity}); err != nil {
+			return cliErrFromAPI(err)
+		}
+	}
+	policy, err := client.GetImageScanConfig()
+	if err != nil {
+		return cliErrFromAPI(err)
+	}
+	if outputJSON {
+		return output.WriteSuccessJSON(os.Stdout, output.SuccessEnvelope(map[string]interface{}{"scan_policy": policy}))
+	}
+	fmt.Printf("%s: %t\n%s: %t\n%s: %s\n",
+		i18n.T("image_scan_output_scanner"), policy.ScannerEnabled,
+		i18n.T("image_scan_output_database"), policy.VulnerabilityDBConfigured,
+		i18n.T("image_scan_output_block"), emptyDash(policy.BlockPublicSeverity))
+	return nil
+}
+
+// filterScanFindings keeps the findings at or above the minimum severity.
+func filterScanFindings(findings []api.ImageScanFinding, minimum string) []api.ImageScanFinding {
+	if minimum == "" {
+		return findings
+	}
+	floor := slices.Index(imageScanSeverities, minimum)
+	out := make([]api.ImageScanFinding, 0, len(findings))
+	for _, finding := range findings {
+		if slices.Index(imageScanSeverities, strings.ToUpper(finding.Severity)) >= floor {
+			out = append(out, finding)
+		}
+	}
+	return out
+}
+
+func printImageScan(report *api.ImageScanReport) {
+	scanned := "-"
+	if report.ScannedAt != nil {
+		scanned = report.ScannedAt.Format("2006-01-02 15:04:05")
+	}
+	fmt.Printf("%s: %s\n%s: %s\n%s: %s\n", i18n.T("table_image"), report.ImageLink, i18n.T("table_status"), report.Status, i18n.T("image_scan_output_scanned"), scanned)
+	if report.Message != "" {
+		fmt.Printf("%s: %s\n", i18n.T("image_scan_output_message"), report.Message)
+	}
+	fmt.Printf("%s: %s\n%s: %s\n%s: %s\n%s: %d\n%s: %s\n",
+		i18n.T("image_scan_output_digest"), emptyDash(report.Digest),
+		i18n.T("image_scan_output_os"), emptyDash(report.OS),
+		i18n.T("table_arch"), emptyDash(report.Arch),
+		i18n.T("image_scan_output_packages"), report.PackageCount,
+		i18n.T("image_scan_output_database"), emptyDash(report.DatabaseVersion))
+	fmt.Printf("%s: CRITICAL %d, HIGH %d, MEDIUM %d, LOW %d, UNKNOWN %d\n",
+		i18n.T("image_scan_output_summary"), report.Critical, report.High, report.Medium, report.Low, report.Unknown)
+
+	if len(report.Findings) > 0 {
+		fmt.Println()
+		fmt.Printf("%s %s %s %s %s %s\n", i18n.PadRight(i18n.T("image_scan_table_severity"), 10), i18n.PadRight(i18n.T("image_scan_table_vulnerability"), 22), i18n.PadRight(i18n.T("image_scan_table_package"), 28), i18n.PadRight(i18n.T("image_scan_table_version"), 24), i18n.PadRight(i18n.T("image_scan_table_fixed"), 24), i18n.T("image_scan_table_title"))
+		for _, finding := range report.Findings {
+			fmt.Printf("%s %s %s %s %s %s\n", i18n.PadRight(finding.Severity, 10), i18n.PadRight(finding.ID, 22), i18n.PadRight(finding.Type+"/"+finding.Package, 28), i18n.PadRight(finding.Version, 24), i18n.PadRight(emptyDash(finding.FixedVersion), 24), finding.Title)
+		}
+	}
+	if len(report.Packages) > 0 {
+		fmt.Println()
+		fmt.Printf("%s %s %s %s\n", i18n.PadRight(i18n.T("table_type"), 8), i18n.PadRight(i18n.T("image_scan_table_package"), 36), i18n.PadRight(i18n.T("image_scan_table_version"), 32), i18n.T("image_scan_table_source"))
+		for _, pkg := range report.Packages {
+			fmt.Printf("%s %s %s %s\n", i18n.PadRight(pkg.Type, 8), i18n.PadRight(pkg.Name, 36), i18n.PadRight(pkg.Version, 32), emptyDash(pkg.Source))
+		}
+	}
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{imageScanCmd, adminImageScanCmd} {
+		cmd.Flags().Bool("rescan", false, "Queue a new scan instead of showing the report")
+		cmd.Flags().Bool("packages", false, "Also list the package inventory")
+		cmd.Flags().String("severity", "", "Only show findings at or above this severity")
+	}
+	adminImageScanPolicyCmd.Flags().String("block-severity", "", "Block publishing images with findings at or above this severity; none disables the check")
+
+	completion.RegisterFlagValue([]string{"image", "scan"}, "severity", staticValueCompleter(imageScanSeverities, nil))
+	completion.RegisterFlagValue([]string{"admin", "image", "scan"}, "severity", staticValueCompleter(imageScanSeverities, nil))
+	completion.RegisterFlagValue([]string{"admin", "image", "scan-policy"}, "block-severity", staticValueCompleter(append(slices.Clone(imageScanSeverities), "none"), nil))
+
+	imageCmd.AddCommand(imageScanCmd)
+	adminImageCmd.AddCommand(adminImageScanCmd, adminImageScanPolicyCmd)
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/raids-lab/crater/cli/internal/api"
 	"github.com/raids-lab/crater/cli/internal/i18n"
@@ -61,6 +62,46 @@ func TestImageCommandGroupsRejectUnknownSubcommands(t *testing.T) {
 	}
 }
 
+func TestImageScanFiltersFindingsBySeverity(t *testing.T) {
+	findings := []api.ImageScanFinding{
+		{ID: "CVE-1", Severity: "CRITICAL"},
+		{ID: "CVE-2", Severity: "LOW"},
+		{ID: "CVE-3", Severity: "HIGH"},
+		{ID: "CVE-4", Severity: "UNKNOWN"},
+	}
+	if got := filterScanFindings(findings, ""); len(got) != len(findings) {
+		t.Fatalf("no minimum must keep every finding, got %+v", got)
+	}
+	got := filterScanFindings(findings, "HIGH")
+	if len(got) != 2 || got[0].ID != "CVE-1" || got[1].ID != "CVE-3" {
+		t.Fatalf("unexpected findings at or above HIGH: %+v", got)
+	}
+}
+
+func TestPrintImageScanShowsSummaryAndFindings(t *testing.T) {
+	previousLanguage := i18n.GetCurrentLanguage()
+	i18n.SetLanguage("en")
+	t.Cleanup(func() { i18n.SetLanguage(previousLanguage) })
+
+	got := captureImageTestStdout(t, func() {
+		printImageScan(&api.ImageScanReport{
+			ImageLink:       "registry.example/demo:v1",
+			Status:          "Succeeded",
+			OS:              "Ubuntu 22.04.4 LTS",
+			PackageCount:    2,
+			High:            1,
+			DatabaseVersion: "2026-10-01",
+			Findings:        []api.ImageScanFinding{{ID: "CVE-2026-0001", Severity: "HIGH", Type: "deb", Package: "openssl", Version: "3.0.2-0ubuntu1.14", FixedVersion: "3.0.2-0ubuntu1.15"}},
+			Packages:        []api.ImageScanPackage{{Type: "pypi", Name: "torch", Version: "2.3.0"}},
+		})
+	})
+	for _, want := range []string{"HIGH 1", "CVE-2026-0001", "deb/openssl", "3.0.2-0ubuntu1.15", "torch", "2026-10-01"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("scan output must contain %q, got %q", want, got)
+		}
+	}
+}
+
 func captureImageTestStdout(t *testing.T, fn func()) string {
 	t.Helper()
 	old := os.Stdout
@@ -81,3 +122,92 @@ func captureImageTestStdout(t *testing.T, fn func()) string {
 	}
 	return string(output)
 }
+
+func TestPrintImageGCReportMarksUnnotifiedImages(t *testing.T) {
+	previousLanguage := i18n.GetCurrentLanguage()
+	i18n.SetLanguage("en")
+	t.Cleanup(func() { i18n.SetLanguage(previousLanguage) })
+
+	notifiedAt := time.Date(2026, 10, 1, 3, 0, 0, 0, time.UTC)
+	got := captureImageTestStdout(t, func() {
+		printImageGCReport(&api.ImageGCReport{
+			Schedule:  "0 3 * * *",
+			JobStatus: "suspended",
+			Policy:    api.ImageGCPolicy{KeepLastPerRepository: 5, UnusedDays: 90, NoticeDays: 7},
+			Scanned:   12,
+			TotalSize: 3 << 30,
+			Candidates: []api.ImageGCCandidate{
+				{ImageID: 3, ImageLink: "registry.example/user-alice/train:v1", Username: "alice", Reason: "superseded", Size: 1 << 30, NotifiedAt: &notifiedAt, DeleteAfter: notifiedAt.AddDate(0, 0, 7)},
+				{ImageID: 4, ImageLink: "registry.example/user-bob/snap:v2", Username: "bob", Reason: "unused", Size: 2 << 30, DeleteAfter: time.Date(2026, 10, 25, 3, 0, 0, 0, time.UTC)},
+			},
+		})
+	})
+	for _, want := range []string{"keepLastPerRepository=5", "skipShared=true", "2/12, 3.0GiB", "2026-10-08 03:00 ", "2026-10-25 03:00*", "user-bob/snap:v2", "owner not notified"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("gc report must contain %q, got %q", want, got)
+		}
+	}
+}
+
+func TestCollectDockerfileBuildValidatesArchsAndNoCache(t *testing.T) {
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{Use: "dockerfile"}
+		addCommonBuildFlags(cmd)
+		cmd.Flags().String("dockerfile", "", "")
+		cmd.Flags().String("file", "", "")
+		cmd.Flags().Bool("no-cache", false, "")
+		if err := cmd.Flags().Parse(args); err != nil {
+			t.Fatalf("parse flags: %v", err)
+		}
+		return cmd
+	}
+
+	req, err := collectDockerfileBuild(newCmd("--name", "demo", "--tag", "v1", "--dockerfile", "FROM ubuntu",
+		"--archs", "linux/amd64,linux/arm64", "--no-cache"))
+	if err != nil {
+		t.Fatalf("collect multi-arch build: %v", err)
+	}
+	if strings.Join(req.Archs, ",") != "linux/amd64,linux/arm64" || !req.NoCache {
+		t.Fatalf("unexpected request %+v", req)
+	}
+
+	if _, err := collectDockerfileBuild(newCmd("--name", "demo", "--tag", "v1", "--dockerfile", "FROM ubuntu",
+		"--archs", "linux/riscv64")); err == nil || !strings.Contains(err.Error(), "linux/riscv64") {
+		t.Fatalf("expected invalid arch error, got %v", err)
+	}
+}
+
+func TestCollectGitBuildRequiresRepo(t *testing.T) {
+	parse := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{Use: "git"}
+		addGitBuildFlags(cmd)
+		if err := cmd.Flags().Parse(args); err != nil {
+			t.Fatalf("parse flags: %v", err)
+		}
+		return cmd
+	}
+
+	req, err := collectGitBuild(parse("--name", "crater", "--tag", "v1", "--repo", " https://github.com/raids-lab/crater.git ",
+		"--ref", "main", "--subdir", "backend", "--credential-secret", "alice-git"))
+	if err != nil {
+		t.Fatalf("collect git build: %v", err)
+	}
+	if req.Repo != "https://github.com/raids-lab/crater.git" || req.Ref != "main" || req.Subdir != "backend" ||
+		req.CredentialSecret != "alice-git" || strings.Join(req.Archs, ",") != "linux/amd64" {
+		t.Fatalf("unexpected request %+v", req)
+	}
+
+	if _, err := collectGitBuild(parse("--name", "crater", "--tag", "v1")); err == nil || !strings.Contains(err.Error(), "repo") {
+		t.Fatalf("expected missing repo error, got %v", err)
+	}
+}
+
+func TestReadGitCredentialPassword(t *testing.T) {
+	password, err := readGitCredentialPassword(strings.NewReader("  ghp_token\n"))
+	if err != nil || password != "ghp_token" {
+		t.Fatalf("readGitCredentialPassword = %q, %v", password, err)
+	}
+	if _, err := readGitCredentialPassword(strings.NewReader("\n")); err == nil {
+		t.Fatal("expected an error for an empty password")
+	}
+}
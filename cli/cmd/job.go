@@ -442,14 +442,23 @@ func runJobCreateCustom(cmd *cobra.Command, _ []string) error {
 	if err != nil {
 		return err
 	}
+	if err := applyJobArrayFlags(cmd, &req); err != nil {
+		return err
+	}
 	if err := validateTrainingRequest(req); err != nil {
 		return err
 	}
+	if jobEstimateRequested(cmd) {
+		return runJobEstimate("training", req)
+	}
 	client, err := activeAPIClient()
 	if err != nil {
 		return err
 	}
 	data, err := client.CreateTrainingJob(req)
+	if req.Array != nil {
+		return writeJobArrayResult("job_array_create_success", data, err)
+	}
 	return writeCreateResult(data, err)
 }
 
@@ -461,6 +470,9 @@ func runJobCreateTensorflow(cmd *cobra.Command, _ []string) error {
 	if err := validateDistributedRequest(req); err != nil {
 		return err
 	}
+	if jobEstimateRequested(cmd) {
+		return runJobEstimate("tensorflow", req)
+	}
 	client, err := activeAPIClient()
 	if err != nil {
 		return err
@@ -477,6 +489,9 @@ func runJobCreatePytorch(cmd *cobra.Command, _ []string) error {
 	if err := validateDistributedRequest(req); err != nil {
 		return err
 	}
+	if jobEstimateRequested(cmd) {
+		return runJobEstimate("pytorch", req)
+	}
 	client, err := activeAPIClient()
 	if err != nil {
 		return err
@@ -789,14 +804,17 @@ func parseDatasetFlags(cmd *cobra.Command) ([]api.VolumeMount, error) {
 	out := make([]api.VolumeMount, 0, len(values))
 	for _, value := range values {
 		rawID, mountPath, ok := strings.Cut(value, ":")
+		rawID, version, versioned := strings.Cut(rawID, "@")
 		id, parseErr := strconv.ParseUint(strings.TrimSpace(rawID), 10, 0)
-		if !ok || parseErr != nil || id == 0 || strings.TrimSpace(mountPath) == "" {
+		if !ok || parseErr != nil || id == 0 || strings.TrimSpace(mountPath) == "" ||
+			(versioned && strings.TrimSpace(version) == "") {
 			return nil, errUsageFromIssues([]usageIssue{invalidIssue("dataset", i18n.T("err_invalid_enum", "dataset", value))})
 		}
 		out = append(out, api.VolumeMount{
-			Type:      volumeTypeDataset,
-			DatasetID: uint(id),
-			MountPath: strings.TrimSpace(mountPath),
+			Type:           volumeTypeDataset,
+			DatasetID:      uint(id),
+			DatasetVersion: strings.TrimSpace(version),
+			MountPath:      strings.TrimSpace(mountPath),
 		})
 	}
 	return out, nil
@@ -1286,13 +1304,21 @@ func printJobTable(jobs []api.JobInfo) {
 			i18n.PadRight(job.Name, 24),
 			i18n.PadRight(job.JobName, 34),
 			i18n.PadRight(job.JobType, 12),
-			i18n.PadRight(job.Status, 14),
+			i18n.PadRight(formatJobListStatus(job), 14),
 			i18n.PadRight(job.Queue, 18),
 			i18n.PadRight(strings.Join(job.Nodes, ","), 22),
 			i18n.PadRight(formatResources(job.Resources), 24))
 	}
 }
 
+// formatJobListStatus appends the fair-share activation priority to prequeued jobs.
+func formatJobListStatus(job api.JobInfo) string {
+	if job.FairSharePriority == nil {
+		return job.Status
+	}
+	return fmt.Sprintf("%s(%.2f)", job.Status, *job.FairSharePriority)
+}
+
 func printJobDetail(job *api.JobDetail) {
 	if job == nil {
 		return
@@ -1379,7 +1405,7 @@ func addCreateCommonFlags(cmd *cobra.Command) {
 	cmd.Flags().String("schedule", "", "Schedule type: normal or backfill")
 	cmd.Flags().StringArray("env", nil, "Environment variable KEY=VALUE, repeatable")
 	cmd.Flags().StringArray("volume", nil, "Workspace mount subPath:mountPath, repeatable")
-	cmd.Flags().StringArray("dataset", nil, "Dataset mount id:mountPath, repeatable")
+	cmd.Flags().StringArray("dataset", nil, "Dataset mount id[@version]:mountPath, repeatable")
 	cmd.Flags().StringArray("selector", nil, "Node selector key=Operator:value1,value2, repeatable")
 	cmd.Flags().StringArray("forward", nil, "Forward name:port, repeatable")
 }
@@ -1416,8 +1442,12 @@ func init() {
 	jobCreateCustomCmd.Flags().String("working-dir", "/workspace", "Working directory")
 	jobCreateCustomCmd.Flags().String("command", "", "Command to run")
 	jobCreateCustomCmd.Flags().String("shell", "sh", "Shell for --command")
+	addJobArrayFlags(jobCreateCustomCmd)
 	jobCreateTensorflowCmd.Flags().String("file", "", "Read exact JSON request body from file")
 	jobCreatePytorchCmd.Flags().String("file", "", "Read exact JSON request body from file")
+	for _, createCmd := range []*cobra.Command{jobCreateCustomCmd, jobCreateTensorflowCmd, jobCreatePytorchCmd} {
+		addJobEstimateFlag(createCmd)
+	}
 
 	jobDeleteCmd.Flags().BoolP("yes", "y", false, "Delete without confirmation")
 	adminJobDeleteCmd.Flags().BoolP("yes", "y", false, "Delete without confirmation")
@@ -1452,7 +1482,7 @@ func init() {
 	jobCreateCmd.AddCommand(jobCreateJupyterCmd, jobCreateWebIDECmd, jobCreateCustomCmd, jobCreateTensorflowCmd, jobCreatePytorchCmd)
 	jobAdminCleanCmd.AddCommand(jobAdminCleanWaitingJupyterCmd, jobAdminCleanWaitingCustomCmd, jobAdminCleanLongRunningCmd, jobAdminCleanLowGPUCmd)
 	adminJobCmd.AddCommand(adminJobLsCmd, adminJobDeleteCmd, jobAdminLockCmd, jobAdminUnlockCmd, jobAdminKeepCmd, jobAdminCleanCmd)
-	jobCmd.AddCommand(jobLsCmd, jobGetCmd, jobPodsCmd, jobEventsCmd, jobYAMLCmd, jobTemplateCmd, jobTokenCmd, jobSecretCmd, jobSSHCmd, jobSnapshotCmd, jobAlertCmd, jobDeleteCmd, jobCreateCmd)
+	jobCmd.AddCommand(jobLsCmd, jobGetCmd, jobPodsCmd, jobEventsCmd, jobYAMLCmd, jobTemplateCmd, jobTokenCmd, jobSecretCmd, jobSSHCmd, jobSnapshotCmd, jobAlertCmd, jobDeleteCmd, jobCreateCmd, jobArrayCmd)
 	adminCmd.AddCommand(adminJobCmd)
 	rootCmd.AddCommand(jobCmd)
 }
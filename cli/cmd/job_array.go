@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/raids-lab/crater/cli/internal/api"
+	"github.com/raids-lab/crater/cli/internal/i18n"
+	"github.com/raids-lab/crater/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var jobArrayCmd = &cobra.Command{
+	Use:   "array",
+	Short: "Manage job arrays",
+	Long:  "List, inspect, bill and cancel job arrays created with `job create custom --array-*`.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 0 {
+			return errUnknownSubcommand(cmd, args[0])
+		}
+		return cmd.Help()
+	},
+}
+
+var jobArrayLsCmd = &cobra.Command{Use: "ls", Short: "List job arrays", Args: noArgs, RunE: runJobArrayLs}
+var jobArrayGetCmd = &cobra.Command{Use: "get <id>", Short: "Get a job array and its elements", Args: exactArgs(1, "id"), RunE: runJobArrayGet}
+var jobArrayBillingCmd = &cobra.Command{Use: "billing <id>", Short: "Show the billed points of a job array", Args: exactArgs(1, "id"), RunE: runJobArrayBilling}
+var jobArrayCancelCmd = &cobra.Command{Use: "cancel <id>", Short: "Cancel every element of a job array", Args: exactArgs(1, "id"), RunE: runJobArrayCancel}
+
+func runJobArrayLs(cmd *cobra.Command, _ []string) error {
+	return runRawRead(cmd, rawReadSpec{PayloadKey: "job_arrays", Path: api.JobArraysPrefix, Params: noParams, Table: printJobArrayTable})
+}
+
+func runJobArrayGet(cmd *cobra.Command, args []string) error {
+	id, err := requiredUintArg(args, "job_array_label_id", "id")
+	if err != nil {
+		return err
+	}
+	return runRawRead(cmd, rawReadSpec{PayloadKey: "job_array", Path: api.JobArraysPrefix + "/" + api.UintPath(id), Params: noParams, Table: printJobArrayDetail})
+}
+
+func runJobArrayBilling(cmd *cobra.Command, args []string) error {
+	id, err := requiredUintArg(args, "job_array_label_id", "id")
+	if err != nil {
+		return err
+	}
+	return runRawRead(cmd, rawReadSpec{PayloadKey: "billing", Path: api.JobArraysPrefix + "/" + api.UintPath(id) + "/billing", Params: noParams, Table: printJobArrayBilling})
+}
+
+func runJobArrayCancel(cmd *cobra.Command, args []string) error {
+	id, err := requiredUintArg(args, "job_array_label_id", "id")
+	if err != nil {
+		return err
+	}
+	if err := requireConfirmation(cmd); err != nil {
+		return err
+	}
+	client, err := activeAPIClient()
+	if err != nil {
+		return err
+	}
+	data, err := client.CancelJobArray(id)
+	return writeJobArrayResult("job_array_cancel_success", data, err)
+}
+
+func addJobArrayFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("array-count", 0, "Submit N copies indexed by CRATER_ARRAY_INDEX")
+	cmd.Flags().StringArray("array-param", nil, "Sweep parameter NAME=v1,v2 injected as env, repeatable")
+	cmd.Flags().Int("array-parallelism", 0, "Maximum number of array elements running at once")
+}
+
+// applyJobArrayFlags turns the --array-* flags into the array field of the request.
+// Flags override an array given in --file.
+func applyJobArrayFlags(cmd *cobra.Command, req *api.CreateTrainingJobRequest) error {
+	count, _ := cmd.Flags().GetInt("array-count")
+	params, _ := cmd.Flags().GetStringArray("array-param")
+	parallelism, _ := cmd.Flags().GetInt("array-parallelism")
+	if count == 0 && len(params) == 0 && parallelism == 0 {
+		return validateJobArrayRequest(req.Array)
+	}
+
+	array := &api.JobArrayRequest{Count: count, Parallelism: parallelism}
+	if len(params) > 0 {
+		array.Matrix = make(map[string][]string, len(params))
+	}
+	var issues []usageIssue
+	for _, param := range params {
+		name, values, ok := strings.Cut(param, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || strings.TrimSpace(values) == "" {
+			issues = append(issues, invalidIssue("array-param", i18n.T("err_invalid_array_param", param)))
+			continue
+		}
+		for _, value := range strings.Split(values, ",") {
+			array.Matrix[name] = append(array.Matrix[name], strings.TrimSpace(value))
+		}
+	}
+	if len(issues) > 0 {
+		return errUsageFromIssues(issues)
+	}
+	req.Array = array
+	return validateJobArrayRequest(req.Array)
+}
+
+func validateJobArrayRequest(array *api.JobArrayRequest) error {
+	if array == nil {
+		return nil
+	}
+	var issues []usageIssue
+	switch {
+	case array.Count < 0:
+		issues = append(issues, invalidIssue("array-count", i18n.T("err_job_array_count")))
+	case array.Count > 0 && len(array.Matrix) > 0:
+		issues = append(issues, invalidIssue("array-count", i18n.T("err_job_array_count_and_param")))
+	case array.Count == 0 && len(array.Matrix) == 0:
+		issues = append(issues, missingIssue("array-count", "job_array_label_count"))
+	}
+	if array.Parallelism < 0 {
+		issues = append(issues, invalidIssue("array-parallelism", i18n.T("err_job_array_parallelism")))
+	}
+	if len(issues) > 0 {
+		return errUsageFromIssues(issues)
+	}
+	return nil
+}
+
+func writeJobArrayResult(key string, data map[string]interface{}, err error) error {
+	if err != nil {
+		return cliErrFromAPI(err)
+	}
+	if outputJSON {
+		return output.WriteSuccessJSON(os.Stdout, output.SuccessEnvelope(map[string]interface{}{"job_array": data}))
+	}
+	fmt.Println(i18n.T(key, rawString(data, "name")))
+	printJobArrayTasks(data)
+	return nil
+}
+
+func printJobArrayTable(data interface{}) {
+	fmt.Printf("%s %s %s %s %s\n", i18n.PadRight(i18n.T("table_id"), 8), i18n.PadRight(i18n.T("table_name"), 28), i18n.PadRight(i18n.T("job_array_table_size"), 12), i18n.PadRight(i18n.T("table_status"), 12), i18n.PadRight("CREATED", 22))
+	for _, row := range rawList(data) {
+		size := rawString(row, "size") + "/" + rawString(row, "parallelism")
+		fmt.Printf("%s %s %s %s %s\n", i18n.PadRight(rawString(row, "id"), 8), i18n.PadRight(rawString(row, "name"), 28), i18n.PadRight(size, 12), i18n.PadRight(rawString(row, "status"), 12), i18n.PadRight(rawString(row, "createdAt"), 22))
+	}
+}
+
+func printJobArrayDetail(data interface{}) {
+	array := rawMap(data)
+	if array == nil {
+		printRawObject(data)
+		return
+	}
+	fmt.Printf("%s: %s\n", i18n.T("table_id"), rawString(array, "id"))
+	fmt.Printf("%s: %s\n", i18n.T("table_name"), rawString(array, "name"))
+	fmt.Printf("%s: %s/%s\n", i18n.T("job_array_table_size"), rawString(array, "size"), rawString(array, "parallelism"))
+	fmt.Printf("%s: %s\n", i18n.T("table_status"), rawString(array, "status"))
+	printJobArrayTasks(array)
+}
+
+func printJobArrayTasks(array map[string]interface{}) {
+	tasks := rawList(array["tasks"])
+	if len(tasks) == 0 {
+		return
+	}
+	fmt.Printf("%s %s %s %s\n", i18n.PadRight(i18n.T("job_array_table_index"), 8), i18n.PadRight(i18n.T("table_job_name"), 32), i18n.PadRight(i18n.T("table_status"), 12), i18n.T("job_array_table_params"))
+	for _, task := range tasks {
+		params := rawMap(task["params"])
+		parts := make([]string, 0, len(params))
+		for _, name := range slices.Sorted(maps.Keys(params)) {
+			parts = append(parts, name+"="+rawString(params, name))
+		}
+		fmt.Printf("%s %s %s %s\n", i18n.PadRight(rawString(task, "index"), 8), i18n.PadRight(rawString(task, "jobName"), 32), i18n.PadRight(rawString(task, "status"), 12), emptyDash(strings.Join(parts, ",")))
+	}
+}
+
+func printJobArrayBilling(data interface{}) {
+	billing := rawMap(data)
+	if billing == nil {
+		printRawObject(data)
+		return
+	}
+	fmt.Printf("%s: %s\n", i18n.T("table_name"), rawString(billing, "name"))
+	fmt.Printf("%s: %s\n", i18n.T("job_array_table_billed"), rawString(billing, "billedPointsTotal"))
+	for _, job := range rawList(billing["jobs"]) {
+		fmt.Printf("%s %s\n", i18n.PadRight(rawString(job, "jobName"), 32), rawString(job, "billedPointsTotal"))
+	}
+}
+
+func init() {
+	jobArrayCancelCmd.Flags().BoolP("yes", "y", false, "Skip confirmation")
+	jobArrayCmd.AddCommand(jobArrayLsCmd, jobArrayGetCmd, jobArrayBillingCmd, jobArrayCancelCmd)
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/raids-lab/crater/cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+func TestApplyJobArrayFlags(t *testing.T) {
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{Use: "custom"}
+		addJobArrayFlags(cmd)
+		if err := cmd.Flags().Parse(args); err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		return cmd
+	}
+
+	var req api.CreateTrainingJobRequest
+	if err := applyJobArrayFlags(newCmd(), &req); err != nil || req.Array != nil {
+		t.Fatalf("no flags: array = %+v, err = %v", req.Array, err)
+	}
+
+	cmd := newCmd("--array-param", "LR=0.1, 0.01", "--array-param", "BS=32", "--array-parallelism", "2")
+	if err := applyJobArrayFlags(cmd, &req); err != nil {
+		t.Fatalf("applyJobArrayFlags() error = %v", err)
+	}
+	if got := strings.Join(req.Array.Matrix["LR"], ","); got != "0.1,0.01" || req.Array.Parallelism != 2 {
+		t.Fatalf("array = %+v", req.Array)
+	}
+
+	err := applyJobArrayFlags(newCmd("--array-count", "3", "--array-param", "broken"), &req)
+	if err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Fatalf("error = %v, want invalid array param", err)
+	}
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/raids-lab/crater/cli/internal/i18n"
+	"github.com/raids-lab/crater/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+func addJobEstimateFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("estimate", false, "Price the job with the current unit prices instead of submitting it")
+}
+
+func jobEstimateRequested(cmd *cobra.Command) bool {
+	estimate, _ := cmd.Flags().GetBool("estimate")
+	return estimate
+}
+
+// runJobEstimate sends the create payload to the dry-run pricing endpoint; kind is
+// the create endpoint of the job type (training, tensorflow or pytorch).
+func runJobEstimate(kind string, body interface{}) error {
+	client, err := activeAPIClient()
+	if err != nil {
+		return err
+	}
+	data, err := client.EstimateJobCost(kind, body)
+	if err != nil {
+		return cliErrFromAPI(err)
+	}
+	if outputJSON {
+		return output.WriteSuccessJSON(os.Stdout, output.SuccessEnvelope(map[string]interface{}{"estimate": data}))
+	}
+	printJobEstimate(data)
+	return nil
+}
+
+func printJobEstimate(data map[string]interface{}) {
+	if rawString(data, "billable") != "true" {
+		fmt.Println(i18n.T("job_estimate_not_billed"))
+		return
+	}
+	fmt.Printf("%s %s\n", i18n.PadRight(i18n.T("job_estimate_cost_per_hour"), 20), rawString(data, "costPerHour"))
+	fmt.Printf("%s %s\n", i18n.PadRight(i18n.T("job_estimate_free_minutes"), 20), rawString(data, "freeMinutes"))
+	fmt.Printf("%s %s / %s\n", i18n.PadRight(i18n.T("job_estimate_balance"), 20), rawString(data, "periodFreeBalance"), rawString(data, "extraBalance"))
+	fmt.Printf("%s %s\n", i18n.PadRight(i18n.T("job_estimate_covered_hours"), 20), formatCoveredHours(data["coveredHours"]))
+}
+
+func formatCoveredHours(v interface{}) string {
+	hours, ok := v.(float64)
+	if !ok {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", hours)
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/raids-lab/crater/cli/internal/api"
+	"github.com/raids-lab/crater/cli/internal/i18n"
+	"github.com/spf13/cobra"
+)
+
+var jobQueueCmd = &cobra.Command{Use: "queue", Short: "Show queue position, blocking reason and estimated start of my prequeued jobs", Args: noArgs, RunE: runJobQueue(api.VCJobsPrefix)}
+var adminJobQueueCmd = &cobra.Command{Use: "queue", Short: "Show queue position, blocking reason and estimated start of all prequeued jobs", Args: noArgs, RunE: runJobQueue(api.AdminVCJobsPrefix)}
+
+func runJobQueue(prefix string) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		return runRawRead(cmd, rawReadSpec{PayloadKey: "queue", Path: prefix + "/queue", Params: jobQueueParams, Table: printJobQueueTable})
+	}
+}
+
+func jobQueueParams(cmd *cobra.Command) map[string]string {
+	return map[string]string{
+		"page":      getIntParam(cmd, "page"),
+		"page_size": getIntParam(cmd, "page-size"),
+	}
+}
+
+func printJobQueueTable(data interface{}) {
+	page := rawMap(data)
+	rows := rawList(page["items"])
+	if len(rows) == 0 {
+		fmt.Println(i18n.T("job_queue_empty"))
+		return
+	}
+	fmt.Printf("%s %s %s %s %s %s %s\n",
+		i18n.PadRight(i18n.T("job_queue_table_position"), 8),
+		i18n.PadRight(i18n.T("table_job_name"), 34),
+		i18n.PadRight(i18n.T("table_owner"), 16),
+		i18n.PadRight(i18n.T("table_queue"), 18),
+		i18n.PadRight(i18n.T("job_queue_table_reason"), 18),
+		i18n.PadRight(i18n.T("job_queue_table_eta"), 16),
+		i18n.T("job_queue_table_detail"))
+	now := time.Now()
+	for _, row := range rows {
+		fmt.Printf("%s %s %s %s %s %s %s\n",
+			i18n.PadRight(rawString(row, "position")+"/"+rawString(row, "scopeSize"), 8),
+			i18n.PadRight(rawString(row, "jobName"), 34),
+			i18n.PadRight(rawNestedString(row, "userInfo", "username"), 16),
+			i18n.PadRight(rawString(row, "account"), 18),
+			i18n.PadRight(queueReasonLabel(rawString(row, "reason")), 18),
+			i18n.PadRight(formatQueueETA(rawString(row, "estimatedStartAt"), now), 16),
+			emptyDash(rawString(row, "detail")))
+	}
+	fmt.Printf("Page %s, %s items total\n", rawString(page, "page"), rawString(page, "total"))
+}
+
+func queueReasonLabel(reason string) string {
+	key := "job_queue_reason_" + reason
+	if label := i18n.T(key); label != key {
+		return label
+	}
+	return reason
+}
+
+// formatQueueETA renders the estimated start relative to now, e.g. "~1h20m".
+func formatQueueETA(value string, now time.Time) string {
+	if value == "" {
+		return "-"
+	}
+	start, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return value
+	}
+	wait := start.Sub(now).Round(time.Minute)
+	if wait <= 0 {
+		return i18n.T("job_queue_eta_now")
+	}
+	return "~" + shortDuration(wait)
+}
+
+func shortDuration(d time.Duration) string {
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+	switch {
+	case hours >= 24:
+		return fmt.Sprintf("%dd%dh", hours/24, hours%24)
+	case hours > 0:
+		return fmt.Sprintf("%dh%dm", hours, minutes)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{jobQueueCmd, adminJobQueueCmd} {
+		cmd.Flags().Int("page", 1, "Page number")
+		cmd.Flags().Int("page-size", 50, "Page size")
+	}
+	jobCmd.AddCommand(jobQueueCmd)
+	adminJobCmd.AddCommand(adminJobQueueCmd)
+}
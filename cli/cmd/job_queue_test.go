@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatQueueETA(t *testing.T) {
+	now := time.Date(2026, 8, 25, 12, 0, 0, 0, time.UTC)
+	cases := map[string]string{
+		"":                          "-",
+		"2026-08-25T11:00:00Z":      "now",
+		"2026-08-25T13:20:00Z":      "~1h20m",
+		"2026-08-25T12:45:00Z":      "~45m",
+		"2026-08-27T15:00:00+00:00": "~2d3h",
+	}
+	for value, want := range cases {
+		if got := formatQueueETA(value, now); got != want {
+			t.Errorf("formatQueueETA(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
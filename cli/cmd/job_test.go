@@ -62,6 +62,35 @@ func TestParseForwardFlags(t *testing.T) {
 	}
 }
 
+func TestParseDatasetFlags(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.Flags().StringArray("dataset", nil, "")
+	for _, value := range []string{"7:/data", "9@v1:/models"} {
+		if err := cmd.Flags().Set("dataset", value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mounts, err := parseDatasetFlags(cmd)
+	if err != nil {
+		t.Fatalf("parseDatasetFlags: %v", err)
+	}
+	want := []api.VolumeMount{
+		{Type: volumeTypeDataset, DatasetID: 7, MountPath: "/data"},
+		{Type: volumeTypeDataset, DatasetID: 9, DatasetVersion: "v1", MountPath: "/models"},
+	}
+	if len(mounts) != len(want) || mounts[0] != want[0] || mounts[1] != want[1] {
+		t.Fatalf("mounts = %#v", mounts)
+	}
+
+	for _, invalid := range []string{"9@:/models", "@v1:/models", "9@v1"} {
+		cmd := &cobra.Command{}
+		cmd.Flags().StringArray("dataset", []string{invalid}, "")
+		if _, err := parseDatasetFlags(cmd); err == nil {
+			t.Fatalf("parseDatasetFlags(%q) succeeded", invalid)
+		}
+	}
+}
+
 func TestReadJSONFileRejectsUnknownFields(t *testing.T) {
 	path := t.TempDir() + "/request.json"
 	if err := os.WriteFile(path, []byte(`{"name":"demo","unknown":true}`), 0o600); err != nil {
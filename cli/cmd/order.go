@@ -40,6 +40,9 @@ var orderByNameCmd = &cobra.Command{Use: "by-name <name>", Short: "List approval
 var orderSubmitCmd = &cobra.Command{Use: "submit", Short: "Submit an approval order", Args: noArgs, RunE: runOrderSubmit}
 var orderEditCmd = &cobra.Command{Use: "edit <id>", Short: "Edit a pending approval order", Args: exactArgs(1, "id"), RunE: runOrderEdit}
 var orderCancelCmd = &cobra.Command{Use: "cancel <id>", Short: "Cancel an approval order", Args: exactArgs(1, "id"), RunE: runOrderCancel}
+var orderAssignedCmd = &cobra.Command{Use: "assigned", Short: "List approval orders assigned to you for review", Args: noArgs, RunE: runOrderAssigned}
+var orderApproveCmd = &cobra.Command{Use: "approve <id>", Short: "Approve an approval order assigned to you", Args: exactArgs(1, "id"), RunE: runOrderApprove}
+var orderRejectCmd = &cobra.Command{Use: "reject <id>", Short: "Reject an approval order assigned to you", Args: exactArgs(1, "id"), RunE: runOrderReject}
 var adminOrderCmd = &cobra.Command{Use: "order", Short: "Manage admin approval orders"}
 var adminOrderLsCmd = &cobra.Command{Use: "ls", Short: "List approval orders", Args: noArgs, RunE: runAdminOrderLs}
 var adminOrderGetCmd = &cobra.Command{Use: "get <id>", Short: "Get an approval order", Args: exactArgs(1, "id"), RunE: runAdminOrderGet}
@@ -138,6 +141,48 @@ func runOrderCancel(cmd *cobra.Command, args []string) error {
 	return writeOrderMessage("order_delete_success", message)
 }
 
+func runOrderAssigned(cmd *cobra.Command, _ []string) error {
+	return runRawRead(cmd, rawReadSpec{PayloadKey: "orders", Path: api.ApprovalOrderPrefix + "/assigned", Params: noParams, Table: printOrderTable})
+}
+
+func runOrderApprove(cmd *cobra.Command, args []string) error {
+	return reviewAssignedApprovalOrder(cmd, args, "Approved")
+}
+
+func runOrderReject(cmd *cobra.Command, args []string) error {
+	return reviewAssignedApprovalOrder(cmd, args, "Rejected")
+}
+
+// reviewAssignedApprovalOrder reviews an order as its assignee, e.g. the owner of a requested dataset.
+func reviewAssignedApprovalOrder(cmd *cobra.Command, args []string, status string) error {
+	id, err := requiredUintArg(args, "order_label_id", "id")
+	if err != nil {
+		return err
+	}
+	notes, _ := cmd.Flags().GetString("review-notes")
+	notes = strings.TrimSpace(notes)
+	expiresAt, issues := collectExpiresFlag(cmd)
+	if status == "Rejected" && notes == "" {
+		issues = append(issues, missingIssue("review-notes", "flag_review-notes"))
+	}
+	if len(issues) > 0 {
+		return errUsageFromIssues(issues)
+	}
+	client, err := activeAPIClient()
+	if err != nil {
+		return err
+	}
+	message, err := client.ReviewAssignedApprovalOrder(id, api.ApprovalOrderReviewRequest{
+		Status:      status,
+		ReviewNotes: notes,
+		ExpiresAt:   expiresAt,
+	})
+	if err != nil {
+		return cliErrFromAPI(err)
+	}
+	return writeOrderMessage("order_update_success", message)
+}
+
 func runAdminOrderApprove(cmd *cobra.Command, args []string) error {
 	id, err := requiredUintArg(args, "order_label_id", "id")
 	if err != nil {
@@ -282,6 +327,8 @@ func collectOrderEditRequest(cmd *cobra.Command, current *api.ApprovalOrder) (ap
 		TypeID:         typeID,
 		Reason:         reason,
 		ExtensionHours: hours,
+		AccountID:      current.Content.ApprovalOrderAccountID,
+		ExpiresAt:      current.Content.ApprovalOrderExpiresAt,
 	}, nil
 }
 
@@ -298,6 +345,8 @@ func reviewApprovalOrder(cmd *cobra.Command, id uint, status string) (message st
 	}
 	lockReq, lockEnabled, lockIssues := collectOrderLockRequest(cmd, nil)
 	issues = append(issues, lockIssues...)
+	expiresAt, expiresIssues := collectExpiresFlag(cmd)
+	issues = append(issues, expiresIssues...)
 	if len(issues) > 0 {
 		return "", "", false, errUsageFromIssues(issues)
 	}
@@ -328,6 +377,7 @@ func reviewApprovalOrder(cmd *cobra.Command, id uint, status string) (message st
 	message, err = client.ReviewApprovalOrder(id, api.ApprovalOrderReviewRequest{
 		Status:      status,
 		ReviewNotes: notes,
+		ExpiresAt:   expiresAt,
 	})
 	if err != nil {
 		return "", "", false, cliErrFromAPI(err)
@@ -419,6 +469,9 @@ func init() {
 	orderEditCmd.Flags().String("status", "Pending", "Approval order status")
 
 	orderCancelCmd.Flags().BoolP("yes", "y", false, "Skip confirmation")
+	orderApproveCmd.Flags().String("review-notes", "", "Review notes")
+	orderApproveCmd.Flags().String("expires", "", "Expiry of the granted dataset access (RFC3339 or YYYY-MM-DD), overriding the requested one")
+	orderRejectCmd.Flags().String("review-notes", "", "Review notes")
 
 	adminOrderApproveCmd.Flags().Bool("lock", false, "Lock the job before approving")
 	adminOrderApproveCmd.Flags().Bool("permanent", false, "Lock the job permanently")
@@ -426,6 +479,7 @@ func init() {
 	adminOrderApproveCmd.Flags().Int("hours", 0, "Lock duration hours")
 	adminOrderApproveCmd.Flags().Int("minutes", 0, "Lock duration minutes")
 	adminOrderApproveCmd.Flags().String("review-notes", "", "Review notes")
+	adminOrderApproveCmd.Flags().String("expires", "", "Expiry of the granted dataset access (RFC3339 or YYYY-MM-DD), overriding the requested one")
 	adminOrderRejectCmd.Flags().String("review-notes", "", "Review notes")
 	adminOrderCheckCmd.Flags().BoolP("yes", "y", false, "Skip confirmation")
 
@@ -433,7 +487,8 @@ func init() {
 	completion.RegisterFlagValue([]string{"order", "edit"}, "type", staticValueCompleter(orderTypes, nil))
 	completion.RegisterFlagValue([]string{"order", "edit"}, "status", staticValueCompleter(orderEditableStatus, nil))
 
-	orderCmd.AddCommand(orderLsCmd, orderGetCmd, orderByNameCmd, orderSubmitCmd, orderEditCmd, orderCancelCmd)
+	orderCmd.AddCommand(orderLsCmd, orderGetCmd, orderByNameCmd, orderSubmitCmd, orderEditCmd, orderCancelCmd,
+		orderAssignedCmd, orderApproveCmd, orderRejectCmd)
 	rootCmd.AddCommand(orderCmd)
 	adminOrderCmd.AddCommand(adminOrderLsCmd, adminOrderGetCmd, adminOrderApproveCmd, adminOrderRejectCmd, adminOrderCheckCmd)
 	adminCmd.AddCommand(adminOrderCmd)
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/raids-lab/crater/cli/internal/api"
+	"github.com/raids-lab/crater/cli/internal/i18n"
+	"github.com/spf13/cobra"
+)
+
+var shareCmd = &cobra.Command{
+	Use:   "share",
+	Short: "View time-limited dataset and image shares",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 0 {
+			return errUnknownSubcommand(cmd, args[0])
+		}
+		return cmd.Help()
+	},
+}
+var shareExpiringCmd = &cobra.Command{
+	Use:   "expiring",
+	Short: "List shares that are about to expire",
+	Long: "List time-limited shares of your datasets and images, and shares you or your queues receive, that expire within " +
+		"--days. Expired shares are removed by the revoke-expired-shares cron job.",
+	Args: noArgs,
+	RunE: runShareExpiring,
+}
+var adminShareCmd = &cobra.Command{Use: "share", Short: "View all time-limited shares"}
+var adminShareExpiringCmd = &cobra.Command{Use: "expiring", Short: "List all shares that are about to expire", Args: noArgs, RunE: runAdminShareExpiring}
+
+func runShareExpiring(cmd *cobra.Command, _ []string) error {
+	return runExpiringShares(cmd, api.SharesPrefix)
+}
+
+func runAdminShareExpiring(cmd *cobra.Command, _ []string) error {
+	return runExpiringShares(cmd, api.AdminSharesPrefix)
+}
+
+func runExpiringShares(cmd *cobra.Command, prefix string) error {
+	days, _ := cmd.Flags().GetInt("days")
+	if days < 1 || days > 365 {
+		return errUsageFromIssues([]usageIssue{invalidIssue("days", i18n.T("err_share_days", days))})
+	}
+	params := map[string]string{"days": strconv.Itoa(days)}
+	return runRawRead(cmd, rawReadSpec{
+		PayloadKey: "shares",
+		Path:       prefix + "/expiring",
+		Params:     func(*cobra.Command) map[string]string { return params },
+		Table:      printExpiringShareTable,
+	})
+}
+
+// collectExpiresFlag reads the optional --expires flag of commands that create time-limited shares.
+func collectExpiresFlag(cmd *cobra.Command) (*time.Time, []usageIssue) {
+	if cmd.Flags().Lookup("expires") == nil {
+		return nil, nil
+	}
+	value, _ := cmd.Flags().GetString("expires")
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	expiresAt, err := parseAPITime(value)
+	if err != nil {
+		return nil, []usageIssue{invalidIssue("expires", i18n.T("err_invalid_time", "expires", value))}
+	}
+	if !expiresAt.After(time.Now()) {
+		return nil, []usageIssue{invalidIssue("expires", i18n.T("err_expires_past", value))}
+	}
+	return &expiresAt, nil
+}
+
+func formatShareExpiry(expiresAt *time.Time) string {
+	if expiresAt == nil {
+		return "-"
+	}
+	return formatAPITime(*expiresAt)
+}
+
+func printExpiringShareTable(data interface{}) {
+	fmt.Printf("%s %s %s %s %s %s\n",
+		i18n.PadRight(i18n.T("table_type"), 10),
+		i18n.PadRight(i18n.T("table_id"), 8),
+		i18n.PadRight(i18n.T("table_name"), 32),
+		i18n.PadRight(i18n.T("share_table_target"), 10),
+		i18n.PadRight(i18n.T("share_table_target_name"), 20),
+		i18n.T("share_table_expires"))
+	for _, row := range rawList(data) {
+		fmt.Printf("%s %s %s %s %s %s\n",
+			i18n.PadRight(rawString(row, "resource"), 10),
+			i18n.PadRight(rawString(row, "resourceID"), 8),
+			i18n.PadRight(rawString(row, "resourceName"), 32),
+			i18n.PadRight(rawString(row, "target"), 10),
+			i18n.PadRight(rawString(row, "targetName"), 20),
+			rawString(row, "expiresAt"))
+	}
+}
+
+func init() {
+	shareExpiringCmd.Flags().Int("days", 7, "Look-ahead window in days")
+	adminShareExpiringCmd.Flags().Int("days", 7, "Look-ahead window in days")
+	shareCmd.AddCommand(shareExpiringCmd)
+	rootCmd.AddCommand(shareCmd)
+	adminShareCmd.AddCommand(adminShareExpiringCmd)
+	adminCmd.AddCommand(adminShareCmd)
+}
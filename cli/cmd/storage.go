@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/raids-lab/crater/cli/internal/api"
+	"github.com/raids-lab/crater/cli/internal/i18n"
+	"github.com/raids-lab/crater/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var storageCmd = &cobra.Command{
+	Use:   "storage",
+	Short: "View storage usage and upload files",
+	Long:  "View the usage and quotas of your user and account spaces on the shared filesystem, upload large files in resumable chunks, download directories as archives, restore deleted files from the trash, and get an access key for the S3 gateway.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 0 {
+			return errUnknownSubcommand(cmd, args[0])
+		}
+		return cmd.Help()
+	},
+}
+
+var storageUsageCmd = &cobra.Command{Use: "usage", Short: "Show usage and quotas of my user and account spaces", Args: noArgs, RunE: func(cmd *cobra.Command, _ []string) error {
+	return runRawRead(cmd, rawReadSpec{PayloadKey: "usage", Path: api.StoragePrefix + "/usage", Params: noParams, Table: printStorageUsageTable})
+}}
+var storageS3CredentialsCmd = &cobra.Command{Use: "s3-credentials", Short: "Show my S3 access key for the current account", Long: "Show the S3 access key of the current account. S3 clients such as boto3, rclone and aws s3 can use it against the storage server's S3 gateway with path-style addressing. The buckets are user, account and public. The key keeps working while you belong to the account, until you rotate it.", Args: noArgs, RunE: runStorageS3Credentials}
+var adminStorageCmd = &cobra.Command{Use: "storage", Short: "View and set storage quotas"}
+var adminStorageUsageCmd = &cobra.Command{Use: "usage", Short: "Show usage and quotas of all user or account spaces", Args: noArgs, RunE: runAdminStorageUsage}
+var adminStorageQuotaCmd = &cobra.Command{Use: "quota", Short: "Manage storage quotas"}
+var adminStorageQuotaSetCmd = &cobra.Command{Use: "set", Short: "Set the byte and inode quota of a user or account space", Args: noArgs, RunE: runAdminStorageQuotaSet}
+
+func runStorageS3Credentials(cmd *cobra.Command, _ []string) error {
+	if rotate, _ := cmd.Flags().GetBool("rotate"); !rotate {
+		return runRawRead(cmd, rawReadSpec{PayloadKey: "credentials", Path: api.StoragePrefix + "/s3/credentials", Params: noParams, Table: printS3Credentials})
+	}
+	client, err := activeAPIClient()
+	if err != nil {
+		return err
+	}
+	data, err := client.RotateS3Credentials()
+	if err != nil {
+		return cliErrFromAPI(err)
+	}
+	if outputJSON {
+		return output.WriteSuccessJSON(os.Stdout, output.SuccessEnvelope(map[string]interface{}{"credentials": data}))
+	}
+	printS3Credentials(data)
+	return nil
+}
+
+func runAdminStorageUsage(cmd *cobra.Command, _ []string) error {
+	accounts, _ := cmd.Flags().GetBool("accounts")
+	path, nameKey := api.StoragePrefix+"/userspace", "username"
+	if accounts {
+		path, nameKey = api.StoragePrefix+"/queuespace", "queuename"
+	}
+	return runRawRead(cmd, rawReadSpec{PayloadKey: "spaces", Path: path, Params: noParams, Table: func(data interface{}) {
+		spaces := rawList(data)
+		rows := make([]map[string]interface{}, 0, len(spaces))
+		for _, space := range spaces {
+			row := map[string]interface{}{"name": rawString(space, nameKey), "space": rawString(space, "space")}
+			for key, value := range rawMap(space["usage"]) {
+				if key != "space" {
+					row[key] = value
+				}
+			}
+			rows = append(rows, row)
+		}
+		printStorageUsageRows(rows, true)
+	}})
+}
+
+func runAdminStorageQuotaSet(cmd *cobra.Command, _ []string) error {
+	req, err := storageQuotaRequestFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	client, err := activeAPIClient()
+	if err != nil {
+		return err
+	}
+	data, err := client.SetStorageQuota(req)
+	if err != nil {
+		return cliErrFromAPI(err)
+	}
+	if outputJSON {
+		return output.WriteSuccessJSON(os.Stdout, output.SuccessEnvelope(map[string]interface{}{"usage": data}))
+	}
+	printStorageUsageRows([]map[string]interface{}{data}, false)
+	return nil
+}
+
+// storageQuotaRequestFromFlags requires exactly one of --user/--account and at least one limit.
+func storageQuotaRequestFromFlags(cmd *cobra.Command) (api.StorageQuotaRequest, error) {
+	var req api.StorageQuotaRequest
+	user := getStringParam(cmd, "user")
+	account := getStringParam(cmd, "account")
+	switch {
+	case user != "" && account != "":
+		return req, errUsageFromIssues([]usageIssue{invalidIssue("account", i18n.T("err_mutually_exclusive_flags", "user", "account"))})
+	case user != "":
+		req.Kind, req.Name = "user", user
+	case account != "":
+		req.Kind, req.Name = "account", account
+	default:
+		return req, errUsageFromIssues([]usageIssue{missingIssue("user", "storage_label_space")})
+	}
+
+	var issues []usageIssue
+	if cmd.Flags().Changed("bytes") {
+		raw := getStringParam(cmd, "bytes")
+		size, err := parseByteSize(raw)
+		if err != nil {
+			issues = append(issues, invalidIssue("bytes", i18n.T("err_invalid_byte_size", raw)))
+		} else {
+			req.MaxBytes = &size
+		}
+	}
+	if cmd.Flags().Changed("inodes") {
+		inodes, _ := cmd.Flags().GetInt64("inodes")
+		if inodes < 0 {
+			issues = append(issues, invalidIssue("inodes", i18n.T("err_invalid_inode_quota", inodes)))
+		} else {
+			req.MaxInodes = &inodes
+		}
+	}
+	if len(issues) > 0 {
+		return req, errUsageFromIssues(issues)
+	}
+	if req.MaxBytes == nil && req.MaxInodes == nil {
+		return req, errUsageFromIssues([]usageIssue{missingIssue("bytes", "storage_label_limit")})
+	}
+	return req, nil
+}
+
+var byteSizeUnits = []struct {
+	suffix string
+	factor int64
+}{
+	{"Ti", 1 << 40}, {"Gi", 1 << 30}, {"Mi", 1 << 20}, {"Ki", 1 << 10},
+	{"T", 1e12}, {"G", 1e9}, {"M", 1e6}, {"K", 1e3},
+}
+
+// parseByteSize accepts plain byte counts and Kubernetes-style suffixes such as 500Gi or 2T.
+func parseByteSize(raw string) (int64, error) {
+	value := strings.TrimSuffix(strings.TrimSpace(raw), "B")
+	factor := int64(1)
+	for _, unit := range byteSizeUnits {
+		if trimmed, ok := strings.CutSuffix(value, unit.suffix); ok {
+			value, factor = trimmed, unit.factor
+			break
+		}
+	}
+	number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || number < 0 || math.IsInf(number, 0) || number*float64(factor) > math.MaxInt64 {
+		return 0, fmt.Errorf("invalid byte size %q", raw)
+	}
+	return int64(number * float64(factor)), nil
+}
+
+// formatBytes renders a byte count with binary units.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return strconv.FormatInt(n, 10) + "B"
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func printStorageUsageTable(data interface{}) {
+	printStorageUsageRows(rawList(data), false)
+}
+
+func printStorageUsageRows(rows []map[string]interface{}, withName bool) {
+	header := ""
+	if withName {
+		header = i18n.PadRight("NAME", 20) + " "
+	}
+	fmt.Printf("%s%s %s %s %s %s\n", header,
+		i18n.PadRight(i18n.T("storage_table_space"), 28),
+		i18n.PadRight(i18n.T("storage_table_kind"), 8),
+		i18n.PadRight(i18n.T("storage_table_bytes"), 24),
+		i18n.PadRight(i18n.T("storage_table_inodes"), 20),
+		i18n.T("storage_table_scanned"))
+	for _, row := range rows {
+		prefix := ""
+		if withName {
+			prefix = i18n.PadRight(emptyDash(rawString(row, "name")), 20) + " "
+		}
+		fmt.Printf("%s%s %s %s %s %s\n", prefix,
+			i18n.PadRight(emptyDash(rawString(row, "space")), 28),
+			i18n.PadRight(emptyDash(rawString(row, "kind")), 8),
+			i18n.PadRight(storageUsageCell(row, "usedBytes", "maxBytes", formatBytes), 24),
+			i18n.PadRight(storageUsageCell(row, "usedInodes", "maxInodes", func(n int64) string { return strconv.FormatInt(n, 10) }), 20),
+			formatStorageScannedAt(rawString(row, "scannedAt")))
+	}
+}
+
+// storageUsageCell renders "used / limit (percent)", or just the usage of an unlimited space.
+func storageUsageCell(row map[string]interface{}, usedKey, maxKey string, format func(int64) string) string {
+	used, _ := row[usedKey].(float64)
+	limit, limited := row[maxKey].(float64)
+	if !limited || limit <= 0 {
+		return format(int64(used))
+	}
+	return fmt.Sprintf("%s / %s (%.0f%%)", format(int64(used)), format(int64(limit)), used/limit*100)
+}
+
+// printS3Credentials prints the key and the environment variables S3 clients read.
+func printS3Credentials(data interface{}) {
+	creds := rawMap(data)
+	fmt.Printf("%s %s\n", i18n.PadRight("ACCESS KEY", 12), rawString(creds, "accessKeyId"))
+	fmt.Printf("%s %s\n", i18n.PadRight("SECRET KEY", 12), rawString(creds, "secretAccessKey"))
+	fmt.Printf("%s %s\n", i18n.PadRight(i18n.T("storage_s3_endpoint"), 12), emptyDash(rawString(creds, "endpoint")))
+	var buckets []string
+	if list, ok := creds["buckets"].([]interface{}); ok {
+		for _, bucket := range list {
+			buckets = append(buckets, fmt.Sprint(bucket))
+		}
+	}
+	fmt.Printf("%s %s\n\n", i18n.PadRight(i18n.T("storage_s3_buckets"), 12), emptyDash(strings.Join(buckets, ", ")))
+	fmt.Println(i18n.T("storage_s3_env_hint"))
+	fmt.Printf("export AWS_ACCESS_KEY_ID=%s\n", rawString(creds, "accessKeyId"))
+	fmt.Printf("export AWS_SECRET_ACCESS_KEY=%s\n", rawString(creds, "secretAccessKey"))
+	fmt.Printf("export AWS_DEFAULT_REGION=%s\n", rawString(creds, "region"))
+	if endpoint := rawString(creds, "endpoint"); endpoint != "" {
+		fmt.Printf("export AWS_ENDPOINT_URL=%s\n", endpoint)
+	}
+}
+
+func formatStorageScannedAt(raw string) string {
+	scanned, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return i18n.T("storage_not_scanned")
+	}
+	return scanned.Local().Format("2006-01-02 15:04")
+}
+
+func init() {
+	storageS3CredentialsCmd.Flags().Bool("rotate", false, "Replace the key with a new one; the old key stops working right away")
+	adminStorageUsageCmd.Flags().Bool("accounts", false, "List account spaces instead of user spaces")
+	adminStorageQuotaSetCmd.Flags().String("user", "", "Username whose user space is limited")
+	adminStorageQuotaSetCmd.Flags().String("account", "", "Account name whose account space is limited")
+	adminStorageQuotaSetCmd.Flags().String("bytes", "", "Byte quota, e.g. 500Gi or 2T; 0 removes the limit")
+	adminStorageQuotaSetCmd.Flags().Int64("inodes", 0, "Maximum number of files and directories; 0 removes the limit")
+	adminStorageQuotaCmd.AddCommand(adminStorageQuotaSetCmd)
+	adminStorageCmd.AddCommand(adminStorageUsageCmd, adminStorageQuotaCmd)
+	adminCmd.AddCommand(adminStorageCmd)
+	storageCmd.AddCommand(storageUsageCmd, storageS3CredentialsCmd)
+	rootCmd.AddCommand(storageCmd)
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/raids-lab/crater/cli/internal/i18n"
+	"github.com/raids-lab/crater/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var storagePullCmd = &cobra.Command{
+	Use:   "pull <path>",
+	Short: "Download a file or directory as one archive",
+	Long: "Download a storage path such as user/results in a single streamed archive and unpack it into the output directory. " +
+		"With --archive the tar.gz or zip file is saved as is instead. Existing local files are kept unless --force is set.",
+	Args: exactArgs(1, "path"),
+	RunE: runStoragePull,
+}
+
+func runStoragePull(cmd *cobra.Command, args []string) error {
+	archivePath := getStringParam(cmd, "archive")
+	outputDir := getStringParam(cmd, "output")
+	force, _ := cmd.Flags().GetBool("force")
+	format := "tar.gz"
+	if strings.HasSuffix(strings.ToLower(archivePath), ".zip") {
+		format = "zip"
+	}
+
+	client, err := activeAPIClient()
+	if err != nil {
+		return err
+	}
+	body, size, err := client.DownloadArchive(args[0], format)
+	if err != nil {
+		return cliErrFromAPI(err)
+	}
+	defer body.Close()
+
+	result := map[string]interface{}{"path": args[0], "bytes": size}
+	if archivePath != "" {
+		written, err := saveArchive(body, archivePath, force)
+		if err != nil {
+			return err
+		}
+		result["archive"], result["archiveBytes"] = archivePath, written
+		if outputJSON {
+			return output.WriteSuccessJSON(os.Stdout, output.SuccessEnvelope(map[string]interface{}{"pull": result}))
+		}
+		fmt.Println(i18n.T("storage_pull_saved", archivePath, formatBytes(written)))
+		return nil
+	}
+	files, written, err := extractTarGz(body, outputDir, force)
+	if err != nil {
+		return err
+	}
+	result["output"], result["files"], result["bytes"] = outputDir, files, written
+	if outputJSON {
+		return output.WriteSuccessJSON(os.Stdout, output.SuccessEnvelope(map[string]interface{}{"pull": result}))
+	}
+	fmt.Println(i18n.T("storage_pull_done", files, formatBytes(written), outputDir))
+	return nil
+}
+
+func saveArchive(body io.Reader, archivePath string, force bool) (int64, error) {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if force {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	f, err := os.OpenFile(archivePath, flags, 0o644)
+	if errors.Is(err, os.ErrExist) {
+		return 0, errUsageFromIssues([]usageIssue{invalidIssue("archive", i18n.T("err_pull_exists", archivePath))})
+	} else if err != nil {
+		return 0, err
+	}
+	written, err := io.Copy(f, body)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return written, err
+}
+
+// extractTarGz unpacks directories and regular files below dest. Entries that would land
+// outside dest, links and special files are rejected.
+func extractTarGz(r io.Reader, dest string, force bool) (files int, written int64, err error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return 0, 0, fmt.Errorf("read archive: %w", err)
+	}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return files, written, nil
+		} else if err != nil {
+			return files, written, fmt.Errorf("read archive: %w", err)
+		}
+		target, err := archiveTarget(dest, header.Name)
+		if err != nil {
+			return files, written, err
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err = os.MkdirAll(target, 0o755); err != nil {
+				return files, written, err
+			}
+		case tar.TypeReg:
+			n, err := extractFile(tr, target, force)
+			if err != nil {
+				return files, written, err
+			}
+			files++
+			written += n
+		default:
+			return files, written, fmt.Errorf("archive entry %s is not a file or directory", header.Name)
+		}
+	}
+}
+
+func archiveTarget(dest, name string) (string, error) {
+	cleaned := path.Clean("/" + name)
+	if cleaned == "/" || strings.Contains(name, "\\") {
+		return "", fmt.Errorf("unsafe archive entry %q", name)
+	}
+	for _, segment := range strings.Split(strings.Trim(name, "/"), "/") {
+		if segment == ".." {
+			return "", fmt.Errorf("unsafe archive entry %q", name)
+		}
+	}
+	return filepath.Join(dest, filepath.FromSlash(cleaned)), nil
+}
+
+func extractFile(r io.Reader, target string, force bool) (int64, error) {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return 0, err
+	}
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if force {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	f, err := os.OpenFile(target, flags, 0o644)
+	if errors.Is(err, os.ErrExist) {
+		return 0, errUsageFromIssues([]usageIssue{invalidIssue("output", i18n.T("err_pull_exists", target))})
+	} else if err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(f, r)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return n, err
+}
+
+func init() {
+	storagePullCmd.Flags().StringP("output", "o", ".", "Directory to unpack into")
+	storagePullCmd.Flags().String("archive", "", "Save the archive to this file instead of unpacking; a .zip name requests zip")
+	storagePullCmd.Flags().Bool("force", false, "Overwrite existing local files")
+	storageCmd.AddCommand(storagePullCmd)
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type tarEntry struct {
+	name string
+	dir  bool
+	body string
+	link bool
+}
+
+func buildTarGz(t *testing.T, entries []tarEntry) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, e := range entries {
+		header := &tar.Header{Name: e.name, Mode: 0o644, Typeflag: tar.TypeReg, Size: int64(len(e.body))}
+		switch {
+		case e.dir:
+			header.Typeflag, header.Mode, header.Size = tar.TypeDir, 0o755, 0
+		case e.link:
+			header.Typeflag, header.Linkname, header.Size = tar.TypeSymlink, "/etc/passwd", 0
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if header.Size > 0 {
+			if _, err := tw.Write([]byte(e.body)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &buf
+}
+
+func TestExtractTarGz(t *testing.T) {
+	dest := t.TempDir()
+	archive := buildTarGz(t, []tarEntry{
+		{name: "results/", dir: true},
+		{name: "results/a.txt", body: "hello"},
+		{name: "results/sub/b.txt", body: "world!"},
+	})
+	files, written, err := extractTarGz(archive, dest, false)
+	if err != nil {
+		t.Fatalf("extract: %v", err)
+	}
+	if files != 2 || written != 11 {
+		t.Fatalf("got %d files and %d bytes, want 2 and 11", files, written)
+	}
+	data, err := os.ReadFile(filepath.Join(dest, "results", "sub", "b.txt"))
+	if err != nil || string(data) != "world!" {
+		t.Fatalf("read b.txt: %q %v", data, err)
+	}
+}
+
+func TestExtractTarGzKeepsExistingFiles(t *testing.T) {
+	dest := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dest, "a.txt"), []byte("mine"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	entries := []tarEntry{{name: "a.txt", body: "theirs"}}
+	if _, _, err := extractTarGz(buildTarGz(t, entries), dest, false); err == nil {
+		t.Fatal("expected an error for an existing file")
+	}
+	if _, _, err := extractTarGz(buildTarGz(t, entries), dest, true); err != nil {
+		t.Fatalf("extract with force: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dest, "a.txt")); string(data) != "theirs" {
+		t.Fatalf("a.txt = %q, want it overwritten", data)
+	}
+}
+
+func TestExtractTarGzRejectsUnsafeEntries(t *testing.T) {
+	for _, entry := range []tarEntry{
+		{name: "../escape.txt", body: "x"},
+		{name: "a/../../escape.txt", body: "x"},
+		{name: "link", link: true},
+	} {
+		dest := t.TempDir()
+		if _, _, err := extractTarGz(buildTarGz(t, []tarEntry{entry}), filepath.Join(dest, "out"), false); err == nil {
+			t.Errorf("%s: expected an error", entry.name)
+		}
+		if _, err := os.Stat(filepath.Join(dest, "escape.txt")); err == nil {
+			t.Errorf("%s: file escaped the output directory", entry.name)
+		}
+	}
+}
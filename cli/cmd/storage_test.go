@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestParseByteSize(t *testing.T) {
+	cases := map[string]int64{"0": 0, "1024": 1024, "500Gi": 500 << 30, "2T": 2e12, "1.5Ki": 1536, "10MiB": 10 << 20}
+	for raw, want := range cases {
+		got, err := parseByteSize(raw)
+		if err != nil || got != want {
+			t.Fatalf("parseByteSize(%q) = %d, %v; want %d", raw, got, err, want)
+		}
+	}
+	for _, raw := range []string{"", "abc", "-1Gi", "1Xi"} {
+		if _, err := parseByteSize(raw); err == nil {
+			t.Fatalf("parseByteSize(%q) should fail", raw)
+		}
+	}
+}
+
+func TestStorageQuotaRequestFromFlags(t *testing.T) {
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{Use: "set"}
+		cmd.Flags().String("user", "", "")
+		cmd.Flags().String("account", "", "")
+		cmd.Flags().String("bytes", "", "")
+		cmd.Flags().Int64("inodes", 0, "")
+		if err := cmd.Flags().Parse(args); err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		return cmd
+	}
+
+	req, err := storageQuotaRequestFromFlags(newCmd("--user", "alice", "--bytes", "1Gi"))
+	if err != nil || req.Kind != "user" || req.Name != "alice" || req.MaxBytes == nil || *req.MaxBytes != 1<<30 || req.MaxInodes != nil {
+		t.Fatalf("req = %+v, err = %v", req, err)
+	}
+	req, err = storageQuotaRequestFromFlags(newCmd("--account", "q1", "--inodes", "0"))
+	if err != nil || req.Kind != "account" || req.MaxInodes == nil || *req.MaxInodes != 0 {
+		t.Fatalf("--inodes 0 should clear the limit, req = %+v, err = %v", req, err)
+	}
+	for _, args := range [][]string{
+		{"--bytes", "1Gi"},
+		{"--user", "alice", "--account", "q1", "--bytes", "1Gi"},
+		{"--user", "alice"},
+		{"--user", "alice", "--inodes", "-1"},
+		{"--user", "alice", "--bytes", "lots"},
+	} {
+		if _, err := storageQuotaRequestFromFlags(newCmd(args...)); err == nil {
+			t.Fatalf("args %v should be rejected", args)
+		}
+	}
+}
+
+func TestStorageUsageCell(t *testing.T) {
+	row := map[string]interface{}{"usedBytes": float64(512 << 20), "maxBytes": float64(1 << 30), "usedInodes": float64(42)}
+	if got := storageUsageCell(row, "usedBytes", "maxBytes", formatBytes); got != "512.0MiB / 1.0GiB (50%)" {
+		t.Fatalf("bytes cell = %q", got)
+	}
+	if got := storageUsageCell(row, "usedInodes", "maxInodes", formatBytes); got != "42B" {
+		t.Fatalf("unlimited cell = %q", got)
+	}
+}
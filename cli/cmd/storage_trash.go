@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/raids-lab/crater/cli/internal/api"
+	"github.com/raids-lab/crater/cli/internal/i18n"
+	"github.com/raids-lab/crater/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var storageTrashCmd = &cobra.Command{Use: "trash", Short: "List and restore deleted files"}
+var storageTrashLsCmd = &cobra.Command{Use: "ls", Short: "List deleted files of my user and account spaces", Args: noArgs, RunE: func(cmd *cobra.Command, _ []string) error {
+	return runRawRead(cmd, rawReadSpec{PayloadKey: "items", Path: api.StoragePrefix + "/trash", Params: noParams, Table: printStorageTrashTable})
+}}
+var storageTrashRestoreCmd = &cobra.Command{Use: "restore <id>", Short: "Restore a deleted file or directory", Args: exactArgs(1, "id"), RunE: runStorageTrashRestore}
+
+func runStorageTrashRestore(cmd *cobra.Command, args []string) error {
+	id, err := strconv.ParseUint(args[0], 10, 0)
+	if err != nil || id == 0 {
+		return errUsageFromIssues([]usageIssue{invalidIssue("id", i18n.T("err_invalid_trash_id", args[0]))})
+	}
+	client, err := activeAPIClient()
+	if err != nil {
+		return err
+	}
+	data, err := client.RestoreTrash(uint(id), getStringParam(cmd, "to"))
+	if err != nil {
+		return cliErrFromAPI(err)
+	}
+	if outputJSON {
+		return output.WriteSuccessJSON(os.Stdout, output.SuccessEnvelope(map[string]interface{}{"item": data}))
+	}
+	fmt.Println(i18n.T("storage_trash_restored", rawString(data, "path")))
+	return nil
+}
+
+func printStorageTrashTable(data interface{}) {
+	fmt.Printf("%s %s %s %s %s\n",
+		i18n.PadRight("ID", 8),
+		i18n.PadRight(i18n.T("storage_table_path"), 40),
+		i18n.PadRight(i18n.T("storage_table_bytes"), 12),
+		i18n.PadRight(i18n.T("storage_table_deleted"), 18),
+		i18n.T("storage_table_expires"))
+	for _, item := range rawList(data) {
+		id, _ := item["id"].(float64)
+		bytes, _ := item["bytes"].(float64)
+		path := rawString(item, "path")
+		if isDir, _ := item["isDir"].(bool); isDir {
+			path += "/"
+		}
+		fmt.Printf("%s %s %s %s %s\n",
+			i18n.PadRight(strconv.FormatInt(int64(id), 10), 8),
+			i18n.PadRight(emptyDash(path), 40),
+			i18n.PadRight(formatBytes(int64(bytes)), 12),
+			i18n.PadRight(formatTrashTime(rawString(item, "trashedAt")), 18),
+			formatTrashTime(rawString(item, "expiresAt")))
+	}
+}
+
+func formatTrashTime(raw string) string {
+	at, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return "-"
+	}
+	return at.Local().Format("2006-01-02 15:04")
+}
+
+func init() {
+	storageTrashRestoreCmd.Flags().String("to", "", "Restore to this path, e.g. user/restored/a.txt, instead of the original one")
+	storageTrashCmd.AddCommand(storageTrashLsCmd, storageTrashRestoreCmd)
+	storageCmd.AddCommand(storageTrashCmd)
+}
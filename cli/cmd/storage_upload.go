@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/raids-lab/crater/cli/internal/api"
+	"github.com/raids-lab/crater/cli/internal/i18n"
+	"github.com/raids-lab/crater/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var storageUploadCmd = &cobra.Command{
+	Use:   "upload <local-file> <remote-path>",
+	Short: "Upload a file in resumable chunks",
+	Long: "Upload a local file to a storage path such as user/ckpt/model.bin in resumable chunks. " +
+		"A remote path ending in / keeps the local file name. Rerunning the same upload after an interruption " +
+		"continues from the last chunk the server received.",
+	Args: exactArgs(2, "local-file", "remote-path"),
+	RunE: runStorageUpload,
+}
+
+// uploadClient is the part of the API client used by resumable uploads.
+type uploadClient interface {
+	ListUploads() ([]api.Upload, error)
+	CreateUpload(req api.CreateUploadRequest) (*api.Upload, error)
+	GetUpload(id string) (*api.Upload, error)
+	PatchUpload(id string, offset int64, chunk []byte) (*api.Upload, error)
+	CompleteUpload(id string) (*api.Upload, error)
+}
+
+// uploadRetryDelay is the wait before the nth retry of a failed chunk.
+var uploadRetryDelay = func(attempt int) time.Duration {
+	return time.Duration(attempt) * 2 * time.Second
+}
+
+func runStorageUpload(cmd *cobra.Command, args []string) error {
+	chunkRaw := getStringParam(cmd, "chunk-size")
+	chunkSize, err := parseByteSize(chunkRaw)
+	if err != nil || chunkSize <= 0 {
+		return errUsageFromIssues([]usageIssue{invalidIssue("chunk-size", i18n.T("err_invalid_byte_size", chunkRaw))})
+	}
+	retries, _ := cmd.Flags().GetInt("retries")
+
+	file, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	stat, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if stat.IsDir() {
+		return errUsageFromIssues([]usageIssue{invalidIssue("local-file", i18n.T("err_upload_not_a_file", args[0]))})
+	}
+	sum, err := fileSHA256(file)
+	if err != nil {
+		return err
+	}
+
+	client, err := activeAPIClient()
+	if err != nil {
+		return err
+	}
+	req := api.CreateUploadRequest{Path: uploadRemotePath(args[1], args[0]), Size: stat.Size(), SHA256: sum}
+	progress := func(offset, size int64) {
+		if !outputJSON {
+			fmt.Fprintf(os.Stderr, "\r%s / %s", formatBytes(offset), formatBytes(size))
+		}
+	}
+	upload, resumed, err := resumableUpload(client, file, req, chunkSize, retries, progress)
+	if !outputJSON && stat.Size() > 0 {
+		fmt.Fprintln(os.Stderr)
+	}
+	if err != nil {
+		return cliErrFromAPI(err)
+	}
+	if outputJSON {
+		return output.WriteSuccessJSON(os.Stdout, output.SuccessEnvelope(map[string]interface{}{"upload": upload, "resumed": resumed}))
+	}
+	if resumed {
+		fmt.Println(i18n.T("storage_upload_resumed"))
+	}
+	fmt.Println(i18n.T("storage_upload_done", upload.Path, formatBytes(upload.Size), upload.SHA256))
+	return nil
+}
+
+// uploadRemotePath normalizes the remote path the way the server stores it.
+func uploadRemotePath(remote, local string) string {
+	if strings.HasSuffix(remote, "/") {
+		remote += filepath.Base(local)
+	}
+	cleaned := path.Clean("/" + strings.ReplaceAll(remote, "\\", "/"))
+	return strings.TrimPrefix(cleaned, "/")
+}
+
+func fileSHA256(file *os.File) (string, error) {
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// resumableUpload continues an unfinished upload of the same file to the same path, or
+// starts a new one, then sends the remaining chunks. Failed chunks are retried after
+// asking the server for its offset, since part of a chunk may have been stored.
+func resumableUpload(client uploadClient, file io.ReaderAt, req api.CreateUploadRequest, chunkSize int64,
+	retries int, progress func(offset, size int64)) (*api.Upload, bool, error) {
+	upload, resumed, err := findOrCreateUpload(client, req)
+	if err != nil {
+		return nil, false, err
+	}
+	buf := make([]byte, chunkSize)
+	attempt := 0
+	for upload.Offset < upload.Size {
+		progress(upload.Offset, upload.Size)
+		n, readErr := file.ReadAt(buf[:min(chunkSize, upload.Size-upload.Offset)], upload.Offset)
+		if readErr != nil && !errors.Is(readErr, io.EOF) {
+			return nil, resumed, readErr
+		}
+		if n == 0 {
+			return nil, resumed, fmt.Errorf("local file is shorter than the upload (%d of %d bytes)", upload.Offset, upload.Size)
+		}
+		next, err := client.PatchUpload(upload.ID, upload.Offset, buf[:n])
+		if err == nil {
+			upload, attempt = next, 0
+			continue
+		}
+		if !retryableUploadError(err) || attempt >= retries {
+			return nil, resumed, err
+		}
+		attempt++
+		time.Sleep(uploadRetryDelay(attempt))
+		if synced, syncErr := client.GetUpload(upload.ID); syncErr == nil {
+			upload = synced
+		}
+	}
+	progress(upload.Offset, upload.Size)
+	done, err := client.CompleteUpload(upload.ID)
+	return done, resumed, err
+}
+
+func findOrCreateUpload(client uploadClient, req api.CreateUploadRequest) (*api.Upload, bool, error) {
+	uploads, err := client.ListUploads()
+	if err != nil {
+		return nil, false, err
+	}
+	for i := range uploads {
+		u := uploads[i]
+		if u.Path == req.Path && u.Size == req.Size && u.SHA256 == req.SHA256 {
+			return &u, true, nil
+		}
+	}
+	upload, err := client.CreateUpload(req)
+	return upload, false, err
+}
+
+// retryableUploadError covers dropped connections, server errors and offset conflicts.
+func retryableUploadError(err error) bool {
+	var netErr *api.NetworkError
+	if errors.As(err, &netErr) {
+		return true
+	}
+	var reqErr *api.RequestError
+	if errors.As(err, &reqErr) {
+		return reqErr.HTTPStatus == http.StatusConflict || reqErr.HTTPStatus >= http.StatusInternalServerError
+	}
+	return false
+}
+
+func init() {
+	storageUploadCmd.Flags().String("chunk-size", "8Mi", "Chunk size, e.g. 8Mi or 64Mi")
+	storageUploadCmd.Flags().Int("retries", 5, "Retries of a failed chunk before giving up")
+	storageCmd.AddCommand(storageUploadCmd)
+}
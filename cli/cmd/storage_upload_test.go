@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/raids-lab/crater/cli/internal/api"
+)
+
+// flakyUploadServer keeps the upload in memory and drops the connection on every other chunk
+// after storing half of it, like a proxy cutting a transfer short.
+type flakyUploadServer struct {
+	uploads []api.Upload
+	data    bytes.Buffer
+	patches int
+	created int
+}
+
+func (s *flakyUploadServer) ListUploads() ([]api.Upload, error) { return s.uploads, nil }
+
+func (s *flakyUploadServer) CreateUpload(req api.CreateUploadRequest) (*api.Upload, error) {
+	s.created++
+	s.uploads = []api.Upload{{ID: "u1", Path: req.Path, Size: req.Size, SHA256: req.SHA256}}
+	return &s.uploads[0], nil
+}
+
+func (s *flakyUploadServer) GetUpload(string) (*api.Upload, error) {
+	u := s.uploads[0]
+	u.Offset = int64(s.data.Len())
+	return &u, nil
+}
+
+func (s *flakyUploadServer) PatchUpload(_ string, offset int64, chunk []byte) (*api.Upload, error) {
+	s.patches++
+	if offset != int64(s.data.Len()) {
+		return nil, &api.RequestError{HTTPStatus: http.StatusConflict}
+	}
+	if s.patches%2 == 0 {
+		s.data.Write(chunk[:len(chunk)/2])
+		return nil, &api.NetworkError{Cause: errors.New("connection reset")}
+	}
+	s.data.Write(chunk)
+	return s.GetUpload("")
+}
+
+func (s *flakyUploadServer) CompleteUpload(string) (*api.Upload, error) { return s.GetUpload("") }
+
+func noUploadRetryDelay(t *testing.T) {
+	saved := uploadRetryDelay
+	uploadRetryDelay = func(int) time.Duration { return 0 }
+	t.Cleanup(func() { uploadRetryDelay = saved })
+}
+
+func TestResumableUploadRetriesPartialChunks(t *testing.T) {
+	noUploadRetryDelay(t)
+
+	content := strings.Repeat("0123456789", 10)
+	server := &flakyUploadServer{}
+	req := api.CreateUploadRequest{Path: "user/a.bin", Size: int64(len(content)), SHA256: "sum"}
+	upload, resumed, err := resumableUpload(server, strings.NewReader(content), req, 16, 2, func(int64, int64) {})
+	if err != nil || resumed {
+		t.Fatalf("resumableUpload = %+v, %v, %v", upload, resumed, err)
+	}
+	if server.data.String() != content || upload.Offset != upload.Size {
+		t.Fatalf("server has %q (offset %d), want the full content", server.data.String(), upload.Offset)
+	}
+
+	// A second run for the same file finds the existing upload instead of starting over.
+	if _, resumed, err = resumableUpload(server, strings.NewReader(content), req, 16, 2, func(int64, int64) {}); err != nil || !resumed {
+		t.Fatalf("second run resumed = %v, err = %v", resumed, err)
+	}
+	if server.created != 1 {
+		t.Fatalf("created %d uploads, want 1", server.created)
+	}
+}
+
+func TestResumableUploadGivesUp(t *testing.T) {
+	noUploadRetryDelay(t)
+
+	server := &flakyUploadServer{}
+	req := api.CreateUploadRequest{Path: "user/a.bin", Size: 100, SHA256: "sum"}
+	// A 100-byte chunk fails on the second attempt of every chunk; with no retries the first failure is final.
+	server.patches = 1
+	if _, _, err := resumableUpload(server, strings.NewReader(strings.Repeat("x", 100)), req, 100, 0, func(int64, int64) {}); err == nil {
+		t.Fatal("upload should fail once retries are exhausted")
+	}
+}
+
+func TestUploadRemotePath(t *testing.T) {
+	cases := map[[2]string]string{
+		{"user/ckpt/", "/tmp/model.bin"}: "user/ckpt/model.bin",
+		{"/user//a/../b.bin", "x"}:       "user/b.bin",
+		{"account/data.tar", "x"}:        "account/data.tar",
+	}
+	for in, want := range cases {
+		if got := uploadRemotePath(in[0], in[1]); got != want {
+			t.Fatalf("uploadRemotePath(%q, %q) = %q, want %q", in[0], in[1], got, want)
+		}
+	}
+}
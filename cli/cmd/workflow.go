@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/raids-lab/crater/cli/internal/api"
+	"github.com/raids-lab/crater/cli/internal/i18n"
+	"github.com/raids-lab/crater/cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var (
+	workflowJobTypes   = []string{"custom", "pytorch"}
+	workflowConditions = []string{"success", "completion"}
+)
+
+var workflowCmd = &cobra.Command{
+	Use:   "workflow",
+	Short: "Manage job workflows",
+	Long:  "Submit vcjobs as a DAG whose downstream jobs start after their parents finish.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 0 {
+			return errUnknownSubcommand(cmd, args[0])
+		}
+		return cmd.Help()
+	},
+}
+
+var workflowLsCmd = &cobra.Command{Use: "ls", Short: "List workflows", Args: noArgs, RunE: runWorkflowLs}
+var workflowGetCmd = &cobra.Command{Use: "get <id>", Short: "Get a workflow and its nodes", Args: exactArgs(1, "id"), RunE: runWorkflowGet}
+var workflowCreateCmd = &cobra.Command{Use: "create", Short: "Create a workflow from a JSON file", Args: noArgs, RunE: runWorkflowCreate}
+var workflowCancelCmd = &cobra.Command{Use: "cancel <id>", Short: "Cancel a workflow", Args: exactArgs(1, "id"), RunE: runWorkflowCancel}
+
+func runWorkflowLs(cmd *cobra.Command, _ []string) error {
+	return runRawRead(cmd, rawReadSpec{PayloadKey: "workflows", Path: api.WorkflowsPrefix, Params: noParams, Table: printWorkflowTable})
+}
+
+func runWorkflowGet(cmd *cobra.Command, args []string) error {
+	id, err := requiredUintArg(args, "workflow_label_id", "id")
+	if err != nil {
+		return err
+	}
+	return runRawRead(cmd, rawReadSpec{PayloadKey: "workflow", Path: api.WorkflowsPrefix + "/" + api.UintPath(id), Params: noParams, Table: printWorkflowDetail})
+}
+
+func runWorkflowCreate(cmd *cobra.Command, _ []string) error {
+	var req api.CreateWorkflowRequest
+	if err := readJSONFlag(cmd, &req); err != nil {
+		return err
+	}
+	if err := validateWorkflowRequest(req); err != nil {
+		return err
+	}
+	client, err := activeAPIClient()
+	if err != nil {
+		return err
+	}
+	data, err := client.CreateWorkflow(req)
+	return writeWorkflowResult("workflow_create_success", data, err)
+}
+
+func runWorkflowCancel(cmd *cobra.Command, args []string) error {
+	id, err := requiredUintArg(args, "workflow_label_id", "id")
+	if err != nil {
+		return err
+	}
+	if err := requireConfirmation(cmd); err != nil {
+		return err
+	}
+	client, err := activeAPIClient()
+	if err != nil {
+		return err
+	}
+	data, err := client.CancelWorkflow(id)
+	return writeWorkflowResult("workflow_cancel_success", data, err)
+}
+
+// validateWorkflowRequest only checks the DAG shape; node specs are validated by the
+// backend with the same rules as `job create custom|pytorch`.
+func validateWorkflowRequest(req api.CreateWorkflowRequest) error {
+	var issues []usageIssue
+	if strings.TrimSpace(req.Name) == "" {
+		issues = append(issues, missingIssue("name", "workflow_label_name"))
+	}
+	if len(req.Nodes) == 0 {
+		issues = append(issues, missingIssue("nodes", "workflow_label_nodes"))
+	}
+	for i, node := range req.Nodes {
+		field := fmt.Sprintf("nodes[%d]", i)
+		if strings.TrimSpace(node.Name) == "" {
+			issues = append(issues, missingIssue(field+".name", "workflow_label_node_name"))
+		}
+		if !slices.Contains(workflowJobTypes, node.JobType) {
+			issues = append(issues, invalidIssue(field+".jobType", i18n.T("err_invalid_workflow_job_type", node.JobType)))
+		}
+		if len(node.Spec) == 0 {
+			issues = append(issues, missingIssue(field+".spec", "workflow_label_node_spec"))
+		}
+		for _, dep := range node.DependsOn {
+			if dep.Condition != "" && !slices.Contains(workflowConditions, dep.Condition) {
+				issues = append(issues, invalidIssue(field+".dependsOn", i18n.T("err_invalid_workflow_condition", dep.Condition)))
+			}
+		}
+	}
+	if len(issues) > 0 {
+		return errUsageFromIssues(issues)
+	}
+	return nil
+}
+
+func writeWorkflowResult(key string, data map[string]interface{}, err error) error {
+	if err != nil {
+		return cliErrFromAPI(err)
+	}
+	if outputJSON {
+		return output.WriteSuccessJSON(os.Stdout, output.SuccessEnvelope(map[string]interface{}{"workflow": data}))
+	}
+	fmt.Println(i18n.T(key, rawString(data, "name")))
+	printWorkflowNodes(data)
+	return nil
+}
+
+func printWorkflowTable(data interface{}) {
+	fmt.Printf("%s %s %s %s\n", i18n.PadRight(i18n.T("table_id"), 8), i18n.PadRight(i18n.T("table_name"), 28), i18n.PadRight(i18n.T("table_status"), 12), i18n.PadRight("CREATED", 22))
+	for _, row := range rawList(data) {
+		fmt.Printf("%s %s %s %s\n", i18n.PadRight(rawString(row, "id"), 8), i18n.PadRight(rawString(row, "name"), 28), i18n.PadRight(rawString(row, "status"), 12), i18n.PadRight(rawString(row, "createdAt"), 22))
+	}
+}
+
+func printWorkflowDetail(data interface{}) {
+	workflow := rawMap(data)
+	if workflow == nil {
+		printRawObject(data)
+		return
+	}
+	fmt.Printf("%s: %s\n", i18n.T("table_id"), rawString(workflow, "id"))
+	fmt.Printf("%s: %s\n", i18n.T("table_name"), rawString(workflow, "name"))
+	fmt.Printf("%s: %s\n", i18n.T("table_status"), rawString(workflow, "status"))
+	printWorkflowNodes(workflow)
+}
+
+func printWorkflowNodes(workflow map[string]interface{}) {
+	nodes := rawList(workflow["nodes"])
+	if len(nodes) == 0 {
+		return
+	}
+	fmt.Printf("%s %s %s %s\n", i18n.PadRight(i18n.T("workflow_table_node"), 20), i18n.PadRight(i18n.T("table_job_name"), 32), i18n.PadRight(i18n.T("table_status"), 12), i18n.T("workflow_table_depends_on"))
+	for _, node := range nodes {
+		parents := make([]string, 0)
+		for _, dep := range rawList(node["dependsOn"]) {
+			parents = append(parents, rawString(dep, "node")+":"+rawString(dep, "condition"))
+		}
+		fmt.Printf("%s %s %s %s\n", i18n.PadRight(rawString(node, "name"), 20), i18n.PadRight(rawString(node, "jobName"), 32), i18n.PadRight(rawString(node, "status"), 12), emptyDash(strings.Join(parents, ",")))
+	}
+}
+
+func init() {
+	workflowCreateCmd.Flags().String("file", "", "Read workflow JSON request body from file")
+	workflowCancelCmd.Flags().BoolP("yes", "y", false, "Skip confirmation")
+	workflowCmd.AddCommand(workflowLsCmd, workflowGetCmd, workflowCreateCmd, workflowCancelCmd)
+	rootCmd.AddCommand(workflowCmd)
+}
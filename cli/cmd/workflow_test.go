@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/raids-lab/crater/cli/internal/api"
+)
+
+func TestValidateWorkflowRequest(t *testing.T) {
+	spec := json.RawMessage(`{"name":"prep"}`)
+	valid := api.CreateWorkflowRequest{
+		Name: "pipeline",
+		Nodes: []api.WorkflowNodeRequest{
+			{Name: "prep", JobType: "custom", Spec: spec},
+			{Name: "train", JobType: "pytorch", Spec: spec, DependsOn: []api.WorkflowDependency{{Node: "prep"}}},
+		},
+	}
+	if err := validateWorkflowRequest(valid); err != nil {
+		t.Fatalf("validateWorkflowRequest() error = %v", err)
+	}
+
+	invalid := api.CreateWorkflowRequest{
+		Name: "pipeline",
+		Nodes: []api.WorkflowNodeRequest{
+			{Name: "prep", JobType: "jupyter", Spec: spec},
+			{Name: "train", JobType: "custom", Spec: spec, DependsOn: []api.WorkflowDependency{{Node: "prep", Condition: "always"}}},
+		},
+	}
+	err := validateWorkflowRequest(invalid)
+	if err == nil || !strings.Contains(err.Error(), "jupyter") || !strings.Contains(err.Error(), "always") {
+		t.Fatalf("error = %v, want job type and condition issues", err)
+	}
+}
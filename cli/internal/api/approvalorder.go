@@ -12,6 +12,7 @@ type ApprovalOrderClient interface {
 	CreateApprovalOrder(req ApprovalOrderRequest) (string, error)
 	UpdateApprovalOrder(id uint, req ApprovalOrderRequest) (string, error)
 	ReviewApprovalOrder(id uint, req ApprovalOrderReviewRequest) (string, error)
+	ReviewAssignedApprovalOrder(id uint, req ApprovalOrderReviewRequest) (string, error)
 	DeleteApprovalOrder(id uint) (string, error)
 	CheckApprovalOrders() (string, error)
 	LockJob(req JobLockRequest) (string, error)
@@ -21,6 +22,9 @@ type ApprovalOrderContent struct {
 	ApprovalOrderTypeID         uint   `json:"approvalorderTypeID"`
 	ApprovalOrderReason         string `json:"approvalorderReason"`
 	ApprovalOrderExtensionHours uint   `json:"approvalorderExtensionHours"`
+	// Dataset access requests only: the queue the grant is for (0 for the creator) and its expiry.
+	ApprovalOrderAccountID uint       `json:"approvalorderAccountID,omitempty"`
+	ApprovalOrderExpiresAt *time.Time `json:"approvalorderExpiresAt,omitempty"`
 }
 
 type ApprovalUserInfo struct {
@@ -40,20 +44,25 @@ type ApprovalOrder struct {
 	Creator     ApprovalUserInfo     `json:"creator"`
 	ReviewerID  uint                 `json:"reviewerID"`
 	Reviewer    ApprovalUserInfo     `json:"reviewer"`
+	AssigneeID  uint                 `json:"assigneeID"`
 }
 
 type ApprovalOrderRequest struct {
-	Name           string `json:"name"`
-	Type           string `json:"type"`
-	Status         string `json:"status,omitempty"`
-	TypeID         uint   `json:"approvalorderTypeID"`
-	Reason         string `json:"approvalOrderReason"`
-	ExtensionHours uint   `json:"approvalOrderExtensionHours"`
+	Name           string     `json:"name"`
+	Type           string     `json:"type"`
+	Status         string     `json:"status,omitempty"`
+	TypeID         uint       `json:"approvalorderTypeID"`
+	Reason         string     `json:"approvalOrderReason"`
+	ExtensionHours uint       `json:"approvalOrderExtensionHours"`
+	AccountID      uint       `json:"approvalOrderAccountID,omitempty"`
+	ExpiresAt      *time.Time `json:"approvalOrderExpiresAt,omitempty"`
 }
 
 type ApprovalOrderReviewRequest struct {
 	Status      string `json:"status"`
 	ReviewNotes string `json:"reviewNotes,omitempty"`
+	// ExpiresAt overrides the requested expiry when approving a dataset access request.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
 }
 
 type JobLockRequest struct {
@@ -144,6 +153,20 @@ func (c *Client) ReviewApprovalOrder(id uint, req ApprovalOrderReviewRequest) (s
 	return result.Data, nil
 }
 
+// ReviewAssignedApprovalOrder reviews an order assigned to the current user, such as a request
+// for access to one of their datasets.
+func (c *Client) ReviewAssignedApprovalOrder(id uint, req ApprovalOrderReviewRequest) (string, error) {
+	var result Response[string]
+	resp, err := c.httpClient.R().SetBody(&req).SetSuccessResult(&result).SetErrorResult(&result).Put(fmt.Sprintf("%s/%d/review", ApprovalOrderPrefix, id))
+	if err != nil {
+		return "", &NetworkError{Cause: err}
+	}
+	if err := errorFromResponse(resp, result.Code, result.Message); err != nil {
+		return "", err
+	}
+	return result.Data, nil
+}
+
 func (c *Client) DeleteApprovalOrder(id uint) (string, error) {
 	var result Response[string]
 	resp, err := c.httpClient.R().SetSuccessResult(&result).SetErrorResult(&result).Delete(fmt.Sprintf("%s/%d", ApprovalOrderPrefix, id))
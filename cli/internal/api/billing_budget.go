@@ -0,0 +1,42 @@
+package api
+
+import "fmt"
+
+// BillingBudgetRequest is the request body of PUT {prefix}/{aid}/billing/budgets.
+// UserID 0 targets the account-wide budget; an empty HardCap disables the cap.
+type BillingBudgetRequest struct {
+	UserID        uint   `json:"userId"`
+	AlertPercents []int  `json:"alertPercents,omitempty"`
+	HardCap       string `json:"hardCap,omitempty"`
+	CapAction     string `json:"capAction,omitempty"`
+}
+
+// UpsertBillingBudget creates or replaces a budget. prefix is AccountsPrefix for
+// account administrators or AdminAccountsPrefix for platform administrators.
+func (c *Client) UpsertBillingBudget(prefix string, accountID uint, req BillingBudgetRequest) (map[string]interface{}, error) {
+	var result Response[map[string]interface{}]
+	resp, err := c.httpClient.R().
+		SetBody(req).
+		SetSuccessResult(&result).
+		SetErrorResult(&result).
+		Put(fmt.Sprintf("%s/%s/billing/budgets", prefix, UintPath(accountID)))
+	if err != nil {
+		return nil, &NetworkError{Cause: err}
+	}
+	if err := errorFromResponse(resp, result.Code, result.Message); err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+func (c *Client) DeleteBillingBudget(prefix string, accountID, userID uint) error {
+	var result Response[string]
+	resp, err := c.httpClient.R().
+		SetSuccessResult(&result).
+		SetErrorResult(&result).
+		Delete(fmt.Sprintf("%s/%s/billing/budgets/%s", prefix, UintPath(accountID), UintPath(userID)))
+	if err != nil {
+		return &NetworkError{Cause: err}
+	}
+	return errorFromResponse(resp, result.Code, result.Message)
+}
@@ -0,0 +1,45 @@
+package api
+
+import "fmt"
+
+// PublishDatasetVersionRequest freezes the current content of a dataset under Version.
+type PublishDatasetVersionRequest struct {
+	Version  string `json:"version"`
+	Describe string `json:"describe,omitempty"`
+	Hardlink bool   `json:"hardlink,omitempty"`
+}
+
+// PublishDatasetVersion asks the storage server to snapshot a dataset as a new version. The
+// snapshot is built in the background; the returned version starts in the Creating status.
+func (c *Client) PublishDatasetVersion(id uint, req PublishDatasetVersionRequest) (map[string]interface{}, error) {
+	var result Response[map[string]interface{}]
+	resp, err := c.httpClient.R().
+		SetBody(req).
+		SetSuccessResult(&result).
+		SetErrorResult(&result).
+		Post(fmt.Sprintf("%s/datasets/%s/versions", StoragePrefix, UintPath(id)))
+	if err != nil {
+		return nil, &NetworkError{Cause: err}
+	}
+	if err := errorFromResponse(resp, result.Code, result.Message); err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// VerifyDataset queues a full rehash of a dataset and a comparison with its manifest. The
+// returned manifest reports verifyRequested until the storage server has run the check.
+func (c *Client) VerifyDataset(id uint) (map[string]interface{}, error) {
+	var result Response[map[string]interface{}]
+	resp, err := c.httpClient.R().
+		SetSuccessResult(&result).
+		SetErrorResult(&result).
+		Post(fmt.Sprintf("%s/%s/verify", DatasetPrefix, UintPath(id)))
+	if err != nil {
+		return nil, &NetworkError{Cause: err}
+	}
+	if err := errorFromResponse(resp, result.Code, result.Message); err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
@@ -0,0 +1,26 @@
+package api
+
+import "fmt"
+
+// FairShareRequest is the request body of PUT /v1/admin/accounts/{aid}/fair-share.
+// UserID 0 targets the account itself; a nil Share restores the default share.
+type FairShareRequest struct {
+	UserID uint `json:"userId"`
+	Share  *int `json:"share"`
+}
+
+func (c *Client) UpdateFairShare(accountID uint, req FairShareRequest) (map[string]interface{}, error) {
+	var result Response[map[string]interface{}]
+	resp, err := c.httpClient.R().
+		SetBody(req).
+		SetSuccessResult(&result).
+		SetErrorResult(&result).
+		Put(fmt.Sprintf("%s/%s/fair-share", AdminAccountsPrefix, UintPath(accountID)))
+	if err != nil {
+		return nil, &NetworkError{Cause: err}
+	}
+	if err := errorFromResponse(resp, result.Code, result.Message); err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"fmt"
+	"net/url"
 	"time"
 )
 
@@ -13,6 +14,10 @@ type ImageClient interface {
 	CreatePipApt(req PipAptBuildRequest) (string, error)
 	CreateDockerfile(req DockerfileBuildRequest) (string, error)
 	CreateEnvd(req EnvdBuildRequest) (string, error)
+	CreateGit(req GitBuildRequest) (string, error)
+	ListGitCredentials() ([]GitCredential, error)
+	SaveGitCredential(req GitCredentialRequest) (*GitCredential, error)
+	DeleteGitCredential(name string) (string, error)
 	RemoveKaniko(ids []uint, admin bool) (string, error)
 	ListImageRecords(admin bool) (*ListImageResponse, error)
 	ListAvailableImages() ([]ImageInfo, error)
@@ -37,6 +42,11 @@ type ImageClient interface {
 	ListCudaBaseImages() (*CudaBaseImagesResponse, error)
 	AdminAddCudaBaseImage(req CudaBaseImageRequest) (string, error)
 	AdminDeleteCudaBaseImage(id uint) (string, error)
+	GetImageScan(id uint, packages, admin bool) (*ImageScanReport, error)
+	RescanImage(id uint, admin bool) (string, error)
+	GetImageScanConfig() (*ImageScanConfig, error)
+	UpdateImageScanConfig(req ImageScanConfigRequest) (string, error)
+	GetImageGCReport(params map[string]string) (*ImageGCReport, error)
 }
 
 type KanikoInfo struct {
@@ -110,6 +120,7 @@ type PipAptBuildRequest struct {
 	Tags         []string `json:"tags"`
 	Template     string   `json:"template"`
 	Archs        []string `json:"archs"`
+	NoCache      bool     `json:"noCache,omitempty"`
 }
 
 type DockerfileBuildRequest struct {
@@ -120,6 +131,34 @@ type DockerfileBuildRequest struct {
 	Tags        []string `json:"tags"`
 	Template    string   `json:"template"`
 	Archs       []string `json:"archs"`
+	NoCache     bool     `json:"noCache,omitempty"`
+}
+
+type GitBuildRequest struct {
+	Description      string   `json:"description"`
+	Repo             string   `json:"repo"`
+	Ref              string   `json:"ref,omitempty"`
+	Subdir           string   `json:"subdir,omitempty"`
+	Dockerfile       string   `json:"dockerfile,omitempty"`
+	CredentialSecret string   `json:"credentialSecret,omitempty"`
+	Name             string   `json:"name"`
+	Tag              string   `json:"tag"`
+	Tags             []string `json:"tags"`
+	Archs            []string `json:"archs"`
+	NoCache          bool     `json:"noCache,omitempty"`
+}
+
+// GitCredentialRequest saves a basic-auth secret that git builds reference by name.
+type GitCredentialRequest struct {
+	Name     string `json:"name"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type GitCredential struct {
+	Name      string    `json:"name"`
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"createdAt"`
 }
 
 type EnvdBuildRequest struct {
@@ -168,9 +207,10 @@ type ImageArchRequest struct {
 }
 
 type ImageShareRequest struct {
-	IDList  []uint `json:"idList"`
-	ImageID uint   `json:"imageID"`
-	Type    string `json:"type"`
+	IDList    []uint     `json:"idList"`
+	ImageID   uint       `json:"imageID"`
+	Type      string     `json:"type"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
 }
 
 type ImageCancelShareRequest struct {
@@ -192,14 +232,16 @@ type CheckLinkValidityResponse struct {
 }
 
 type ImageGrantedUser struct {
-	ID       uint   `json:"id"`
-	Name     string `json:"name"`
-	Nickname string `json:"nickname"`
+	ID        uint       `json:"id"`
+	Name      string     `json:"name"`
+	Nickname  string     `json:"nickname"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
 }
 
 type ImageGrantedAccount struct {
-	ID   uint   `json:"id"`
-	Name string `json:"name"`
+	ID        uint       `json:"id"`
+	Name      string     `json:"name"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
 }
 
 type ImageGrantResponse struct {
@@ -240,6 +282,87 @@ type CudaBaseImageRequest struct {
 	Value      string `json:"value"`
 }
 
+type ImageScanPackage struct {
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Source  string `json:"source,omitempty"`
+}
+
+type ImageScanFinding struct {
+	ID           string `json:"id"`
+	Severity     string `json:"severity"`
+	Type         string `json:"type"`
+	Package      string `json:"package"`
+	Version      string `json:"version"`
+	FixedVersion string `json:"fixedVersion,omitempty"`
+	Title        string `json:"title,omitempty"`
+}
+
+type ImageScanReport struct {
+	ImageID         uint               `json:"imageId"`
+	ImageLink       string             `json:"imageLink"`
+	Digest          string             `json:"digest"`
+	Status          string             `json:"status"`
+	Message         string             `json:"message"`
+	RequestedAt     time.Time          `json:"requestedAt"`
+	ScannedAt       *time.Time         `json:"scannedAt"`
+	OS              string             `json:"os"`
+	Arch            string             `json:"arch"`
+	PackageCount    int                `json:"packageCount"`
+	Critical        int                `json:"critical"`
+	High            int                `json:"high"`
+	Medium          int                `json:"medium"`
+	Low             int                `json:"low"`
+	Unknown         int                `json:"unknown"`
+	MaxSeverity     string             `json:"maxSeverity"`
+	DatabaseVersion string             `json:"databaseVersion"`
+	Findings        []ImageScanFinding `json:"findings"`
+	Packages        []ImageScanPackage `json:"packages,omitempty"`
+}
+
+type ImageScanConfig struct {
+	ScannerEnabled            bool   `json:"scannerEnabled"`
+	VulnerabilityDBConfigured bool   `json:"vulnerabilityDbConfigured"`
+	BlockPublicSeverity       string `json:"blockPublicSeverity"`
+}
+
+type ImageScanConfigRequest struct {
+	BlockPublicSeverity string `json:"blockPublicSeverity"`
+}
+
+// ImageGCPolicy is the retention policy of the clean-stale-images cron job.
+type ImageGCPolicy struct {
+	KeepLastPerRepository int   `json:"keepLastPerRepository"`
+	UnusedDays            int   `json:"unusedDays"`
+	DeleteUntagged        bool  `json:"deleteUntagged"`
+	SkipShared            *bool `json:"skipShared"`
+	NoticeDays            int   `json:"noticeDays"`
+}
+
+type ImageGCCandidate struct {
+	ImageID     uint       `json:"imageId"`
+	ImageLink   string     `json:"imageLink"`
+	UserID      uint       `json:"userId"`
+	Username    string     `json:"username"`
+	Reason      string     `json:"reason"`
+	Size        int64      `json:"size"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	LastUsedAt  *time.Time `json:"lastUsedAt"`
+	NotifiedAt  *time.Time `json:"notifiedAt"`
+	DeleteAfter time.Time  `json:"deleteAfter"`
+}
+
+// ImageGCReport is the dry run of the image garbage collector.
+type ImageGCReport struct {
+	Schedule   string             `json:"schedule"`
+	JobStatus  string             `json:"jobStatus"`
+	Policy     ImageGCPolicy      `json:"policy"`
+	Scanned    int                `json:"scanned"`
+	TotalSize  int64              `json:"totalSize"`
+	Candidates []ImageGCCandidate `json:"candidates"`
+}
+
 func (c *Client) ListKaniko(admin bool) (*ListKanikoResponse, error) {
 	path := ImagesPrefix + "/kaniko"
 	if admin {
@@ -288,6 +411,39 @@ func (c *Client) CreateEnvd(req EnvdBuildRequest) (string, error) {
 	return c.postString(ImagesPrefix+"/envd", req)
 }
 
+func (c *Client) CreateGit(req GitBuildRequest) (string, error) {
+	return c.postString(ImagesPrefix+"/git", req)
+}
+
+func (c *Client) ListGitCredentials() ([]GitCredential, error) {
+	var result Response[[]GitCredential]
+	if err := c.get(ImagesPrefix+"/git/credential", nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+func (c *Client) SaveGitCredential(req GitCredentialRequest) (*GitCredential, error) {
+	var result Response[GitCredential]
+	if err := c.post(ImagesPrefix+"/git/credential", req, &result); err != nil {
+		return nil, err
+	}
+	return &result.Data, nil
+}
+
+func (c *Client) DeleteGitCredential(name string) (string, error) {
+	var result Response[string]
+	path := ImagesPrefix + "/git/credential/" + url.PathEscape(name)
+	resp, err := c.httpClient.R().SetSuccessResult(&result).SetErrorResult(&result).Delete(path)
+	if err != nil {
+		return "", &NetworkError{Cause: err}
+	}
+	if err := errorFromResponse(resp, result.Code, result.Message); err != nil {
+		return "", err
+	}
+	return result.Data, nil
+}
+
 func (c *Client) RemoveKaniko(ids []uint, admin bool) (string, error) {
 	path := ImagesPrefix + "/remove"
 	if admin {
@@ -460,6 +616,50 @@ func (c *Client) AdminDeleteCudaBaseImage(id uint) (string, error) {
 	return result.Data, nil
 }
 
+func (c *Client) GetImageScan(id uint, packages, admin bool) (*ImageScanReport, error) {
+	var result Response[ImageScanReport]
+	params := map[string]string{}
+	if packages {
+		params["packages"] = "true"
+	}
+	if err := c.get(imageAdminPath(fmt.Sprintf("/scan/%d", id), admin), params, &result); err != nil {
+		return nil, err
+	}
+	return &result.Data, nil
+}
+
+func (c *Client) RescanImage(id uint, admin bool) (string, error) {
+	return c.postString(imageAdminPath(fmt.Sprintf("/scan/%d", id), admin), nil)
+}
+
+func (c *Client) GetImageScanConfig() (*ImageScanConfig, error) {
+	var result Response[ImageScanConfig]
+	if err := c.get(AdminSysConfigPfx+"/image-scan", nil, &result); err != nil {
+		return nil, err
+	}
+	return &result.Data, nil
+}
+
+func (c *Client) UpdateImageScanConfig(req ImageScanConfigRequest) (string, error) {
+	var result Response[string]
+	resp, err := c.httpClient.R().SetBody(&req).SetSuccessResult(&result).SetErrorResult(&result).Put(AdminSysConfigPfx + "/image-scan")
+	if err != nil {
+		return "", &NetworkError{Cause: err}
+	}
+	if err := errorFromResponse(resp, result.Code, result.Message); err != nil {
+		return "", err
+	}
+	return result.Data, nil
+}
+
+func (c *Client) GetImageGCReport(params map[string]string) (*ImageGCReport, error) {
+	var result Response[ImageGCReport]
+	if err := c.get(AdminImagesPrefix+"/gc", params, &result); err != nil {
+		return nil, err
+	}
+	return &result.Data, nil
+}
+
 func imageAdminPath(suffix string, admin bool) string {
 	if admin {
 		return AdminImagesPrefix + suffix
@@ -525,6 +725,12 @@ func responseCodeMsg(result interface{}) (int, string) {
 		return r.Code, r.Message
 	case *Response[CudaBaseImagesResponse]:
 		return r.Code, r.Message
+	case *Response[ImageScanReport]:
+		return r.Code, r.Message
+	case *Response[ImageScanConfig]:
+		return r.Code, r.Message
+	case *Response[ImageGCReport]:
+		return r.Code, r.Message
 	default:
 		return 0, ""
 	}
@@ -50,6 +50,7 @@ func TestImageClientRoutesMatchBackend(t *testing.T) {
 		{"create pip apt build", http.MethodPost, "/api/v1/images/kaniko", "", func(c *Client) error { _, err := c.CreatePipApt(PipAptBuildRequest{}); return err }},
 		{"create dockerfile build", http.MethodPost, "/api/v1/images/dockerfile", "", func(c *Client) error { _, err := c.CreateDockerfile(DockerfileBuildRequest{}); return err }},
 		{"create envd build", http.MethodPost, "/api/v1/images/envd", "", func(c *Client) error { _, err := c.CreateEnvd(EnvdBuildRequest{}); return err }},
+		{"create git build", http.MethodPost, "/api/v1/images/git", "", func(c *Client) error { _, err := c.CreateGit(GitBuildRequest{}); return err }},
 		{"remove user builds", http.MethodPost, "/api/v1/images/remove", "", func(c *Client) error { _, err := c.RemoveKaniko([]uint{7}, false); return err }},
 		{"remove admin builds", http.MethodPost, "/api/v1/admin/images/remove", "", func(c *Client) error { _, err := c.RemoveKaniko([]uint{7}, true); return err }},
 		{"list user images", http.MethodGet, "/api/v1/images/image", "", func(c *Client) error { _, err := c.ListImageRecords(false); return err }},
@@ -84,6 +85,25 @@ func TestImageClientRoutesMatchBackend(t *testing.T) {
 		{"list cuda images", http.MethodGet, "/api/v1/images/cudabaseimage", "", func(c *Client) error { _, err := c.ListCudaBaseImages(); return err }},
 		{"add cuda image", http.MethodPost, "/api/v1/admin/images/cudabaseimage", "", func(c *Client) error { _, err := c.AdminAddCudaBaseImage(CudaBaseImageRequest{}); return err }},
 		{"delete cuda image", http.MethodDelete, "/api/v1/admin/images/cudabaseimage/7", "", func(c *Client) error { _, err := c.AdminDeleteCudaBaseImage(7); return err }},
+		{"get image scan", http.MethodGet, "/api/v1/images/scan/7", "", func(c *Client) error { _, err := c.GetImageScan(7, false, false); return err }},
+		{"get admin image scan with packages", http.MethodGet, "/api/v1/admin/images/scan/7", "packages=true", func(c *Client) error { _, err := c.GetImageScan(7, true, true); return err }},
+		{"rescan image", http.MethodPost, "/api/v1/images/scan/7", "", func(c *Client) error { _, err := c.RescanImage(7, false); return err }},
+		{"rescan admin image", http.MethodPost, "/api/v1/admin/images/scan/7", "", func(c *Client) error { _, err := c.RescanImage(7, true); return err }},
+		{"get scan policy", http.MethodGet, "/api/v1/admin/system-config/image-scan", "", func(c *Client) error { _, err := c.GetImageScanConfig(); return err }},
+		{"update scan policy", http.MethodPut, "/api/v1/admin/system-config/image-scan", "", func(c *Client) error {
+			_, err := c.UpdateImageScanConfig(ImageScanConfigRequest{BlockPublicSeverity: "HIGH"})
+			return err
+		}},
+		{"list git credentials", http.MethodGet, "/api/v1/images/git/credential", "", func(c *Client) error { _, err := c.ListGitCredentials(); return err }},
+		{"save git credential", http.MethodPost, "/api/v1/images/git/credential", "", func(c *Client) error {
+			_, err := c.SaveGitCredential(GitCredentialRequest{Name: "alice-git", Username: "alice", Password: "token"})
+			return err
+		}},
+		{"delete git credential", http.MethodDelete, "/api/v1/images/git/credential/alice-git", "", func(c *Client) error { _, err := c.DeleteGitCredential("alice-git"); return err }},
+		{"get image gc report", http.MethodGet, "/api/v1/admin/images/gc", "unusedDays=30", func(c *Client) error {
+			_, err := c.GetImageGCReport(map[string]string{"unusedDays": "30"})
+			return err
+		}},
 	}
 
 	for _, tt := range tests {
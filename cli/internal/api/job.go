@@ -58,10 +58,11 @@ type ImageBaseInfo struct {
 }
 
 type VolumeMount struct {
-	Type      uint   `json:"type"`
-	DatasetID uint   `json:"datasetID,omitempty"`
-	SubPath   string `json:"subPath,omitempty"`
-	MountPath string `json:"mountPath"`
+	Type           uint   `json:"type"`
+	DatasetID      uint   `json:"datasetID,omitempty"`
+	DatasetVersion string `json:"datasetVersion,omitempty"`
+	SubPath        string `json:"subPath,omitempty"`
+	MountPath      string `json:"mountPath"`
 }
 
 type EnvVar struct {
@@ -99,6 +100,7 @@ type JobInfo struct {
 	PermanentLocked         bool         `json:"permanentLocked"`
 	LockedTimestamp         time.Time    `json:"lockedTimestamp"`
 	BilledPointsTotal       float64      `json:"billedPointsTotal"`
+	FairSharePriority       *float64     `json:"fairSharePriority,omitempty"`
 }
 
 type JobDetail struct {
@@ -166,9 +168,18 @@ type CreateInteractiveJobRequest struct {
 
 type CreateTrainingJobRequest struct {
 	CreateInteractiveJobRequest
-	Shell      *string `json:"shell,omitempty"`
-	Command    *string `json:"command,omitempty"`
-	WorkingDir string  `json:"workingDir"`
+	Shell      *string          `json:"shell,omitempty"`
+	Command    *string          `json:"command,omitempty"`
+	WorkingDir string           `json:"workingDir"`
+	Array      *JobArrayRequest `json:"array,omitempty"`
+}
+
+// JobArrayRequest expands a training job into a job array: either Count elements or the
+// cartesian product of Matrix, with at most Parallelism of them running at once.
+type JobArrayRequest struct {
+	Count       int                 `json:"count,omitempty"`
+	Matrix      map[string][]string `json:"matrix,omitempty"`
+	Parallelism int                 `json:"parallelism,omitempty"`
 }
 
 type PortRequest struct {
@@ -419,6 +430,21 @@ func (c *Client) createJob(kind string, body interface{}) (map[string]interface{
 	return result.Data, nil
 }
 
+func (c *Client) CancelJobArray(id uint) (map[string]interface{}, error) {
+	var result Response[map[string]interface{}]
+	resp, err := c.httpClient.R().
+		SetSuccessResult(&result).
+		SetErrorResult(&result).
+		Delete(JobArraysPrefix + "/" + UintPath(id))
+	if err != nil {
+		return nil, &NetworkError{Cause: err}
+	}
+	if err := errorFromResponse(resp, result.Code, result.Message); err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
 func (c *Client) AdminToggleJobKeep(name string) (string, error) {
 	return c.messagePut(AdminOperationsPfx+"/keep/"+url.PathEscape(name), nil)
 }
@@ -510,3 +536,21 @@ func (c *Client) messageDelete(path string) (string, error) {
 	}
 	return result.Data, nil
 }
+
+// EstimateJobCost prices a create payload without submitting it; kind is the create
+// endpoint of the job type (training, tensorflow or pytorch).
+func (c *Client) EstimateJobCost(kind string, body interface{}) (map[string]interface{}, error) {
+	var result Response[map[string]interface{}]
+	resp, err := c.httpClient.R().
+		SetBody(body).
+		SetSuccessResult(&result).
+		SetErrorResult(&result).
+		Post(VCJobsPrefix + "/estimate/" + kind)
+	if err != nil {
+		return nil, &NetworkError{Cause: err}
+	}
+	if err := errorFromResponse(resp, result.Code, result.Message); err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
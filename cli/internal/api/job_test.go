@@ -264,3 +264,23 @@ func TestFetchAllJobPagesSequentially(t *testing.T) {
 		t.Fatalf("unexpected items: %#v", items)
 	}
 }
+
+func TestJobClientEstimateRoute(t *testing.T) {
+	client := jobTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/vcjobs/estimate/pytorch" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		if body := decodeJobTestBody(t, r); body["name"] != "sweep" {
+			t.Errorf("body = %#v", body)
+		}
+		writeJobTestResponse(t, w, map[string]interface{}{"billable": true, "costPerHour": 12.5})
+	})
+
+	data, err := client.EstimateJobCost("pytorch", CreateDistributedJobRequest{JobCommonRequest: JobCommonRequest{Name: "sweep"}})
+	if err != nil {
+		t.Fatalf("EstimateJobCost() error = %v", err)
+	}
+	if data["costPerHour"] != 12.5 {
+		t.Fatalf("data = %#v", data)
+	}
+}
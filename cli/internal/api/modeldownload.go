@@ -29,6 +29,14 @@ type CreateModelDownloadReq struct {
 	Source   string `json:"source,omitempty"`
 	Category string `json:"category"`
 	Token    string `json:"token,omitempty"`
+	// URL and the fields below apply to s3 and http sources only.
+	URL       string `json:"url,omitempty"`
+	Endpoint  string `json:"endpoint,omitempty"`
+	Region    string `json:"region,omitempty"`
+	AccessKey string `json:"accessKey,omitempty"`
+	SecretKey string `json:"secretKey,omitempty"`
+	Extract   bool   `json:"extract,omitempty"`
+	Checksum  string `json:"checksum,omitempty"`
 }
 
 // ModelDownloadResp mirrors the platform model download task summary.
@@ -47,6 +55,9 @@ type ModelDownloadResp struct {
 	JobName         string    `json:"jobName"`
 	CreatorID       uint      `json:"creatorId"`
 	ReferenceCount  int       `json:"referenceCount"`
+	SourceURL       string    `json:"sourceUrl"`
+	Extract         bool      `json:"extract"`
+	Checksum        string    `json:"checksum"`
 	CreatedAt       time.Time `json:"createdAt"`
 	UpdatedAt       time.Time `json:"updatedAt"`
 }
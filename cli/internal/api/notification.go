@@ -0,0 +1,66 @@
+package api
+
+// NotificationRuleRequest is the request body of PUT /api/v1/notifications/rules.
+// AccountID 0 targets the user-wide rule.
+type NotificationRuleRequest struct {
+	AccountID            uint     `json:"accountId"`
+	Events               []string `json:"events"`
+	QueuedTooLongMinutes int      `json:"queuedTooLongMinutes,omitempty"`
+	BalanceLowThreshold  string   `json:"balanceLowThreshold,omitempty"`
+	QuietStart           string   `json:"quietStart,omitempty"`
+	QuietEnd             string   `json:"quietEnd,omitempty"`
+	DigestMinutes        int      `json:"digestMinutes,omitempty"`
+}
+
+// NotificationChannel is one entry of the user's notification fallback chain.
+type NotificationChannel struct {
+	Type   string `json:"type"`
+	URL    string `json:"url,omitempty"`
+	Secret string `json:"secret,omitempty"`
+}
+
+// NotificationPreference is the request body of PUT /api/v1/notifications/channels.
+type NotificationPreference struct {
+	Channels []NotificationChannel `json:"channels"`
+}
+
+func (c *Client) UpsertNotificationRule(req NotificationRuleRequest) (map[string]interface{}, error) {
+	var result Response[map[string]interface{}]
+	resp, err := c.httpClient.R().
+		SetBody(req).
+		SetSuccessResult(&result).
+		SetErrorResult(&result).
+		Put(NotificationsPrefix + "/rules")
+	if err != nil {
+		return nil, &NetworkError{Cause: err}
+	}
+	if err := errorFromResponse(resp, result.Code, result.Message); err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+func (c *Client) DeleteNotificationRule(accountID uint) error {
+	var result Response[string]
+	resp, err := c.httpClient.R().
+		SetSuccessResult(&result).
+		SetErrorResult(&result).
+		Delete(NotificationsPrefix + "/rules/" + UintPath(accountID))
+	if err != nil {
+		return &NetworkError{Cause: err}
+	}
+	return errorFromResponse(resp, result.Code, result.Message)
+}
+
+func (c *Client) UpdateNotificationChannels(pref NotificationPreference) error {
+	var result Response[string]
+	resp, err := c.httpClient.R().
+		SetBody(pref).
+		SetSuccessResult(&result).
+		SetErrorResult(&result).
+		Put(NotificationsPrefix + "/channels")
+	if err != nil {
+		return &NetworkError{Cause: err}
+	}
+	return errorFromResponse(resp, result.Code, result.Message)
+}
@@ -33,6 +33,12 @@ const (
 	SPJobsPrefix        = "/api/v1/spjobs"
 	VCJobsPrefix        = "/api/v1/vcjobs"
 	AdminVCJobsPrefix   = "/api/v1/admin/vcjobs"
+	WorkflowsPrefix     = "/api/v1/workflows"
+	JobArraysPrefix     = "/api/v1/jobarrays"
+	NotificationsPrefix = "/api/v1/notifications"
+	SharesPrefix        = "/api/v1/shares"
+	AdminSharesPrefix   = "/api/v1/admin/shares"
+	StoragePrefix       = "/api/ss"
 )
 
 // AuthLoginPath 为登录接口路径（含模块前缀）。
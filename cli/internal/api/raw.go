@@ -52,6 +52,22 @@ func (c *Client) GetRawString(path string, params map[string]string) (string, er
 	return result.Data, nil
 }
 
+// GetRawBytes returns the body of a download endpoint that answers with a file
+// (such as a CSV export) on success and a JSON envelope on failure.
+func (c *Client) GetRawBytes(path string, params map[string]string) ([]byte, error) {
+	var result Response[interface{}]
+	resp, err := c.rawRequest(params).
+		SetErrorResult(&result).
+		Get(path)
+	if err != nil {
+		return nil, &NetworkError{Cause: err}
+	}
+	if err := errorFromResponse(resp, result.Code, result.Message); err != nil {
+		return nil, err
+	}
+	return resp.Bytes(), nil
+}
+
 func UintPath(v uint) string {
 	return strconv.FormatUint(uint64(v), 10)
 }
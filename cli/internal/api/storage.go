@@ -0,0 +1,194 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// StorageQuotaRequest is the request body of POST /ss/admin/quota.
+// A nil limit is left unchanged, zero removes it.
+type StorageQuotaRequest struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	MaxBytes  *int64 `json:"maxBytes,omitempty"`
+	MaxInodes *int64 `json:"maxInodes,omitempty"`
+}
+
+func (c *Client) SetStorageQuota(req StorageQuotaRequest) (map[string]interface{}, error) {
+	var result Response[map[string]interface{}]
+	resp, err := c.httpClient.R().
+		SetBody(req).
+		SetSuccessResult(&result).
+		SetErrorResult(&result).
+		Post(StoragePrefix + "/admin/quota")
+	if err != nil {
+		return nil, &NetworkError{Cause: err}
+	}
+	if err := errorFromResponse(resp, result.Code, result.Message); err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// Upload is a resumable upload staged by the storage server.
+type Upload struct {
+	ID     string `json:"id"`
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	Offset int64  `json:"offset"`
+	SHA256 string `json:"sha256"`
+}
+
+// CreateUploadRequest is the request body of POST /ss/uploads.
+type CreateUploadRequest struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+func (c *Client) ListUploads() ([]Upload, error) {
+	var result Response[[]Upload]
+	resp, err := c.httpClient.R().
+		SetSuccessResult(&result).
+		SetErrorResult(&result).
+		Get(StoragePrefix + "/uploads")
+	if err != nil {
+		return nil, &NetworkError{Cause: err}
+	}
+	if err := errorFromResponse(resp, result.Code, result.Message); err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+func (c *Client) CreateUpload(req CreateUploadRequest) (*Upload, error) {
+	var result Response[Upload]
+	resp, err := c.httpClient.R().
+		SetBody(req).
+		SetSuccessResult(&result).
+		SetErrorResult(&result).
+		Post(StoragePrefix + "/uploads")
+	if err != nil {
+		return nil, &NetworkError{Cause: err}
+	}
+	if err := errorFromResponse(resp, result.Code, result.Message); err != nil {
+		return nil, err
+	}
+	return &result.Data, nil
+}
+
+func (c *Client) GetUpload(id string) (*Upload, error) {
+	var result Response[Upload]
+	resp, err := c.httpClient.R().
+		SetSuccessResult(&result).
+		SetErrorResult(&result).
+		Get(StoragePrefix + "/uploads/" + id)
+	if err != nil {
+		return nil, &NetworkError{Cause: err}
+	}
+	if err := errorFromResponse(resp, result.Code, result.Message); err != nil {
+		return nil, err
+	}
+	return &result.Data, nil
+}
+
+// PatchUpload sends one chunk starting at offset.
+func (c *Client) PatchUpload(id string, offset int64, chunk []byte) (*Upload, error) {
+	var result Response[Upload]
+	resp, err := c.httpClient.R().
+		SetHeader("Upload-Offset", strconv.FormatInt(offset, 10)).
+		SetContentType("application/offset+octet-stream").
+		SetBodyBytes(chunk).
+		SetSuccessResult(&result).
+		SetErrorResult(&result).
+		Patch(StoragePrefix + "/uploads/" + id)
+	if err != nil {
+		return nil, &NetworkError{Cause: err}
+	}
+	if err := errorFromResponse(resp, result.Code, result.Message); err != nil {
+		return nil, err
+	}
+	return &result.Data, nil
+}
+
+// CompleteUpload asks the server to verify the checksum and move the file into place.
+func (c *Client) CompleteUpload(id string) (*Upload, error) {
+	var result Response[Upload]
+	resp, err := c.httpClient.R().
+		SetSuccessResult(&result).
+		SetErrorResult(&result).
+		Post(StoragePrefix + "/uploads/" + id + "/complete")
+	if err != nil {
+		return nil, &NetworkError{Cause: err}
+	}
+	if err := errorFromResponse(resp, result.Code, result.Message); err != nil {
+		return nil, err
+	}
+	return &result.Data, nil
+}
+
+// RestoreTrash moves a trashed item back to its original path, or to path when it is not empty.
+func (c *Client) RestoreTrash(id uint, path string) (map[string]interface{}, error) {
+	var result Response[map[string]interface{}]
+	body := map[string]string{}
+	if path != "" {
+		body["path"] = path
+	}
+	resp, err := c.httpClient.R().
+		SetBody(body).
+		SetSuccessResult(&result).
+		SetErrorResult(&result).
+		Post(StoragePrefix + "/trash/" + strconv.FormatUint(uint64(id), 10) + "/restore")
+	if err != nil {
+		return nil, &NetworkError{Cause: err}
+	}
+	if err := errorFromResponse(resp, result.Code, result.Message); err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// RotateS3Credentials replaces the S3 access key of the current account and returns the new one.
+func (c *Client) RotateS3Credentials() (map[string]interface{}, error) {
+	var result Response[map[string]interface{}]
+	resp, err := c.httpClient.R().
+		SetSuccessResult(&result).
+		SetErrorResult(&result).
+		Post(StoragePrefix + "/s3/credentials/rotate")
+	if err != nil {
+		return nil, &NetworkError{Cause: err}
+	}
+	if err := errorFromResponse(resp, result.Code, result.Message); err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// DownloadArchive streams a storage path packed as tar.gz or zip, along with the size of the
+// file data the server announced. The caller closes the body.
+func (c *Client) DownloadArchive(path, format string) (io.ReadCloser, int64, error) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	// Archives can take longer than the default request timeout to stream.
+	resp, err := c.httpClient.Clone().SetTimeout(0).R().
+		DisableAutoReadResponse().
+		SetQueryParam("format", format).
+		Get(StoragePrefix + "/archive/" + strings.Join(segments, "/"))
+	if err != nil {
+		return nil, 0, &NetworkError{Cause: err}
+	}
+	if !resp.IsSuccessState() {
+		defer resp.Body.Close()
+		var result Response[any]
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		_ = json.Unmarshal(body, &result)
+		return nil, 0, errorFromResponse(resp, result.Code, result.Message)
+	}
+	size, _ := strconv.ParseInt(resp.GetHeader("X-Archive-Size"), 10, 64)
+	return resp.Body, size, nil
+}
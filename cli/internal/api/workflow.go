@@ -0,0 +1,54 @@
+package api
+
+import "encoding/json"
+
+// WorkflowDependency is an edge from a workflow node to one of its parents.
+type WorkflowDependency struct {
+	Node      string `json:"node"`
+	Condition string `json:"condition,omitempty"`
+}
+
+// WorkflowNodeRequest wraps one vcjob create request into a workflow node.
+type WorkflowNodeRequest struct {
+	Name      string               `json:"name"`
+	DependsOn []WorkflowDependency `json:"dependsOn,omitempty"`
+	JobType   string               `json:"jobType"`
+	Spec      json.RawMessage      `json:"spec"`
+}
+
+// CreateWorkflowRequest is the request body of POST /api/v1/workflows.
+type CreateWorkflowRequest struct {
+	Name  string                `json:"name"`
+	Nodes []WorkflowNodeRequest `json:"nodes"`
+}
+
+func (c *Client) CreateWorkflow(req CreateWorkflowRequest) (map[string]interface{}, error) {
+	var result Response[map[string]interface{}]
+	resp, err := c.httpClient.R().
+		SetBody(req).
+		SetSuccessResult(&result).
+		SetErrorResult(&result).
+		Post(WorkflowsPrefix)
+	if err != nil {
+		return nil, &NetworkError{Cause: err}
+	}
+	if err := errorFromResponse(resp, result.Code, result.Message); err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+func (c *Client) CancelWorkflow(id uint) (map[string]interface{}, error) {
+	var result Response[map[string]interface{}]
+	resp, err := c.httpClient.R().
+		SetSuccessResult(&result).
+		SetErrorResult(&result).
+		Delete(WorkflowsPrefix + "/" + UintPath(id))
+	if err != nil {
+		return nil, &NetworkError{Cause: err}
+	}
+	if err := errorFromResponse(resp, result.Code, result.Message); err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
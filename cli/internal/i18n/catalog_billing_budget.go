@@ -0,0 +1,97 @@
+package i18n
+
+// billing budget domain: per-account and per-member budgets with alert thresholds and hard caps.
+var catalogBillingBudget = map[Language]map[string]string{
+	En: {
+		"billing_budget_short":              "Manage account budgets and spending caps",
+		"billing_budget_ls_short":           "List budgets of an account with current spend",
+		"billing_budget_set_short":          "Create or update a budget",
+		"billing_budget_delete_short":       "Delete a budget",
+		"admin_billing_budget_short":        "Manage budgets of any account",
+		"admin_billing_budget_ls_short":     "List budgets of an account with current spend",
+		"admin_billing_budget_set_short":    "Create or update a budget",
+		"admin_billing_budget_delete_short": "Delete a budget",
+
+		"billing_budget_ls_flag_account":           "Account ID the budget belongs to",
+		"billing_budget_set_flag_account":          "Account ID the budget belongs to",
+		"billing_budget_set_flag_user":             "User ID of a member budget (0 for the account-wide budget)",
+		"billing_budget_set_flag_alert":            "Alert thresholds in percent of the period issuance, comma separated (default 50,80,100)",
+		"billing_budget_set_flag_hard-cap":         "Points the scope may spend per issuance period; empty disables the cap",
+		"billing_budget_set_flag_action":           "What happens to running jobs at the cap: stop or suspend (default stop)",
+		"billing_budget_delete_flag_account":       "Account ID the budget belongs to",
+		"billing_budget_delete_flag_user":          "User ID of the member budget to delete (0 for the account-wide budget)",
+		"billing_budget_delete_flag_yes":           "Skip confirmation",
+		"admin_billing_budget_ls_flag_account":     "Account ID the budget belongs to",
+		"admin_billing_budget_set_flag_account":    "Account ID the budget belongs to",
+		"admin_billing_budget_set_flag_user":       "User ID of a member budget (0 for the account-wide budget)",
+		"admin_billing_budget_set_flag_alert":      "Alert thresholds in percent of the period issuance, comma separated (default 50,80,100)",
+		"admin_billing_budget_set_flag_hard-cap":   "Points the scope may spend per issuance period; empty disables the cap",
+		"admin_billing_budget_set_flag_action":     "What happens to running jobs at the cap: stop or suspend (default stop)",
+		"admin_billing_budget_delete_flag_account": "Account ID the budget belongs to",
+		"admin_billing_budget_delete_flag_user":    "User ID of the member budget to delete (0 for the account-wide budget)",
+		"admin_billing_budget_delete_flag_yes":     "Skip confirmation",
+
+		"billing_budget_label_account": "account id",
+		"err_invalid_budget_percent":   "invalid alert threshold: %d (expected 1-1000)",
+		"err_invalid_budget_cap":       "invalid hard cap: %s (expected positive points)",
+		"err_invalid_budget_action":    "invalid cap action: %s (expected one of %s)",
+
+		"billing_budget_set_success":     "Budget saved for %s",
+		"billing_budget_delete_success":  "Budget deleted for %s",
+		"billing_budget_scope_account":   "whole account",
+		"billing_budget_scope_user":      "user %s",
+		"billing_budget_table_scope":     "SCOPE",
+		"billing_budget_table_alerts":    "ALERTS",
+		"billing_budget_table_cap":       "HARD_CAP",
+		"billing_budget_table_issued":    "ISSUED",
+		"billing_budget_table_spent":     "SPENT",
+		"billing_budget_table_projected": "PROJECTED",
+		"billing_budget_table_action":    "ACTION",
+	},
+	ZhCN: {
+		"billing_budget_short":              "管理账户预算与消费上限",
+		"billing_budget_ls_short":           "列出账户预算及当前消费",
+		"billing_budget_set_short":          "创建或更新预算",
+		"billing_budget_delete_short":       "删除预算",
+		"admin_billing_budget_short":        "管理任意账户的预算",
+		"admin_billing_budget_ls_short":     "列出账户预算及当前消费",
+		"admin_billing_budget_set_short":    "创建或更新预算",
+		"admin_billing_budget_delete_short": "删除预算",
+
+		"billing_budget_ls_flag_account":           "预算所属账户 ID",
+		"billing_budget_set_flag_account":          "预算所属账户 ID",
+		"billing_budget_set_flag_user":             "成员预算的用户 ID（0 表示整个账户）",
+		"billing_budget_set_flag_alert":            "告警阈值，占本周期发放额度的百分比，逗号分隔（默认 50,80,100）",
+		"billing_budget_set_flag_hard-cap":         "每个发放周期允许消费的点数上限，留空表示不限制",
+		"billing_budget_set_flag_action":           "达到上限后对运行中作业的处理：stop 或 suspend（默认 stop）",
+		"billing_budget_delete_flag_account":       "预算所属账户 ID",
+		"billing_budget_delete_flag_user":          "要删除的成员预算用户 ID（0 表示整个账户）",
+		"billing_budget_delete_flag_yes":           "跳过确认",
+		"admin_billing_budget_ls_flag_account":     "预算所属账户 ID",
+		"admin_billing_budget_set_flag_account":    "预算所属账户 ID",
+		"admin_billing_budget_set_flag_user":       "成员预算的用户 ID（0 表示整个账户）",
+		"admin_billing_budget_set_flag_alert":      "告警阈值，占本周期发放额度的百分比，逗号分隔（默认 50,80,100）",
+		"admin_billing_budget_set_flag_hard-cap":   "每个发放周期允许消费的点数上限，留空表示不限制",
+		"admin_billing_budget_set_flag_action":     "达到上限后对运行中作业的处理：stop 或 suspend（默认 stop）",
+		"admin_billing_budget_delete_flag_account": "预算所属账户 ID",
+		"admin_billing_budget_delete_flag_user":    "要删除的成员预算用户 ID（0 表示整个账户）",
+		"admin_billing_budget_delete_flag_yes":     "跳过确认",
+
+		"billing_budget_label_account": "账户 ID",
+		"err_invalid_budget_percent":   "无效的告警阈值：%d（范围 1-1000）",
+		"err_invalid_budget_cap":       "无效的消费上限：%s（须为正的点数）",
+		"err_invalid_budget_action":    "无效的上限动作：%s（可选 %s）",
+
+		"billing_budget_set_success":     "已保存%s的预算",
+		"billing_budget_delete_success":  "已删除%s的预算",
+		"billing_budget_scope_account":   "整个账户",
+		"billing_budget_scope_user":      "用户 %s",
+		"billing_budget_table_scope":     "范围",
+		"billing_budget_table_alerts":    "告警阈值",
+		"billing_budget_table_cap":       "上限",
+		"billing_budget_table_issued":    "发放",
+		"billing_budget_table_spent":     "已消费",
+		"billing_budget_table_projected": "预计",
+		"billing_budget_table_action":    "动作",
+	},
+}
@@ -0,0 +1,77 @@
+package i18n
+
+// billing statement domain: period statement export for users, accounts and admins.
+var catalogBillingStatement = map[Language]map[string]string{
+	En: {
+		"billing_statement_short":       "Export a billing statement for a period",
+		"admin_billing_statement_short": "Export the billing statement of a user or an account",
+
+		"billing_statement_flag_from":          "Start date (YYYY-MM-DD or RFC3339), defaults to the start of this month",
+		"billing_statement_flag_to":            "End date (YYYY-MM-DD is inclusive), defaults to now",
+		"billing_statement_flag_format":        "Output format: table, csv or json",
+		"billing_statement_flag_output":        "Write the CSV statement to a file instead of stdout",
+		"billing_statement_flag_account":       "Limit the statement to one account",
+		"billing_statement_flag_all-members":   "Export every member of --account (account administrators only)",
+		"admin_billing_statement_flag_from":    "Start date (YYYY-MM-DD or RFC3339), defaults to the start of this month",
+		"admin_billing_statement_flag_to":      "End date (YYYY-MM-DD is inclusive), defaults to now",
+		"admin_billing_statement_flag_format":  "Output format: table, csv or json",
+		"admin_billing_statement_flag_output":  "Write the CSV statement to a file instead of stdout",
+		"admin_billing_statement_flag_user":    "Username to export",
+		"admin_billing_statement_flag_account": "Account ID to export, or limit --user to one account",
+
+		"billing_statement_label_account": "account id",
+		"billing_statement_label_target":  "user or account",
+		"err_invalid_statement_date":      "invalid date: %s (expected YYYY-MM-DD or RFC3339)",
+		"err_invalid_statement_format":    "invalid format: %s (expected table, csv or json)",
+		"err_write_file":                  "failed to write %s: %s",
+
+		"billing_statement_saved":         "Statement saved to %s",
+		"billing_statement_period":        "Period",
+		"billing_statement_issued":        "Issued",
+		"billing_statement_adjusted":      "Adjusted",
+		"billing_statement_settled":       "Settled",
+		"billing_statement_net":           "Net",
+		"billing_statement_table_time":    "TIME",
+		"billing_statement_table_type":    "TYPE",
+		"billing_statement_table_user":    "USER",
+		"billing_statement_table_account": "ACCOUNT",
+		"billing_statement_table_amount":  "AMOUNT",
+		"billing_statement_table_detail":  "DETAIL",
+	},
+	ZhCN: {
+		"billing_statement_short":       "导出指定时间段的计费账单",
+		"admin_billing_statement_short": "导出用户或账户的计费账单",
+
+		"billing_statement_flag_from":          "开始日期（YYYY-MM-DD 或 RFC3339），默认本月初",
+		"billing_statement_flag_to":            "结束日期（YYYY-MM-DD 包含当天），默认当前时间",
+		"billing_statement_flag_format":        "输出格式：table、csv 或 json",
+		"billing_statement_flag_output":        "将 CSV 账单写入文件而非标准输出",
+		"billing_statement_flag_account":       "仅统计指定账户",
+		"billing_statement_flag_all-members":   "导出 --account 全部成员的账单（仅账户管理员）",
+		"admin_billing_statement_flag_from":    "开始日期（YYYY-MM-DD 或 RFC3339），默认本月初",
+		"admin_billing_statement_flag_to":      "结束日期（YYYY-MM-DD 包含当天），默认当前时间",
+		"admin_billing_statement_flag_format":  "输出格式：table、csv 或 json",
+		"admin_billing_statement_flag_output":  "将 CSV 账单写入文件而非标准输出",
+		"admin_billing_statement_flag_user":    "要导出的用户名",
+		"admin_billing_statement_flag_account": "要导出的账户 ID，或与 --user 一起限定账户",
+
+		"billing_statement_label_account": "账户 ID",
+		"billing_statement_label_target":  "用户或账户",
+		"err_invalid_statement_date":      "无效的日期：%s（格式应为 YYYY-MM-DD 或 RFC3339）",
+		"err_invalid_statement_format":    "无效的格式：%s（可选 table、csv、json）",
+		"err_write_file":                  "写入 %s 失败：%s",
+
+		"billing_statement_saved":         "账单已保存到 %s",
+		"billing_statement_period":        "统计区间",
+		"billing_statement_issued":        "发放",
+		"billing_statement_adjusted":      "调整",
+		"billing_statement_settled":       "结算",
+		"billing_statement_net":           "净变化",
+		"billing_statement_table_time":    "时间",
+		"billing_statement_table_type":    "类型",
+		"billing_statement_table_user":    "用户",
+		"billing_statement_table_account": "账户",
+		"billing_statement_table_amount":  "点数",
+		"billing_statement_table_detail":  "明细",
+	},
+}
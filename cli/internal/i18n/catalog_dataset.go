@@ -0,0 +1,65 @@
+package i18n
+
+// dataset domain: immutable dataset versions, content manifests and verification.
+var catalogDataset = map[Language]map[string]string{
+	En: {
+		"dataset_version_short":                 "List, inspect and publish dataset versions",
+		"dataset_version_long":                  "A dataset version is a read-only snapshot of the dataset content with a manifest of paths, sizes and sha256 hashes. Jobs mount it with --dataset id@version:mountPath and keep seeing the same files after the dataset changes.",
+		"dataset_version_ls_short":              "List the versions of a dataset",
+		"dataset_version_get_short":             "Show a dataset version and its manifest",
+		"dataset_version_publish_short":         "Publish the current content of a dataset as a version",
+		"dataset_version_publish_flag_describe": "Description of the version",
+		"dataset_version_publish_flag_hardlink": "Hardlink the files instead of copying them; saves space, but files modified in place change in the version too",
+
+		"dataset_version_published":       "Publishing version %s of dataset %d. Once `crater dataset version ls` shows it as Ready, mount it with --dataset %d@%s:<mountPath>",
+		"dataset_version_table_version":   "VERSION",
+		"dataset_version_table_files":     "FILES",
+		"dataset_version_table_published": "PUBLISHED",
+		"dataset_version_message":         "Message",
+		"dataset_version_describe":        "Description",
+
+		"dataset_manifest_short":          "Show the content manifest and integrity of a dataset",
+		"dataset_manifest_long":           "The storage server indexes every dataset and model in the background: file count, size, a digest over all files and, with --files, the path, size, sha256 and mtime of each file. The integrity is the result of the last verification.",
+		"dataset_manifest_flag_files":     "Also list every file with its size, mtime and sha256",
+		"dataset_verify_short":            "Rehash a dataset and compare it with its manifest",
+		"dataset_manifest_none":           "Dataset %d has not been indexed yet",
+		"dataset_manifest_digest":         "Digest",
+		"dataset_manifest_indexed":        "Indexed",
+		"dataset_manifest_integrity":      "Integrity",
+		"dataset_manifest_verified":       "Verified",
+		"dataset_manifest_verify_pending": "verification queued",
+		"dataset_manifest_problem":        "PROBLEM",
+		"dataset_manifest_mtime":          "MODIFIED",
+		"dataset_verify_queued":           "Verification of dataset %d queued. The storage server rehashes every file; check the result with `crater dataset manifest %d`",
+	},
+	ZhCN: {
+		"dataset_version_short":                 "查看和发布数据集版本",
+		"dataset_version_long":                  "数据集版本是数据集内容的只读快照，并附带记录路径、大小和 sha256 的清单。作业通过 --dataset id@version:mountPath 挂载版本，数据集之后发生变化也不会影响已发布的版本。",
+		"dataset_version_ls_short":              "列出数据集的版本",
+		"dataset_version_get_short":             "查看数据集版本及其清单",
+		"dataset_version_publish_short":         "将数据集当前内容发布为新版本",
+		"dataset_version_publish_flag_describe": "版本描述",
+		"dataset_version_publish_flag_hardlink": "使用硬链接而非复制文件；节省空间，但原地修改的文件也会改变该版本",
+
+		"dataset_version_published":       "正在发布数据集 %[2]d 的版本 %[1]s。`crater dataset version ls` 显示 Ready 后，即可通过 --dataset %[3]d@%[4]s:<mountPath> 挂载",
+		"dataset_version_table_version":   "版本",
+		"dataset_version_table_files":     "文件数",
+		"dataset_version_table_published": "发布时间",
+		"dataset_version_message":         "消息",
+		"dataset_version_describe":        "描述",
+
+		"dataset_manifest_short":          "查看数据集的内容清单与完整性",
+		"dataset_manifest_long":           "存储服务会在后台为每个数据集和模型建立索引：文件数、总大小、全部文件的整体摘要，以及（使用 --files 时）每个文件的路径、大小、sha256 和修改时间。完整性为最近一次校验的结果。",
+		"dataset_manifest_flag_files":     "同时列出每个文件的大小、修改时间和 sha256",
+		"dataset_verify_short":            "重新计算数据集的校验和并与清单比对",
+		"dataset_manifest_none":           "数据集 %d 尚未建立索引",
+		"dataset_manifest_digest":         "摘要",
+		"dataset_manifest_indexed":        "索引时间",
+		"dataset_manifest_integrity":      "完整性",
+		"dataset_manifest_verified":       "校验时间",
+		"dataset_manifest_verify_pending": "等待校验",
+		"dataset_manifest_problem":        "问题",
+		"dataset_manifest_mtime":          "修改时间",
+		"dataset_verify_queued":           "已提交数据集 %[1]d 的校验请求。存储服务将重新计算每个文件的校验和，可通过 `crater dataset manifest %[2]d` 查看结果",
+	},
+}
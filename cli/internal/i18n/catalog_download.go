@@ -7,7 +7,7 @@ var catalogDownload = map[Language]map[string]string{
 		"download_short":         "Create and manage model or dataset download tasks",
 		"download_long":          "Create and manage platform-side model or dataset download tasks.",
 		"download_create_short":  "Create a model or dataset download task",
-		"download_create_long":   "Submit a platform-side download task for a ModelScope or HuggingFace model or dataset.",
+		"download_create_long":   "Submit a platform-side download task for a ModelScope or HuggingFace model or dataset, or import one from an s3:// bucket prefix or an http(s):// URL passed as --source. The category may be given as an argument or with --category.",
 		"download_model_short":   "Create a model download task",
 		"download_dataset_short": "Create a dataset download task",
 		"download_ls_short":      "List model and dataset download tasks",
@@ -21,7 +21,7 @@ var catalogDownload = map[Language]map[string]string{
 		// Flag descriptions
 		"flag_name":          "Resource name in owner/name format",
 		"flag_category":      "Download category (model | dataset)",
-		"flag_source":        "Download source (modelscope | ms | huggingface | hf)",
+		"flag_source":        "Download source (modelscope | ms | huggingface | hf), or an s3:// or http(s):// URL",
 		"flag_revision":      "Optional branch, tag, or revision",
 		"flag_token":         "Optional access token for gated or private repositories",
 		"flag_token-env":     "Read the optional repository access token from an environment variable",
@@ -58,6 +58,22 @@ var catalogDownload = map[Language]map[string]string{
 		"err_download_wait_timeout":          "timed out waiting for download after %s",
 		"err_download_follow_json":           "--follow cannot be combined with --json",
 
+		// S3 and HTTP sources
+		"flag_endpoint":                           "S3-compatible service URL for s3:// sources, such as a MinIO endpoint",
+		"flag_region":                             "Bucket region for s3:// sources",
+		"flag_access-key":                         "Access key ID for s3:// sources; stored encrypted until the download is ready",
+		"flag_secret-key":                         "Secret access key for s3:// sources",
+		"flag_secret-key-env":                     "Read the secret access key for s3:// sources from an environment variable",
+		"flag_extract":                            "Extract the zip or tar archive fetched from an http(s):// source",
+		"flag_checksum":                           "Expected sha256 of the file fetched from an http(s):// source",
+		"download_label_secret_key_env":           "secret key environment variable",
+		"err_download_category_conflict":          "category argument %s conflicts with --category %s",
+		"err_download_flag_source":                "--%s is not supported for %s sources",
+		"err_download_s3_keys_pair":               "--access-key and --secret-key must be provided together",
+		"err_download_checksum":                   "invalid checksum %q: expected a sha256 digest, optionally prefixed with sha256:",
+		"err_download_secret_key_source_conflict": "use only one of --secret-key or --secret-key-env",
+		"err_download_secret_key_env_missing":     "secret key environment variable %s is not set or is empty",
+
 		// Human-readable messages
 		"download_task_summary":   "Download task:\n  ID: %d\n  Name: %s\n  Category: %s\n  Source: %s\n  Status: %s\n  Path: %s",
 		"download_remove_confirm": "Remove download task %d?",
@@ -76,7 +92,7 @@ var catalogDownload = map[Language]map[string]string{
 		"download_short":         "创建和管理模型或数据集下载任务",
 		"download_long":          "创建和管理平台侧执行的模型或数据集下载任务。",
 		"download_create_short":  "创建模型或数据集下载任务",
-		"download_create_long":   "提交平台侧下载任务，支持 ModelScope 或 Hugging Face 的模型与数据集。",
+		"download_create_long":   "提交平台侧下载任务，支持 ModelScope 或 Hugging Face 的模型与数据集，也可通过 --source 传入 s3:// 存储桶前缀或 http(s):// 地址导入。类别可作为参数或通过 --category 指定。",
 		"download_model_short":   "创建模型下载任务",
 		"download_dataset_short": "创建数据集下载任务",
 		"download_ls_short":      "列出模型和数据集下载任务",
@@ -90,7 +106,7 @@ var catalogDownload = map[Language]map[string]string{
 		// Flag descriptions
 		"flag_name":          "资源名称，格式为 owner/name",
 		"flag_category":      "下载类别 (model | dataset)",
-		"flag_source":        "下载来源 (modelscope | ms | huggingface | hf)",
+		"flag_source":        "下载来源 (modelscope | ms | huggingface | hf)，或 s3://、http(s):// 地址",
 		"flag_revision":      "可选的分支、tag 或 revision",
 		"flag_token":         "用于 gated 或私有仓库的可选访问令牌",
 		"flag_token-env":     "从环境变量读取可选的仓库访问令牌",
@@ -127,6 +143,22 @@ var catalogDownload = map[Language]map[string]string{
 		"err_download_wait_timeout":          "等待下载超时：%s",
 		"err_download_follow_json":           "--follow 不能与 --json 同时使用",
 
+		// S3 and HTTP sources
+		"flag_endpoint":                           "s3:// 来源使用的 S3 兼容服务地址，例如 MinIO 地址",
+		"flag_region":                             "s3:// 来源的存储桶区域",
+		"flag_access-key":                         "s3:// 来源的访问密钥 ID；下载完成前加密保存",
+		"flag_secret-key":                         "s3:// 来源的访问密钥",
+		"flag_secret-key-env":                     "从环境变量读取 s3:// 来源的访问密钥",
+		"flag_extract":                            "解压从 http(s):// 来源下载的 zip 或 tar 归档",
+		"flag_checksum":                           "http(s):// 来源文件的期望 sha256 校验和",
+		"download_label_secret_key_env":           "访问密钥环境变量",
+		"err_download_category_conflict":          "类别参数 %s 与 --category %s 冲突",
+		"err_download_flag_source":                "%[2]s 来源不支持 --%[1]s",
+		"err_download_s3_keys_pair":               "--access-key 与 --secret-key 必须同时提供",
+		"err_download_checksum":                   "无效的校验和 %q：应为 sha256 摘要，可带 sha256: 前缀",
+		"err_download_secret_key_source_conflict": "只能使用 --secret-key、--secret-key-env 中的一种",
+		"err_download_secret_key_env_missing":     "访问密钥环境变量 %s 未设置或为空",
+
 		// Human-readable messages
 		"download_task_summary":   "下载任务：\n  ID：%d\n  名称：%s\n  类别：%s\n  来源：%s\n  状态：%s\n  路径：%s",
 		"download_remove_confirm": "是否移除下载任务 %d？",
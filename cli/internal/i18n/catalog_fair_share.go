@@ -0,0 +1,37 @@
+package i18n
+
+// fair share domain: account and member weights for the prequeue fair-share activation policy.
+var catalogFairShare = map[Language]map[string]string{
+	En: {
+		"admin_account_fair-share_short":          "View and set fair-share weights of an account",
+		"admin_account_fair-share_get_short":      "Show shares, decayed GPU-hours and priorities",
+		"admin_account_fair-share_set_short":      "Set the share of an account or one of its members",
+		"admin_account_fair-share_set_flag_user":  "Member user ID (0 sets the share of the account itself)",
+		"admin_account_fair-share_set_flag_share": "Positive share weight; 0 restores the default share of 1",
+
+		"fair_share_label_share":    "share",
+		"err_invalid_fair_share":    "invalid share: %d (expected 0 or a positive weight)",
+		"fair_share_policy":         "Activation policy",
+		"fair_share_half_life":      "Usage half-life",
+		"fair_share_table_share":    "SHARE",
+		"fair_share_table_usage":    "GPU_HOURS",
+		"fair_share_table_factor":   "FACTOR",
+		"fair_share_table_priority": "PRIORITY",
+	},
+	ZhCN: {
+		"admin_account_fair-share_short":          "查看和设置账户的公平调度份额",
+		"admin_account_fair-share_get_short":      "查看份额、衰减后的 GPU 时与优先级",
+		"admin_account_fair-share_set_short":      "设置账户或其成员的份额",
+		"admin_account_fair-share_set_flag_user":  "成员用户 ID（0 表示设置账户本身的份额）",
+		"admin_account_fair-share_set_flag_share": "正整数份额；0 表示恢复默认份额 1",
+
+		"fair_share_label_share":    "份额",
+		"err_invalid_fair_share":    "无效的份额：%d（须为 0 或正整数）",
+		"fair_share_policy":         "激活策略",
+		"fair_share_half_life":      "用量半衰期",
+		"fair_share_table_share":    "份额",
+		"fair_share_table_usage":    "GPU 时",
+		"fair_share_table_factor":   "因子",
+		"fair_share_table_priority": "优先级",
+	},
+}
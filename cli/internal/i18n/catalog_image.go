@@ -89,6 +89,69 @@ var catalogImage = map[Language]map[string]string{
 		"table_image_label":            "IMAGE_LABEL",
 		"table_value":                  "VALUE",
 		"table_nickname":               "NICKNAME",
+
+		// image scans: package inventory, vulnerability findings and the publishing policy
+		"image_scan_short":                            "Show the package and vulnerability scan of an image",
+		"image_scan_flag_rescan":                      "Queue a new scan instead of showing the report",
+		"image_scan_flag_packages":                    "Also list the package inventory",
+		"image_scan_flag_severity":                    "Only show findings at or above this severity",
+		"admin_image_scan_short":                      "Show the package and vulnerability scan of any image",
+		"admin_image_scan_flag_rescan":                "Queue a new scan instead of showing the report",
+		"admin_image_scan_flag_packages":              "Also list the package inventory",
+		"admin_image_scan_flag_severity":              "Only show findings at or above this severity",
+		"admin_image_scan-policy_short":               "View or set the scan severity that blocks publishing images",
+		"admin_image_scan-policy_flag_block-severity": "Block publishing images with findings at or above this severity; none disables the check",
+		"image_scan_output_scanned":                   "Scanned",
+		"image_scan_output_message":                   "Message",
+		"image_scan_output_digest":                    "Digest",
+		"image_scan_output_os":                        "OS",
+		"image_scan_output_packages":                  "Packages",
+		"image_scan_output_database":                  "Vulnerability database",
+		"image_scan_output_summary":                   "Findings",
+		"image_scan_output_scanner":                   "Scanner enabled",
+		"image_scan_output_block":                     "Block publishing at",
+		"image_scan_table_severity":                   "SEVERITY",
+		"image_scan_table_vulnerability":              "VULNERABILITY",
+		"image_scan_table_package":                    "PACKAGE",
+		"image_scan_table_version":                    "VERSION",
+		"image_scan_table_fixed":                      "FIXED",
+		"image_scan_table_title":                      "TITLE",
+		"image_scan_table_source":                     "SOURCE",
+
+		// image garbage collection: dry-run report of the clean-stale-images cron job
+		"admin_image_gc-report_short":                "Show the images the clean-stale-images cron job would delete",
+		"admin_image_gc-report_flag_keep-last":       "Override the number of newest images kept per repository",
+		"admin_image_gc-report_flag_unused-days":     "Override the days after which images no job used are deleted",
+		"admin_image_gc-report_flag_delete-untagged": "Override whether images no registry tag points at are deleted",
+		"admin_image_gc-report_flag_skip-shared":     "Override whether public and shared images are kept",
+		"admin_image_gc-report_flag_notice-days":     "Override the days between notifying owners and deleting",
+		"image_gc_output_schedule":                   "Schedule",
+		"image_gc_output_policy":                     "Policy",
+		"image_gc_output_candidates":                 "To delete",
+		"image_gc_output_not_notified":               "* owner not notified yet; the next run notifies them and deletion waits for the notice period",
+		"image_gc_table_last_used":                   "LAST_USED",
+		"image_gc_table_delete_after":                "DELETE_AFTER",
+
+		// multi-platform builds with a per-user registry build cache
+		"image_flag_no-cache": "Ignore cached layers; the build cache is still refreshed",
+
+		// builds from a Git repository
+		"image_build_git_short":           "Build an image from a Dockerfile in a Git repository",
+		"image_flag_repo":                 "Git repository URL (http or https)",
+		"image_flag_ref":                  "Branch, tag or commit; defaults to the default branch",
+		"image_flag_subdir":               "Build context directory inside the repository",
+		"image_build_git_flag_dockerfile": "Dockerfile path relative to --subdir (default Dockerfile)",
+		"image_flag_credential-secret":    "Git credential saved with image git-credential set, for private repositories",
+
+		// credentials for private Git repositories
+		"image_git-credential_short":             "Manage credentials for building from private Git repositories",
+		"image_git-credential_ls_short":          "List your Git credentials",
+		"image_git-credential_set_short":         "Create or update a Git credential, reading the password or token from stdin",
+		"image_git-credential_set_flag_username": "Username of the Git server",
+		"image_git-credential_rm_short":          "Delete a Git credential",
+		"image_label_git_credential":             "credential name",
+		"err_image_git_credential_stdin_read":    "failed to read the password from stdin: %s",
+		"err_image_git_credential_stdin_empty":   "password read from stdin is empty",
 	},
 	ZhCN: {
 		"image_build_short":              "管理镜像构建",
@@ -178,5 +241,68 @@ var catalogImage = map[Language]map[string]string{
 		"table_image_label":            "镜像标签",
 		"table_value":                  "值",
 		"table_nickname":               "昵称",
+
+		// image scans: package inventory, vulnerability findings and the publishing policy
+		"image_scan_short":                            "查看镜像的软件包与漏洞扫描报告",
+		"image_scan_flag_rescan":                      "重新排队扫描，而不是显示报告",
+		"image_scan_flag_packages":                    "同时列出软件包清单",
+		"image_scan_flag_severity":                    "只显示不低于该级别的漏洞",
+		"admin_image_scan_short":                      "查看任意镜像的软件包与漏洞扫描报告",
+		"admin_image_scan_flag_rescan":                "重新排队扫描，而不是显示报告",
+		"admin_image_scan_flag_packages":              "同时列出软件包清单",
+		"admin_image_scan_flag_severity":              "只显示不低于该级别的漏洞",
+		"admin_image_scan-policy_short":               "查看或设置禁止公开镜像的漏洞级别",
+		"admin_image_scan-policy_flag_block-severity": "存在不低于该级别漏洞的镜像禁止公开；none 表示不限制",
+		"image_scan_output_scanned":                   "扫描时间",
+		"image_scan_output_message":                   "消息",
+		"image_scan_output_digest":                    "摘要",
+		"image_scan_output_os":                        "操作系统",
+		"image_scan_output_packages":                  "软件包数",
+		"image_scan_output_database":                  "漏洞库",
+		"image_scan_output_summary":                   "漏洞统计",
+		"image_scan_output_scanner":                   "扫描器已启用",
+		"image_scan_output_block":                     "禁止公开的级别",
+		"image_scan_table_severity":                   "级别",
+		"image_scan_table_vulnerability":              "漏洞",
+		"image_scan_table_package":                    "软件包",
+		"image_scan_table_version":                    "版本",
+		"image_scan_table_fixed":                      "修复版本",
+		"image_scan_table_title":                      "标题",
+		"image_scan_table_source":                     "源码包",
+
+		// image garbage collection: dry-run report of the clean-stale-images cron job
+		"admin_image_gc-report_short":                "查看 clean-stale-images 定时任务将删除的镜像",
+		"admin_image_gc-report_flag_keep-last":       "临时覆盖每个仓库保留的最新镜像数",
+		"admin_image_gc-report_flag_unused-days":     "临时覆盖未被作业使用多少天后删除",
+		"admin_image_gc-report_flag_delete-untagged": "临时覆盖是否删除没有仓库标签指向的镜像",
+		"admin_image_gc-report_flag_skip-shared":     "临时覆盖是否保留公开或共享的镜像",
+		"admin_image_gc-report_flag_notice-days":     "临时覆盖通知所有者后多少天删除",
+		"image_gc_output_schedule":                   "调度",
+		"image_gc_output_policy":                     "策略",
+		"image_gc_output_candidates":                 "待删除",
+		"image_gc_output_not_notified":               "* 尚未通知所有者；下次运行时发送通知，通知期满后才会删除",
+		"image_gc_table_last_used":                   "最近使用",
+		"image_gc_table_delete_after":                "最早删除时间",
+
+		// multi-platform builds with a per-user registry build cache
+		"image_flag_no-cache": "不使用已缓存的层，但仍会刷新构建缓存",
+
+		// builds from a Git repository
+		"image_build_git_short":           "基于 Git 仓库中的 Dockerfile 构建镜像",
+		"image_flag_repo":                 "Git 仓库地址（http 或 https）",
+		"image_flag_ref":                  "分支、标签或提交，默认使用仓库默认分支",
+		"image_flag_subdir":               "仓库内作为构建上下文的目录",
+		"image_build_git_flag_dockerfile": "相对于 --subdir 的 Dockerfile 路径（默认 Dockerfile）",
+		"image_flag_credential-secret":    "通过 image git-credential set 保存的 Git 凭据，用于私有仓库",
+
+		// credentials for private Git repositories
+		"image_git-credential_short":             "管理用于私有 Git 仓库构建的凭据",
+		"image_git-credential_ls_short":          "列出本人的 Git 凭据",
+		"image_git-credential_set_short":         "创建或更新 Git 凭据，密码或访问令牌从 stdin 读取",
+		"image_git-credential_set_flag_username": "Git 服务器的用户名",
+		"image_git-credential_rm_short":          "删除 Git 凭据",
+		"image_label_git_credential":             "凭据名称",
+		"err_image_git_credential_stdin_read":    "从 stdin 读取密码失败：%s",
+		"err_image_git_credential_stdin_empty":   "从 stdin 读取到的密码为空",
 	},
 }
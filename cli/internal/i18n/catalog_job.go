@@ -6,7 +6,7 @@ var catalogJob = map[Language]map[string]string{
 		"flag_batch-days":       "Batch job running days threshold",
 		"flag_command":          "Command to run",
 		"flag_cpu-pinning":      "Enable CPU pinning",
-		"flag_dataset":          "Dataset mount id:mountPath, repeatable",
+		"flag_dataset":          "Dataset mount id[@version]:mountPath, repeatable; a version mounts its read-only snapshot",
 		"flag_env":              "Environment variable KEY=VALUE, repeatable",
 		"flag_file":             "Read exact JSON request body from file",
 		"flag_forward":          "Forward name:port, repeatable",
@@ -151,7 +151,7 @@ var catalogJob = map[Language]map[string]string{
 		"flag_batch-days":       "批处理作业运行天数阈值",
 		"flag_command":          "要运行的命令",
 		"flag_cpu-pinning":      "开启 CPU 绑核",
-		"flag_dataset":          "数据集挂载 id:mountPath，可重复",
+		"flag_dataset":          "数据集挂载 id[@version]:mountPath，可重复；指定版本时挂载该版本的只读快照",
 		"flag_env":              "环境变量 KEY=VALUE，可重复",
 		"flag_file":             "从文件读取完整 JSON 请求体",
 		"flag_forward":          "端口转发 name:port，可重复",
@@ -0,0 +1,58 @@
+package i18n
+
+var catalogJobArray = map[Language]map[string]string{
+	En: {
+		"job_array_short":         "Manage job arrays",
+		"job_array_long":          "List, inspect, bill and cancel job arrays created with `job create custom --array-*`.",
+		"job_array_ls_short":      "List job arrays",
+		"job_array_get_short":     "Get a job array and its elements",
+		"job_array_billing_short": "Show the billed points of a job array",
+		"job_array_cancel_short":  "Cancel every element of a job array",
+
+		"job_array_cancel_flag_yes":                "Skip confirmation",
+		"job_create_custom_flag_array-count":       "Submit N copies indexed by CRATER_ARRAY_INDEX",
+		"job_create_custom_flag_array-param":       "Sweep parameter NAME=v1,v2 injected as env, repeatable",
+		"job_create_custom_flag_array-parallelism": "Maximum number of array elements running at once",
+
+		"job_array_label_id":            "job array id",
+		"job_array_label_count":         "array count or array param",
+		"err_invalid_array_param":       "invalid array param: %s (expected NAME=v1,v2)",
+		"err_job_array_count":           "array count must not be negative",
+		"err_job_array_count_and_param": "array count and array param cannot be used together",
+		"err_job_array_parallelism":     "array parallelism must not be negative",
+
+		"job_array_table_size":     "SIZE/PAR",
+		"job_array_table_index":    "INDEX",
+		"job_array_table_params":   "PARAMS",
+		"job_array_table_billed":   "BILLED POINTS",
+		"job_array_create_success": "Created job array: %s",
+		"job_array_cancel_success": "Canceled job array: %s",
+	},
+	ZhCN: {
+		"job_array_short":         "管理作业数组",
+		"job_array_long":          "列出、查看、计费和取消通过 `job create custom --array-*` 创建的作业数组。",
+		"job_array_ls_short":      "列出作业数组",
+		"job_array_get_short":     "查看作业数组及其元素",
+		"job_array_billing_short": "查看作业数组的计费点数",
+		"job_array_cancel_short":  "取消作业数组的全部元素",
+
+		"job_array_cancel_flag_yes":                "跳过确认",
+		"job_create_custom_flag_array-count":       "提交 N 个副本，以 CRATER_ARRAY_INDEX 区分",
+		"job_create_custom_flag_array-param":       "扫描参数 NAME=v1,v2，以环境变量注入，可重复",
+		"job_create_custom_flag_array-parallelism": "同时运行的数组元素上限",
+
+		"job_array_label_id":            "作业数组 ID",
+		"job_array_label_count":         "数组数量或数组参数",
+		"err_invalid_array_param":       "无效的数组参数: %s（应为 NAME=v1,v2）",
+		"err_job_array_count":           "数组数量不能为负数",
+		"err_job_array_count_and_param": "数组数量与数组参数不能同时使用",
+		"err_job_array_parallelism":     "数组并发数不能为负数",
+
+		"job_array_table_size":     "规模/并发",
+		"job_array_table_index":    "序号",
+		"job_array_table_params":   "参数",
+		"job_array_table_billed":   "计费点数",
+		"job_array_create_success": "已创建作业数组: %s",
+		"job_array_cancel_success": "已取消作业数组: %s",
+	},
+}
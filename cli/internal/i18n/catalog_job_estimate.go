@@ -0,0 +1,27 @@
+package i18n
+
+// job estimate domain: dry-run pricing of create payloads.
+var catalogJobEstimate = map[Language]map[string]string{
+	En: {
+		"job_create_custom_flag_estimate":     "Price the job with the current unit prices instead of submitting it",
+		"job_create_tensorflow_flag_estimate": "Price the job with the current unit prices instead of submitting it",
+		"job_create_pytorch_flag_estimate":    "Price the job with the current unit prices instead of submitting it",
+
+		"job_estimate_not_billed":    "This job is not billed (billing inactive, backfill or unpriced resources)",
+		"job_estimate_cost_per_hour": "Cost per hour",
+		"job_estimate_free_minutes":  "Free minutes",
+		"job_estimate_balance":       "Balance (free/extra)",
+		"job_estimate_covered_hours": "Hours covered",
+	},
+	ZhCN: {
+		"job_create_custom_flag_estimate":     "按当前单价估算费用，不提交作业",
+		"job_create_tensorflow_flag_estimate": "按当前单价估算费用，不提交作业",
+		"job_create_pytorch_flag_estimate":    "按当前单价估算费用，不提交作业",
+
+		"job_estimate_not_billed":    "该作业不计费（计费未启用、backfill 作业或资源未定价）",
+		"job_estimate_cost_per_hour": "每小时费用",
+		"job_estimate_free_minutes":  "免费分钟数",
+		"job_estimate_balance":       "余额（周期/额外）",
+		"job_estimate_covered_hours": "余额可运行小时数",
+	},
+}
@@ -0,0 +1,39 @@
+package i18n
+
+// job queue domain: queue position, blocking reason and estimated start of prequeued jobs.
+var catalogJobQueue = map[Language]map[string]string{
+	En: {
+		"job_queue_short":       "Show queue position, blocking reason and estimated start of my prequeued jobs",
+		"admin_job_queue_short": "Show queue position, blocking reason and estimated start of all prequeued jobs",
+
+		"job_queue_empty":          "No prequeued jobs",
+		"job_queue_table_position": "POS",
+		"job_queue_table_reason":   "REASON",
+		"job_queue_table_eta":      "EST_START",
+		"job_queue_table_detail":   "DETAIL",
+		"job_queue_eta_now":        "now",
+
+		"job_queue_reason_waiting":           "waiting",
+		"job_queue_reason_quota":             "quota",
+		"job_queue_reason_timed_out_blocker": "timed-out blocker",
+		"job_queue_reason_node_fit":          "no node fits",
+		"job_queue_reason_held":              "held",
+	},
+	ZhCN: {
+		"job_queue_short":       "查看我的排队作业的位置、阻塞原因与预计开始时间",
+		"admin_job_queue_short": "查看所有排队作业的位置、阻塞原因与预计开始时间",
+
+		"job_queue_empty":          "没有排队中的作业",
+		"job_queue_table_position": "位置",
+		"job_queue_table_reason":   "原因",
+		"job_queue_table_eta":      "预计开始",
+		"job_queue_table_detail":   "详情",
+		"job_queue_eta_now":        "即将",
+
+		"job_queue_reason_waiting":           "等待激活",
+		"job_queue_reason_quota":             "配额不足",
+		"job_queue_reason_timed_out_blocker": "让行超时作业",
+		"job_queue_reason_node_fit":          "无可用节点",
+		"job_queue_reason_held":              "等待上游",
+	},
+}
@@ -0,0 +1,93 @@
+package i18n
+
+// config notifications domain: subscription rules and delivery channels.
+var catalogNotifications = map[Language]map[string]string{
+	En: {
+		"config_notifications_short":              "Manage notification rules and channels",
+		"config_notifications_long":               "Subscribe to job, billing and approval events per user or per account, with quiet hours and digest batching.",
+		"config_notifications_ls_short":           "List notification rules",
+		"config_notifications_events_short":       "List subscribable notification events",
+		"config_notifications_set_short":          "Create or update a notification rule",
+		"config_notifications_delete_short":       "Delete a notification rule",
+		"config_notifications_channels_short":     "Show notification channels",
+		"config_notifications_channels_set_short": "Replace notification channels",
+
+		"config_notifications_set_flag_account":           "Account ID the rule applies to (0 for all accounts)",
+		"config_notifications_set_flag_events":            "Subscribed events, comma separated",
+		"config_notifications_set_flag_queued-minutes":    "Minutes a job may wait in queue before job_queued_too_long fires",
+		"config_notifications_set_flag_balance-threshold": "Points below which billing_balance_low fires",
+		"config_notifications_set_flag_quiet":             "Quiet hours as HH:MM-HH:MM; notifications are held until they end",
+		"config_notifications_set_flag_digest":            "Batch notifications into a digest every N minutes (0 sends immediately)",
+		"config_notifications_delete_flag_account":        "Account ID of the rule to delete (0 for the user-wide rule)",
+		"config_notifications_delete_flag_yes":            "Skip confirmation",
+		"config_notifications_channels_set_flag_channel":  "Channel as TYPE=URL in fallback order, repeatable",
+		"config_notifications_channels_set_flag_file":     "JSON file with the full channel list, including secrets",
+		"config_notifications_channels_set_flag_clear":    "Remove all channels and fall back to email",
+
+		"notifications_label_events":       "notification events",
+		"notifications_label_channel":      "notification channel",
+		"err_invalid_notification_event":   "invalid notification event: %s (expected one of: %s)",
+		"err_notification_negative":        "%s must not be negative",
+		"err_invalid_quiet_hours":          "invalid quiet hours: %s (expected HH:MM-HH:MM)",
+		"err_invalid_notification_channel": "invalid notification channel: %s (expected TYPE=URL)",
+		"err_notification_channel_source":  "--channel, --file and --clear cannot be used together",
+
+		"notifications_scope_user":           "all accounts",
+		"notifications_scope_account":        "account %s",
+		"notifications_set_success":          "Saved notification rule for %s",
+		"notifications_delete_success":       "Deleted notification rule for %s",
+		"notifications_channels_set_success": "Saved %d notification channel(s)",
+		"notifications_channels_empty":       "No channels configured; notifications go to your email.",
+		"notifications_table_scope":          "SCOPE",
+		"notifications_table_quiet":          "QUIET",
+		"notifications_table_digest":         "DIGEST",
+		"notifications_table_queued":         "QUEUED",
+		"notifications_table_balance":        "BALANCE",
+		"notifications_table_events":         "EVENTS",
+		"notifications_table_type":           "TYPE",
+	},
+	ZhCN: {
+		"config_notifications_short":              "管理通知订阅规则与渠道",
+		"config_notifications_long":               "按用户或账户订阅作业、计费与审批事件，支持免打扰时段与摘要合并。",
+		"config_notifications_ls_short":           "列出通知规则",
+		"config_notifications_events_short":       "列出可订阅的通知事件",
+		"config_notifications_set_short":          "创建或更新通知规则",
+		"config_notifications_delete_short":       "删除通知规则",
+		"config_notifications_channels_short":     "查看通知渠道",
+		"config_notifications_channels_set_short": "替换通知渠道",
+
+		"config_notifications_set_flag_account":           "规则适用的账户 ID（0 表示所有账户）",
+		"config_notifications_set_flag_events":            "订阅的事件，逗号分隔",
+		"config_notifications_set_flag_queued-minutes":    "作业排队超过多少分钟触发 job_queued_too_long",
+		"config_notifications_set_flag_balance-threshold": "余额低于多少点数触发 billing_balance_low",
+		"config_notifications_set_flag_quiet":             "免打扰时段，格式 HH:MM-HH:MM，期间通知延后发送",
+		"config_notifications_set_flag_digest":            "每 N 分钟合并为一条摘要发送（0 表示立即发送）",
+		"config_notifications_delete_flag_account":        "要删除规则的账户 ID（0 表示用户级规则）",
+		"config_notifications_delete_flag_yes":            "跳过确认",
+		"config_notifications_channels_set_flag_channel":  "渠道，格式 TYPE=URL，按回退顺序重复指定",
+		"config_notifications_channels_set_flag_file":     "包含完整渠道列表（含密钥）的 JSON 文件",
+		"config_notifications_channels_set_flag_clear":    "清空所有渠道，仅使用邮件",
+
+		"notifications_label_events":       "通知事件",
+		"notifications_label_channel":      "通知渠道",
+		"err_invalid_notification_event":   "无效的通知事件：%s（可选值：%s）",
+		"err_notification_negative":        "%s 不能为负数",
+		"err_invalid_quiet_hours":          "无效的免打扰时段：%s（格式应为 HH:MM-HH:MM）",
+		"err_invalid_notification_channel": "无效的通知渠道：%s（格式应为 TYPE=URL）",
+		"err_notification_channel_source":  "--channel、--file 与 --clear 不能同时使用",
+
+		"notifications_scope_user":           "所有账户",
+		"notifications_scope_account":        "账户 %s",
+		"notifications_set_success":          "已保存%s的通知规则",
+		"notifications_delete_success":       "已删除%s的通知规则",
+		"notifications_channels_set_success": "已保存 %d 个通知渠道",
+		"notifications_channels_empty":       "未配置通知渠道，通知将发送到邮箱。",
+		"notifications_table_scope":          "范围",
+		"notifications_table_quiet":          "免打扰",
+		"notifications_table_digest":         "摘要",
+		"notifications_table_queued":         "排队",
+		"notifications_table_balance":        "余额阈值",
+		"notifications_table_events":         "事件",
+		"notifications_table_type":           "类型",
+	},
+}
@@ -8,6 +8,11 @@ var catalogOrder = map[Language]map[string]string{
 		"admin_order_approve_short": "Approve an approval order",
 		"admin_order_reject_short":  "Reject an approval order",
 		"admin_order_check_short":   "Cancel invalid pending job approval orders",
+		"order_assigned_short":      "List approval orders assigned to you for review",
+		"order_approve_short":       "Approve an approval order assigned to you",
+		"order_reject_short":        "Reject an approval order assigned to you",
+		"dataset_request_short":     "Request read access to a dataset",
+		"dataset_request_long":      "Submit a dataset access request for yourself or, with --account, for a queue you belong to. The request is routed to the dataset owner; once approved the dataset can be mounted until the optional expiry.",
 
 		"order_submit_flag_name":                "Approval target name",
 		"order_submit_flag_type":                "Approval order type",
@@ -29,6 +34,13 @@ var catalogOrder = map[Language]map[string]string{
 		"admin_order_approve_flag_minutes":      "Lock duration minutes",
 		"admin_order_reject_flag_review-notes":  "Review notes",
 		"admin_order_check_flag_yes":            "Skip confirmation",
+		"admin_order_approve_flag_expires":      "Expiry of the granted dataset access (RFC3339 or YYYY-MM-DD), overriding the requested one",
+		"order_approve_flag_review-notes":       "Review notes",
+		"order_approve_flag_expires":            "Expiry of the granted dataset access (RFC3339 or YYYY-MM-DD), overriding the requested one",
+		"order_reject_flag_review-notes":        "Review notes",
+		"dataset_request_flag_reason":           "Why you need the dataset",
+		"dataset_request_flag_account":          "Request access for this queue (account ID) instead of yourself",
+		"dataset_request_flag_expires":          "When the access should end (RFC3339 or YYYY-MM-DD); omit for permanent access",
 
 		"err_invalid_order_type":     "invalid approval order type: %s",
 		"err_invalid_edit_status":    "invalid editable approval order status: %s",
@@ -53,6 +65,11 @@ var catalogOrder = map[Language]map[string]string{
 		"admin_order_approve_short": "批准审批工单",
 		"admin_order_reject_short":  "拒绝审批工单",
 		"admin_order_check_short":   "取消已失效的待审批作业工单",
+		"order_assigned_short":      "列出指派给我审批的工单",
+		"order_approve_short":       "批准指派给我的审批工单",
+		"order_reject_short":        "拒绝指派给我的审批工单",
+		"dataset_request_short":     "申请数据集的读取权限",
+		"dataset_request_long":      "为自己或（使用 --account 时）为所在的队列提交数据集访问申请。申请由数据集所有者审批，批准后可挂载该数据集，直至可选的到期时间。",
 
 		"order_submit_flag_name":                "审批目标名称",
 		"order_submit_flag_type":                "审批工单类型",
@@ -74,6 +91,13 @@ var catalogOrder = map[Language]map[string]string{
 		"admin_order_approve_flag_minutes":      "锁定时长分钟数",
 		"admin_order_reject_flag_review-notes":  "审核备注",
 		"admin_order_check_flag_yes":            "跳过确认",
+		"admin_order_approve_flag_expires":      "授予的数据集访问权限的到期时间（RFC3339 或 YYYY-MM-DD），覆盖申请中的到期时间",
+		"order_approve_flag_review-notes":       "审核备注",
+		"order_approve_flag_expires":            "授予的数据集访问权限的到期时间（RFC3339 或 YYYY-MM-DD），覆盖申请中的到期时间",
+		"order_reject_flag_review-notes":        "审核备注",
+		"dataset_request_flag_reason":           "申请该数据集的原因",
+		"dataset_request_flag_account":          "为该队列（账户 ID）而非本人申请",
+		"dataset_request_flag_expires":          "访问权限的到期时间（RFC3339 或 YYYY-MM-DD），不填表示永久",
 
 		"err_invalid_order_type":     "无效的审批工单类型：%s",
 		"err_invalid_edit_status":    "无效的可编辑审批工单状态：%s",
@@ -0,0 +1,37 @@
+package i18n
+
+// share domain: time-limited dataset and image shares and their expiry.
+var catalogShare = map[Language]map[string]string{
+	En: {
+		"share_short":                    "View time-limited dataset and image shares",
+		"share_expiring_short":           "List shares that are about to expire",
+		"share_expiring_long":            "List time-limited shares of your datasets and images, and shares you or your queues receive, that expire within --days. Expired shares are removed by the revoke-expired-shares cron job.",
+		"share_expiring_flag_days":       "Look-ahead window in days",
+		"admin_share_short":              "View all time-limited shares",
+		"admin_share_expiring_short":     "List all shares that are about to expire",
+		"admin_share_expiring_flag_days": "Look-ahead window in days",
+		"image_share_add_flag_expires":   "When the share ends (RFC3339 or YYYY-MM-DD); omit for a permanent share",
+
+		"share_table_target":      "TARGET",
+		"share_table_target_name": "TARGET_NAME",
+		"share_table_expires":     "EXPIRES",
+		"err_share_days":          "invalid --days %d: use 1 to 365",
+		"err_expires_past":        "--expires %s is not in the future",
+	},
+	ZhCN: {
+		"share_short":                    "查看限时的数据集与镜像分享",
+		"share_expiring_short":           "列出即将到期的分享",
+		"share_expiring_long":            "列出在 --days 天内到期的限时分享，包括你的数据集和镜像的分享，以及你或你所在队列收到的分享。到期的分享由 revoke-expired-shares 定时任务移除。",
+		"share_expiring_flag_days":       "向后查看的天数",
+		"admin_share_short":              "查看全部限时分享",
+		"admin_share_expiring_short":     "列出全部即将到期的分享",
+		"admin_share_expiring_flag_days": "向后查看的天数",
+		"image_share_add_flag_expires":   "分享的到期时间（RFC3339 或 YYYY-MM-DD），不填表示永久分享",
+
+		"share_table_target":      "分享对象",
+		"share_table_target_name": "对象名称",
+		"share_table_expires":     "到期时间",
+		"err_share_days":          "无效的 --days %d：取值范围为 1 到 365",
+		"err_expires_past":        "--expires %s 不是将来的时间",
+	},
+}
@@ -0,0 +1,115 @@
+package i18n
+
+// storage domain: user and account space usage and quotas on the shared filesystem.
+var catalogStorage = map[Language]map[string]string{
+	En: {
+		"storage_short":                        "View storage usage and upload files",
+		"storage_long":                         "View the usage and quotas of your user and account spaces on the shared filesystem, upload large files in resumable chunks, download directories as archives, restore deleted files from the trash, and get an access key for the S3 gateway.",
+		"storage_usage_short":                  "Show usage and quotas of my user and account spaces",
+		"storage_upload_short":                 "Upload a file in resumable chunks",
+		"storage_upload_long":                  "Upload a local file to a storage path such as user/ckpt/model.bin in resumable chunks. A remote path ending in / keeps the local file name. Rerunning the same upload after an interruption continues from the last chunk the server received.",
+		"storage_upload_flag_chunk-size":       "Chunk size, e.g. 8Mi or 64Mi",
+		"storage_upload_flag_retries":          "Retries of a failed chunk before giving up",
+		"admin_storage_short":                  "View and set storage quotas",
+		"admin_storage_usage_short":            "Show usage and quotas of all user or account spaces",
+		"admin_storage_usage_flag_accounts":    "List account spaces instead of user spaces",
+		"admin_storage_quota_short":            "Manage storage quotas",
+		"admin_storage_quota_set_short":        "Set the byte and inode quota of a user or account space",
+		"admin_storage_quota_set_flag_user":    "Username whose user space is limited",
+		"admin_storage_quota_set_flag_account": "Account name whose account space is limited",
+		"admin_storage_quota_set_flag_bytes":   "Byte quota, e.g. 500Gi or 2T; 0 removes the limit",
+		"admin_storage_quota_set_flag_inodes":  "Maximum number of files and directories; 0 removes the limit",
+
+		"storage_label_space":                "user or account",
+		"storage_label_limit":                "bytes or inodes",
+		"err_invalid_byte_size":              "invalid byte size: %s (expected a number with an optional Ki/Mi/Gi/Ti or K/M/G/T suffix)",
+		"err_invalid_inode_quota":            "invalid inode quota: %d (expected 0 or a positive count)",
+		"storage_table_space":                "SPACE",
+		"storage_table_kind":                 "KIND",
+		"storage_table_bytes":                "BYTES",
+		"storage_table_inodes":               "INODES",
+		"storage_table_scanned":              "SCANNED",
+		"storage_not_scanned":                "not yet",
+		"err_upload_not_a_file":              "%s is a directory, not a file",
+		"storage_upload_resumed":             "Resumed an interrupted upload.",
+		"storage_upload_done":                "Uploaded %s (%s, sha256 %s)",
+		"storage_trash_short":                "List and restore deleted files",
+		"storage_trash_ls_short":             "List deleted files of my user and account spaces",
+		"storage_trash_restore_short":        "Restore a deleted file or directory",
+		"storage_trash_restore_flag_to":      "Restore to this path, e.g. user/restored/a.txt, instead of the original one",
+		"storage_table_path":                 "PATH",
+		"storage_table_deleted":              "DELETED",
+		"storage_table_expires":              "EXPIRES",
+		"err_invalid_trash_id":               "invalid trash item ID: %s",
+		"storage_trash_restored":             "Restored %s",
+		"storage_s3-credentials_short":       "Show my S3 access key for the current account",
+		"storage_s3-credentials_long":        "Show the S3 access key of the current account. S3 clients such as boto3, rclone and aws s3 can use it against the storage server's S3 gateway with path-style addressing. The buckets are user, account and public. The key keeps working while you belong to the account, until you rotate it.",
+		"storage_s3-credentials_flag_rotate": "Replace the key with a new one; the old key stops working right away",
+		"storage_s3_endpoint":                "ENDPOINT",
+		"storage_s3_buckets":                 "BUCKETS",
+		"storage_s3_env_hint":                "Environment for S3 clients:",
+		"storage_pull_short":                 "Download a file or directory as one archive",
+		"storage_pull_long":                  "Download a storage path such as user/results in a single streamed archive and unpack it into the output directory. With --archive the tar.gz or zip file is saved as is instead. Existing local files are kept unless --force is set.",
+		"storage_pull_flag_output":           "Directory to unpack into",
+		"storage_pull_flag_archive":          "Save the archive to this file instead of unpacking; a .zip name requests zip",
+		"storage_pull_flag_force":            "Overwrite existing local files",
+		"storage_pull_done":                  "Pulled %d files (%s) into %s",
+		"storage_pull_saved":                 "Saved %s (%s)",
+		"err_pull_exists":                    "%s already exists, use --force to overwrite it",
+	},
+	ZhCN: {
+		"storage_short":                        "查看存储用量并上传文件",
+		"storage_long":                         "查看共享文件系统上个人空间与账户空间的用量和配额，以可续传的分块方式上传大文件，将目录打包下载，从回收站恢复已删除的文件，并获取 S3 网关的访问密钥。",
+		"storage_usage_short":                  "查看我的个人空间与账户空间的用量和配额",
+		"storage_upload_short":                 "以可续传的分块方式上传文件",
+		"storage_upload_long":                  "以可续传的分块方式把本地文件上传到 user/ckpt/model.bin 这样的存储路径。远程路径以 / 结尾时沿用本地文件名。中断后重新执行同一上传会从服务器已收到的位置继续。",
+		"storage_upload_flag_chunk-size":       "分块大小，例如 8Mi 或 64Mi",
+		"storage_upload_flag_retries":          "单个分块失败后的重试次数",
+		"admin_storage_short":                  "查看和设置存储配额",
+		"admin_storage_usage_short":            "查看所有用户或账户空间的用量和配额",
+		"admin_storage_usage_flag_accounts":    "列出账户空间而非用户空间",
+		"admin_storage_quota_short":            "管理存储配额",
+		"admin_storage_quota_set_short":        "设置用户或账户空间的字节与 inode 配额",
+		"admin_storage_quota_set_flag_user":    "要限制其个人空间的用户名",
+		"admin_storage_quota_set_flag_account": "要限制其账户空间的账户名",
+		"admin_storage_quota_set_flag_bytes":   "字节配额，例如 500Gi 或 2T；0 表示取消限制",
+		"admin_storage_quota_set_flag_inodes":  "文件与目录数量上限；0 表示取消限制",
+
+		"storage_label_space":                "用户或账户",
+		"storage_label_limit":                "字节或 inode 配额",
+		"err_invalid_byte_size":              "无效的容量：%s（须为数字，可带 Ki/Mi/Gi/Ti 或 K/M/G/T 后缀）",
+		"err_invalid_inode_quota":            "无效的 inode 配额：%d（须为 0 或正整数）",
+		"storage_table_space":                "空间",
+		"storage_table_kind":                 "类型",
+		"storage_table_bytes":                "容量",
+		"storage_table_inodes":               "INODE",
+		"storage_table_scanned":              "统计时间",
+		"storage_not_scanned":                "尚未统计",
+		"err_upload_not_a_file":              "%s 是目录而不是文件",
+		"storage_upload_resumed":             "已从中断处继续上传。",
+		"storage_upload_done":                "已上传 %s（%s，sha256 %s）",
+		"storage_trash_short":                "查看并恢复已删除的文件",
+		"storage_trash_ls_short":             "列出我的个人空间与账户空间中已删除的文件",
+		"storage_trash_restore_short":        "恢复已删除的文件或目录",
+		"storage_trash_restore_flag_to":      "恢复到该路径（例如 user/restored/a.txt）而非原路径",
+		"storage_table_path":                 "路径",
+		"storage_table_deleted":              "删除时间",
+		"storage_table_expires":              "过期时间",
+		"err_invalid_trash_id":               "无效的回收站条目 ID：%s",
+		"storage_trash_restored":             "已恢复 %s",
+		"storage_s3-credentials_short":       "查看我在当前账户下的 S3 访问密钥",
+		"storage_s3-credentials_long":        "查看当前账户的 S3 访问密钥。boto3、rclone、aws s3 等 S3 客户端可以用它以 path-style 寻址访问存储服务的 S3 网关。bucket 为 user、account 和 public。只要你仍属于该账户且未轮换密钥，密钥就一直有效。",
+		"storage_s3-credentials_flag_rotate": "用新密钥替换当前密钥，旧密钥立即失效",
+		"storage_s3_endpoint":                "网关地址",
+		"storage_s3_buckets":                 "BUCKET",
+		"storage_s3_env_hint":                "S3 客户端环境变量：",
+		"storage_pull_short":                 "将文件或目录打包为一个归档下载",
+		"storage_pull_long":                  "以单个流式归档下载 user/results 这样的存储路径，并解压到输出目录。使用 --archive 时直接保存 tar.gz 或 zip 文件而不解压。除非设置 --force，否则不会覆盖已存在的本地文件。",
+		"storage_pull_flag_output":           "解压到的目录",
+		"storage_pull_flag_archive":          "将归档保存到该文件而不解压；文件名以 .zip 结尾时请求 zip 格式",
+		"storage_pull_flag_force":            "覆盖已存在的本地文件",
+		"storage_pull_done":                  "已拉取 %d 个文件（%s）到 %s",
+		"storage_pull_saved":                 "已保存 %s（%s）",
+		"err_pull_exists":                    "%s 已存在，使用 --force 覆盖",
+	},
+}
@@ -0,0 +1,52 @@
+package i18n
+
+var catalogWorkflow = map[Language]map[string]string{
+	En: {
+		"workflow_short":        "Manage job workflows",
+		"workflow_long":         "Submit vcjobs as a DAG whose downstream jobs start after their parents finish.",
+		"workflow_ls_short":     "List workflows",
+		"workflow_get_short":    "Get a workflow and its nodes",
+		"workflow_create_short": "Create a workflow from a JSON file",
+		"workflow_cancel_short": "Cancel a workflow",
+
+		"workflow_create_flag_file": "Read workflow JSON request body from file",
+		"workflow_cancel_flag_yes":  "Skip confirmation",
+
+		"workflow_label_id":              "workflow id",
+		"workflow_label_name":            "workflow name",
+		"workflow_label_nodes":           "workflow nodes",
+		"workflow_label_node_name":       "workflow node name",
+		"workflow_label_node_spec":       "workflow node job spec",
+		"err_invalid_workflow_job_type":  "invalid workflow node job type: %s (expected custom or pytorch)",
+		"err_invalid_workflow_condition": "invalid workflow dependency condition: %s (expected success or completion)",
+
+		"workflow_table_node":       "NODE",
+		"workflow_table_depends_on": "DEPENDS ON",
+		"workflow_create_success":   "Created workflow: %s",
+		"workflow_cancel_success":   "Canceled workflow: %s",
+	},
+	ZhCN: {
+		"workflow_short":        "管理作业工作流",
+		"workflow_long":         "以 DAG 形式提交 vcjob，下游作业在上游作业结束后才开始运行。",
+		"workflow_ls_short":     "列出工作流",
+		"workflow_get_short":    "查看工作流及其节点",
+		"workflow_create_short": "从 JSON 文件创建工作流",
+		"workflow_cancel_short": "取消工作流",
+
+		"workflow_create_flag_file": "从文件读取工作流 JSON 请求体",
+		"workflow_cancel_flag_yes":  "跳过确认",
+
+		"workflow_label_id":              "工作流 ID",
+		"workflow_label_name":            "工作流名称",
+		"workflow_label_nodes":           "工作流节点",
+		"workflow_label_node_name":       "工作流节点名称",
+		"workflow_label_node_spec":       "工作流节点作业定义",
+		"err_invalid_workflow_job_type":  "无效的工作流节点作业类型: %s（应为 custom 或 pytorch）",
+		"err_invalid_workflow_condition": "无效的工作流依赖条件: %s（应为 success 或 completion）",
+
+		"workflow_table_node":       "节点",
+		"workflow_table_depends_on": "依赖",
+		"workflow_create_success":   "已创建工作流: %s",
+		"workflow_cancel_success":   "已取消工作流: %s",
+	},
+}
@@ -28,6 +28,17 @@ var translations = mergeCatalogs(
 	catalogOrder,
 	catalogErrors,
 	catalogJob,
+	catalogWorkflow,
+	catalogJobArray,
+	catalogNotifications,
+	catalogBillingStatement,
+	catalogBillingBudget,
+	catalogJobEstimate,
+	catalogFairShare,
+	catalogJobQueue,
+	catalogStorage,
+	catalogDataset,
+	catalogShare,
 )
 
 func mergeCatalogs(catalogs ...map[Language]map[string]string) map[Language]map[string]string {
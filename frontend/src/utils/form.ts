@@ -143,6 +143,7 @@ export const volumeMountsSchema = z.array(
       message: '挂载源不能为空',
     }),
     datasetID: z.number().int().nonnegative().optional(),
+    datasetVersion: z.string().optional(),
     mountPath: z
       .string()
       .min(1, {